@@ -0,0 +1,235 @@
+// Copyright 2025 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package generate
+
+import (
+	"maps"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+
+	"github.com/dadrus/heimdall/internal/config"
+	"github.com/dadrus/heimdall/internal/heimdall"
+	config2 "github.com/dadrus/heimdall/internal/rules/config"
+	"github.com/dadrus/heimdall/internal/x/errorchain"
+)
+
+var (
+	pathParameterPattern = regexp.MustCompile(`{([^}]+)}`)  //nolint:gochecknoglobals
+	nonIDCharsPattern    = regexp.MustCompile(`[^a-z0-9]+`) //nolint:gochecknoglobals
+)
+
+type openAPIRulesOptions struct {
+	name                   string
+	idPrefix               string
+	authenticators         map[string]string
+	anonymousAuthenticator string
+	authorizers            []string
+	upstream               string
+}
+
+func ruleSetFromOpenAPI(doc *openapi3.T, opts openAPIRulesOptions) (*config2.RuleSet, error) {
+	basePath, err := doc.Servers.BasePath()
+	if err != nil {
+		return nil, errorchain.NewWithMessage(heimdall.ErrConfiguration,
+			"failed to determine base path from the servers definition").CausedBy(err)
+	}
+
+	basePath = strings.TrimSuffix(basePath, "/")
+	ruleSet := &config2.RuleSet{
+		Version: config2.CurrentRuleSetVersion,
+		Name:    opts.name,
+	}
+
+	paths := doc.Paths.Map()
+	for _, path := range slices.Sorted(maps.Keys(paths)) {
+		operations := paths[path].Operations()
+
+		for _, method := range slices.Sorted(maps.Keys(operations)) {
+			rule, err := ruleFromOperation(doc, basePath+path, method, operations[method], opts)
+			if err != nil {
+				return nil, err
+			}
+
+			ruleSet.Rules = append(ruleSet.Rules, rule)
+		}
+	}
+
+	return ruleSet, nil
+}
+
+func ruleFromOperation(
+	doc *openapi3.T,
+	path, method string,
+	operation *openapi3.Operation,
+	opts openAPIRulesOptions,
+) (config2.Rule, error) {
+	rule := config2.Rule{
+		ID: opts.idPrefix + ruleID(path, method, operation),
+		Matcher: config2.Matcher{
+			Routes:  []config2.Route{{Path: routePath(path)}},
+			Methods: []string{method},
+		},
+	}
+
+	authenticators, err := authenticatorsFor(doc, operation, opts)
+	if err != nil {
+		return config2.Rule{}, errorchain.NewWithMessagef(heimdall.ErrConfiguration,
+			"failed to create rule for operation '%s %s'", method, path).CausedBy(err)
+	}
+
+	rule.Execute = authenticators
+	for _, id := range opts.authorizers {
+		rule.Execute = append(rule.Execute, config.MechanismConfig{"authorizer": id})
+	}
+
+	if len(opts.upstream) != 0 {
+		rule.Backend = &config2.Backend{Host: opts.upstream}
+	}
+
+	return rule, nil
+}
+
+// authenticatorsFor maps the security requirements of the given operation to authenticators. The
+// alternative requirements defined by OpenAPI correspond to the fallback semantics of multiple
+// authenticators in a heimdall pipeline. Requirements combining several schemes cannot be expressed
+// that way and result in an error.
+func authenticatorsFor(
+	doc *openapi3.T,
+	operation *openapi3.Operation,
+	opts openAPIRulesOptions,
+) ([]config.MechanismConfig, error) {
+	requirements := doc.Security
+	if operation.Security != nil {
+		requirements = *operation.Security
+	}
+
+	var (
+		result         []config.MechanismConfig
+		allowAnonymous = len(requirements) == 0
+	)
+
+	for _, requirement := range requirements {
+		switch len(requirement) {
+		case 0:
+			allowAnonymous = true
+
+			continue
+		case 1:
+		default:
+			return nil, errorchain.NewWithMessagef(ErrUnsupportedSecurity,
+				"combination of security schemes %s", strings.Join(slices.Sorted(maps.Keys(requirement)), ", "))
+		}
+
+		for scheme, scopes := range requirement {
+			id, ok := opts.authenticators[scheme]
+			if !ok {
+				return nil, errorchain.NewWithMessagef(ErrNoAuthenticatorConfigured,
+					"for security scheme '%s'", scheme)
+			}
+
+			authenticator := config.MechanismConfig{"authenticator": id}
+			if len(scopes) != 0 && supportsScopes(doc, scheme) {
+				authenticator["config"] = map[string]any{
+					"assertions": map[string]any{"scopes": scopes},
+				}
+			}
+
+			result = append(result, authenticator)
+		}
+	}
+
+	if allowAnonymous {
+		if len(opts.anonymousAuthenticator) == 0 {
+			return nil, errorchain.NewWithMessage(ErrNoAuthenticatorConfigured,
+				"for operation not requiring authentication")
+		}
+
+		result = append(result, config.MechanismConfig{"authenticator": opts.anonymousAuthenticator})
+	}
+
+	return result, nil
+}
+
+func supportsScopes(doc *openapi3.T, scheme string) bool {
+	if doc.Components == nil {
+		return false
+	}
+
+	ref, ok := doc.Components.SecuritySchemes[scheme]
+	if !ok || ref.Value == nil {
+		return false
+	}
+
+	return ref.Value.Type == "oauth2" || ref.Value.Type == "openIdConnect"
+}
+
+func ruleID(path, method string, operation *openapi3.Operation) string {
+	if len(operation.OperationID) != 0 {
+		return operation.OperationID
+	}
+
+	return strings.Trim(nonIDCharsPattern.ReplaceAllString(strings.ToLower(method+"-"+path), "-"), "-")
+}
+
+// routePath converts an OpenAPI path template into a heimdall path expression. Segments consisting
+// of a single path parameter become named single wildcards. Segments mixing literals and parameters,
+// like "{name}.{ext}", cannot be expressed in heimdall and become single wildcards named after the
+// contained parameters, like ":name_ext". If that name is already in use, the index of the segment
+// is appended to keep the captures unique.
+func routePath(path string) string {
+	segments := strings.Split(path, "/")
+	names := make(map[string]bool, len(segments))
+
+	for _, segment := range segments {
+		if matches := pathParameterPattern.FindAllStringSubmatch(segment, -1); len(matches) == 1 &&
+			matches[0][0] == segment {
+			names[matches[0][1]] = true
+		}
+	}
+
+	for idx, segment := range segments {
+		matches := pathParameterPattern.FindAllStringSubmatch(segment, -1)
+
+		switch {
+		case len(matches) == 0:
+			if strings.HasPrefix(segment, ":") || strings.HasPrefix(segment, "*") {
+				segments[idx] = "\\" + segment
+			}
+		case len(matches) == 1 && matches[0][0] == segment:
+			segments[idx] = ":" + matches[0][1]
+		default:
+			parameters := make([]string, len(matches))
+			for pidx, match := range matches {
+				parameters[pidx] = match[1]
+			}
+
+			name := strings.Join(parameters, "_")
+			if names[name] {
+				name += strconv.Itoa(idx)
+			}
+
+			names[name] = true
+			segments[idx] = ":" + name
+		}
+	}
+
+	return strings.Join(segments, "/")
+}
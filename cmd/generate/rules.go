@@ -0,0 +1,115 @@
+// Copyright 2025 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package generate
+
+import (
+	"bytes"
+	"os"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	"github.com/dadrus/heimdall/internal/rules/mechanisms/authorizers"
+)
+
+const (
+	generateFlagFromOpenAPI            = "from-openapi"
+	generateFlagName                   = "name"
+	generateFlagIDPrefix               = "id-prefix"
+	generateFlagAuthenticator          = "authenticator"
+	generateFlagAnonymousAuthenticator = "anonymous-authenticator"
+	generateFlagAuthorizer             = "authorizer"
+	generateFlagUpstream               = "upstream"
+	generateFlagOutput                 = "output"
+)
+
+// NewGenerateRulesCommand represents the "generate rules" command.
+func NewGenerateRulesCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rules",
+		Short: "Generates a heimdall rule set",
+		Example: `heimdall generate rules --from-openapi openapi.yaml \
+  --authenticator bearerAuth=jwt_auth --authenticator basicAuth=basic_auth \
+  --anonymous-authenticator anonymous --authorizer openapi_validator \
+  --upstream my-backend:8080 -o rules.yaml`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE:         generateRuleSet,
+	}
+
+	cmd.Flags().String(generateFlagFromOpenAPI, "",
+		"The path to the OpenAPI specification to generate the rules from. One rule is generated per operation")
+	cmd.Flags().String(generateFlagName, "", "The name of the generated rule set")
+	cmd.Flags().String(generateFlagIDPrefix, "",
+		"The prefix for the ids of the generated rules. The id itself is derived from the operation")
+	cmd.Flags().StringToString(generateFlagAuthenticator, nil,
+		`Maps a security scheme from the OpenAPI specification to the id of a configured authenticator.
+Can be specified multiple times. Example: "bearerAuth=jwt_auth"`)
+	cmd.Flags().String(generateFlagAnonymousAuthenticator, "",
+		"The id of the authenticator to use for operations, which do not require authentication")
+	cmd.Flags().StringSlice(generateFlagAuthorizer, nil,
+		"The ids of the authorizers to add to the pipeline of each rule. Can be specified multiple times")
+	cmd.Flags().String(generateFlagUpstream, "",
+		"The host (and port) of the upstream service to forward the requests to in proxy mode")
+	cmd.Flags().StringP(generateFlagOutput, "o", "",
+		"The file to write the generated rule set to. Defaults to stdout")
+
+	return cmd
+}
+
+func generateRuleSet(cmd *cobra.Command, _ []string) error {
+	specPath, _ := cmd.Flags().GetString(generateFlagFromOpenAPI)
+	if len(specPath) == 0 {
+		return ErrNoSpecification
+	}
+
+	doc, err := authorizers.LoadOpenAPISpecification(specPath)
+	if err != nil {
+		return err
+	}
+
+	var opts openAPIRulesOptions
+
+	opts.name, _ = cmd.Flags().GetString(generateFlagName)
+	opts.idPrefix, _ = cmd.Flags().GetString(generateFlagIDPrefix)
+	opts.authenticators, _ = cmd.Flags().GetStringToString(generateFlagAuthenticator)
+	opts.anonymousAuthenticator, _ = cmd.Flags().GetString(generateFlagAnonymousAuthenticator)
+	opts.authorizers, _ = cmd.Flags().GetStringSlice(generateFlagAuthorizer)
+	opts.upstream, _ = cmd.Flags().GetString(generateFlagUpstream)
+
+	ruleSet, err := ruleSetFromOpenAPI(doc, opts)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2) //nolint:mnd
+
+	if err = enc.Encode(ruleSet); err != nil {
+		return err
+	}
+
+	if output, _ := cmd.Flags().GetString(generateFlagOutput); len(output) != 0 {
+		return os.WriteFile(output, buf.Bytes(), 0o600) //nolint:mnd
+	}
+
+	cmd.Print(buf.String())
+
+	return nil
+}
@@ -0,0 +1,192 @@
+// Copyright 2025 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package generate
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/goccy/go-json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+
+	"github.com/dadrus/heimdall/internal/config"
+	config2 "github.com/dadrus/heimdall/internal/rules/config"
+)
+
+func TestGenerateRuleSet(t *testing.T) {
+	t.Parallel()
+
+	for uc, tc := range map[string]struct {
+		args   []string
+		assert func(t *testing.T, err error, ruleSet *config2.RuleSet)
+	}{
+		"no specification provided": {
+			assert: func(t *testing.T, err error, _ *config2.RuleSet) {
+				t.Helper()
+
+				require.ErrorIs(t, err, ErrNoSpecification)
+			},
+		},
+		"not existing specification": {
+			args: []string{"--" + generateFlagFromOpenAPI, "test_data/does-not-exist.yaml"},
+			assert: func(t *testing.T, err error, _ *config2.RuleSet) {
+				t.Helper()
+
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), "no such file or directory")
+			},
+		},
+		"security requirement combining multiple schemes": {
+			args: []string{
+				"--" + generateFlagFromOpenAPI, "test_data/openapi-combined-security.yaml",
+				"--" + generateFlagAuthenticator, "apiKey=api_key,bearerAuth=jwt",
+			},
+			assert: func(t *testing.T, err error, _ *config2.RuleSet) {
+				t.Helper()
+
+				require.ErrorIs(t, err, ErrUnsupportedSecurity)
+				assert.Contains(t, err.Error(), "apiKey, bearerAuth")
+			},
+		},
+		"no authenticator mapped to a security scheme": {
+			args: []string{
+				"--" + generateFlagFromOpenAPI, "test_data/openapi.yaml",
+				"--" + generateFlagAuthenticator, "bearerAuth=jwt",
+				"--" + generateFlagAnonymousAuthenticator, "anon",
+			},
+			assert: func(t *testing.T, err error, _ *config2.RuleSet) {
+				t.Helper()
+
+				require.ErrorIs(t, err, ErrNoAuthenticatorConfigured)
+				assert.Contains(t, err.Error(), "'oauth2'")
+			},
+		},
+		"no anonymous authenticator configured": {
+			args: []string{
+				"--" + generateFlagFromOpenAPI, "test_data/openapi.yaml",
+				"--" + generateFlagAuthenticator, "bearerAuth=jwt,oauth2=introspection",
+			},
+			assert: func(t *testing.T, err error, _ *config2.RuleSet) {
+				t.Helper()
+
+				require.ErrorIs(t, err, ErrNoAuthenticatorConfigured)
+				assert.Contains(t, err.Error(), "not requiring authentication")
+			},
+		},
+		"rule set generated": {
+			args: []string{
+				"--" + generateFlagFromOpenAPI, "test_data/openapi.yaml",
+				"--" + generateFlagName, "pet-store",
+				"--" + generateFlagIDPrefix, "pets:",
+				"--" + generateFlagAuthenticator, "bearerAuth=jwt,oauth2=introspection",
+				"--" + generateFlagAnonymousAuthenticator, "anon",
+				"--" + generateFlagAuthorizer, "openapi",
+				"--" + generateFlagUpstream, "pet-store:8080",
+			},
+			assert: func(t *testing.T, err error, ruleSet *config2.RuleSet) {
+				t.Helper()
+
+				require.NoError(t, err)
+				assert.Equal(t, config2.CurrentRuleSetVersion, ruleSet.Version)
+				assert.Equal(t, "pet-store", ruleSet.Name)
+				require.Len(t, ruleSet.Rules, 4)
+
+				rule := ruleSet.Rules[0]
+				assert.Equal(t, "pets:listPets", rule.ID)
+				assert.Equal(t, "/v1/pets", rule.Matcher.Routes[0].Path)
+				assert.Equal(t, []string{"GET"}, rule.Matcher.Methods)
+				assert.Equal(t, "pet-store:8080", rule.Backend.Host)
+				assert.Equal(t, []config.MechanismConfig{
+					{"authenticator": "anon"},
+					{"authorizer": "openapi"},
+				}, rule.Execute)
+
+				rule = ruleSet.Rules[1]
+				assert.Equal(t, "pets:createPet", rule.ID)
+				assert.Equal(t, []string{"POST"}, rule.Matcher.Methods)
+				execute, err := json.Marshal(rule.Execute)
+				require.NoError(t, err)
+				assert.JSONEq(t, `[
+  { "authenticator": "introspection", "config": { "assertions": { "scopes": [ "pets:write" ] } } },
+  { "authenticator": "jwt" },
+  { "authorizer": "openapi" }
+]`, string(execute))
+
+				rule = ruleSet.Rules[2]
+				assert.Equal(t, "pets:get-v1-pets-petid", rule.ID)
+				assert.Equal(t, "/v1/pets/:petId", rule.Matcher.Routes[0].Path)
+				assert.Equal(t, []config.MechanismConfig{
+					{"authenticator": "jwt"},
+					{"authorizer": "openapi"},
+				}, rule.Execute)
+
+				rule = ruleSet.Rules[3]
+				assert.Equal(t, "pets:getPhoto", rule.ID)
+				assert.Equal(t, "/v1/pets/:petId/photos/:name_ext", rule.Matcher.Routes[0].Path)
+			},
+		},
+	} {
+		t.Run(uc, func(t *testing.T) {
+			t.Parallel()
+
+			// GIVEN
+			output := filepath.Join(t.TempDir(), "rules.yaml")
+			cmd := NewGenerateRulesCommand()
+
+			err := cmd.ParseFlags(append(tc.args, "--"+generateFlagOutput, output))
+			require.NoError(t, err)
+
+			// WHEN
+			err = generateRuleSet(cmd, nil)
+
+			// THEN
+			var ruleSet *config2.RuleSet
+
+			if err == nil {
+				raw, err := os.ReadFile(output)
+				require.NoError(t, err)
+
+				ruleSet = &config2.RuleSet{}
+				require.NoError(t, yaml.Unmarshal(raw, ruleSet))
+			}
+
+			tc.assert(t, err, ruleSet)
+		})
+	}
+}
+
+func TestRoutePath(t *testing.T) {
+	t.Parallel()
+
+	for path, expected := range map[string]string{
+		"/v1/pets":                      "/v1/pets",
+		"/v1/pets/{petId}":              "/v1/pets/:petId",
+		"/files/{name}.{ext}":           "/files/:name_ext",
+		"/files/{name}.json/{name}.xml": "/files/:name/:name3",
+		"/items/{id}/{id}.json":         "/items/:id/:id3",
+		"/:foo/*bar":                    "/\\:foo/\\*bar",
+	} {
+		t.Run(path, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, expected, routePath(path))
+		})
+	}
+}
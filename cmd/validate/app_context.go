@@ -20,8 +20,10 @@ import (
 	"github.com/rs/zerolog"
 
 	"github.com/dadrus/heimdall/internal/config"
+	"github.com/dadrus/heimdall/internal/geoip"
 	"github.com/dadrus/heimdall/internal/keyholder"
 	"github.com/dadrus/heimdall/internal/otel/metrics/certificate"
+	"github.com/dadrus/heimdall/internal/scheduler"
 	"github.com/dadrus/heimdall/internal/validation"
 	"github.com/dadrus/heimdall/internal/watcher"
 )
@@ -30,6 +32,8 @@ type appContext struct {
 	w   watcher.Watcher
 	khr keyholder.Registry
 	co  certificate.Observer
+	geo geoip.Resolver
+	sch scheduler.Scheduler
 	v   validation.Validator
 	l   zerolog.Logger
 	c   *config.Configuration
@@ -38,6 +42,8 @@ type appContext struct {
 func (c *appContext) Watcher() watcher.Watcher                  { return c.w }
 func (c *appContext) KeyHolderRegistry() keyholder.Registry     { return c.khr }
 func (c *appContext) CertificateObserver() certificate.Observer { return c.co }
+func (c *appContext) GeoIP() geoip.Resolver                     { return c.geo }
+func (c *appContext) Scheduler() scheduler.Scheduler            { return c.sch }
 func (c *appContext) Validator() validation.Validator           { return c.v }
 func (c *appContext) Logger() zerolog.Logger                    { return c.l }
 func (c *appContext) Config() *config.Configuration             { return c.c }
@@ -30,6 +30,7 @@ import (
 	"github.com/dadrus/heimdall/internal/rules/provider/cloudblob"
 	"github.com/dadrus/heimdall/internal/rules/provider/filesystem"
 	"github.com/dadrus/heimdall/internal/rules/provider/httpendpoint"
+	"github.com/dadrus/heimdall/internal/scheduler"
 	"github.com/dadrus/heimdall/internal/validation"
 	"github.com/dadrus/heimdall/internal/watcher"
 	"github.com/dadrus/heimdall/internal/x/errorchain"
@@ -78,6 +79,8 @@ func validateConfig(cmd *cobra.Command, _ []string) error {
 		w:   &watcher.NoopWatcher{},
 		khr: &noopRegistry{},
 		co:  &noopCertificateObserver{},
+		geo: newNoopGeoIPResolver(conf),
+		sch: &scheduler.NoopScheduler{},
 		v:   validator,
 		l:   logger,
 		c:   conf,
@@ -90,6 +93,8 @@ func validateConfig(cmd *cobra.Command, _ []string) error {
 
 	rFactory, err := rules.NewRuleFactory(
 		mFactory,
+		appCtx.GeoIP(),
+		appCtx.Watcher(),
 		conf,
 		config.DecisionMode,
 		logger,
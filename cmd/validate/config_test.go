@@ -193,6 +193,16 @@ func TestValidateConfig(t *testing.T) {
 				require.ErrorContains(t, err, "'tls'.'disabled' must be false")
 			},
 		},
+		"geoip contextualizer without configured geoip databases": {
+			confFile: "test_data/config-geoip-contextualizer-without-databases.yaml",
+			assert: func(t *testing.T, err error) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				require.ErrorContains(t, err, "requires geoip databases to be configured")
+			},
+		},
 		"valid config": {
 			confFile: "test_data/config-valid.yaml",
 			assert: func(t *testing.T, err error) {
@@ -26,6 +26,7 @@ import (
 
 	"github.com/dadrus/heimdall/cmd/flags"
 	"github.com/dadrus/heimdall/internal/config"
+	"github.com/dadrus/heimdall/internal/geoip"
 	"github.com/dadrus/heimdall/internal/heimdall"
 	"github.com/dadrus/heimdall/internal/keyholder"
 	"github.com/dadrus/heimdall/internal/otel/metrics/certificate"
@@ -33,6 +34,7 @@ import (
 	"github.com/dadrus/heimdall/internal/rules/mechanisms"
 	"github.com/dadrus/heimdall/internal/rules/provider/filesystem"
 	"github.com/dadrus/heimdall/internal/rules/rule"
+	"github.com/dadrus/heimdall/internal/scheduler"
 	"github.com/dadrus/heimdall/internal/validation"
 	"github.com/dadrus/heimdall/internal/watcher"
 )
@@ -97,6 +99,8 @@ func validateRuleSet(cmd *cobra.Command, args []string) error {
 		w:   &watcher.NoopWatcher{},
 		khr: &noopRegistry{},
 		co:  &noopCertificateObserver{},
+		geo: newNoopGeoIPResolver(conf),
+		sch: &scheduler.NoopScheduler{},
 		v:   validator,
 		l:   logger,
 		c:   conf,
@@ -109,6 +113,8 @@ func validateRuleSet(cmd *cobra.Command, args []string) error {
 
 	rFactory, err := rules.NewRuleFactory(
 		mFactory,
+		appCtx.GeoIP(),
+		appCtx.Watcher(),
 		conf,
 		opMode,
 		logger,
@@ -155,3 +161,19 @@ type noopCertificateObserver struct{}
 
 func (*noopCertificateObserver) Add(_ certificate.Supplier) {}
 func (*noopCertificateObserver) Start() error               { return errFunctionNotSupported }
+
+// noopGeoIPResolver is used in place of the configured geoip databases, which are not
+// loaded for validation purposes.
+type noopGeoIPResolver struct{}
+
+func newNoopGeoIPResolver(conf *config.Configuration) geoip.Resolver {
+	if len(conf.GeoIP.Databases) == 0 {
+		return nil
+	}
+
+	return &noopGeoIPResolver{}
+}
+
+func (*noopGeoIPResolver) Lookup(_ string) (geoip.Record, error) {
+	return geoip.Record{}, errFunctionNotSupported
+}
@@ -98,6 +98,11 @@ func TestValidateRuleset(t *testing.T) {
 			rulesFile: "test_data/ruleset-no-https-for-upstream.yaml",
 			expError:  "'rules'[0].'forward_to'.'rewrite'.'scheme' must be https",
 		},
+		"url rewrite referencing not existing capture group": {
+			confFile:  configFile,
+			rulesFile: "test_data/ruleset-invalid-url-rewrite.yaml",
+			expError:  "not existing capture group '2'",
+		},
 	} {
 		t.Run(uc, func(t *testing.T) {
 			// GIVEN
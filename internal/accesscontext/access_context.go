@@ -25,9 +25,16 @@ type ctxKey struct{}
 type accessContext struct {
 	err     error
 	subject string
+	ruleID  string
 }
 
+// New returns a context holding an access context. If the given context already holds one,
+// it is returned as is, so that middlewares sharing the same request can exchange data.
 func New(ctx context.Context) context.Context {
+	if _, ok := ctx.Value(ctxKey{}).(*accessContext); ok {
+		return ctx
+	}
+
 	return context.WithValue(ctx, ctxKey{}, &accessContext{})
 }
 
@@ -58,3 +65,17 @@ func SetSubject(ctx context.Context, subject string) {
 		c.subject = subject
 	}
 }
+
+func RuleID(ctx context.Context) string {
+	if c, ok := ctx.Value(ctxKey{}).(*accessContext); ok {
+		return c.ruleID
+	}
+
+	return ""
+}
+
+func SetRuleID(ctx context.Context, ruleID string) {
+	if c, ok := ctx.Value(ctxKey{}).(*accessContext); ok {
+		c.ruleID = ruleID
+	}
+}
@@ -20,8 +20,10 @@ import (
 	"github.com/rs/zerolog"
 
 	"github.com/dadrus/heimdall/internal/config"
+	"github.com/dadrus/heimdall/internal/geoip"
 	"github.com/dadrus/heimdall/internal/keyholder"
 	"github.com/dadrus/heimdall/internal/otel/metrics/certificate"
+	"github.com/dadrus/heimdall/internal/scheduler"
 	"github.com/dadrus/heimdall/internal/validation"
 	"github.com/dadrus/heimdall/internal/watcher"
 )
@@ -32,6 +34,8 @@ type Context interface {
 	Watcher() watcher.Watcher
 	KeyHolderRegistry() keyholder.Registry
 	CertificateObserver() certificate.Observer
+	GeoIP() geoip.Resolver
+	Scheduler() scheduler.Scheduler
 	Validator() validation.Validator
 	Logger() zerolog.Logger
 	Config() *config.Configuration
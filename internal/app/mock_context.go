@@ -6,10 +6,14 @@ import (
 	config "github.com/dadrus/heimdall/internal/config"
 	certificate "github.com/dadrus/heimdall/internal/otel/metrics/certificate"
 
+	geoip "github.com/dadrus/heimdall/internal/geoip"
+
 	keyholder "github.com/dadrus/heimdall/internal/keyholder"
 
 	mock "github.com/stretchr/testify/mock"
 
+	scheduler "github.com/dadrus/heimdall/internal/scheduler"
+
 	validation "github.com/dadrus/heimdall/internal/validation"
 
 	watcher "github.com/dadrus/heimdall/internal/watcher"
@@ -124,6 +128,53 @@ func (_c *ContextMock_Config_Call) RunAndReturn(run func() *config.Configuration
 	return _c
 }
 
+// GeoIP provides a mock function with given fields:
+func (_m *ContextMock) GeoIP() geoip.Resolver {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for GeoIP")
+	}
+
+	var r0 geoip.Resolver
+	if rf, ok := ret.Get(0).(func() geoip.Resolver); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(geoip.Resolver)
+		}
+	}
+
+	return r0
+}
+
+// ContextMock_GeoIP_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GeoIP'
+type ContextMock_GeoIP_Call struct {
+	*mock.Call
+}
+
+// GeoIP is a helper method to define mock.On call
+func (_e *ContextMock_Expecter) GeoIP() *ContextMock_GeoIP_Call {
+	return &ContextMock_GeoIP_Call{Call: _e.mock.On("GeoIP")}
+}
+
+func (_c *ContextMock_GeoIP_Call) Run(run func()) *ContextMock_GeoIP_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *ContextMock_GeoIP_Call) Return(_a0 geoip.Resolver) *ContextMock_GeoIP_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *ContextMock_GeoIP_Call) RunAndReturn(run func() geoip.Resolver) *ContextMock_GeoIP_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // KeyHolderRegistry provides a mock function with given fields:
 func (_m *ContextMock) KeyHolderRegistry() keyholder.Registry {
 	ret := _m.Called()
@@ -216,6 +267,53 @@ func (_c *ContextMock_Logger_Call) RunAndReturn(run func() zerolog.Logger) *Cont
 	return _c
 }
 
+// Scheduler provides a mock function with given fields:
+func (_m *ContextMock) Scheduler() scheduler.Scheduler {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for Scheduler")
+	}
+
+	var r0 scheduler.Scheduler
+	if rf, ok := ret.Get(0).(func() scheduler.Scheduler); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(scheduler.Scheduler)
+		}
+	}
+
+	return r0
+}
+
+// ContextMock_Scheduler_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Scheduler'
+type ContextMock_Scheduler_Call struct {
+	*mock.Call
+}
+
+// Scheduler is a helper method to define mock.On call
+func (_e *ContextMock_Expecter) Scheduler() *ContextMock_Scheduler_Call {
+	return &ContextMock_Scheduler_Call{Call: _e.mock.On("Scheduler")}
+}
+
+func (_c *ContextMock_Scheduler_Call) Run(run func()) *ContextMock_Scheduler_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *ContextMock_Scheduler_Call) Return(_a0 scheduler.Scheduler) *ContextMock_Scheduler_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *ContextMock_Scheduler_Call) RunAndReturn(run func() scheduler.Scheduler) *ContextMock_Scheduler_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Validator provides a mock function with given fields:
 func (_m *ContextMock) Validator() validation.Validator {
 	ret := _m.Called()
@@ -33,6 +33,7 @@ type Configuration struct { //nolint:musttag
 	Metrics              MetricsConfig        `koanf:"metrics"`
 	Profiling            ProfilingConfig      `koanf:"profiling"`
 	Cache                CacheConfig          `koanf:"cache"`
+	GeoIP                GeoIPConfig          `koanf:"geoip,omitempty"`
 	Prototypes           *MechanismPrototypes `koanf:"mechanisms,omitempty"`
 	Default              *DefaultRule         `koanf:"default_rule,omitempty"`
 	Providers            RuleProviders        `koanf:"providers,omitempty"`
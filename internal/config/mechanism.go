@@ -23,6 +23,7 @@ type Mechanism struct {
 	Type      string          `koanf:"type"`
 	Config    MechanismConfig `koanf:"config"`
 	Condition string          `koanf:"if"`
+	Enforce   *bool           `koanf:"enforce"`
 }
 
 type MechanismConfig map[string]any
@@ -68,14 +68,17 @@ type ResponseOverride struct {
 }
 
 type RespondConfig struct {
-	Verbose bool `koanf:"verbose"`
-	With    struct {
-		Accepted            ResponseOverride `koanf:"accepted"`
-		ArgumentError       ResponseOverride `koanf:"argument_error"`
-		AuthenticationError ResponseOverride `koanf:"authentication_error"`
-		AuthorizationError  ResponseOverride `koanf:"authorization_error"`
-		CommunicationError  ResponseOverride `koanf:"communication_error"`
-		InternalError       ResponseOverride `koanf:"internal_error"`
-		NoRuleError         ResponseOverride `koanf:"no_rule_error"`
+	Verbose        bool `koanf:"verbose"`
+	ProblemDetails bool `koanf:"problem_details"`
+	With           struct {
+		Accepted                ResponseOverride `koanf:"accepted"`
+		ArgumentError           ResponseOverride `koanf:"argument_error"`
+		AuthenticationError     ResponseOverride `koanf:"authentication_error"`
+		AuthorizationError      ResponseOverride `koanf:"authorization_error"`
+		CommunicationError      ResponseOverride `koanf:"communication_error"`
+		InternalError           ResponseOverride `koanf:"internal_error"`
+		NoRuleError             ResponseOverride `koanf:"no_rule_error"`
+		RateLimitError          ResponseOverride `koanf:"rate_limit_error"`
+		ServiceUnavailableError ResponseOverride `koanf:"service_unavailable_error"`
 	} `koanf:"with"`
 }
@@ -0,0 +1,171 @@
+// Copyright 2025 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package geoip
+
+import (
+	"errors"
+	"net"
+	"os"
+	"sync/atomic"
+
+	"github.com/oschwald/maxminddb-golang"
+	"github.com/rs/zerolog"
+
+	"github.com/dadrus/heimdall/internal/heimdall"
+	"github.com/dadrus/heimdall/internal/watcher"
+	"github.com/dadrus/heimdall/internal/x/errorchain"
+)
+
+var (
+	ErrNotConfigured    = errors.New("no geoip database configured")
+	ErrInvalidIPAddress = errors.New("invalid ip address")
+)
+
+// Record holds the geolocation and network information resolved for an ip address. Fields,
+// not available in the configured databases, or not known for the given address, are empty.
+type Record struct {
+	Country        string
+	City           string
+	ASN            int64
+	ASOrganization string
+}
+
+func (r Record) ToMap() map[string]any {
+	return map[string]any{
+		"country":         r.Country,
+		"city":            r.City,
+		"asn":             r.ASN,
+		"as_organization": r.ASOrganization,
+	}
+}
+
+type mmdbRecord struct {
+	Country struct {
+		ISOCode string `maxminddb:"iso_code"`
+	} `maxminddb:"country"`
+	City struct {
+		Names map[string]string `maxminddb:"names"`
+	} `maxminddb:"city"`
+	ASN            uint   `maxminddb:"autonomous_system_number"`
+	ASOrganization string `maxminddb:"autonomous_system_organization"`
+}
+
+// Resolver resolves ip addresses to geolocation and network information.
+type Resolver interface {
+	Lookup(ipAddress string) (Record, error)
+}
+
+// Database resolves ip addresses using one or more MaxMind DB files, like GeoLite2-City and
+// GeoLite2-ASN. The files are reloaded if changed, as long as watching for changes is enabled.
+type Database struct {
+	files []*databaseFile
+}
+
+func NewDatabase(paths []string, cw watcher.Watcher) (*Database, error) {
+	files := make([]*databaseFile, len(paths))
+
+	for idx, path := range paths {
+		file := &databaseFile{path: path}
+
+		if err := file.load(); err != nil {
+			return nil, errorchain.NewWithMessagef(heimdall.ErrConfiguration,
+				"failed loading geoip database from %s", path).CausedBy(err)
+		}
+
+		if err := cw.Add(path, file); err != nil {
+			return nil, errorchain.NewWithMessagef(heimdall.ErrInternal,
+				"failed registering watcher for geoip database %s", path).CausedBy(err)
+		}
+
+		files[idx] = file
+	}
+
+	return &Database{files: files}, nil
+}
+
+func (d *Database) Lookup(ipAddress string) (Record, error) {
+	var result Record
+
+	ip := net.ParseIP(ipAddress)
+	if ip == nil {
+		return result, errorchain.NewWithMessagef(ErrInvalidIPAddress, "'%s'", ipAddress)
+	}
+
+	for _, file := range d.files {
+		var rec mmdbRecord
+
+		if err := file.reader.Load().Lookup(ip, &rec); err != nil {
+			return result, errorchain.NewWithMessagef(heimdall.ErrInternal,
+				"failed looking up %s in geoip database %s", ipAddress, file.path).CausedBy(err)
+		}
+
+		if len(result.Country) == 0 {
+			result.Country = rec.Country.ISOCode
+		}
+
+		if len(result.City) == 0 {
+			result.City = rec.City.Names["en"]
+		}
+
+		if result.ASN == 0 {
+			result.ASN = int64(rec.ASN) //nolint:gosec
+		}
+
+		if len(result.ASOrganization) == 0 {
+			result.ASOrganization = rec.ASOrganization
+		}
+	}
+
+	return result, nil
+}
+
+type databaseFile struct {
+	path   string
+	reader atomic.Pointer[maxminddb.Reader]
+}
+
+func (f *databaseFile) load() error {
+	// the file is read into memory instead of being mapped to allow replacing
+	// the reader without having to care about lookups still in progress.
+	data, err := os.ReadFile(f.path)
+	if err != nil {
+		return err
+	}
+
+	reader, err := maxminddb.FromBytes(data)
+	if err != nil {
+		return err
+	}
+
+	f.reader.Store(reader)
+
+	return nil
+}
+
+func (f *databaseFile) OnChanged(log zerolog.Logger) {
+	if err := f.load(); err != nil {
+		log.Warn().Err(err).
+			Str("_source", "geoip").
+			Str("_file", f.path).
+			Msg("Database reload failed")
+	} else {
+		log.Info().
+			Str("_source", "geoip").
+			Str("_file", f.path).
+			Msg("Database reloaded")
+	}
+}
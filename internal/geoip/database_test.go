@@ -0,0 +1,233 @@
+// Copyright 2025 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package geoip
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dadrus/heimdall/internal/heimdall"
+	"github.com/dadrus/heimdall/internal/watcher"
+	"github.com/dadrus/heimdall/internal/watcher/mocks"
+	"github.com/dadrus/heimdall/internal/x"
+	"github.com/dadrus/heimdall/internal/x/testsupport"
+)
+
+func TestNewDatabase(t *testing.T) {
+	t.Parallel()
+
+	testDir := t.TempDir()
+
+	cityDB := filepath.Join(testDir, "city.mmdb")
+	err := testsupport.WriteGeoIPDatabase(cityDB,
+		testsupport.GeoIPEntry{Network: "192.0.2.0/24", Country: "DE", City: "Berlin"})
+	require.NoError(t, err)
+
+	invalidDB := filepath.Join(testDir, "invalid.mmdb")
+	err = os.WriteFile(invalidDB, []byte("foo"), 0o600)
+	require.NoError(t, err)
+
+	for uc, tc := range map[string]struct {
+		paths          []string
+		configureMocks func(t *testing.T, cw *mocks.WatcherMock)
+		assert         func(t *testing.T, err error, db *Database)
+	}{
+		"not existing database file": {
+			paths: []string{filepath.Join(testDir, "missing.mmdb")},
+			assert: func(t *testing.T, err error, _ *Database) {
+				t.Helper()
+
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				require.ErrorContains(t, err, "missing.mmdb")
+			},
+		},
+		"invalid database file": {
+			paths: []string{invalidDB},
+			assert: func(t *testing.T, err error, _ *Database) {
+				t.Helper()
+
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				require.ErrorContains(t, err, "invalid.mmdb")
+			},
+		},
+		"failing watcher registration": {
+			paths: []string{cityDB},
+			configureMocks: func(t *testing.T, cw *mocks.WatcherMock) {
+				t.Helper()
+
+				cw.EXPECT().Add(cityDB, mock.Anything).Return(errors.New("test error"))
+			},
+			assert: func(t *testing.T, err error, _ *Database) {
+				t.Helper()
+
+				require.ErrorIs(t, err, heimdall.ErrInternal)
+				require.ErrorContains(t, err, "test error")
+			},
+		},
+		"successful creation": {
+			paths: []string{cityDB},
+			configureMocks: func(t *testing.T, cw *mocks.WatcherMock) {
+				t.Helper()
+
+				cw.EXPECT().Add(cityDB, mock.Anything).Return(nil)
+			},
+			assert: func(t *testing.T, err error, db *Database) {
+				t.Helper()
+
+				require.NoError(t, err)
+				require.Len(t, db.files, 1)
+				assert.Equal(t, cityDB, db.files[0].path)
+				assert.NotNil(t, db.files[0].reader.Load())
+			},
+		},
+	} {
+		t.Run(uc, func(t *testing.T) {
+			cw := mocks.NewWatcherMock(t)
+
+			configureMocks := x.IfThenElse(tc.configureMocks != nil,
+				tc.configureMocks,
+				func(t *testing.T, _ *mocks.WatcherMock) { t.Helper() })
+			configureMocks(t, cw)
+
+			db, err := NewDatabase(tc.paths, cw)
+
+			tc.assert(t, err, db)
+		})
+	}
+}
+
+func TestDatabaseLookup(t *testing.T) {
+	t.Parallel()
+
+	testDir := t.TempDir()
+
+	cityDB := filepath.Join(testDir, "city.mmdb")
+	err := testsupport.WriteGeoIPDatabase(cityDB,
+		testsupport.GeoIPEntry{Network: "192.0.2.0/24", Country: "DE", City: "Berlin"},
+		testsupport.GeoIPEntry{Network: "2001:db8::/32", Country: "FR", City: "Paris"},
+	)
+	require.NoError(t, err)
+
+	asnDB := filepath.Join(testDir, "asn.mmdb")
+	err = testsupport.WriteGeoIPDatabase(asnDB,
+		testsupport.GeoIPEntry{Network: "192.0.2.0/24", ASN: 3320, ASOrganization: "Deutsche Telekom AG"},
+	)
+	require.NoError(t, err)
+
+	cw := mocks.NewWatcherMock(t)
+	cw.EXPECT().Add(mock.Anything, mock.Anything).Return(nil)
+
+	db, err := NewDatabase([]string{cityDB, asnDB}, cw)
+	require.NoError(t, err)
+
+	for uc, tc := range map[string]struct {
+		ip     string
+		assert func(t *testing.T, err error, rec Record)
+	}{
+		"invalid ip address": {
+			ip: "foo",
+			assert: func(t *testing.T, err error, _ Record) {
+				t.Helper()
+
+				require.ErrorIs(t, err, ErrInvalidIPAddress)
+			},
+		},
+		"ip v4 address known to all databases": {
+			ip: "192.0.2.10",
+			assert: func(t *testing.T, err error, rec Record) {
+				t.Helper()
+
+				require.NoError(t, err)
+				assert.Equal(t, Record{
+					Country: "DE", City: "Berlin", ASN: 3320, ASOrganization: "Deutsche Telekom AG",
+				}, rec)
+			},
+		},
+		"ip v6 address known to one database": {
+			ip: "2001:db8::1",
+			assert: func(t *testing.T, err error, rec Record) {
+				t.Helper()
+
+				require.NoError(t, err)
+				assert.Equal(t, Record{Country: "FR", City: "Paris"}, rec)
+			},
+		},
+		"unknown ip address": {
+			ip: "198.51.100.1",
+			assert: func(t *testing.T, err error, rec Record) {
+				t.Helper()
+
+				require.NoError(t, err)
+				assert.Equal(t, Record{}, rec)
+			},
+		},
+	} {
+		t.Run(uc, func(t *testing.T) {
+			rec, err := db.Lookup(tc.ip)
+
+			tc.assert(t, err, rec)
+		})
+	}
+}
+
+func TestDatabaseReload(t *testing.T) {
+	t.Parallel()
+
+	dbFile := filepath.Join(t.TempDir(), "city.mmdb")
+	err := testsupport.WriteGeoIPDatabase(dbFile,
+		testsupport.GeoIPEntry{Network: "192.0.2.0/24", Country: "DE"})
+	require.NoError(t, err)
+
+	var listener watcher.ChangeListener
+
+	cw := mocks.NewWatcherMock(t)
+	cw.EXPECT().Add(dbFile, mock.Anything).
+		Run(func(_ string, cl watcher.ChangeListener) { listener = cl }).
+		Return(nil)
+
+	db, err := NewDatabase([]string{dbFile}, cw)
+	require.NoError(t, err)
+	require.NotNil(t, listener)
+
+	// update the database
+	err = testsupport.WriteGeoIPDatabase(dbFile,
+		testsupport.GeoIPEntry{Network: "192.0.2.0/24", Country: "AT"})
+	require.NoError(t, err)
+
+	listener.OnChanged(log.Logger)
+
+	rec, err := db.Lookup("192.0.2.1")
+	require.NoError(t, err)
+	assert.Equal(t, "AT", rec.Country)
+
+	// corrupt the database. The previously loaded data is kept
+	err = os.WriteFile(dbFile, []byte("foo"), 0o600)
+	require.NoError(t, err)
+
+	listener.OnChanged(log.Logger)
+
+	rec, err = db.Lookup("192.0.2.1")
+	require.NoError(t, err)
+	assert.Equal(t, "AT", rec.Country)
+}
@@ -0,0 +1,48 @@
+// Copyright 2025 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package geoip
+
+import (
+	"github.com/rs/zerolog"
+	"go.uber.org/fx"
+
+	"github.com/dadrus/heimdall/internal/config"
+	"github.com/dadrus/heimdall/internal/watcher"
+)
+
+// Module is used on app bootstrap.
+// nolint: gochecknoglobals
+var Module = fx.Provide(newResolver)
+
+// newResolver creates the Resolver for the configured databases. If no databases are
+// configured, no Resolver is available, which is represented by a nil value.
+func newResolver(cfg *config.Configuration, cw watcher.Watcher, logger zerolog.Logger) (Resolver, error) {
+	if len(cfg.GeoIP.Databases) == 0 {
+		logger.Info().Msg("No geoip databases configured")
+
+		return nil, nil //nolint:nilnil
+	}
+
+	db, err := NewDatabase(cfg.GeoIP.Databases, cw)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info().Strs("_databases", cfg.GeoIP.Databases).Msg("GeoIP databases loaded")
+
+	return db, nil
+}
@@ -0,0 +1,86 @@
+// Copyright 2025 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package geoip
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dadrus/heimdall/internal/config"
+	"github.com/dadrus/heimdall/internal/heimdall"
+	"github.com/dadrus/heimdall/internal/watcher"
+	"github.com/dadrus/heimdall/internal/x/testsupport"
+)
+
+func TestNewResolver(t *testing.T) {
+	t.Parallel()
+
+	dbFile := filepath.Join(t.TempDir(), "city.mmdb")
+	err := testsupport.WriteGeoIPDatabase(dbFile,
+		testsupport.GeoIPEntry{Network: "192.0.2.0/24", Country: "DE"})
+	require.NoError(t, err)
+
+	for uc, tc := range map[string]struct {
+		databases []string
+		assert    func(t *testing.T, err error, resolver Resolver)
+	}{
+		"without configured databases": {
+			assert: func(t *testing.T, err error, resolver Resolver) {
+				t.Helper()
+
+				require.NoError(t, err)
+				assert.Nil(t, resolver)
+			},
+		},
+		"with not existing database": {
+			databases: []string{filepath.Join(t.TempDir(), "foo.mmdb")},
+			assert: func(t *testing.T, err error, _ Resolver) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+			},
+		},
+		"with configured database": {
+			databases: []string{dbFile},
+			assert: func(t *testing.T, err error, resolver Resolver) {
+				t.Helper()
+
+				require.NoError(t, err)
+				require.NotNil(t, resolver)
+
+				rec, err := resolver.Lookup("192.0.2.1")
+				require.NoError(t, err)
+				assert.Equal(t, "DE", rec.Country)
+			},
+		},
+	} {
+		t.Run(uc, func(t *testing.T) {
+			conf := &config.Configuration{GeoIP: config.GeoIPConfig{Databases: tc.databases}}
+
+			// WHEN
+			resolver, err := newResolver(conf, &watcher.NoopWatcher{}, log.Logger)
+
+			// THEN
+			tc.assert(t, err, resolver)
+		})
+	}
+}
@@ -18,6 +18,8 @@ package decision
 
 import (
 	"net/http"
+	"slices"
+	"strings"
 
 	"github.com/rs/zerolog"
 
@@ -33,6 +35,7 @@ func newContextFactory(
 			RequestContext: requestcontext.New(req),
 			responseCode:   responseCode,
 			rw:             rw,
+			req:            req,
 		}
 	})
 }
@@ -41,6 +44,7 @@ type requestContext struct {
 	*requestcontext.RequestContext
 
 	rw           http.ResponseWriter
+	req          *http.Request
 	responseCode int
 }
 
@@ -49,7 +53,18 @@ func (r *requestContext) Finalize(_ rule.Backend) error {
 		return err
 	}
 
-	zerolog.Ctx(r.Context()).Debug().Msg("Creating response")
+	logger := zerolog.Ctx(r.Context())
+	logger.Debug().Msg("Creating response")
+
+	if len(r.RemovedUpstreamHeaders()) != 0 || len(r.RemovedUpstreamCookies()) != 0 {
+		logger.Warn().Msg("Removing headers or cookies from the upstream request is not supported " +
+			"in decision mode. Ignoring it")
+	}
+
+	if r.UpstreamURLModified() {
+		logger.Warn().Msg("Modifying the path or the query of the upstream request is not supported " +
+			"in decision mode. Ignoring it")
+	}
 
 	uh := r.UpstreamHeaders()
 	for name, values := range uh {
@@ -62,7 +77,72 @@ func (r *requestContext) Finalize(_ rule.Backend) error {
 		http.SetCookie(r.rw, &http.Cookie{Name: k, Value: v})
 	}
 
+	if err := r.signUpstreamRequest(); err != nil {
+		return err
+	}
+
 	r.rw.WriteHeader(r.responseCode)
 
 	return nil
 }
+
+// signUpstreamRequest applies the registered upstream request signers to a representation of the
+// request, the calling proxy will forward to the upstream service, and sets the headers added by the
+// signers to the response. That way the proxy can take them over like any other upstream header.
+func (r *requestContext) signUpstreamRequest() error {
+	signers := r.UpstreamSigners()
+	if len(signers) == 0 {
+		return nil
+	}
+
+	hmdlReq := r.Request()
+	reqURL := hmdlReq.URL.URL
+
+	req := r.req.Clone(r.Context())
+	req.Method = hmdlReq.Method
+	req.URL = &reqURL
+	req.Host = reqURL.Host
+
+	// the X-Forwarded-* headers are used by the proxy to communicate the details of the request
+	// to heimdall only and are not sent to the upstream service
+	for name := range req.Header {
+		if strings.HasPrefix(name, "X-Forwarded-") {
+			req.Header.Del(name)
+		}
+	}
+
+	uh := r.UpstreamHeaders()
+	for name := range uh {
+		req.Header.Del(name)
+	}
+
+	for name, values := range uh {
+		for _, value := range values {
+			req.Header.Add(name, value)
+		}
+	}
+
+	for k, v := range r.UpstreamCookies() {
+		req.AddCookie(&http.Cookie{Name: k, Value: v})
+	}
+
+	original := req.Header.Clone()
+
+	if err := requestcontext.SignRequest(req, signers); err != nil {
+		return err
+	}
+
+	for name, values := range req.Header {
+		if slices.Equal(original.Values(name), values) {
+			continue
+		}
+
+		r.rw.Header().Del(name)
+
+		for _, value := range values {
+			r.rw.Header().Add(name, value)
+		}
+	}
+
+	return nil
+}
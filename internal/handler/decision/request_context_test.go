@@ -22,10 +22,16 @@ import (
 	"net/http/httptest"
 	"testing"
 
+	"github.com/dadrus/httpsig"
+	"github.com/rs/zerolog"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
 	"github.com/dadrus/heimdall/internal/handler/requestcontext"
+	"github.com/dadrus/heimdall/internal/heimdall"
+	"github.com/dadrus/heimdall/internal/heimdall/mocks"
+	"github.com/dadrus/heimdall/internal/x/testsupport"
 )
 
 func TestRequestContextFinalize(t *testing.T) {
@@ -141,6 +147,52 @@ func TestRequestContextFinalize(t *testing.T) {
 				assert.Equal(t, http.StatusOK, rec.Code)
 			},
 		},
+		"headers set by upstream signers are added": {
+			code: http.StatusOK,
+			setup: func(t *testing.T, rc requestcontext.Context) {
+				t.Helper()
+
+				signer := mocks.NewUpstreamRequestSignerMock(t)
+				signer.EXPECT().Sign(mock.Anything).Run(func(req *http.Request) {
+					assert.Equal(t, http.MethodPost, req.Method)
+					assert.Equal(t, "http://heimdall.local/foo", req.URL.String())
+					assert.Equal(t, "bar", req.Header.Get("X-Foo"))
+					assert.Equal(t, "baz", req.Header.Get("X-Bar"))
+
+					req.Header.Set("Signature", "sig=:Zm9v:")
+				}).Return(nil)
+
+				rc.AddHeaderForUpstream("X-Foo", "bar")
+				rc.AddSignerForUpstream(signer)
+			},
+			assert: func(t *testing.T, err error, rec *httptest.ResponseRecorder) {
+				t.Helper()
+
+				require.NoError(t, err)
+
+				assert.Len(t, rec.Header(), 2)
+				assert.Equal(t, "bar", rec.Header().Get("X-Foo"))
+				assert.Equal(t, "sig=:Zm9v:", rec.Header().Get("Signature"))
+				assert.Equal(t, http.StatusOK, rec.Code)
+			},
+		},
+		"upstream signer fails": {
+			code: http.StatusOK,
+			setup: func(t *testing.T, rc requestcontext.Context) {
+				t.Helper()
+
+				signer := mocks.NewUpstreamRequestSignerMock(t)
+				signer.EXPECT().Sign(mock.Anything).Return(heimdall.ErrInternal)
+
+				rc.AddSignerForUpstream(signer)
+			},
+			assert: func(t *testing.T, err error, _ *httptest.ResponseRecorder) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrInternal)
+			},
+		},
 	} {
 		t.Run(uc, func(t *testing.T) {
 			// GIVEN
@@ -148,6 +200,7 @@ func TestRequestContextFinalize(t *testing.T) {
 
 			req, err := http.NewRequestWithContext(t.Context(), http.MethodPost, "http://heimdall.local/foo", nil)
 			require.NoError(t, err)
+			req.Header.Set("X-Bar", "baz")
 
 			reqCtx := newContextFactory(tc.code).Create(rw, req)
 			tc.setup(t, reqCtx)
@@ -160,3 +213,138 @@ func TestRequestContextFinalize(t *testing.T) {
 		})
 	}
 }
+
+func TestRequestContextFinalizeSignsForwardedAuthority(t *testing.T) {
+	t.Parallel()
+
+	// GIVEN
+	key := httpsig.Key{KeyID: "test", Algorithm: httpsig.HmacSha256, Key: []byte("supersecretsupersecretsupersecret")}
+
+	sigSigner, err := httpsig.NewSigner(key,
+		httpsig.WithTag("heimdall"),
+		httpsig.WithComponents("@method", "@authority", "@path", "x-foo"),
+	)
+	require.NoError(t, err)
+
+	signer := mocks.NewUpstreamRequestSignerMock(t)
+	signer.EXPECT().Sign(mock.Anything).RunAndReturn(func(req *http.Request) error {
+		assert.Empty(t, req.Header.Get("X-Forwarded-Host"))
+		assert.Empty(t, req.Header.Get("X-Forwarded-Proto"))
+		assert.Empty(t, req.Header.Get("X-Forwarded-Uri"))
+
+		header, err := sigSigner.Sign(httpsig.MessageFromRequest(req))
+		if err != nil {
+			return err
+		}
+
+		for name, values := range header {
+			req.Header[name] = values
+		}
+
+		return nil
+	})
+
+	rw := httptest.NewRecorder()
+
+	req, err := http.NewRequestWithContext(t.Context(), http.MethodPost, "http://heimdall.local/decisions/foo", nil)
+	require.NoError(t, err)
+	req.Header.Set("X-Forwarded-Host", "upstream.local")
+	req.Header.Set("X-Forwarded-Proto", "https")
+	req.Header.Set("X-Forwarded-Uri", "/foo")
+	req.Header.Set("X-Forwarded-Method", http.MethodGet)
+
+	reqCtx := newContextFactory(http.StatusOK).Create(rw, req)
+	reqCtx.AddHeaderForUpstream("X-Foo", "bar")
+	reqCtx.AddSignerForUpstream(signer)
+
+	// WHEN
+	err = reqCtx.Finalize(nil)
+
+	// THEN
+	require.NoError(t, err)
+
+	assert.Contains(t, rw.Header().Get("Signature-Input"), `("@method" "@authority" "@path" "x-foo")`)
+
+	// the request as forwarded by the proxy to the upstream service
+	upstreamReq, err := http.NewRequestWithContext(t.Context(), http.MethodGet, "https://upstream.local/foo", nil)
+	require.NoError(t, err)
+
+	for name, values := range rw.Header() {
+		upstreamReq.Header[name] = values
+	}
+
+	verifier, err := httpsig.NewVerifier(key,
+		httpsig.WithRequiredTag("heimdall", httpsig.WithRequiredComponents("@authority")))
+	require.NoError(t, err)
+
+	require.NoError(t, verifier.Verify(httpsig.MessageFromRequest(upstreamReq)))
+
+	// a signature covering heimdall's authority must not be valid
+	upstreamReq.Host = "heimdall.local"
+	require.Error(t, verifier.Verify(httpsig.MessageFromRequest(upstreamReq)))
+}
+
+func TestRequestContextFinalizeWarnsAboutUnsupportedModifications(t *testing.T) {
+	t.Parallel()
+
+	for uc, tc := range map[string]struct {
+		setup   func(rc requestcontext.Context)
+		warning string
+	}{
+		"without unsupported modifications": {
+			setup: func(rc requestcontext.Context) { rc.AddHeaderForUpstream("X-Foo", "bar") },
+		},
+		"with removed header": {
+			setup:   func(rc requestcontext.Context) { rc.RemoveHeaderForUpstream("X-Foo") },
+			warning: "Removing headers or cookies from the upstream request is not supported",
+		},
+		"with removed cookie": {
+			setup:   func(rc requestcontext.Context) { rc.RemoveCookieForUpstream("foo") },
+			warning: "Removing headers or cookies from the upstream request is not supported",
+		},
+		"with set query parameter": {
+			setup:   func(rc requestcontext.Context) { rc.SetQueryParameterForUpstream("foo", "bar") },
+			warning: "Modifying the path or the query of the upstream request is not supported",
+		},
+		"with removed query parameter": {
+			setup:   func(rc requestcontext.Context) { rc.RemoveQueryParameterForUpstream("foo") },
+			warning: "Modifying the path or the query of the upstream request is not supported",
+		},
+		"with set path": {
+			setup:   func(rc requestcontext.Context) { rc.SetPathForUpstream("/bar") },
+			warning: "Modifying the path or the query of the upstream request is not supported",
+		},
+		"with path prefix": {
+			setup:   func(rc requestcontext.Context) { rc.AddPathPrefixForUpstream("/api") },
+			warning: "Modifying the path or the query of the upstream request is not supported",
+		},
+	} {
+		t.Run(uc, func(t *testing.T) {
+			// GIVEN
+			tb := &testsupport.TestingLog{TB: t}
+			logger := zerolog.New(zerolog.TestWriter{T: tb})
+			rw := httptest.NewRecorder()
+
+			req, err := http.NewRequestWithContext(
+				logger.WithContext(t.Context()), http.MethodGet, "http://heimdall.local/foo", nil)
+			require.NoError(t, err)
+
+			reqCtx := newContextFactory(http.StatusOK).Create(rw, req)
+			tc.setup(reqCtx)
+
+			// WHEN
+			err = reqCtx.Finalize(nil)
+
+			// THEN
+			require.NoError(t, err)
+			assert.Equal(t, http.StatusOK, rw.Code)
+
+			if len(tc.warning) != 0 {
+				assert.Contains(t, tb.CollectedLog(), `"level":"warn"`)
+				assert.Contains(t, tb.CollectedLog(), tc.warning)
+			} else {
+				assert.NotContains(t, tb.CollectedLog(), `"level":"warn"`)
+			}
+		})
+	}
+}
@@ -52,11 +52,14 @@ func newService(
 	cfg := conf.Serve
 	eh := errorhandler.New(
 		errorhandler.WithVerboseErrors(cfg.Respond.Verbose),
+		errorhandler.WithProblemDetails(cfg.Respond.ProblemDetails),
 		errorhandler.WithPreconditionErrorCode(cfg.Respond.With.ArgumentError.Code),
 		errorhandler.WithAuthenticationErrorCode(cfg.Respond.With.AuthenticationError.Code),
 		errorhandler.WithAuthorizationErrorCode(cfg.Respond.With.AuthorizationError.Code),
 		errorhandler.WithCommunicationErrorCode(cfg.Respond.With.CommunicationError.Code),
 		errorhandler.WithNoRuleErrorCode(cfg.Respond.With.NoRuleError.Code),
+		errorhandler.WithRateLimitErrorCode(cfg.Respond.With.RateLimitError.Code),
+		errorhandler.WithServiceUnavailableErrorCode(cfg.Respond.With.ServiceUnavailableError.Code),
 		errorhandler.WithInternalServerErrorCode(cfg.Respond.With.InternalError.Code),
 	)
 	acceptedCode := x.IfThenElse(cfg.Respond.With.Accepted.Code != 0, cfg.Respond.With.Accepted.Code, http.StatusOK)
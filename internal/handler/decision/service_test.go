@@ -170,6 +170,48 @@ func TestHandleDecisionEndpointRequest(t *testing.T) {
 				assert.Empty(t, data)
 			},
 		},
+		{
+			uc: "rule execution fails with authorization error rendered as problem details",
+			serviceConf: config.ServeConfig{
+				TrustedProxies: []string{"0.0.0.0/0"},
+				Respond:        config.RespondConfig{ProblemDetails: true},
+			},
+			createRequest: func(t *testing.T, host string) *http.Request {
+				t.Helper()
+
+				req, err := http.NewRequestWithContext(
+					t.Context(),
+					http.MethodPost,
+					fmt.Sprintf("http://%s/decisions", host),
+					nil,
+				)
+				require.NoError(t, err)
+
+				req.Header.Set("X-Forwarded-Host", "test.com")
+				req.Header.Set("X-Forwarded-Uri", "/bar/baz?foo=bar")
+				req.Header.Set("X-Forwarded-Method", http.MethodGet)
+
+				return req
+			},
+			configureMocks: func(t *testing.T, exec *mocks4.ExecutorMock) {
+				t.Helper()
+
+				exec.EXPECT().Execute(mock.Anything).Return(nil, heimdall.ErrAuthorization)
+			},
+			assertResponse: func(t *testing.T, err error, response *http.Response) {
+				t.Helper()
+
+				require.NoError(t, err)
+				assert.Equal(t, http.StatusForbidden, response.StatusCode)
+				assert.Equal(t, "application/problem+json", response.Header.Get("Content-Type"))
+
+				data, err := io.ReadAll(response.Body)
+				require.NoError(t, err)
+				assert.JSONEq(t,
+					`{"type":"about:blank","title":"Forbidden","status":403,"instance":"/bar/baz"}`,
+					string(data))
+			},
+		},
 		{
 			uc: "successful rule execution - request method, path and hostname " +
 				"are taken from the real request (trusted proxy not configured)",
@@ -17,11 +17,14 @@
 package grpcv3
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"net/http"
 	"net/url"
+	"slices"
 	"strings"
+	"sync"
 
 	envoy_core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
 	envoy_auth "github.com/envoyproxy/go-control-plane/envoy/service/auth/v3"
@@ -30,9 +33,11 @@ import (
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
 
+	"github.com/dadrus/heimdall/internal/handler/requestcontext"
 	"github.com/dadrus/heimdall/internal/heimdall"
 	"github.com/dadrus/heimdall/internal/rules/mechanisms/contenttype"
 	"github.com/dadrus/heimdall/internal/x"
+	"github.com/dadrus/heimdall/internal/x/errorchain"
 )
 
 type RequestContext struct {
@@ -45,8 +50,18 @@ type RequestContext struct {
 	reqRawBody      []byte
 	upstreamHeaders http.Header
 	upstreamCookies map[string]string
+	upstreamSigners []heimdall.UpstreamRequestSigner
+	removedHeaders  []string
+	removedCookies  []string
+	upstreamQuery   map[string]string
+	removedQuery    []string
+	pathModified    bool
 	err             error
 
+	// the body is decoded lazily. As it can be accessed concurrently (e.g. by steps of a
+	// parallel group), decoding is guarded
+
+	bodyOnce  sync.Once
 	savedBody any
 	outputs   map[string]any
 }
@@ -77,6 +92,7 @@ func NewRequestContext(ctx context.Context, req *envoy_auth.CheckRequest) *Reque
 		reqRawBody:      req.GetAttributes().GetRequest().GetHttp().GetRawBody(),
 		upstreamHeaders: make(http.Header),
 		upstreamCookies: make(map[string]string),
+		upstreamQuery:   make(map[string]string),
 	}
 }
 
@@ -118,25 +134,23 @@ func (r *RequestContext) Cookie(name string) string {
 }
 
 func (r *RequestContext) Body() any {
-	if r.savedBody == nil {
-		decoder, err := contenttype.NewDecoder(r.Header("Content-Type"))
-		if err != nil {
-			r.savedBody = string(r.reqRawBody)
-
-			return r.savedBody
-		}
+	r.bodyOnce.Do(func() { r.savedBody = r.decodeBody() })
 
-		data, err := decoder.Decode(r.reqRawBody)
-		if err != nil {
-			r.savedBody = string(r.reqRawBody)
+	return r.savedBody
+}
 
-			return r.savedBody
-		}
+func (r *RequestContext) decodeBody() any {
+	decoder, err := contenttype.NewDecoder(r.Header("Content-Type"))
+	if err != nil {
+		return string(r.reqRawBody)
+	}
 
-		r.savedBody = data
+	data, err := decoder.Decode(r.reqRawBody)
+	if err != nil {
+		return string(r.reqRawBody)
 	}
 
-	return r.savedBody
+	return data
 }
 
 func (r *RequestContext) Context() context.Context                { return r.ctx }
@@ -144,6 +158,31 @@ func (r *RequestContext) SetPipelineError(err error)              { r.err = err
 func (r *RequestContext) AddHeaderForUpstream(name, value string) { r.upstreamHeaders.Add(name, value) }
 func (r *RequestContext) AddCookieForUpstream(name, value string) { r.upstreamCookies[name] = value }
 
+func (r *RequestContext) RemoveHeaderForUpstream(name string) {
+	r.removedHeaders = append(r.removedHeaders, http.CanonicalHeaderKey(name))
+}
+
+func (r *RequestContext) RemoveCookieForUpstream(name string) {
+	r.removedCookies = append(r.removedCookies, name)
+}
+
+func (r *RequestContext) SetQueryParameterForUpstream(name, value string) {
+	r.upstreamQuery[name] = value
+}
+
+func (r *RequestContext) RemoveQueryParameterForUpstream(name string) {
+	r.removedQuery = append(r.removedQuery, name)
+}
+
+// SetPathForUpstream and AddPathPrefixForUpstream only record the modification, as envoy does not
+// support rewriting the path of the request based on the response of the authorization service.
+func (r *RequestContext) SetPathForUpstream(_ string)       { r.pathModified = true }
+func (r *RequestContext) AddPathPrefixForUpstream(_ string) { r.pathModified = true }
+
+func (r *RequestContext) AddSignerForUpstream(signer heimdall.UpstreamRequestSigner) {
+	r.upstreamSigners = append(r.upstreamSigners, signer)
+}
+
 func (r *RequestContext) Outputs() map[string]any {
 	if r.outputs == nil {
 		r.outputs = make(map[string]any)
@@ -157,10 +196,20 @@ func (r *RequestContext) Finalize() (*envoy_auth.CheckResponse, error) {
 		return nil, r.err
 	}
 
-	zerolog.Ctx(r.ctx).Debug().Msg("Creating response")
+	logger := zerolog.Ctx(r.ctx)
+	logger.Debug().Msg("Creating response")
+
+	if r.pathModified {
+		logger.Warn().Msg("Rewriting the path of the upstream request is not supported by envoy. Ignoring it")
+	}
+
+	if err := r.signUpstreamRequest(); err != nil {
+		return nil, err
+	}
 
+	cookies := r.cookiesForUpstream()
 	headers := make([]*envoy_core.HeaderValueOption,
-		len(r.upstreamHeaders)+x.IfThenElse(len(r.upstreamCookies) == 0, 0, 1))
+		len(r.upstreamHeaders)+x.IfThenElse(len(cookies) == 0, 0, 1))
 	hidx := 0
 
 	for k := range r.upstreamHeaders {
@@ -174,15 +223,7 @@ func (r *RequestContext) Finalize() (*envoy_auth.CheckResponse, error) {
 		hidx++
 	}
 
-	if len(r.upstreamCookies) != 0 {
-		cookies := make([]string, len(r.upstreamCookies))
-		cidx := 0
-
-		for k, v := range r.upstreamCookies {
-			cookies[cidx] = fmt.Sprintf("%s=%s", k, v)
-			cidx++
-		}
-
+	if len(cookies) != 0 {
 		headers[hidx] = &envoy_core.HeaderValueOption{
 			Header: &envoy_core.HeaderValue{
 				Key:   "Cookie",
@@ -194,7 +235,155 @@ func (r *RequestContext) Finalize() (*envoy_auth.CheckResponse, error) {
 	return &envoy_auth.CheckResponse{
 		Status: &status.Status{Code: int32(codes.OK)},
 		HttpResponse: &envoy_auth.CheckResponse_OkResponse{
-			OkResponse: &envoy_auth.OkHttpResponse{Headers: headers},
+			OkResponse: &envoy_auth.OkHttpResponse{
+				Headers:                 headers,
+				HeadersToRemove:         r.headersToRemove(len(cookies) == 0),
+				QueryParametersToSet:    r.queryParametersToSet(),
+				QueryParametersToRemove: r.removedQuery,
+			},
 		},
 	}, nil
 }
+
+// cookiesForUpstream returns the cookies to be set in the Cookie header of the request forwarded to
+// the upstream service. If cookies should be removed, the cookies of the original request, which
+// have not been removed, are included as well, as envoy can only replace the Cookie header as a whole.
+func (r *RequestContext) cookiesForUpstream() []string {
+	var cookies []string
+
+	if len(r.removedCookies) != 0 {
+		for _, cookie := range strings.Split(r.reqHeaders["Cookie"], ";") {
+			name, _, ok := strings.Cut(cookie, "=")
+			name = strings.TrimSpace(name)
+
+			if !ok || slices.Contains(r.removedCookies, name) {
+				continue
+			}
+
+			if _, overridden := r.upstreamCookies[name]; !overridden {
+				cookies = append(cookies, strings.TrimSpace(cookie))
+			}
+		}
+	}
+
+	for k, v := range r.upstreamCookies {
+		cookies = append(cookies, fmt.Sprintf("%s=%s", k, v))
+	}
+
+	return cookies
+}
+
+func (r *RequestContext) queryParametersToSet() []*envoy_core.QueryParameter {
+	if len(r.upstreamQuery) == 0 {
+		return nil
+	}
+
+	params := make([]*envoy_core.QueryParameter, 0, len(r.upstreamQuery))
+	for name, value := range r.upstreamQuery {
+		params = append(params, &envoy_core.QueryParameter{Key: name, Value: value})
+	}
+
+	return params
+}
+
+// headersToRemove returns the headers envoy should remove from the request before forwarding it to
+// the upstream service. Headers set by heimdall are not included as they replace the original ones anyway.
+func (r *RequestContext) headersToRemove(noCookies bool) []string {
+	var names []string
+
+	for _, name := range r.removedHeaders {
+		if _, set := r.upstreamHeaders[name]; set || name == "Cookie" && !noCookies {
+			continue
+		}
+
+		names = append(names, strings.ToLower(name))
+	}
+
+	if noCookies && len(r.removedCookies) != 0 && !slices.Contains(names, "cookie") {
+		names = append(names, "cookie")
+	}
+
+	return names
+}
+
+// signUpstreamRequest applies the registered upstream request signers to a representation of the
+// request, envoy will forward to the upstream service, and adds the headers set by the signers to
+// the upstream headers.
+func (r *RequestContext) signUpstreamRequest() error {
+	if len(r.upstreamSigners) == 0 {
+		return nil
+	}
+
+	reqURL := *r.reqURL
+
+	if len(r.upstreamQuery) != 0 || len(r.removedQuery) != 0 {
+		query := reqURL.Query()
+
+		for _, name := range r.removedQuery {
+			query.Del(name)
+		}
+
+		for name, value := range r.upstreamQuery {
+			query.Set(name, value)
+		}
+
+		reqURL.RawQuery = query.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(r.ctx, r.reqMethod, reqURL.String(), bytes.NewReader(r.reqRawBody))
+	if err != nil {
+		return errorchain.NewWithMessage(heimdall.ErrInternal,
+			"failed to create request representation for signing").CausedBy(err)
+	}
+
+	req.Host = reqURL.Host
+
+	for name, value := range r.reqHeaders {
+		if name != "Host" && !strings.HasPrefix(name, ":") {
+			req.Header.Set(name, value)
+		}
+	}
+
+	for _, name := range r.removedHeaders {
+		req.Header.Del(name)
+	}
+
+	if len(r.removedCookies) != 0 {
+		cookies := req.Cookies()
+		req.Header.Del("Cookie")
+
+		for _, cookie := range cookies {
+			if !slices.Contains(r.removedCookies, cookie.Name) {
+				req.AddCookie(cookie)
+			}
+		}
+	}
+
+	for name := range r.upstreamHeaders {
+		req.Header.Del(name)
+	}
+
+	for name, values := range r.upstreamHeaders {
+		for _, value := range values {
+			req.Header.Add(name, value)
+		}
+	}
+
+	for k, v := range r.upstreamCookies {
+		req.AddCookie(&http.Cookie{Name: k, Value: v})
+	}
+
+	original := req.Header.Clone()
+
+	if err = requestcontext.SignRequest(req, r.upstreamSigners); err != nil {
+		return err
+	}
+
+	for name, values := range req.Header {
+		if !slices.Equal(original.Values(name), values) {
+			r.upstreamHeaders[name] = values
+		}
+	}
+
+	return nil
+}
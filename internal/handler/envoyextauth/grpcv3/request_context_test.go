@@ -18,6 +18,7 @@ package grpcv3
 
 import (
 	"errors"
+	"io"
 	"net/http"
 	"strings"
 	"testing"
@@ -25,11 +26,13 @@ import (
 	corev3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
 	envoy_auth "github.com/envoyproxy/go-control-plane/envoy/service/auth/v3"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
 
 	"github.com/dadrus/heimdall/internal/heimdall"
+	"github.com/dadrus/heimdall/internal/heimdall/mocks"
 )
 
 func TestNewRequestContext(t *testing.T) {
@@ -229,6 +232,133 @@ func TestFinalizeRequestContext(t *testing.T) {
 				require.Nil(t, response)
 			},
 		},
+		"successful with upstream signer": {
+			updateContext: func(t *testing.T, ctx heimdall.RequestContext) {
+				t.Helper()
+
+				signer := mocks.NewUpstreamRequestSignerMock(t)
+				signer.EXPECT().Sign(mock.Anything).Run(func(req *http.Request) {
+					assert.Equal(t, http.MethodPatch, req.Method)
+					assert.Equal(t, "https://foo.bar:8080/test?bar=moo#foobar", req.URL.String())
+					assert.Equal(t, "foo.bar:8080", req.Host)
+					assert.Equal(t, "barfoo", req.Header.Get("X-Foo-Bar"))
+					assert.Equal(t, "some-value", req.Header.Get("X-For-Upstream"))
+
+					body, err := io.ReadAll(req.Body)
+					require.NoError(t, err)
+					assert.Equal(t, "content=heimdall", string(body))
+
+					req.Header.Set("Signature", "sig=:Zm9v:")
+				}).Return(nil)
+
+				ctx.AddHeaderForUpstream("x-for-upstream", "some-value")
+				ctx.AddSignerForUpstream(signer)
+			},
+			assert: func(t *testing.T, err error, response *envoy_auth.CheckResponse) {
+				t.Helper()
+
+				require.NoError(t, err)
+				require.NotNil(t, response)
+
+				okResponse := response.GetOkResponse()
+				require.NotNil(t, okResponse)
+
+				require.Len(t, okResponse.GetHeaders(), 2)
+				header := findHeader(okResponse.GetHeaders(), "X-For-Upstream")
+				require.NotNil(t, header)
+				assert.Equal(t, "some-value", header.GetValue())
+				header = findHeader(okResponse.GetHeaders(), "Signature")
+				require.NotNil(t, header)
+				assert.Equal(t, "sig=:Zm9v:", header.GetValue())
+			},
+		},
+		"erroneous due to failing upstream signer": {
+			updateContext: func(t *testing.T, ctx heimdall.RequestContext) {
+				t.Helper()
+
+				signer := mocks.NewUpstreamRequestSignerMock(t)
+				signer.EXPECT().Sign(mock.Anything).Return(heimdall.ErrInternal)
+
+				ctx.AddSignerForUpstream(signer)
+			},
+			assert: func(t *testing.T, err error, response *envoy_auth.CheckResponse) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrInternal)
+				require.Nil(t, response)
+			},
+		},
+		"successful with removed headers and cookies": {
+			updateContext: func(t *testing.T, ctx heimdall.RequestContext) {
+				t.Helper()
+
+				ctx.RemoveHeaderForUpstream("x-foo-bar")
+				ctx.RemoveHeaderForUpstream("Authorization")
+				ctx.RemoveCookieForUpstream("bar")
+				ctx.AddHeaderForUpstream("Authorization", "Bearer foo")
+				ctx.AddCookieForUpstream("baz", "bar")
+			},
+			assert: func(t *testing.T, err error, response *envoy_auth.CheckResponse) {
+				t.Helper()
+
+				require.NoError(t, err)
+
+				okResponse := response.GetOkResponse()
+				require.NotNil(t, okResponse)
+
+				assert.Equal(t, []string{"x-foo-bar"}, okResponse.GetHeadersToRemove())
+
+				require.Len(t, okResponse.GetHeaders(), 2)
+				header := findHeader(okResponse.GetHeaders(), "Authorization")
+				require.NotNil(t, header)
+				assert.Equal(t, "Bearer foo", header.GetValue())
+				header = findHeader(okResponse.GetHeaders(), "Cookie")
+				require.NotNil(t, header)
+				assert.Equal(t, "foo=baz;baz=bar", header.GetValue())
+			},
+		},
+		"successful with query modifications": {
+			updateContext: func(t *testing.T, ctx heimdall.RequestContext) {
+				t.Helper()
+
+				ctx.SetQueryParameterForUpstream("user", "alice")
+				ctx.RemoveQueryParameterForUpstream("bar")
+				ctx.AddPathPrefixForUpstream("/ignored")
+			},
+			assert: func(t *testing.T, err error, response *envoy_auth.CheckResponse) {
+				t.Helper()
+
+				require.NoError(t, err)
+
+				okResponse := response.GetOkResponse()
+				require.NotNil(t, okResponse)
+
+				require.Len(t, okResponse.GetQueryParametersToSet(), 1)
+				assert.Equal(t, "user", okResponse.GetQueryParametersToSet()[0].GetKey())
+				assert.Equal(t, "alice", okResponse.GetQueryParametersToSet()[0].GetValue())
+				assert.Equal(t, []string{"bar"}, okResponse.GetQueryParametersToRemove())
+			},
+		},
+		"successful with all cookies removed": {
+			updateContext: func(t *testing.T, ctx heimdall.RequestContext) {
+				t.Helper()
+
+				ctx.RemoveCookieForUpstream("bar")
+				ctx.RemoveCookieForUpstream("foo")
+			},
+			assert: func(t *testing.T, err error, response *envoy_auth.CheckResponse) {
+				t.Helper()
+
+				require.NoError(t, err)
+
+				okResponse := response.GetOkResponse()
+				require.NotNil(t, okResponse)
+
+				assert.Empty(t, okResponse.GetHeaders())
+				assert.Equal(t, []string{"cookie"}, okResponse.GetHeadersToRemove())
+			},
+		},
 	} {
 		t.Run(uc, func(t *testing.T) {
 			// GIVEN
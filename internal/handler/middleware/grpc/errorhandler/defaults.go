@@ -28,5 +28,7 @@ var defaultOptions = opts{ //nolint:gochecknoglobals
 	communicationError:  responseWith(codes.DeadlineExceeded, http.StatusBadGateway),
 	preconditionError:   responseWith(codes.InvalidArgument, http.StatusBadRequest),
 	noRuleError:         responseWith(codes.NotFound, http.StatusNotFound),
+	rateLimitError:      responseWith(codes.ResourceExhausted, http.StatusTooManyRequests),
+	unavailableError:    responseWith(codes.Unavailable, http.StatusServiceUnavailable),
 	internalError:       responseWith(codes.Internal, http.StatusInternalServerError),
 }
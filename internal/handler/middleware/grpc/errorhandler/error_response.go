@@ -17,7 +17,9 @@
 package errorhandler
 
 import (
+	"context"
 	"encoding/xml"
+	"errors"
 	"fmt"
 
 	"github.com/elnormous/contenttype"
@@ -25,22 +27,30 @@ import (
 	envoy_auth "github.com/envoyproxy/go-control-plane/envoy/service/auth/v3"
 	envoy_type "github.com/envoyproxy/go-control-plane/envoy/type/v3"
 	"github.com/goccy/go-json"
+	"github.com/rs/zerolog"
 	"google.golang.org/genproto/googleapis/rpc/status"
 	"google.golang.org/grpc/codes"
 
+	"github.com/dadrus/heimdall/internal/handler/problemdetails"
+	"github.com/dadrus/heimdall/internal/heimdall"
 	"github.com/dadrus/heimdall/internal/x/stringx"
 )
 
-func responseWith(
-	grpcCode codes.Code, httpCodeOverride int,
-) func(err error, verbose bool, mimeType string) (any, error) {
-	return func(err error, verbose bool, mimeType string) (any, error) {
-		return errorResponse(grpcCode, httpCodeOverride, err, verbose, mimeType), nil
+type responseSettings struct {
+	verbose        bool
+	problemDetails bool
+	accept         string
+	instance       string
+}
+
+func responseWith(grpcCode codes.Code, httpCodeOverride int) responder {
+	return func(ctx context.Context, err error, settings responseSettings) (any, error) {
+		return errorResponse(ctx, grpcCode, httpCodeOverride, err, settings), nil
 	}
 }
 
 func errorResponse(
-	grpcCode codes.Code, httpCodeOverride int, decErr error, verbose bool, mimeType string,
+	ctx context.Context, grpcCode codes.Code, httpCodeOverride int, decErr error, settings responseSettings,
 ) *envoy_auth.CheckResponse {
 	deniedResponse := &envoy_auth.DeniedHttpResponse{
 		//nolint:gosec
@@ -48,11 +58,27 @@ func errorResponse(
 		Status: &envoy_type.HttpStatus{Code: envoy_type.StatusCode(httpCodeOverride)},
 	}
 
-	if verbose {
-		contentType := "text/html"
+	var (
+		contentType string
+		body        string
+	)
+
+	switch {
+	case settings.problemDetails:
+		contentType = problemdetails.MediaType(settings.accept)
+
+		res, err := problemdetails.New(ctx, httpCodeOverride, settings.instance, decErr, settings.verbose).
+			Marshal(contentType)
+		if err != nil {
+			zerolog.Ctx(ctx).Warn().Err(err).Msg("Rendering problem details failed. No body is sent")
+		}
+
+		body = stringx.ToString(res)
+	case settings.verbose:
+		contentType = "text/html"
 
 		mt, _, err := contenttype.GetAcceptableMediaTypeFromHeader(
-			mimeType, []contenttype.MediaType{
+			settings.accept, []contenttype.MediaType{
 				{Type: "application", Subtype: "json"},
 				{Type: "application", Subtype: "xml"},
 				{Type: "text", Subtype: "html"},
@@ -62,11 +88,20 @@ func errorResponse(
 			contentType = mt.MIME()
 		}
 
-		body, _ := format(contentType, decErr)
+		body, _ = format(contentType, decErr)
+	}
 
-		deniedResponse.Headers = []*envoy_core.HeaderValueOption{
-			{Header: &envoy_core.HeaderValue{Key: "Content-Type", Value: contentType}},
-		}
+	var retryAfterErr *heimdall.RetryAfterError
+	if errors.As(decErr, &retryAfterErr) {
+		deniedResponse.Headers = append(deniedResponse.Headers, &envoy_core.HeaderValueOption{
+			Header: &envoy_core.HeaderValue{Key: "Retry-After", Value: retryAfterErr.RetryAfter},
+		})
+	}
+
+	if len(body) != 0 {
+		deniedResponse.Headers = append(deniedResponse.Headers,
+			&envoy_core.HeaderValueOption{Header: &envoy_core.HeaderValue{Key: "Content-Type", Value: contentType}},
+		)
 		deniedResponse.Body = body
 	}
 
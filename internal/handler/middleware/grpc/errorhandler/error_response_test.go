@@ -38,9 +38,21 @@ func TestErrorResponse(t *testing.T) {
 		httpCode     int
 		err          error
 		offeredType  string
+		problem      bool
 		expectedType string
 		expBody      string
 	}{
+		{
+			uc:           "problem details",
+			grpcCode:     codes.PermissionDenied,
+			httpCode:     http.StatusForbidden,
+			err:          errorchain.NewWithMessage(heimdall.ErrAuthorization, "test"),
+			offeredType:  "application/json",
+			problem:      true,
+			expectedType: "application/problem+json",
+			expBody: `{"type":"about:blank","title":"Forbidden","status":403,` +
+				`"detail":"authorization error: test","instance":"/foo"}`,
+		},
 		{
 			uc:           "select text/plain from multiple offered",
 			grpcCode:     codes.NotFound,
@@ -89,7 +101,8 @@ func TestErrorResponse(t *testing.T) {
 	} {
 		t.Run(tc.uc, func(t *testing.T) {
 			// WHEN
-			resp := errorResponse(tc.grpcCode, tc.httpCode, tc.err, true, tc.offeredType)
+			resp := errorResponse(t.Context(), tc.grpcCode, tc.httpCode, tc.err,
+				responseSettings{verbose: true, problemDetails: tc.problem, accept: tc.offeredType, instance: "/foo"})
 
 			// THEN
 			require.NotNil(t, resp)
@@ -105,3 +118,21 @@ func TestErrorResponse(t *testing.T) {
 		})
 	}
 }
+
+func TestErrorResponseWithRetryAfter(t *testing.T) {
+	t.Parallel()
+
+	// GIVEN
+	err := errorchain.New(heimdall.ErrRateLimit).CausedBy(&heimdall.RetryAfterError{RetryAfter: "120"})
+
+	// WHEN
+	resp := errorResponse(t.Context(), codes.ResourceExhausted, http.StatusTooManyRequests, err, responseSettings{})
+
+	// THEN
+	deniedResp := resp.GetDeniedResponse()
+	require.NotNil(t, deniedResp)
+	require.Len(t, deniedResp.GetHeaders(), 1)
+	assert.Equal(t, "Retry-After", deniedResp.GetHeaders()[0].GetHeader().GetKey())
+	assert.Equal(t, "120", deniedResp.GetHeaders()[0].GetHeader().GetValue())
+	assert.Empty(t, deniedResp.GetBody())
+}
@@ -19,6 +19,9 @@ package errorhandler
 import (
 	"context"
 	"errors"
+	"maps"
+	"slices"
+	"strings"
 
 	envoy_core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
 	envoy_auth "github.com/envoyproxy/go-control-plane/envoy/service/auth/v3"
@@ -39,6 +42,19 @@ func New(opts ...Option) grpc.UnaryServerInterceptor {
 		opt(&options)
 	}
 
+	// rate limit and unavailability errors are communication errors as well. So these
+	// are answered with the code configured for communication errors, unless a
+	// dedicated one is configured
+	if options.communicationErrorCode > 0 {
+		if options.rateLimitErrorCode == 0 {
+			options.rateLimitError = responseWith(codes.ResourceExhausted, options.communicationErrorCode)
+		}
+
+		if options.unavailableErrorCode == 0 {
+			options.unavailableError = responseWith(codes.Unavailable, options.communicationErrorCode)
+		}
+	}
+
 	h := &interceptor{opts: options}
 
 	return h.intercept
@@ -51,6 +67,10 @@ type interceptor struct {
 func (h *interceptor) intercept(
 	ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler,
 ) (any, error) {
+	// the access context is created here to make data, like the id of the matched rule,
+	// set by the inner handlers, available for rendering the error response
+	ctx = accesscontext.New(ctx)
+
 	res, err := handler(ctx, req)
 	if err == nil {
 		return res, nil
@@ -58,17 +78,29 @@ func (h *interceptor) intercept(
 
 	accesscontext.SetError(ctx, err)
 
+	settings := responseSettings{
+		verbose:        h.verboseErrors,
+		problemDetails: h.problemDetails,
+		accept:         acceptType(req),
+		instance:       requestPath(req),
+	}
+
 	switch {
 	case errors.Is(err, heimdall.ErrAuthentication):
-		return h.authenticationError(err, h.verboseErrors, acceptType(req))
+		return h.authenticationError(ctx, err, settings)
 	case errors.Is(err, heimdall.ErrAuthorization):
-		return h.authorizationError(err, h.verboseErrors, acceptType(req))
-	case errors.Is(err, heimdall.ErrCommunicationTimeout) || errors.Is(err, heimdall.ErrCommunication):
-		return h.communicationError(err, h.verboseErrors, acceptType(req))
+		return h.authorizationError(ctx, err, settings)
+	case errors.Is(err, heimdall.ErrRateLimit):
+		return h.rateLimitError(ctx, err, settings)
+	case errors.Is(err, heimdall.ErrServiceUnavailable):
+		return h.unavailableError(ctx, err, settings)
+	case errors.Is(err, heimdall.ErrCommunicationTimeout) || errors.Is(err, heimdall.ErrCommunication) ||
+		errors.Is(err, heimdall.ErrGraphQL):
+		return h.communicationError(ctx, err, settings)
 	case errors.Is(err, heimdall.ErrArgument):
-		return h.preconditionError(err, h.verboseErrors, acceptType(req))
+		return h.preconditionError(ctx, err, settings)
 	case errors.Is(err, heimdall.ErrNoRuleFound):
-		return h.noRuleError(err, h.verboseErrors, acceptType(req))
+		return h.noRuleError(ctx, err, settings)
 	case errors.Is(err, &heimdall.RedirectError{}):
 		var redirectError *heimdall.RedirectError
 
@@ -92,12 +124,17 @@ func (h *interceptor) intercept(
 				},
 			},
 		}, nil
+	case errors.Is(err, &heimdall.ResponseError{}):
+		var responseError *heimdall.ResponseError
 
+		errors.As(err, &responseError)
+
+		return responseErrorResponse(responseError), nil
 	default:
 		logger := zerolog.Ctx(ctx)
 		logger.Error().Err(err).Msg("Internal error occurred")
 
-		return h.internalError(err, h.verboseErrors, acceptType(req))
+		return h.internalError(ctx, err, settings)
 	}
 }
 
@@ -109,3 +146,40 @@ func acceptType(req any) string {
 	// This should never happen as the API is typed
 	return ""
 }
+
+func requestPath(req any) string {
+	if req, ok := req.(*envoy_auth.CheckRequest); ok {
+		path := req.GetAttributes().GetRequest().GetHttp().GetPath()
+		if idx := strings.IndexByte(path, '?'); idx >= 0 {
+			return path[:idx]
+		}
+
+		return path
+	}
+
+	// This should never happen as the API is typed
+	return ""
+}
+
+func responseErrorResponse(err *heimdall.ResponseError) *envoy_auth.CheckResponse {
+	names := slices.Sorted(maps.Keys(err.Headers))
+	headers := make([]*envoy_core.HeaderValueOption, len(names))
+	for idx, name := range names {
+		headers[idx] = &envoy_core.HeaderValueOption{
+			Header: &envoy_core.HeaderValue{Key: name, Value: err.Headers[name]},
+		}
+	}
+
+	return &envoy_auth.CheckResponse{
+		Status: &status.Status{Code: int32(codes.PermissionDenied)},
+		HttpResponse: &envoy_auth.CheckResponse_DeniedResponse{
+			DeniedResponse: &envoy_auth.DeniedHttpResponse{
+				//nolint:gosec
+				// no integer overflow during conversion possible
+				Status:  &envoy_type.HttpStatus{Code: envoy_type.StatusCode(err.Code)},
+				Headers: headers,
+				Body:    err.Body,
+			},
+		},
+	}
+}
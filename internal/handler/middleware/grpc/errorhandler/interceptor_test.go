@@ -134,6 +134,13 @@ func TestErrorInterceptor(t *testing.T) {
 			expGRPCCode: codes.DeadlineExceeded,
 			expHTTPCode: http.StatusContinue,
 		},
+		{
+			uc:          "graphql error default",
+			interceptor: New(),
+			err:         heimdall.ErrGraphQL,
+			expGRPCCode: codes.DeadlineExceeded,
+			expHTTPCode: http.StatusBadGateway,
+		},
 		{
 			uc:          "communication error verbose",
 			interceptor: New(WithVerboseErrors(true)),
@@ -200,6 +207,70 @@ func TestErrorInterceptor(t *testing.T) {
 			expGRPCCode: codes.FailedPrecondition,
 			expHTTPCode: http.StatusFound,
 		},
+		{
+			uc:          "rate limit error default",
+			interceptor: New(),
+			err:         heimdall.ErrRateLimit,
+			expGRPCCode: codes.ResourceExhausted,
+			expHTTPCode: http.StatusTooManyRequests,
+		},
+		{
+			uc:          "rate limit error overridden",
+			interceptor: New(WithRateLimitErrorCode(http.StatusBadGateway)),
+			err:         heimdall.ErrRateLimit,
+			expGRPCCode: codes.ResourceExhausted,
+			expHTTPCode: http.StatusBadGateway,
+		},
+		{
+			uc:          "rate limit error with configured communication error code",
+			interceptor: New(WithCommunicationErrorCode(http.StatusInternalServerError)),
+			err:         heimdall.ErrRateLimit,
+			expGRPCCode: codes.ResourceExhausted,
+			expHTTPCode: http.StatusInternalServerError,
+		},
+		{
+			uc: "rate limit error with configured communication and rate limit error codes",
+			interceptor: New(
+				WithCommunicationErrorCode(http.StatusInternalServerError),
+				WithRateLimitErrorCode(http.StatusServiceUnavailable),
+			),
+			err:         heimdall.ErrRateLimit,
+			expGRPCCode: codes.ResourceExhausted,
+			expHTTPCode: http.StatusServiceUnavailable,
+		},
+		{
+			uc:          "service unavailable error with configured communication error code",
+			interceptor: New(WithCommunicationErrorCode(http.StatusInternalServerError)),
+			err:         heimdall.ErrServiceUnavailable,
+			expGRPCCode: codes.Unavailable,
+			expHTTPCode: http.StatusInternalServerError,
+		},
+		{
+			uc:          "service unavailable error default",
+			interceptor: New(),
+			err:         heimdall.ErrServiceUnavailable,
+			expGRPCCode: codes.Unavailable,
+			expHTTPCode: http.StatusServiceUnavailable,
+		},
+		{
+			uc:          "response error",
+			interceptor: New(),
+			err: &heimdall.ResponseError{
+				Code:    http.StatusForbidden,
+				Headers: map[string]string{"Content-Type": "application/json"},
+				Body:    `{"error": "forbidden"}`,
+			},
+			expGRPCCode: codes.PermissionDenied,
+			expHTTPCode: http.StatusForbidden,
+			expBody:     `{"error": "forbidden"}`,
+		},
+		{
+			uc:          "response error verbose without body",
+			interceptor: New(WithVerboseErrors(true)),
+			err:         &heimdall.ResponseError{Code: http.StatusTeapot},
+			expGRPCCode: codes.PermissionDenied,
+			expHTTPCode: http.StatusTeapot,
+		},
 		{
 			uc:          "internal error default",
 			interceptor: New(),
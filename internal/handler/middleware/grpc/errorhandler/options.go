@@ -16,16 +16,31 @@
 
 package errorhandler
 
-import "google.golang.org/grpc/codes"
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+)
+
+type responder func(ctx context.Context, err error, settings responseSettings) (any, error)
 
 type opts struct {
 	verboseErrors       bool
-	authenticationError func(err error, verbose bool, mimeType string) (any, error)
-	authorizationError  func(err error, verbose bool, mimeType string) (any, error)
-	communicationError  func(err error, verbose bool, mimeType string) (any, error)
-	preconditionError   func(err error, verbose bool, mimeType string) (any, error)
-	noRuleError         func(err error, verbose bool, mimeType string) (any, error)
-	internalError       func(err error, verbose bool, mimeType string) (any, error)
+	problemDetails      bool
+	authenticationError responder
+	authorizationError  responder
+	communicationError  responder
+	preconditionError   responder
+	noRuleError         responder
+	rateLimitError      responder
+	unavailableError    responder
+	internalError       responder
+
+	// the explicitly configured response codes, required to fall back to the code configured
+	// for communication errors, if there is none configured for rate limit and unavailability errors
+	communicationErrorCode int
+	rateLimitErrorCode     int
+	unavailableErrorCode   int
 }
 
 type Option func(*opts)
@@ -58,6 +73,7 @@ func WithCommunicationErrorCode(code int) Option {
 	return func(o *opts) {
 		if code > 0 {
 			o.communicationError = responseWith(codes.DeadlineExceeded, code)
+			o.communicationErrorCode = code
 		}
 	}
 }
@@ -83,3 +99,27 @@ func WithVerboseErrors(flag bool) Option {
 		o.verboseErrors = flag
 	}
 }
+
+func WithRateLimitErrorCode(code int) Option {
+	return func(o *opts) {
+		if code > 0 {
+			o.rateLimitError = responseWith(codes.ResourceExhausted, code)
+			o.rateLimitErrorCode = code
+		}
+	}
+}
+
+func WithServiceUnavailableErrorCode(code int) Option {
+	return func(o *opts) {
+		if code > 0 {
+			o.unavailableError = responseWith(codes.Unavailable, code)
+			o.unavailableErrorCode = code
+		}
+	}
+}
+
+func WithProblemDetails(flag bool) Option {
+	return func(o *opts) {
+		o.problemDetails = flag
+	}
+}
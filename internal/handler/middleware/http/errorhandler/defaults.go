@@ -27,6 +27,8 @@ func defaultOptions() *opts {
 	defaults.onCommunicationError = errorWriter(defaults, http.StatusBadGateway)
 	defaults.onPreconditionError = errorWriter(defaults, http.StatusBadRequest)
 	defaults.onNoRuleError = errorWriter(defaults, http.StatusNotFound)
+	defaults.onRateLimitError = errorWriter(defaults, http.StatusTooManyRequests)
+	defaults.onUnavailableError = errorWriter(defaults, http.StatusServiceUnavailable)
 	defaults.onInternalError = errorWriter(defaults, http.StatusInternalServerError)
 
 	return defaults
@@ -24,6 +24,7 @@ import (
 
 	"github.com/dadrus/heimdall/internal/accesscontext"
 	"github.com/dadrus/heimdall/internal/heimdall"
+	"github.com/dadrus/heimdall/internal/x/stringx"
 )
 
 //go:generate mockery --name ErrorHandler --structname ErrorHandlerMock
@@ -39,6 +40,19 @@ func New(opts ...Option) ErrorHandler {
 		opt(options)
 	}
 
+	// rate limit and unavailability errors are communication errors as well. So these
+	// are answered with the code configured for communication errors, unless a
+	// dedicated one is configured
+	if options.communicationErrorCode != 0 {
+		if options.rateLimitErrorCode == 0 {
+			options.onRateLimitError = errorWriter(options, options.communicationErrorCode)
+		}
+
+		if options.unavailableErrorCode == 0 {
+			options.onUnavailableError = errorWriter(options, options.communicationErrorCode)
+		}
+	}
+
 	return &errorHandler{opts: options}
 }
 
@@ -54,7 +68,12 @@ func (h *errorHandler) HandleError(rw http.ResponseWriter, req *http.Request, er
 		h.onAuthenticationError(rw, req, err)
 	case errors.Is(err, heimdall.ErrAuthorization):
 		h.onAuthorizationError(rw, req, err)
-	case errors.Is(err, heimdall.ErrCommunicationTimeout) || errors.Is(err, heimdall.ErrCommunication):
+	case errors.Is(err, heimdall.ErrRateLimit):
+		h.onRateLimitError(rw, req, err)
+	case errors.Is(err, heimdall.ErrServiceUnavailable):
+		h.onUnavailableError(rw, req, err)
+	case errors.Is(err, heimdall.ErrCommunicationTimeout) || errors.Is(err, heimdall.ErrCommunication) ||
+		errors.Is(err, heimdall.ErrGraphQL):
 		h.onCommunicationError(rw, req, err)
 	case errors.Is(err, heimdall.ErrArgument):
 		h.onPreconditionError(rw, req, err)
@@ -69,6 +88,22 @@ func (h *errorHandler) HandleError(rw http.ResponseWriter, req *http.Request, er
 		rw.WriteHeader(redirectError.Code)
 
 		return
+	case errors.Is(err, &heimdall.ResponseError{}):
+		var responseError *heimdall.ResponseError
+
+		errors.As(err, &responseError)
+
+		for name, value := range responseError.Headers {
+			rw.Header().Set(name, value)
+		}
+
+		rw.WriteHeader(responseError.Code)
+
+		if len(responseError.Body) != 0 {
+			// Cannot do anything else here if writing fails
+			//nolint:errcheck
+			rw.Write(stringx.ToBytes(responseError.Body))
+		}
 	default:
 		logger := zerolog.Ctx(ctx)
 		logger.Error().Err(err).Msg("Internal error occurred")
@@ -31,12 +31,13 @@ func TestHandlerHandle(t *testing.T) {
 	t.Parallel()
 
 	for _, tc := range []struct {
-		uc      string
-		handler ErrorHandler
-		err     error
-		expCode int
-		accept  string
-		expBody string
+		uc            string
+		handler       ErrorHandler
+		err           error
+		expCode       int
+		accept        string
+		expBody       string
+		expRetryAfter string
 	}{
 		{
 			uc:      "authentication error default",
@@ -109,6 +110,12 @@ func TestHandlerHandle(t *testing.T) {
 			err:     errorchain.New(heimdall.ErrCommunication),
 			expCode: http.StatusContinue,
 		},
+		{
+			uc:      "graphql error default",
+			handler: New(),
+			err:     errorchain.New(heimdall.ErrGraphQL),
+			expCode: http.StatusBadGateway,
+		},
 		{
 			uc:      "communication error verbose expecting application/json",
 			handler: New(WithVerboseErrors(true)),
@@ -167,6 +174,90 @@ func TestHandlerHandle(t *testing.T) {
 			err:     &heimdall.RedirectError{RedirectTo: "http://foo.local", Code: http.StatusFound},
 			expCode: http.StatusFound,
 		},
+		{
+			uc:      "authorization error with problem details",
+			handler: New(WithProblemDetails(true)),
+			err:     errorchain.NewWithMessage(heimdall.ErrAuthorization, "denied"),
+			expCode: http.StatusForbidden,
+			expBody: `{"type":"about:blank","title":"Forbidden","status":403,"instance":"/foo"}`,
+		},
+		{
+			uc:      "authentication error verbose with problem details and xml accept type",
+			handler: New(WithProblemDetails(true), WithVerboseErrors(true)),
+			err:     errorchain.NewWithMessage(heimdall.ErrAuthentication, "no token"),
+			accept:  "application/xml",
+			expCode: http.StatusUnauthorized,
+			expBody: `<problem xmlns="urn:ietf:rfc:7807"><type>about:blank</type><title>Unauthorized</title>` +
+				`<status>401</status><detail>authentication error: no token</detail><instance>/foo</instance></problem>`,
+		},
+		{
+			uc:      "rate limit error default",
+			handler: New(),
+			err: errorchain.New(heimdall.ErrRateLimit).
+				CausedBy(&heimdall.RetryAfterError{RetryAfter: "120"}),
+			expCode:       http.StatusTooManyRequests,
+			expRetryAfter: "120",
+		},
+		{
+			uc:      "rate limit error overridden",
+			handler: New(WithRateLimitErrorCode(http.StatusBadGateway)),
+			err: errorchain.New(heimdall.ErrRateLimit).
+				CausedBy(&heimdall.RetryAfterError{RetryAfter: "120"}),
+			expCode:       http.StatusBadGateway,
+			expRetryAfter: "120",
+		},
+		{
+			uc:      "rate limit error with configured communication error code",
+			handler: New(WithCommunicationErrorCode(http.StatusInternalServerError)),
+			err: errorchain.New(heimdall.ErrRateLimit).
+				CausedBy(&heimdall.RetryAfterError{RetryAfter: "120"}),
+			expCode:       http.StatusInternalServerError,
+			expRetryAfter: "120",
+		},
+		{
+			uc: "rate limit error with configured communication and rate limit error codes",
+			handler: New(
+				WithCommunicationErrorCode(http.StatusInternalServerError),
+				WithRateLimitErrorCode(http.StatusServiceUnavailable),
+			),
+			err:     errorchain.New(heimdall.ErrRateLimit),
+			expCode: http.StatusServiceUnavailable,
+		},
+		{
+			uc:      "service unavailable error with configured communication error code",
+			handler: New(WithCommunicationErrorCode(http.StatusInternalServerError)),
+			err:     errorchain.New(heimdall.ErrServiceUnavailable),
+			expCode: http.StatusInternalServerError,
+		},
+		{
+			uc:      "service unavailable error default",
+			handler: New(),
+			err:     errorchain.New(heimdall.ErrServiceUnavailable),
+			expCode: http.StatusServiceUnavailable,
+		},
+		{
+			uc:      "service unavailable error overridden",
+			handler: New(WithServiceUnavailableErrorCode(http.StatusBadGateway)),
+			err:     errorchain.New(heimdall.ErrServiceUnavailable),
+			expCode: http.StatusBadGateway,
+		},
+		{
+			uc:      "response error",
+			handler: New(),
+			err: &heimdall.ResponseError{
+				Code:    http.StatusForbidden,
+				Headers: map[string]string{"Content-Type": "application/json"},
+				Body:    `{"error": "forbidden"}`,
+			},
+			expCode: http.StatusForbidden,
+			expBody: `{"error": "forbidden"}`,
+		},
+		{
+			uc:      "response error verbose without body",
+			handler: New(WithVerboseErrors(true)),
+			err:     &heimdall.ResponseError{Code: http.StatusTeapot},
+			expCode: http.StatusTeapot,
+		},
 		{
 			uc:      "internal error default",
 			handler: New(),
@@ -200,6 +291,7 @@ func TestHandlerHandle(t *testing.T) {
 
 			assert.Equal(t, tc.expCode, recorder.Code)
 			assert.Equal(t, tc.expBody, recorder.Body.String())
+			assert.Equal(t, tc.expRetryAfter, recorder.Header().Get("Retry-After"))
 		})
 	}
 }
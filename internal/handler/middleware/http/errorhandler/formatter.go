@@ -18,12 +18,17 @@ package errorhandler
 
 import (
 	"encoding/xml"
+	"errors"
 	"fmt"
 	"net/http"
 
 	"github.com/elnormous/contenttype"
 	"github.com/goccy/go-json"
 	"github.com/rs/zerolog"
+
+	"github.com/dadrus/heimdall/internal/handler/problemdetails"
+	"github.com/dadrus/heimdall/internal/handler/requestcontext"
+	"github.com/dadrus/heimdall/internal/heimdall"
 )
 
 var supportedMediaTypes = []contenttype.MediaType{ //nolint:gochecknoglobals
@@ -33,47 +38,71 @@ var supportedMediaTypes = []contenttype.MediaType{ //nolint:gochecknoglobals
 	contenttype.NewMediaType("application/xml"),
 }
 
-func format(req *http.Request, body error) (contenttype.MediaType, []byte, error) {
+func format(req *http.Request, body error) (string, []byte, error) {
 	mediaType, _, err := contenttype.GetAcceptableMediaType(req, supportedMediaTypes)
 	if err != nil {
-		return contenttype.MediaType{}, nil, err
+		return "", nil, err
 	}
 
 	// Format based on the accept content type
 	switch mediaType.Subtype {
 	case "html":
-		return mediaType, []byte(fmt.Sprintf("<p>%s</p>", body)), nil
+		return mediaType.String(), []byte(fmt.Sprintf("<p>%s</p>", body)), nil
 	case "json":
 		res, err := json.Marshal(body)
 
-		return mediaType, res, err
+		return mediaType.String(), res, err
 	case "xml":
 		res, err := xml.Marshal(body)
 
-		return mediaType, res, err
+		return mediaType.String(), res, err
 	case "plain":
 		fallthrough
 	default:
-		return supportedMediaTypes[2], []byte(body.Error()), nil
+		return supportedMediaTypes[2].String(), []byte(body.Error()), nil
 	}
 }
 
+func formatProblem(req *http.Request, code int, cause error, verbose bool) (string, []byte, error) {
+	mediaType := problemdetails.MediaType(req.Header.Get("Accept"))
+
+	// in decision mode, the instance is the path of the request forwarded by the proxy
+	// and not the path of heimdall's endpoint
+	instance := requestcontext.ExtractURL(req).Path
+
+	res, err := problemdetails.New(req.Context(), code, instance, cause, verbose).Marshal(mediaType)
+
+	return mediaType, res, err
+}
+
 func errorWriter(options *opts, code int) func(rw http.ResponseWriter, req *http.Request, err error) {
-	return func(rw http.ResponseWriter, req *http.Request, err error) {
+	return func(rw http.ResponseWriter, req *http.Request, cause error) {
 		var (
-			mt   contenttype.MediaType
+			mt   string
 			body []byte
+			err  error
 		)
 
-		if options.verboseErrors {
-			mt, body, err = format(req, err)
+		switch {
+		case options.problemDetails:
+			mt, body, err = formatProblem(req, code, cause, options.verboseErrors)
+			if err != nil {
+				zerolog.Ctx(req.Context()).Warn().Err(err).Msg("Rendering problem details failed. No body is sent")
+			}
+		case options.verboseErrors:
+			mt, body, err = format(req, cause)
 			if err != nil {
 				zerolog.Ctx(req.Context()).Warn().Err(err).Msg("Response format negotiation failed. No body is sent")
 			}
 		}
 
+		var retryAfterErr *heimdall.RetryAfterError
+		if errors.As(cause, &retryAfterErr) {
+			rw.Header().Set("Retry-After", retryAfterErr.RetryAfter)
+		}
+
 		if len(body) != 0 {
-			rw.Header().Set("Content-Type", mt.String())
+			rw.Header().Set("Content-Type", mt)
 			rw.Header().Set("X-Content-Type-Options", "nosniff")
 		}
 
@@ -22,12 +22,21 @@ import (
 
 type opts struct {
 	verboseErrors         bool
+	problemDetails        bool
 	onAuthenticationError func(rw http.ResponseWriter, req *http.Request, err error)
 	onAuthorizationError  func(rw http.ResponseWriter, req *http.Request, err error)
 	onCommunicationError  func(rw http.ResponseWriter, req *http.Request, err error)
 	onPreconditionError   func(rw http.ResponseWriter, req *http.Request, err error)
 	onNoRuleError         func(rw http.ResponseWriter, req *http.Request, err error)
+	onRateLimitError      func(rw http.ResponseWriter, req *http.Request, err error)
+	onUnavailableError    func(rw http.ResponseWriter, req *http.Request, err error)
 	onInternalError       func(rw http.ResponseWriter, req *http.Request, err error)
+
+	// the explicitly configured response codes, required to fall back to the code configured
+	// for communication errors, if there is none configured for rate limit and unavailability errors
+	communicationErrorCode int
+	rateLimitErrorCode     int
+	unavailableErrorCode   int
 }
 
 type Option func(*opts)
@@ -60,6 +69,7 @@ func WithCommunicationErrorCode(code int) Option {
 	return func(o *opts) {
 		if code != 0 {
 			o.onCommunicationError = errorWriter(o, code)
+			o.communicationErrorCode = code
 		}
 	}
 }
@@ -80,8 +90,32 @@ func WithNoRuleErrorCode(code int) Option {
 	}
 }
 
+func WithRateLimitErrorCode(code int) Option {
+	return func(o *opts) {
+		if code != 0 {
+			o.onRateLimitError = errorWriter(o, code)
+			o.rateLimitErrorCode = code
+		}
+	}
+}
+
+func WithServiceUnavailableErrorCode(code int) Option {
+	return func(o *opts) {
+		if code != 0 {
+			o.onUnavailableError = errorWriter(o, code)
+			o.unavailableErrorCode = code
+		}
+	}
+}
+
 func WithVerboseErrors(flag bool) Option {
 	return func(o *opts) {
 		o.verboseErrors = flag
 	}
 }
+
+func WithProblemDetails(flag bool) Option {
+	return func(o *opts) {
+		o.problemDetails = flag
+	}
+}
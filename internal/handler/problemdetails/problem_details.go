@@ -0,0 +1,102 @@
+// Copyright 2025 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package problemdetails
+
+import (
+	"context"
+	"encoding/xml"
+	"net/http"
+
+	"github.com/elnormous/contenttype"
+	"github.com/goccy/go-json"
+
+	"github.com/dadrus/heimdall/internal/accesscontext"
+	"github.com/dadrus/heimdall/internal/x/opentelemetry/tracecontext"
+)
+
+const (
+	MediaTypeJSON = "application/problem+json"
+	MediaTypeXML  = "application/problem+xml"
+)
+
+var supportedMediaTypes = []contenttype.MediaType{ //nolint:gochecknoglobals
+	contenttype.NewMediaType(MediaTypeJSON),
+	contenttype.NewMediaType(MediaTypeXML),
+	contenttype.NewMediaType("application/json"),
+	contenttype.NewMediaType("application/xml"),
+}
+
+// Details implements the problem details object defined in RFC 9457. Next to the standard
+// members, it carries the id of the matched rule and the id of the trace as extension members.
+type Details struct {
+	XMLName  xml.Name `json:"-"                  xml:"urn:ietf:rfc:7807 problem"`
+	Type     string   `json:"type"               xml:"type"`
+	Title    string   `json:"title"              xml:"title"`
+	Status   int      `json:"status"             xml:"status"`
+	Detail   string   `json:"detail,omitempty"   xml:"detail,omitempty"`
+	Instance string   `json:"instance,omitempty" xml:"instance,omitempty"`
+	RuleID   string   `json:"rule_id,omitempty"  xml:"rule_id,omitempty"`
+	TraceID  string   `json:"trace_id,omitempty" xml:"trace_id,omitempty"`
+}
+
+// New creates problem details for the given status code. The error message is used as detail
+// only if verbose is set, as it might contain information, which should not be exposed otherwise.
+func New(ctx context.Context, status int, instance string, err error, verbose bool) *Details {
+	details := &Details{
+		Type:     "about:blank",
+		Title:    http.StatusText(status),
+		Status:   status,
+		Instance: instance,
+		RuleID:   accesscontext.RuleID(ctx),
+	}
+
+	if verbose && err != nil {
+		details.Detail = err.Error()
+	}
+
+	if tc := tracecontext.Extract(ctx); tc != nil {
+		details.TraceID = tc.TraceID
+	}
+
+	return details
+}
+
+// MediaType selects the problem details media type based on the given value of the
+// Accept header. Defaults to application/problem+json.
+func MediaType(accept string) string {
+	mt, _, err := contenttype.GetAcceptableMediaTypeFromHeader(accept, supportedMediaTypes)
+	if err != nil {
+		return MediaTypeJSON
+	}
+
+	switch mt.Subtype {
+	case "xml", "problem+xml":
+		return MediaTypeXML
+	default:
+		return MediaTypeJSON
+	}
+}
+
+// Marshal renders the details according to the given media type, which is expected to be
+// one of the values returned by MediaType.
+func (d *Details) Marshal(mediaType string) ([]byte, error) {
+	if mediaType == MediaTypeXML {
+		return xml.Marshal(d)
+	}
+
+	return json.Marshal(d)
+}
@@ -0,0 +1,138 @@
+// Copyright 2025 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package problemdetails
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/dadrus/heimdall/internal/accesscontext"
+	"github.com/dadrus/heimdall/internal/heimdall"
+	"github.com/dadrus/heimdall/internal/x/errorchain"
+)
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	traceID, err := trace.TraceIDFromHex("0af7651916cd43dd8448eb211c80319c")
+	require.NoError(t, err)
+
+	spanID, err := trace.SpanIDFromHex("b7ad6b7169203331")
+	require.NoError(t, err)
+
+	for uc, tc := range map[string]struct {
+		verbose  bool
+		withData bool
+		assert   func(t *testing.T, details *Details)
+	}{
+		"not verbose without access and trace context": {
+			assert: func(t *testing.T, details *Details) {
+				t.Helper()
+
+				assert.Equal(t, "about:blank", details.Type)
+				assert.Equal(t, "Forbidden", details.Title)
+				assert.Equal(t, http.StatusForbidden, details.Status)
+				assert.Equal(t, "/foo", details.Instance)
+				assert.Empty(t, details.Detail)
+				assert.Empty(t, details.RuleID)
+				assert.Empty(t, details.TraceID)
+			},
+		},
+		"verbose with access and trace context": {
+			verbose:  true,
+			withData: true,
+			assert: func(t *testing.T, details *Details) {
+				t.Helper()
+
+				assert.Equal(t, "about:blank", details.Type)
+				assert.Equal(t, "Forbidden", details.Title)
+				assert.Equal(t, http.StatusForbidden, details.Status)
+				assert.Equal(t, "/foo", details.Instance)
+				assert.Equal(t, "authorization error: denied", details.Detail)
+				assert.Equal(t, "rule-1", details.RuleID)
+				assert.Equal(t, traceID.String(), details.TraceID)
+			},
+		},
+	} {
+		t.Run(uc, func(t *testing.T) {
+			ctx := t.Context()
+
+			if tc.withData {
+				ctx = accesscontext.New(ctx)
+				accesscontext.SetRuleID(ctx, "rule-1")
+
+				ctx = trace.ContextWithSpanContext(ctx, trace.NewSpanContext(trace.SpanContextConfig{
+					TraceID: traceID,
+					SpanID:  spanID,
+				}))
+			}
+
+			details := New(ctx, http.StatusForbidden, "/foo",
+				errorchain.NewWithMessage(heimdall.ErrAuthorization, "denied"), tc.verbose)
+
+			tc.assert(t, details)
+		})
+	}
+}
+
+func TestMediaType(t *testing.T) {
+	t.Parallel()
+
+	for accept, expected := range map[string]string{
+		"":                         MediaTypeJSON,
+		"*/*":                      MediaTypeJSON,
+		"text/html":                MediaTypeJSON,
+		"application/json":         MediaTypeJSON,
+		"application/problem+json": MediaTypeJSON,
+		"application/xml":          MediaTypeXML,
+		"application/problem+xml":  MediaTypeXML,
+		"application/json;q=0.5, application/xml": MediaTypeXML,
+	} {
+		t.Run(accept, func(t *testing.T) {
+			assert.Equal(t, expected, MediaType(accept))
+		})
+	}
+}
+
+func TestDetailsMarshal(t *testing.T) {
+	t.Parallel()
+
+	details := &Details{
+		Type:     "about:blank",
+		Title:    "Unauthorized",
+		Status:   http.StatusUnauthorized,
+		Instance: "/foo",
+		RuleID:   "rule-1",
+	}
+
+	res, err := details.Marshal(MediaTypeJSON)
+	require.NoError(t, err)
+	assert.JSONEq(t,
+		`{"type":"about:blank","title":"Unauthorized","status":401,"instance":"/foo","rule_id":"rule-1"}`,
+		string(res))
+
+	res, err = details.Marshal(MediaTypeXML)
+	require.NoError(t, err)
+	assert.Equal(t,
+		`<problem xmlns="urn:ietf:rfc:7807"><type>about:blank</type><title>Unauthorized</title>`+
+			`<status>401</status><instance>/foo</instance><rule_id>rule-1</rule_id></problem>`,
+		string(res))
+}
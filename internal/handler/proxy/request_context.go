@@ -18,13 +18,13 @@ package proxy
 
 import (
 	"crypto/tls"
+	"errors"
 	"fmt"
-	"net"
 	"net/http"
 	"net/http/httputil"
 	"net/url"
+	"slices"
 	"strings"
-	"time"
 
 	"github.com/rs/zerolog"
 	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
@@ -41,39 +41,21 @@ import (
 type requestContext struct {
 	*requestcontext.RequestContext
 
-	rw        http.ResponseWriter
-	req       *http.Request
-	transport *http.Transport
+	rw         http.ResponseWriter
+	req        *http.Request
+	transports *transportPool
 }
 
 func newContextFactory(
 	cfg config.ServeConfig,
 	tlsCfg *tls.Config,
 ) requestcontext.ContextFactory {
-	transport := &http.Transport{
-		// tlsClientConfig used for test purposes only
-		// must be removed as soon as tls configuration
-		// is possible per upstream
-		Proxy: http.ProxyFromEnvironment,
-		DialContext: (&net.Dialer{
-			Timeout:   30 * time.Second, //nolint:mnd
-			KeepAlive: 30 * time.Second, //nolint:mnd
-		}).DialContext,
-		ResponseHeaderTimeout: cfg.Timeout.Read,
-		MaxIdleConns:          cfg.ConnectionsLimit.MaxIdle,
-		MaxIdleConnsPerHost:   cfg.ConnectionsLimit.MaxIdlePerHost,
-		MaxConnsPerHost:       cfg.ConnectionsLimit.MaxPerHost,
-		IdleConnTimeout:       cfg.Timeout.Idle,
-		TLSHandshakeTimeout:   10 * time.Second, //nolint:mnd
-		ExpectContinueTimeout: 1 * time.Second,
-		ForceAttemptHTTP2:     true,
-		TLSClientConfig:       tlsCfg,
-	}
+	transports := newTransportPool(cfg, tlsCfg)
 
 	return requestcontext.FactoryFunc(func(rw http.ResponseWriter, req *http.Request) requestcontext.Context {
 		return &requestContext{
 			RequestContext: requestcontext.New(req),
-			transport:      transport,
+			transports:     transports,
 			rw:             rw,
 			req:            req,
 		}
@@ -84,6 +66,11 @@ func (r *requestContext) Finalize(upstream rule.Backend) error {
 	logger := zerolog.Ctx(r.Context())
 
 	if err := r.PipelineError(); err != nil {
+		if upstream != nil {
+			// request is not forwarded
+			upstream.Done(r.Context(), 0, nil)
+		}
+
 		return err
 	}
 
@@ -96,18 +83,36 @@ func (r *requestContext) Finalize(upstream rule.Backend) error {
 		Str("_upstream", upstream.URL().String()).
 		Msg("Forwarding request")
 
-	errHolder := struct{ err error }{}
+	result := struct {
+		err        error
+		statusCode int
+	}{}
 
 	proxy := &httputil.ReverseProxy{
 		ErrorHandler: func(_ http.ResponseWriter, _ *http.Request, err error) {
 			logger.Error().Err(err).Msg("Proxying error")
 
-			errHolder.err = errorchain.NewWithMessage(heimdall.ErrCommunication, "Failed to proxy request").
+			if errors.Is(err, heimdall.ErrInternal) || errors.Is(err, heimdall.ErrCommunication) {
+				result.err = err
+
+				return
+			}
+
+			result.err = errorchain.NewWithMessage(heimdall.ErrCommunication, "Failed to proxy request").
 				CausedBy(err)
 		},
+		ModifyResponse: func(resp *http.Response) error {
+			result.statusCode = resp.StatusCode
+
+			return nil
+		},
 		Rewrite: r.rewriteRequest(upstream.URL(), upstream.ForwardHostHeader()),
 		Transport: otelhttp.NewTransport(
-			httpx.NewTraceRoundTripper(r.transport),
+			// retries are applied before signing to have each attempt signed separately
+			upstream.RoundTripper(newSigningRoundTripper(
+				httpx.NewTraceRoundTripper(r.transports.transportFor(upstream.TransportSettings())),
+				r.UpstreamSigners(),
+			)),
 			otelhttp.WithSpanNameFormatter(func(_ string, r *http.Request) string {
 				return fmt.Sprintf("%s %s %s @%s", r.Proto, r.Method, r.URL.Path, r.URL.Host)
 			})),
@@ -115,21 +120,28 @@ func (r *requestContext) Finalize(upstream rule.Backend) error {
 
 	proxy.ServeHTTP(r.rw, r.req)
 
+	upstream.Done(r.Context(), result.statusCode, result.err)
+
 	// set in the proxy error handler above
-	return errHolder.err
+	return result.err
 }
 
 func (r *requestContext) rewriteRequest(targetURL *url.URL, passHostHeader bool) func(req *httputil.ProxyRequest) {
 	return func(proxyReq *httputil.ProxyRequest) {
+		upstreamURL := *targetURL
+		r.RewriteUpstreamURL(&upstreamURL)
+
 		proxyReq.Out.Method = r.Request().Method
-		proxyReq.Out.URL = targetURL
-		proxyReq.Out.Host = targetURL.Host
+		proxyReq.Out.URL = &upstreamURL
+		proxyReq.Out.Host = upstreamURL.Host
 
 		// delete headers, which are useless for the upstream service, before forwarding the request
 		proxyReq.Out.Header.Del("X-Forwarded-Method")
 		proxyReq.Out.Header.Del("X-Forwarded-Uri")
 		proxyReq.Out.Header.Del("X-Forwarded-Path")
 
+		r.removeUpstreamHeaders(proxyReq.Out)
+		r.removeUpstreamCookies(proxyReq.Out)
 		r.addUpstreamHeader(proxyReq.Out)
 		r.addUpstreamCookies(proxyReq.Out)
 		r.rewriteForwardedHeader(proxyReq.In, proxyReq.Out)
@@ -184,6 +196,28 @@ func (r *requestContext) rewriteForwardedHeader(in, out *http.Request) {
 		}))
 }
 
+func (r *requestContext) removeUpstreamHeaders(req *http.Request) {
+	for _, name := range r.RemovedUpstreamHeaders() {
+		req.Header.Del(name)
+	}
+}
+
+func (r *requestContext) removeUpstreamCookies(req *http.Request) {
+	removed := r.RemovedUpstreamCookies()
+	if len(removed) == 0 {
+		return
+	}
+
+	cookies := req.Cookies()
+	req.Header.Del("Cookie")
+
+	for _, cookie := range cookies {
+		if !slices.Contains(removed, cookie.Name) {
+			req.AddCookie(cookie)
+		}
+	}
+}
+
 func (r *requestContext) addUpstreamCookies(req *http.Request) {
 	for k, v := range r.UpstreamCookies() {
 		req.AddCookie(&http.Cookie{Name: k, Value: v})
@@ -22,16 +22,21 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
 	"github.com/dadrus/heimdall/internal/config"
 	"github.com/dadrus/heimdall/internal/handler/requestcontext"
+	"github.com/dadrus/heimdall/internal/heimdall"
+	heimdallmocks "github.com/dadrus/heimdall/internal/heimdall/mocks"
 	"github.com/dadrus/heimdall/internal/rules/rule"
 	mocks2 "github.com/dadrus/heimdall/internal/rules/rule/mocks"
+	"github.com/dadrus/heimdall/internal/x/errorchain"
 )
 
 func TestRequestContextFinalize(t *testing.T) {
@@ -54,6 +59,18 @@ func TestRequestContextFinalize(t *testing.T) {
 				return nil
 			},
 		},
+		"error was present, forwarding aborted, backend is informed": {
+			setup: func(t *testing.T, ctx requestcontext.Context, _ *url.URL) rule.Backend {
+				t.Helper()
+
+				ctx.SetPipelineError(errors.New("test error"))
+
+				backend := mocks2.NewBackendMock(t)
+				backend.EXPECT().Done(mock.Anything, 0, nil)
+
+				return backend
+			},
+		},
 		"no headers set, ipv6 is used": {
 			upstreamCalled: true,
 			useIPv6:        true,
@@ -63,6 +80,10 @@ func TestRequestContextFinalize(t *testing.T) {
 				backend := mocks2.NewBackendMock(t)
 				backend.EXPECT().URL().Return(upstreamURL)
 				backend.EXPECT().ForwardHostHeader().Return(false)
+				backend.EXPECT().TransportSettings().Return(nil)
+				backend.EXPECT().RoundTripper(mock.Anything).RunAndReturn(
+					func(next http.RoundTripper) http.RoundTripper { return next })
+				backend.EXPECT().Done(mock.Anything, http.StatusOK, nil)
 
 				return backend
 			},
@@ -99,6 +120,10 @@ func TestRequestContextFinalize(t *testing.T) {
 				backend := mocks2.NewBackendMock(t)
 				backend.EXPECT().URL().Return(upstreamURL)
 				backend.EXPECT().ForwardHostHeader().Return(false)
+				backend.EXPECT().TransportSettings().Return(nil)
+				backend.EXPECT().RoundTripper(mock.Anything).RunAndReturn(
+					func(next http.RoundTripper) http.RoundTripper { return next })
+				backend.EXPECT().Done(mock.Anything, mock.Anything, mock.Anything)
 
 				return backend
 			},
@@ -129,6 +154,10 @@ func TestRequestContextFinalize(t *testing.T) {
 				backend := mocks2.NewBackendMock(t)
 				backend.EXPECT().URL().Return(upstreamURL)
 				backend.EXPECT().ForwardHostHeader().Return(false)
+				backend.EXPECT().TransportSettings().Return(nil)
+				backend.EXPECT().RoundTripper(mock.Anything).RunAndReturn(
+					func(next http.RoundTripper) http.RoundTripper { return next })
+				backend.EXPECT().Done(mock.Anything, mock.Anything, mock.Anything)
 
 				return backend
 			},
@@ -165,6 +194,10 @@ func TestRequestContextFinalize(t *testing.T) {
 				backend := mocks2.NewBackendMock(t)
 				backend.EXPECT().URL().Return(upstreamURL)
 				backend.EXPECT().ForwardHostHeader().Return(false)
+				backend.EXPECT().TransportSettings().Return(nil)
+				backend.EXPECT().RoundTripper(mock.Anything).RunAndReturn(
+					func(next http.RoundTripper) http.RoundTripper { return next })
+				backend.EXPECT().Done(mock.Anything, mock.Anything, mock.Anything)
 
 				return backend
 			},
@@ -210,6 +243,10 @@ func TestRequestContextFinalize(t *testing.T) {
 				backend := mocks2.NewBackendMock(t)
 				backend.EXPECT().URL().Return(upstreamURL)
 				backend.EXPECT().ForwardHostHeader().Return(false)
+				backend.EXPECT().TransportSettings().Return(nil)
+				backend.EXPECT().RoundTripper(mock.Anything).RunAndReturn(
+					func(next http.RoundTripper) http.RoundTripper { return next })
+				backend.EXPECT().Done(mock.Anything, mock.Anything, mock.Anything)
 
 				return backend
 			},
@@ -245,6 +282,10 @@ func TestRequestContextFinalize(t *testing.T) {
 				backend := mocks2.NewBackendMock(t)
 				backend.EXPECT().URL().Return(upstreamURL)
 				backend.EXPECT().ForwardHostHeader().Return(false)
+				backend.EXPECT().TransportSettings().Return(nil)
+				backend.EXPECT().RoundTripper(mock.Anything).RunAndReturn(
+					func(next http.RoundTripper) http.RoundTripper { return next })
+				backend.EXPECT().Done(mock.Anything, mock.Anything, mock.Anything)
 
 				return backend
 			},
@@ -274,6 +315,10 @@ func TestRequestContextFinalize(t *testing.T) {
 				backend := mocks2.NewBackendMock(t)
 				backend.EXPECT().URL().Return(upstreamURL)
 				backend.EXPECT().ForwardHostHeader().Return(false)
+				backend.EXPECT().TransportSettings().Return(nil)
+				backend.EXPECT().RoundTripper(mock.Anything).RunAndReturn(
+					func(next http.RoundTripper) http.RoundTripper { return next })
+				backend.EXPECT().Done(mock.Anything, mock.Anything, mock.Anything)
 
 				return backend
 			},
@@ -303,6 +348,10 @@ func TestRequestContextFinalize(t *testing.T) {
 				backend := mocks2.NewBackendMock(t)
 				backend.EXPECT().URL().Return(upstreamURL)
 				backend.EXPECT().ForwardHostHeader().Return(true)
+				backend.EXPECT().TransportSettings().Return(nil)
+				backend.EXPECT().RoundTripper(mock.Anything).RunAndReturn(
+					func(next http.RoundTripper) http.RoundTripper { return next })
+				backend.EXPECT().Done(mock.Anything, mock.Anything, mock.Anything)
 
 				return backend
 			},
@@ -332,6 +381,10 @@ func TestRequestContextFinalize(t *testing.T) {
 				backend := mocks2.NewBackendMock(t)
 				backend.EXPECT().URL().Return(upstreamURL)
 				backend.EXPECT().ForwardHostHeader().Return(false)
+				backend.EXPECT().TransportSettings().Return(nil)
+				backend.EXPECT().RoundTripper(mock.Anything).RunAndReturn(
+					func(next http.RoundTripper) http.RoundTripper { return next })
+				backend.EXPECT().Done(mock.Anything, mock.Anything, mock.Anything)
 
 				return backend
 			},
@@ -350,6 +403,142 @@ func TestRequestContextFinalize(t *testing.T) {
 				assert.Equal(t, "172.2.34.1, 192.0.2.1", req.Header.Get("X-Forwarded-For"))
 			},
 		},
+		"request is signed by registered upstream signers": {
+			upstreamCalled: true,
+			setup: func(t *testing.T, ctx requestcontext.Context, upstreamURL *url.URL) rule.Backend {
+				t.Helper()
+
+				signer := heimdallmocks.NewUpstreamRequestSignerMock(t)
+				signer.EXPECT().Sign(mock.Anything).Run(func(req *http.Request) {
+					assert.Equal(t, upstreamURL.Host, req.URL.Host)
+					assert.Equal(t, "bar", req.Header.Get("X-Foo"))
+
+					req.Header.Set("Signature", "sig=:Zm9v:")
+				}).Return(nil)
+
+				ctx.AddHeaderForUpstream("X-Foo", "bar")
+				ctx.AddSignerForUpstream(signer)
+
+				backend := mocks2.NewBackendMock(t)
+				backend.EXPECT().URL().Return(upstreamURL)
+				backend.EXPECT().ForwardHostHeader().Return(false)
+				backend.EXPECT().TransportSettings().Return(nil)
+				backend.EXPECT().RoundTripper(mock.Anything).RunAndReturn(
+					func(next http.RoundTripper) http.RoundTripper { return next })
+				backend.EXPECT().Done(mock.Anything, mock.Anything, mock.Anything)
+
+				return backend
+			},
+			assertRequest: func(t *testing.T, req *http.Request) {
+				t.Helper()
+
+				assert.Equal(t, "bar", req.Header.Get("X-Foo"))
+				assert.Equal(t, "sig=:Zm9v:", req.Header.Get("Signature"))
+			},
+		},
+		"headers and cookies removed for upstream": {
+			upstreamCalled: true,
+			headers: http.Header{
+				"Authorization": []string{"Bearer foo"},
+				"X-User-Id":     []string{"bar"},
+				"Cookie":        []string{"session=foo; theme=dark; csrf=baz"},
+			},
+			setup: func(t *testing.T, ctx requestcontext.Context, upstreamURL *url.URL) rule.Backend {
+				t.Helper()
+
+				ctx.RemoveHeaderForUpstream("authorization")
+				ctx.RemoveHeaderForUpstream("X-User-Id")
+				ctx.RemoveCookieForUpstream("session")
+				ctx.RemoveCookieForUpstream("csrf")
+				ctx.AddHeaderForUpstream("Authorization", "Bearer bar")
+
+				backend := mocks2.NewBackendMock(t)
+				backend.EXPECT().URL().Return(upstreamURL)
+				backend.EXPECT().ForwardHostHeader().Return(false)
+				backend.EXPECT().TransportSettings().Return(nil)
+				backend.EXPECT().RoundTripper(mock.Anything).RunAndReturn(
+					func(next http.RoundTripper) http.RoundTripper { return next })
+				backend.EXPECT().Done(mock.Anything, mock.Anything, mock.Anything)
+
+				return backend
+			},
+			assertRequest: func(t *testing.T, req *http.Request) {
+				t.Helper()
+
+				assert.Equal(t, "Bearer bar", req.Header.Get("Authorization"))
+				assert.Empty(t, req.Header.Get("X-User-Id"))
+				assert.Equal(t, "theme=dark", req.Header.Get("Cookie"))
+			},
+		},
+		"upstream url modified": {
+			upstreamCalled: true,
+			setup: func(t *testing.T, ctx requestcontext.Context, upstreamURL *url.URL) rule.Backend {
+				t.Helper()
+
+				ctx.AddPathPrefixForUpstream("/tenants/acme")
+				ctx.SetQueryParameterForUpstream("user", "alice")
+
+				backend := mocks2.NewBackendMock(t)
+				backend.EXPECT().URL().Return(&url.URL{
+					Scheme:   upstreamURL.Scheme,
+					Host:     upstreamURL.Host,
+					Path:     "/test",
+					RawQuery: "foo=bar",
+				})
+				backend.EXPECT().ForwardHostHeader().Return(false)
+				backend.EXPECT().TransportSettings().Return(nil)
+				backend.EXPECT().RoundTripper(mock.Anything).RunAndReturn(
+					func(next http.RoundTripper) http.RoundTripper { return next })
+				backend.EXPECT().Done(mock.Anything, mock.Anything, mock.Anything)
+
+				return backend
+			},
+			assertRequest: func(t *testing.T, req *http.Request) {
+				t.Helper()
+
+				assert.Equal(t, "/tenants/acme/test", req.URL.Path)
+				assert.Equal(t, "foo=bar&user=alice", req.URL.RawQuery)
+			},
+		},
+		"signing of the request fails": {
+			setup: func(t *testing.T, ctx requestcontext.Context, upstreamURL *url.URL) rule.Backend {
+				t.Helper()
+
+				signer := heimdallmocks.NewUpstreamRequestSignerMock(t)
+				signer.EXPECT().Sign(mock.Anything).Return(heimdall.ErrInternal)
+
+				ctx.AddSignerForUpstream(signer)
+
+				backend := mocks2.NewBackendMock(t)
+				backend.EXPECT().URL().Return(upstreamURL)
+				backend.EXPECT().ForwardHostHeader().Return(false)
+				backend.EXPECT().TransportSettings().Return(nil)
+				backend.EXPECT().RoundTripper(mock.Anything).RunAndReturn(
+					func(next http.RoundTripper) http.RoundTripper { return next })
+				backend.EXPECT().Done(mock.Anything, mock.Anything, mock.Anything)
+
+				return backend
+			},
+		},
+		"circuit breaker of the backend is open": {
+			setup: func(t *testing.T, _ requestcontext.Context, upstreamURL *url.URL) rule.Backend {
+				t.Helper()
+
+				backend := mocks2.NewBackendMock(t)
+				backend.EXPECT().URL().Return(upstreamURL)
+				backend.EXPECT().ForwardHostHeader().Return(false)
+				backend.EXPECT().TransportSettings().Return(nil)
+				backend.EXPECT().RoundTripper(mock.Anything).Return(
+					roundTripperFunc(func(_ *http.Request) (*http.Response, error) {
+						return nil, errorchain.NewWithMessage(heimdall.ErrCommunication, "circuit breaker is open")
+					}))
+				backend.EXPECT().Done(mock.Anything, 0, mock.MatchedBy(func(err error) bool {
+					return errors.Is(err, heimdall.ErrCommunication) && strings.Contains(err.Error(), "circuit breaker")
+				}))
+
+				return backend
+			},
+		},
 	} {
 		t.Run(uc, func(t *testing.T) {
 			// GIVEN
@@ -394,3 +583,7 @@ func TestRequestContextFinalize(t *testing.T) {
 		})
 	}
 }
+
+type roundTripperFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
@@ -296,6 +296,10 @@ func TestProxyService(t *testing.T) {
 					Path:   "/foobar",
 				})
 				backend.EXPECT().ForwardHostHeader().Return(true)
+				backend.EXPECT().TransportSettings().Return(nil)
+				backend.EXPECT().RoundTripper(mock.Anything).RunAndReturn(
+					func(next http.RoundTripper) http.RoundTripper { return next })
+				backend.EXPECT().Done(mock.Anything, mock.Anything, mock.Anything)
 
 				exec.EXPECT().Execute(
 					mock.MatchedBy(func(ctx heimdall.RequestContext) bool {
@@ -378,6 +382,10 @@ func TestProxyService(t *testing.T) {
 					Path:   "/[id]/foobar",
 				})
 				backend.EXPECT().ForwardHostHeader().Return(true)
+				backend.EXPECT().TransportSettings().Return(nil)
+				backend.EXPECT().RoundTripper(mock.Anything).RunAndReturn(
+					func(next http.RoundTripper) http.RoundTripper { return next })
+				backend.EXPECT().Done(mock.Anything, mock.Anything, mock.Anything)
 
 				exec.EXPECT().Execute(
 					mock.MatchedBy(func(ctx heimdall.RequestContext) bool {
@@ -459,6 +467,10 @@ func TestProxyService(t *testing.T) {
 					Path:   "/[barfoo]",
 				})
 				backend.EXPECT().ForwardHostHeader().Return(true)
+				backend.EXPECT().TransportSettings().Return(nil)
+				backend.EXPECT().RoundTripper(mock.Anything).RunAndReturn(
+					func(next http.RoundTripper) http.RoundTripper { return next })
+				backend.EXPECT().Done(mock.Anything, mock.Anything, mock.Anything)
 
 				exec.EXPECT().Execute(
 					mock.MatchedBy(func(ctx heimdall.RequestContext) bool {
@@ -547,6 +559,10 @@ func TestProxyService(t *testing.T) {
 					Path:   "/bar",
 				})
 				backend.EXPECT().ForwardHostHeader().Return(true)
+				backend.EXPECT().TransportSettings().Return(nil)
+				backend.EXPECT().RoundTripper(mock.Anything).RunAndReturn(
+					func(next http.RoundTripper) http.RoundTripper { return next })
+				backend.EXPECT().Done(mock.Anything, mock.Anything, mock.Anything)
 
 				exec.EXPECT().Execute(
 					mock.MatchedBy(func(ctx heimdall.RequestContext) bool {
@@ -721,6 +737,10 @@ func TestProxyService(t *testing.T) {
 					Path:   "/bar",
 				})
 				backend.EXPECT().ForwardHostHeader().Return(false)
+				backend.EXPECT().TransportSettings().Return(nil)
+				backend.EXPECT().RoundTripper(mock.Anything).RunAndReturn(
+					func(next http.RoundTripper) http.RoundTripper { return next })
+				backend.EXPECT().Done(mock.Anything, mock.Anything, mock.Anything)
 
 				exec.EXPECT().Execute(
 					mock.MatchedBy(func(ctx heimdall.RequestContext) bool {
@@ -816,6 +836,10 @@ func TestProxyService(t *testing.T) {
 					Path:   "/bar",
 				})
 				backend.EXPECT().ForwardHostHeader().Return(true)
+				backend.EXPECT().TransportSettings().Return(nil)
+				backend.EXPECT().RoundTripper(mock.Anything).RunAndReturn(
+					func(next http.RoundTripper) http.RoundTripper { return next })
+				backend.EXPECT().Done(mock.Anything, mock.Anything, mock.Anything)
 
 				exec.EXPECT().Execute(
 					mock.MatchedBy(func(ctx heimdall.RequestContext) bool {
@@ -1011,6 +1035,11 @@ func TestWebSocketSupport(t *testing.T) {
 		Path:   "/bar",
 	})
 	backend.EXPECT().ForwardHostHeader().Return(true)
+	backend.EXPECT().TransportSettings().Return(nil)
+	backend.EXPECT().RoundTripper(mock.Anything).RunAndReturn(
+		func(next http.RoundTripper) http.RoundTripper { return next })
+	// the websocket connection might still be open while the test finishes
+	backend.EXPECT().Done(mock.Anything, mock.Anything, mock.Anything).Maybe()
 
 	exec.EXPECT().Execute(
 		mock.MatchedBy(func(ctx heimdall.RequestContext) bool {
@@ -1110,6 +1139,10 @@ func TestServerSentEventsSupport(t *testing.T) {
 		Path:   "/bar",
 	})
 	backend.EXPECT().ForwardHostHeader().Return(true)
+	backend.EXPECT().TransportSettings().Return(nil)
+	backend.EXPECT().RoundTripper(mock.Anything).RunAndReturn(
+		func(next http.RoundTripper) http.RoundTripper { return next })
+	backend.EXPECT().Done(mock.Anything, mock.Anything, mock.Anything)
 
 	exec.EXPECT().Execute(
 		mock.MatchedBy(func(ctx heimdall.RequestContext) bool {
@@ -0,0 +1,51 @@
+// Copyright 2025 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package proxy
+
+import (
+	"net/http"
+
+	"github.com/dadrus/heimdall/internal/handler/requestcontext"
+	"github.com/dadrus/heimdall/internal/heimdall"
+)
+
+// signingRoundTripper applies the upstream request signers registered by the pipeline mechanisms
+// to the outbound request. It is used as late as possible in the round tripper chain, so that the
+// signature covers the request, which is actually sent to the upstream service.
+type signingRoundTripper struct {
+	t       http.RoundTripper
+	signers []heimdall.UpstreamRequestSigner
+}
+
+func newSigningRoundTripper(rt http.RoundTripper, signers []heimdall.UpstreamRequestSigner) http.RoundTripper {
+	if len(signers) == 0 {
+		return rt
+	}
+
+	return &signingRoundTripper{t: rt, signers: signers}
+}
+
+func (t *signingRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	// round trippers must not modify the request
+	outReq := req.Clone(req.Context())
+
+	if err := requestcontext.SignRequest(outReq, t.signers); err != nil {
+		return nil, err
+	}
+
+	return t.t.RoundTrip(outReq)
+}
@@ -0,0 +1,98 @@
+// Copyright 2025 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package proxy
+
+import (
+	"crypto/tls"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/dadrus/heimdall/internal/config"
+	"github.com/dadrus/heimdall/internal/rules/rule"
+)
+
+// transportPool provides the transports of the backends defining their own transport settings
+// and falls back to a shared default transport for all other backends. The former transports
+// are owned by the transport settings and go away together with the rules referencing them.
+type transportPool struct {
+	defaultTransport *http.Transport
+}
+
+func newTransportPool(cfg config.ServeConfig, tlsCfg *tls.Config) *transportPool {
+	return &transportPool{
+		defaultTransport: &http.Transport{
+			Proxy: http.ProxyFromEnvironment,
+			DialContext: (&net.Dialer{
+				Timeout:   30 * time.Second, //nolint:mnd
+				KeepAlive: 30 * time.Second, //nolint:mnd
+			}).DialContext,
+			ResponseHeaderTimeout: cfg.Timeout.Read,
+			MaxIdleConns:          cfg.ConnectionsLimit.MaxIdle,
+			MaxIdleConnsPerHost:   cfg.ConnectionsLimit.MaxIdlePerHost,
+			MaxConnsPerHost:       cfg.ConnectionsLimit.MaxPerHost,
+			IdleConnTimeout:       cfg.Timeout.Idle,
+			TLSHandshakeTimeout:   10 * time.Second, //nolint:mnd
+			ExpectContinueTimeout: 1 * time.Second,
+			ForceAttemptHTTP2:     true,
+			// tlsCfg is set for test purposes only
+			// and affects the default transport only
+			TLSClientConfig: tlsCfg,
+		},
+	}
+}
+
+func (p *transportPool) transportFor(settings *rule.TransportSettings) *http.Transport {
+	if settings == nil {
+		return p.defaultTransport
+	}
+
+	return settings.Transport(p.newTransport)
+}
+
+func (p *transportPool) newTransport(settings *rule.TransportSettings) *http.Transport {
+	transport := p.defaultTransport.Clone()
+
+	if tlsConfig := settings.TLSConfig(); tlsConfig != nil {
+		transport.TLSClientConfig = tlsConfig.Clone()
+	}
+
+	if settings.MaxPerHost != 0 {
+		transport.MaxConnsPerHost = settings.MaxPerHost
+	}
+
+	if settings.MaxIdle != 0 {
+		transport.MaxIdleConns = settings.MaxIdle
+	}
+
+	if settings.MaxIdlePerHost != 0 {
+		transport.MaxIdleConnsPerHost = settings.MaxIdlePerHost
+	}
+
+	if settings.ConnectTimeout != 0 {
+		transport.DialContext = (&net.Dialer{
+			Timeout:   settings.ConnectTimeout,
+			KeepAlive: 30 * time.Second, //nolint:mnd
+		}).DialContext
+	}
+
+	if settings.ResponseTimeout != 0 {
+		transport.ResponseHeaderTimeout = settings.ResponseTimeout
+	}
+
+	return transport
+}
@@ -0,0 +1,184 @@
+// Copyright 2025 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package proxy
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dadrus/heimdall/internal/config"
+	"github.com/dadrus/heimdall/internal/rules/rule"
+)
+
+func TestTransportPoolTransportFor(t *testing.T) {
+	t.Parallel()
+
+	// GIVEN
+	pool := newTransportPool(config.ServeConfig{
+		Timeout:          config.Timeout{Read: 5 * time.Second, Idle: time.Minute},
+		ConnectionsLimit: config.ConnectionsLimit{MaxPerHost: 1, MaxIdle: 2, MaxIdlePerHost: 3},
+	}, nil)
+
+	settings := &rule.TransportSettings{MaxPerHost: 10}
+	settings.SetTLSConfig(&tls.Config{ServerName: "foo.bar", MinVersion: tls.VersionTLS13})
+
+	// WHEN
+	defaultTransport := pool.transportFor(nil)
+	transport1 := pool.transportFor(settings)
+	transport2 := pool.transportFor(settings)
+	transport3 := pool.transportFor(&rule.TransportSettings{MaxIdle: 20, MaxIdlePerHost: 30})
+	transport4 := pool.transportFor(&rule.TransportSettings{
+		ConnectTimeout: time.Second, ResponseTimeout: 10 * time.Second,
+	})
+
+	// THEN
+	assert.Same(t, pool.defaultTransport, defaultTransport)
+	assert.Same(t, transport1, transport2)
+	assert.NotSame(t, transport1, transport3)
+	assert.NotSame(t, defaultTransport, transport1)
+
+	require.NotNil(t, transport1.TLSClientConfig)
+	assert.Equal(t, "foo.bar", transport1.TLSClientConfig.ServerName)
+	assert.Equal(t, 10, transport1.MaxConnsPerHost)
+	assert.Equal(t, 2, transport1.MaxIdleConns)
+	assert.Equal(t, 3, transport1.MaxIdleConnsPerHost)
+	assert.Equal(t, 5*time.Second, transport1.ResponseHeaderTimeout)
+	assert.Equal(t, time.Minute, transport1.IdleConnTimeout)
+
+	assert.Equal(t, 1, transport3.MaxConnsPerHost)
+	assert.Equal(t, 20, transport3.MaxIdleConns)
+	assert.Equal(t, 30, transport3.MaxIdleConnsPerHost)
+
+	assert.Equal(t, 10*time.Second, transport4.ResponseHeaderTimeout)
+	assert.Equal(t, 5*time.Second, transport3.ResponseHeaderTimeout)
+}
+
+func TestTransportPoolMutualTLS(t *testing.T) {
+	t.Parallel()
+
+	// GIVEN
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if len(req.TLS.PeerCertificates) == 0 {
+			rw.WriteHeader(http.StatusUnauthorized)
+
+			return
+		}
+
+		rw.WriteHeader(http.StatusOK)
+	}))
+	srv.TLS = &tls.Config{ClientAuth: tls.RequestClientCert, MinVersion: tls.VersionTLS12}
+	srv.StartTLS()
+
+	defer srv.Close()
+
+	rootCAs := x509.NewCertPool()
+	rootCAs.AddCert(srv.Certificate())
+
+	// the server certificate is used as client certificate as well
+	clientCert := srv.TLS.Certificates[0]
+
+	pool := newTransportPool(config.ServeConfig{}, nil)
+
+	for uc, tc := range map[string]struct {
+		settings *rule.TransportSettings
+		assert   func(t *testing.T, err error, resp *http.Response)
+	}{
+		"default transport does not trust the server": {
+			assert: func(t *testing.T, err error, _ *http.Response) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorContains(t, err, "certificate")
+			},
+		},
+		"backend specific trust store without client certificate": {
+			settings: newTransportSettings(&tls.Config{RootCAs: rootCAs, MinVersion: tls.VersionTLS12}),
+			assert: func(t *testing.T, err error, resp *http.Response) {
+				t.Helper()
+
+				require.NoError(t, err)
+				assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+			},
+		},
+		"backend specific trust store with client certificate": {
+			settings: newTransportSettings(&tls.Config{
+				RootCAs:      rootCAs,
+				Certificates: []tls.Certificate{clientCert},
+				MinVersion:   tls.VersionTLS12,
+			}),
+			assert: func(t *testing.T, err error, resp *http.Response) {
+				t.Helper()
+
+				require.NoError(t, err)
+				assert.Equal(t, http.StatusOK, resp.StatusCode)
+			},
+		},
+	} {
+		t.Run(uc, func(t *testing.T) {
+			req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, srv.URL, nil)
+			require.NoError(t, err)
+
+			// WHEN
+			resp, err := pool.transportFor(tc.settings).RoundTrip(req)
+			if err == nil {
+				defer resp.Body.Close()
+			}
+
+			// THEN
+			tc.assert(t, err, resp)
+		})
+	}
+}
+
+func TestTransportPoolTransportForChangedTLSConfig(t *testing.T) {
+	t.Parallel()
+
+	// GIVEN
+	pool := newTransportPool(config.ServeConfig{}, nil)
+	settings := newTransportSettings(&tls.Config{ServerName: "foo.bar", MinVersion: tls.VersionTLS13})
+
+	transport1 := pool.transportFor(settings)
+
+	// WHEN
+	settings.SetTLSConfig(&tls.Config{ServerName: "bar.foo", MinVersion: tls.VersionTLS13})
+	transport2 := pool.transportFor(settings)
+
+	// THEN
+	assert.NotSame(t, transport1, transport2)
+	assert.Equal(t, "bar.foo", transport2.TLSClientConfig.ServerName)
+	assert.Same(t, transport2, pool.transportFor(settings))
+
+	// WHEN
+	settings.Close()
+
+	// THEN
+	assert.NotSame(t, transport2, pool.transportFor(settings))
+}
+
+func newTransportSettings(cfg *tls.Config) *rule.TransportSettings {
+	settings := &rule.TransportSettings{}
+	settings.SetTLSConfig(cfg)
+
+	return settings
+}
@@ -23,7 +23,9 @@ import (
 	"github.com/dadrus/heimdall/internal/x"
 )
 
-func extractURL(req *http.Request) *url.URL {
+// ExtractURL returns the url of the request to be handled by heimdall. That is the url of the actual
+// request, or, if these are present, the url derived from the X-Forwarded-* headers set by a proxy.
+func ExtractURL(req *http.Request) *url.URL {
 	var (
 		rawPath string
 		path    string
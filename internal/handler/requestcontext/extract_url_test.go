@@ -115,7 +115,7 @@ func TestExtractURL(t *testing.T) {
 			tc.configureRequest(t, req)
 
 			// WHEN
-			extracted := extractURL(req)
+			extracted := ExtractURL(req)
 
 			// THEN
 			tc.assert(t, extracted)
@@ -92,6 +92,72 @@ func (_c *ContextMock_AddHeaderForUpstream_Call) RunAndReturn(run func(string, s
 	return _c
 }
 
+// AddPathPrefixForUpstream provides a mock function with given fields: prefix
+func (_m *ContextMock) AddPathPrefixForUpstream(prefix string) {
+	_m.Called(prefix)
+}
+
+// ContextMock_AddPathPrefixForUpstream_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AddPathPrefixForUpstream'
+type ContextMock_AddPathPrefixForUpstream_Call struct {
+	*mock.Call
+}
+
+// AddPathPrefixForUpstream is a helper method to define mock.On call
+//   - prefix string
+func (_e *ContextMock_Expecter) AddPathPrefixForUpstream(prefix interface{}) *ContextMock_AddPathPrefixForUpstream_Call {
+	return &ContextMock_AddPathPrefixForUpstream_Call{Call: _e.mock.On("AddPathPrefixForUpstream", prefix)}
+}
+
+func (_c *ContextMock_AddPathPrefixForUpstream_Call) Run(run func(prefix string)) *ContextMock_AddPathPrefixForUpstream_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string))
+	})
+	return _c
+}
+
+func (_c *ContextMock_AddPathPrefixForUpstream_Call) Return() *ContextMock_AddPathPrefixForUpstream_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *ContextMock_AddPathPrefixForUpstream_Call) RunAndReturn(run func(string)) *ContextMock_AddPathPrefixForUpstream_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// AddSignerForUpstream provides a mock function with given fields: signer
+func (_m *ContextMock) AddSignerForUpstream(signer heimdall.UpstreamRequestSigner) {
+	_m.Called(signer)
+}
+
+// ContextMock_AddSignerForUpstream_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AddSignerForUpstream'
+type ContextMock_AddSignerForUpstream_Call struct {
+	*mock.Call
+}
+
+// AddSignerForUpstream is a helper method to define mock.On call
+//   - signer heimdall.UpstreamRequestSigner
+func (_e *ContextMock_Expecter) AddSignerForUpstream(signer interface{}) *ContextMock_AddSignerForUpstream_Call {
+	return &ContextMock_AddSignerForUpstream_Call{Call: _e.mock.On("AddSignerForUpstream", signer)}
+}
+
+func (_c *ContextMock_AddSignerForUpstream_Call) Run(run func(signer heimdall.UpstreamRequestSigner)) *ContextMock_AddSignerForUpstream_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(heimdall.UpstreamRequestSigner))
+	})
+	return _c
+}
+
+func (_c *ContextMock_AddSignerForUpstream_Call) Return() *ContextMock_AddSignerForUpstream_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *ContextMock_AddSignerForUpstream_Call) RunAndReturn(run func(heimdall.UpstreamRequestSigner)) *ContextMock_AddSignerForUpstream_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Context provides a mock function with given fields:
 func (_m *ContextMock) Context() context.Context {
 	ret := _m.Called()
@@ -232,6 +298,105 @@ func (_c *ContextMock_Outputs_Call) RunAndReturn(run func() map[string]interface
 	return _c
 }
 
+// RemoveCookieForUpstream provides a mock function with given fields: name
+func (_m *ContextMock) RemoveCookieForUpstream(name string) {
+	_m.Called(name)
+}
+
+// ContextMock_RemoveCookieForUpstream_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RemoveCookieForUpstream'
+type ContextMock_RemoveCookieForUpstream_Call struct {
+	*mock.Call
+}
+
+// RemoveCookieForUpstream is a helper method to define mock.On call
+//   - name string
+func (_e *ContextMock_Expecter) RemoveCookieForUpstream(name interface{}) *ContextMock_RemoveCookieForUpstream_Call {
+	return &ContextMock_RemoveCookieForUpstream_Call{Call: _e.mock.On("RemoveCookieForUpstream", name)}
+}
+
+func (_c *ContextMock_RemoveCookieForUpstream_Call) Run(run func(name string)) *ContextMock_RemoveCookieForUpstream_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string))
+	})
+	return _c
+}
+
+func (_c *ContextMock_RemoveCookieForUpstream_Call) Return() *ContextMock_RemoveCookieForUpstream_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *ContextMock_RemoveCookieForUpstream_Call) RunAndReturn(run func(string)) *ContextMock_RemoveCookieForUpstream_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RemoveHeaderForUpstream provides a mock function with given fields: name
+func (_m *ContextMock) RemoveHeaderForUpstream(name string) {
+	_m.Called(name)
+}
+
+// ContextMock_RemoveHeaderForUpstream_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RemoveHeaderForUpstream'
+type ContextMock_RemoveHeaderForUpstream_Call struct {
+	*mock.Call
+}
+
+// RemoveHeaderForUpstream is a helper method to define mock.On call
+//   - name string
+func (_e *ContextMock_Expecter) RemoveHeaderForUpstream(name interface{}) *ContextMock_RemoveHeaderForUpstream_Call {
+	return &ContextMock_RemoveHeaderForUpstream_Call{Call: _e.mock.On("RemoveHeaderForUpstream", name)}
+}
+
+func (_c *ContextMock_RemoveHeaderForUpstream_Call) Run(run func(name string)) *ContextMock_RemoveHeaderForUpstream_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string))
+	})
+	return _c
+}
+
+func (_c *ContextMock_RemoveHeaderForUpstream_Call) Return() *ContextMock_RemoveHeaderForUpstream_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *ContextMock_RemoveHeaderForUpstream_Call) RunAndReturn(run func(string)) *ContextMock_RemoveHeaderForUpstream_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RemoveQueryParameterForUpstream provides a mock function with given fields: name
+func (_m *ContextMock) RemoveQueryParameterForUpstream(name string) {
+	_m.Called(name)
+}
+
+// ContextMock_RemoveQueryParameterForUpstream_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RemoveQueryParameterForUpstream'
+type ContextMock_RemoveQueryParameterForUpstream_Call struct {
+	*mock.Call
+}
+
+// RemoveQueryParameterForUpstream is a helper method to define mock.On call
+//   - name string
+func (_e *ContextMock_Expecter) RemoveQueryParameterForUpstream(name interface{}) *ContextMock_RemoveQueryParameterForUpstream_Call {
+	return &ContextMock_RemoveQueryParameterForUpstream_Call{Call: _e.mock.On("RemoveQueryParameterForUpstream", name)}
+}
+
+func (_c *ContextMock_RemoveQueryParameterForUpstream_Call) Run(run func(name string)) *ContextMock_RemoveQueryParameterForUpstream_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string))
+	})
+	return _c
+}
+
+func (_c *ContextMock_RemoveQueryParameterForUpstream_Call) Return() *ContextMock_RemoveQueryParameterForUpstream_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *ContextMock_RemoveQueryParameterForUpstream_Call) RunAndReturn(run func(string)) *ContextMock_RemoveQueryParameterForUpstream_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Request provides a mock function with given fields:
 func (_m *ContextMock) Request() *heimdall.Request {
 	ret := _m.Called()
@@ -279,6 +444,39 @@ func (_c *ContextMock_Request_Call) RunAndReturn(run func() *heimdall.Request) *
 	return _c
 }
 
+// SetPathForUpstream provides a mock function with given fields: path
+func (_m *ContextMock) SetPathForUpstream(path string) {
+	_m.Called(path)
+}
+
+// ContextMock_SetPathForUpstream_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetPathForUpstream'
+type ContextMock_SetPathForUpstream_Call struct {
+	*mock.Call
+}
+
+// SetPathForUpstream is a helper method to define mock.On call
+//   - path string
+func (_e *ContextMock_Expecter) SetPathForUpstream(path interface{}) *ContextMock_SetPathForUpstream_Call {
+	return &ContextMock_SetPathForUpstream_Call{Call: _e.mock.On("SetPathForUpstream", path)}
+}
+
+func (_c *ContextMock_SetPathForUpstream_Call) Run(run func(path string)) *ContextMock_SetPathForUpstream_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string))
+	})
+	return _c
+}
+
+func (_c *ContextMock_SetPathForUpstream_Call) Return() *ContextMock_SetPathForUpstream_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *ContextMock_SetPathForUpstream_Call) RunAndReturn(run func(string)) *ContextMock_SetPathForUpstream_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // SetPipelineError provides a mock function with given fields: err
 func (_m *ContextMock) SetPipelineError(err error) {
 	_m.Called(err)
@@ -312,6 +510,40 @@ func (_c *ContextMock_SetPipelineError_Call) RunAndReturn(run func(error)) *Cont
 	return _c
 }
 
+// SetQueryParameterForUpstream provides a mock function with given fields: name, value
+func (_m *ContextMock) SetQueryParameterForUpstream(name string, value string) {
+	_m.Called(name, value)
+}
+
+// ContextMock_SetQueryParameterForUpstream_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetQueryParameterForUpstream'
+type ContextMock_SetQueryParameterForUpstream_Call struct {
+	*mock.Call
+}
+
+// SetQueryParameterForUpstream is a helper method to define mock.On call
+//   - name string
+//   - value string
+func (_e *ContextMock_Expecter) SetQueryParameterForUpstream(name interface{}, value interface{}) *ContextMock_SetQueryParameterForUpstream_Call {
+	return &ContextMock_SetQueryParameterForUpstream_Call{Call: _e.mock.On("SetQueryParameterForUpstream", name, value)}
+}
+
+func (_c *ContextMock_SetQueryParameterForUpstream_Call) Run(run func(name string, value string)) *ContextMock_SetQueryParameterForUpstream_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *ContextMock_SetQueryParameterForUpstream_Call) Return() *ContextMock_SetQueryParameterForUpstream_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *ContextMock_SetQueryParameterForUpstream_Call) RunAndReturn(run func(string, string)) *ContextMock_SetQueryParameterForUpstream_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // NewContextMock creates a new instance of ContextMock. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewContextMock(t interface {
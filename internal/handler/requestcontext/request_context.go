@@ -24,6 +24,7 @@ import (
 	"net/textproto"
 	"net/url"
 	"strings"
+	"sync"
 
 	"github.com/dadrus/heimdall/internal/heimdall"
 	"github.com/dadrus/heimdall/internal/rules/mechanisms/contenttype"
@@ -36,23 +37,35 @@ type RequestContext struct {
 	reqURL          *url.URL
 	upstreamHeaders http.Header
 	upstreamCookies map[string]string
+	upstreamSigners []heimdall.UpstreamRequestSigner
+	removedHeaders  []string
+	removedCookies  []string
+	upstreamQuery   url.Values
+	removedQuery    []string
+	upstreamPath    string
+	pathPrefix      string
 	req             *http.Request
 	err             error
 
-	// the following properties are created lazy and cached
+	// the following properties are created lazy and cached. As these can be accessed concurrently
+	// (e.g. by steps of a parallel group), their creation is guarded
 
-	savedBody any
-	hmdlReq   *heimdall.Request
-	headers   map[string]string
-	outputs   map[string]any
+	bodyOnce    sync.Once
+	savedBody   any
+	hmdlReqOnce sync.Once
+	hmdlReq     *heimdall.Request
+	headersOnce sync.Once
+	headers     map[string]string
+	outputs     map[string]any
 }
 
 func New(req *http.Request) *RequestContext {
 	return &RequestContext{
 		reqMethod:       extractMethod(req),
-		reqURL:          extractURL(req),
+		reqURL:          ExtractURL(req),
 		upstreamHeaders: make(http.Header),
 		upstreamCookies: make(map[string]string),
+		upstreamQuery:   make(url.Values),
 		req:             req,
 	}
 }
@@ -75,66 +88,64 @@ func (r *RequestContext) Cookie(name string) string {
 }
 
 func (r *RequestContext) Headers() map[string]string {
-	if len(r.headers) == 0 {
+	r.headersOnce.Do(func() {
 		r.headers = make(map[string]string, len(r.req.Header)+1)
 
 		r.headers["Host"] = r.req.Host
 		for k, v := range r.req.Header {
 			r.headers[textproto.CanonicalMIMEHeaderKey(k)] = strings.Join(v, ",")
 		}
-	}
+	})
 
 	return r.headers
 }
 
 func (r *RequestContext) Body() any {
+	r.bodyOnce.Do(func() { r.savedBody = r.decodeBody() })
+
+	return r.savedBody
+}
+
+func (r *RequestContext) decodeBody() any {
 	if r.req.Body == nil || r.req.Body == http.NoBody {
 		return ""
 	}
 
-	if r.savedBody == nil {
-		// drain body by reading its contents into memory and preserving
-		var buf bytes.Buffer
-		if _, err := buf.ReadFrom(r.req.Body); err != nil {
-			return ""
-		}
-
-		if err := r.req.Body.Close(); err != nil {
-			return ""
-		}
-
-		body := buf.Bytes()
-		r.req.Body = io.NopCloser(bytes.NewReader(body))
-
-		decoder, err := contenttype.NewDecoder(r.Header("Content-Type"))
-		if err != nil {
-			r.savedBody = string(body)
+	// drain body by reading its contents into memory and preserving
+	var buf bytes.Buffer
+	if _, err := buf.ReadFrom(r.req.Body); err != nil {
+		return ""
+	}
 
-			return r.savedBody
-		}
+	if err := r.req.Body.Close(); err != nil {
+		return ""
+	}
 
-		data, err := decoder.Decode(body)
-		if err != nil {
-			r.savedBody = string(body)
+	body := buf.Bytes()
+	r.req.Body = io.NopCloser(bytes.NewReader(body))
 
-			return r.savedBody
-		}
+	decoder, err := contenttype.NewDecoder(r.Header("Content-Type"))
+	if err != nil {
+		return string(body)
+	}
 
-		r.savedBody = data
+	data, err := decoder.Decode(body)
+	if err != nil {
+		return string(body)
 	}
 
-	return r.savedBody
+	return data
 }
 
 func (r *RequestContext) Request() *heimdall.Request {
-	if r.hmdlReq == nil {
+	r.hmdlReqOnce.Do(func() {
 		r.hmdlReq = &heimdall.Request{
 			RequestFunctions:  r,
 			Method:            r.reqMethod,
 			URL:               &heimdall.URL{URL: *r.reqURL},
 			ClientIPAddresses: r.requestClientIPs(),
 		}
-	}
+	})
 
 	return r.hmdlReq
 }
@@ -180,3 +191,81 @@ func (r *RequestContext) Outputs() map[string]any {
 
 	return r.outputs
 }
+
+func (r *RequestContext) RemoveHeaderForUpstream(name string) {
+	r.removedHeaders = append(r.removedHeaders, textproto.CanonicalMIMEHeaderKey(name))
+}
+
+func (r *RequestContext) RemoveCookieForUpstream(name string) {
+	r.removedCookies = append(r.removedCookies, name)
+}
+
+func (r *RequestContext) RemovedUpstreamHeaders() []string { return r.removedHeaders }
+func (r *RequestContext) RemovedUpstreamCookies() []string { return r.removedCookies }
+
+func (r *RequestContext) SetQueryParameterForUpstream(name, value string) {
+	r.upstreamQuery.Set(name, value)
+}
+
+func (r *RequestContext) RemoveQueryParameterForUpstream(name string) {
+	r.removedQuery = append(r.removedQuery, name)
+}
+
+func (r *RequestContext) SetPathForUpstream(path string)         { r.upstreamPath = path }
+func (r *RequestContext) AddPathPrefixForUpstream(prefix string) { r.pathPrefix += prefix }
+
+// UpstreamURLModified returns whether query or path modifications have been registered for the upstream.
+func (r *RequestContext) UpstreamURLModified() bool {
+	return len(r.upstreamPath) != 0 || len(r.pathPrefix) != 0 || len(r.upstreamQuery) != 0 || len(r.removedQuery) != 0
+}
+
+// RewriteUpstreamURL applies the query and path modifications registered for the upstream
+// to the given url, which is expected to be the url the request is forwarded to.
+func (r *RequestContext) RewriteUpstreamURL(target *url.URL) {
+	if len(r.upstreamPath) != 0 {
+		target.Path = "/" + strings.TrimPrefix(r.upstreamPath, "/")
+		target.RawPath = ""
+	}
+
+	if len(r.pathPrefix) != 0 {
+		prefix := "/" + strings.Trim(r.pathPrefix, "/")
+
+		if len(target.RawPath) != 0 {
+			target.RawPath = (&url.URL{Path: prefix}).EscapedPath() + target.RawPath
+		}
+
+		target.Path = prefix + target.Path
+	}
+
+	if len(r.upstreamQuery) != 0 || len(r.removedQuery) != 0 {
+		query := target.Query()
+
+		for _, name := range r.removedQuery {
+			query.Del(name)
+		}
+
+		for name, values := range r.upstreamQuery {
+			query[name] = values
+		}
+
+		target.RawQuery = query.Encode()
+	}
+}
+
+func (r *RequestContext) AddSignerForUpstream(signer heimdall.UpstreamRequestSigner) {
+	r.upstreamSigners = append(r.upstreamSigners, signer)
+}
+
+func (r *RequestContext) UpstreamSigners() []heimdall.UpstreamRequestSigner { return r.upstreamSigners }
+
+// SignRequest applies the given signers in the order of their registration to the given request,
+// which is expected to represent the request forwarded to the upstream service.
+func SignRequest(req *http.Request, signers []heimdall.UpstreamRequestSigner) error {
+	for _, signer := range signers {
+		if err := signer.Sign(req); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -21,6 +21,7 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -240,3 +241,71 @@ func TestRequestContextBody(t *testing.T) {
 		})
 	}
 }
+
+func TestRequestContextRewriteUpstreamURL(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		uc       string
+		target   string
+		update   func(ctx *RequestContext)
+		expected string
+	}{
+		{
+			uc:       "no modifications",
+			target:   "https://foo.bar/baz?b=2&a=1",
+			update:   func(_ *RequestContext) {},
+			expected: "https://foo.bar/baz?b=2&a=1",
+		},
+		{
+			uc:     "query parameters set and removed",
+			target: "https://foo.bar/baz?debug=true&a=1&user=evil",
+			update: func(ctx *RequestContext) {
+				ctx.RemoveQueryParameterForUpstream("debug")
+				ctx.SetQueryParameterForUpstream("user", "alice")
+				ctx.SetQueryParameterForUpstream("tenant", "acme")
+			},
+			expected: "https://foo.bar/baz?a=1&tenant=acme&user=alice",
+		},
+		{
+			uc:     "path replaced",
+			target: "https://foo.bar/baz%2Fzab?a=1",
+			update: func(ctx *RequestContext) {
+				ctx.SetPathForUpstream("users/alice")
+			},
+			expected: "https://foo.bar/users/alice?a=1",
+		},
+		{
+			uc:     "path prefixes added",
+			target: "https://foo.bar/baz",
+			update: func(ctx *RequestContext) {
+				ctx.AddPathPrefixForUpstream("/tenants/")
+				ctx.AddPathPrefixForUpstream("acme")
+			},
+			expected: "https://foo.bar/tenants/acme/baz",
+		},
+		{
+			uc:     "path prefix added to path with encoded slash",
+			target: "https://foo.bar/baz%2Fzab",
+			update: func(ctx *RequestContext) {
+				ctx.AddPathPrefixForUpstream("/acme")
+			},
+			expected: "https://foo.bar/acme/baz%2Fzab",
+		},
+	} {
+		t.Run(tc.uc, func(t *testing.T) {
+			// GIVEN
+			target, err := url.Parse(tc.target)
+			require.NoError(t, err)
+
+			ctx := New(httptest.NewRequest(http.MethodGet, "https://foo.bar", nil))
+			tc.update(ctx)
+
+			// WHEN
+			ctx.RewriteUpstreamURL(target)
+
+			// THEN
+			assert.Equal(t, tc.expected, target.String())
+		})
+	}
+}
@@ -28,8 +28,11 @@ var (
 	ErrCommunication        = errors.New("communication error")
 	ErrCommunicationTimeout = errors.New("communication timeout error")
 	ErrConfiguration        = errors.New("configuration error")
+	ErrGraphQL              = errors.New("graphql error")
 	ErrInternal             = errors.New("internal error")
 	ErrNoRuleFound          = errors.New("no rule found")
+	ErrRateLimit            = errors.New("rate limit error")
+	ErrServiceUnavailable   = errors.New("service unavailable error")
 )
 
 type RedirectError struct {
@@ -41,3 +44,24 @@ type RedirectError struct {
 func (e *RedirectError) Error() string { return e.Message }
 
 func (e *RedirectError) Is(target error) bool { return reflect.TypeOf(e) == reflect.TypeOf(target) }
+
+type ResponseError struct {
+	Message string
+	Code    int
+	Headers map[string]string
+	Body    string
+}
+
+func (e *ResponseError) Error() string { return e.Message }
+
+func (e *ResponseError) Is(target error) bool { return reflect.TypeOf(e) == reflect.TypeOf(target) }
+
+// RetryAfterError holds the value of the Retry-After header received from an endpoint
+// responding with a rate limit or service unavailable error.
+type RetryAfterError struct {
+	RetryAfter string
+}
+
+func (e *RetryAfterError) Error() string { return "retry after " + e.RetryAfter }
+
+func (e *RetryAfterError) Is(target error) bool { return reflect.TypeOf(e) == reflect.TypeOf(target) }
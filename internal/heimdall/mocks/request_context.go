@@ -90,6 +90,72 @@ func (_c *RequestContextMock_AddHeaderForUpstream_Call) RunAndReturn(run func(st
 	return _c
 }
 
+// AddPathPrefixForUpstream provides a mock function with given fields: prefix
+func (_m *RequestContextMock) AddPathPrefixForUpstream(prefix string) {
+	_m.Called(prefix)
+}
+
+// RequestContextMock_AddPathPrefixForUpstream_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AddPathPrefixForUpstream'
+type RequestContextMock_AddPathPrefixForUpstream_Call struct {
+	*mock.Call
+}
+
+// AddPathPrefixForUpstream is a helper method to define mock.On call
+//   - prefix string
+func (_e *RequestContextMock_Expecter) AddPathPrefixForUpstream(prefix interface{}) *RequestContextMock_AddPathPrefixForUpstream_Call {
+	return &RequestContextMock_AddPathPrefixForUpstream_Call{Call: _e.mock.On("AddPathPrefixForUpstream", prefix)}
+}
+
+func (_c *RequestContextMock_AddPathPrefixForUpstream_Call) Run(run func(prefix string)) *RequestContextMock_AddPathPrefixForUpstream_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string))
+	})
+	return _c
+}
+
+func (_c *RequestContextMock_AddPathPrefixForUpstream_Call) Return() *RequestContextMock_AddPathPrefixForUpstream_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *RequestContextMock_AddPathPrefixForUpstream_Call) RunAndReturn(run func(string)) *RequestContextMock_AddPathPrefixForUpstream_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// AddSignerForUpstream provides a mock function with given fields: signer
+func (_m *RequestContextMock) AddSignerForUpstream(signer heimdall.UpstreamRequestSigner) {
+	_m.Called(signer)
+}
+
+// RequestContextMock_AddSignerForUpstream_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AddSignerForUpstream'
+type RequestContextMock_AddSignerForUpstream_Call struct {
+	*mock.Call
+}
+
+// AddSignerForUpstream is a helper method to define mock.On call
+//   - signer heimdall.UpstreamRequestSigner
+func (_e *RequestContextMock_Expecter) AddSignerForUpstream(signer interface{}) *RequestContextMock_AddSignerForUpstream_Call {
+	return &RequestContextMock_AddSignerForUpstream_Call{Call: _e.mock.On("AddSignerForUpstream", signer)}
+}
+
+func (_c *RequestContextMock_AddSignerForUpstream_Call) Run(run func(signer heimdall.UpstreamRequestSigner)) *RequestContextMock_AddSignerForUpstream_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(heimdall.UpstreamRequestSigner))
+	})
+	return _c
+}
+
+func (_c *RequestContextMock_AddSignerForUpstream_Call) Return() *RequestContextMock_AddSignerForUpstream_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *RequestContextMock_AddSignerForUpstream_Call) RunAndReturn(run func(heimdall.UpstreamRequestSigner)) *RequestContextMock_AddSignerForUpstream_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Context provides a mock function with given fields:
 func (_m *RequestContextMock) Context() context.Context {
 	ret := _m.Called()
@@ -184,6 +250,105 @@ func (_c *RequestContextMock_Outputs_Call) RunAndReturn(run func() map[string]in
 	return _c
 }
 
+// RemoveCookieForUpstream provides a mock function with given fields: name
+func (_m *RequestContextMock) RemoveCookieForUpstream(name string) {
+	_m.Called(name)
+}
+
+// RequestContextMock_RemoveCookieForUpstream_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RemoveCookieForUpstream'
+type RequestContextMock_RemoveCookieForUpstream_Call struct {
+	*mock.Call
+}
+
+// RemoveCookieForUpstream is a helper method to define mock.On call
+//   - name string
+func (_e *RequestContextMock_Expecter) RemoveCookieForUpstream(name interface{}) *RequestContextMock_RemoveCookieForUpstream_Call {
+	return &RequestContextMock_RemoveCookieForUpstream_Call{Call: _e.mock.On("RemoveCookieForUpstream", name)}
+}
+
+func (_c *RequestContextMock_RemoveCookieForUpstream_Call) Run(run func(name string)) *RequestContextMock_RemoveCookieForUpstream_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string))
+	})
+	return _c
+}
+
+func (_c *RequestContextMock_RemoveCookieForUpstream_Call) Return() *RequestContextMock_RemoveCookieForUpstream_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *RequestContextMock_RemoveCookieForUpstream_Call) RunAndReturn(run func(string)) *RequestContextMock_RemoveCookieForUpstream_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RemoveHeaderForUpstream provides a mock function with given fields: name
+func (_m *RequestContextMock) RemoveHeaderForUpstream(name string) {
+	_m.Called(name)
+}
+
+// RequestContextMock_RemoveHeaderForUpstream_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RemoveHeaderForUpstream'
+type RequestContextMock_RemoveHeaderForUpstream_Call struct {
+	*mock.Call
+}
+
+// RemoveHeaderForUpstream is a helper method to define mock.On call
+//   - name string
+func (_e *RequestContextMock_Expecter) RemoveHeaderForUpstream(name interface{}) *RequestContextMock_RemoveHeaderForUpstream_Call {
+	return &RequestContextMock_RemoveHeaderForUpstream_Call{Call: _e.mock.On("RemoveHeaderForUpstream", name)}
+}
+
+func (_c *RequestContextMock_RemoveHeaderForUpstream_Call) Run(run func(name string)) *RequestContextMock_RemoveHeaderForUpstream_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string))
+	})
+	return _c
+}
+
+func (_c *RequestContextMock_RemoveHeaderForUpstream_Call) Return() *RequestContextMock_RemoveHeaderForUpstream_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *RequestContextMock_RemoveHeaderForUpstream_Call) RunAndReturn(run func(string)) *RequestContextMock_RemoveHeaderForUpstream_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RemoveQueryParameterForUpstream provides a mock function with given fields: name
+func (_m *RequestContextMock) RemoveQueryParameterForUpstream(name string) {
+	_m.Called(name)
+}
+
+// RequestContextMock_RemoveQueryParameterForUpstream_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RemoveQueryParameterForUpstream'
+type RequestContextMock_RemoveQueryParameterForUpstream_Call struct {
+	*mock.Call
+}
+
+// RemoveQueryParameterForUpstream is a helper method to define mock.On call
+//   - name string
+func (_e *RequestContextMock_Expecter) RemoveQueryParameterForUpstream(name interface{}) *RequestContextMock_RemoveQueryParameterForUpstream_Call {
+	return &RequestContextMock_RemoveQueryParameterForUpstream_Call{Call: _e.mock.On("RemoveQueryParameterForUpstream", name)}
+}
+
+func (_c *RequestContextMock_RemoveQueryParameterForUpstream_Call) Run(run func(name string)) *RequestContextMock_RemoveQueryParameterForUpstream_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string))
+	})
+	return _c
+}
+
+func (_c *RequestContextMock_RemoveQueryParameterForUpstream_Call) Return() *RequestContextMock_RemoveQueryParameterForUpstream_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *RequestContextMock_RemoveQueryParameterForUpstream_Call) RunAndReturn(run func(string)) *RequestContextMock_RemoveQueryParameterForUpstream_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Request provides a mock function with given fields:
 func (_m *RequestContextMock) Request() *heimdall.Request {
 	ret := _m.Called()
@@ -231,6 +396,39 @@ func (_c *RequestContextMock_Request_Call) RunAndReturn(run func() *heimdall.Req
 	return _c
 }
 
+// SetPathForUpstream provides a mock function with given fields: path
+func (_m *RequestContextMock) SetPathForUpstream(path string) {
+	_m.Called(path)
+}
+
+// RequestContextMock_SetPathForUpstream_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetPathForUpstream'
+type RequestContextMock_SetPathForUpstream_Call struct {
+	*mock.Call
+}
+
+// SetPathForUpstream is a helper method to define mock.On call
+//   - path string
+func (_e *RequestContextMock_Expecter) SetPathForUpstream(path interface{}) *RequestContextMock_SetPathForUpstream_Call {
+	return &RequestContextMock_SetPathForUpstream_Call{Call: _e.mock.On("SetPathForUpstream", path)}
+}
+
+func (_c *RequestContextMock_SetPathForUpstream_Call) Run(run func(path string)) *RequestContextMock_SetPathForUpstream_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string))
+	})
+	return _c
+}
+
+func (_c *RequestContextMock_SetPathForUpstream_Call) Return() *RequestContextMock_SetPathForUpstream_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *RequestContextMock_SetPathForUpstream_Call) RunAndReturn(run func(string)) *RequestContextMock_SetPathForUpstream_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // SetPipelineError provides a mock function with given fields: err
 func (_m *RequestContextMock) SetPipelineError(err error) {
 	_m.Called(err)
@@ -264,6 +462,40 @@ func (_c *RequestContextMock_SetPipelineError_Call) RunAndReturn(run func(error)
 	return _c
 }
 
+// SetQueryParameterForUpstream provides a mock function with given fields: name, value
+func (_m *RequestContextMock) SetQueryParameterForUpstream(name string, value string) {
+	_m.Called(name, value)
+}
+
+// RequestContextMock_SetQueryParameterForUpstream_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetQueryParameterForUpstream'
+type RequestContextMock_SetQueryParameterForUpstream_Call struct {
+	*mock.Call
+}
+
+// SetQueryParameterForUpstream is a helper method to define mock.On call
+//   - name string
+//   - value string
+func (_e *RequestContextMock_Expecter) SetQueryParameterForUpstream(name interface{}, value interface{}) *RequestContextMock_SetQueryParameterForUpstream_Call {
+	return &RequestContextMock_SetQueryParameterForUpstream_Call{Call: _e.mock.On("SetQueryParameterForUpstream", name, value)}
+}
+
+func (_c *RequestContextMock_SetQueryParameterForUpstream_Call) Run(run func(name string, value string)) *RequestContextMock_SetQueryParameterForUpstream_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *RequestContextMock_SetQueryParameterForUpstream_Call) Return() *RequestContextMock_SetQueryParameterForUpstream_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *RequestContextMock_SetQueryParameterForUpstream_Call) RunAndReturn(run func(string, string)) *RequestContextMock_SetQueryParameterForUpstream_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // NewRequestContextMock creates a new instance of RequestContextMock. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewRequestContextMock(t interface {
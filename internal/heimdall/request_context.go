@@ -18,6 +18,7 @@ package heimdall
 
 import (
 	"context"
+	"net/http"
 	"net/url"
 )
 
@@ -28,6 +29,13 @@ type RequestContext interface {
 
 	AddHeaderForUpstream(name, value string)
 	AddCookieForUpstream(name, value string)
+	RemoveHeaderForUpstream(name string)
+	RemoveCookieForUpstream(name string)
+	SetQueryParameterForUpstream(name, value string)
+	RemoveQueryParameterForUpstream(name string)
+	SetPathForUpstream(path string)
+	AddPathPrefixForUpstream(prefix string)
+	AddSignerForUpstream(signer UpstreamRequestSigner)
 
 	Context() context.Context
 
@@ -36,6 +44,17 @@ type RequestContext interface {
 	Outputs() map[string]any
 }
 
+//go:generate mockery --name UpstreamRequestSigner --structname UpstreamRequestSignerMock
+
+// UpstreamRequestSigner is implemented by mechanisms, which sign the request forwarded to the upstream
+// service. Since the final request is known only after the pipeline has been executed, signing happens
+// as part of the request finalization.
+type UpstreamRequestSigner interface {
+	// Sign signs the given request by adding the signature related headers to it. Returned errors
+	// are expected to be heimdall errors and are reported as is.
+	Sign(req *http.Request) error
+}
+
 //go:generate mockery --name RequestFunctions --structname RequestFunctionsMock
 
 type RequestFunctions interface {
@@ -62,6 +62,8 @@ func (e *Entry) JOSEAlgorithm() jose.SignatureAlgorithm {
 		return getRSAAlgorithm(e.KeySize)
 	case AlgECDSA:
 		return getECDSAAlgorithm(e.KeySize)
+	case AlgEdDSA:
+		return jose.EdDSA
 	default:
 		panic("Unsupported algorithm: " + e.Alg)
 	}
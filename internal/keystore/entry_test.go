@@ -18,6 +18,7 @@ package keystore
 
 import (
 	"crypto/ecdsa"
+	"crypto/ed25519"
 	"crypto/elliptic"
 	"crypto/rand"
 	"crypto/rsa"
@@ -55,6 +56,9 @@ func TestEntryToJWK(t *testing.T) {
 	ecdsaPrivKey3, err := ecdsa.GenerateKey(elliptic.P521(), rand.Reader)
 	require.NoError(t, err)
 
+	_, ed25519PrivKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
 	for _, tc := range []struct {
 		uc     string
 		entry  *Entry
@@ -156,6 +160,22 @@ func TestEntryToJWK(t *testing.T) {
 				assert.Empty(t, jwk.CertificateThumbprintSHA256)
 			},
 		},
+		{
+			uc:    "ed25519 key",
+			entry: &Entry{KeyID: "baz", Alg: AlgEdDSA, PrivateKey: ed25519PrivKey, KeySize: 256},
+			assert: func(t *testing.T, entry *Entry, jwk jose.JSONWebKey) {
+				t.Helper()
+
+				assert.Equal(t, entry.KeyID, jwk.KeyID)
+				assert.Equal(t, entry.PrivateKey.Public(), jwk.Key)
+				assert.Equal(t, "sig", jwk.Use)
+				assert.Equal(t, string(jose.EdDSA), jwk.Algorithm)
+				assert.Empty(t, jwk.Certificates)
+				assert.Nil(t, jwk.CertificatesURL)
+				assert.Empty(t, jwk.CertificateThumbprintSHA1)
+				assert.Empty(t, jwk.CertificateThumbprintSHA256)
+			},
+		},
 	} {
 		t.Run("case="+tc.uc, func(t *testing.T) {
 			// GIVEN
@@ -19,6 +19,7 @@ package keystore
 import (
 	"crypto"
 	"crypto/ecdsa"
+	"crypto/ed25519"
 	"crypto/rsa"
 	"crypto/x509"
 	"encoding/hex"
@@ -43,6 +44,7 @@ const (
 
 	AlgRSA   = "RSA"
 	AlgECDSA = "ECDSA"
+	AlgEdDSA = "EdDSA"
 )
 
 var ErrNoSuchKey = errors.New("no such key")
@@ -235,6 +237,8 @@ func readPEMContents(data []byte) []*pem.Block {
 }
 
 func createEntry(key any, keyID string) (*Entry, error) {
+	const bitsInByte = 8
+
 	var (
 		sigKey    crypto.Signer
 		algorithm string
@@ -243,8 +247,6 @@ func createEntry(key any, keyID string) (*Entry, error) {
 
 	switch typedKey := key.(type) {
 	case *rsa.PrivateKey:
-		const bitsInByte = 8
-
 		algorithm = AlgRSA
 		sigKey = typedKey
 		size = typedKey.Size() * bitsInByte
@@ -252,9 +254,13 @@ func createEntry(key any, keyID string) (*Entry, error) {
 		algorithm = AlgECDSA
 		sigKey = typedKey
 		size = typedKey.Params().BitSize
+	case ed25519.PrivateKey:
+		algorithm = AlgEdDSA
+		sigKey = typedKey
+		size = ed25519.PublicKeySize * bitsInByte
 	default:
 		return nil, errorchain.NewWithMessage(heimdall.ErrInternal,
-			"unsupported key type; only rsa and ecdsa keys are supported")
+			"unsupported key type; only rsa, ecdsa and ed25519 keys are supported")
 	}
 
 	return &Entry{
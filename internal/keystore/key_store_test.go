@@ -20,6 +20,7 @@ import (
 	"bytes"
 	"crypto"
 	"crypto/ecdsa"
+	"crypto/ed25519"
 	"crypto/elliptic"
 	"crypto/rand"
 	"crypto/rsa"
@@ -677,6 +678,32 @@ func TestCreateKeyStoreFromKey(t *testing.T) {
 				assert.Nil(t, rsaKeyEntry.CertChain)
 			},
 		},
+		{
+			uc: "from ed25519 private key",
+			signer: func(t *testing.T) crypto.Signer {
+				t.Helper()
+
+				_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+				require.NoError(t, err)
+
+				return privateKey
+			},
+			assert: func(t *testing.T, ks keystore.KeyStore, err error) {
+				t.Helper()
+
+				require.NoError(t, err)
+				require.NotNil(t, ks)
+
+				assert.Len(t, ks.Entries(), 1)
+
+				edKeyEntry := findKeyType(ks.Entries(), keystore.AlgEdDSA)
+				assert.NotNil(t, edKeyEntry)
+				assert.NotEmpty(t, edKeyEntry.KeyID)
+				assert.NotNil(t, edKeyEntry.PrivateKey)
+				assert.Equal(t, 256, edKeyEntry.KeySize)
+				assert.Nil(t, edKeyEntry.CertChain)
+			},
+		},
 	} {
 		t.Run("case="+tc.uc, func(t *testing.T) {
 			// GIVEN
@@ -23,6 +23,7 @@ import (
 	"github.com/dadrus/heimdall/internal/app"
 	cache "github.com/dadrus/heimdall/internal/cache/module"
 	"github.com/dadrus/heimdall/internal/config"
+	"github.com/dadrus/heimdall/internal/geoip"
 	"github.com/dadrus/heimdall/internal/handler/management"
 	"github.com/dadrus/heimdall/internal/handler/metrics"
 	"github.com/dadrus/heimdall/internal/handler/profiling"
@@ -31,6 +32,7 @@ import (
 	"github.com/dadrus/heimdall/internal/otel/metrics/certificate"
 	"github.com/dadrus/heimdall/internal/rules"
 	"github.com/dadrus/heimdall/internal/rules/mechanisms"
+	"github.com/dadrus/heimdall/internal/scheduler"
 	"github.com/dadrus/heimdall/internal/validation"
 	"github.com/dadrus/heimdall/internal/watcher"
 )
@@ -39,6 +41,8 @@ type appContext struct {
 	w   watcher.Watcher
 	khr keyholder.Registry
 	co  certificate.Observer
+	geo geoip.Resolver
+	sch scheduler.Scheduler
 	v   validation.Validator
 	l   zerolog.Logger
 	c   *config.Configuration
@@ -47,6 +51,8 @@ type appContext struct {
 func (c *appContext) Watcher() watcher.Watcher                  { return c.w }
 func (c *appContext) KeyHolderRegistry() keyholder.Registry     { return c.khr }
 func (c *appContext) CertificateObserver() certificate.Observer { return c.co }
+func (c *appContext) GeoIP() geoip.Resolver                     { return c.geo }
+func (c *appContext) Scheduler() scheduler.Scheduler            { return c.sch }
 func (c *appContext) Validator() validation.Validator           { return c.v }
 func (c *appContext) Logger() zerolog.Logger                    { return c.l }
 func (c *appContext) Config() *config.Configuration             { return c.c }
@@ -54,10 +60,14 @@ func (c *appContext) Config() *config.Configuration             { return c.c }
 var Module = fx.Options( //nolint:gochecknoglobals
 	watcher.Module,
 	keyholder.Module,
+	geoip.Module,
+	scheduler.Module,
 	fx.Provide(func(
 		watcher watcher.Watcher,
 		khr keyholder.Registry,
 		observer certificate.Observer,
+		geo geoip.Resolver,
+		sch scheduler.Scheduler,
 		validator validation.Validator,
 		logger zerolog.Logger,
 		conf *config.Configuration,
@@ -66,6 +76,8 @@ var Module = fx.Options( //nolint:gochecknoglobals
 			w:   watcher,
 			khr: khr,
 			co:  observer,
+			geo: geo,
+			sch: sch,
 			v:   validator,
 			l:   logger,
 			c:   conf,
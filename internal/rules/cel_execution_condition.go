@@ -21,6 +21,7 @@ import (
 
 	"github.com/google/cel-go/cel"
 
+	"github.com/dadrus/heimdall/internal/geoip"
 	"github.com/dadrus/heimdall/internal/heimdall"
 	"github.com/dadrus/heimdall/internal/rules/mechanisms/cellib"
 	"github.com/dadrus/heimdall/internal/rules/mechanisms/subject"
@@ -55,8 +56,8 @@ func (c *celExecutionCondition) CanExecuteOnError(ctx heimdall.RequestContext, c
 	return true, nil
 }
 
-func newCelExecutionCondition(expression string) (*celExecutionCondition, error) {
-	env, err := cel.NewEnv(cellib.Library())
+func newCelExecutionCondition(expression string, geo geoip.Resolver) (*celExecutionCondition, error) {
+	env, err := cel.NewEnv(cellib.Library(geo))
 	if err != nil {
 		return nil, errorchain.NewWithMessage(heimdall.ErrInternal,
 			"failed creating CEL environment").CausedBy(err)
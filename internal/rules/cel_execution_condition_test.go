@@ -44,7 +44,7 @@ func TestNewCelExecutionCondition(t *testing.T) {
 	} {
 		t.Run(tc.uc, func(t *testing.T) {
 			// WHEN
-			condition, err := newCelExecutionCondition(tc.expression)
+			condition, err := newCelExecutionCondition(tc.expression, nil)
 
 			// THEN
 			if len(tc.err) != 0 {
@@ -114,7 +114,7 @@ func TestCelExecutionConditionCanExecuteOnSubject(t *testing.T) {
 				ClientIPAddresses: []string{"127.0.0.1", "10.10.10.10"},
 			})
 
-			condition, err := newCelExecutionCondition(tc.expression)
+			condition, err := newCelExecutionCondition(tc.expression, nil)
 			require.NoError(t, err)
 
 			// WHEN
@@ -172,7 +172,7 @@ func TestCelExecutionConditionCanExecuteOnError(t *testing.T) {
 				ClientIPAddresses: []string{"127.0.0.1", "10.10.10.10"},
 			})
 
-			condition, err := newCelExecutionCondition(tc.expression)
+			condition, err := newCelExecutionCondition(tc.expression, nil)
 			require.NoError(t, err)
 
 			// WHEN
@@ -23,6 +23,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/dadrus/heimdall/internal/heimdall/mocks"
+	"github.com/dadrus/heimdall/internal/rules/mechanisms/subject"
 	rulemocks "github.com/dadrus/heimdall/internal/rules/mocks"
 )
 
@@ -36,15 +37,15 @@ func TestCompositeErrorHandlerExecutionWithFallback(t *testing.T) {
 	ctx.EXPECT().Context().Return(t.Context())
 
 	eh1 := rulemocks.NewErrorHandlerMock(t)
-	eh1.EXPECT().Execute(ctx, testErr).Return(errErrorHandlerNotApplicable)
+	eh1.EXPECT().Execute(ctx, (*subject.Subject)(nil), testErr).Return(errErrorHandlerNotApplicable)
 
 	eh2 := rulemocks.NewErrorHandlerMock(t)
-	eh2.EXPECT().Execute(ctx, testErr).Return(nil)
+	eh2.EXPECT().Execute(ctx, (*subject.Subject)(nil), testErr).Return(nil)
 
 	eh := compositeErrorHandler{eh1, eh2}
 
 	// WHEN
-	err := eh.Execute(ctx, testErr)
+	err := eh.Execute(ctx, nil, testErr)
 
 	// THEN
 	require.NoError(t, err)
@@ -60,14 +61,14 @@ func TestCompositeErrorHandlerExecutionWithoutFallback(t *testing.T) {
 	ctx.EXPECT().Context().Return(t.Context())
 
 	eh1 := rulemocks.NewErrorHandlerMock(t)
-	eh1.EXPECT().Execute(ctx, testErr).Return(nil)
+	eh1.EXPECT().Execute(ctx, (*subject.Subject)(nil), testErr).Return(nil)
 
 	eh2 := rulemocks.NewErrorHandlerMock(t)
 
 	eh := compositeErrorHandler{eh1, eh2}
 
 	// WHEN
-	err := eh.Execute(ctx, testErr)
+	err := eh.Execute(ctx, nil, testErr)
 
 	// THEN
 	require.NoError(t, err)
@@ -83,15 +84,15 @@ func TestCompositeErrorHandlerExecutionWithNoApplicableErrorHandler(t *testing.T
 	ctx.EXPECT().Context().Return(t.Context())
 
 	eh1 := rulemocks.NewErrorHandlerMock(t)
-	eh1.EXPECT().Execute(ctx, testErr).Return(errErrorHandlerNotApplicable)
+	eh1.EXPECT().Execute(ctx, (*subject.Subject)(nil), testErr).Return(errErrorHandlerNotApplicable)
 
 	eh2 := rulemocks.NewErrorHandlerMock(t)
-	eh2.EXPECT().Execute(ctx, testErr).Return(errErrorHandlerNotApplicable)
+	eh2.EXPECT().Execute(ctx, (*subject.Subject)(nil), testErr).Return(errErrorHandlerNotApplicable)
 
 	eh := compositeErrorHandler{eh1, eh2}
 
 	// WHEN
-	err := eh.Execute(ctx, testErr)
+	err := eh.Execute(ctx, nil, testErr)
 
 	// THEN
 	require.Error(t, err)
@@ -31,6 +31,14 @@ type conditionalSubjectHandler struct {
 }
 
 func (h *conditionalSubjectHandler) Execute(ctx heimdall.RequestContext, sub *subject.Subject) error {
+	_, err := h.execute(ctx, sub)
+
+	return err
+}
+
+// execute executes the wrapped handler if the execution condition holds and reports whether
+// the handler has been executed.
+func (h *conditionalSubjectHandler) execute(ctx heimdall.RequestContext, sub *subject.Subject) (bool, error) {
 	logger := zerolog.Ctx(ctx.Context())
 
 	logger.Debug().Str("_id", h.h.ID()).Msg("Checking execution condition")
@@ -45,14 +53,14 @@ func (h *conditionalSubjectHandler) Execute(ctx heimdall.RequestContext, sub *su
 	}
 
 	if canExecute, err := h.c.CanExecuteOnSubject(ctx, sub); err != nil {
-		return err
+		return false, err
 	} else if canExecute {
-		return h.h.Execute(ctx, sub)
+		return true, h.h.Execute(ctx, sub)
 	}
 
 	logger.Debug().Str("_id", h.h.ID()).Msg("Execution skipped")
 
-	return nil
+	return false, nil
 }
 
 func (h *conditionalSubjectHandler) ID() string { return h.h.ID() }
@@ -21,9 +21,17 @@ import (
 )
 
 type Backend struct {
-	Host              string       `json:"host"                yaml:"host"             validate:"required"` //nolint:tagalign,lll
-	ForwardHostHeader *bool        `json:"forward_host_header" yaml:"forward_host_header"`
-	URLRewriter       *URLRewriter `json:"rewrite"             yaml:"rewrite"          validate:"omitnil"` //nolint:tagalign,lll
+	Host              string          `json:"host"                      yaml:"host,omitempty"                validate:"required_without=Targets,excluded_with=Targets"` //nolint:tagalign,lll
+	Targets           []Target        `json:"targets,omitempty"         yaml:"targets,omitempty"             validate:"omitempty,dive"`                                 //nolint:tagalign,lll
+	ForwardHostHeader *bool           `json:"forward_host_header"       yaml:"forward_host_header,omitempty"`
+	URLRewriter       *URLRewriter    `json:"rewrite"                   yaml:"rewrite,omitempty"             validate:"omitnil"` //nolint:tagalign,lll
+	TLS               *BackendTLS     `json:"tls,omitempty"             yaml:"tls,omitempty"                 validate:"omitnil"` //nolint:tagalign,lll
+	Connection        *Connection     `json:"connection,omitempty"      yaml:"connection,omitempty"          validate:"omitnil"` //nolint:tagalign,lll
+	LoadBalancing     *LoadBalancing  `json:"load_balancing,omitempty"  yaml:"load_balancing,omitempty"      validate:"omitnil"` //nolint:tagalign,lll
+	HealthCheck       *HealthCheck    `json:"health_check,omitempty"    yaml:"health_check,omitempty"        validate:"omitnil"` //nolint:tagalign,lll
+	Timeout           *Timeout        `json:"timeout,omitempty"         yaml:"timeout,omitempty"             validate:"omitnil"` //nolint:tagalign,lll
+	Retry             *Retry          `json:"retry,omitempty"           yaml:"retry,omitempty"               validate:"omitnil"` //nolint:tagalign,lll
+	CircuitBreaker    *CircuitBreaker `json:"circuit_breaker,omitempty" yaml:"circuit_breaker,omitempty"     validate:"omitnil"` //nolint:tagalign,lll
 }
 
 func (b *Backend) CreateURL(value *url.URL) *url.URL {
@@ -52,10 +60,76 @@ func (b *Backend) DeepCopyInto(out *Backend) {
 	}
 
 	if b.URLRewriter != nil {
-		b.URLRewriter.DeepCopyInto(out.URLRewriter)
+		in, out := &b.URLRewriter, &out.URLRewriter
+		*out = new(URLRewriter)
+		(*in).DeepCopyInto(*out)
 	}
+
+	if b.Targets != nil {
+		in, out := &b.Targets, &out.Targets
+		*out = make([]Target, len(*in))
+
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+
+	if b.TLS != nil {
+		in, out := &b.TLS, &out.TLS
+		*out = new(BackendTLS)
+		(*in).DeepCopyInto(*out)
+	}
+
+	if b.Connection != nil {
+		in, out := &b.Connection, &out.Connection
+		*out = new(Connection)
+		**out = **in
+	}
+
+	if b.LoadBalancing != nil {
+		in, out := &b.LoadBalancing, &out.LoadBalancing
+		*out = new(LoadBalancing)
+		(*in).DeepCopyInto(*out)
+	}
+
+	if b.HealthCheck != nil {
+		in, out := &b.HealthCheck, &out.HealthCheck
+		*out = new(HealthCheck)
+		(*in).DeepCopyInto(*out)
+	}
+
+	if b.Timeout != nil {
+		in, out := &b.Timeout, &out.Timeout
+		*out = new(Timeout)
+		**out = **in
+	}
+
+	if b.Retry != nil {
+		in, out := &b.Retry, &out.Retry
+		*out = new(Retry)
+		(*in).DeepCopyInto(*out)
+	}
+
+	if b.CircuitBreaker != nil {
+		in, out := &b.CircuitBreaker, &out.CircuitBreaker
+		*out = new(CircuitBreaker)
+		**out = **in
+	}
+}
+
+// HasTransportSettings returns true if the backend defines settings requiring
+// a dedicated transport to communicate with it.
+func (b *Backend) HasTransportSettings() bool {
+	return b != nil && (b.TLS != nil || b.Connection != nil || b.Timeout != nil)
+}
+
+// HasResiliencePolicy returns true if the backend defines retry or circuit breaking settings.
+func (b *Backend) HasResiliencePolicy() bool {
+	return b != nil && (b.Retry != nil || b.CircuitBreaker != nil)
 }
 
 func (b *Backend) IsInsecure() bool {
-	return b != nil && b.URLRewriter != nil && b.URLRewriter.Scheme == "http"
+	return b != nil &&
+		((b.URLRewriter != nil && b.URLRewriter.Scheme == "http") ||
+			(b.HealthCheck != nil && b.HealthCheck.Active != nil && b.HealthCheck.Active.Scheme == "http"))
 }
@@ -19,6 +19,7 @@ package config
 import (
 	"net/url"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -69,6 +70,7 @@ func TestBackendDeepCopyInto(t *testing.T) {
 	var out Backend
 
 	trueVal := true
+	weight := 2
 
 	in := Backend{
 		Host:              "bar.foo",
@@ -79,6 +81,31 @@ func TestBackendDeepCopyInto(t *testing.T) {
 			PathPrefixToAdd:     "/baz",
 			QueryParamsToRemove: QueryParamsRemover{"foo", "bar"},
 		},
+		TLS: &BackendTLS{
+			TrustStore: &TrustStore{Path: "/path/to/trust_store.pem"},
+			KeyStore:   &KeyStore{Path: "/path/to/key_store.pem", Password: "secret"},
+			KeyID:      "foo",
+			ServerName: "baz.foo",
+			MinVersion: "TLS1.2",
+		},
+		Connection: &Connection{MaxPerHost: 10, MaxIdle: 20, MaxIdlePerHost: 5},
+		Targets:    []Target{{Host: "foo.bar", Weight: &weight}},
+		LoadBalancing: &LoadBalancing{
+			Strategy: ConsistentHash,
+			HashKey:  &HashKey{Source: HashKeySourceHeader, Header: "X-Tenant"},
+		},
+		HealthCheck: &HealthCheck{
+			Active:  &ActiveHealthCheck{Path: "/health", Interval: Duration(time.Second)},
+			Passive: &PassiveHealthCheck{ConsecutiveFailures: 3},
+		},
+		Timeout: &Timeout{Connect: Duration(time.Second), Response: Duration(time.Minute)},
+		Retry: &Retry{
+			MaxAttempts: 3,
+			OnStatus:    []int{503},
+			Backoff:     &Backoff{Initial: Duration(time.Millisecond)},
+			Budget:      &RetryBudget{Percent: 10},
+		},
+		CircuitBreaker: &CircuitBreaker{ConsecutiveFailures: 10},
 	}
 
 	// WHEN
@@ -86,6 +113,20 @@ func TestBackendDeepCopyInto(t *testing.T) {
 
 	// THEN
 	require.Equal(t, in, out)
+	require.NotSame(t, in.TLS, out.TLS)
+	require.NotSame(t, in.TLS.TrustStore, out.TLS.TrustStore)
+	require.NotSame(t, in.TLS.KeyStore, out.TLS.KeyStore)
+	require.NotSame(t, in.Connection, out.Connection)
+	require.NotSame(t, &in.Targets[0], &out.Targets[0])
+	require.NotSame(t, in.Targets[0].Weight, out.Targets[0].Weight)
+	require.NotSame(t, in.LoadBalancing.HashKey, out.LoadBalancing.HashKey)
+	require.NotSame(t, in.HealthCheck.Active, out.HealthCheck.Active)
+	require.NotSame(t, in.HealthCheck.Passive, out.HealthCheck.Passive)
+	require.NotSame(t, in.Timeout, out.Timeout)
+	require.NotSame(t, &in.Retry.OnStatus[0], &out.Retry.OnStatus[0])
+	require.NotSame(t, in.Retry.Backoff, out.Retry.Backoff)
+	require.NotSame(t, in.Retry.Budget, out.Retry.Budget)
+	require.NotSame(t, in.CircuitBreaker, out.CircuitBreaker)
 }
 
 func TestBackendIsInsecure(t *testing.T) {
@@ -106,6 +147,13 @@ func TestBackendIsInsecure(t *testing.T) {
 		"secure if url rewriter configured with https scheme": {
 			backend: &Backend{URLRewriter: &URLRewriter{Scheme: "https"}},
 		},
+		"insecure if active health check configured with http scheme": {
+			backend:    &Backend{HealthCheck: &HealthCheck{Active: &ActiveHealthCheck{Scheme: "http"}}},
+			isInsecure: true,
+		},
+		"secure if active health check configured without scheme": {
+			backend: &Backend{HealthCheck: &HealthCheck{Active: &ActiveHealthCheck{}}},
+		},
 	} {
 		t.Run(uc, func(t *testing.T) {
 			assert.Equal(t, tc.isInsecure, tc.backend.IsInsecure())
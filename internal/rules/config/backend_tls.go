@@ -0,0 +1,56 @@
+// Copyright 2025 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+type TrustStore struct {
+	Path string `json:"path" yaml:"path" validate:"required"`
+}
+
+type KeyStore struct {
+	Path     string `json:"path"     yaml:"path"               validate:"required"`
+	Password string `json:"password" yaml:"password,omitempty"`
+}
+
+type BackendTLS struct {
+	TrustStore *TrustStore `json:"trust_store" yaml:"trust_store,omitempty" validate:"omitnil"`                   //nolint:lll,tagalign
+	KeyStore   *KeyStore   `json:"key_store"   yaml:"key_store,omitempty"   validate:"omitnil"`                   //nolint:lll,tagalign
+	KeyID      string      `json:"key_id"      yaml:"key_id,omitempty"      validate:"excluded_without=KeyStore"` //nolint:lll,tagalign
+	ServerName string      `json:"server_name" yaml:"server_name,omitempty"`
+	MinVersion string      `json:"min_version" yaml:"min_version,omitempty" validate:"omitempty,oneof=TLS1.2 TLS1.3"` //nolint:lll,tagalign
+}
+
+func (t *BackendTLS) DeepCopyInto(out *BackendTLS) {
+	*out = *t
+
+	if t.TrustStore != nil {
+		in, out := &t.TrustStore, &out.TrustStore
+		*out = new(TrustStore)
+		**out = **in
+	}
+
+	if t.KeyStore != nil {
+		in, out := &t.KeyStore, &out.KeyStore
+		*out = new(KeyStore)
+		**out = **in
+	}
+}
+
+type Connection struct {
+	MaxPerHost     int `json:"max_per_host"      yaml:"max_per_host,omitempty"      validate:"gte=0"`
+	MaxIdle        int `json:"max_idle"          yaml:"max_idle,omitempty"          validate:"gte=0"`
+	MaxIdlePerHost int `json:"max_idle_per_host" yaml:"max_idle_per_host,omitempty" validate:"gte=0"`
+}
@@ -29,6 +29,7 @@ func DecodeConfig(app app.Context, input any, output any) error {
 		&mapstructure.DecoderConfig{
 			DecodeHook: mapstructure.ComposeDecodeHookFunc(
 				mapstructure.StringToTimeDurationHookFunc(),
+				stringToDurationHookFunc(),
 			),
 			Result:      output,
 			ErrorUnused: true,
@@ -0,0 +1,78 @@
+// Copyright 2025 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"encoding/json"
+	"reflect"
+	"time"
+
+	"github.com/go-viper/mapstructure/v2"
+)
+
+// Duration is a time.Duration, which can be specified as a string, like "10s", in
+// rule sets independent of whether these are decoded via mapstructure or json.
+type Duration time.Duration
+
+func (d Duration) OrDefault(value time.Duration) time.Duration {
+	if d == 0 {
+		return value
+	}
+
+	return time.Duration(d)
+}
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var value any
+
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+
+	switch val := value.(type) {
+	case float64:
+		*d = Duration(val)
+	case string:
+		dur, err := time.ParseDuration(val)
+		if err != nil {
+			return err
+		}
+
+		*d = Duration(dur)
+	default:
+		return &json.UnmarshalTypeError{Value: string(data), Type: reflect.TypeOf(d)}
+	}
+
+	return nil
+}
+
+func stringToDurationHookFunc() mapstructure.DecodeHookFunc {
+	return func(from reflect.Type, to reflect.Type, data any) (any, error) {
+		if from.Kind() != reflect.String || to != reflect.TypeOf(Duration(0)) {
+			return data, nil
+		}
+
+		// nolint: forcetypeassert
+		dur, err := time.ParseDuration(data.(string))
+
+		return Duration(dur), err
+	}
+}
@@ -0,0 +1,70 @@
+// Copyright 2025 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDurationUnmarshalJSON(t *testing.T) {
+	t.Parallel()
+
+	for uc, tc := range map[string]struct {
+		value    string
+		expected Duration
+		err      bool
+	}{
+		"string":         {value: `"1m30s"`, expected: Duration(90 * time.Second)},
+		"number":         {value: `1000`, expected: Duration(1000)},
+		"invalid string": {value: `"foo"`, err: true},
+		"invalid type":   {value: `true`, err: true},
+	} {
+		t.Run(uc, func(t *testing.T) {
+			var dur Duration
+
+			err := json.Unmarshal([]byte(tc.value), &dur)
+
+			if tc.err {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tc.expected, dur)
+			}
+		})
+	}
+}
+
+func TestDurationMarshalJSON(t *testing.T) {
+	t.Parallel()
+
+	raw, err := json.Marshal(Duration(90 * time.Second))
+
+	require.NoError(t, err)
+	assert.JSONEq(t, `"1m30s"`, string(raw))
+}
+
+func TestDurationOrDefault(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, time.Second, Duration(0).OrDefault(time.Second))
+	assert.Equal(t, time.Minute, Duration(time.Minute).OrDefault(time.Second))
+}
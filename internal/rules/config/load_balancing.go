@@ -0,0 +1,102 @@
+// Copyright 2025 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+type LoadBalancingStrategy string
+
+const (
+	RoundRobin     LoadBalancingStrategy = "round_robin"
+	LeastRequests  LoadBalancingStrategy = "least_requests"
+	ConsistentHash LoadBalancingStrategy = "consistent_hash"
+)
+
+type HashKeySource string
+
+const (
+	HashKeySourceSubject HashKeySource = "subject"
+	HashKeySourceHeader  HashKeySource = "header"
+)
+
+type Target struct {
+	Host   string `json:"host"             yaml:"host"             validate:"required"`
+	Weight *int   `json:"weight,omitempty" yaml:"weight,omitempty" validate:"omitnil,gte=0,lte=1000"`
+}
+
+func (t *Target) DeepCopyInto(out *Target) {
+	*out = *t
+
+	if t.Weight != nil {
+		in, out := &t.Weight, &out.Weight
+		*out = new(int)
+		**out = **in
+	}
+}
+
+type HashKey struct {
+	Source HashKeySource `json:"source"           yaml:"source"           validate:"required,oneof=subject header"` //nolint:lll,tagalign
+	Header string        `json:"header,omitempty" yaml:"header,omitempty" validate:"required_if=Source header"`     //nolint:lll,tagalign
+}
+
+type LoadBalancing struct {
+	Strategy LoadBalancingStrategy `json:"strategy"           yaml:"strategy"           validate:"required,oneof=round_robin least_requests consistent_hash"` //nolint:lll,tagalign
+	HashKey  *HashKey              `json:"hash_key,omitempty" yaml:"hash_key,omitempty" validate:"required_if=Strategy consistent_hash"`                      //nolint:lll,tagalign
+}
+
+func (l *LoadBalancing) DeepCopyInto(out *LoadBalancing) {
+	*out = *l
+
+	if l.HashKey != nil {
+		in, out := &l.HashKey, &out.HashKey
+		*out = new(HashKey)
+		**out = **in
+	}
+}
+
+type ActiveHealthCheck struct {
+	Path               string   `json:"path,omitempty"                yaml:"path,omitempty"                validate:"omitempty,startswith=/"`                    //nolint:lll,tagalign
+	Scheme             string   `json:"scheme,omitempty"              yaml:"scheme,omitempty"              validate:"omitempty,oneof=http https,enforced=https"` //nolint:lll,tagalign
+	Interval           Duration `json:"interval,omitempty"            yaml:"interval,omitempty"            validate:"gte=0"`                                     //nolint:lll,tagalign
+	Timeout            Duration `json:"timeout,omitempty"             yaml:"timeout,omitempty"             validate:"gte=0"`                                     //nolint:lll,tagalign
+	HealthyThreshold   int      `json:"healthy_threshold,omitempty"   yaml:"healthy_threshold,omitempty"   validate:"gte=0"`                                     //nolint:lll,tagalign
+	UnhealthyThreshold int      `json:"unhealthy_threshold,omitempty" yaml:"unhealthy_threshold,omitempty" validate:"gte=0"`                                     //nolint:lll,tagalign
+}
+
+type PassiveHealthCheck struct {
+	ConsecutiveFailures int      `json:"consecutive_failures,omitempty" yaml:"consecutive_failures,omitempty" validate:"gte=0"` //nolint:lll,tagalign
+	EjectionTime        Duration `json:"ejection_time,omitempty"        yaml:"ejection_time,omitempty"        validate:"gte=0"` //nolint:lll,tagalign
+}
+
+type HealthCheck struct {
+	Active  *ActiveHealthCheck  `json:"active,omitempty"  yaml:"active,omitempty"  validate:"omitnil"`
+	Passive *PassiveHealthCheck `json:"passive,omitempty" yaml:"passive,omitempty" validate:"omitnil"`
+}
+
+func (h *HealthCheck) DeepCopyInto(out *HealthCheck) {
+	*out = *h
+
+	if h.Active != nil {
+		in, out := &h.Active, &out.Active
+		*out = new(ActiveHealthCheck)
+		**out = **in
+	}
+
+	if h.Passive != nil {
+		in, out := &h.Passive, &out.Passive
+		*out = new(PassiveHealthCheck)
+		**out = **in
+	}
+}
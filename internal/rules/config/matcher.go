@@ -19,16 +19,16 @@ package config
 import "slices"
 
 type Matcher struct {
-	Routes              []Route       `json:"routes"               yaml:"routes"               validate:"required,dive"`              //nolint:lll,tagalign
-	BacktrackingEnabled *bool         `json:"backtracking_enabled" yaml:"backtracking_enabled"`                                       //nolint:lll,tagalign
-	Scheme              string        `json:"scheme"               yaml:"scheme"               validate:"omitempty,oneof=http https"` //nolint:lll,tagalign
-	Methods             []string      `json:"methods"              yaml:"methods"              validate:"omitempty,dive,required"`    //nolint:lll,tagalign
-	Hosts               []HostMatcher `json:"hosts"                yaml:"hosts"                validate:"omitempty,dive,required"`    //nolint:lll,tagalign
+	Routes              []Route       `json:"routes"               yaml:"routes"                         validate:"required,dive"`              //nolint:lll,tagalign
+	BacktrackingEnabled *bool         `json:"backtracking_enabled" yaml:"backtracking_enabled,omitempty"`                                       //nolint:lll,tagalign
+	Scheme              string        `json:"scheme"               yaml:"scheme,omitempty"               validate:"omitempty,oneof=http https"` //nolint:lll,tagalign
+	Methods             []string      `json:"methods"              yaml:"methods,omitempty"              validate:"omitempty,dive,required"`    //nolint:lll,tagalign
+	Hosts               []HostMatcher `json:"hosts"                yaml:"hosts,omitempty"                validate:"omitempty,dive,required"`    //nolint:lll,tagalign
 }
 
 type Route struct {
-	Path       string             `json:"path"        yaml:"path"        validate:"required"`                //nolint:lll,tagalign
-	PathParams []ParameterMatcher `json:"path_params" yaml:"path_params" validate:"omitempty,dive,required"` //nolint:lll,tagalign
+	Path       string             `json:"path"        yaml:"path"                  validate:"required"`                //nolint:lll,tagalign
+	PathParams []ParameterMatcher `json:"path_params" yaml:"path_params,omitempty" validate:"omitempty,dive,required"` //nolint:lll,tagalign
 }
 
 func (r *Route) DeepCopyInto(out *Route) {
@@ -19,6 +19,7 @@ package config
 import (
 	"bytes"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -276,6 +277,284 @@ rules:
 				assert.Equal(t, "test", rul.Execute[0]["authenticator"])
 			},
 		},
+		"valid yaml rule set with load balanced backend": {
+			contentType: "application/yaml",
+			content: []byte(`
+version: "1"
+name: foo
+rules:
+- id: bar
+  match:
+    routes:
+      - path: /foo
+  forward_to:
+    targets:
+      - host: foo-1
+      - host: foo-2
+        weight: 3
+    load_balancing:
+      strategy: consistent_hash
+      hash_key:
+        source: header
+        header: X-Tenant
+    health_check:
+      active:
+        path: /health
+        interval: 5s
+      passive:
+        consecutive_failures: 3
+        ejection_time: 1m
+  execute:
+    - authenticator: test
+`),
+			assert: func(t *testing.T, err error, ruleSet *RuleSet) {
+				t.Helper()
+
+				require.NoError(t, err)
+				require.NotNil(t, ruleSet)
+				require.Len(t, ruleSet.Rules, 1)
+
+				be := ruleSet.Rules[0].Backend
+				require.NotNil(t, be)
+				assert.Empty(t, be.Host)
+				require.Len(t, be.Targets, 2)
+				assert.Equal(t, Target{Host: "foo-1"}, be.Targets[0])
+				assert.Equal(t, "foo-2", be.Targets[1].Host)
+				require.NotNil(t, be.Targets[1].Weight)
+				assert.Equal(t, 3, *be.Targets[1].Weight)
+				require.NotNil(t, be.LoadBalancing)
+				assert.Equal(t, ConsistentHash, be.LoadBalancing.Strategy)
+				assert.Equal(t, &HashKey{Source: HashKeySourceHeader, Header: "X-Tenant"}, be.LoadBalancing.HashKey)
+				require.NotNil(t, be.HealthCheck)
+				assert.Equal(t, &ActiveHealthCheck{Path: "/health", Interval: Duration(5 * time.Second)},
+					be.HealthCheck.Active)
+				assert.Equal(t, &PassiveHealthCheck{ConsecutiveFailures: 3, EjectionTime: Duration(time.Minute)},
+					be.HealthCheck.Passive)
+			},
+		},
+		"valid yaml rule set with backend defining timeouts, retries and circuit breaking": {
+			contentType: "application/yaml",
+			content: []byte(`
+version: "1"
+name: foo
+rules:
+- id: bar
+  match:
+    routes:
+      - path: /foo
+  forward_to:
+    host: foo
+    timeout:
+      connect: 2s
+      response: 10s
+    retry:
+      max_attempts: 3
+      on_status: [ 502, 503 ]
+      backoff:
+        initial: 10ms
+        max: 1s
+      budget:
+        percent: 10
+        min_concurrent: 5
+    circuit_breaker:
+      consecutive_failures: 10
+      open_duration: 1m
+      half_open_requests: 2
+  execute:
+    - authenticator: test
+`),
+			assert: func(t *testing.T, err error, ruleSet *RuleSet) {
+				t.Helper()
+
+				require.NoError(t, err)
+				require.NotNil(t, ruleSet)
+				require.Len(t, ruleSet.Rules, 1)
+
+				be := ruleSet.Rules[0].Backend
+				require.NotNil(t, be)
+				assert.Equal(t, &Timeout{Connect: Duration(2 * time.Second), Response: Duration(10 * time.Second)},
+					be.Timeout)
+				assert.Equal(t, &Retry{
+					MaxAttempts: 3,
+					OnStatus:    []int{502, 503},
+					Backoff:     &Backoff{Initial: Duration(10 * time.Millisecond), Max: Duration(time.Second)},
+					Budget:      &RetryBudget{Percent: 10, MinConcurrent: 5},
+				}, be.Retry)
+				assert.Equal(t, &CircuitBreaker{
+					ConsecutiveFailures: 10,
+					OpenDuration:        Duration(time.Minute),
+					HalfOpenRequests:    2,
+				}, be.CircuitBreaker)
+			},
+		},
+		"yaml rule set with retry policy without max attempts": {
+			contentType: "application/yaml",
+			content: []byte(`
+version: "1"
+name: foo
+rules:
+- id: bar
+  match:
+    routes:
+      - path: /foo
+  forward_to:
+    host: foo
+    retry:
+      on_status: [ 503 ]
+  execute:
+    - authenticator: test
+`),
+			assert: func(t *testing.T, err error, _ *RuleSet) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				require.ErrorContains(t, err, "'forward_to'.'retry'.'max_attempts'")
+			},
+		},
+		"valid yaml rule set with backend defining dynamic url rewrites": {
+			contentType: "application/yaml",
+			content: []byte(`
+version: "1"
+name: foo
+rules:
+- id: bar
+  match:
+    routes:
+      - path: /api/v1/tenants/:tenant/users/:id/profile
+  forward_to:
+    host: foo
+    rewrite:
+      replace_path:
+        regex: "^/api/v1/tenants/[^/]+/users/([^/]+)/profile$"
+        replacement: /profiles/$1
+      rename_query_parameters:
+        q: query
+      add_query_parameters:
+        tenant: "{{ .Request.URL.Captures.tenant }}"
+  execute:
+    - authenticator: test
+`),
+			assert: func(t *testing.T, err error, ruleSet *RuleSet) {
+				t.Helper()
+
+				require.NoError(t, err)
+				require.NotNil(t, ruleSet)
+				require.Len(t, ruleSet.Rules, 1)
+
+				rw := ruleSet.Rules[0].Backend.URLRewriter
+				require.NotNil(t, rw)
+				assert.Equal(t, &PathReplacement{
+					Regex:       "^/api/v1/tenants/[^/]+/users/([^/]+)/profile$",
+					Replacement: "/profiles/$1",
+				}, rw.PathReplacement)
+				assert.Equal(t, map[string]string{"q": "query"}, rw.QueryParamsToRename)
+				assert.Equal(t, map[string]string{"tenant": "{{ .Request.URL.Captures.tenant }}"}, rw.QueryParamsToAdd)
+			},
+		},
+		"yaml rule set with path template used together with path prefix removal": {
+			contentType: "application/yaml",
+			content: []byte(`
+version: "1"
+name: foo
+rules:
+- id: bar
+  match:
+    routes:
+      - path: /api/v1/users/:id
+  forward_to:
+    host: foo
+    rewrite:
+      strip_path_prefix: /api/v1
+      path_template: "/profiles/{{ .Request.URL.Captures.id }}"
+  execute:
+    - authenticator: test
+`),
+			assert: func(t *testing.T, err error, _ *RuleSet) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				require.ErrorContains(t, err, "'forward_to'.'rewrite'.'path_template'")
+			},
+		},
+		"yaml rule set with path replacement without regex": {
+			contentType: "application/yaml",
+			content: []byte(`
+version: "1"
+name: foo
+rules:
+- id: bar
+  match:
+    routes:
+      - path: /foo
+  forward_to:
+    host: foo
+    rewrite:
+      replace_path:
+        replacement: /bar
+  execute:
+    - authenticator: test
+`),
+			assert: func(t *testing.T, err error, _ *RuleSet) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				require.ErrorContains(t, err, "'forward_to'.'rewrite'.'replace_path'.'regex'")
+			},
+		},
+		"yaml rule set with backend defining host and targets": {
+			contentType: "application/yaml",
+			content: []byte(`
+version: "1"
+name: foo
+rules:
+- id: bar
+  match:
+    routes:
+      - path: /foo
+  forward_to:
+    host: foo
+    targets:
+      - host: foo-1
+  execute:
+    - authenticator: test
+`),
+			assert: func(t *testing.T, err error, _ *RuleSet) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				require.ErrorContains(t, err, "'forward_to'.'host'")
+			},
+		},
+		"yaml rule set with consistent hash load balancing without hash key": {
+			contentType: "application/yaml",
+			content: []byte(`
+version: "1"
+name: foo
+rules:
+- id: bar
+  match:
+    routes:
+      - path: /foo
+  forward_to:
+    targets:
+      - host: foo-1
+    load_balancing:
+      strategy: consistent_hash
+  execute:
+    - authenticator: test
+`),
+			assert: func(t *testing.T, err error, _ *RuleSet) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				require.ErrorContains(t, err, "'forward_to'.'load_balancing'.'hash_key'")
+			},
+		},
 		"yaml content type and validation error due to missing properties": {
 			contentType: "application/yaml",
 			content: []byte(`
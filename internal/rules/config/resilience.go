@@ -0,0 +1,67 @@
+// Copyright 2025 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+type Timeout struct {
+	Connect  Duration `json:"connect,omitempty"  yaml:"connect,omitempty"  validate:"gte=0"`
+	Response Duration `json:"response,omitempty" yaml:"response,omitempty" validate:"gte=0"`
+}
+
+type Backoff struct {
+	Initial Duration `json:"initial,omitempty" yaml:"initial,omitempty" validate:"gte=0"`
+	Max     Duration `json:"max,omitempty"     yaml:"max,omitempty"     validate:"gte=0"`
+}
+
+type RetryBudget struct {
+	Percent       int `json:"percent,omitempty"        yaml:"percent,omitempty"        validate:"gte=0,lte=100"`
+	MinConcurrent int `json:"min_concurrent,omitempty" yaml:"min_concurrent,omitempty" validate:"gte=0"`
+}
+
+type Retry struct {
+	MaxAttempts int          `json:"max_attempts"        yaml:"max_attempts"        validate:"required,gte=2,lte=10"`
+	OnStatus    []int        `json:"on_status,omitempty" yaml:"on_status,omitempty" validate:"omitempty,dive,gte=500,lte=599"` //nolint:lll,tagalign
+	Backoff     *Backoff     `json:"backoff,omitempty"   yaml:"backoff,omitempty"   validate:"omitnil"`
+	Budget      *RetryBudget `json:"budget,omitempty"    yaml:"budget,omitempty"    validate:"omitnil"`
+}
+
+func (r *Retry) DeepCopyInto(out *Retry) {
+	*out = *r
+
+	if r.OnStatus != nil {
+		in, out := &r.OnStatus, &out.OnStatus
+		*out = make([]int, len(*in))
+		copy(*out, *in)
+	}
+
+	if r.Backoff != nil {
+		in, out := &r.Backoff, &out.Backoff
+		*out = new(Backoff)
+		**out = **in
+	}
+
+	if r.Budget != nil {
+		in, out := &r.Budget, &out.Budget
+		*out = new(RetryBudget)
+		**out = **in
+	}
+}
+
+type CircuitBreaker struct {
+	ConsecutiveFailures int      `json:"consecutive_failures,omitempty" yaml:"consecutive_failures,omitempty" validate:"gte=0"` //nolint:lll,tagalign
+	OpenDuration        Duration `json:"open_duration,omitempty"        yaml:"open_duration,omitempty"        validate:"gte=0"` //nolint:lll,tagalign
+	HalfOpenRequests    int      `json:"half_open_requests,omitempty"   yaml:"half_open_requests,omitempty"   validate:"gte=0"` //nolint:lll,tagalign
+}
@@ -27,9 +27,9 @@ type MetaData struct {
 }
 
 type RuleSet struct {
-	MetaData
+	MetaData `yaml:",inline"`
 
 	Version string `json:"version" yaml:"version" validate:"required"` //nolint:tagalign
-	Name    string `json:"name"    yaml:"name"`
+	Name    string `json:"name"    yaml:"name,omitempty"`
 	Rules   []Rule `json:"rules"   yaml:"rules"   validate:"gt=0,dive,required"` //nolint:tagalign
 }
@@ -18,6 +18,7 @@ package config
 
 import (
 	"fmt"
+	"maps"
 	"net/url"
 	"slices"
 	"strings"
@@ -66,16 +67,40 @@ func (r QueryParamsRemover) RemoveFrom(value string) string {
 	return query.Encode()
 }
 
+type PathReplacement struct {
+	Regex       string `json:"regex"       yaml:"regex"                 validate:"required"`
+	Replacement string `json:"replacement" yaml:"replacement,omitempty"`
+}
+
 type URLRewriter struct {
-	Scheme              string             `json:"scheme"                 yaml:"scheme"                 validate:"omitempty,enforced=https"` //nolint: lll,tagalign
-	PathPrefixToCut     PrefixCutter       `json:"strip_path_prefix"      yaml:"strip_path_prefix"`
-	PathPrefixToAdd     PrefixAdder        `json:"add_path_prefix"        yaml:"add_path_prefix"`
-	QueryParamsToRemove QueryParamsRemover `json:"strip_query_parameters" yaml:"strip_query_parameters"`
+	Scheme              string             `json:"scheme"                            yaml:"scheme,omitempty"                  validate:"omitempty,enforced=https"` //nolint: lll,tagalign
+	PathPrefixToCut     PrefixCutter       `json:"strip_path_prefix"                 yaml:"strip_path_prefix,omitempty"`
+	PathPrefixToAdd     PrefixAdder        `json:"add_path_prefix"                   yaml:"add_path_prefix,omitempty"`
+	PathReplacement     *PathReplacement   `json:"replace_path,omitempty"            yaml:"replace_path,omitempty"            validate:"omitnil"`                                                       //nolint: lll,tagalign
+	PathTemplate        string             `json:"path_template,omitempty"           yaml:"path_template,omitempty"           validate:"excluded_with=PathPrefixToCut PathPrefixToAdd PathReplacement"` //nolint: lll,tagalign
+	QueryParamsToRemove QueryParamsRemover `json:"strip_query_parameters"            yaml:"strip_query_parameters,omitempty"`
+	QueryParamsToRename map[string]string  `json:"rename_query_parameters,omitempty" yaml:"rename_query_parameters,omitempty" validate:"omitempty,dive,keys,required,endkeys,required"` //nolint: lll,tagalign
+	QueryParamsToAdd    map[string]string  `json:"add_query_parameters,omitempty"    yaml:"add_query_parameters,omitempty"    validate:"omitempty,dive,keys,required,endkeys"`          //nolint: lll,tagalign
 }
 
 func (r *URLRewriter) DeepCopyInto(out *URLRewriter) {
 	*out = *r
 	out.QueryParamsToRemove = slices.Clone(r.QueryParamsToRemove)
+	out.QueryParamsToRename = maps.Clone(r.QueryParamsToRename)
+	out.QueryParamsToAdd = maps.Clone(r.QueryParamsToAdd)
+
+	if r.PathReplacement != nil {
+		in, out := &r.PathReplacement, &out.PathReplacement
+		*out = new(PathReplacement)
+		**out = **in
+	}
+}
+
+// HasDynamicRewrites returns true if the rewriter defines rewrites, which require compilation
+// or depend on the actual request, like regex based path replacements or templates.
+func (r *URLRewriter) HasDynamicRewrites() bool {
+	return r != nil && (r.PathReplacement != nil || len(r.PathTemplate) != 0 ||
+		len(r.QueryParamsToRename) != 0 || len(r.QueryParamsToAdd) != 0)
 }
 
 func (r *URLRewriter) Rewrite(value *url.URL) {
@@ -143,6 +143,9 @@ func TestURLRewriterDeepCopyInto(t *testing.T) {
 		PathPrefixToCut:     "/foo",
 		PathPrefixToAdd:     "/baz",
 		QueryParamsToRemove: QueryParamsRemover{"foo", "bar", "baz"},
+		PathReplacement:     &PathReplacement{Regex: "^/foo/(.*)$", Replacement: "/bar/$1"},
+		QueryParamsToRename: map[string]string{"foo": "bar"},
+		QueryParamsToAdd:    map[string]string{"baz": "{{ .Subject.ID }}"},
 	}
 
 	var out URLRewriter
@@ -155,4 +158,38 @@ func TestURLRewriterDeepCopyInto(t *testing.T) {
 
 	in.QueryParamsToRemove[0] = "oof"
 	assert.NotElementsMatch(t, in.QueryParamsToRemove, out.QueryParamsToRemove)
+	assert.NotSame(t, in.PathReplacement, out.PathReplacement)
+
+	in.QueryParamsToRename["foo"] = "baz"
+	in.QueryParamsToAdd["baz"] = "foo"
+	assert.Equal(t, "bar", out.QueryParamsToRename["foo"])
+	assert.Equal(t, "{{ .Subject.ID }}", out.QueryParamsToAdd["baz"])
+}
+
+func TestURLRewriterHasDynamicRewrites(t *testing.T) {
+	t.Parallel()
+
+	for uc, tc := range map[string]struct {
+		rewriter *URLRewriter
+		expected bool
+	}{
+		"nil rewriter":          {},
+		"static rewrites only":  {rewriter: &URLRewriter{Scheme: "https", PathPrefixToCut: "/foo"}},
+		"with path replacement": {rewriter: &URLRewriter{PathReplacement: &PathReplacement{}}, expected: true},
+		"with path template":    {rewriter: &URLRewriter{PathTemplate: "/foo"}, expected: true},
+		"with query parameter renames": {
+			rewriter: &URLRewriter{QueryParamsToRename: map[string]string{"a": "b"}},
+			expected: true,
+		},
+		"with query parameter to add": {
+			rewriter: &URLRewriter{QueryParamsToAdd: map[string]string{"a": "b"}},
+			expected: true,
+		},
+	} {
+		t.Run(uc, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tc.expected, tc.rewriter.HasDynamicRewrites())
+		})
+	}
 }
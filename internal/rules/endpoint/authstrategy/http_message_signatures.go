@@ -66,7 +66,7 @@ type HTTPMessageSignatures struct {
 }
 
 func (s *HTTPMessageSignatures) OnChanged(logger zerolog.Logger) {
-	err := s.init()
+	err := s.Init()
 	if err != nil {
 		logger.Warn().Err(err).
 			Str("_file", s.Signer.KeyStore.Path).
@@ -78,7 +78,7 @@ func (s *HTTPMessageSignatures) OnChanged(logger zerolog.Logger) {
 	}
 }
 
-func (s *HTTPMessageSignatures) init() error {
+func (s *HTTPMessageSignatures) Init() error {
 	ks, err := keystore.NewKeyStoreFromPEMFile(s.Signer.KeyStore.Path, s.Signer.KeyStore.Password)
 	if err != nil {
 		return errorchain.NewWithMessage(heimdall.ErrConfiguration,
@@ -213,6 +213,8 @@ func toHTTPSigKey(entry *keystore.Entry) httpsig.Key {
 		httpSigAlg = getRSAAlgorithm(entry.KeySize)
 	case keystore.AlgECDSA:
 		httpSigAlg = getECDSAAlgorithm(entry.KeySize)
+	case keystore.AlgEdDSA:
+		httpSigAlg = httpsig.Ed25519
 	default:
 		panic("unsupported key algorithm: " + entry.Alg)
 	}
@@ -246,7 +246,7 @@ func TestHTTPMessageSignaturesInit(t *testing.T) {
 		},
 	} {
 		t.Run(tc.uc, func(t *testing.T) {
-			err := tc.conf.init()
+			err := tc.conf.Init()
 
 			tc.assert(t, err, tc.conf)
 		})
@@ -360,7 +360,7 @@ func TestHTTPMessageSignaturesApply(t *testing.T) {
 		},
 	} {
 		t.Run(tc.uc, func(t *testing.T) {
-			err := tc.conf.init()
+			err := tc.conf.Init()
 			require.NoError(t, err)
 
 			req, err := http.NewRequestWithContext(
@@ -440,7 +440,7 @@ func TestHTTPMessageSignaturesOnChanged(t *testing.T) {
 		Signer:     SignerConfig{KeyStore: KeyStore{Path: pemFile.Name()}, KeyID: "key1"},
 		Components: []string{"@method"},
 	}
-	err = conf.init()
+	err = conf.Init()
 	require.NoError(t, err)
 
 	require.Equal(t, cert1, conf.certChain[0])
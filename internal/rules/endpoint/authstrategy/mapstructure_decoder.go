@@ -37,8 +37,10 @@ func DecodeAuthenticationStrategyHookFunc(ctx app.Context) mapstructure.DecodeHo
 			return data, nil
 		}
 
+		// only fields of the AuthenticationStrategy type are of interest. Fields of
+		// type any, to which a strategy is assignable as well, must be left untouched
 		dect := reflect.ValueOf(&as).Elem().Type()
-		if !dect.AssignableTo(to) {
+		if to != dect {
 			return data, nil
 		}
 
@@ -91,7 +93,7 @@ func decodeHTTPMessageSignaturesStrategy(ctx app.Context, config any) (any, erro
 		return nil, err
 	}
 
-	if err := httpSig.init(); err != nil {
+	if err := httpSig.Init(); err != nil {
 		return nil, err
 	}
 
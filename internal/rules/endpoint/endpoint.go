@@ -176,10 +176,37 @@ func (e Endpoint) readResponse(resp *http.Response) ([]byte, error) {
 		return rawData, nil
 	}
 
+	if err := UnavailabilityError(resp); err != nil {
+		return nil, err
+	}
+
 	return nil, errorchain.
 		NewWithMessagef(heimdall.ErrCommunication, "unexpected response code: %v", resp.StatusCode)
 }
 
+// UnavailabilityError classifies responses with 429 and 503 status codes as rate limit, respectively
+// service unavailable errors, preserving the value of the Retry-After header, if present. Returns nil
+// for responses with any other status code. The returned error is an *errorchain.ErrorChain.
+func UnavailabilityError(resp *http.Response) error {
+	var err *errorchain.ErrorChain
+
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests:
+		err = errorchain.NewWithMessage(heimdall.ErrRateLimit, "endpoint responded with 429 Too Many Requests")
+	case http.StatusServiceUnavailable:
+		err = errorchain.NewWithMessage(heimdall.ErrServiceUnavailable,
+			"endpoint responded with 503 Service Unavailable")
+	default:
+		return nil
+	}
+
+	if retryAfter := resp.Header.Get("Retry-After"); len(retryAfter) != 0 {
+		err.CausedBy(&heimdall.RetryAfterError{RetryAfter: retryAfter})
+	}
+
+	return err
+}
+
 func (e Endpoint) Hash() []byte {
 	hash := sha256.New()
 
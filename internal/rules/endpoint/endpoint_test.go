@@ -530,3 +530,58 @@ func TestEndpointHash(t *testing.T) {
 	assert.NotEqual(t, hash2, hash4)
 	assert.NotEqual(t, hash3, hash4)
 }
+
+func TestUnavailabilityError(t *testing.T) {
+	t.Parallel()
+
+	for uc, tc := range map[string]struct {
+		code   int
+		header http.Header
+		assert func(t *testing.T, err error)
+	}{
+		"not classified response code": {
+			code: http.StatusBadGateway,
+			assert: func(t *testing.T, err error) {
+				t.Helper()
+
+				require.NoError(t, err)
+			},
+		},
+		"too many requests without retry hint": {
+			code: http.StatusTooManyRequests,
+			assert: func(t *testing.T, err error) {
+				t.Helper()
+
+				require.ErrorIs(t, err, heimdall.ErrRateLimit)
+				require.NotErrorIs(t, err, &heimdall.RetryAfterError{})
+			},
+		},
+		"service unavailable with retry hint": {
+			code:   http.StatusServiceUnavailable,
+			header: http.Header{"Retry-After": []string{"Wed, 21 Oct 2015 07:28:00 GMT"}},
+			assert: func(t *testing.T, err error) {
+				t.Helper()
+
+				require.ErrorIs(t, err, heimdall.ErrServiceUnavailable)
+
+				var retryAfterErr *heimdall.RetryAfterError
+				require.ErrorAs(t, err, &retryAfterErr)
+				assert.Equal(t, "Wed, 21 Oct 2015 07:28:00 GMT", retryAfterErr.RetryAfter)
+			},
+		},
+	} {
+		t.Run(uc, func(t *testing.T) {
+			// GIVEN
+			resp := &http.Response{StatusCode: tc.code, Header: tc.header}
+			if resp.Header == nil {
+				resp.Header = http.Header{}
+			}
+
+			// WHEN
+			err := UnavailabilityError(resp)
+
+			// THEN
+			tc.assert(t, err)
+		})
+	}
+}
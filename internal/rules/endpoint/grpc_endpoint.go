@@ -0,0 +1,401 @@
+// Copyright 2025 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package endpoint
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"crypto/x509"
+	"errors"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	reflectionpb "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+
+	"github.com/dadrus/heimdall/internal/app"
+	"github.com/dadrus/heimdall/internal/config"
+	"github.com/dadrus/heimdall/internal/heimdall"
+	"github.com/dadrus/heimdall/internal/x"
+	"github.com/dadrus/heimdall/internal/x/errorchain"
+	"github.com/dadrus/heimdall/internal/x/stringx"
+	"github.com/dadrus/heimdall/internal/x/tlsx"
+)
+
+const grpcMaxRetryCount = 5
+
+var errNotUnaryMethod = errors.New("not a unary method")
+
+// for test purposes only.
+var grpcRootCertPool *x509.CertPool //nolint:gochecknoglobals
+
+type GRPCTLS struct {
+	Disabled bool            `mapstructure:"disabled"  validate:"enforced=false"`
+	KeyStore config.KeyStore `mapstructure:"key_store"`
+	KeyID    string          `mapstructure:"key_id"`
+}
+
+// GRPCEndpoint describes a unary method of a gRPC service. The request and response messages
+// are built dynamically using either the configured descriptor set, or the server reflection.
+type GRPCEndpoint struct {
+	Address       string            `mapstructure:"address"        validate:"required,hostname_port"`
+	Method        string            `mapstructure:"method"         validate:"required"`
+	DescriptorSet string            `mapstructure:"descriptor_set"`
+	Metadata      map[string]string `mapstructure:"metadata"`
+	Timeout       time.Duration     `mapstructure:"timeout"`
+	Retry         *Retry            `mapstructure:"retry"`
+	TLS           GRPCTLS           `mapstructure:"tls"`
+}
+
+// GRPCResponse holds the response message encoded as JSON and the response metadata.
+type GRPCResponse struct {
+	Payload  []byte
+	Metadata metadata.MD
+}
+
+func (e GRPCEndpoint) CreateClient(app app.Context, name string) (*GRPCClient, error) {
+	service, method, ok := strings.Cut(strings.TrimPrefix(e.Method, "/"), "/")
+	if !ok || len(service) == 0 || len(method) == 0 {
+		return nil, errorchain.NewWithMessagef(heimdall.ErrConfiguration,
+			"method '%s' is not in the <package>.<service>/<method> format", e.Method)
+	}
+
+	creds := insecure.NewCredentials()
+
+	if !e.TLS.Disabled {
+		tlsCfg, err := tlsx.ToTLSConfig(
+			&config.TLS{KeyStore: e.TLS.KeyStore, KeyID: e.TLS.KeyID},
+			tlsx.WithClientAuthentication(len(e.TLS.KeyStore.Path) != 0),
+			tlsx.WithSecretsWatcher(app.Watcher()),
+			tlsx.WithCertificateObserver(name, app.CertificateObserver()),
+		)
+		if err != nil {
+			return nil, errorchain.NewWithMessage(heimdall.ErrConfiguration,
+				"failed creating tls configuration for the grpc endpoint").CausedBy(err)
+		}
+
+		tlsCfg.RootCAs = grpcRootCertPool
+		creds = credentials.NewTLS(tlsCfg)
+	}
+
+	client := &GRPCClient{e: e, service: service, method: method}
+
+	if len(e.DescriptorSet) != 0 {
+		desc, err := loadMethodDescriptor(e.DescriptorSet, service, method)
+		if err != nil {
+			return nil, errorchain.NewWithMessagef(heimdall.ErrConfiguration,
+				"failed loading descriptor for method '%s' from %s", e.Method, e.DescriptorSet).CausedBy(err)
+		}
+
+		client.desc = desc
+	}
+
+	conn, err := grpc.NewClient(e.Address,
+		grpc.WithTransportCredentials(creds),
+		grpc.WithStatsHandler(otelgrpc.NewClientHandler()),
+	)
+	if err != nil {
+		return nil, errorchain.NewWithMessagef(heimdall.ErrConfiguration,
+			"failed creating grpc client for %s", e.Address).CausedBy(err)
+	}
+
+	client.conn = conn
+
+	return client, nil
+}
+
+func (e GRPCEndpoint) Hash() []byte {
+	hash := sha256.New()
+
+	hash.Write(stringx.ToBytes(e.Address))
+	hash.Write(stringx.ToBytes(e.Method))
+
+	buf := bytes.NewBufferString("")
+	for k, v := range e.Metadata {
+		buf.Write(stringx.ToBytes(k))
+		buf.Write(stringx.ToBytes(v))
+	}
+
+	hash.Write(buf.Bytes())
+
+	return hash.Sum(nil)
+}
+
+type GRPCClient struct {
+	e       GRPCEndpoint
+	conn    *grpc.ClientConn
+	service string
+	method  string
+
+	desc protoreflect.MethodDescriptor
+	mut  sync.Mutex
+}
+
+// Invoke calls the configured method with the request message created from the given payload,
+// which is expected to be the JSON representation of the request message. Metadata values are
+// rendered using the given renderer.
+func (c *GRPCClient) Invoke(ctx context.Context, payload string, rndr Renderer) (*GRPCResponse, error) {
+	logger := zerolog.Ctx(ctx)
+	tpl := x.IfThenElse[Renderer](rndr != nil, rndr, noopRenderer{})
+
+	desc, err := c.methodDescriptor(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	req := dynamicpb.NewMessage(desc.Input())
+	if len(payload) != 0 {
+		if err = protojson.Unmarshal(stringx.ToBytes(payload), req); err != nil {
+			return nil, errorchain.NewWithMessagef(heimdall.ErrInternal,
+				"failed to create %s message from payload", desc.Input().FullName()).CausedBy(err)
+		}
+	}
+
+	md := metadata.MD{}
+
+	for key, valueTemplate := range c.e.Metadata {
+		value, err := tpl.Render(valueTemplate)
+		if err != nil {
+			return nil, errorchain.NewWithMessagef(heimdall.ErrInternal,
+				"failed to render %s metadata value", key).CausedBy(err)
+		}
+
+		md.Set(key, value)
+	}
+
+	logger.Debug().Str("_endpoint", c.e.Address).Str("_method", c.e.Method).Msg("Calling grpc method")
+
+	resp := dynamicpb.NewMessage(desc.Output())
+	header := metadata.MD{}
+
+	if err = c.invoke(metadata.NewOutgoingContext(ctx, md), req, resp, grpc.Header(&header)); err != nil {
+		if status.Code(err) == codes.DeadlineExceeded {
+			return nil, errorchain.New(heimdall.ErrCommunicationTimeout).CausedBy(err)
+		}
+
+		return nil, errorchain.New(heimdall.ErrCommunication).CausedBy(err)
+	}
+
+	data, err := protojson.MarshalOptions{UseProtoNames: true, EmitUnpopulated: true}.Marshal(resp)
+	if err != nil {
+		return nil, errorchain.NewWithMessage(heimdall.ErrInternal,
+			"failed to marshal response message").CausedBy(err)
+	}
+
+	return &GRPCResponse{Payload: data, Metadata: header}, nil
+}
+
+func (c *GRPCClient) invoke(ctx context.Context, req, resp proto.Message, opts ...grpc.CallOption) error {
+	fullMethod := "/" + c.service + "/" + c.method
+
+	call := func() error {
+		callCtx := ctx
+
+		if c.e.Timeout > 0 {
+			var cancel context.CancelFunc
+
+			callCtx, cancel = context.WithTimeout(ctx, c.e.Timeout)
+			defer cancel()
+		}
+
+		return c.conn.Invoke(callCtx, fullMethod, req, resp, opts...)
+	}
+
+	err := call()
+	if c.e.Retry == nil {
+		return err
+	}
+
+	delay := c.e.Retry.MaxDelay
+
+	for attempt := 0; attempt < grpcMaxRetryCount && isRetryable(err); attempt++ {
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(delay):
+		}
+
+		err = call()
+
+		delay *= 2
+		if c.e.Retry.GiveUpAfter > 0 && delay > c.e.Retry.GiveUpAfter {
+			delay = c.e.Retry.GiveUpAfter
+		}
+	}
+
+	return err
+}
+
+func (c *GRPCClient) methodDescriptor(ctx context.Context) (protoreflect.MethodDescriptor, error) {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+
+	if c.desc != nil {
+		return c.desc, nil
+	}
+
+	files, err := c.resolveUsingReflection(ctx)
+	if err != nil {
+		return nil, errorchain.NewWithMessagef(heimdall.ErrCommunication,
+			"failed resolving method '%s' using server reflection", c.e.Method).CausedBy(err)
+	}
+
+	desc, err := findMethodDescriptor(files, c.service, c.method)
+	if err != nil {
+		return nil, errorchain.NewWithMessagef(heimdall.ErrInternal,
+			"failed resolving method '%s' using server reflection", c.e.Method).CausedBy(err)
+	}
+
+	c.desc = desc
+
+	return desc, nil
+}
+
+func (c *GRPCClient) resolveUsingReflection(ctx context.Context) (*protoregistry.Files, error) {
+	stream, err := reflectionpb.NewServerReflectionClient(c.conn).ServerReflectionInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defer stream.CloseSend() //nolint:errcheck
+
+	if err = stream.Send(&reflectionpb.ServerReflectionRequest{
+		MessageRequest: &reflectionpb.ServerReflectionRequest_FileContainingSymbol{
+			FileContainingSymbol: c.service,
+		},
+	}); err != nil {
+		return nil, err
+	}
+
+	resp, err := stream.Recv()
+	if err != nil {
+		return nil, err
+	}
+
+	if errResp := resp.GetErrorResponse(); errResp != nil {
+		return nil, status.Error(codes.Code(errResp.GetErrorCode()), errResp.GetErrorMessage()) //nolint:gosec
+	}
+
+	rawFiles := resp.GetFileDescriptorResponse().GetFileDescriptorProto()
+	fds := make([]*descriptorpb.FileDescriptorProto, len(rawFiles))
+
+	for idx, raw := range rawFiles {
+		fd := &descriptorpb.FileDescriptorProto{}
+		if err = proto.Unmarshal(raw, fd); err != nil {
+			return nil, err
+		}
+
+		fds[idx] = fd
+	}
+
+	return newFiles(fds)
+}
+
+func isRetryable(err error) bool {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.ResourceExhausted:
+		return true
+	default:
+		return false
+	}
+}
+
+func loadMethodDescriptor(path, service, method string) (protoreflect.MethodDescriptor, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var fds descriptorpb.FileDescriptorSet
+	if err = proto.Unmarshal(raw, &fds); err != nil {
+		return nil, err
+	}
+
+	files, err := newFiles(fds.GetFile())
+	if err != nil {
+		return nil, err
+	}
+
+	return findMethodDescriptor(files, service, method)
+}
+
+// newFiles creates a registry from the given file descriptors. Dependencies not included,
+// like the well known types, are taken from the files linked into heimdall.
+func newFiles(fds []*descriptorpb.FileDescriptorProto) (*protoregistry.Files, error) {
+	known := make(map[string]bool, len(fds))
+	for _, fd := range fds {
+		known[fd.GetName()] = true
+	}
+
+	for idx := 0; idx < len(fds); idx++ {
+		for _, dep := range fds[idx].GetDependency() {
+			if known[dep] {
+				continue
+			}
+
+			if global, err := protoregistry.GlobalFiles.FindFileByPath(dep); err == nil {
+				fds = append(fds, protodesc.ToFileDescriptorProto(global))
+				known[dep] = true
+			}
+		}
+	}
+
+	return protodesc.NewFiles(&descriptorpb.FileDescriptorSet{File: fds})
+}
+
+func findMethodDescriptor(
+	files *protoregistry.Files,
+	service, method string,
+) (protoreflect.MethodDescriptor, error) {
+	desc, err := files.FindDescriptorByName(protoreflect.FullName(service))
+	if err != nil {
+		return nil, err
+	}
+
+	svcDesc, ok := desc.(protoreflect.ServiceDescriptor)
+	if !ok {
+		return nil, errorchain.NewWithMessagef(protoregistry.NotFound, "'%s' is not a service", service)
+	}
+
+	methodDesc := svcDesc.Methods().ByName(protoreflect.Name(method))
+	if methodDesc == nil {
+		return nil, errorchain.NewWithMessagef(protoregistry.NotFound, "'%s/%s'", service, method)
+	}
+
+	if methodDesc.IsStreamingClient() || methodDesc.IsStreamingServer() {
+		return nil, errorchain.NewWithMessagef(errNotUnaryMethod, "'%s/%s'", service, method)
+	}
+
+	return methodDesc, nil
+}
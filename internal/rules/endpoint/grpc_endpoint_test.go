@@ -0,0 +1,421 @@
+// Copyright 2025 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package endpoint
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/types/descriptorpb"
+
+	"github.com/dadrus/heimdall/internal/app"
+	"github.com/dadrus/heimdall/internal/config"
+	"github.com/dadrus/heimdall/internal/heimdall"
+	certmocks "github.com/dadrus/heimdall/internal/otel/metrics/certificate/mocks"
+	"github.com/dadrus/heimdall/internal/watcher"
+	"github.com/dadrus/heimdall/internal/x/testsupport"
+)
+
+func writeHealthDescriptorSet(t *testing.T) string {
+	t.Helper()
+
+	fds := &descriptorpb.FileDescriptorSet{
+		File: []*descriptorpb.FileDescriptorProto{
+			protodesc.ToFileDescriptorProto(grpc_health_v1.File_grpc_health_v1_health_proto),
+		},
+	}
+
+	raw, err := proto.Marshal(fds)
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "health.pb")
+	require.NoError(t, os.WriteFile(path, raw, 0o600))
+
+	return path
+}
+
+func newTestAppContext(t *testing.T) app.Context {
+	t.Helper()
+
+	appCtx := app.NewContextMock(t)
+	appCtx.EXPECT().Watcher().Maybe().Return(&watcher.NoopWatcher{})
+	appCtx.EXPECT().CertificateObserver().Maybe().Return(certmocks.NewObserverMock(t))
+
+	return appCtx
+}
+
+func TestGRPCEndpointCreateClient(t *testing.T) {
+	t.Parallel()
+
+	descriptorSet := writeHealthDescriptorSet(t)
+
+	for uc, tc := range map[string]struct {
+		endpoint GRPCEndpoint
+		assert   func(t *testing.T, err error, client *GRPCClient)
+	}{
+		"with malformed method": {
+			endpoint: GRPCEndpoint{Address: "foo:1234", Method: "Check", TLS: GRPCTLS{Disabled: true}},
+			assert: func(t *testing.T, err error, _ *GRPCClient) {
+				t.Helper()
+
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				require.ErrorContains(t, err, "<package>.<service>/<method>")
+			},
+		},
+		"with not existing descriptor set": {
+			endpoint: GRPCEndpoint{
+				Address:       "foo:1234",
+				Method:        "grpc.health.v1.Health/Check",
+				DescriptorSet: "/does/not/exist.pb",
+				TLS:           GRPCTLS{Disabled: true},
+			},
+			assert: func(t *testing.T, err error, _ *GRPCClient) {
+				t.Helper()
+
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				require.ErrorContains(t, err, "failed loading descriptor")
+			},
+		},
+		"with unknown method in descriptor set": {
+			endpoint: GRPCEndpoint{
+				Address:       "foo:1234",
+				Method:        "grpc.health.v1.Health/Foo",
+				DescriptorSet: descriptorSet,
+				TLS:           GRPCTLS{Disabled: true},
+			},
+			assert: func(t *testing.T, err error, _ *GRPCClient) {
+				t.Helper()
+
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				require.ErrorContains(t, err, "grpc.health.v1.Health/Foo")
+			},
+		},
+		"with streaming method in descriptor set": {
+			endpoint: GRPCEndpoint{
+				Address:       "foo:1234",
+				Method:        "/grpc.health.v1.Health/Watch",
+				DescriptorSet: descriptorSet,
+				TLS:           GRPCTLS{Disabled: true},
+			},
+			assert: func(t *testing.T, err error, _ *GRPCClient) {
+				t.Helper()
+
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				require.ErrorContains(t, err, "not a unary method")
+			},
+		},
+		"with known method in descriptor set": {
+			endpoint: GRPCEndpoint{
+				Address:       "foo:1234",
+				Method:        "grpc.health.v1.Health/Check",
+				DescriptorSet: descriptorSet,
+				TLS:           GRPCTLS{Disabled: true},
+			},
+			assert: func(t *testing.T, err error, client *GRPCClient) {
+				t.Helper()
+
+				require.NoError(t, err)
+				require.NotNil(t, client.desc)
+				assert.Equal(t, "grpc.health.v1.Health.Check", string(client.desc.FullName()))
+				assert.Equal(t, "grpc.health.v1.Health", client.service)
+				assert.Equal(t, "Check", client.method)
+			},
+		},
+		"with TLS and without descriptor set": {
+			endpoint: GRPCEndpoint{Address: "foo:1234", Method: "grpc.health.v1.Health/Check"},
+			assert: func(t *testing.T, err error, client *GRPCClient) {
+				t.Helper()
+
+				require.NoError(t, err)
+				require.Nil(t, client.desc)
+				require.NotNil(t, client.conn)
+			},
+		},
+		"with TLS and not existing key store": {
+			endpoint: GRPCEndpoint{
+				Address: "foo:1234",
+				Method:  "grpc.health.v1.Health/Check",
+				TLS:     GRPCTLS{KeyStore: config.KeyStore{Path: "/does/not/exist.pem"}},
+			},
+			assert: func(t *testing.T, err error, _ *GRPCClient) {
+				t.Helper()
+
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				require.ErrorContains(t, err, "tls configuration")
+			},
+		},
+	} {
+		t.Run(uc, func(t *testing.T) {
+			client, err := tc.endpoint.CreateClient(newTestAppContext(t), "test")
+
+			tc.assert(t, err, client)
+		})
+	}
+}
+
+func TestGRPCClientInvoke(t *testing.T) {
+	t.Parallel()
+
+	descriptorSet := writeHealthDescriptorSet(t)
+
+	for uc, tc := range map[string]struct {
+		endpoint    func(addr string) GRPCEndpoint
+		payload     string
+		interceptor func(t *testing.T) grpc.UnaryServerInterceptor
+		assert      func(t *testing.T, err error, resp *GRPCResponse)
+	}{
+		"using server reflection": {
+			endpoint: func(addr string) GRPCEndpoint {
+				return GRPCEndpoint{
+					Address:  addr,
+					Method:   "grpc.health.v1.Health/Check",
+					Metadata: map[string]string{"x-foo": "{{ .Value }}"},
+					TLS:      GRPCTLS{Disabled: true},
+				}
+			},
+			payload: `{"service": "foo"}`,
+			interceptor: func(t *testing.T) grpc.UnaryServerInterceptor {
+				t.Helper()
+
+				return func(
+					ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler,
+				) (any, error) {
+					md, _ := metadata.FromIncomingContext(ctx)
+					assert.Equal(t, []string{"bar"}, md.Get("x-foo"))
+
+					require.NoError(t, grpc.SetHeader(ctx, metadata.Pairs("x-bar", "baz")))
+
+					return handler(ctx, req)
+				}
+			},
+			assert: func(t *testing.T, err error, resp *GRPCResponse) {
+				t.Helper()
+
+				require.NoError(t, err)
+
+				var payload map[string]any
+				require.NoError(t, json.Unmarshal(resp.Payload, &payload))
+				assert.Equal(t, map[string]any{"status": "SERVING"}, payload)
+				assert.Equal(t, []string{"baz"}, resp.Metadata.Get("x-bar"))
+			},
+		},
+		"using descriptor set": {
+			endpoint: func(addr string) GRPCEndpoint {
+				return GRPCEndpoint{
+					Address:       addr,
+					Method:        "grpc.health.v1.Health/Check",
+					DescriptorSet: descriptorSet,
+					TLS:           GRPCTLS{Disabled: true},
+				}
+			},
+			payload: `{"service": "foo"}`,
+			assert: func(t *testing.T, err error, resp *GRPCResponse) {
+				t.Helper()
+
+				require.NoError(t, err)
+				assert.JSONEq(t, `{"status": "SERVING"}`, string(resp.Payload))
+			},
+		},
+		"with unknown method": {
+			endpoint: func(addr string) GRPCEndpoint {
+				return GRPCEndpoint{Address: addr, Method: "grpc.health.v1.Health/Foo", TLS: GRPCTLS{Disabled: true}}
+			},
+			assert: func(t *testing.T, err error, _ *GRPCResponse) {
+				t.Helper()
+
+				require.ErrorIs(t, err, heimdall.ErrInternal)
+				require.ErrorContains(t, err, "server reflection")
+			},
+		},
+		"with unknown service": {
+			endpoint: func(addr string) GRPCEndpoint {
+				return GRPCEndpoint{Address: addr, Method: "foo.Bar/Baz", TLS: GRPCTLS{Disabled: true}}
+			},
+			assert: func(t *testing.T, err error, _ *GRPCResponse) {
+				t.Helper()
+
+				require.ErrorIs(t, err, heimdall.ErrCommunication)
+				require.ErrorContains(t, err, "server reflection")
+			},
+		},
+		"with invalid payload": {
+			endpoint: func(addr string) GRPCEndpoint {
+				return GRPCEndpoint{Address: addr, Method: "grpc.health.v1.Health/Check", TLS: GRPCTLS{Disabled: true}}
+			},
+			payload: `{"foo": "bar"}`,
+			assert: func(t *testing.T, err error, _ *GRPCResponse) {
+				t.Helper()
+
+				require.ErrorIs(t, err, heimdall.ErrInternal)
+				require.ErrorContains(t, err, "grpc.health.v1.HealthCheckRequest")
+			},
+		},
+		"with error response": {
+			endpoint: func(addr string) GRPCEndpoint {
+				return GRPCEndpoint{Address: addr, Method: "grpc.health.v1.Health/Check", TLS: GRPCTLS{Disabled: true}}
+			},
+			payload: `{"service": "bar"}`,
+			assert: func(t *testing.T, err error, _ *GRPCResponse) {
+				t.Helper()
+
+				require.ErrorIs(t, err, heimdall.ErrCommunication)
+				assert.Equal(t, codes.NotFound, status.Code(err))
+			},
+		},
+		"with timeout": {
+			endpoint: func(addr string) GRPCEndpoint {
+				return GRPCEndpoint{
+					Address: addr,
+					Method:  "grpc.health.v1.Health/Check",
+					Timeout: 10 * time.Millisecond,
+					TLS:     GRPCTLS{Disabled: true},
+				}
+			},
+			interceptor: func(t *testing.T) grpc.UnaryServerInterceptor {
+				t.Helper()
+
+				return func(
+					ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler,
+				) (any, error) {
+					if info.FullMethod == "/grpc.health.v1.Health/Check" {
+						time.Sleep(100 * time.Millisecond)
+					}
+
+					return handler(ctx, req)
+				}
+			},
+			assert: func(t *testing.T, err error, _ *GRPCResponse) {
+				t.Helper()
+
+				require.ErrorIs(t, err, heimdall.ErrCommunicationTimeout)
+			},
+		},
+		"with successful retry": {
+			endpoint: func(addr string) GRPCEndpoint {
+				return GRPCEndpoint{
+					Address: addr,
+					Method:  "grpc.health.v1.Health/Check",
+					Retry:   &Retry{MaxDelay: time.Millisecond, GiveUpAfter: 2 * time.Millisecond},
+					TLS:     GRPCTLS{Disabled: true},
+				}
+			},
+			payload: `{"service": "foo"}`,
+			interceptor: func(t *testing.T) grpc.UnaryServerInterceptor {
+				t.Helper()
+
+				calls := 0
+
+				return func(
+					ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler,
+				) (any, error) {
+					if info.FullMethod == "/grpc.health.v1.Health/Check" {
+						calls++
+						if calls < 3 {
+							return nil, status.Error(codes.Unavailable, "try again")
+						}
+					}
+
+					return handler(ctx, req)
+				}
+			},
+			assert: func(t *testing.T, err error, resp *GRPCResponse) {
+				t.Helper()
+
+				require.NoError(t, err)
+				assert.JSONEq(t, `{"status": "SERVING"}`, string(resp.Payload))
+			},
+		},
+		"without retry on not retryable error": {
+			endpoint: func(addr string) GRPCEndpoint {
+				return GRPCEndpoint{
+					Address: addr,
+					Method:  "grpc.health.v1.Health/Check",
+					Retry:   &Retry{MaxDelay: time.Millisecond},
+					TLS:     GRPCTLS{Disabled: true},
+				}
+			},
+			interceptor: func(t *testing.T) grpc.UnaryServerInterceptor {
+				t.Helper()
+
+				calls := 0
+
+				t.Cleanup(func() { assert.Equal(t, 1, calls) })
+
+				return func(
+					ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler,
+				) (any, error) {
+					if info.FullMethod == "/grpc.health.v1.Health/Check" {
+						calls++
+
+						return nil, status.Error(codes.PermissionDenied, "denied")
+					}
+
+					return handler(ctx, req)
+				}
+			},
+			assert: func(t *testing.T, err error, _ *GRPCResponse) {
+				t.Helper()
+
+				require.ErrorIs(t, err, heimdall.ErrCommunication)
+				assert.Equal(t, codes.PermissionDenied, status.Code(err))
+			},
+		},
+	} {
+		t.Run(uc, func(t *testing.T) {
+			var interceptor grpc.UnaryServerInterceptor
+			if tc.interceptor != nil {
+				interceptor = tc.interceptor(t)
+			}
+
+			addr := testsupport.StartGRPCHealthServer(t, interceptor)
+
+			client, err := tc.endpoint(addr).CreateClient(newTestAppContext(t), "test")
+			require.NoError(t, err)
+
+			resp, err := client.Invoke(t.Context(), tc.payload, RenderFunc(func(value string) (string, error) {
+				return strings.ReplaceAll(value, "{{ .Value }}", "bar"), nil
+			}))
+
+			tc.assert(t, err, resp)
+		})
+	}
+}
+
+func TestGRPCEndpointHash(t *testing.T) {
+	t.Parallel()
+
+	e1 := GRPCEndpoint{Address: "foo:1234", Method: "foo.Bar/Baz"}
+	e2 := GRPCEndpoint{Address: "foo:1234", Method: "foo.Bar/Baz", Metadata: map[string]string{"foo": "bar"}}
+
+	assert.NotEmpty(t, e1.Hash())
+	assert.Equal(t, e1.Hash(), e1.Hash())
+	assert.NotEqual(t, e1.Hash(), e2.Hash())
+}
@@ -18,11 +18,12 @@ package rules
 
 import (
 	"github.com/dadrus/heimdall/internal/heimdall"
+	"github.com/dadrus/heimdall/internal/rules/mechanisms/subject"
 )
 
 //go:generate mockery --name errorHandler --structname ErrorHandlerMock
 
 type errorHandler interface {
 	ID() string
-	Execute(ctx heimdall.RequestContext, causeErr error) error
+	Execute(ctx heimdall.RequestContext, sub *subject.Subject, causeErr error) error
 }
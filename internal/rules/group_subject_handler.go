@@ -0,0 +1,129 @@
+// Copyright 2025 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package rules
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/rs/zerolog"
+
+	"github.com/dadrus/heimdall/internal/heimdall"
+	"github.com/dadrus/heimdall/internal/rules/mechanisms/subject"
+	"github.com/dadrus/heimdall/internal/x/errorchain"
+)
+
+const (
+	groupTypeAnyOf = "any_of"
+	groupTypeAllOf = "all_of"
+)
+
+// groupSubjectHandler executes a group of pipeline steps. An all_of group succeeds if all steps,
+// which are executed according to their execution conditions, succeed. An any_of group succeeds
+// as soon as one of its steps has been executed successfully. Steps skipped due to their execution
+// condition do not count. If none succeeds, the errors of all failed steps are reported.
+type groupSubjectHandler struct {
+	typ      string
+	handlers []*conditionalSubjectHandler
+}
+
+func (g *groupSubjectHandler) Execute(ctx heimdall.RequestContext, sub *subject.Subject) error {
+	if g.typ == groupTypeAllOf {
+		return g.executeAllOf(ctx, sub)
+	}
+
+	return g.executeAnyOf(ctx, sub)
+}
+
+func (g *groupSubjectHandler) executeAllOf(ctx heimdall.RequestContext, sub *subject.Subject) error {
+	logger := zerolog.Ctx(ctx.Context())
+
+	for _, handler := range g.handlers {
+		if _, err := handler.execute(ctx, sub); err != nil {
+			logger.Info().Err(err).Msg("Pipeline step execution failed")
+
+			if !handler.ContinueOnError() {
+				return err
+			}
+
+			logger.Info().Msg("Error ignored. Continuing group execution")
+		}
+	}
+
+	return nil
+}
+
+func (g *groupSubjectHandler) executeAnyOf(ctx heimdall.RequestContext, sub *subject.Subject) error {
+	logger := zerolog.Ctx(ctx.Context())
+
+	var errs []error
+
+	for _, handler := range g.handlers {
+		executed, err := handler.execute(ctx, sub)
+		if err != nil {
+			logger.Info().Err(err).Msg("Pipeline step execution failed. Trying next step of the group")
+
+			errs = append(errs, err)
+
+			continue
+		}
+
+		if executed {
+			logger.Debug().Str("_id", handler.ID()).Msg("Group satisfied")
+
+			return nil
+		}
+	}
+
+	if len(errs) == 0 {
+		return errorchain.NewWithMessage(heimdall.ErrAuthorization,
+			"none of the steps of the any_of group has been executed").
+			WithErrorContext(g)
+	}
+
+	return errorchain.NewWithMessagef(heimdall.ErrAuthorization,
+		"none of the steps of the any_of group succeeded: %s", joinErrorMessages(errs)).
+		CausedBy(errors.Join(errs...)).
+		WithErrorContext(g)
+}
+
+func (g *groupSubjectHandler) ID() string {
+	ids := make([]string, len(g.handlers))
+	for idx, handler := range g.handlers {
+		ids[idx] = handler.ID()
+	}
+
+	return g.typ + "(" + strings.Join(ids, ",") + ")"
+}
+
+func (g *groupSubjectHandler) ContinueOnError() bool { return false }
+
+func joinErrorMessages(errs []error) string {
+	msgs := make([]string, len(errs))
+
+	for idx, err := range errs {
+		var identifier interface{ ID() string }
+
+		if errors.As(err, &identifier) {
+			msgs[idx] = identifier.ID() + ": " + err.Error()
+		} else {
+			msgs[idx] = err.Error()
+		}
+	}
+
+	return strings.Join(msgs, "; ")
+}
@@ -0,0 +1,243 @@
+// Copyright 2025 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package rules
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dadrus/heimdall/internal/heimdall"
+	"github.com/dadrus/heimdall/internal/heimdall/mocks"
+	rulemocks "github.com/dadrus/heimdall/internal/rules/mocks"
+	"github.com/dadrus/heimdall/internal/x/errorchain"
+)
+
+func TestGroupSubjectHandlerExecute(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		uc             string
+		typ            string
+		configureMocks func(t *testing.T, h1, h2 *rulemocks.SubjectHandlerMock,
+			c1, c2 *rulemocks.ExecutionConditionMock)
+		assert func(t *testing.T, err error)
+	}{
+		{
+			uc:  "all_of with all steps succeeding",
+			typ: groupTypeAllOf,
+			configureMocks: func(t *testing.T, h1, h2 *rulemocks.SubjectHandlerMock,
+				c1, c2 *rulemocks.ExecutionConditionMock,
+			) {
+				t.Helper()
+
+				c1.EXPECT().CanExecuteOnSubject(mock.Anything, mock.Anything).Return(true, nil)
+				c2.EXPECT().CanExecuteOnSubject(mock.Anything, mock.Anything).Return(true, nil)
+				h1.EXPECT().Execute(mock.Anything, mock.Anything).Return(nil)
+				h2.EXPECT().Execute(mock.Anything, mock.Anything).Return(nil)
+			},
+			assert: func(t *testing.T, err error) {
+				t.Helper()
+
+				require.NoError(t, err)
+			},
+		},
+		{
+			uc:  "all_of with first step failing",
+			typ: groupTypeAllOf,
+			configureMocks: func(t *testing.T, h1, _ *rulemocks.SubjectHandlerMock,
+				c1, _ *rulemocks.ExecutionConditionMock,
+			) {
+				t.Helper()
+
+				c1.EXPECT().CanExecuteOnSubject(mock.Anything, mock.Anything).Return(true, nil)
+				h1.EXPECT().Execute(mock.Anything, mock.Anything).Return(heimdall.ErrAuthorization)
+				h1.EXPECT().ContinueOnError().Return(false)
+			},
+			assert: func(t *testing.T, err error) {
+				t.Helper()
+
+				require.ErrorIs(t, err, heimdall.ErrAuthorization)
+			},
+		},
+		{
+			uc:  "all_of with failing step configured to continue on error",
+			typ: groupTypeAllOf,
+			configureMocks: func(t *testing.T, h1, h2 *rulemocks.SubjectHandlerMock,
+				c1, c2 *rulemocks.ExecutionConditionMock,
+			) {
+				t.Helper()
+
+				c1.EXPECT().CanExecuteOnSubject(mock.Anything, mock.Anything).Return(true, nil)
+				c2.EXPECT().CanExecuteOnSubject(mock.Anything, mock.Anything).Return(false, nil)
+				h1.EXPECT().Execute(mock.Anything, mock.Anything).Return(heimdall.ErrCommunication)
+				h1.EXPECT().ContinueOnError().Return(true)
+			},
+			assert: func(t *testing.T, err error) {
+				t.Helper()
+
+				require.NoError(t, err)
+			},
+		},
+		{
+			uc:  "any_of short circuits on first successful step",
+			typ: groupTypeAnyOf,
+			configureMocks: func(t *testing.T, h1, _ *rulemocks.SubjectHandlerMock,
+				c1, _ *rulemocks.ExecutionConditionMock,
+			) {
+				t.Helper()
+
+				c1.EXPECT().CanExecuteOnSubject(mock.Anything, mock.Anything).Return(true, nil)
+				h1.EXPECT().Execute(mock.Anything, mock.Anything).Return(nil)
+			},
+			assert: func(t *testing.T, err error) {
+				t.Helper()
+
+				require.NoError(t, err)
+			},
+		},
+		{
+			uc:  "any_of succeeds with second step after first one failed",
+			typ: groupTypeAnyOf,
+			configureMocks: func(t *testing.T, h1, h2 *rulemocks.SubjectHandlerMock,
+				c1, c2 *rulemocks.ExecutionConditionMock,
+			) {
+				t.Helper()
+
+				c1.EXPECT().CanExecuteOnSubject(mock.Anything, mock.Anything).Return(true, nil)
+				c2.EXPECT().CanExecuteOnSubject(mock.Anything, mock.Anything).Return(true, nil)
+				h1.EXPECT().Execute(mock.Anything, mock.Anything).Return(heimdall.ErrAuthorization)
+				h2.EXPECT().Execute(mock.Anything, mock.Anything).Return(nil)
+			},
+			assert: func(t *testing.T, err error) {
+				t.Helper()
+
+				require.NoError(t, err)
+			},
+		},
+		{
+			uc:  "any_of does not consider skipped steps as satisfied",
+			typ: groupTypeAnyOf,
+			configureMocks: func(t *testing.T, _, h2 *rulemocks.SubjectHandlerMock,
+				c1, c2 *rulemocks.ExecutionConditionMock,
+			) {
+				t.Helper()
+
+				c1.EXPECT().CanExecuteOnSubject(mock.Anything, mock.Anything).Return(false, nil)
+				c2.EXPECT().CanExecuteOnSubject(mock.Anything, mock.Anything).Return(true, nil)
+				h2.EXPECT().Execute(mock.Anything, mock.Anything).Return(nil)
+			},
+			assert: func(t *testing.T, err error) {
+				t.Helper()
+
+				require.NoError(t, err)
+			},
+		},
+		{
+			uc:  "any_of with all steps skipped",
+			typ: groupTypeAnyOf,
+			configureMocks: func(t *testing.T, _, _ *rulemocks.SubjectHandlerMock,
+				c1, c2 *rulemocks.ExecutionConditionMock,
+			) {
+				t.Helper()
+
+				c1.EXPECT().CanExecuteOnSubject(mock.Anything, mock.Anything).Return(false, nil)
+				c2.EXPECT().CanExecuteOnSubject(mock.Anything, mock.Anything).Return(false, nil)
+			},
+			assert: func(t *testing.T, err error) {
+				t.Helper()
+
+				require.ErrorIs(t, err, heimdall.ErrAuthorization)
+				require.ErrorContains(t, err, "none of the steps of the any_of group has been executed")
+
+				var identifier interface{ ID() string }
+				require.ErrorAs(t, err, &identifier)
+				assert.Equal(t, "any_of(foo,bar)", identifier.ID())
+			},
+		},
+		{
+			uc:  "any_of with all steps failing",
+			typ: groupTypeAnyOf,
+			configureMocks: func(t *testing.T, h1, h2 *rulemocks.SubjectHandlerMock,
+				c1, c2 *rulemocks.ExecutionConditionMock,
+			) {
+				t.Helper()
+
+				c1.EXPECT().CanExecuteOnSubject(mock.Anything, mock.Anything).Return(true, nil)
+				c2.EXPECT().CanExecuteOnSubject(mock.Anything, mock.Anything).Return(true, nil)
+				h1.EXPECT().Execute(mock.Anything, mock.Anything).
+					Return(errorchain.NewWithMessage(heimdall.ErrAuthorization, "not an admin").
+						WithErrorContext(h1))
+				h2.EXPECT().Execute(mock.Anything, mock.Anything).
+					Return(errorchain.NewWithMessage(heimdall.ErrCommunication, "owner check failed"))
+			},
+			assert: func(t *testing.T, err error) {
+				t.Helper()
+
+				require.ErrorIs(t, err, heimdall.ErrAuthorization)
+				require.ErrorIs(t, err, heimdall.ErrCommunication)
+				require.ErrorContains(t, err, "none of the steps of the any_of group succeeded")
+				require.ErrorContains(t, err, "foo: authorization error: not an admin")
+				require.ErrorContains(t, err, "communication error: owner check failed")
+
+				var identifier interface{ ID() string }
+				require.ErrorAs(t, err, &identifier)
+				assert.Equal(t, "any_of(foo,bar)", identifier.ID())
+			},
+		},
+	} {
+		t.Run(tc.uc, func(t *testing.T) {
+			// GIVEN
+			h1 := rulemocks.NewSubjectHandlerMock(t)
+			h2 := rulemocks.NewSubjectHandlerMock(t)
+			c1 := rulemocks.NewExecutionConditionMock(t)
+			c2 := rulemocks.NewExecutionConditionMock(t)
+
+			h1.EXPECT().ID().Maybe().Return("foo")
+			h2.EXPECT().ID().Maybe().Return("bar")
+
+			group := &groupSubjectHandler{
+				typ: tc.typ,
+				handlers: []*conditionalSubjectHandler{
+					{h: h1, c: c1},
+					{h: h2, c: c2},
+				},
+			}
+
+			ctx := mocks.NewRequestContextMock(t)
+			ctx.EXPECT().Context().Return(t.Context())
+
+			tc.configureMocks(t, h1, h2, c1, c2)
+
+			// WHEN
+			err := group.Execute(ctx, nil)
+
+			// THEN
+			tc.assert(t, err)
+		})
+	}
+}
+
+func TestGroupSubjectHandlerContinueOnError(t *testing.T) {
+	t.Parallel()
+
+	group := &groupSubjectHandler{typ: groupTypeAnyOf}
+
+	assert.False(t, group.ContinueOnError())
+}
@@ -0,0 +1,270 @@
+// Copyright 2025 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package loadbalancer
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+
+	"github.com/dadrus/heimdall/internal/heimdall"
+	"github.com/dadrus/heimdall/internal/rules/config"
+	"github.com/dadrus/heimdall/internal/x"
+	"github.com/dadrus/heimdall/internal/x/errorchain"
+)
+
+const (
+	defaultHealthCheckInterval = 10 * time.Second
+	defaultHealthCheckTimeout  = 2 * time.Second
+	defaultHealthyThreshold    = 2
+	defaultUnhealthyThreshold  = 2
+	defaultConsecutiveFailures = 5
+	defaultEjectionTime        = 30 * time.Second
+)
+
+// Balancer distributes requests over the targets of a backend and keeps track of their health.
+type Balancer struct {
+	ruleID   string
+	targets  []*Target
+	strategy strategy
+	checker  *healthChecker
+	metrics  *metrics
+	logger   zerolog.Logger
+
+	consecutiveFailures int64
+	ejectionTime        time.Duration
+
+	mut          sync.Mutex
+	started      bool
+	registration metric.Registration
+}
+
+func New(ruleID string, conf *config.Backend, opts ...Option) (*Balancer, error) {
+	args := newOptions()
+	for _, opt := range opts {
+		opt(args)
+	}
+
+	met, err := newMetrics()
+	if err != nil {
+		return nil, err
+	}
+
+	targets := make([]*Target, len(conf.Targets))
+	for idx, tc := range conf.Targets {
+		targets[idx] = newTarget(tc.Host, tc.Weight)
+	}
+
+	if len(targets) != 0 && !slices.ContainsFunc(targets, func(tgt *Target) bool { return !tgt.drained() }) {
+		return nil, errorchain.NewWithMessage(heimdall.ErrConfiguration,
+			"at least one target must have a weight greater than 0")
+	}
+
+	bl := &Balancer{
+		ruleID:   ruleID,
+		targets:  targets,
+		strategy: newStrategy(conf.LoadBalancing, targets),
+		metrics:  met,
+		logger:   args.logger,
+	}
+
+	if conf.HealthCheck == nil {
+		return bl, nil
+	}
+
+	if passive := conf.HealthCheck.Passive; passive != nil {
+		bl.consecutiveFailures = int64(x.IfThenElse(passive.ConsecutiveFailures != 0,
+			passive.ConsecutiveFailures, defaultConsecutiveFailures))
+		bl.ejectionTime = passive.EjectionTime.OrDefault(defaultEjectionTime)
+	}
+
+	if active := conf.HealthCheck.Active; active != nil {
+		// the scheme used by the upstream is only known in advance if it is configured
+		// explicitly, as it is taken from the request to heimdall otherwise.
+		if len(active.Scheme) == 0 && (conf.URLRewriter == nil || len(conf.URLRewriter.Scheme) == 0) {
+			return nil, errorchain.NewWithMessage(heimdall.ErrConfiguration,
+				"active health checking requires a scheme, if no rewrite scheme is configured")
+		}
+
+		bl.checker = newHealthChecker(conf, active, args)
+	}
+
+	return bl, nil
+}
+
+func newStrategy(conf *config.LoadBalancing, targets []*Target) strategy {
+	if conf == nil {
+		return &roundRobin{}
+	}
+
+	switch conf.Strategy {
+	case config.LeastRequests:
+		return leastRequests{}
+	case config.ConsistentHash:
+		return newConsistentHash(targets)
+	default:
+		return &roundRobin{}
+	}
+}
+
+func newHealthChecker(conf *config.Backend, active *config.ActiveHealthCheck, args *options) *healthChecker {
+	scheme := active.Scheme
+	if len(scheme) == 0 {
+		scheme = conf.URLRewriter.Scheme
+	}
+
+	return &healthChecker{
+		tlsConfig:          args.tlsConfig,
+		scheme:             scheme,
+		path:               x.IfThenElse(len(active.Path) != 0, active.Path, "/"),
+		interval:           active.Interval.OrDefault(defaultHealthCheckInterval),
+		timeout:            active.Timeout.OrDefault(defaultHealthCheckTimeout),
+		healthyThreshold:   x.IfThenElse(active.HealthyThreshold != 0, active.HealthyThreshold, defaultHealthyThreshold),
+		unhealthyThreshold: x.IfThenElse(active.UnhealthyThreshold != 0, active.UnhealthyThreshold, defaultUnhealthyThreshold),
+		logger:             args.logger,
+	}
+}
+
+// Start starts the active health checking, if configured, and the export of the availability metrics.
+func (b *Balancer) Start() error {
+	b.mut.Lock()
+	defer b.mut.Unlock()
+
+	if b.started {
+		return nil
+	}
+
+	registration, err := b.metrics.observeAvailability(b.ruleID, b.targets)
+	if err != nil {
+		return err
+	}
+
+	b.registration = registration
+	b.started = true
+
+	if b.checker != nil {
+		b.checker.start(b.targets)
+	}
+
+	return nil
+}
+
+// Stop stops all activities started by Start.
+func (b *Balancer) Stop() {
+	b.mut.Lock()
+	defer b.mut.Unlock()
+
+	if !b.started {
+		return
+	}
+
+	if b.checker != nil {
+		b.checker.stop()
+	}
+
+	_ = b.registration.Unregister()
+	b.started = false
+}
+
+// Next selects the target to forward the request to. The key is used by the consistent
+// hash strategy only. Targets given as excluded, e.g. a target a previous attempt of the
+// same request failed on, are only considered if no other target is available. If none
+// of the targets is available, all targets, which are not drained by a weight of 0, are
+// considered to avoid rejecting requests, which might still succeed. Each call must be
+// followed by a call to Done.
+func (b *Balancer) Next(ctx context.Context, key string, exclude ...*Target) *Target {
+	now := time.Now()
+
+	candidates := b.available(now, exclude)
+	if len(candidates) == 0 && len(exclude) != 0 {
+		candidates = b.available(now, nil)
+	}
+
+	if len(candidates) == 0 {
+		b.logger.Warn().Str("_rule_id", b.ruleID).Msg("No available upstream targets. Considering all targets")
+
+		candidates = slices.DeleteFunc(slices.Clone(b.targets), (*Target).drained)
+	}
+
+	tgt := b.strategy.next(candidates, key)
+
+	tgt.inflight.Add(1)
+	b.metrics.activeRequests.Add(ctx, 1, metric.WithAttributes(b.attributes(tgt)...))
+
+	return tgt
+}
+
+// Done reports the outcome of a request forwarded to the given target. A zero status code
+// together with a nil error means, the request has not been forwarded at all.
+func (b *Balancer) Done(ctx context.Context, tgt *Target, statusCode int, err error) {
+	tgt.inflight.Add(-1)
+	b.metrics.activeRequests.Add(ctx, -1, metric.WithAttributes(b.attributes(tgt)...))
+
+	if statusCode == 0 && err == nil {
+		return
+	}
+
+	failed := err != nil || statusCode >= http.StatusInternalServerError
+
+	b.metrics.requests.Add(ctx, 1, metric.WithAttributes(
+		append(b.attributes(tgt), outcomeAttrKey.String(x.IfThenElse(failed, "failure", "success")))...))
+
+	if b.consecutiveFailures == 0 {
+		return
+	}
+
+	if !failed {
+		tgt.failures.Store(0)
+
+		return
+	}
+
+	if tgt.failures.Add(1) < b.consecutiveFailures {
+		return
+	}
+
+	tgt.failures.Store(0)
+	tgt.ejectedUntil.Store(time.Now().Add(b.ejectionTime).UnixNano())
+
+	b.metrics.ejections.Add(ctx, 1, metric.WithAttributes(b.attributes(tgt)...))
+	b.logger.Warn().
+		Str("_rule_id", b.ruleID).
+		Str("_target", tgt.host).
+		Msg("Upstream target ejected due to consecutive failures")
+}
+
+func (b *Balancer) available(now time.Time, exclude []*Target) []*Target {
+	candidates := make([]*Target, 0, len(b.targets))
+
+	for _, tgt := range b.targets {
+		if !tgt.drained() && tgt.available(now) && !slices.Contains(exclude, tgt) {
+			candidates = append(candidates, tgt)
+		}
+	}
+
+	return candidates
+}
+
+func (b *Balancer) attributes(tgt *Target) []attribute.KeyValue {
+	return []attribute.KeyValue{ruleIDAttrKey.String(b.ruleID), targetAttrKey.String(tgt.host)}
+}
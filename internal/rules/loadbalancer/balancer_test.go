@@ -0,0 +1,345 @@
+// Copyright 2025 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package loadbalancer
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dadrus/heimdall/internal/heimdall"
+	"github.com/dadrus/heimdall/internal/rules/config"
+)
+
+func TestBalancerRoundRobin(t *testing.T) {
+	t.Parallel()
+
+	// GIVEN
+	weight := 3
+
+	bl, err := New("test", &config.Backend{
+		Targets: []config.Target{{Host: "a"}, {Host: "b", Weight: &weight}},
+	})
+	require.NoError(t, err)
+
+	selected := map[string]int{}
+	sequence := ""
+
+	// WHEN
+	for range 8 {
+		tgt := bl.Next(t.Context(), "")
+		selected[tgt.Host()]++
+		sequence += tgt.Host()
+
+		bl.Done(t.Context(), tgt, http.StatusOK, nil)
+	}
+
+	// THEN
+	assert.Equal(t, 2, selected["a"])
+	assert.Equal(t, 6, selected["b"])
+	// smooth weighted round-robin does not send bursts to the heaviest target
+	assert.Equal(t, "babbbabb", sequence)
+}
+
+func TestBalancerLeastRequests(t *testing.T) {
+	t.Parallel()
+
+	// GIVEN
+	weight := 2
+
+	bl, err := New("test", &config.Backend{
+		Targets:       []config.Target{{Host: "a"}, {Host: "b"}, {Host: "c", Weight: &weight}},
+		LoadBalancing: &config.LoadBalancing{Strategy: config.LeastRequests},
+	})
+	require.NoError(t, err)
+
+	// WHEN
+	tgt1 := bl.Next(t.Context(), "")
+	tgt2 := bl.Next(t.Context(), "")
+	tgt3 := bl.Next(t.Context(), "")
+	tgt4 := bl.Next(t.Context(), "")
+
+	bl.Done(t.Context(), tgt1, http.StatusOK, nil)
+
+	tgt5 := bl.Next(t.Context(), "")
+
+	// THEN
+	assert.Equal(t, "a", tgt1.Host())
+	assert.Equal(t, "b", tgt2.Host())
+	assert.Equal(t, "c", tgt3.Host())
+	assert.Equal(t, "c", tgt4.Host())
+	assert.Equal(t, "a", tgt5.Host())
+	assert.Equal(t, int64(2), tgt3.inflight.Load())
+}
+
+func TestBalancerSkipsDrainedTargets(t *testing.T) {
+	t.Parallel()
+
+	zeroWeight := 0
+
+	for uc, conf := range map[string]*config.LoadBalancing{
+		"round robin":    {Strategy: config.RoundRobin},
+		"least requests": {Strategy: config.LeastRequests},
+		"consistent hash": {
+			Strategy: config.ConsistentHash,
+			HashKey:  &config.HashKey{Source: config.HashKeySourceSubject},
+		},
+	} {
+		t.Run(uc, func(t *testing.T) {
+			t.Parallel()
+
+			// GIVEN
+			bl, err := New("test", &config.Backend{
+				Targets:       []config.Target{{Host: "a", Weight: &zeroWeight}, {Host: "b"}, {Host: "c"}},
+				LoadBalancing: conf,
+			})
+			require.NoError(t, err)
+
+			selected := map[string]int{}
+
+			// WHEN
+			for idx := range 10 {
+				tgt := bl.Next(t.Context(), "subject-"+strconv.Itoa(idx))
+				selected[tgt.Host()]++
+
+				bl.Done(t.Context(), tgt, http.StatusOK, nil)
+			}
+
+			// none of the targets is available, so that the fallback kicks in
+			for _, tgt := range bl.targets {
+				tgt.healthy.Store(false)
+			}
+
+			for idx := range 10 {
+				tgt := bl.Next(t.Context(), "subject-"+strconv.Itoa(idx))
+				selected[tgt.Host()]++
+
+				bl.Done(t.Context(), tgt, http.StatusOK, nil)
+			}
+
+			// THEN
+			assert.Zero(t, selected["a"])
+			assert.Equal(t, 20, selected["b"]+selected["c"])
+		})
+	}
+}
+
+func TestBalancerWithAllTargetsDrained(t *testing.T) {
+	t.Parallel()
+
+	// GIVEN
+	zeroWeight := 0
+
+	// WHEN
+	_, err := New("test", &config.Backend{
+		Targets: []config.Target{{Host: "a", Weight: &zeroWeight}, {Host: "b", Weight: &zeroWeight}},
+	})
+
+	// THEN
+	require.Error(t, err)
+	require.ErrorIs(t, err, heimdall.ErrConfiguration)
+	assert.Contains(t, err.Error(), "at least one target must have a weight greater than 0")
+}
+
+func TestBalancerConsistentHash(t *testing.T) {
+	t.Parallel()
+
+	// GIVEN
+	bl, err := New("test", &config.Backend{
+		Targets: []config.Target{{Host: "a"}, {Host: "b"}, {Host: "c"}},
+		LoadBalancing: &config.LoadBalancing{
+			Strategy: config.ConsistentHash,
+			HashKey:  &config.HashKey{Source: config.HashKeySourceSubject},
+		},
+	})
+	require.NoError(t, err)
+
+	selected := map[string]string{}
+	used := map[string]bool{}
+
+	// WHEN
+	for idx := range 30 {
+		key := "subject-" + strconv.Itoa(idx)
+
+		tgt := bl.Next(t.Context(), key)
+		selected[key] = tgt.Host()
+		used[tgt.Host()] = true
+
+		bl.Done(t.Context(), tgt, http.StatusOK, nil)
+	}
+
+	// THEN
+	assert.Len(t, used, 3)
+
+	for key, host := range selected {
+		tgt := bl.Next(t.Context(), key)
+		assert.Equal(t, host, tgt.Host())
+
+		bl.Done(t.Context(), tgt, http.StatusOK, nil)
+	}
+
+	// WHEN the target serving a key is not available anymore
+	key := "subject-1"
+	ejected := bl.targets[slicesIndex(bl.targets, selected[key])]
+	ejected.healthy.Store(false)
+
+	tgt := bl.Next(t.Context(), key)
+
+	// THEN another target is used, whereby keys of other targets are not affected
+	assert.NotEqual(t, selected[key], tgt.Host())
+
+	for other, host := range selected {
+		if host == ejected.Host() {
+			continue
+		}
+
+		assert.Equal(t, host, bl.Next(t.Context(), other).Host())
+	}
+
+	// WHEN no key is present, the requests are distributed
+	used = map[string]bool{}
+
+	for range 4 {
+		used[bl.Next(t.Context(), "").Host()] = true
+	}
+
+	// THEN
+	assert.Len(t, used, 2)
+}
+
+func TestBalancerPassiveHealthCheck(t *testing.T) {
+	t.Parallel()
+
+	// GIVEN
+	bl, err := New("test", &config.Backend{
+		Targets: []config.Target{{Host: "a"}, {Host: "b"}},
+		HealthCheck: &config.HealthCheck{
+			Passive: &config.PassiveHealthCheck{ConsecutiveFailures: 2, EjectionTime: config.Duration(time.Hour)},
+		},
+	})
+	require.NoError(t, err)
+
+	tgtA := bl.targets[0]
+
+	// WHEN
+	bl.Done(t.Context(), tgtA, http.StatusBadGateway, nil)
+	bl.Done(t.Context(), tgtA, http.StatusOK, nil)
+	bl.Done(t.Context(), tgtA, 0, errors.New("test error"))
+
+	// THEN failure counter has been reset by the successful request
+	assert.True(t, tgtA.available(time.Now()))
+
+	// WHEN
+	bl.Done(t.Context(), tgtA, http.StatusServiceUnavailable, nil)
+
+	// THEN
+	assert.False(t, tgtA.available(time.Now()))
+
+	for range 4 {
+		assert.Equal(t, "b", bl.Next(t.Context(), "").Host())
+	}
+
+	// WHEN all targets are ejected
+	bl.Done(t.Context(), bl.targets[1], http.StatusBadGateway, nil)
+	bl.Done(t.Context(), bl.targets[1], http.StatusBadGateway, nil)
+
+	// THEN all targets are considered
+	used := map[string]bool{}
+	for range 4 {
+		used[bl.Next(t.Context(), "").Host()] = true
+	}
+
+	assert.Len(t, used, 2)
+
+	// WHEN the ejection time is over
+	tgtA.ejectedUntil.Store(time.Now().Add(-time.Second).UnixNano())
+
+	// THEN
+	assert.True(t, tgtA.available(time.Now()))
+}
+
+func TestBalancerDoneWithoutForwarding(t *testing.T) {
+	t.Parallel()
+
+	// GIVEN
+	bl, err := New("test", &config.Backend{
+		Targets: []config.Target{{Host: "a"}},
+		HealthCheck: &config.HealthCheck{
+			Passive: &config.PassiveHealthCheck{ConsecutiveFailures: 1},
+		},
+	})
+	require.NoError(t, err)
+
+	tgt := bl.Next(t.Context(), "")
+	require.Equal(t, int64(1), tgt.inflight.Load())
+
+	// WHEN
+	bl.Done(t.Context(), tgt, 0, nil)
+
+	// THEN
+	assert.Equal(t, int64(0), tgt.inflight.Load())
+	assert.True(t, tgt.available(time.Now()))
+}
+
+func TestBalancerNextWithExcludedTargets(t *testing.T) {
+	t.Parallel()
+
+	// GIVEN
+	bl, err := New("test", &config.Backend{
+		Targets: []config.Target{{Host: "a"}, {Host: "b"}},
+		HealthCheck: &config.HealthCheck{
+			Passive: &config.PassiveHealthCheck{ConsecutiveFailures: 1, EjectionTime: config.Duration(time.Hour)},
+		},
+	})
+	require.NoError(t, err)
+
+	tgtA, tgtB := bl.targets[0], bl.targets[1]
+
+	// WHEN & THEN excluded targets are not selected
+	for range 4 {
+		tgt := bl.Next(t.Context(), "", tgtA)
+		assert.Equal(t, "b", tgt.Host())
+
+		bl.Done(t.Context(), tgt, 0, nil)
+	}
+
+	// WHEN the only other target is not available
+	bl.Done(t.Context(), tgtB, http.StatusBadGateway, nil)
+
+	// THEN excluded targets are considered
+	for range 4 {
+		tgt := bl.Next(t.Context(), "", tgtA)
+		assert.Equal(t, "a", tgt.Host())
+
+		bl.Done(t.Context(), tgt, 0, nil)
+	}
+}
+
+func slicesIndex(targets []*Target, host string) int {
+	for idx, tgt := range targets {
+		if tgt.host == host {
+			return idx
+		}
+	}
+
+	return -1
+}
@@ -0,0 +1,160 @@
+// Copyright 2025 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package loadbalancer
+
+import (
+	"context"
+	"crypto/tls"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+type healthChecker struct {
+	tlsConfig          func() *tls.Config
+	scheme             string
+	path               string
+	interval           time.Duration
+	timeout            time.Duration
+	healthyThreshold   int
+	unhealthyThreshold int
+	logger             zerolog.Logger
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	mut       sync.Mutex
+	client    *http.Client
+	clientTLS *tls.Config
+}
+
+func (hc *healthChecker) start(targets []*Target) {
+	ctx, cancel := context.WithCancel(context.Background())
+	hc.cancel = cancel
+
+	for _, tgt := range targets {
+		hc.wg.Add(1)
+
+		go hc.watch(ctx, tgt)
+	}
+}
+
+func (hc *healthChecker) stop() {
+	hc.cancel()
+	hc.wg.Wait()
+
+	hc.mut.Lock()
+	defer hc.mut.Unlock()
+
+	if hc.client != nil {
+		hc.client.CloseIdleConnections()
+		hc.client = nil
+	}
+}
+
+// httpClient returns the client used for the health checks. It is recreated if the TLS
+// configuration has changed since its creation, e.g. due to a renewed key or trust store.
+func (hc *healthChecker) httpClient() *http.Client {
+	cfg := hc.tlsConfig()
+
+	hc.mut.Lock()
+	defer hc.mut.Unlock()
+
+	if hc.client != nil && hc.clientTLS == cfg {
+		return hc.client
+	}
+
+	if hc.client != nil {
+		hc.client.CloseIdleConnections()
+	}
+
+	hc.client = &http.Client{
+		Transport: &http.Transport{
+			Proxy:           http.ProxyFromEnvironment,
+			TLSClientConfig: cfg,
+		},
+		CheckRedirect: func(_ *http.Request, _ []*http.Request) error { return http.ErrUseLastResponse },
+	}
+	hc.clientTLS = cfg
+
+	return hc.client
+}
+
+func (hc *healthChecker) watch(ctx context.Context, tgt *Target) {
+	defer hc.wg.Done()
+
+	ticker := time.NewTicker(hc.interval)
+	defer ticker.Stop()
+
+	for {
+		hc.update(tgt, hc.check(ctx, tgt))
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (hc *healthChecker) check(ctx context.Context, tgt *Target) bool {
+	ctx, cancel := context.WithTimeout(ctx, hc.timeout)
+	defer cancel()
+
+	checkURL := &url.URL{Scheme: hc.scheme, Host: tgt.host, Path: hc.path}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, checkURL.String(), nil)
+	if err != nil {
+		return false
+	}
+
+	resp, err := hc.httpClient().Do(req)
+	if err != nil {
+		hc.logger.Debug().Err(err).Str("_target", tgt.host).Msg("Health check failed")
+
+		return false
+	}
+
+	defer resp.Body.Close()
+
+	return resp.StatusCode >= http.StatusOK && resp.StatusCode < http.StatusMultipleChoices
+}
+
+func (hc *healthChecker) update(tgt *Target, success bool) {
+	if success {
+		tgt.checkFailures = 0
+		tgt.checkSuccesses++
+
+		if !tgt.healthy.Load() && tgt.checkSuccesses >= hc.healthyThreshold {
+			hc.logger.Info().Str("_target", tgt.host).Msg("Upstream target became healthy")
+			tgt.healthy.Store(true)
+		}
+
+		return
+	}
+
+	tgt.checkSuccesses = 0
+	tgt.checkFailures++
+
+	if tgt.healthy.Load() && tgt.checkFailures >= hc.unhealthyThreshold {
+		hc.logger.Warn().Str("_target", tgt.host).Msg("Upstream target became unhealthy")
+		tgt.healthy.Store(false)
+	}
+}
@@ -0,0 +1,142 @@
+// Copyright 2025 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package loadbalancer
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dadrus/heimdall/internal/heimdall"
+	"github.com/dadrus/heimdall/internal/rules/config"
+)
+
+func TestBalancerActiveHealthCheck(t *testing.T) {
+	t.Parallel()
+
+	// GIVEN
+	var (
+		healthy atomic.Bool
+		path    atomic.Value
+	)
+
+	healthy.Store(true)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		path.Store(req.URL.Path)
+
+		if healthy.Load() {
+			rw.WriteHeader(http.StatusOK)
+		} else {
+			rw.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer srv.Close()
+
+	srvURL, err := url.Parse(srv.URL)
+	require.NoError(t, err)
+
+	bl, err := New("test", &config.Backend{
+		Targets: []config.Target{{Host: srvURL.Host}, {Host: "127.0.0.1:1"}},
+		HealthCheck: &config.HealthCheck{
+			Active: &config.ActiveHealthCheck{
+				Path:               "/health",
+				Scheme:             "http",
+				Interval:           config.Duration(10 * time.Millisecond),
+				Timeout:            config.Duration(100 * time.Millisecond),
+				HealthyThreshold:   1,
+				UnhealthyThreshold: 1,
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	// WHEN
+	require.NoError(t, bl.Start())
+	defer bl.Stop()
+
+	// THEN the target, which can not be reached, is marked as unhealthy
+	assert.Eventually(t, func() bool { return !bl.targets[1].healthy.Load() }, time.Second, 10*time.Millisecond)
+	assert.True(t, bl.targets[0].healthy.Load())
+	assert.Equal(t, "/health", path.Load())
+
+	for range 4 {
+		assert.Equal(t, srvURL.Host, bl.Next(t.Context(), "").Host())
+	}
+
+	// WHEN the target becomes unhealthy
+	healthy.Store(false)
+
+	// THEN
+	assert.Eventually(t, func() bool { return !bl.targets[0].healthy.Load() }, time.Second, 10*time.Millisecond)
+
+	// WHEN the target recovers
+	healthy.Store(true)
+
+	// THEN
+	assert.Eventually(t, func() bool { return bl.targets[0].healthy.Load() }, time.Second, 10*time.Millisecond)
+}
+
+func TestBalancerStartStop(t *testing.T) {
+	t.Parallel()
+
+	// GIVEN
+	bl, err := New("test", &config.Backend{
+		Targets:     []config.Target{{Host: "127.0.0.1:1"}},
+		URLRewriter: &config.URLRewriter{Scheme: "https"},
+		HealthCheck: &config.HealthCheck{
+			Active: &config.ActiveHealthCheck{Interval: config.Duration(time.Hour)},
+		},
+	})
+	require.NoError(t, err)
+
+	// WHEN
+	require.NoError(t, bl.Start())
+	require.NoError(t, bl.Start())
+
+	bl.Stop()
+	bl.Stop()
+
+	// THEN
+	assert.False(t, bl.started)
+	assert.Equal(t, "https", bl.checker.scheme)
+	assert.Equal(t, "/", bl.checker.path)
+	assert.Equal(t, defaultHealthCheckTimeout, bl.checker.timeout)
+}
+
+func TestBalancerActiveHealthCheckWithoutScheme(t *testing.T) {
+	t.Parallel()
+
+	// WHEN
+	_, err := New("test", &config.Backend{
+		Targets: []config.Target{{Host: "127.0.0.1:1"}},
+		HealthCheck: &config.HealthCheck{
+			Active: &config.ActiveHealthCheck{Interval: config.Duration(time.Hour)},
+		},
+	})
+
+	// THEN
+	require.Error(t, err)
+	require.ErrorIs(t, err, heimdall.ErrConfiguration)
+	require.ErrorContains(t, err, "requires a scheme")
+}
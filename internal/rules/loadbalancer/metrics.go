@@ -0,0 +1,115 @@
+// Copyright 2025 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package loadbalancer
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+
+	"github.com/dadrus/heimdall/version"
+)
+
+const (
+	ruleIDAttrKey  = attribute.Key("rule_id")
+	targetAttrKey  = attribute.Key("target")
+	outcomeAttrKey = attribute.Key("outcome")
+)
+
+type metrics struct {
+	meter          metric.Meter
+	requests       metric.Int64Counter
+	activeRequests metric.Int64UpDownCounter
+	ejections      metric.Int64Counter
+	available      metric.Int64ObservableGauge
+}
+
+func newMetrics() (*metrics, error) {
+	meter := otel.GetMeterProvider().Meter(
+		"github.com/dadrus/heimdall/internal/rules/loadbalancer",
+		metric.WithInstrumentationVersion(version.Version),
+	)
+
+	requests, err := meter.Int64Counter(
+		"upstream.requests",
+		metric.WithDescription("Number of requests forwarded to an upstream target"),
+		metric.WithUnit("{request}"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	activeRequests, err := meter.Int64UpDownCounter(
+		"upstream.active_requests",
+		metric.WithDescription("Number of in-flight requests to an upstream target"),
+		metric.WithUnit("{request}"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	ejections, err := meter.Int64Counter(
+		"upstream.ejections",
+		metric.WithDescription("Number of times an upstream target has been ejected by the outlier detection"),
+		metric.WithUnit("{ejection}"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	available, err := meter.Int64ObservableGauge(
+		"upstream.available",
+		metric.WithDescription("Whether an upstream target is healthy and not ejected (1) or not (0)"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &metrics{
+		meter:          meter,
+		requests:       requests,
+		activeRequests: activeRequests,
+		ejections:      ejections,
+		available:      available,
+	}, nil
+}
+
+func (m *metrics) observeAvailability(ruleID string, targets []*Target) (metric.Registration, error) {
+	return m.meter.RegisterCallback(
+		func(_ context.Context, observer metric.Observer) error {
+			now := time.Now()
+
+			for _, tgt := range targets {
+				var value int64
+				if tgt.available(now) {
+					value = 1
+				}
+
+				observer.ObserveInt64(m.available, value, metric.WithAttributes(
+					ruleIDAttrKey.String(ruleID),
+					targetAttrKey.String(tgt.host),
+				))
+			}
+
+			return nil
+		},
+		m.available,
+	)
+}
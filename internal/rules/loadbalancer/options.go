@@ -0,0 +1,53 @@
+// Copyright 2025 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package loadbalancer
+
+import (
+	"crypto/tls"
+
+	"github.com/rs/zerolog"
+)
+
+type options struct {
+	tlsConfig func() *tls.Config
+	logger    zerolog.Logger
+}
+
+func newOptions() *options {
+	return &options{
+		tlsConfig: func() *tls.Config { return nil },
+		logger:    zerolog.Nop(),
+	}
+}
+
+type Option func(*options)
+
+// WithTLSConfig sets the function providing the current TLS configuration used by the
+// active health checks.
+func WithTLSConfig(cfg func() *tls.Config) Option {
+	return func(o *options) {
+		if cfg != nil {
+			o.tlsConfig = cfg
+		}
+	}
+}
+
+func WithLogger(logger zerolog.Logger) Option {
+	return func(o *options) {
+		o.logger = logger
+	}
+}
@@ -0,0 +1,126 @@
+// Copyright 2025 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package loadbalancer
+
+import (
+	"slices"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/cespare/xxhash/v2"
+)
+
+const virtualNodesPerWeight = 100
+
+type strategy interface {
+	next(candidates []*Target, key string) *Target
+}
+
+// roundRobin implements the smooth weighted round-robin algorithm, which distributes
+// requests proportionally to the weights of the targets without bursts.
+type roundRobin struct {
+	mut sync.Mutex
+}
+
+func (s *roundRobin) next(candidates []*Target, _ string) *Target {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+
+	var (
+		selected    *Target
+		totalWeight int
+	)
+
+	for _, tgt := range candidates {
+		tgt.currentWeight += tgt.weight
+		totalWeight += tgt.weight
+
+		if selected == nil || tgt.currentWeight > selected.currentWeight {
+			selected = tgt
+		}
+	}
+
+	selected.currentWeight -= totalWeight
+
+	return selected
+}
+
+// leastRequests selects the target with the least number of in-flight requests
+// relative to its weight.
+type leastRequests struct{}
+
+func (leastRequests) next(candidates []*Target, _ string) *Target {
+	selected := candidates[0]
+	selectedLoad := selected.inflight.Load()
+
+	for _, tgt := range candidates[1:] {
+		load := tgt.inflight.Load()
+		if load*int64(selected.weight) < selectedLoad*int64(tgt.weight) {
+			selected, selectedLoad = tgt, load
+		}
+	}
+
+	return selected
+}
+
+type ringEntry struct {
+	hash   uint64
+	target *Target
+}
+
+// consistentHash maps keys to targets by making use of a hash ring with virtual
+// nodes proportional to the weights of the targets. Requests without a key are
+// distributed using the fallback strategy.
+type consistentHash struct {
+	ring     []ringEntry
+	fallback strategy
+}
+
+func newConsistentHash(targets []*Target) *consistentHash {
+	var ring []ringEntry
+
+	for _, tgt := range targets {
+		for idx := range tgt.weight * virtualNodesPerWeight {
+			ring = append(ring, ringEntry{hash: hashOf(tgt.host + "#" + strconv.Itoa(idx)), target: tgt})
+		}
+	}
+
+	sort.Slice(ring, func(i, j int) bool { return ring[i].hash < ring[j].hash })
+
+	return &consistentHash{ring: ring, fallback: &roundRobin{}}
+}
+
+func (s *consistentHash) next(candidates []*Target, key string) *Target {
+	if len(key) == 0 {
+		return s.fallback.next(candidates, key)
+	}
+
+	hash := hashOf(key)
+	start := sort.Search(len(s.ring), func(i int) bool { return s.ring[i].hash >= hash })
+
+	for idx := range len(s.ring) {
+		entry := s.ring[(start+idx)%len(s.ring)]
+		if slices.Contains(candidates, entry.target) {
+			return entry.target
+		}
+	}
+
+	return candidates[0]
+}
+
+func hashOf(value string) uint64 { return xxhash.Sum64String(value) }
@@ -0,0 +1,63 @@
+// Copyright 2025 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package loadbalancer
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/dadrus/heimdall/internal/x"
+)
+
+const defaultWeight = 1
+
+// Target represents a single upstream host a Balancer distributes requests to.
+type Target struct {
+	host   string
+	weight int
+
+	inflight     atomic.Int64
+	healthy      atomic.Bool
+	failures     atomic.Int64
+	ejectedUntil atomic.Int64
+
+	// used by the smooth weighted round-robin strategy only. Guarded by its mutex
+	currentWeight int
+
+	// used by the active health checker only
+	checkSuccesses int
+	checkFailures  int
+}
+
+func newTarget(host string, weight *int) *Target {
+	tgt := &Target{host: host, weight: x.IfThenElseExec(weight != nil,
+		func() int { return *weight },
+		func() int { return defaultWeight })}
+	tgt.healthy.Store(true)
+
+	return tgt
+}
+
+func (t *Target) Host() string { return t.host }
+
+// drained reports whether the target has been configured with a weight of 0 and must
+// not receive any requests.
+func (t *Target) drained() bool { return t.weight == 0 }
+
+func (t *Target) available(now time.Time) bool {
+	return t.healthy.Load() && now.UnixNano() >= t.ejectedUntil.Load()
+}
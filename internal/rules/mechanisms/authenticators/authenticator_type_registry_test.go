@@ -31,7 +31,7 @@ func TestCreateAuthenticatorPrototype(t *testing.T) {
 	t.Parallel()
 
 	// there are seven authenticators implemented, which should have been registered
-	require.Len(t, authenticatorTypeFactories, 6)
+	require.Len(t, authenticatorTypeFactories, 7)
 
 	for _, tc := range []struct {
 		uc     string
@@ -23,4 +23,5 @@ const (
 	AuthenticatorOAuth2Introspection = "oauth2_introspection"
 	AuthenticatorJwt                 = "jwt"
 	AuthenticatorGeneric             = "generic"
+	AuthenticatorPASETO              = "paseto"
 )
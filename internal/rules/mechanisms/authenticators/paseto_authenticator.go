@@ -0,0 +1,461 @@
+// Copyright 2025 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package authenticators
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-jose/go-jose/v4"
+	"github.com/goccy/go-json"
+	"github.com/rs/zerolog"
+
+	"github.com/dadrus/heimdall/internal/app"
+	"github.com/dadrus/heimdall/internal/cache"
+	"github.com/dadrus/heimdall/internal/heimdall"
+	"github.com/dadrus/heimdall/internal/rules/endpoint"
+	"github.com/dadrus/heimdall/internal/rules/mechanisms/authenticators/extractors"
+	"github.com/dadrus/heimdall/internal/rules/mechanisms/oauth2"
+	"github.com/dadrus/heimdall/internal/rules/mechanisms/subject"
+	"github.com/dadrus/heimdall/internal/x"
+	"github.com/dadrus/heimdall/internal/x/errorchain"
+	"github.com/dadrus/heimdall/internal/x/paseto"
+	"github.com/dadrus/heimdall/internal/x/stringx"
+)
+
+const defaultPASETOAuthenticatorTTL = 10 * time.Minute
+
+// by intention. Used only during application bootstrap
+//
+//nolint:gochecknoinits
+func init() {
+	registerTypeFactory(
+		func(app app.Context, id string, typ string, conf map[string]any) (bool, Authenticator, error) {
+			if typ != AuthenticatorPASETO {
+				return false, nil, nil
+			}
+
+			auth, err := newPASETOAuthenticator(app, id, conf)
+
+			return true, auth, err
+		})
+}
+
+type pasetoAuthenticator struct {
+	id  string
+	app app.Context
+	ep  *endpoint.Endpoint
+	key *pasetoKey
+	a   oauth2.Expectation
+	ttl *time.Duration
+	sf  SubjectFactory
+	ads extractors.AuthDataExtractStrategy
+}
+
+func newPASETOAuthenticator(
+	app app.Context,
+	id string,
+	rawConfig map[string]any,
+) (*pasetoAuthenticator, error) {
+	logger := app.Logger()
+	logger.Info().Str("_id", id).Msg("Creating paseto authenticator")
+
+	type SymmetricKeyConfig struct {
+		Path string `mapstructure:"path" validate:"required"`
+	}
+
+	type Config struct {
+		JWKSEndpoint   *endpoint.Endpoint                  `mapstructure:"jwks_endpoint" validate:"required_without=SymmetricKey,excluded_with=SymmetricKey"` //nolint:lll,tagalign
+		SymmetricKey   *SymmetricKeyConfig                 `mapstructure:"symmetric_key" validate:"required_without=JWKSEndpoint"`                            //nolint:lll,tagalign
+		Assertions     oauth2.Expectation                  `mapstructure:"assertions"`
+		SubjectInfo    SubjectInfo                         `mapstructure:"subject"       validate:"-"`
+		AuthDataSource extractors.CompositeExtractStrategy `mapstructure:"token_source"`
+		CacheTTL       *time.Duration                      `mapstructure:"cache_ttl"`
+	}
+
+	var conf Config
+	if err := decodeConfig(app, rawConfig, &conf); err != nil {
+		return nil, errorchain.NewWithMessagef(heimdall.ErrConfiguration,
+			"failed decoding config for paseto authenticator '%s'", id).CausedBy(err)
+	}
+
+	if len(conf.Assertions.TrustedIssuers) == 0 {
+		return nil, errorchain.
+			NewWithMessage(heimdall.ErrConfiguration, "'issuers' is a required field")
+	}
+
+	if conf.Assertions.ScopesMatcher == nil {
+		conf.Assertions.ScopesMatcher = oauth2.NoopMatcher{}
+	}
+
+	if len(conf.SubjectInfo.IDFrom) == 0 {
+		conf.SubjectInfo.IDFrom = "sub"
+	}
+
+	auth := &pasetoAuthenticator{
+		id:  id,
+		app: app,
+		a:   conf.Assertions,
+		ttl: conf.CacheTTL,
+		sf:  &conf.SubjectInfo,
+		ads: x.IfThenElseExec(conf.AuthDataSource == nil,
+			func() extractors.CompositeExtractStrategy {
+				return extractors.CompositeExtractStrategy{
+					extractors.HeaderValueExtractStrategy{Name: "Authorization", Scheme: "Bearer"},
+				}
+			},
+			func() extractors.CompositeExtractStrategy { return conf.AuthDataSource },
+		),
+	}
+
+	if conf.JWKSEndpoint != nil {
+		if strings.HasPrefix(conf.JWKSEndpoint.URL, "http://") {
+			logger.Warn().Str("_id", id).
+				Msg("No TLS configured for the jwks endpoint used in paseto authenticator")
+		}
+
+		ep := conf.JWKSEndpoint
+
+		if ep.Headers == nil {
+			ep.Headers = make(map[string]string)
+		}
+
+		if _, ok := ep.Headers["Accept"]; !ok {
+			ep.Headers["Accept"] = "application/json"
+		}
+
+		if len(ep.Method) == 0 {
+			ep.Method = http.MethodGet
+		}
+
+		auth.ep = ep
+	} else {
+		key := &pasetoKey{path: conf.SymmetricKey.Path}
+		if err := key.load(); err != nil {
+			return nil, err
+		}
+
+		if err := app.Watcher().Add(key.path, key); err != nil {
+			return nil, errorchain.NewWithMessage(heimdall.ErrInternal,
+				"failed registering paseto key for updates").CausedBy(err)
+		}
+
+		auth.key = key
+	}
+
+	return auth, nil
+}
+
+func (a *pasetoAuthenticator) Execute(ctx heimdall.RequestContext) (*subject.Subject, error) {
+	logger := zerolog.Ctx(ctx.Context())
+	logger.Debug().Str("_id", a.id).Msg("Authenticating using PASETO authenticator")
+
+	token, err := a.ads.GetAuthData(ctx)
+	if err != nil {
+		return nil, errorchain.
+			NewWithMessage(heimdall.ErrAuthentication, "no PASETO token present").
+			WithErrorContext(a).
+			CausedBy(err)
+	}
+
+	payload, err := a.verifyToken(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	var claims pasetoClaims
+	if err = json.Unmarshal(payload, &claims); err != nil {
+		return nil, errorchain.
+			NewWithMessage(heimdall.ErrAuthentication, "failed to unmarshal PASETO claims").
+			WithErrorContext(a).
+			CausedBy(err)
+	}
+
+	if err = claims.Validate(a.a); err != nil {
+		return nil, errorchain.
+			NewWithMessage(heimdall.ErrAuthentication, "token does not satisfy assertion conditions").
+			WithErrorContext(a).
+			CausedBy(err)
+	}
+
+	sub, err := a.sf.CreateSubject(payload)
+	if err != nil {
+		return nil, errorchain.
+			NewWithMessage(heimdall.ErrInternal, "failed to extract subject information from PASETO token").
+			WithErrorContext(a).
+			CausedBy(err)
+	}
+
+	return sub, nil
+}
+
+func (a *pasetoAuthenticator) WithConfig(config map[string]any) (Authenticator, error) {
+	// this authenticator allows assertions and ttl to be redefined on the rule level
+	if len(config) == 0 {
+		return a, nil
+	}
+
+	type Config struct {
+		Assertions oauth2.Expectation `mapstructure:"assertions" validate:"-"`
+		CacheTTL   *time.Duration     `mapstructure:"cache_ttl"`
+	}
+
+	var conf Config
+	if err := decodeConfig(a.app, config, &conf); err != nil {
+		return nil, errorchain.NewWithMessagef(heimdall.ErrConfiguration,
+			"failed decoding config for paseto authenticator '%s'", a.id).CausedBy(err)
+	}
+
+	return &pasetoAuthenticator{
+		id:  a.id,
+		app: a.app,
+		ep:  a.ep,
+		key: a.key,
+		a:   conf.Assertions.Merge(a.a),
+		ttl: x.IfThenElse(conf.CacheTTL != nil, conf.CacheTTL, a.ttl),
+		sf:  a.sf,
+		ads: a.ads,
+	}, nil
+}
+
+func (a *pasetoAuthenticator) ID() string { return a.id }
+
+func (a *pasetoAuthenticator) IsInsecure() bool { return false }
+
+func (a *pasetoAuthenticator) verifyToken(ctx heimdall.RequestContext, token string) ([]byte, error) {
+	switch {
+	case a.key != nil && strings.HasPrefix(token, paseto.HeaderV4Local):
+		payload, _, err := paseto.Decrypt(a.key.get(), token, nil)
+		if err != nil {
+			return nil, errorchain.
+				NewWithMessage(heimdall.ErrAuthentication, "failed to decrypt PASETO token").
+				WithErrorContext(a).
+				CausedBy(err)
+		}
+
+		return payload, nil
+	case a.ep != nil && strings.HasPrefix(token, paseto.HeaderV4Public):
+		return a.verifyPublicToken(ctx, token)
+	default:
+		return nil, errorchain.
+			NewWithMessage(heimdall.ErrAuthentication, "unsupported PASETO token").
+			WithErrorContext(a).
+			CausedBy(heimdall.ErrArgument)
+	}
+}
+
+func (a *pasetoAuthenticator) verifyPublicToken(ctx heimdall.RequestContext, token string) ([]byte, error) {
+	footer, err := paseto.Footer(token)
+	if err != nil {
+		return nil, errorchain.
+			NewWithMessage(heimdall.ErrAuthentication, "failed to parse PASETO token").
+			WithErrorContext(a).
+			CausedBy(heimdall.ErrArgument).
+			CausedBy(err)
+	}
+
+	keys, err := a.getKeys(ctx, pasetoKeyID(footer))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, key := range keys {
+		if payload, _, err := paseto.Verify(key, token, nil); err == nil {
+			return payload, nil
+		}
+	}
+
+	return nil, errorchain.
+		NewWithMessage(heimdall.ErrAuthentication, "failed to verify PASETO token signature").
+		WithErrorContext(a)
+}
+
+// getKeys returns the ed25519 keys, which can be used to verify the token. If the key id is known,
+// the corresponding key is cached. Otherwise, all ed25519 keys from the JWKS are returned.
+func (a *pasetoAuthenticator) getKeys(ctx heimdall.RequestContext, keyID string) ([]ed25519.PublicKey, error) {
+	cch := cache.Ctx(ctx.Context())
+	logger := zerolog.Ctx(ctx.Context())
+	cacheKey := a.calculateCacheKey(keyID)
+
+	if len(keyID) != 0 && a.isCacheEnabled() {
+		if entry, err := cch.Get(ctx.Context(), cacheKey); err == nil {
+			logger.Debug().Msg("Reusing PASETO verification key from cache")
+
+			return []ed25519.PublicKey{entry}, nil
+		}
+	}
+
+	logger.Debug().Msg("Retrieving JWKS from configured endpoint")
+
+	rawData, err := a.ep.SendRequest(ctx.Context(), nil, nil)
+	if err != nil {
+		return nil, errorchain.NewWithMessage(heimdall.ErrCommunication,
+			"failed to retrieve keys from the JWKS endpoint").
+			WithErrorContext(a).
+			CausedBy(err)
+	}
+
+	var jwks jose.JSONWebKeySet
+	if err = json.Unmarshal(rawData, &jwks); err != nil {
+		return nil, errorchain.NewWithMessage(heimdall.ErrInternal, "failed to unmarshal received jwks").
+			WithErrorContext(a).
+			CausedBy(err)
+	}
+
+	candidates := x.IfThenElseExec(len(keyID) != 0,
+		func() []jose.JSONWebKey { return jwks.Key(keyID) },
+		func() []jose.JSONWebKey { return jwks.Keys })
+
+	var keys []ed25519.PublicKey
+
+	for _, jwk := range candidates {
+		if key, ok := jwk.Key.(ed25519.PublicKey); ok {
+			keys = append(keys, key)
+		}
+	}
+
+	if len(keys) == 0 || (len(keyID) != 0 && len(keys) != 1) {
+		return nil, errorchain.
+			NewWithMessagef(heimdall.ErrAuthentication,
+				"no (unique) ed25519 key found for the keyID='%s' referenced in the PASETO token", keyID).
+			WithErrorContext(a)
+	}
+
+	if len(keyID) != 0 && a.isCacheEnabled() {
+		if err = cch.Set(ctx.Context(), cacheKey, keys[0], a.getCacheTTL()); err != nil {
+			logger.Warn().Err(err).Msg("Failed to cache PASETO verification key")
+		}
+	}
+
+	return keys, nil
+}
+
+func (a *pasetoAuthenticator) isCacheEnabled() bool {
+	return a.ttl == nil || *a.ttl > 0
+}
+
+func (a *pasetoAuthenticator) getCacheTTL() time.Duration {
+	return x.IfThenElseExec(a.ttl != nil,
+		func() time.Duration { return *a.ttl },
+		func() time.Duration { return defaultPASETOAuthenticatorTTL })
+}
+
+func (a *pasetoAuthenticator) calculateCacheKey(keyID string) string {
+	digest := sha256.New()
+	digest.Write(stringx.ToBytes(paseto.HeaderV4Public))
+	digest.Write(a.ep.Hash())
+	digest.Write(stringx.ToBytes(keyID))
+
+	return hex.EncodeToString(digest.Sum(nil))
+}
+
+func pasetoKeyID(footer []byte) string {
+	var kid struct {
+		KeyID string `json:"kid"`
+	}
+
+	if len(footer) == 0 || json.Unmarshal(footer, &kid) != nil {
+		return ""
+	}
+
+	return kid.KeyID
+}
+
+// pasetoKey holds the symmetric key used to decrypt v4.local tokens and reloads it on changes.
+type pasetoKey struct {
+	path string
+
+	mut sync.RWMutex
+	key []byte
+}
+
+func (k *pasetoKey) OnChanged(logger zerolog.Logger) {
+	err := k.load()
+	if err != nil {
+		logger.Warn().Err(err).
+			Str("_file", k.path).
+			Msg("Symmetric key reload failed")
+	} else {
+		logger.Info().
+			Str("_file", k.path).
+			Msg("Symmetric key reloaded")
+	}
+}
+
+func (k *pasetoKey) load() error {
+	key, err := paseto.ReadKeyFile(k.path)
+	if err != nil {
+		return errorchain.NewWithMessage(heimdall.ErrConfiguration, "failed loading symmetric key").
+			CausedBy(err)
+	}
+
+	k.mut.Lock()
+	k.key = key
+	k.mut.Unlock()
+
+	return nil
+}
+
+func (k *pasetoKey) get() []byte {
+	k.mut.RLock()
+	defer k.mut.RUnlock()
+
+	return k.key
+}
+
+// pasetoClaims represents the registered claims of a PASETO token. Unlike JWTs, PASETO tokens
+// use ISO 8601 (RFC 3339) formatted strings for the time related claims.
+type pasetoClaims struct {
+	Issuer    string          `json:"iss,omitempty"`
+	Subject   string          `json:"sub,omitempty"`
+	Audience  oauth2.Audience `json:"aud,omitempty"`
+	Scp       oauth2.Scopes   `json:"scp,omitempty"`
+	Scope     oauth2.Scopes   `json:"scope,omitempty"`
+	Expiry    *time.Time      `json:"exp,omitempty"`
+	NotBefore *time.Time      `json:"nbf,omitempty"`
+	IssuedAt  *time.Time      `json:"iat,omitempty"`
+	ID        string          `json:"jti,omitempty"`
+}
+
+func (c pasetoClaims) Validate(exp oauth2.Expectation) error {
+	timeOf := func(t *time.Time) time.Time {
+		return x.IfThenElseExec(t != nil, func() time.Time { return *t }, func() time.Time { return time.Time{} })
+	}
+
+	if err := exp.AssertIssuer(c.Issuer); err != nil {
+		return err
+	}
+
+	if err := exp.AssertAudience(c.Audience); err != nil {
+		return err
+	}
+
+	if err := exp.AssertValidity(timeOf(c.NotBefore), timeOf(c.Expiry)); err != nil {
+		return err
+	}
+
+	if err := exp.AssertIssuanceTime(timeOf(c.IssuedAt)); err != nil {
+		return err
+	}
+
+	return exp.AssertScopes(x.IfThenElse(len(c.Scp) != 0, c.Scp, c.Scope))
+}
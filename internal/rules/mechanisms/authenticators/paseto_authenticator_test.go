@@ -0,0 +1,694 @@
+// Copyright 2025 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package authenticators
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-jose/go-jose/v4"
+	"github.com/goccy/go-json"
+	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dadrus/heimdall/internal/app"
+	"github.com/dadrus/heimdall/internal/cache"
+	"github.com/dadrus/heimdall/internal/cache/mocks"
+	"github.com/dadrus/heimdall/internal/config"
+	"github.com/dadrus/heimdall/internal/heimdall"
+	heimdallmocks "github.com/dadrus/heimdall/internal/heimdall/mocks"
+	"github.com/dadrus/heimdall/internal/rules/endpoint"
+	"github.com/dadrus/heimdall/internal/rules/mechanisms/authenticators/extractors"
+	mocks2 "github.com/dadrus/heimdall/internal/rules/mechanisms/authenticators/extractors/mocks"
+	"github.com/dadrus/heimdall/internal/rules/mechanisms/oauth2"
+	"github.com/dadrus/heimdall/internal/rules/mechanisms/subject"
+	"github.com/dadrus/heimdall/internal/validation"
+	mocks3 "github.com/dadrus/heimdall/internal/watcher/mocks"
+	"github.com/dadrus/heimdall/internal/x/paseto"
+	"github.com/dadrus/heimdall/internal/x/testsupport"
+)
+
+func createPASETOClaims(t *testing.T, iss string, exp time.Time) []byte {
+	t.Helper()
+
+	now := time.Now().UTC()
+
+	payload, err := json.Marshal(map[string]any{
+		"iss": iss,
+		"sub": "foo",
+		"aud": []string{"bar"},
+		"iat": now.Format(time.RFC3339),
+		"nbf": now.Format(time.RFC3339),
+		"exp": exp.Format(time.RFC3339),
+		"baz": "zab",
+	})
+	require.NoError(t, err)
+
+	return payload
+}
+
+func TestPASETOAuthenticatorCreate(t *testing.T) {
+	t.Parallel()
+
+	symKey := make([]byte, paseto.KeySize)
+	_, err := rand.Read(symKey)
+	require.NoError(t, err)
+
+	keyFile := filepath.Join(t.TempDir(), "paseto.key")
+	require.NoError(t, os.WriteFile(keyFile, []byte(hex.EncodeToString(symKey)), 0o600))
+
+	for uc, tc := range map[string]struct {
+		config              []byte
+		configureAppContext func(t *testing.T, ctx *app.ContextMock)
+		assert              func(t *testing.T, err error, auth *pasetoAuthenticator)
+	}{
+		"without config": {
+			assert: func(t *testing.T, err error, _ *pasetoAuthenticator) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				require.ErrorContains(t, err, "'jwks_endpoint' is a required field")
+				require.ErrorContains(t, err, "'symmetric_key' is a required field")
+			},
+		},
+		"with jwks endpoint and symmetric key": {
+			config: []byte(`
+jwks_endpoint:
+  url: https://foo.bar/jwks
+symmetric_key:
+  path: ` + keyFile + `
+assertions:
+  issuers: [ foo ]
+`),
+			assert: func(t *testing.T, err error, _ *pasetoAuthenticator) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				require.ErrorContains(t, err, "'jwks_endpoint' is an excluded field")
+			},
+		},
+		"without trusted issuers": {
+			config: []byte(`
+jwks_endpoint:
+  url: https://foo.bar/jwks
+`),
+			assert: func(t *testing.T, err error, _ *pasetoAuthenticator) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				require.ErrorContains(t, err, "'issuers' is a required field")
+			},
+		},
+		"with unsupported properties": {
+			config: []byte(`
+jwks_endpoint:
+  url: https://foo.bar/jwks
+assertions:
+  issuers: [ foo ]
+foo: bar
+`),
+			assert: func(t *testing.T, err error, _ *pasetoAuthenticator) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				require.ErrorContains(t, err, "invalid keys: foo")
+			},
+		},
+		"with jwks endpoint and defaults": {
+			config: []byte(`
+jwks_endpoint:
+  url: https://foo.bar/jwks
+assertions:
+  issuers: [ foo ]
+`),
+			assert: func(t *testing.T, err error, auth *pasetoAuthenticator) {
+				t.Helper()
+
+				require.NoError(t, err)
+				require.NotNil(t, auth)
+
+				assert.Equal(t, "auth1", auth.ID())
+				assert.False(t, auth.IsInsecure())
+				require.NotNil(t, auth.ep)
+				assert.Equal(t, http.MethodGet, auth.ep.Method)
+				assert.Equal(t, "application/json", auth.ep.Headers["Accept"])
+				assert.Nil(t, auth.key)
+				assert.Equal(t, []string{"foo"}, auth.a.TrustedIssuers)
+				assert.Equal(t, oauth2.NoopMatcher{}, auth.a.ScopesMatcher)
+				assert.Nil(t, auth.ttl)
+				assert.Equal(t, &SubjectInfo{IDFrom: "sub"}, auth.sf)
+				assert.Equal(t, extractors.CompositeExtractStrategy{
+					extractors.HeaderValueExtractStrategy{Name: "Authorization", Scheme: "Bearer"},
+				}, auth.ads)
+			},
+		},
+		"with symmetric key and custom settings": {
+			config: []byte(`
+symmetric_key:
+  path: ` + keyFile + `
+assertions:
+  issuers: [ foo ]
+  audience: [ bar ]
+subject:
+  id: identity.id
+token_source:
+  - header: X-Token
+cache_ttl: 5m
+`),
+			configureAppContext: func(t *testing.T, ctx *app.ContextMock) {
+				t.Helper()
+
+				wm := mocks3.NewWatcherMock(t)
+				wm.EXPECT().Add(keyFile, mock.Anything).Return(nil)
+
+				ctx.EXPECT().Watcher().Return(wm)
+			},
+			assert: func(t *testing.T, err error, auth *pasetoAuthenticator) {
+				t.Helper()
+
+				require.NoError(t, err)
+				require.NotNil(t, auth)
+
+				assert.Nil(t, auth.ep)
+				require.NotNil(t, auth.key)
+				assert.Equal(t, symKey, auth.key.get())
+				assert.Equal(t, []string{"bar"}, auth.a.Audiences)
+				assert.Equal(t, 5*time.Minute, *auth.ttl)
+				assert.Equal(t, &SubjectInfo{IDFrom: "identity.id"}, auth.sf)
+				assert.Equal(t, extractors.CompositeExtractStrategy{
+					&extractors.HeaderValueExtractStrategy{Name: "X-Token"},
+				}, auth.ads)
+			},
+		},
+		"with not existing symmetric key file": {
+			config: []byte(`
+symmetric_key:
+  path: /does/not/exist.key
+assertions:
+  issuers: [ foo ]
+`),
+			assert: func(t *testing.T, err error, _ *pasetoAuthenticator) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				require.ErrorContains(t, err, "failed loading symmetric key")
+			},
+		},
+	} {
+		t.Run(uc, func(t *testing.T) {
+			// GIVEN
+			conf, err := testsupport.DecodeTestConfig(tc.config)
+			require.NoError(t, err)
+
+			es := config.EnforcementSettings{}
+			validator, err := validation.NewValidator(
+				validation.WithTagValidator(es),
+				validation.WithErrorTranslator(es),
+			)
+			require.NoError(t, err)
+
+			appCtx := app.NewContextMock(t)
+			appCtx.EXPECT().Validator().Maybe().Return(validator)
+			appCtx.EXPECT().Logger().Return(log.Logger)
+
+			if tc.configureAppContext != nil {
+				tc.configureAppContext(t, appCtx)
+			}
+
+			// WHEN
+			auth, err := newPASETOAuthenticator(appCtx, "auth1", conf)
+
+			// THEN
+			tc.assert(t, err, auth)
+		})
+	}
+}
+
+func TestPASETOAuthenticatorWithConfig(t *testing.T) {
+	t.Parallel()
+
+	protoConf, err := testsupport.DecodeTestConfig([]byte(`
+jwks_endpoint:
+  url: https://foo.bar/jwks
+assertions:
+  issuers: [ foo ]
+  audience: [ bar ]
+cache_ttl: 1m
+`))
+	require.NoError(t, err)
+
+	for uc, tc := range map[string]struct {
+		config []byte
+		assert func(t *testing.T, err error, prototype, configured *pasetoAuthenticator)
+	}{
+		"without config": {
+			assert: func(t *testing.T, err error, prototype, configured *pasetoAuthenticator) {
+				t.Helper()
+
+				require.NoError(t, err)
+				assert.Equal(t, prototype, configured)
+			},
+		},
+		"with not overridable property": {
+			config: []byte(`token_source: [ { header: X-Foo } ]`),
+			assert: func(t *testing.T, err error, _, _ *pasetoAuthenticator) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				require.ErrorContains(t, err, "invalid keys: token_source")
+			},
+		},
+		"with overridden assertions and cache ttl": {
+			config: []byte(`
+assertions:
+  audience: [ baz ]
+cache_ttl: 0s
+`),
+			assert: func(t *testing.T, err error, prototype, configured *pasetoAuthenticator) {
+				t.Helper()
+
+				require.NoError(t, err)
+				require.NotNil(t, configured)
+				assert.NotEqual(t, prototype, configured)
+
+				assert.Equal(t, prototype.ID(), configured.ID())
+				assert.Equal(t, prototype.ep, configured.ep)
+				assert.Equal(t, prototype.sf, configured.sf)
+				assert.Equal(t, prototype.ads, configured.ads)
+				assert.Equal(t, []string{"foo"}, configured.a.TrustedIssuers)
+				assert.Equal(t, []string{"baz"}, configured.a.Audiences)
+				assert.Equal(t, time.Minute, *prototype.ttl)
+				assert.Equal(t, time.Duration(0), *configured.ttl)
+				assert.False(t, configured.isCacheEnabled())
+			},
+		},
+	} {
+		t.Run(uc, func(t *testing.T) {
+			conf, err := testsupport.DecodeTestConfig(tc.config)
+			require.NoError(t, err)
+
+			es := config.EnforcementSettings{}
+			validator, err := validation.NewValidator(
+				validation.WithTagValidator(es),
+				validation.WithErrorTranslator(es),
+			)
+			require.NoError(t, err)
+
+			appCtx := app.NewContextMock(t)
+			appCtx.EXPECT().Validator().Maybe().Return(validator)
+			appCtx.EXPECT().Logger().Return(log.Logger)
+
+			prototype, err := newPASETOAuthenticator(appCtx, "auth1", protoConf)
+			require.NoError(t, err)
+
+			// WHEN
+			auth, err := prototype.WithConfig(conf)
+
+			// THEN
+			var (
+				ok         bool
+				configured *pasetoAuthenticator
+			)
+
+			if err == nil {
+				configured, ok = auth.(*pasetoAuthenticator)
+				require.True(t, ok)
+			}
+
+			tc.assert(t, err, prototype, configured)
+		})
+	}
+}
+
+func TestPASETOAuthenticatorExecute(t *testing.T) {
+	t.Parallel()
+
+	var (
+		endpointCalled bool
+		responseCode   int
+		jwks           []byte
+	)
+
+	pubKey, privKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	_, otherPrivKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	symKey := make([]byte, paseto.KeySize)
+	_, err = rand.Read(symKey)
+	require.NoError(t, err)
+
+	jwksWithKey, err := json.Marshal(jose.JSONWebKeySet{Keys: []jose.JSONWebKey{
+		{KeyID: "key", Key: pubKey, Algorithm: string(jose.EdDSA), Use: "sig"},
+	}})
+	require.NoError(t, err)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		endpointCalled = true
+
+		if responseCode != http.StatusOK {
+			w.WriteHeader(responseCode)
+
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, err := w.Write(jwks)
+		assert.NoError(t, err)
+	}))
+	defer srv.Close()
+
+	validClaims := createPASETOClaims(t, "foo", time.Now().Add(time.Minute))
+	expiredClaims := createPASETOClaims(t, "foo", time.Now().Add(-time.Minute))
+	untrustedClaims := createPASETOClaims(t, "baz", time.Now().Add(time.Minute))
+
+	sign := func(t *testing.T, key ed25519.PrivateKey, payload, footer []byte) string {
+		t.Helper()
+
+		token, err := paseto.Sign(key, payload, footer, nil)
+		require.NoError(t, err)
+
+		return token
+	}
+
+	encrypt := func(t *testing.T, key, payload []byte) string {
+		t.Helper()
+
+		token, err := paseto.Encrypt(key, payload, nil, nil)
+		require.NoError(t, err)
+
+		return token
+	}
+
+	publicAuth := func(ttl *time.Duration) *pasetoAuthenticator {
+		return &pasetoAuthenticator{
+			id:  "auth",
+			ep:  &endpoint.Endpoint{URL: srv.URL, Method: http.MethodGet},
+			a:   oauth2.Expectation{TrustedIssuers: []string{"foo"}, ScopesMatcher: oauth2.NoopMatcher{}},
+			ttl: ttl,
+			sf:  &SubjectInfo{IDFrom: "sub"},
+		}
+	}
+
+	localAuth := &pasetoAuthenticator{
+		id:  "auth",
+		key: &pasetoKey{key: symKey},
+		a:   oauth2.Expectation{TrustedIssuers: []string{"foo"}, ScopesMatcher: oauth2.NoopMatcher{}},
+		sf:  &SubjectInfo{IDFrom: "sub"},
+	}
+
+	disabledTTL := time.Duration(0)
+
+	for uc, tc := range map[string]struct {
+		authenticator  *pasetoAuthenticator
+		jwks           []byte
+		responseCode   int
+		configureMocks func(t *testing.T, ctx *heimdallmocks.RequestContextMock, cch *mocks.CacheMock,
+			ads *mocks2.AuthDataExtractStrategyMock)
+		assert func(t *testing.T, err error, sub *subject.Subject)
+	}{
+		"without token": {
+			authenticator: localAuth,
+			configureMocks: func(t *testing.T, ctx *heimdallmocks.RequestContextMock, _ *mocks.CacheMock,
+				ads *mocks2.AuthDataExtractStrategyMock,
+			) {
+				t.Helper()
+
+				ads.EXPECT().GetAuthData(ctx).Return("", errors.New("no token"))
+			},
+			assert: func(t *testing.T, err error, _ *subject.Subject) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrAuthentication)
+				require.ErrorContains(t, err, "no PASETO token present")
+				assert.False(t, endpointCalled)
+			},
+		},
+		"with public token, but symmetric key configured": {
+			authenticator: localAuth,
+			configureMocks: func(t *testing.T, ctx *heimdallmocks.RequestContextMock, _ *mocks.CacheMock,
+				ads *mocks2.AuthDataExtractStrategyMock,
+			) {
+				t.Helper()
+
+				ads.EXPECT().GetAuthData(ctx).Return(sign(t, privKey, validClaims, nil), nil)
+			},
+			assert: func(t *testing.T, err error, _ *subject.Subject) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrAuthentication)
+				require.ErrorIs(t, err, heimdall.ErrArgument)
+				require.ErrorContains(t, err, "unsupported PASETO token")
+			},
+		},
+		"with local token encrypted with another key": {
+			authenticator: localAuth,
+			configureMocks: func(t *testing.T, ctx *heimdallmocks.RequestContextMock, _ *mocks.CacheMock,
+				ads *mocks2.AuthDataExtractStrategyMock,
+			) {
+				t.Helper()
+
+				otherKey := make([]byte, paseto.KeySize)
+				ads.EXPECT().GetAuthData(ctx).Return(encrypt(t, otherKey, validClaims), nil)
+			},
+			assert: func(t *testing.T, err error, _ *subject.Subject) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrAuthentication)
+				require.ErrorContains(t, err, "failed to decrypt PASETO token")
+			},
+		},
+		"with expired local token": {
+			authenticator: localAuth,
+			configureMocks: func(t *testing.T, ctx *heimdallmocks.RequestContextMock, _ *mocks.CacheMock,
+				ads *mocks2.AuthDataExtractStrategyMock,
+			) {
+				t.Helper()
+
+				ads.EXPECT().GetAuthData(ctx).Return(encrypt(t, symKey, expiredClaims), nil)
+			},
+			assert: func(t *testing.T, err error, _ *subject.Subject) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrAuthentication)
+				require.ErrorContains(t, err, "does not satisfy assertion conditions")
+				require.ErrorContains(t, err, "expired")
+			},
+		},
+		"with local token from untrusted issuer": {
+			authenticator: localAuth,
+			configureMocks: func(t *testing.T, ctx *heimdallmocks.RequestContextMock, _ *mocks.CacheMock,
+				ads *mocks2.AuthDataExtractStrategyMock,
+			) {
+				t.Helper()
+
+				ads.EXPECT().GetAuthData(ctx).Return(encrypt(t, symKey, untrustedClaims), nil)
+			},
+			assert: func(t *testing.T, err error, _ *subject.Subject) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrAuthentication)
+				require.ErrorContains(t, err, "issuer baz is not trusted")
+			},
+		},
+		"with valid local token": {
+			authenticator: localAuth,
+			configureMocks: func(t *testing.T, ctx *heimdallmocks.RequestContextMock, _ *mocks.CacheMock,
+				ads *mocks2.AuthDataExtractStrategyMock,
+			) {
+				t.Helper()
+
+				ads.EXPECT().GetAuthData(ctx).Return(encrypt(t, symKey, validClaims), nil)
+			},
+			assert: func(t *testing.T, err error, sub *subject.Subject) {
+				t.Helper()
+
+				require.NoError(t, err)
+				require.NotNil(t, sub)
+				assert.Equal(t, "foo", sub.ID)
+				assert.Equal(t, "zab", sub.Attributes["baz"])
+				assert.False(t, endpointCalled)
+			},
+		},
+		"with public token and failing jwks endpoint": {
+			authenticator: publicAuth(nil),
+			responseCode:  http.StatusInternalServerError,
+			configureMocks: func(t *testing.T, ctx *heimdallmocks.RequestContextMock, cch *mocks.CacheMock,
+				ads *mocks2.AuthDataExtractStrategyMock,
+			) {
+				t.Helper()
+
+				ads.EXPECT().GetAuthData(ctx).Return(sign(t, privKey, validClaims, []byte(`{"kid":"key"}`)), nil)
+				cch.EXPECT().Get(mock.Anything, mock.Anything).Return(nil, errors.New("no cache entry"))
+			},
+			assert: func(t *testing.T, err error, _ *subject.Subject) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrCommunication)
+				require.ErrorContains(t, err, "failed to retrieve keys")
+				assert.True(t, endpointCalled)
+			},
+		},
+		"with public token referencing unknown key": {
+			authenticator: publicAuth(nil),
+			jwks:          jwksWithKey,
+			responseCode:  http.StatusOK,
+			configureMocks: func(t *testing.T, ctx *heimdallmocks.RequestContextMock, cch *mocks.CacheMock,
+				ads *mocks2.AuthDataExtractStrategyMock,
+			) {
+				t.Helper()
+
+				ads.EXPECT().GetAuthData(ctx).Return(sign(t, privKey, validClaims, []byte(`{"kid":"foo"}`)), nil)
+				cch.EXPECT().Get(mock.Anything, mock.Anything).Return(nil, errors.New("no cache entry"))
+			},
+			assert: func(t *testing.T, err error, _ *subject.Subject) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrAuthentication)
+				require.ErrorContains(t, err, "no (unique) ed25519 key found for the keyID='foo'")
+			},
+		},
+		"with public token signed by another key": {
+			authenticator: publicAuth(&disabledTTL),
+			jwks:          jwksWithKey,
+			responseCode:  http.StatusOK,
+			configureMocks: func(t *testing.T, ctx *heimdallmocks.RequestContextMock, _ *mocks.CacheMock,
+				ads *mocks2.AuthDataExtractStrategyMock,
+			) {
+				t.Helper()
+
+				ads.EXPECT().GetAuthData(ctx).Return(sign(t, otherPrivKey, validClaims, []byte(`{"kid":"key"}`)), nil)
+			},
+			assert: func(t *testing.T, err error, _ *subject.Subject) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrAuthentication)
+				require.ErrorContains(t, err, "failed to verify PASETO token signature")
+			},
+		},
+		"with valid public token and key id": {
+			authenticator: publicAuth(nil),
+			jwks:          jwksWithKey,
+			responseCode:  http.StatusOK,
+			configureMocks: func(t *testing.T, ctx *heimdallmocks.RequestContextMock, cch *mocks.CacheMock,
+				ads *mocks2.AuthDataExtractStrategyMock,
+			) {
+				t.Helper()
+
+				ads.EXPECT().GetAuthData(ctx).Return(sign(t, privKey, validClaims, []byte(`{"kid":"key"}`)), nil)
+				cch.EXPECT().Get(mock.Anything, mock.Anything).Return(nil, errors.New("no cache entry"))
+				cch.EXPECT().Set(mock.Anything, mock.Anything, []byte(pubKey), defaultPASETOAuthenticatorTTL).
+					Return(nil)
+			},
+			assert: func(t *testing.T, err error, sub *subject.Subject) {
+				t.Helper()
+
+				require.NoError(t, err)
+				require.NotNil(t, sub)
+				assert.Equal(t, "foo", sub.ID)
+				assert.True(t, endpointCalled)
+			},
+		},
+		"with valid public token and key from cache": {
+			authenticator: publicAuth(nil),
+			configureMocks: func(t *testing.T, ctx *heimdallmocks.RequestContextMock, cch *mocks.CacheMock,
+				ads *mocks2.AuthDataExtractStrategyMock,
+			) {
+				t.Helper()
+
+				ads.EXPECT().GetAuthData(ctx).Return(sign(t, privKey, validClaims, []byte(`{"kid":"key"}`)), nil)
+				cch.EXPECT().Get(mock.Anything, mock.Anything).Return(pubKey, nil)
+			},
+			assert: func(t *testing.T, err error, sub *subject.Subject) {
+				t.Helper()
+
+				require.NoError(t, err)
+				require.NotNil(t, sub)
+				assert.Equal(t, "foo", sub.ID)
+				assert.False(t, endpointCalled)
+			},
+		},
+		"with valid public token without key id": {
+			authenticator: publicAuth(nil),
+			jwks:          jwksWithKey,
+			responseCode:  http.StatusOK,
+			configureMocks: func(t *testing.T, ctx *heimdallmocks.RequestContextMock, _ *mocks.CacheMock,
+				ads *mocks2.AuthDataExtractStrategyMock,
+			) {
+				t.Helper()
+
+				ads.EXPECT().GetAuthData(ctx).Return(sign(t, privKey, validClaims, nil), nil)
+			},
+			assert: func(t *testing.T, err error, sub *subject.Subject) {
+				t.Helper()
+
+				require.NoError(t, err)
+				require.NotNil(t, sub)
+				assert.Equal(t, "foo", sub.ID)
+				assert.True(t, endpointCalled)
+			},
+		},
+	} {
+		t.Run(uc, func(t *testing.T) {
+			// GIVEN
+			endpointCalled = false
+			jwks = tc.jwks
+			responseCode = tc.responseCode
+
+			cch := mocks.NewCacheMock(t)
+			ads := mocks2.NewAuthDataExtractStrategyMock(t)
+			ctx := heimdallmocks.NewRequestContextMock(t)
+			ctx.EXPECT().Context().Return(cache.WithContext(t.Context(), cch))
+
+			tc.configureMocks(t, ctx, cch, ads)
+
+			auth := *tc.authenticator
+			auth.ads = ads
+
+			// WHEN
+			sub, err := auth.Execute(ctx)
+
+			// THEN
+			tc.assert(t, err, sub)
+		})
+	}
+}
@@ -63,7 +63,7 @@ func newCELAuthorizer(app app.Context, id string, rawConfig map[string]any) (*ce
 			"failed decoding config for cel authorizer '%s'", id).CausedBy(err)
 	}
 
-	env, err := cel.NewEnv(cellib.Library())
+	env, err := cel.NewEnv(cellib.Library(app.GeoIP()))
 	if err != nil {
 		return nil, errorchain.NewWithMessage(heimdall.ErrInternal,
 			"failed creating CEL environment").CausedBy(err)
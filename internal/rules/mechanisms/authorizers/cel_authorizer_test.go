@@ -148,6 +148,8 @@ expressions:
 			require.NoError(t, err)
 
 			appCtx := app.NewContextMock(t)
+
+			appCtx.EXPECT().GeoIP().Maybe().Return(nil)
 			appCtx.EXPECT().Validator().Maybe().Return(validator)
 			appCtx.EXPECT().Logger().Return(log.Logger)
 
@@ -233,6 +235,8 @@ expressions:
 			require.NoError(t, err)
 
 			appCtx := app.NewContextMock(t)
+
+			appCtx.EXPECT().GeoIP().Maybe().Return(nil)
 			appCtx.EXPECT().Validator().Maybe().Return(validator)
 			appCtx.EXPECT().Logger().Return(log.Logger)
 
@@ -366,6 +370,8 @@ expressions:
 			require.NoError(t, err)
 
 			appCtx := app.NewContextMock(t)
+
+			appCtx.EXPECT().GeoIP().Maybe().Return(nil)
 			appCtx.EXPECT().Validator().Maybe().Return(validator)
 			appCtx.EXPECT().Logger().Return(log.Logger)
 
@@ -17,9 +17,13 @@
 package authorizers
 
 const (
-	AuthorizerAllow  = "allow"
-	AuthorizerDeny   = "deny"
-	AuthorizerLocal  = "local"
-	AuthorizerCEL    = "cel"
-	AuthorizerRemote = "remote"
+	AuthorizerAllow      = "allow"
+	AuthorizerDeny       = "deny"
+	AuthorizerLocal      = "local"
+	AuthorizerCEL        = "cel"
+	AuthorizerRemote     = "remote"
+	AuthorizerJSONSchema = "json_schema"
+	AuthorizerOpenAPI    = "openapi"
+	AuthorizerGraphQL    = "graphql"
+	AuthorizerGRPC       = "grpc"
 )
@@ -0,0 +1,332 @@
+// Copyright 2025 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package authorizers
+
+import (
+	"net/http"
+
+	"github.com/google/cel-go/cel"
+	"github.com/rs/zerolog"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/parser"
+
+	"github.com/dadrus/heimdall/internal/app"
+	"github.com/dadrus/heimdall/internal/heimdall"
+	"github.com/dadrus/heimdall/internal/rules/mechanisms/cellib"
+	"github.com/dadrus/heimdall/internal/rules/mechanisms/subject"
+	"github.com/dadrus/heimdall/internal/x/errorchain"
+)
+
+// by intention. Used only during application bootstrap
+//
+//nolint:gochecknoinits
+func init() {
+	registerTypeFactory(
+		func(app app.Context, id string, typ string, conf map[string]any) (bool, Authorizer, error) {
+			if typ != AuthorizerGraphQL {
+				return false, nil, nil
+			}
+
+			auth, err := newGraphQLAuthorizer(app, id, conf)
+
+			return true, auth, err
+		})
+}
+
+type FieldPermission struct {
+	Path        string       `mapstructure:"path"        validate:"required"`
+	Expressions []Expression `mapstructure:"expressions" validate:"required,gt=0,dive"`
+}
+
+type compiledFieldPermission struct {
+	path        string
+	expressions compiledExpressions
+}
+
+type graphQLAuthorizer struct {
+	id            string
+	app           app.Context
+	maxDepth      int
+	maxComplexity int
+	expressions   compiledExpressions
+	fields        []compiledFieldPermission
+}
+
+type graphQLAuthorizerConfig struct {
+	MaxDepth      int               `mapstructure:"max_depth"      validate:"gte=0"`
+	MaxComplexity int               `mapstructure:"max_complexity" validate:"gte=0"`
+	Expressions   []Expression      `mapstructure:"expressions"    validate:"dive"`
+	Fields        []FieldPermission `mapstructure:"fields"         validate:"dive"`
+}
+
+func newGraphQLAuthorizer(app app.Context, id string, rawConfig map[string]any) (*graphQLAuthorizer, error) {
+	logger := app.Logger()
+	logger.Info().Str("_id", id).Msg("Creating graphql authorizer")
+
+	var conf graphQLAuthorizerConfig
+	if err := decodeConfig(app, rawConfig, &conf); err != nil {
+		return nil, errorchain.NewWithMessagef(heimdall.ErrConfiguration,
+			"failed decoding config for graphql authorizer '%s'", id).CausedBy(err)
+	}
+
+	auth := &graphQLAuthorizer{
+		id:            id,
+		app:           app,
+		maxDepth:      conf.MaxDepth,
+		maxComplexity: conf.MaxComplexity,
+	}
+
+	if err := auth.compile(conf); err != nil {
+		return nil, err
+	}
+
+	return auth, nil
+}
+
+func (a *graphQLAuthorizer) compile(conf graphQLAuthorizerConfig) error {
+	env, err := cel.NewEnv(cellib.Library(a.app.GeoIP()), cel.Variable("GraphQL", cel.MapType(cel.StringType, cel.DynType)))
+	if err != nil {
+		return errorchain.NewWithMessage(heimdall.ErrInternal, "failed creating CEL environment").CausedBy(err)
+	}
+
+	if len(conf.Expressions) != 0 {
+		if a.expressions, err = compileExpressions(conf.Expressions, env); err != nil {
+			return err
+		}
+	}
+
+	if len(conf.Fields) != 0 {
+		a.fields = make([]compiledFieldPermission, len(conf.Fields))
+
+		for idx, field := range conf.Fields {
+			expressions, err := compileExpressions(field.Expressions, env)
+			if err != nil {
+				return errorchain.NewWithMessagef(heimdall.ErrConfiguration,
+					"failed to compile expressions for field '%s'", field.Path).CausedBy(err)
+			}
+
+			a.fields[idx] = compiledFieldPermission{path: field.Path, expressions: expressions}
+		}
+	}
+
+	return nil
+}
+
+func (a *graphQLAuthorizer) Execute(ctx heimdall.RequestContext, sub *subject.Subject) error {
+	logger := zerolog.Ctx(ctx.Context())
+	logger.Debug().Str("_id", a.id).Msg("Authorizing using graphql authorizer")
+
+	req := ctx.Request()
+
+	op, err := a.parseOperation(req)
+	if err != nil {
+		return err
+	}
+
+	if a.maxDepth != 0 && op.depth > a.maxDepth {
+		return errorchain.NewWithMessagef(heimdall.ErrArgument,
+			"depth of the GraphQL operation (%d) exceeds the allowed maximum of %d", op.depth, a.maxDepth).
+			WithErrorContext(a)
+	}
+
+	if a.maxComplexity != 0 && op.complexity > a.maxComplexity {
+		return errorchain.NewWithMessagef(heimdall.ErrArgument,
+			"complexity of the GraphQL operation (%d) exceeds the allowed maximum of %d",
+			op.complexity, a.maxComplexity).
+			WithErrorContext(a)
+	}
+
+	obj := map[string]any{
+		"Subject": sub,
+		"Request": req,
+		"Outputs": ctx.Outputs(),
+		"GraphQL": op.asMap(),
+	}
+
+	if err = a.expressions.eval(obj, a); err != nil {
+		return err
+	}
+
+	for _, field := range a.fields {
+		if _, selected := op.paths[field.path]; !selected {
+			continue
+		}
+
+		if err = field.expressions.eval(obj, a); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (a *graphQLAuthorizer) WithConfig(rawConfig map[string]any) (Authorizer, error) {
+	if len(rawConfig) == 0 {
+		return a, nil
+	}
+
+	var conf graphQLAuthorizerConfig
+	if err := decodeConfig(a.app, rawConfig, &conf); err != nil {
+		return nil, errorchain.NewWithMessagef(heimdall.ErrConfiguration,
+			"failed decoding config for graphql authorizer '%s'", a.id).CausedBy(err)
+	}
+
+	auth := *a
+
+	if _, ok := rawConfig["max_depth"]; ok {
+		auth.maxDepth = conf.MaxDepth
+	}
+
+	if _, ok := rawConfig["max_complexity"]; ok {
+		auth.maxComplexity = conf.MaxComplexity
+	}
+
+	override := graphQLAuthorizerConfig{Expressions: conf.Expressions, Fields: conf.Fields}
+	if err := auth.compile(override); err != nil {
+		return nil, err
+	}
+
+	return &auth, nil
+}
+
+func (a *graphQLAuthorizer) ID() string { return a.id }
+
+func (a *graphQLAuthorizer) ContinueOnError() bool { return false }
+
+type graphQLOperation struct {
+	typ        string
+	name       string
+	fields     []string
+	variables  map[string]any
+	depth      int
+	complexity int
+	paths      map[string]struct{}
+}
+
+func (o *graphQLOperation) asMap() map[string]any {
+	return map[string]any{
+		"Operation":     o.typ,
+		"OperationName": o.name,
+		"Fields":        o.fields,
+		"Variables":     o.variables,
+		"Depth":         o.depth,
+		"Complexity":    o.complexity,
+	}
+}
+
+// parseOperation extracts the GraphQL request from the given request and parses the operation to be
+// executed. The request can be either a GET request with the operation in the query parameters, or a
+// POST request with a JSON encoded body, or with an application/graphql body.
+func (a *graphQLAuthorizer) parseOperation(req *heimdall.Request) (*graphQLOperation, error) {
+	var (
+		query         string
+		operationName string
+		variables     map[string]any
+	)
+
+	if req.Method == http.MethodGet {
+		params := req.URL.Query()
+		query = params.Get("query")
+		operationName = params.Get("operationName")
+	} else {
+		switch body := req.Body().(type) {
+		case string:
+			query = body
+		case map[string]any:
+			query, _ = body["query"].(string)
+			operationName, _ = body["operationName"].(string)
+			variables, _ = body["variables"].(map[string]any)
+		}
+	}
+
+	if len(query) == 0 {
+		return nil, errorchain.NewWithMessage(heimdall.ErrArgument, "request does not contain a GraphQL query").
+			WithErrorContext(a)
+	}
+
+	doc, err := parser.ParseQuery(&ast.Source{Input: query})
+	if err != nil {
+		return nil, errorchain.NewWithMessagef(heimdall.ErrArgument,
+			"failed parsing GraphQL query: %s", err.Error()).
+			WithErrorContext(a)
+	}
+
+	var op *ast.OperationDefinition
+
+	switch {
+	case len(operationName) != 0:
+		op = doc.Operations.ForName(operationName)
+	case len(doc.Operations) == 1:
+		op = doc.Operations[0]
+	}
+
+	if op == nil {
+		return nil, errorchain.NewWithMessage(heimdall.ErrArgument,
+			"could not determine the GraphQL operation to execute").
+			WithErrorContext(a)
+	}
+
+	result := &graphQLOperation{
+		typ:       string(op.Operation),
+		name:      op.Name,
+		variables: variables,
+		paths:     make(map[string]struct{}),
+	}
+
+	result.walk(doc, op.SelectionSet, result.typ, 1, make(map[string]bool))
+
+	return result, nil
+}
+
+// walk traverses the given selection set, resolving fragments, and computes the depth, the complexity
+// and the selected field paths of the operation. Every selected field contributes a value of 1 to the
+// complexity. Fragment spreads, which are already being resolved (cyclic fragments), are ignored.
+func (o *graphQLOperation) walk(
+	doc *ast.QueryDocument,
+	selections ast.SelectionSet,
+	parentPath string,
+	level int,
+	visiting map[string]bool,
+) {
+	for _, selection := range selections {
+		switch sel := selection.(type) {
+		case *ast.Field:
+			path := parentPath + "." + sel.Name
+
+			if _, seen := o.paths[path]; !seen && level == 1 {
+				o.fields = append(o.fields, sel.Name)
+			}
+
+			o.paths[path] = struct{}{}
+			o.complexity++
+			o.depth = max(o.depth, level)
+
+			o.walk(doc, sel.SelectionSet, path, level+1, visiting)
+		case *ast.InlineFragment:
+			o.walk(doc, sel.SelectionSet, parentPath, level, visiting)
+		case *ast.FragmentSpread:
+			fragment := doc.Fragments.ForName(sel.Name)
+			if fragment == nil || visiting[sel.Name] {
+				continue
+			}
+
+			visiting[sel.Name] = true
+			o.walk(doc, fragment.SelectionSet, parentPath, level, visiting)
+			delete(visiting, sel.Name)
+		}
+	}
+}
@@ -0,0 +1,525 @@
+// Copyright 2025 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package authorizers
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dadrus/heimdall/internal/app"
+	"github.com/dadrus/heimdall/internal/heimdall"
+	"github.com/dadrus/heimdall/internal/heimdall/mocks"
+	"github.com/dadrus/heimdall/internal/rules/mechanisms/subject"
+	"github.com/dadrus/heimdall/internal/validation"
+	"github.com/dadrus/heimdall/internal/x/testsupport"
+)
+
+func TestCreateGraphQLAuthorizer(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		uc     string
+		config []byte
+		assert func(t *testing.T, err error, auth *graphQLAuthorizer)
+	}{
+		{
+			uc: "without configuration",
+			assert: func(t *testing.T, err error, auth *graphQLAuthorizer) {
+				t.Helper()
+
+				require.NoError(t, err)
+				assert.Equal(t, "authz", auth.ID())
+				assert.Zero(t, auth.maxDepth)
+				assert.Zero(t, auth.maxComplexity)
+				assert.Empty(t, auth.expressions)
+				assert.Empty(t, auth.fields)
+				assert.False(t, auth.ContinueOnError())
+			},
+		},
+		{
+			uc:     "with unsupported attributes",
+			config: []byte(`foo: bar`),
+			assert: func(t *testing.T, err error, _ *graphQLAuthorizer) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				assert.Contains(t, err.Error(), "failed decoding")
+			},
+		},
+		{
+			uc:     "with negative max depth",
+			config: []byte(`max_depth: -1`),
+			assert: func(t *testing.T, err error, _ *graphQLAuthorizer) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				assert.Contains(t, err.Error(), "'max_depth' must be 0 or greater")
+			},
+		},
+		{
+			uc: "with field permission without expressions",
+			config: []byte(`
+fields:
+  - path: mutation.deleteUser
+`),
+			assert: func(t *testing.T, err error, _ *graphQLAuthorizer) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				assert.Contains(t, err.Error(), "'expressions' is a required field")
+			},
+		},
+		{
+			uc: "with malformed field permission expression",
+			config: []byte(`
+fields:
+  - path: mutation.deleteUser
+    expressions:
+      - expression: "foo()"
+`),
+			assert: func(t *testing.T, err error, _ *graphQLAuthorizer) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				assert.Contains(t, err.Error(), "field 'mutation.deleteUser'")
+			},
+		},
+		{
+			uc: "with full configuration",
+			config: []byte(`
+max_depth: 5
+max_complexity: 50
+expressions:
+  - expression: GraphQL.Operation != "subscription"
+fields:
+  - path: mutation.deleteUser
+    expressions:
+      - expression: Subject.ID == "admin"
+`),
+			assert: func(t *testing.T, err error, auth *graphQLAuthorizer) {
+				t.Helper()
+
+				require.NoError(t, err)
+				assert.Equal(t, 5, auth.maxDepth)
+				assert.Equal(t, 50, auth.maxComplexity)
+				assert.Len(t, auth.expressions, 1)
+				require.Len(t, auth.fields, 1)
+				assert.Equal(t, "mutation.deleteUser", auth.fields[0].path)
+				assert.Len(t, auth.fields[0].expressions, 1)
+			},
+		},
+	} {
+		t.Run("case="+tc.uc, func(t *testing.T) {
+			// GIVEN
+			conf, err := testsupport.DecodeTestConfig(tc.config)
+			require.NoError(t, err)
+
+			validator, err := validation.NewValidator()
+			require.NoError(t, err)
+
+			appCtx := app.NewContextMock(t)
+
+			appCtx.EXPECT().GeoIP().Maybe().Return(nil)
+			appCtx.EXPECT().Validator().Maybe().Return(validator)
+			appCtx.EXPECT().Logger().Return(log.Logger)
+
+			// WHEN
+			a, err := newGraphQLAuthorizer(appCtx, "authz", conf)
+
+			// THEN
+			tc.assert(t, err, a)
+		})
+	}
+}
+
+func TestCreateGraphQLAuthorizerFromPrototype(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		uc              string
+		prototypeConfig []byte
+		config          []byte
+		assert          func(t *testing.T, err error, prototype *graphQLAuthorizer, configured *graphQLAuthorizer)
+	}{
+		{
+			uc:              "no new configuration provided",
+			prototypeConfig: []byte(`max_depth: 5`),
+			assert: func(t *testing.T, err error, prototype *graphQLAuthorizer, configured *graphQLAuthorizer) {
+				t.Helper()
+
+				require.NoError(t, err)
+				assert.Equal(t, prototype, configured)
+			},
+		},
+		{
+			uc:              "with unsupported attributes",
+			prototypeConfig: []byte(`max_depth: 5`),
+			config:          []byte(`foo: bar`),
+			assert: func(t *testing.T, err error, _ *graphQLAuthorizer, _ *graphQLAuthorizer) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+			},
+		},
+		{
+			uc: "limits overridden",
+			prototypeConfig: []byte(`
+max_depth: 5
+max_complexity: 50
+expressions:
+  - expression: "true"
+`),
+			config: []byte(`
+max_depth: 0
+max_complexity: 10
+`),
+			assert: func(t *testing.T, err error, prototype *graphQLAuthorizer, configured *graphQLAuthorizer) {
+				t.Helper()
+
+				require.NoError(t, err)
+				assert.NotEqual(t, prototype, configured)
+				assert.Equal(t, prototype.id, configured.id)
+				assert.Equal(t, 5, prototype.maxDepth)
+				assert.Equal(t, 50, prototype.maxComplexity)
+				assert.Zero(t, configured.maxDepth)
+				assert.Equal(t, 10, configured.maxComplexity)
+				assert.Equal(t, prototype.expressions, configured.expressions)
+			},
+		},
+		{
+			uc: "field permissions overridden",
+			prototypeConfig: []byte(`
+max_depth: 5
+fields:
+  - path: query.users
+    expressions:
+      - expression: "true"
+`),
+			config: []byte(`
+fields:
+  - path: mutation.deleteUser
+    expressions:
+      - expression: "false"
+`),
+			assert: func(t *testing.T, err error, prototype *graphQLAuthorizer, configured *graphQLAuthorizer) {
+				t.Helper()
+
+				require.NoError(t, err)
+				assert.Equal(t, 5, configured.maxDepth)
+				require.Len(t, prototype.fields, 1)
+				assert.Equal(t, "query.users", prototype.fields[0].path)
+				require.Len(t, configured.fields, 1)
+				assert.Equal(t, "mutation.deleteUser", configured.fields[0].path)
+			},
+		},
+	} {
+		t.Run("case="+tc.uc, func(t *testing.T) {
+			// GIVEN
+			pc, err := testsupport.DecodeTestConfig(tc.prototypeConfig)
+			require.NoError(t, err)
+
+			conf, err := testsupport.DecodeTestConfig(tc.config)
+			require.NoError(t, err)
+
+			validator, err := validation.NewValidator()
+			require.NoError(t, err)
+
+			appCtx := app.NewContextMock(t)
+
+			appCtx.EXPECT().GeoIP().Maybe().Return(nil)
+			appCtx.EXPECT().Validator().Maybe().Return(validator)
+			appCtx.EXPECT().Logger().Return(log.Logger)
+
+			prototype, err := newGraphQLAuthorizer(appCtx, "authz", pc)
+			require.NoError(t, err)
+
+			// WHEN
+			auth, err := prototype.WithConfig(conf)
+
+			// THEN
+			var (
+				configured *graphQLAuthorizer
+				ok         bool
+			)
+
+			if err == nil {
+				configured, ok = auth.(*graphQLAuthorizer)
+				require.True(t, ok)
+			}
+
+			tc.assert(t, err, prototype, configured)
+		})
+	}
+}
+
+func TestGraphQLAuthorizerExecute(t *testing.T) {
+	t.Parallel()
+
+	const config = `
+max_depth: 3
+max_complexity: 6
+expressions:
+  - expression: GraphQL.Operation != "subscription"
+    message: subscriptions are not allowed
+  - expression: GraphQL.Operation != "mutation" || GraphQL.OperationName != ""
+    message: mutations must be named
+fields:
+  - path: mutation.deleteUser
+    expressions:
+      - expression: Subject.ID == "admin"
+        message: only admins can delete users
+  - path: query.user.email
+    expressions:
+      - expression: Subject.ID == GraphQL.Variables.id
+        message: email can only be queried for oneself
+`
+
+	for _, tc := range []struct {
+		uc     string
+		method string
+		query  string
+		body   any
+		assert func(t *testing.T, err error)
+	}{
+		{
+			uc:     "no query present",
+			method: http.MethodPost,
+			body:   map[string]any{"foo": "bar"},
+			assert: func(t *testing.T, err error) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrArgument)
+				assert.Contains(t, err.Error(), "does not contain a GraphQL query")
+
+				var identifier interface{ ID() string }
+				require.ErrorAs(t, err, &identifier)
+				assert.Equal(t, "authz", identifier.ID())
+			},
+		},
+		{
+			uc:     "malformed query",
+			method: http.MethodPost,
+			body:   map[string]any{"query": "{ users { "},
+			assert: func(t *testing.T, err error) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrArgument)
+				assert.Contains(t, err.Error(), "failed parsing GraphQL query")
+			},
+		},
+		{
+			uc:     "ambiguous operation",
+			method: http.MethodPost,
+			body:   map[string]any{"query": "query A { users { id } } query B { users { name } }"},
+			assert: func(t *testing.T, err error) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrArgument)
+				assert.Contains(t, err.Error(), "could not determine the GraphQL operation")
+			},
+		},
+		{
+			uc:     "max depth exceeded",
+			method: http.MethodPost,
+			body:   map[string]any{"query": "{ users { friends { friends { id } } } }"},
+			assert: func(t *testing.T, err error) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrArgument)
+				assert.Contains(t, err.Error(), "depth of the GraphQL operation (4) exceeds the allowed maximum of 3")
+			},
+		},
+		{
+			uc:     "max complexity exceeded using fragments",
+			method: http.MethodPost,
+			body: map[string]any{
+				"query": `
+query { users { ...userFields friends { ...userFields } } }
+fragment userFields on User { id name email ... on Admin { roles } }
+`,
+			},
+			assert: func(t *testing.T, err error) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrArgument)
+				assert.Contains(t, err.Error(),
+					"complexity of the GraphQL operation (10) exceeds the allowed maximum of 6")
+			},
+		},
+		{
+			uc:     "cyclic fragments are not followed",
+			method: http.MethodPost,
+			body: map[string]any{
+				"query": `
+query { users { ...userFields } }
+fragment userFields on User { id ...userFields }
+`,
+			},
+			assert: func(t *testing.T, err error) {
+				t.Helper()
+
+				require.NoError(t, err)
+			},
+		},
+		{
+			uc:     "operation level expression fails",
+			method: http.MethodPost,
+			body:   map[string]any{"query": "subscription { userCreated { id } }"},
+			assert: func(t *testing.T, err error) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrAuthorization)
+				assert.Contains(t, err.Error(), "subscriptions are not allowed")
+			},
+		},
+		{
+			uc:     "field permission fails",
+			method: http.MethodPost,
+			body: map[string]any{
+				"query":         "mutation DeleteUsers { deleteUser(id: 2) { id } }",
+				"operationName": "DeleteUsers",
+			},
+			assert: func(t *testing.T, err error) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrAuthorization)
+				assert.Contains(t, err.Error(), "only admins can delete users")
+			},
+		},
+		{
+			uc:     "nested field permission fails using variables",
+			method: http.MethodPost,
+			body: map[string]any{
+				"query":     "query Q($id: ID!) { user(id: $id) { id alias: email } }",
+				"variables": map[string]any{"id": "bar"},
+			},
+			assert: func(t *testing.T, err error) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrAuthorization)
+				assert.Contains(t, err.Error(), "email can only be queried for oneself")
+			},
+		},
+		{
+			uc:     "nested field permission succeeds using variables",
+			method: http.MethodPost,
+			body: map[string]any{
+				"query":     "query Q($id: ID!) { user(id: $id) { id email } }",
+				"variables": map[string]any{"id": "foo"},
+			},
+			assert: func(t *testing.T, err error) {
+				t.Helper()
+
+				require.NoError(t, err)
+			},
+		},
+		{
+			uc:     "query selected by operation name from multiple operations",
+			method: http.MethodPost,
+			body: map[string]any{
+				"query":         "query A { users { id } } mutation B { deleteUser(id: 1) { id } }",
+				"operationName": "A",
+			},
+			assert: func(t *testing.T, err error) {
+				t.Helper()
+
+				require.NoError(t, err)
+			},
+		},
+		{
+			uc:     "query sent as application/graphql body",
+			method: http.MethodPost,
+			body:   "{ users { id name } }",
+			assert: func(t *testing.T, err error) {
+				t.Helper()
+
+				require.NoError(t, err)
+			},
+		},
+		{
+			uc:     "query sent with GET request",
+			method: http.MethodGet,
+			query:  url.Values{"query": []string{"mutation { createUser { id } }"}}.Encode(),
+			assert: func(t *testing.T, err error) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrAuthorization)
+				assert.Contains(t, err.Error(), "mutations must be named")
+			},
+		},
+	} {
+		t.Run("case="+tc.uc, func(t *testing.T) {
+			// GIVEN
+			conf, err := testsupport.DecodeTestConfig([]byte(config))
+			require.NoError(t, err)
+
+			reqf := mocks.NewRequestFunctionsMock(t)
+			reqf.EXPECT().Body().Maybe().Return(tc.body)
+
+			ctx := mocks.NewRequestContextMock(t)
+			ctx.EXPECT().Context().Return(t.Context())
+			ctx.EXPECT().Outputs().Maybe().Return(map[string]any{})
+			ctx.EXPECT().Request().Return(&heimdall.Request{
+				RequestFunctions: reqf,
+				Method:           tc.method,
+				URL: &heimdall.URL{URL: url.URL{
+					Scheme:   "http",
+					Host:     "heimdall.local",
+					Path:     "/graphql",
+					RawQuery: tc.query,
+				}},
+			})
+
+			validator, err := validation.NewValidator()
+			require.NoError(t, err)
+
+			appCtx := app.NewContextMock(t)
+
+			appCtx.EXPECT().GeoIP().Maybe().Return(nil)
+			appCtx.EXPECT().Validator().Maybe().Return(validator)
+			appCtx.EXPECT().Logger().Return(log.Logger)
+
+			auth, err := newGraphQLAuthorizer(appCtx, "authz", conf)
+			require.NoError(t, err)
+
+			// WHEN
+			err = auth.Execute(ctx, &subject.Subject{ID: "foo"})
+
+			// THEN
+			tc.assert(t, err)
+		})
+	}
+}
@@ -0,0 +1,380 @@
+// Copyright 2025 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package authorizers
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/google/cel-go/cel"
+	"github.com/rs/zerolog"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/dadrus/heimdall/internal/app"
+	"github.com/dadrus/heimdall/internal/cache"
+	"github.com/dadrus/heimdall/internal/heimdall"
+	"github.com/dadrus/heimdall/internal/rules/endpoint"
+	"github.com/dadrus/heimdall/internal/rules/mechanisms/cellib"
+	"github.com/dadrus/heimdall/internal/rules/mechanisms/subject"
+	"github.com/dadrus/heimdall/internal/rules/mechanisms/template"
+	"github.com/dadrus/heimdall/internal/rules/mechanisms/values"
+	"github.com/dadrus/heimdall/internal/x"
+	"github.com/dadrus/heimdall/internal/x/errorchain"
+	"github.com/dadrus/heimdall/internal/x/stringx"
+)
+
+// by intention. Used only during application bootstrap
+//
+//nolint:gochecknoinits
+func init() {
+	registerTypeFactory(
+		func(app app.Context, id string, typ string, conf map[string]any) (bool, Authorizer, error) {
+			if typ != AuthorizerGRPC {
+				return false, nil, nil
+			}
+
+			auth, err := newGRPCAuthorizer(app, id, conf)
+
+			return true, auth, err
+		})
+}
+
+type grpcAuthorizer struct {
+	id                  string
+	app                 app.Context
+	e                   endpoint.GRPCEndpoint
+	client              *endpoint.GRPCClient
+	payload             template.Template
+	expressions         compiledExpressions
+	metadataForUpstream []string
+	ttl                 time.Duration
+	celEnv              *cel.Env
+	v                   values.Values
+}
+
+type grpcAuthorizationInformation struct {
+	Metadata metadata.MD `json:"metadata"`
+	Payload  any         `json:"payload"`
+}
+
+func (ai *grpcAuthorizationInformation) addMetadataTo(keys []string, ctx heimdall.RequestContext) {
+	for _, key := range keys {
+		for _, value := range ai.Metadata.Get(key) {
+			ctx.AddHeaderForUpstream(key, value)
+		}
+	}
+}
+
+func (ai *grpcAuthorizationInformation) addResultsTo(key string, ctx heimdall.RequestContext) {
+	if ai.Payload != nil {
+		ctx.Outputs()[key] = ai.Payload
+	}
+}
+
+func newGRPCAuthorizer(app app.Context, id string, rawConfig map[string]any) (*grpcAuthorizer, error) {
+	logger := app.Logger()
+	logger.Info().Str("_id", id).Msg("Creating grpc authorizer")
+
+	type Config struct {
+		Endpoint                  endpoint.GRPCEndpoint `mapstructure:"endpoint"                              validate:"required"` //nolint:lll
+		Expressions               []Expression          `mapstructure:"expressions"                           validate:"dive"`
+		Payload                   template.Template     `mapstructure:"payload"`
+		ResponseMetadataToForward []string              `mapstructure:"forward_response_metadata_to_upstream"`
+		CacheTTL                  time.Duration         `mapstructure:"cache_ttl"`
+		Values                    values.Values         `mapstructure:"values"`
+	}
+
+	var conf Config
+	if err := decodeConfig(app, rawConfig, &conf); err != nil {
+		return nil, errorchain.NewWithMessagef(heimdall.ErrConfiguration,
+			"failed decoding config for grpc authorizer '%s'", id).CausedBy(err)
+	}
+
+	env, err := cel.NewEnv(cellib.Library(app.GeoIP()))
+	if err != nil {
+		return nil, errorchain.NewWithMessage(heimdall.ErrInternal, "failed creating CEL environment").
+			CausedBy(err)
+	}
+
+	expressions, err := compileExpressions(conf.Expressions, env)
+	if err != nil {
+		return nil, err
+	}
+
+	if conf.Endpoint.TLS.Disabled {
+		logger.Warn().Str("_id", id).
+			Msg("No TLS configured for the endpoint used in grpc authorizer")
+	}
+
+	client, err := conf.Endpoint.CreateClient(app, id)
+	if err != nil {
+		return nil, errorchain.NewWithMessagef(heimdall.ErrConfiguration,
+			"failed creating grpc client for grpc authorizer '%s'", id).CausedBy(err)
+	}
+
+	return &grpcAuthorizer{
+		id:                  id,
+		app:                 app,
+		e:                   conf.Endpoint,
+		client:              client,
+		payload:             conf.Payload,
+		expressions:         expressions,
+		metadataForUpstream: conf.ResponseMetadataToForward,
+		ttl:                 conf.CacheTTL,
+		celEnv:              env,
+		v:                   conf.Values,
+	}, nil
+}
+
+func (a *grpcAuthorizer) Execute(ctx heimdall.RequestContext, sub *subject.Subject) error {
+	logger := zerolog.Ctx(ctx.Context())
+	logger.Debug().Str("_id", a.id).Msg("Authorizing using grpc authorizer")
+
+	if sub == nil {
+		return errorchain.NewWithMessage(heimdall.ErrInternal,
+			"failed to execute grpc authorizer due to 'nil' subject").
+			WithErrorContext(a)
+	}
+
+	cch := cache.Ctx(ctx.Context())
+
+	var (
+		cacheKey string
+		authInfo *grpcAuthorizationInformation
+	)
+
+	vals, payload, err := a.renderTemplates(ctx, sub)
+	if err != nil {
+		return err
+	}
+
+	if a.ttl > 0 {
+		cacheKey = a.calculateCacheKey(sub, vals, payload)
+		if entry, err := cch.Get(ctx.Context(), cacheKey); err == nil {
+			var ai grpcAuthorizationInformation
+
+			if err = json.Unmarshal(entry, &ai); err == nil {
+				logger.Debug().Msg("Reusing authorization information from cache")
+
+				authInfo = &ai
+			}
+		}
+	}
+
+	if authInfo == nil {
+		authInfo, err = a.doAuthorize(ctx, sub, vals, payload)
+		if err != nil {
+			return err
+		}
+
+		if a.ttl > 0 && len(cacheKey) != 0 {
+			data, _ := json.Marshal(authInfo)
+
+			if err = cch.Set(ctx.Context(), cacheKey, data, a.ttl); err != nil {
+				logger.Warn().Err(err).Msg("Failed to cache authorization information")
+			}
+		}
+	}
+
+	authInfo.addMetadataTo(a.metadataForUpstream, ctx)
+	authInfo.addResultsTo(a.id, ctx)
+
+	return nil
+}
+
+func (a *grpcAuthorizer) WithConfig(rawConfig map[string]any) (Authorizer, error) {
+	if len(rawConfig) == 0 {
+		return a, nil
+	}
+
+	type Config struct {
+		Payload                   template.Template `mapstructure:"payload"`
+		Expressions               []Expression      `mapstructure:"expressions"                           validate:"dive"`
+		ResponseMetadataToForward []string          `mapstructure:"forward_response_metadata_to_upstream"`
+		CacheTTL                  time.Duration     `mapstructure:"cache_ttl"`
+		Values                    values.Values     `mapstructure:"values"`
+	}
+
+	var conf Config
+	if err := decodeConfig(a.app, rawConfig, &conf); err != nil {
+		return nil, errorchain.NewWithMessagef(heimdall.ErrConfiguration,
+			"failed decoding config for grpc authorizer '%s'", a.id).CausedBy(err)
+	}
+
+	expressions, err := compileExpressions(conf.Expressions, a.celEnv)
+	if err != nil {
+		return nil, err
+	}
+
+	return &grpcAuthorizer{
+		id:          a.id,
+		app:         a.app,
+		e:           a.e,
+		client:      a.client,
+		payload:     x.IfThenElse(conf.Payload != nil, conf.Payload, a.payload),
+		celEnv:      a.celEnv,
+		expressions: x.IfThenElse(len(expressions) != 0, expressions, a.expressions),
+		metadataForUpstream: x.IfThenElse(len(conf.ResponseMetadataToForward) != 0,
+			conf.ResponseMetadataToForward, a.metadataForUpstream),
+		ttl: x.IfThenElse(conf.CacheTTL > 0, conf.CacheTTL, a.ttl),
+		v:   a.v.Merge(conf.Values),
+	}, nil
+}
+
+func (a *grpcAuthorizer) ID() string { return a.id }
+
+func (a *grpcAuthorizer) ContinueOnError() bool { return false }
+
+func (a *grpcAuthorizer) doAuthorize(
+	ctx heimdall.RequestContext,
+	sub *subject.Subject,
+	values map[string]string,
+	payload string,
+) (*grpcAuthorizationInformation, error) {
+	logger := zerolog.Ctx(ctx.Context())
+	logger.Debug().Msg("Calling grpc authorization endpoint")
+
+	renderer := endpoint.RenderFunc(func(tplString string) (string, error) {
+		tpl, err := template.New(tplString)
+		if err != nil {
+			return "", errorchain.NewWithMessage(heimdall.ErrInternal, "failed to create template").
+				WithErrorContext(a).
+				CausedBy(err)
+		}
+
+		return tpl.Render(map[string]any{
+			"Subject": sub,
+			"Values":  values,
+			"Outputs": ctx.Outputs(),
+		})
+	})
+
+	resp, err := a.client.Invoke(ctx.Context(), payload, renderer)
+	if err != nil {
+		return nil, a.mapError(err)
+	}
+
+	var data any
+	if err = json.Unmarshal(resp.Payload, &data); err != nil {
+		return nil, errorchain.NewWithMessage(heimdall.ErrInternal, "failed to unmarshal response").
+			WithErrorContext(a).
+			CausedBy(err)
+	}
+
+	logger.Debug().Msg("Verifying authorization response")
+
+	if err = a.expressions.eval(map[string]any{"Payload": data}, a); err != nil {
+		return nil, err
+	}
+
+	return &grpcAuthorizationInformation{Metadata: resp.Metadata, Payload: data}, nil
+}
+
+func (a *grpcAuthorizer) mapError(err error) error {
+	switch code := status.Code(err); {
+	case code == codes.PermissionDenied || code == codes.Unauthenticated:
+		return errorchain.NewWithMessagef(heimdall.ErrAuthorization,
+			"authorization failed based on received status code: %v", code).
+			WithErrorContext(a).
+			CausedBy(err)
+	case errors.Is(err, heimdall.ErrCommunicationTimeout):
+		return errorchain.NewWithMessage(heimdall.ErrCommunicationTimeout,
+			"call to the authorization grpc endpoint timed out").
+			WithErrorContext(a).
+			CausedBy(err)
+	case errors.Is(err, heimdall.ErrCommunication):
+		return errorchain.NewWithMessage(heimdall.ErrCommunication,
+			"call to the authorization grpc endpoint failed").
+			WithErrorContext(a).
+			CausedBy(err)
+	default:
+		return errorchain.NewWithMessage(heimdall.ErrInternal,
+			"failed calling the authorization grpc endpoint").
+			WithErrorContext(a).
+			CausedBy(err)
+	}
+}
+
+func (a *grpcAuthorizer) calculateCacheKey(sub *subject.Subject, values map[string]string, payload string) string {
+	const int64BytesCount = 8
+
+	ttlBytes := make([]byte, int64BytesCount)
+
+	//nolint:gosec
+	// no integer overflow during conversion possible
+	binary.LittleEndian.PutUint64(ttlBytes, uint64(a.ttl))
+
+	hash := sha256.New()
+	hash.Write(a.e.Hash())
+	hash.Write(stringx.ToBytes(a.id))
+	hash.Write(stringx.ToBytes(strings.Join(a.metadataForUpstream, ",")))
+	hash.Write(stringx.ToBytes(payload))
+	hash.Write(ttlBytes)
+	hash.Write(sub.Hash())
+
+	for k, v := range values {
+		hash.Write(stringx.ToBytes(k))
+		hash.Write(stringx.ToBytes(v))
+	}
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+func (a *grpcAuthorizer) renderTemplates(
+	ctx heimdall.RequestContext,
+	sub *subject.Subject,
+) (map[string]string, string, error) {
+	var (
+		values  map[string]string
+		payload string
+		err     error
+	)
+
+	if values, err = a.v.Render(map[string]any{
+		"Request": ctx.Request(),
+		"Subject": sub,
+		"Outputs": ctx.Outputs(),
+	}); err != nil {
+		return nil, "", errorchain.NewWithMessage(heimdall.ErrInternal,
+			"failed to render values for the authorization endpoint").
+			WithErrorContext(a).
+			CausedBy(err)
+	}
+
+	if a.payload != nil {
+		if payload, err = a.payload.Render(map[string]any{
+			"Request": ctx.Request(),
+			"Subject": sub,
+			"Values":  values,
+			"Outputs": ctx.Outputs(),
+		}); err != nil {
+			return nil, "", errorchain.NewWithMessage(heimdall.ErrInternal,
+				"failed to render payload for the authorization endpoint").
+				WithErrorContext(a).
+				CausedBy(err)
+		}
+	}
+
+	return values, payload, nil
+}
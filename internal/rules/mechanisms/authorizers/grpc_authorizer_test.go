@@ -0,0 +1,431 @@
+// Copyright 2025 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package authorizers
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/dadrus/heimdall/internal/app"
+	"github.com/dadrus/heimdall/internal/cache"
+	"github.com/dadrus/heimdall/internal/cache/mocks"
+	"github.com/dadrus/heimdall/internal/config"
+	"github.com/dadrus/heimdall/internal/heimdall"
+	heimdallmocks "github.com/dadrus/heimdall/internal/heimdall/mocks"
+	"github.com/dadrus/heimdall/internal/rules/mechanisms/subject"
+	"github.com/dadrus/heimdall/internal/validation"
+	"github.com/dadrus/heimdall/internal/watcher"
+	"github.com/dadrus/heimdall/internal/x/testsupport"
+)
+
+func newGRPCTestAppContext(t *testing.T, enforceTLS bool) app.Context {
+	t.Helper()
+
+	es := config.EnforcementSettings{EnforceEgressTLS: enforceTLS}
+	validator, err := validation.NewValidator(
+		validation.WithTagValidator(es),
+		validation.WithErrorTranslator(es),
+	)
+	require.NoError(t, err)
+
+	appCtx := app.NewContextMock(t)
+
+	appCtx.EXPECT().GeoIP().Maybe().Return(nil)
+	appCtx.EXPECT().Validator().Maybe().Return(validator)
+	appCtx.EXPECT().Logger().Maybe().Return(log.Logger)
+	appCtx.EXPECT().Watcher().Maybe().Return(&watcher.NoopWatcher{})
+	appCtx.EXPECT().CertificateObserver().Maybe().Return(nil)
+
+	return appCtx
+}
+
+func TestCreateGRPCAuthorizer(t *testing.T) {
+	t.Parallel()
+
+	for uc, tc := range map[string]struct {
+		enforceTLS bool
+		config     []byte
+		assert     func(t *testing.T, err error, auth *grpcAuthorizer)
+	}{
+		"without endpoint": {
+			config: []byte(`payload: bar`),
+			assert: func(t *testing.T, err error, _ *grpcAuthorizer) {
+				t.Helper()
+
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				require.ErrorContains(t, err, "'endpoint' is a required field")
+			},
+		},
+		"with unsupported fields": {
+			config: []byte(`
+endpoint:
+  address: foo:1234
+  method: foo.Bar/Baz
+foo: bar
+`),
+			assert: func(t *testing.T, err error, _ *grpcAuthorizer) {
+				t.Helper()
+
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				require.ErrorContains(t, err, "failed decoding")
+			},
+		},
+		"with disabled TLS while TLS is enforced": {
+			enforceTLS: true,
+			config: []byte(`
+endpoint:
+  address: foo:1234
+  method: foo.Bar/Baz
+  tls:
+    disabled: true
+`),
+			assert: func(t *testing.T, err error, _ *grpcAuthorizer) {
+				t.Helper()
+
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				require.ErrorContains(t, err, "'endpoint'.'tls'.'disabled' must be false")
+			},
+		},
+		"with invalid expression": {
+			config: []byte(`
+endpoint:
+  address: foo:1234
+  method: foo.Bar/Baz
+expressions:
+  - expression: "foo()"
+`),
+			assert: func(t *testing.T, err error, _ *grpcAuthorizer) {
+				t.Helper()
+
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				require.ErrorContains(t, err, "failed to compile expression")
+			},
+		},
+		"with malformed method": {
+			config: []byte(`
+endpoint:
+  address: foo:1234
+  method: Baz
+`),
+			assert: func(t *testing.T, err error, _ *grpcAuthorizer) {
+				t.Helper()
+
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				require.ErrorContains(t, err, "failed creating grpc client")
+			},
+		},
+		"with full configuration": {
+			enforceTLS: true,
+			config: []byte(`
+endpoint:
+  address: foo:1234
+  method: foo.Bar/Baz
+payload: '{"id": {{ quote .Subject.ID }}}'
+expressions:
+  - expression: "Payload.allowed == true"
+forward_response_metadata_to_upstream:
+  - x-foo
+cache_ttl: 5s
+values:
+  foo: bar
+`),
+			assert: func(t *testing.T, err error, auth *grpcAuthorizer) {
+				t.Helper()
+
+				require.NoError(t, err)
+				assert.Equal(t, "foo:1234", auth.e.Address)
+				assert.NotNil(t, auth.client)
+				assert.NotNil(t, auth.payload)
+				assert.Len(t, auth.expressions, 1)
+				assert.Equal(t, []string{"x-foo"}, auth.metadataForUpstream)
+				assert.Equal(t, 5*time.Second, auth.ttl)
+				assert.Len(t, auth.v, 1)
+				assert.Equal(t, "authz", auth.ID())
+				assert.False(t, auth.ContinueOnError())
+			},
+		},
+	} {
+		t.Run(uc, func(t *testing.T) {
+			conf, err := testsupport.DecodeTestConfig(tc.config)
+			require.NoError(t, err)
+
+			auth, err := newGRPCAuthorizer(newGRPCTestAppContext(t, tc.enforceTLS), "authz", conf)
+
+			tc.assert(t, err, auth)
+		})
+	}
+}
+
+func TestCreateGRPCAuthorizerFromPrototype(t *testing.T) {
+	t.Parallel()
+
+	conf, err := testsupport.DecodeTestConfig([]byte(`
+endpoint:
+  address: foo:1234
+  method: foo.Bar/Baz
+payload: foo
+expressions:
+  - expression: "true"
+forward_response_metadata_to_upstream:
+  - x-foo
+values:
+  foo: bar
+`))
+	require.NoError(t, err)
+
+	prototype, err := newGRPCAuthorizer(newGRPCTestAppContext(t, false), "authz", conf)
+	require.NoError(t, err)
+
+	// without config
+	configured, err := prototype.WithConfig(nil)
+	require.NoError(t, err)
+	assert.Equal(t, prototype, configured)
+
+	// with unsupported config
+	_, err = prototype.WithConfig(map[string]any{"endpoint": map[string]any{"address": "bar:1234"}})
+	require.ErrorIs(t, err, heimdall.ErrConfiguration)
+
+	// with config
+	configured, err = prototype.WithConfig(map[string]any{
+		"payload":                               "bar",
+		"expressions":                           []any{map[string]any{"expression": "false"}},
+		"forward_response_metadata_to_upstream": []string{"x-bar"},
+		"cache_ttl":                             "1s",
+		"values":                                map[string]any{"bar": "baz"},
+	})
+	require.NoError(t, err)
+
+	auth, ok := configured.(*grpcAuthorizer)
+	require.True(t, ok)
+	assert.Equal(t, prototype.id, auth.id)
+	assert.Equal(t, prototype.e, auth.e)
+	assert.Same(t, prototype.client, auth.client)
+	assert.NotEqual(t, prototype.payload, auth.payload)
+	assert.NotEqual(t, prototype.expressions, auth.expressions)
+	assert.Equal(t, []string{"x-bar"}, auth.metadataForUpstream)
+	assert.Equal(t, time.Second, auth.ttl)
+	assert.Len(t, auth.v, 2)
+}
+
+func TestGRPCAuthorizerExecute(t *testing.T) {
+	t.Parallel()
+
+	for uc, tc := range map[string]struct {
+		config         func(addr string) []byte
+		subject        *subject.Subject
+		interceptor    grpc.UnaryServerInterceptor
+		configureMocks func(t *testing.T, ctx *heimdallmocks.RequestContextMock, cch *mocks.CacheMock)
+		assert         func(t *testing.T, err error, outputs map[string]any)
+	}{
+		"with nil subject": {
+			config: func(addr string) []byte {
+				return []byte("endpoint:\n  address: " + addr + "\n  method: grpc.health.v1.Health/Check")
+			},
+			assert: func(t *testing.T, err error, _ map[string]any) {
+				t.Helper()
+
+				require.ErrorIs(t, err, heimdall.ErrInternal)
+				require.ErrorContains(t, err, "'nil' subject")
+			},
+		},
+		"with permission denied status": {
+			config: func(addr string) []byte {
+				return []byte(`
+endpoint:
+  address: ` + addr + `
+  method: grpc.health.v1.Health/Check
+  tls:
+    disabled: true
+payload: '{"service": "foo"}'
+`)
+			},
+			subject: &subject.Subject{ID: "foo"},
+			interceptor: func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+				if info.FullMethod == "/grpc.health.v1.Health/Check" {
+					return nil, status.Error(codes.PermissionDenied, "denied")
+				}
+
+				return handler(ctx, req)
+			},
+			assert: func(t *testing.T, err error, _ map[string]any) {
+				t.Helper()
+
+				require.ErrorIs(t, err, heimdall.ErrAuthorization)
+				require.ErrorContains(t, err, "PermissionDenied")
+
+				var identifier interface{ ID() string }
+				require.ErrorAs(t, err, &identifier)
+				assert.Equal(t, "authz", identifier.ID())
+			},
+		},
+		"with failing call": {
+			config: func(addr string) []byte {
+				return []byte(`
+endpoint:
+  address: ` + addr + `
+  method: grpc.health.v1.Health/Check
+  tls:
+    disabled: true
+payload: '{"service": "bar"}'
+`)
+			},
+			subject: &subject.Subject{ID: "foo"},
+			assert: func(t *testing.T, err error, _ map[string]any) {
+				t.Helper()
+
+				require.ErrorIs(t, err, heimdall.ErrCommunication)
+				require.ErrorContains(t, err, "authorization grpc endpoint failed")
+			},
+		},
+		"with failing expression": {
+			config: func(addr string) []byte {
+				return []byte(`
+endpoint:
+  address: ` + addr + `
+  method: grpc.health.v1.Health/Check
+  tls:
+    disabled: true
+payload: '{"service": "foo"}'
+expressions:
+  - expression: "Payload.status == 'NOT_SERVING'"
+    message: service is serving
+`)
+			},
+			subject: &subject.Subject{ID: "foo"},
+			assert: func(t *testing.T, err error, _ map[string]any) {
+				t.Helper()
+
+				require.ErrorIs(t, err, heimdall.ErrAuthorization)
+				require.ErrorContains(t, err, "service is serving")
+			},
+		},
+		"with successful call, metadata forwarding and caching of the response": {
+			config: func(addr string) []byte {
+				return []byte(`
+endpoint:
+  address: ` + addr + `
+  method: grpc.health.v1.Health/Check
+  tls:
+    disabled: true
+payload: '{"service": {{ quote .Values.service }}}'
+expressions:
+  - expression: "Payload.status == 'SERVING'"
+forward_response_metadata_to_upstream:
+  - x-authz
+cache_ttl: 10s
+values:
+  service: '{{ .Subject.Attributes.service }}'
+`)
+			},
+			subject: &subject.Subject{ID: "alice", Attributes: map[string]any{"service": "foo"}},
+			interceptor: func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+				if err := grpc.SetHeader(ctx, metadata.Pairs("x-authz", "granted")); err != nil {
+					return nil, err
+				}
+
+				return handler(ctx, req)
+			},
+			configureMocks: func(t *testing.T, ctx *heimdallmocks.RequestContextMock, cch *mocks.CacheMock) {
+				t.Helper()
+
+				ctx.EXPECT().AddHeaderForUpstream("x-authz", "granted")
+				cch.EXPECT().Get(mock.Anything, mock.Anything).Return(nil, errors.New("no cache entry"))
+				cch.EXPECT().Set(mock.Anything, mock.Anything, mock.Anything, 10*time.Second).Return(nil)
+			},
+			assert: func(t *testing.T, err error, outputs map[string]any) {
+				t.Helper()
+
+				require.NoError(t, err)
+				assert.Equal(t, map[string]any{"status": "SERVING"}, outputs["authz"])
+			},
+		},
+		"with response from cache": {
+			config: func(addr string) []byte {
+				return []byte(`
+endpoint:
+  address: ` + addr + `
+  method: grpc.health.v1.Health/Check
+  tls:
+    disabled: true
+forward_response_metadata_to_upstream:
+  - x-authz
+cache_ttl: 10s
+`)
+			},
+			subject: &subject.Subject{ID: "alice"},
+			interceptor: func(_ context.Context, _ any, info *grpc.UnaryServerInfo, _ grpc.UnaryHandler) (any, error) {
+				return nil, errors.New("unexpected call to " + info.FullMethod)
+			},
+			configureMocks: func(t *testing.T, ctx *heimdallmocks.RequestContextMock, cch *mocks.CacheMock) {
+				t.Helper()
+
+				data, err := json.Marshal(grpcAuthorizationInformation{
+					Metadata: metadata.Pairs("x-authz", "cached"),
+					Payload:  map[string]any{"status": "SERVING"},
+				})
+				require.NoError(t, err)
+
+				ctx.EXPECT().AddHeaderForUpstream("x-authz", "cached")
+				cch.EXPECT().Get(mock.Anything, mock.Anything).Return(data, nil)
+			},
+			assert: func(t *testing.T, err error, outputs map[string]any) {
+				t.Helper()
+
+				require.NoError(t, err)
+				assert.Equal(t, map[string]any{"status": "SERVING"}, outputs["authz"])
+			},
+		},
+	} {
+		t.Run(uc, func(t *testing.T) {
+			addr := testsupport.StartGRPCHealthServer(t, tc.interceptor)
+
+			conf, err := testsupport.DecodeTestConfig(tc.config(addr))
+			require.NoError(t, err)
+
+			auth, err := newGRPCAuthorizer(newGRPCTestAppContext(t, false), "authz", conf)
+			require.NoError(t, err)
+
+			cch := mocks.NewCacheMock(t)
+			outputs := map[string]any{}
+
+			ctx := heimdallmocks.NewRequestContextMock(t)
+			ctx.EXPECT().Context().Return(cache.WithContext(t.Context(), cch))
+			ctx.EXPECT().Request().Maybe().Return(&heimdall.Request{})
+			ctx.EXPECT().Outputs().Maybe().Return(outputs)
+
+			if tc.configureMocks != nil {
+				tc.configureMocks(t, ctx, cch)
+			}
+
+			err = auth.Execute(ctx, tc.subject)
+
+			tc.assert(t, err, outputs)
+		})
+	}
+}
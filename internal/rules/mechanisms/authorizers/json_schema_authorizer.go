@@ -0,0 +1,272 @@
+// Copyright 2025 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package authorizers
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/goccy/go-json"
+	"github.com/rs/zerolog"
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+	"gopkg.in/yaml.v3"
+
+	"github.com/dadrus/heimdall/internal/app"
+	"github.com/dadrus/heimdall/internal/heimdall"
+	"github.com/dadrus/heimdall/internal/rules/mechanisms/subject"
+	"github.com/dadrus/heimdall/internal/x"
+	"github.com/dadrus/heimdall/internal/x/errorchain"
+	"github.com/dadrus/heimdall/internal/x/stringx"
+)
+
+// by intention. Used only during application bootstrap
+//
+//nolint:gochecknoinits
+func init() {
+	registerTypeFactory(
+		func(app app.Context, id string, typ string, conf map[string]any) (bool, Authorizer, error) {
+			if typ != AuthorizerJSONSchema {
+				return false, nil, nil
+			}
+
+			auth, err := newJSONSchemaAuthorizer(app, id, conf)
+
+			return true, auth, err
+		})
+}
+
+var errorPrinter = message.NewPrinter(language.English) //nolint:gochecknoglobals
+
+type SchemaSource struct {
+	File   string `mapstructure:"file"   validate:"required_without=Inline,excluded_with=Inline"`
+	Inline string `mapstructure:"inline" validate:"required_without=File,excluded_with=File"`
+}
+
+func (s *SchemaSource) compile(id, part string) (*jsonschema.Schema, error) {
+	var (
+		raw      any
+		url      string
+		contents = stringx.ToBytes(s.Inline)
+	)
+
+	if len(s.File) != 0 {
+		path, err := filepath.Abs(s.File)
+		if err != nil {
+			return nil, err
+		}
+
+		if contents, err = os.ReadFile(path); err != nil {
+			return nil, err
+		}
+
+		url = "file://" + filepath.ToSlash(path)
+	} else {
+		url = fmt.Sprintf("heimdall://authorizers/%s/%s.json", id, part)
+	}
+
+	// YAML is a superset of JSON, so both formats are supported
+	if err := yaml.Unmarshal(contents, &raw); err != nil {
+		return nil, err
+	}
+
+	doc, err := toJSONValue(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	compiler := jsonschema.NewCompiler()
+	compiler.DefaultDraft(jsonschema.Draft2020)
+
+	if err = compiler.AddResource(url, doc); err != nil {
+		return nil, err
+	}
+
+	return compiler.Compile(url)
+}
+
+type jsonSchemaAuthorizer struct {
+	id      string
+	app     app.Context
+	body    *jsonschema.Schema
+	query   *jsonschema.Schema
+	headers *jsonschema.Schema
+}
+
+func newJSONSchemaAuthorizer(app app.Context, id string, rawConfig map[string]any) (*jsonSchemaAuthorizer, error) {
+	logger := app.Logger()
+	logger.Info().Str("_id", id).Msg("Creating json_schema authorizer")
+
+	type Config struct {
+		Body    *SchemaSource `mapstructure:"body"    validate:"required_without_all=Query Headers"`
+		Query   *SchemaSource `mapstructure:"query"`
+		Headers *SchemaSource `mapstructure:"headers"`
+	}
+
+	var conf Config
+	if err := decodeConfig(app, rawConfig, &conf); err != nil {
+		return nil, errorchain.NewWithMessagef(heimdall.ErrConfiguration,
+			"failed decoding config for json_schema authorizer '%s'", id).CausedBy(err)
+	}
+
+	auth := &jsonSchemaAuthorizer{id: id, app: app}
+
+	return auth, auth.compileSchemas(conf.Body, conf.Query, conf.Headers)
+}
+
+func (a *jsonSchemaAuthorizer) Execute(ctx heimdall.RequestContext, _ *subject.Subject) error {
+	logger := zerolog.Ctx(ctx.Context())
+	logger.Debug().Str("_id", a.id).Msg("Authorizing using json_schema authorizer")
+
+	req := ctx.Request()
+
+	if a.headers != nil {
+		if err := a.validate(a.headers, req.Headers(), "headers"); err != nil {
+			return err
+		}
+	}
+
+	if a.query != nil {
+		if err := a.validate(a.query, req.URL.Query(), "query parameters"); err != nil {
+			return err
+		}
+	}
+
+	if a.body != nil {
+		if err := a.validate(a.body, req.Body(), "body"); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (a *jsonSchemaAuthorizer) WithConfig(rawConfig map[string]any) (Authorizer, error) {
+	if len(rawConfig) == 0 {
+		return a, nil
+	}
+
+	type Config struct {
+		Body    *SchemaSource `mapstructure:"body"`
+		Query   *SchemaSource `mapstructure:"query"`
+		Headers *SchemaSource `mapstructure:"headers"`
+	}
+
+	var conf Config
+	if err := decodeConfig(a.app, rawConfig, &conf); err != nil {
+		return nil, errorchain.NewWithMessagef(heimdall.ErrConfiguration,
+			"failed decoding config for json_schema authorizer '%s'", a.id).CausedBy(err)
+	}
+
+	auth := &jsonSchemaAuthorizer{id: a.id, app: a.app}
+	if err := auth.compileSchemas(conf.Body, conf.Query, conf.Headers); err != nil {
+		return nil, err
+	}
+
+	auth.body = x.IfThenElse(auth.body != nil, auth.body, a.body)
+	auth.query = x.IfThenElse(auth.query != nil, auth.query, a.query)
+	auth.headers = x.IfThenElse(auth.headers != nil, auth.headers, a.headers)
+
+	return auth, nil
+}
+
+func (a *jsonSchemaAuthorizer) ID() string { return a.id }
+
+func (a *jsonSchemaAuthorizer) ContinueOnError() bool { return false }
+
+func (a *jsonSchemaAuthorizer) compileSchemas(body, query, headers *SchemaSource) error {
+	for _, entry := range []struct {
+		part   string
+		source *SchemaSource
+		target **jsonschema.Schema
+	}{
+		{part: "body", source: body, target: &a.body},
+		{part: "query", source: query, target: &a.query},
+		{part: "headers", source: headers, target: &a.headers},
+	} {
+		if entry.source == nil {
+			continue
+		}
+
+		schema, err := entry.source.compile(a.id, entry.part)
+		if err != nil {
+			return errorchain.NewWithMessagef(heimdall.ErrConfiguration,
+				"failed to compile %s schema for json_schema authorizer '%s'", entry.part, a.id).CausedBy(err)
+		}
+
+		*entry.target = schema
+	}
+
+	return nil
+}
+
+func (a *jsonSchemaAuthorizer) validate(schema *jsonschema.Schema, value any, what string) error {
+	doc, err := toJSONValue(value)
+	if err != nil {
+		return errorchain.NewWithMessagef(heimdall.ErrInternal, "failed to prepare request %s for validation", what).
+			WithErrorContext(a).
+			CausedBy(err)
+	}
+
+	err = schema.Validate(doc)
+	if err == nil {
+		return nil
+	}
+
+	var validationErr *jsonschema.ValidationError
+	if !errors.As(err, &validationErr) {
+		return errorchain.NewWithMessagef(heimdall.ErrInternal, "failed to validate request %s", what).
+			WithErrorContext(a).
+			CausedBy(err)
+	}
+
+	return errorchain.NewWithMessagef(heimdall.ErrArgument, "request %s failed schema validation: %s",
+		what, strings.Join(violations(validationErr), "; ")).
+		WithErrorContext(a)
+}
+
+// violations flattens the tree of the given validation error to the list of its leaf errors, each
+// prefixed with the location of the offending value within the validated document.
+func violations(err *jsonschema.ValidationError) []string {
+	if len(err.Causes) == 0 {
+		return []string{fmt.Sprintf("/%s: %s",
+			strings.Join(err.InstanceLocation, "/"), err.ErrorKind.LocalizedString(errorPrinter))}
+	}
+
+	var result []string
+	for _, cause := range err.Causes {
+		result = append(result, violations(cause)...)
+	}
+
+	return result
+}
+
+// toJSONValue converts the given value to a representation the jsonschema library can deal with.
+// E.g. form decoded values come as string slices, which are not understood by it.
+func toJSONValue(value any) (any, error) {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	return jsonschema.UnmarshalJSON(bytes.NewReader(raw))
+}
@@ -0,0 +1,454 @@
+// Copyright 2025 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package authorizers
+
+import (
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dadrus/heimdall/internal/app"
+	"github.com/dadrus/heimdall/internal/heimdall"
+	"github.com/dadrus/heimdall/internal/heimdall/mocks"
+	"github.com/dadrus/heimdall/internal/validation"
+	"github.com/dadrus/heimdall/internal/x"
+	"github.com/dadrus/heimdall/internal/x/testsupport"
+)
+
+func TestCreateJSONSchemaAuthorizer(t *testing.T) {
+	t.Parallel()
+
+	schemaFile := filepath.Join(t.TempDir(), "schema.yaml")
+	require.NoError(t, os.WriteFile(schemaFile, []byte(`
+type: object
+required: [ name ]
+properties:
+  name:
+    type: string
+`), 0o600))
+
+	for _, tc := range []struct {
+		uc     string
+		id     string
+		config []byte
+		assert func(t *testing.T, err error, auth *jsonSchemaAuthorizer)
+	}{
+		{
+			uc: "without configuration",
+			assert: func(t *testing.T, err error, _ *jsonSchemaAuthorizer) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				assert.Contains(t, err.Error(), "'body' is a required field")
+			},
+		},
+		{
+			uc: "with unsupported attributes",
+			config: []byte(`
+body:
+  inline: "{ type: object }"
+foo: bar
+`),
+			assert: func(t *testing.T, err error, _ *jsonSchemaAuthorizer) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				assert.Contains(t, err.Error(), "failed decoding")
+			},
+		},
+		{
+			uc: "with both, file and inline schema",
+			config: []byte(`
+body:
+  inline: "{ type: object }"
+  file: /foo/bar.json
+`),
+			assert: func(t *testing.T, err error, _ *jsonSchemaAuthorizer) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				assert.Contains(t, err.Error(), "'file' is an excluded field")
+			},
+		},
+		{
+			uc: "with not existing schema file",
+			config: []byte(`
+body:
+  file: /does/not/exist.json
+`),
+			assert: func(t *testing.T, err error, _ *jsonSchemaAuthorizer) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				assert.Contains(t, err.Error(), "failed to compile body schema")
+			},
+		},
+		{
+			uc: "with invalid inline schema",
+			config: []byte(`
+query:
+  inline: "{ type: foo }"
+`),
+			assert: func(t *testing.T, err error, _ *jsonSchemaAuthorizer) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				assert.Contains(t, err.Error(), "failed to compile query schema")
+			},
+		},
+		{
+			uc: "with valid configuration",
+			id: "authz",
+			config: []byte(`
+body:
+  file: ` + schemaFile + `
+query:
+  inline: |
+    { "type": "object" }
+headers:
+  inline: |
+    type: object
+`),
+			assert: func(t *testing.T, err error, auth *jsonSchemaAuthorizer) {
+				t.Helper()
+
+				require.NoError(t, err)
+				assert.Equal(t, "authz", auth.ID())
+				assert.NotNil(t, auth.body)
+				assert.NotNil(t, auth.query)
+				assert.NotNil(t, auth.headers)
+				assert.False(t, auth.ContinueOnError())
+			},
+		},
+	} {
+		t.Run("case="+tc.uc, func(t *testing.T) {
+			// GIVEN
+			conf, err := testsupport.DecodeTestConfig(tc.config)
+			require.NoError(t, err)
+
+			validator, err := validation.NewValidator()
+			require.NoError(t, err)
+
+			appCtx := app.NewContextMock(t)
+			appCtx.EXPECT().Validator().Maybe().Return(validator)
+			appCtx.EXPECT().Logger().Return(log.Logger)
+
+			// WHEN
+			a, err := newJSONSchemaAuthorizer(appCtx, tc.id, conf)
+
+			// THEN
+			tc.assert(t, err, a)
+		})
+	}
+}
+
+func TestCreateJSONSchemaAuthorizerFromPrototype(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		uc              string
+		prototypeConfig []byte
+		config          []byte
+		assert          func(t *testing.T, err error, prototype *jsonSchemaAuthorizer, configured *jsonSchemaAuthorizer)
+	}{
+		{
+			uc: "no new configuration provided",
+			prototypeConfig: []byte(`
+body:
+  inline: "{ type: object }"
+`),
+			assert: func(t *testing.T, err error, prototype *jsonSchemaAuthorizer, configured *jsonSchemaAuthorizer) {
+				t.Helper()
+
+				require.NoError(t, err)
+				assert.Equal(t, prototype, configured)
+			},
+		},
+		{
+			uc: "with invalid configuration",
+			prototypeConfig: []byte(`
+body:
+  inline: "{ type: object }"
+`),
+			config: []byte(`
+foo: bar
+`),
+			assert: func(t *testing.T, err error, _ *jsonSchemaAuthorizer, _ *jsonSchemaAuthorizer) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				assert.Contains(t, err.Error(), "failed decoding")
+			},
+		},
+		{
+			uc: "with schemas overriding and extending the prototype",
+			prototypeConfig: []byte(`
+body:
+  inline: "{ type: object }"
+headers:
+  inline: "{ type: object }"
+`),
+			config: []byte(`
+body:
+  inline: "{ type: array }"
+query:
+  inline: "{ type: object }"
+`),
+			assert: func(t *testing.T, err error, prototype *jsonSchemaAuthorizer, configured *jsonSchemaAuthorizer) {
+				t.Helper()
+
+				require.NoError(t, err)
+				assert.NotEqual(t, prototype, configured)
+				assert.Equal(t, prototype.ID(), configured.ID())
+				assert.NotEqual(t, prototype.body, configured.body)
+				assert.Nil(t, prototype.query)
+				assert.NotNil(t, configured.query)
+				assert.Equal(t, prototype.headers, configured.headers)
+			},
+		},
+	} {
+		t.Run("case="+tc.uc, func(t *testing.T) {
+			// GIVEN
+			pc, err := testsupport.DecodeTestConfig(tc.prototypeConfig)
+			require.NoError(t, err)
+
+			conf, err := testsupport.DecodeTestConfig(tc.config)
+			require.NoError(t, err)
+
+			validator, err := validation.NewValidator()
+			require.NoError(t, err)
+
+			appCtx := app.NewContextMock(t)
+			appCtx.EXPECT().Validator().Maybe().Return(validator)
+			appCtx.EXPECT().Logger().Return(log.Logger)
+
+			prototype, err := newJSONSchemaAuthorizer(appCtx, "authz", pc)
+			require.NoError(t, err)
+
+			// WHEN
+			auth, err := prototype.WithConfig(conf)
+
+			// THEN
+			var (
+				configured *jsonSchemaAuthorizer
+				ok         bool
+			)
+
+			if err == nil {
+				configured, ok = auth.(*jsonSchemaAuthorizer)
+				require.True(t, ok)
+			}
+
+			tc.assert(t, err, prototype, configured)
+		})
+	}
+}
+
+func TestJSONSchemaAuthorizerExecute(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		uc             string
+		config         []byte
+		configureMocks func(t *testing.T, reqf *mocks.RequestFunctionsMock)
+		rawQuery       string
+		assert         func(t *testing.T, err error)
+	}{
+		{
+			uc: "body does not conform to the schema",
+			config: []byte(`
+body:
+  inline: |
+    type: object
+    required: [ name, age ]
+    properties:
+      name: { type: string }
+      age: { type: integer, minimum: 18 }
+`),
+			configureMocks: func(t *testing.T, reqf *mocks.RequestFunctionsMock) {
+				t.Helper()
+
+				reqf.EXPECT().Body().Return(map[string]any{"age": 16})
+			},
+			assert: func(t *testing.T, err error) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrArgument)
+				assert.Contains(t, err.Error(), "request body failed schema validation")
+				assert.Contains(t, err.Error(), "/: missing property 'name'")
+				assert.Contains(t, err.Error(), "/age: minimum: got 16, want 18")
+
+				var identifier interface{ ID() string }
+				require.ErrorAs(t, err, &identifier)
+				assert.Equal(t, "authz", identifier.ID())
+			},
+		},
+		{
+			uc: "form encoded body conforms to the schema",
+			config: []byte(`
+body:
+  inline: |
+    type: object
+    properties:
+      name:
+        type: array
+        items: { type: string }
+        maxItems: 1
+`),
+			configureMocks: func(t *testing.T, reqf *mocks.RequestFunctionsMock) {
+				t.Helper()
+
+				reqf.EXPECT().Body().Return(map[string]any{"name": []string{"foo"}})
+			},
+			assert: func(t *testing.T, err error) {
+				t.Helper()
+
+				require.NoError(t, err)
+			},
+		},
+		{
+			uc: "query parameters do not conform to the schema",
+			config: []byte(`
+query:
+  inline: |
+    type: object
+    additionalProperties: false
+    properties:
+      limit:
+        type: array
+        items: { type: string, pattern: "^[0-9]+$" }
+`),
+			rawQuery: "limit=foo&offset=10",
+			assert: func(t *testing.T, err error) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrArgument)
+				assert.Contains(t, err.Error(), "request query parameters failed schema validation")
+				assert.Contains(t, err.Error(), "/limit/0")
+				assert.Contains(t, err.Error(), "offset")
+			},
+		},
+		{
+			uc: "headers, query parameters and body conform to the schemas",
+			config: []byte(`
+headers:
+  inline: |
+    type: object
+    required: [ X-Tenant-Id ]
+query:
+  inline: |
+    type: object
+    required: [ limit ]
+body:
+  inline: |
+    type: object
+    required: [ name ]
+`),
+			rawQuery: "limit=10",
+			configureMocks: func(t *testing.T, reqf *mocks.RequestFunctionsMock) {
+				t.Helper()
+
+				reqf.EXPECT().Headers().Return(map[string]string{"X-Tenant-Id": "foo"})
+				reqf.EXPECT().Body().Return(map[string]any{"name": "bar"})
+			},
+			assert: func(t *testing.T, err error) {
+				t.Helper()
+
+				require.NoError(t, err)
+			},
+		},
+		{
+			uc: "headers do not conform to the schema",
+			config: []byte(`
+headers:
+  inline: |
+    type: object
+    required: [ X-Tenant-Id ]
+body:
+  inline: |
+    type: object
+`),
+			configureMocks: func(t *testing.T, reqf *mocks.RequestFunctionsMock) {
+				t.Helper()
+
+				reqf.EXPECT().Headers().Return(map[string]string{"Accept": "*/*"})
+			},
+			assert: func(t *testing.T, err error) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrArgument)
+				assert.Contains(t, err.Error(), "request headers failed schema validation")
+				assert.Contains(t, err.Error(), "missing property 'X-Tenant-Id'")
+			},
+		},
+	} {
+		t.Run("case="+tc.uc, func(t *testing.T) {
+			// GIVEN
+			conf, err := testsupport.DecodeTestConfig(tc.config)
+			require.NoError(t, err)
+
+			configureMocks := x.IfThenElse(tc.configureMocks != nil,
+				tc.configureMocks,
+				func(t *testing.T, _ *mocks.RequestFunctionsMock) { t.Helper() })
+
+			reqf := mocks.NewRequestFunctionsMock(t)
+			configureMocks(t, reqf)
+
+			ctx := mocks.NewRequestContextMock(t)
+			ctx.EXPECT().Context().Return(t.Context())
+			ctx.EXPECT().Request().Return(&heimdall.Request{
+				RequestFunctions: reqf,
+				URL: &heimdall.URL{URL: url.URL{
+					Scheme:   "http",
+					Host:     "localhost",
+					Path:     "/test",
+					RawQuery: tc.rawQuery,
+				}},
+			})
+
+			validator, err := validation.NewValidator()
+			require.NoError(t, err)
+
+			appCtx := app.NewContextMock(t)
+			appCtx.EXPECT().Validator().Maybe().Return(validator)
+			appCtx.EXPECT().Logger().Return(log.Logger)
+
+			auth, err := newJSONSchemaAuthorizer(appCtx, "authz", conf)
+			require.NoError(t, err)
+
+			// WHEN
+			err = auth.Execute(ctx, nil)
+
+			// THEN
+			tc.assert(t, err)
+		})
+	}
+}
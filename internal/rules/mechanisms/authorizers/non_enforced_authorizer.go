@@ -0,0 +1,38 @@
+// Copyright 2025 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package authorizers
+
+// NonEnforced marks the given authorizer as not enforced. The outcome of such authorizers is only
+// recorded by the pipeline (shadow mode). The mark is preserved on reconfiguration.
+func NonEnforced(auth Authorizer) Authorizer {
+	return &nonEnforcedAuthorizer{Authorizer: auth}
+}
+
+type nonEnforcedAuthorizer struct {
+	Authorizer
+}
+
+func (a *nonEnforcedAuthorizer) WithConfig(config map[string]any) (Authorizer, error) {
+	auth, err := a.Authorizer.WithConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	return &nonEnforcedAuthorizer{Authorizer: auth}, nil
+}
+
+func (a *nonEnforcedAuthorizer) Enforced() bool { return false }
@@ -0,0 +1,108 @@
+// Copyright 2025 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package authorizers
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dadrus/heimdall/internal/heimdall"
+	"github.com/dadrus/heimdall/internal/rules/mechanisms/subject"
+)
+
+type testAuthorizer struct {
+	id  string
+	err error
+}
+
+func (a *testAuthorizer) ID() string { return a.id }
+
+func (a *testAuthorizer) Execute(heimdall.RequestContext, *subject.Subject) error { return a.err }
+
+func (a *testAuthorizer) WithConfig(config map[string]any) (Authorizer, error) {
+	if len(config) != 0 {
+		return nil, errors.New("test error")
+	}
+
+	return a, nil
+}
+
+func (a *testAuthorizer) ContinueOnError() bool { return false }
+
+func TestNonEnforcedAuthorizer(t *testing.T) {
+	t.Parallel()
+
+	// GIVEN
+	auth := NonEnforced(&testAuthorizer{id: "authz", err: heimdall.ErrAuthorization})
+
+	// WHEN
+	enforcer, ok := auth.(interface{ Enforced() bool })
+
+	// THEN
+	require.True(t, ok)
+	assert.False(t, enforcer.Enforced())
+	assert.Equal(t, "authz", auth.ID())
+	assert.False(t, auth.ContinueOnError())
+	require.ErrorIs(t, auth.Execute(nil, nil), heimdall.ErrAuthorization)
+}
+
+func TestNonEnforcedAuthorizerWithConfig(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		uc     string
+		config map[string]any
+		assert func(t *testing.T, err error, auth Authorizer)
+	}{
+		{
+			uc: "without config",
+			assert: func(t *testing.T, err error, auth Authorizer) {
+				t.Helper()
+
+				require.NoError(t, err)
+
+				enforcer, ok := auth.(interface{ Enforced() bool })
+				require.True(t, ok)
+				assert.False(t, enforcer.Enforced())
+			},
+		},
+		{
+			uc:     "with config not supported by the wrapped authorizer",
+			config: map[string]any{"foo": "bar"},
+			assert: func(t *testing.T, err error, _ Authorizer) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorContains(t, err, "test error")
+			},
+		},
+	} {
+		t.Run(tc.uc, func(t *testing.T) {
+			// GIVEN
+			auth := NonEnforced(&testAuthorizer{id: "authz"})
+
+			// WHEN
+			configured, err := auth.WithConfig(tc.config)
+
+			// THEN
+			tc.assert(t, err, configured)
+		})
+	}
+}
@@ -0,0 +1,300 @@
+// Copyright 2025 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package authorizers
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/legacy"
+	"github.com/goccy/go-json"
+	"github.com/rs/zerolog"
+	"gopkg.in/yaml.v3"
+
+	"github.com/dadrus/heimdall/internal/app"
+	"github.com/dadrus/heimdall/internal/heimdall"
+	"github.com/dadrus/heimdall/internal/rules/mechanisms/subject"
+	"github.com/dadrus/heimdall/internal/x/errorchain"
+	"github.com/dadrus/heimdall/internal/x/stringx"
+)
+
+var errUnexpectedBodyType = errors.New("unexpected body type")
+
+// by intention. Used only during application bootstrap
+//
+//nolint:gochecknoinits
+func init() {
+	registerTypeFactory(
+		func(app app.Context, id string, typ string, conf map[string]any) (bool, Authorizer, error) {
+			if typ != AuthorizerOpenAPI {
+				return false, nil, nil
+			}
+
+			auth, err := newOpenAPIAuthorizer(app, id, conf)
+
+			return true, auth, err
+		})
+}
+
+type openAPIAuthorizer struct {
+	id     string
+	router routers.Router
+	opts   *openapi3filter.Options
+}
+
+func newOpenAPIAuthorizer(app app.Context, id string, rawConfig map[string]any) (*openAPIAuthorizer, error) {
+	logger := app.Logger()
+	logger.Info().Str("_id", id).Msg("Creating openapi authorizer")
+
+	type Config struct {
+		Specification       string `mapstructure:"specification"         validate:"required"`
+		ValidateRequestBody *bool  `mapstructure:"validate_request_body"`
+	}
+
+	var conf Config
+	if err := decodeConfig(app, rawConfig, &conf); err != nil {
+		return nil, errorchain.NewWithMessagef(heimdall.ErrConfiguration,
+			"failed decoding config for openapi authorizer '%s'", id).CausedBy(err)
+	}
+
+	doc, err := LoadOpenAPISpecification(conf.Specification)
+	if err != nil {
+		return nil, errorchain.NewWithMessagef(heimdall.ErrConfiguration,
+			"failed loading OpenAPI specification for openapi authorizer '%s'", id).CausedBy(err)
+	}
+
+	// The request might have been forwarded to heimdall by some proxy, or heimdall might be
+	// used in decision mode. In both cases the host and the scheme of the request don't have to
+	// match the servers defined in the specification. That is why only the path part is used.
+	servers := make(openapi3.Servers, 0, len(doc.Servers))
+
+	for _, server := range doc.Servers {
+		basePath, err := server.BasePath()
+		if err != nil {
+			return nil, errorchain.NewWithMessagef(heimdall.ErrConfiguration,
+				"invalid server url '%s' in OpenAPI specification", server.URL).CausedBy(err)
+		}
+
+		servers = append(servers, &openapi3.Server{URL: basePath})
+	}
+
+	doc.Servers = servers
+
+	router, err := legacy.NewRouter(doc)
+	if err != nil {
+		return nil, errorchain.NewWithMessagef(heimdall.ErrConfiguration,
+			"failed creating router for openapi authorizer '%s'", id).CausedBy(err)
+	}
+
+	return &openAPIAuthorizer{
+		id:     id,
+		router: router,
+		opts: &openapi3filter.Options{
+			ExcludeRequestBody: conf.ValidateRequestBody != nil && !*conf.ValidateRequestBody,
+			MultiError:         true,
+			// security requirements are taken care of by the authenticators
+			AuthenticationFunc: openapi3filter.NoopAuthenticationFunc,
+		},
+	}, nil
+}
+
+func (a *openAPIAuthorizer) Execute(ctx heimdall.RequestContext, _ *subject.Subject) error {
+	logger := zerolog.Ctx(ctx.Context())
+	logger.Debug().Str("_id", a.id).Msg("Authorizing using openapi authorizer")
+
+	req, err := toHTTPRequest(ctx.Context(), ctx.Request())
+	if err != nil {
+		return errorchain.NewWithMessage(heimdall.ErrInternal, "failed to prepare request for validation").
+			WithErrorContext(a).
+			CausedBy(err)
+	}
+
+	// only the path is used for route lookup (see also the creation of the router)
+	route, pathParams, err := a.router.FindRoute(&http.Request{
+		Method: req.Method,
+		URL:    &url.URL{Path: req.URL.Path, RawPath: req.URL.RawPath},
+	})
+	if err != nil {
+		return errorchain.NewWithMessagef(heimdall.ErrArgument,
+			"request does not match any operation from the OpenAPI specification: %s", err.Error()).
+			WithErrorContext(a)
+	}
+
+	err = openapi3filter.ValidateRequest(ctx.Context(), &openapi3filter.RequestValidationInput{
+		Request:    req,
+		PathParams: pathParams,
+		Route:      route,
+		Options:    a.opts,
+	})
+	if err != nil {
+		return errorchain.NewWithMessagef(heimdall.ErrArgument,
+			"request does not conform to the '%s' operation: %s",
+			route.Method+" "+route.Path, strings.Join(openAPIViolations(err, "request"), "; ")).
+			WithErrorContext(a)
+	}
+
+	return nil
+}
+
+func (a *openAPIAuthorizer) WithConfig(rawConfig map[string]any) (Authorizer, error) {
+	if len(rawConfig) != 0 {
+		return nil, errorchain.NewWithMessage(heimdall.ErrConfiguration,
+			"reconfiguration of an openapi authorizer is not supported")
+	}
+
+	return a, nil
+}
+
+func (a *openAPIAuthorizer) ID() string { return a.id }
+
+func (a *openAPIAuthorizer) ContinueOnError() bool { return false }
+
+// LoadOpenAPISpecification loads and validates the OpenAPI specification from the given file.
+// References to external documents are resolved relative to the location of that file.
+func LoadOpenAPISpecification(path string) (*openapi3.T, error) {
+	loader := openapi3.NewLoader()
+	loader.IsExternalRefsAllowed = true
+
+	doc, err := loader.LoadFromFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = doc.Validate(loader.Context); err != nil {
+		return nil, err
+	}
+
+	return doc, nil
+}
+
+func openAPIViolations(err error, subject string) []string {
+	switch typed := err.(type) { //nolint:errorlint
+	case openapi3.MultiError:
+		var result []string
+		for _, err := range typed {
+			result = append(result, openAPIViolations(err, subject)...)
+		}
+
+		return result
+	case *openapi3filter.RequestError:
+		switch {
+		case typed.Parameter != nil:
+			subject = fmt.Sprintf("%s parameter '%s'", typed.Parameter.In, typed.Parameter.Name)
+		case typed.RequestBody != nil:
+			subject = "body"
+		}
+
+		if typed.Err == nil {
+			return []string{subject + ": " + typed.Reason}
+		}
+
+		return openAPIViolations(typed.Err, subject)
+	case *openapi3.SchemaError:
+		if pointer := typed.JSONPointer(); len(pointer) != 0 {
+			subject = fmt.Sprintf("%s at /%s", subject, strings.Join(pointer, "/"))
+		}
+
+		return []string{subject + ": " + typed.Reason}
+	default:
+		return []string{subject + ": " + err.Error()}
+	}
+}
+
+// toHTTPRequest reconstructs the http request from the given heimdall request as required for
+// the validation. Since the body of the heimdall request is available in its decoded form only,
+// it is encoded again according to the present Content-Type header.
+func toHTTPRequest(ctx context.Context, req *heimdall.Request) (*http.Request, error) {
+	headers := req.Headers()
+
+	body, err := encodeBody(headers["Content-Type"], req.Body())
+	if err != nil {
+		return nil, err
+	}
+
+	hreq, err := http.NewRequestWithContext(ctx, req.Method, req.URL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	for name, value := range headers {
+		if name == "Host" {
+			hreq.Host = value
+
+			continue
+		}
+
+		hreq.Header.Set(name, value)
+	}
+
+	hreq.Header.Del("Content-Length")
+
+	if len(body) != 0 {
+		hreq.Body = io.NopCloser(bytes.NewReader(body))
+		hreq.ContentLength = int64(len(body))
+	}
+
+	return hreq, nil
+}
+
+func encodeBody(contentType string, body any) ([]byte, error) {
+	switch typed := body.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		return stringx.ToBytes(typed), nil
+	case []byte:
+		return typed, nil
+	}
+
+	switch {
+	case strings.Contains(contentType, "json"):
+		return json.Marshal(body)
+	case strings.Contains(contentType, "yaml"):
+		return yaml.Marshal(body)
+	case strings.Contains(contentType, "application/x-www-form-urlencoded"):
+		values, ok := body.(map[string]any)
+		if !ok {
+			break
+		}
+
+		form := make(url.Values, len(values))
+
+		for key, value := range values {
+			switch val := value.(type) {
+			case []string:
+				form[key] = val
+			case string:
+				form[key] = []string{val}
+			default:
+				form[key] = []string{fmt.Sprint(val)}
+			}
+		}
+
+		return stringx.ToBytes(form.Encode()), nil
+	}
+
+	return nil, errUnexpectedBodyType
+}
@@ -0,0 +1,389 @@
+// Copyright 2025 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package authorizers
+
+import (
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dadrus/heimdall/internal/app"
+	"github.com/dadrus/heimdall/internal/heimdall"
+	"github.com/dadrus/heimdall/internal/heimdall/mocks"
+	"github.com/dadrus/heimdall/internal/validation"
+	"github.com/dadrus/heimdall/internal/x/testsupport"
+)
+
+const petStoreSpec = `
+openapi: 3.0.3
+info:
+  title: Pet Store
+  version: 1.0.0
+servers:
+  - url: https://api.example.com/v1
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      parameters:
+        - name: limit
+          in: query
+          schema:
+            type: integer
+            maximum: 100
+      responses:
+        "200":
+          description: pets
+    post:
+      operationId: createPet
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              type: object
+              required: [ name ]
+              properties:
+                name:
+                  type: string
+                age:
+                  type: integer
+      responses:
+        "201":
+          description: created
+  /pets/{petId}:
+    get:
+      operationId: showPetById
+      parameters:
+        - name: petId
+          in: path
+          required: true
+          schema:
+            type: integer
+        - name: X-Tenant-Id
+          in: header
+          required: true
+          schema:
+            type: string
+      responses:
+        "200":
+          description: pet
+`
+
+func writeOpenAPISpec(t *testing.T, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "openapi.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+	return path
+}
+
+func TestCreateOpenAPIAuthorizer(t *testing.T) {
+	t.Parallel()
+
+	specFile := writeOpenAPISpec(t, petStoreSpec)
+	invalidSpecFile := writeOpenAPISpec(t, `
+openapi: 3.0.3
+paths: {}
+`)
+
+	for _, tc := range []struct {
+		uc     string
+		config []byte
+		assert func(t *testing.T, err error, auth *openAPIAuthorizer)
+	}{
+		{
+			uc: "without configuration",
+			assert: func(t *testing.T, err error, _ *openAPIAuthorizer) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				assert.Contains(t, err.Error(), "'specification' is a required field")
+			},
+		},
+		{
+			uc: "with unsupported attributes",
+			config: []byte(`
+specification: ` + specFile + `
+foo: bar
+`),
+			assert: func(t *testing.T, err error, _ *openAPIAuthorizer) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				assert.Contains(t, err.Error(), "failed decoding")
+			},
+		},
+		{
+			uc:     "with not existing specification file",
+			config: []byte(`specification: /does/not/exist.yaml`),
+			assert: func(t *testing.T, err error, _ *openAPIAuthorizer) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				assert.Contains(t, err.Error(), "failed loading OpenAPI specification")
+			},
+		},
+		{
+			uc:     "with invalid specification",
+			config: []byte(`specification: ` + invalidSpecFile),
+			assert: func(t *testing.T, err error, _ *openAPIAuthorizer) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				assert.Contains(t, err.Error(), "failed loading OpenAPI specification")
+			},
+		},
+		{
+			uc: "with valid configuration",
+			config: []byte(`
+specification: ` + specFile + `
+validate_request_body: false
+`),
+			assert: func(t *testing.T, err error, auth *openAPIAuthorizer) {
+				t.Helper()
+
+				require.NoError(t, err)
+				assert.Equal(t, "authz", auth.ID())
+				assert.NotNil(t, auth.router)
+				assert.True(t, auth.opts.ExcludeRequestBody)
+				assert.False(t, auth.ContinueOnError())
+			},
+		},
+	} {
+		t.Run("case="+tc.uc, func(t *testing.T) {
+			// GIVEN
+			conf, err := testsupport.DecodeTestConfig(tc.config)
+			require.NoError(t, err)
+
+			validator, err := validation.NewValidator()
+			require.NoError(t, err)
+
+			appCtx := app.NewContextMock(t)
+			appCtx.EXPECT().Validator().Maybe().Return(validator)
+			appCtx.EXPECT().Logger().Return(log.Logger)
+
+			// WHEN
+			a, err := newOpenAPIAuthorizer(appCtx, "authz", conf)
+
+			// THEN
+			tc.assert(t, err, a)
+		})
+	}
+}
+
+func TestCreateOpenAPIAuthorizerFromPrototype(t *testing.T) {
+	t.Parallel()
+
+	validator, err := validation.NewValidator()
+	require.NoError(t, err)
+
+	appCtx := app.NewContextMock(t)
+	appCtx.EXPECT().Validator().Maybe().Return(validator)
+	appCtx.EXPECT().Logger().Return(log.Logger)
+
+	prototype, err := newOpenAPIAuthorizer(appCtx, "authz",
+		map[string]any{"specification": writeOpenAPISpec(t, petStoreSpec)})
+	require.NoError(t, err)
+
+	// WHEN
+	configured, err := prototype.WithConfig(nil)
+
+	// THEN
+	require.NoError(t, err)
+	assert.Equal(t, prototype, configured)
+
+	// WHEN
+	_, err = prototype.WithConfig(map[string]any{"validate_request_body": false})
+
+	// THEN
+	require.Error(t, err)
+	require.ErrorIs(t, err, heimdall.ErrConfiguration)
+}
+
+func TestOpenAPIAuthorizerExecute(t *testing.T) {
+	t.Parallel()
+
+	specFile := writeOpenAPISpec(t, petStoreSpec)
+
+	for _, tc := range []struct {
+		uc      string
+		method  string
+		path    string
+		query   string
+		headers map[string]string
+		body    any
+		assert  func(t *testing.T, err error)
+	}{
+		{
+			uc:     "no matching operation",
+			method: http.MethodGet,
+			path:   "/v1/owners",
+			assert: func(t *testing.T, err error) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrArgument)
+				assert.Contains(t, err.Error(), "request does not match any operation")
+
+				var identifier interface{ ID() string }
+				require.ErrorAs(t, err, &identifier)
+				assert.Equal(t, "authz", identifier.ID())
+			},
+		},
+		{
+			uc:     "not defined method",
+			method: http.MethodDelete,
+			path:   "/v1/pets",
+			assert: func(t *testing.T, err error) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrArgument)
+				assert.Contains(t, err.Error(), "request does not match any operation")
+			},
+		},
+		{
+			uc:     "invalid query parameter",
+			method: http.MethodGet,
+			path:   "/v1/pets",
+			query:  "limit=1000",
+			assert: func(t *testing.T, err error) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrArgument)
+				assert.Contains(t, err.Error(), "'GET /pets' operation")
+				assert.Contains(t, err.Error(), "query parameter 'limit'")
+			},
+		},
+		{
+			uc:     "valid query parameter",
+			method: http.MethodGet,
+			path:   "/v1/pets",
+			query:  "limit=10",
+			assert: func(t *testing.T, err error) {
+				t.Helper()
+
+				require.NoError(t, err)
+			},
+		},
+		{
+			uc:     "invalid path parameter and missing header",
+			method: http.MethodGet,
+			path:   "/v1/pets/foo",
+			assert: func(t *testing.T, err error) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrArgument)
+				assert.Contains(t, err.Error(), "path parameter 'petId'")
+				assert.Contains(t, err.Error(), "header parameter 'X-Tenant-Id'")
+			},
+		},
+		{
+			uc:      "valid path parameter and header",
+			method:  http.MethodGet,
+			path:    "/v1/pets/1",
+			headers: map[string]string{"X-Tenant-Id": "foo"},
+			assert: func(t *testing.T, err error) {
+				t.Helper()
+
+				require.NoError(t, err)
+			},
+		},
+		{
+			uc:      "invalid body",
+			method:  http.MethodPost,
+			path:    "/v1/pets",
+			headers: map[string]string{"Content-Type": "application/json"},
+			body:    map[string]any{"age": "foo"},
+			assert: func(t *testing.T, err error) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrArgument)
+				assert.Contains(t, err.Error(), "body at /name")
+				assert.Contains(t, err.Error(), "body at /age")
+			},
+		},
+		{
+			uc:      "valid body",
+			method:  http.MethodPost,
+			path:    "/v1/pets",
+			headers: map[string]string{"Content-Type": "application/json"},
+			body:    map[string]any{"name": "Tom", "age": 2},
+			assert: func(t *testing.T, err error) {
+				t.Helper()
+
+				require.NoError(t, err)
+			},
+		},
+	} {
+		t.Run("case="+tc.uc, func(t *testing.T) {
+			// GIVEN
+			headers := map[string]string{"Host": "heimdall.local"}
+			for k, v := range tc.headers {
+				headers[k] = v
+			}
+
+			reqf := mocks.NewRequestFunctionsMock(t)
+			reqf.EXPECT().Headers().Return(headers)
+			reqf.EXPECT().Body().Return(tc.body)
+
+			ctx := mocks.NewRequestContextMock(t)
+			ctx.EXPECT().Context().Return(t.Context())
+			ctx.EXPECT().Request().Return(&heimdall.Request{
+				RequestFunctions: reqf,
+				Method:           tc.method,
+				URL: &heimdall.URL{URL: url.URL{
+					Scheme:   "http",
+					Host:     "heimdall.local",
+					Path:     tc.path,
+					RawQuery: tc.query,
+				}},
+			})
+
+			validator, err := validation.NewValidator()
+			require.NoError(t, err)
+
+			appCtx := app.NewContextMock(t)
+			appCtx.EXPECT().Validator().Maybe().Return(validator)
+			appCtx.EXPECT().Logger().Return(log.Logger)
+
+			auth, err := newOpenAPIAuthorizer(appCtx, "authz", map[string]any{"specification": specFile})
+			require.NoError(t, err)
+
+			// WHEN
+			err = auth.Execute(ctx, nil)
+
+			// THEN
+			tc.assert(t, err)
+		})
+	}
+}
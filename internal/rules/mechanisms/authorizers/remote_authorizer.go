@@ -73,6 +73,7 @@ type remoteAuthorizer struct {
 	ttl                time.Duration
 	celEnv             *cel.Env
 	v                  values.Values
+	kvPlainText        bool
 }
 
 type authorizationInformation struct {
@@ -106,6 +107,7 @@ func newRemoteAuthorizer(app app.Context, id string, rawConfig map[string]any) (
 		ResponseHeadersToForward []string          `mapstructure:"forward_response_headers_to_upstream"`
 		CacheTTL                 time.Duration     `mapstructure:"cache_ttl"`
 		Values                   values.Values     `mapstructure:"values"`
+		KVPlainText              bool              `mapstructure:"plain_text_key_values"`
 	}
 
 	var conf Config
@@ -114,7 +116,7 @@ func newRemoteAuthorizer(app app.Context, id string, rawConfig map[string]any) (
 			"failed decoding config for remote authorizer '%s'", id).CausedBy(err)
 	}
 
-	env, err := cel.NewEnv(cellib.Library())
+	env, err := cel.NewEnv(cellib.Library(app.GeoIP()))
 	if err != nil {
 		return nil, errorchain.NewWithMessage(heimdall.ErrInternal, "failed creating CEL environment").
 			CausedBy(err)
@@ -140,6 +142,7 @@ func newRemoteAuthorizer(app app.Context, id string, rawConfig map[string]any) (
 		ttl:                conf.CacheTTL,
 		celEnv:             env,
 		v:                  conf.Values,
+		kvPlainText:        conf.KVPlainText,
 	}, nil
 }
 
@@ -232,8 +235,9 @@ func (a *remoteAuthorizer) WithConfig(rawConfig map[string]any) (Authorizer, err
 		expressions: x.IfThenElse(len(expressions) != 0, expressions, a.expressions),
 		headersForUpstream: x.IfThenElse(len(conf.ResponseHeadersToForward) != 0,
 			conf.ResponseHeadersToForward, a.headersForUpstream),
-		ttl: x.IfThenElse(conf.CacheTTL > 0, conf.CacheTTL, a.ttl),
-		v:   a.v.Merge(conf.Values),
+		ttl:         x.IfThenElse(conf.CacheTTL > 0, conf.CacheTTL, a.ttl),
+		v:           a.v.Merge(conf.Values),
+		kvPlainText: a.kvPlainText,
 	}, nil
 }
 
@@ -306,6 +310,15 @@ func (a *remoteAuthorizer) doAuthorize(
 func (a *remoteAuthorizer) readResponse(ctx heimdall.RequestContext, resp *http.Response) (any, error) {
 	logger := zerolog.Ctx(ctx.Context())
 
+	if err := endpoint.UnavailabilityError(resp); err != nil {
+		var chain *errorchain.ErrorChain
+		if errors.As(err, &chain) {
+			chain.WithErrorContext(a)
+		}
+
+		return nil, err
+	}
+
 	if !(resp.StatusCode >= http.StatusOK && resp.StatusCode < http.StatusMultipleChoices) {
 		return nil, errorchain.NewWithMessagef(heimdall.ErrAuthorization,
 			"authorization failed based on received response code: %v", resp.StatusCode).
@@ -327,7 +340,7 @@ func (a *remoteAuthorizer) readResponse(ctx heimdall.RequestContext, resp *http.
 
 	contentType := resp.Header.Get("Content-Type")
 
-	decoder, err := contenttype.NewDecoder(contentType)
+	decoder, err := contenttype.NewResponseDecoder(contentType, a.kvPlainText)
 	if err != nil {
 		logger.Warn().Str("_content_type", contentType).
 			Msg("Content type is not supported. Treating it as string")
@@ -336,6 +349,12 @@ func (a *remoteAuthorizer) readResponse(ctx heimdall.RequestContext, resp *http.
 	}
 
 	result, err := decoder.Decode(rawData)
+	if errors.Is(err, contenttype.ErrNoKeyValuePairs) {
+		logger.Debug().Msg("Response does not contain key/value pairs. Treating it as string")
+
+		return stringx.ToString(rawData), nil
+	}
+
 	if err != nil {
 		return nil, errorchain.NewWithMessage(heimdall.ErrInternal, "failed to unmarshal response").
 			WithErrorContext(a).
@@ -120,6 +120,7 @@ payload: "{{ .Subject.ID }}"
 				assert.Equal(t, "bar", val)
 				assert.Empty(t, auth.headersForUpstream)
 				assert.Zero(t, auth.ttl)
+				assert.False(t, auth.kvPlainText)
 
 				assert.Equal(t, "authz", auth.ID())
 				assert.False(t, auth.ContinueOnError())
@@ -191,6 +192,7 @@ forward_response_headers_to_upstream:
 cache_ttl: 5s
 values:
   foo: "{{ .Subject.ID }}"
+plain_text_key_values: true
 `),
 			assert: func(t *testing.T, err error, auth *remoteAuthorizer) {
 				t.Helper()
@@ -229,6 +231,7 @@ values:
 				})
 				require.NoError(t, err)
 				assert.Equal(t, map[string]string{"foo": "bar"}, res)
+				assert.True(t, auth.kvPlainText)
 
 				assert.Equal(t, "authz", auth.ID())
 				assert.False(t, auth.ContinueOnError())
@@ -248,6 +251,8 @@ values:
 			require.NoError(t, err)
 
 			appCtx := app.NewContextMock(t)
+
+			appCtx.EXPECT().GeoIP().Maybe().Return(nil)
 			appCtx.EXPECT().Validator().Maybe().Return(validator)
 			appCtx.EXPECT().Logger().Return(log.Logger)
 
@@ -486,6 +491,8 @@ cache_ttl: 15s
 			require.NoError(t, err)
 
 			appCtx := app.NewContextMock(t)
+
+			appCtx.EXPECT().GeoIP().Maybe().Return(nil)
 			appCtx.EXPECT().Validator().Maybe().Return(validator)
 			appCtx.EXPECT().Logger().Return(log.Logger)
 
@@ -524,7 +531,7 @@ func TestRemoteAuthorizerExecute(t *testing.T) {
 		responseCode        int
 	)
 
-	env, err := cel.NewEnv(cellib.Library())
+	env, err := cel.NewEnv(cellib.Library(nil))
 	require.NoError(t, err)
 
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -931,6 +938,39 @@ func TestRemoteAuthorizerExecute(t *testing.T) {
 				assert.Equal(t, "authz", identifier.ID())
 			},
 		},
+		"with rate limit response from server": {
+			authorizer: &remoteAuthorizer{
+				id: "authz",
+				e:  endpoint.Endpoint{URL: srv.URL},
+			},
+			subject: &subject.Subject{ID: "foo"},
+			instructServer: func(t *testing.T) {
+				t.Helper()
+
+				responseHeaders = map[string]string{"Retry-After": "120"}
+				responseCode = http.StatusTooManyRequests
+			},
+			configureContext: func(t *testing.T, ctx *heimdallmocks.RequestContextMock) {
+				t.Helper()
+
+				ctx.EXPECT().Request().Return(nil)
+			},
+			assert: func(t *testing.T, err error, _ *subject.Subject, _ map[string]any) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrRateLimit)
+				require.NotErrorIs(t, err, heimdall.ErrAuthorization)
+
+				var retryAfterErr *heimdall.RetryAfterError
+				require.ErrorAs(t, err, &retryAfterErr)
+				assert.Equal(t, "120", retryAfterErr.RetryAfter)
+
+				var identifier interface{ ID() string }
+				require.ErrorAs(t, err, &identifier)
+				assert.Equal(t, "authz", identifier.ID())
+			},
+		},
 		"with unsupported response content type": {
 			authorizer: &remoteAuthorizer{
 				id: "foo",
@@ -1172,6 +1212,56 @@ func TestRemoteAuthorizerExecute(t *testing.T) {
 				assert.Contains(t, authorizerAttrs["groups"], "Foo-Users")
 			},
 		},
+		"with expression on xml response, which succeeds": {
+			authorizer: &remoteAuthorizer{
+				id: "authorizer",
+				e:  endpoint.Endpoint{URL: srv.URL},
+				expressions: func() []*cellib.CompiledExpression {
+					exp, err := cellib.CompileExpression(env,
+						`Payload.decision.result == "permit" && "read_foo" in Payload.decision.permission`, "err")
+					require.NoError(t, err)
+
+					return []*cellib.CompiledExpression{exp}
+				}(),
+			},
+			subject: &subject.Subject{
+				ID:         "my-id",
+				Attributes: map[string]any{},
+			},
+			instructServer: func(t *testing.T) {
+				t.Helper()
+
+				checkRequest = func(*http.Request) {}
+
+				responseCode = http.StatusOK
+				responseContent = []byte(`<?xml version="1.0"?>
+<decision>
+  <result>permit</result>
+  <permission>read_foo</permission>
+  <permission>write_foo</permission>
+</decision>`)
+				responseContentType = "text/xml"
+			},
+			configureContext: func(t *testing.T, ctx *heimdallmocks.RequestContextMock) {
+				t.Helper()
+
+				ctx.EXPECT().Request().Return(nil)
+			},
+			assert: func(t *testing.T, err error, _ *subject.Subject, outputs map[string]any) {
+				t.Helper()
+
+				assert.True(t, authorizationEndpointCalled)
+
+				require.NoError(t, err)
+
+				assert.Equal(t, map[string]any{
+					"decision": map[string]any{
+						"result":     "permit",
+						"permission": []any{"read_foo", "write_foo"},
+					},
+				}, outputs["authorizer"])
+			},
+		},
 		"with payload rendering error": {
 			authorizer: &remoteAuthorizer{
 				id: "authorizer",
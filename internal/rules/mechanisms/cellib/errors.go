@@ -162,9 +162,18 @@ func (errorsLib) CompileOptions() []cel.EnvOption {
 		cel.Constant("authorization_error", cel.DynType,
 			ErrorType{types: []error{heimdall.ErrAuthorization}}),
 		cel.Constant("communication_error", cel.DynType,
-			ErrorType{types: []error{heimdall.ErrCommunication, heimdall.ErrCommunicationTimeout}}),
+			ErrorType{types: []error{
+				heimdall.ErrCommunication, heimdall.ErrCommunicationTimeout,
+				heimdall.ErrRateLimit, heimdall.ErrServiceUnavailable, heimdall.ErrGraphQL,
+			}}),
+		cel.Constant("graphql_error", cel.DynType,
+			ErrorType{types: []error{heimdall.ErrGraphQL}}),
 		cel.Constant("internal_error", cel.DynType,
 			ErrorType{types: []error{heimdall.ErrInternal, heimdall.ErrConfiguration}}),
+		cel.Constant("rate_limit_error", cel.DynType,
+			ErrorType{types: []error{heimdall.ErrRateLimit}}),
+		cel.Constant("service_unavailable_error", cel.DynType,
+			ErrorType{types: []error{heimdall.ErrServiceUnavailable}}),
 		cel.Constant("precondition_error", cel.DynType,
 			ErrorType{types: []error{heimdall.ErrArgument}}),
 	}
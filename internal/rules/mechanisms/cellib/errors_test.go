@@ -57,6 +57,9 @@ func TestErrors(t *testing.T) {
 		{expr: `Error.Source == "test"`},
 		{expr: `Error == Error`},
 		{expr: `type(communication_error) != type(Error)`},
+		{expr: `type(Error) != rate_limit_error`},
+		{expr: `type(Error) != service_unavailable_error`},
+		{expr: `type(Error) != graphql_error`},
 	} {
 		t.Run(tc.expr, func(t *testing.T) {
 			ast, iss := env.Compile(tc.expr)
@@ -85,6 +88,42 @@ func TestErrors(t *testing.T) {
 	}
 }
 
+func TestUnavailabilityErrors(t *testing.T) {
+	t.Parallel()
+
+	env, err := cel.NewEnv(
+		Errors(),
+	)
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		err  error
+		expr string
+	}{
+		{err: heimdall.ErrRateLimit, expr: `type(Error) == rate_limit_error`},
+		{err: heimdall.ErrRateLimit, expr: `type(Error) == communication_error`},
+		{err: heimdall.ErrRateLimit, expr: `type(Error) != service_unavailable_error`},
+		{err: heimdall.ErrServiceUnavailable, expr: `type(Error) == service_unavailable_error`},
+		{err: heimdall.ErrServiceUnavailable, expr: `type(Error) == communication_error`},
+		{err: heimdall.ErrCommunication, expr: `type(Error) != rate_limit_error`},
+		{err: heimdall.ErrGraphQL, expr: `type(Error) == graphql_error`},
+		{err: heimdall.ErrGraphQL, expr: `type(Error) == communication_error`},
+		{err: heimdall.ErrCommunication, expr: `type(Error) != graphql_error`},
+	} {
+		t.Run(tc.expr, func(t *testing.T) {
+			ast, iss := env.Compile(tc.expr)
+			require.NoError(t, iss.Err())
+
+			prg, err := env.Program(ast)
+			require.NoError(t, err)
+
+			out, _, err := prg.Eval(map[string]any{"Error": WrapError(errorchain.New(tc.err))})
+			require.NoError(t, err)
+			require.Equal(t, true, out.Value()) //nolint:testifylint
+		})
+	}
+}
+
 func TestWrapError(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,66 @@
+// Copyright 2025 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cellib
+
+import (
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+
+	"github.com/dadrus/heimdall/internal/geoip"
+)
+
+// GeoIP provides the geo function resolving ip addresses using the given resolver. If the
+// resolver is nil, as no geoip databases are configured, the function results in an error.
+func GeoIP(resolver geoip.Resolver) cel.EnvOption {
+	return cel.Lib(geoipLib{r: resolver})
+}
+
+type geoipLib struct {
+	r geoip.Resolver
+}
+
+func (geoipLib) LibraryName() string {
+	return "dadrus.heimdall.geoip"
+}
+
+func (geoipLib) ProgramOptions() []cel.ProgramOption {
+	return []cel.ProgramOption{}
+}
+
+func (l geoipLib) CompileOptions() []cel.EnvOption {
+	return []cel.EnvOption{
+		cel.Function("geo",
+			cel.Overload("geo_string",
+				[]*cel.Type{cel.StringType}, cel.MapType(cel.StringType, cel.DynType),
+				cel.UnaryBinding(func(ipVal ref.Val) ref.Val {
+					if l.r == nil {
+						return types.WrapErr(geoip.ErrNotConfigured)
+					}
+
+					// nolint: forcetypeassert
+					record, err := l.r.Lookup(ipVal.Value().(string))
+					if err != nil {
+						return types.WrapErr(err)
+					}
+
+					return types.NewStringInterfaceMap(types.DefaultTypeAdapter, record.ToMap())
+				}),
+			),
+		),
+	}
+}
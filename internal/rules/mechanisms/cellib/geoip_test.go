@@ -0,0 +1,83 @@
+// Copyright 2025 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cellib
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/google/cel-go/cel"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dadrus/heimdall/internal/geoip"
+	"github.com/dadrus/heimdall/internal/watcher"
+	"github.com/dadrus/heimdall/internal/x/testsupport"
+)
+
+func TestGeoIP(t *testing.T) {
+	t.Parallel()
+
+	compile := func(t *testing.T, resolver geoip.Resolver, expr string) cel.Program {
+		t.Helper()
+
+		env, err := cel.NewEnv(GeoIP(resolver))
+		require.NoError(t, err)
+
+		ast, iss := env.Compile(expr)
+		require.NoError(t, iss.Err())
+
+		prg, err := env.Program(ast, cel.EvalOptions(cel.OptOptimize))
+		require.NoError(t, err)
+
+		return prg
+	}
+
+	// without configured database
+	_, _, err := compile(t, nil, `geo("192.0.2.1").country == "DE"`).Eval(map[string]any{})
+	require.ErrorContains(t, err, geoip.ErrNotConfigured.Error())
+
+	// with configured database
+	dbFile := filepath.Join(t.TempDir(), "geo.mmdb")
+	err = testsupport.WriteGeoIPDatabase(dbFile, testsupport.GeoIPEntry{
+		Network: "192.0.2.0/24", Country: "DE", City: "Berlin", ASN: 3320, ASOrganization: "Deutsche Telekom AG",
+	})
+	require.NoError(t, err)
+
+	db, err := geoip.NewDatabase([]string{dbFile}, &watcher.NoopWatcher{})
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		expr string
+	}{
+		{expr: `geo("192.0.2.1").country == "DE"`},
+		{expr: `geo("192.0.2.1").country in ["DE", "AT", "CH"]`},
+		{expr: `geo("192.0.2.1").city == "Berlin"`},
+		{expr: `geo("192.0.2.1").asn == 3320`},
+		{expr: `geo("192.0.2.1").as_organization.startsWith("Deutsche")`},
+		{expr: `geo("198.51.100.1").country == ""`},
+		{expr: `["192.0.2.1", "192.0.2.2"].all(ip, geo(ip).country == "DE")`},
+	} {
+		t.Run(tc.expr, func(t *testing.T) {
+			out, _, err := compile(t, db, tc.expr).Eval(map[string]any{})
+			require.NoError(t, err)
+			require.Equal(t, true, out.Value()) //nolint:testifylint
+		})
+	}
+
+	_, _, err = compile(t, db, `geo("foo").country == "DE"`).Eval(map[string]any{})
+	require.ErrorContains(t, err, geoip.ErrInvalidIPAddress.Error())
+}
@@ -22,16 +22,19 @@ import (
 	"github.com/google/cel-go/cel"
 	"github.com/google/cel-go/ext"
 
+	"github.com/dadrus/heimdall/internal/geoip"
 	"github.com/dadrus/heimdall/internal/rules/mechanisms/subject"
 )
 
-type heimdallLibrary struct{}
+type heimdallLibrary struct {
+	geo geoip.Resolver
+}
 
 func (heimdallLibrary) LibraryName() string {
 	return "dadrus.heimdall.main"
 }
 
-func (heimdallLibrary) CompileOptions() []cel.EnvOption {
+func (l heimdallLibrary) CompileOptions() []cel.EnvOption {
 	return []cel.EnvOption{
 		cel.DefaultUTCTimeZone(true),
 		cel.StdLib(),
@@ -46,6 +49,7 @@ func (heimdallLibrary) CompileOptions() []cel.EnvOption {
 		Requests(),
 		Errors(),
 		Networks(),
+		GeoIP(l.geo),
 		ext.NativeTypes(reflect.TypeOf(&subject.Subject{})),
 		cel.Variable("Payload", cel.DynType),
 		cel.Variable("Subject", cel.DynType),
@@ -57,6 +61,8 @@ func (heimdallLibrary) ProgramOptions() []cel.ProgramOption {
 	return []cel.ProgramOption{}
 }
 
-func Library() cel.EnvOption {
-	return cel.Lib(heimdallLibrary{})
+// Library provides all the heimdall specific CEL extensions. The given resolver is used by the
+// geo function and can be nil if no geoip databases are configured.
+func Library(geo geoip.Resolver) cel.EnvOption {
+	return cel.Lib(heimdallLibrary{geo: geo})
 }
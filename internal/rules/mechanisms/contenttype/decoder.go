@@ -18,10 +18,14 @@ package contenttype
 
 import (
 	"errors"
+	"mime"
 	"strings"
 )
 
-var ErrUnsupportedContentType = errors.New("unsupported mime type")
+var (
+	ErrUnsupportedContentType = errors.New("unsupported mime type")
+	ErrMissingBoundary        = errors.New("multipart boundary not specified")
+)
 
 type Decoder interface {
 	Decode(data []byte) (map[string]any, error)
@@ -39,3 +43,30 @@ func NewDecoder(contentType string) (Decoder, error) {
 		return nil, ErrUnsupportedContentType
 	}
 }
+
+// NewResponseDecoder returns a decoder for responses of the services contacted by the mechanisms.
+// In addition to the formats supported by NewDecoder, it supports XML and multipart responses.
+// Plain text responses are decoded as key/value pairs only if plainTextKeyValues is set, as
+// otherwise e.g. opaque tokens, URLs, or messages containing "=" or ":" would be split.
+func NewResponseDecoder(contentType string, plainTextKeyValues bool) (Decoder, error) {
+	switch {
+	case strings.HasPrefix(contentType, "multipart/"):
+		_, params, err := mime.ParseMediaType(contentType)
+		if err != nil {
+			return nil, err
+		}
+
+		boundary := params["boundary"]
+		if len(boundary) == 0 {
+			return nil, ErrMissingBoundary
+		}
+
+		return MultipartDecoder{Boundary: boundary, PlainTextKeyValues: plainTextKeyValues}, nil
+	case strings.Contains(contentType, "xml"):
+		return XMLDecoder{}, nil
+	case plainTextKeyValues && strings.HasPrefix(contentType, "text/plain"):
+		return PlainTextDecoder{}, nil
+	default:
+		return NewDecoder(contentType)
+	}
+}
@@ -0,0 +1,103 @@
+// Copyright 2025 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package contenttype
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewResponseDecoder(t *testing.T) {
+	t.Parallel()
+
+	for uc, tc := range map[string]struct {
+		contentType        string
+		plainTextKeyValues bool
+		expected           Decoder
+		err                error
+	}{
+		"json":            {contentType: "application/json", expected: JSONDecoder{}},
+		"xml":             {contentType: "application/xml; charset=utf-8", expected: XMLDecoder{}},
+		"xml with suffix": {contentType: "application/soap+xml", expected: XMLDecoder{}},
+		"plain text":      {contentType: "text/plain; charset=utf-8", err: ErrUnsupportedContentType},
+		"plain text with key/value pairs": {
+			contentType:        "text/plain; charset=utf-8",
+			plainTextKeyValues: true,
+			expected:           PlainTextDecoder{},
+		},
+		"multipart": {contentType: "multipart/mixed; boundary=foo", expected: MultipartDecoder{Boundary: "foo"}},
+		"multipart with plain text key/value pairs": {
+			contentType:        "multipart/mixed; boundary=foo",
+			plainTextKeyValues: true,
+			expected:           MultipartDecoder{Boundary: "foo", PlainTextKeyValues: true},
+		},
+		"multipart without boundary": {contentType: "multipart/mixed", err: ErrMissingBoundary},
+		"unsupported":                {contentType: "text/html", err: ErrUnsupportedContentType},
+	} {
+		t.Run(uc, func(t *testing.T) {
+			decoder, err := NewResponseDecoder(tc.contentType, tc.plainTextKeyValues)
+
+			if tc.err != nil {
+				require.ErrorIs(t, err, tc.err)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tc.expected, decoder)
+			}
+		})
+	}
+}
+
+func TestXMLDecoderDecode(t *testing.T) {
+	t.Parallel()
+
+	for uc, tc := range map[string]struct {
+		data     string
+		expected map[string]any
+		err      bool
+	}{
+		"simple element": {
+			data:     `<status>ok</status>`,
+			expected: map[string]any{"status": "ok"},
+		},
+		"element with attributes and text": {
+			data:     `<ns:user xmlns:ns="urn:foo" ns:id="1" role="admin">  Foo </ns:user>`,
+			expected: map[string]any{"user": map[string]any{"@id": "1", "@role": "admin", "#text": "Foo"}},
+		},
+		"nested and repeated elements": {
+			data: `<?xml version="1.0"?><!-- comment --><a><b><c>1</c></b><b><c>2</c></b><d/></a>`,
+			expected: map[string]any{"a": map[string]any{
+				"b": []any{map[string]any{"c": "1"}, map[string]any{"c": "2"}},
+				"d": "",
+			}},
+		},
+		"empty document":     {data: ``, err: true},
+		"malformed document": {data: `<a><b></a>`, err: true},
+	} {
+		t.Run(uc, func(t *testing.T) {
+			result, err := XMLDecoder{}.Decode([]byte(tc.data))
+
+			if tc.err {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tc.expected, result)
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+// Copyright 2025 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package contenttype
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"mime/multipart"
+	"strconv"
+	"strings"
+
+	"github.com/dadrus/heimdall/internal/x/stringx"
+)
+
+// MultipartDecoder decodes multipart data into a map. The parts are referenced by their form
+// names, or, if not available, by their content ids, or by their indices. The contents of the parts
+// are decoded according to their content types if supported and are made available as strings
+// otherwise. Parts with the same name are represented as lists. Plain text parts are decoded as
+// key/value pairs only if PlainTextKeyValues is set.
+type MultipartDecoder struct {
+	Boundary           string
+	PlainTextKeyValues bool
+}
+
+func (d MultipartDecoder) Decode(rawData []byte) (map[string]any, error) {
+	result := make(map[string]any)
+	reader := multipart.NewReader(bytes.NewReader(rawData), d.Boundary)
+
+	for idx := 0; ; idx++ {
+		part, err := reader.NextPart()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return result, nil
+			}
+
+			return nil, err
+		}
+
+		value, err := d.decodePart(part)
+		if err != nil {
+			return nil, err
+		}
+
+		addValue(result, partName(part, idx), value)
+	}
+}
+
+func partName(part *multipart.Part, idx int) string {
+	if name := part.FormName(); len(name) != 0 {
+		return name
+	}
+
+	if id := strings.Trim(part.Header.Get("Content-Id"), "<>"); len(id) != 0 {
+		return id
+	}
+
+	return strconv.Itoa(idx)
+}
+
+func (d MultipartDecoder) decodePart(part *multipart.Part) (any, error) {
+	defer part.Close()
+
+	data, err := io.ReadAll(part)
+	if err != nil {
+		return nil, err
+	}
+
+	decoder, err := NewResponseDecoder(part.Header.Get("Content-Type"), d.PlainTextKeyValues)
+	if err != nil {
+		return stringx.ToString(data), nil //nolint:nilerr
+	}
+
+	value, err := decoder.Decode(data)
+	if errors.Is(err, ErrNoKeyValuePairs) {
+		return stringx.ToString(data), nil
+	}
+
+	return value, err
+}
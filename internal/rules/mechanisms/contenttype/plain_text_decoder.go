@@ -0,0 +1,60 @@
+// Copyright 2025 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package contenttype
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"strings"
+)
+
+var ErrNoKeyValuePairs = errors.New("data is not formatted as key/value pairs")
+
+// PlainTextDecoder decodes plain text data consisting of lines with key/value pairs separated
+// by either "=" or ":", whatever comes first. Empty lines and lines starting with "#" are ignored.
+// Both, the keys and the values are trimmed. If a key is present multiple times, the last value wins.
+type PlainTextDecoder struct{}
+
+func (PlainTextDecoder) Decode(rawData []byte) (map[string]any, error) {
+	result := make(map[string]any)
+
+	scanner := bufio.NewScanner(bytes.NewReader(rawData))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if len(line) == 0 || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		idx := strings.IndexAny(line, "=:")
+		if idx <= 0 {
+			return nil, ErrNoKeyValuePairs
+		}
+
+		result[strings.TrimSpace(line[:idx])] = strings.TrimSpace(line[idx+1:])
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(result) == 0 {
+		return nil, ErrNoKeyValuePairs
+	}
+
+	return result, nil
+}
@@ -0,0 +1,117 @@
+// Copyright 2025 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package contenttype
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"io"
+	"strings"
+)
+
+var ErrMalformedXML = errors.New("malformed xml document")
+
+// XMLDecoder decodes XML documents into a map with the name of the root element as the only key.
+// Elements without attributes and child elements are represented by their text content. All other
+// elements are represented as maps, with attributes prefixed by "@", child elements referenced by
+// their names, and the text content, if present, available under the "#text" key. Repeated child
+// elements are represented as lists. Namespaces are ignored.
+type XMLDecoder struct{}
+
+func (XMLDecoder) Decode(rawData []byte) (map[string]any, error) {
+	dec := xml.NewDecoder(bytes.NewReader(rawData))
+
+	for {
+		token, err := dec.Token()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil, ErrMalformedXML
+			}
+
+			return nil, err
+		}
+
+		if start, ok := token.(xml.StartElement); ok {
+			value, err := decodeXMLElement(dec, start)
+			if err != nil {
+				return nil, err
+			}
+
+			return map[string]any{start.Name.Local: value}, nil
+		}
+	}
+}
+
+func decodeXMLElement(dec *xml.Decoder, start xml.StartElement) (any, error) {
+	var text strings.Builder
+
+	result := make(map[string]any, len(start.Attr))
+	for _, attr := range start.Attr {
+		if attr.Name.Space == "xmlns" || attr.Name.Local == "xmlns" {
+			continue
+		}
+
+		result["@"+attr.Name.Local] = attr.Value
+	}
+
+	for {
+		token, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+
+		switch tok := token.(type) {
+		case xml.StartElement:
+			value, err := decodeXMLElement(dec, tok)
+			if err != nil {
+				return nil, err
+			}
+
+			addValue(result, tok.Name.Local, value)
+		case xml.CharData:
+			text.Write(tok)
+		case xml.EndElement:
+			content := strings.TrimSpace(text.String())
+
+			if len(result) == 0 {
+				return content, nil
+			}
+
+			if len(content) != 0 {
+				result["#text"] = content
+			}
+
+			return result, nil
+		}
+	}
+}
+
+func addValue(result map[string]any, key string, value any) {
+	existing, present := result[key]
+	if !present {
+		result[key] = value
+
+		return
+	}
+
+	if list, ok := existing.([]any); ok {
+		result[key] = append(list, value)
+	} else {
+		result[key] = []any{existing, value}
+	}
+}
@@ -18,4 +18,8 @@ package contextualizers
 
 const (
 	ContextualizerGeneric = "generic"
+	ContextualizerGeoIP   = "geoip"
+	ContextualizerGRPC    = "grpc"
+	ContextualizerGraphQL = "graphql"
+	ContextualizerLookup  = "lookup"
 )
@@ -31,7 +31,7 @@ func TestCreateContextualzerPrototype(t *testing.T) {
 	t.Parallel()
 
 	// there are 3 error handlers implemented, which should have been registered
-	require.Len(t, typeFactories, 1)
+	require.Len(t, typeFactories, 5)
 
 	for _, tc := range []struct {
 		uc     string
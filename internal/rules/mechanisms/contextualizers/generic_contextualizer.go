@@ -79,6 +79,7 @@ type genericContextualizer struct {
 	fwdCookies      []string
 	continueOnError bool
 	v               values.Values
+	kvPlainText     bool
 }
 
 func newGenericContextualizer(
@@ -97,6 +98,7 @@ func newGenericContextualizer(
 		CacheTTL        *time.Duration    `mapstructure:"cache_ttl"`
 		ContinueOnError bool              `mapstructure:"continue_pipeline_on_error"`
 		Values          values.Values     `mapstructure:"values"`
+		KVPlainText     bool              `mapstructure:"plain_text_key_values"`
 	}
 
 	var conf Config
@@ -125,6 +127,7 @@ func newGenericContextualizer(
 		ttl:             ttl,
 		continueOnError: conf.ContinueOnError,
 		v:               conf.Values,
+		kvPlainText:     conf.KVPlainText,
 	}, nil
 }
 
@@ -219,7 +222,8 @@ func (c *genericContextualizer) WithConfig(rawConfig map[string]any) (Contextual
 		continueOnError: x.IfThenElseExec(conf.ContinueOnError != nil,
 			func() bool { return *conf.ContinueOnError },
 			func() bool { return c.continueOnError }),
-		v: c.v.Merge(conf.Values),
+		v:           c.v.Merge(conf.Values),
+		kvPlainText: c.kvPlainText,
 	}, nil
 }
 
@@ -323,6 +327,15 @@ func (c *genericContextualizer) createRequest(
 func (c *genericContextualizer) readResponse(ctx heimdall.RequestContext, resp *http.Response) (any, error) {
 	logger := zerolog.Ctx(ctx.Context())
 
+	if err := endpoint.UnavailabilityError(resp); err != nil {
+		var chain *errorchain.ErrorChain
+		if errors.As(err, &chain) {
+			chain.WithErrorContext(c)
+		}
+
+		return nil, err
+	}
+
 	if !(resp.StatusCode >= http.StatusOK && resp.StatusCode < http.StatusMultipleChoices) {
 		return nil, errorchain.NewWithMessagef(heimdall.ErrCommunication,
 			"unexpected response code: %v", resp.StatusCode).
@@ -346,7 +359,7 @@ func (c *genericContextualizer) readResponse(ctx heimdall.RequestContext, resp *
 
 	logger.Debug().Str("_content_type", contentType).Msg("Response received")
 
-	decoder, err := contenttype.NewDecoder(contentType)
+	decoder, err := contenttype.NewResponseDecoder(contentType, c.kvPlainText)
 	if err != nil {
 		logger.Warn().Str("_content_type", contentType).
 			Msg("Content type is not supported. Treating it as string")
@@ -355,6 +368,12 @@ func (c *genericContextualizer) readResponse(ctx heimdall.RequestContext, resp *
 	}
 
 	result, err := decoder.Decode(rawData)
+	if errors.Is(err, contenttype.ErrNoKeyValuePairs) {
+		logger.Debug().Msg("Response does not contain key/value pairs. Treating it as string")
+
+		return stringx.ToString(rawData), nil
+	}
+
 	if err != nil {
 		return nil, errorchain.NewWithMessage(heimdall.ErrInternal, "failed to unmarshal response").
 			WithErrorContext(c).
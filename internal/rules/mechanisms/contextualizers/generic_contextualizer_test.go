@@ -107,6 +107,7 @@ payload: bar
 				assert.Empty(t, contextualizer.fwdHeaders)
 				assert.Equal(t, defaultTTL, contextualizer.ttl)
 				assert.False(t, contextualizer.ContinueOnError())
+				assert.False(t, contextualizer.kvPlainText)
 
 				assert.Equal(t, "contextualizer", contextualizer.ID())
 				assert.False(t, contextualizer.ContinueOnError())
@@ -141,6 +142,7 @@ cache_ttl: 5s
 values:
   foo: "{{ .Subject.ID }}"
 continue_pipeline_on_error: true
+plain_text_key_values: true
 `),
 			assert: func(t *testing.T, err error, contextualizer *genericContextualizer) {
 				t.Helper()
@@ -167,6 +169,7 @@ continue_pipeline_on_error: true
 				})
 				require.NoError(t, err)
 				assert.Equal(t, map[string]string{"foo": "bar"}, res)
+				assert.True(t, contextualizer.kvPlainText)
 
 				assert.Equal(t, "contextualizer", contextualizer.ID())
 				assert.True(t, contextualizer.ContinueOnError())
@@ -771,6 +774,204 @@ func TestGenericContextualizerExecute(t *testing.T) {
 				assert.Len(t, outputs, 1)
 			},
 		},
+		"with xml response": {
+			contextualizer: &genericContextualizer{
+				id: "test-contextualizer",
+				e:  endpoint.Endpoint{URL: srv.URL + "/{{ .Subject.ID }}"},
+			},
+			subject: &subject.Subject{ID: "Foo", Attributes: map[string]any{"bar": "baz"}},
+			configureContext: func(t *testing.T, ctx *heimdallmocks.RequestContextMock) {
+				t.Helper()
+
+				ctx.EXPECT().Request().Return(nil)
+			},
+			instructServer: func(t *testing.T) {
+				t.Helper()
+
+				checkRequest = func(*http.Request) {}
+
+				responseContentType = "application/xml; charset=utf-8"
+				responseContent = []byte(`<user id="Foo"><group>admin</group><group>dev</group><name>Foo</name></user>`)
+				responseCode = http.StatusOK
+			},
+			assert: func(t *testing.T, err error, _ *subject.Subject, outputs map[string]any) {
+				t.Helper()
+
+				assert.True(t, remoteEndpointCalled)
+
+				require.NoError(t, err)
+
+				assert.Equal(t, map[string]any{
+					"user": map[string]any{
+						"@id":   "Foo",
+						"group": []any{"admin", "dev"},
+						"name":  "Foo",
+					},
+				}, outputs["test-contextualizer"])
+			},
+		},
+		"with plain text key/value response": {
+			contextualizer: &genericContextualizer{
+				id:          "test-contextualizer",
+				e:           endpoint.Endpoint{URL: srv.URL + "/{{ .Subject.ID }}"},
+				kvPlainText: true,
+			},
+			subject: &subject.Subject{ID: "Foo", Attributes: map[string]any{"bar": "baz"}},
+			configureContext: func(t *testing.T, ctx *heimdallmocks.RequestContextMock) {
+				t.Helper()
+
+				ctx.EXPECT().Request().Return(nil)
+			},
+			instructServer: func(t *testing.T) {
+				t.Helper()
+
+				checkRequest = func(*http.Request) {}
+
+				responseContentType = "text/plain"
+				responseContent = []byte("# legacy response\nplan=enterprise\nregion: eu\n")
+				responseCode = http.StatusOK
+			},
+			assert: func(t *testing.T, err error, _ *subject.Subject, outputs map[string]any) {
+				t.Helper()
+
+				assert.True(t, remoteEndpointCalled)
+
+				require.NoError(t, err)
+
+				assert.Equal(t, map[string]any{"plan": "enterprise", "region": "eu"}, outputs["test-contextualizer"])
+			},
+		},
+		"with plain text response without key/value decoding being enabled": {
+			contextualizer: &genericContextualizer{
+				id: "test-contextualizer",
+				e:  endpoint.Endpoint{URL: srv.URL + "/{{ .Subject.ID }}"},
+			},
+			subject: &subject.Subject{ID: "Foo", Attributes: map[string]any{"bar": "baz"}},
+			configureContext: func(t *testing.T, ctx *heimdallmocks.RequestContextMock) {
+				t.Helper()
+
+				ctx.EXPECT().Request().Return(nil)
+			},
+			instructServer: func(t *testing.T) {
+				t.Helper()
+
+				checkRequest = func(*http.Request) {}
+
+				responseContentType = "text/plain"
+				responseContent = []byte("dG9rZW4=")
+				responseCode = http.StatusOK
+			},
+			assert: func(t *testing.T, err error, _ *subject.Subject, outputs map[string]any) {
+				t.Helper()
+
+				assert.True(t, remoteEndpointCalled)
+
+				require.NoError(t, err)
+
+				assert.Equal(t, "dG9rZW4=", outputs["test-contextualizer"])
+			},
+		},
+		"with plain text response not containing key/value pairs": {
+			contextualizer: &genericContextualizer{
+				id:          "test-contextualizer",
+				e:           endpoint.Endpoint{URL: srv.URL + "/{{ .Subject.ID }}"},
+				kvPlainText: true,
+			},
+			subject: &subject.Subject{ID: "Foo", Attributes: map[string]any{"bar": "baz"}},
+			configureContext: func(t *testing.T, ctx *heimdallmocks.RequestContextMock) {
+				t.Helper()
+
+				ctx.EXPECT().Request().Return(nil)
+			},
+			instructServer: func(t *testing.T) {
+				t.Helper()
+
+				checkRequest = func(*http.Request) {}
+
+				responseContentType = "text/plain"
+				responseContent = []byte("Hi from endpoint")
+				responseCode = http.StatusOK
+			},
+			assert: func(t *testing.T, err error, _ *subject.Subject, outputs map[string]any) {
+				t.Helper()
+
+				assert.True(t, remoteEndpointCalled)
+
+				require.NoError(t, err)
+
+				assert.Equal(t, "Hi from endpoint", outputs["test-contextualizer"])
+			},
+		},
+		"with multipart response": {
+			contextualizer: &genericContextualizer{
+				id: "test-contextualizer",
+				e:  endpoint.Endpoint{URL: srv.URL + "/{{ .Subject.ID }}"},
+			},
+			subject: &subject.Subject{ID: "Foo", Attributes: map[string]any{"bar": "baz"}},
+			configureContext: func(t *testing.T, ctx *heimdallmocks.RequestContextMock) {
+				t.Helper()
+
+				ctx.EXPECT().Request().Return(nil)
+			},
+			instructServer: func(t *testing.T) {
+				t.Helper()
+
+				checkRequest = func(*http.Request) {}
+
+				responseContentType = "multipart/form-data; boundary=foobar"
+				responseContent = []byte("--foobar\r\n" +
+					"Content-Disposition: form-data; name=\"profile\"\r\n" +
+					"Content-Type: application/json\r\n\r\n" +
+					`{"name": "Foo"}` + "\r\n" +
+					"--foobar\r\n" +
+					"Content-Disposition: form-data; name=\"note\"\r\n\r\n" +
+					"Hello\r\n" +
+					"--foobar--\r\n")
+				responseCode = http.StatusOK
+			},
+			assert: func(t *testing.T, err error, _ *subject.Subject, outputs map[string]any) {
+				t.Helper()
+
+				assert.True(t, remoteEndpointCalled)
+
+				require.NoError(t, err)
+
+				assert.Equal(t, map[string]any{
+					"profile": map[string]any{"name": "Foo"},
+					"note":    "Hello",
+				}, outputs["test-contextualizer"])
+			},
+		},
+		"with malformed xml response": {
+			contextualizer: &genericContextualizer{
+				id: "test-contextualizer",
+				e:  endpoint.Endpoint{URL: srv.URL + "/{{ .Subject.ID }}"},
+			},
+			subject: &subject.Subject{ID: "Foo", Attributes: map[string]any{"bar": "baz"}},
+			configureContext: func(t *testing.T, ctx *heimdallmocks.RequestContextMock) {
+				t.Helper()
+
+				ctx.EXPECT().Request().Return(nil)
+			},
+			instructServer: func(t *testing.T) {
+				t.Helper()
+
+				checkRequest = func(*http.Request) {}
+
+				responseContentType = "application/xml"
+				responseContent = []byte(`<user><name>Foo</user>`)
+				responseCode = http.StatusOK
+			},
+			assert: func(t *testing.T, err error, _ *subject.Subject, _ map[string]any) {
+				t.Helper()
+
+				assert.True(t, remoteEndpointCalled)
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrInternal)
+				require.ErrorContains(t, err, "failed to unmarshal response")
+			},
+		},
 		"without payload, but with cache": {
 			contextualizer: &genericContextualizer{
 				id:  "test-contextualizer",
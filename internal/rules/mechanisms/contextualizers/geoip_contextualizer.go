@@ -0,0 +1,182 @@
+// Copyright 2025 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package contextualizers
+
+import (
+	"net"
+	"slices"
+	"strings"
+
+	"github.com/rs/zerolog"
+
+	"github.com/dadrus/heimdall/internal/app"
+	"github.com/dadrus/heimdall/internal/geoip"
+	"github.com/dadrus/heimdall/internal/heimdall"
+	"github.com/dadrus/heimdall/internal/rules/mechanisms/subject"
+	"github.com/dadrus/heimdall/internal/x"
+	"github.com/dadrus/heimdall/internal/x/errorchain"
+)
+
+// by intention. Used only during application bootstrap
+//
+//nolint:gochecknoinits
+func init() {
+	registerTypeFactory(
+		func(app app.Context, id string, typ string, conf map[string]any) (bool, Contextualizer, error) {
+			if typ != ContextualizerGeoIP {
+				return false, nil, nil
+			}
+
+			eh, err := newGeoIPContextualizer(app, id, conf)
+
+			return true, eh, err
+		})
+}
+
+type geoipContextualizer struct {
+	id              string
+	app             app.Context
+	r               geoip.Resolver
+	trustedProxies  []*net.IPNet
+	continueOnError bool
+}
+
+func newGeoIPContextualizer(app app.Context, id string, rawConfig map[string]any) (*geoipContextualizer, error) {
+	logger := app.Logger()
+	logger.Info().Str("_id", id).Msg("Creating geoip contextualizer")
+
+	type Config struct {
+		ContinueOnError bool `mapstructure:"continue_pipeline_on_error"`
+	}
+
+	var conf Config
+	if err := decodeConfig(app, rawConfig, &conf); err != nil {
+		return nil, errorchain.NewWithMessagef(heimdall.ErrConfiguration,
+			"failed decoding config for geoip contextualizer '%s'", id).CausedBy(err)
+	}
+
+	resolver := app.GeoIP()
+	if resolver == nil {
+		return nil, errorchain.NewWithMessagef(heimdall.ErrConfiguration,
+			"geoip contextualizer '%s' requires geoip databases to be configured", id)
+	}
+
+	return &geoipContextualizer{
+		id:              id,
+		app:             app,
+		r:               resolver,
+		trustedProxies:  parseNetworks(app.Config().Serve.TrustedProxies),
+		continueOnError: conf.ContinueOnError,
+	}, nil
+}
+
+func (c *geoipContextualizer) Execute(ctx heimdall.RequestContext, _ *subject.Subject) error {
+	logger := zerolog.Ctx(ctx.Context())
+	logger.Debug().Str("_id", c.id).Msg("Updating using geoip contextualizer")
+
+	clientIP := c.clientIP(ctx.Request().ClientIPAddresses)
+	if len(clientIP) == 0 {
+		return errorchain.NewWithMessage(heimdall.ErrArgument, "no valid client ip address available").
+			WithErrorContext(c)
+	}
+
+	record, err := c.r.Lookup(clientIP)
+	if err != nil {
+		return errorchain.NewWithMessagef(heimdall.ErrInternal, "failed resolving %s", clientIP).
+			WithErrorContext(c).
+			CausedBy(err)
+	}
+
+	result := record.ToMap()
+	result["ip"] = clientIP
+
+	ctx.Outputs()[c.id] = result
+
+	return nil
+}
+
+func (c *geoipContextualizer) WithConfig(rawConfig map[string]any) (Contextualizer, error) {
+	if len(rawConfig) == 0 {
+		return c, nil
+	}
+
+	type Config struct {
+		ContinueOnError bool `mapstructure:"continue_pipeline_on_error"`
+	}
+
+	var conf Config
+	if err := decodeConfig(c.app, rawConfig, &conf); err != nil {
+		return nil, errorchain.NewWithMessagef(heimdall.ErrConfiguration,
+			"failed decoding config for geoip contextualizer '%s'", c.id).CausedBy(err)
+	}
+
+	return &geoipContextualizer{
+		id:              c.id,
+		app:             c.app,
+		r:               c.r,
+		trustedProxies:  c.trustedProxies,
+		continueOnError: conf.ContinueOnError,
+	}, nil
+}
+
+func (c *geoipContextualizer) ID() string { return c.id }
+
+func (c *geoipContextualizer) ContinueOnError() bool { return c.continueOnError }
+
+// clientIP returns the address of the client to resolve the geolocation for. The list of client
+// ip addresses contains the addresses from the Forwarded, or X-Forwarded-For headers, if these were
+// sent by a trusted proxy, followed by the address of the peer. As the client can set any value
+// in these headers, only the entries appended by the trusted proxies can be relied on. So the
+// client address is the rightmost entry, which is not a trusted proxy. If there is no such entry,
+// the address of the peer is used.
+func (c *geoipContextualizer) clientIP(addresses []string) string {
+	for idx := len(addresses) - 1; idx >= 0; idx-- {
+		ip := net.ParseIP(addresses[idx])
+		if ip == nil {
+			// entries to the left of an entry, which is not an ip address,
+			// cannot be attributed to a trusted proxy
+			break
+		}
+
+		if !slices.ContainsFunc(c.trustedProxies, func(network *net.IPNet) bool { return network.Contains(ip) }) {
+			return addresses[idx]
+		}
+	}
+
+	if len(addresses) != 0 && net.ParseIP(addresses[len(addresses)-1]) != nil {
+		return addresses[len(addresses)-1]
+	}
+
+	return ""
+}
+
+func parseNetworks(entries []string) []*net.IPNet {
+	networks := make([]*net.IPNet, 0, len(entries))
+
+	for _, entry := range entries {
+		if !strings.Contains(entry, "/") {
+			entry += x.IfThenElse(strings.Contains(entry, ":"), "/128", "/32")
+		}
+
+		// entries, which cannot be parsed, are ignored the same way the trusted proxy middleware does
+		if _, network, err := net.ParseCIDR(entry); err == nil {
+			networks = append(networks, network)
+		}
+	}
+
+	return networks
+}
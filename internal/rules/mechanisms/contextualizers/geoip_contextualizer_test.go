@@ -0,0 +1,267 @@
+// Copyright 2025 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package contextualizers
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dadrus/heimdall/internal/app"
+	"github.com/dadrus/heimdall/internal/config"
+	"github.com/dadrus/heimdall/internal/geoip"
+	"github.com/dadrus/heimdall/internal/heimdall"
+	heimdallmocks "github.com/dadrus/heimdall/internal/heimdall/mocks"
+	"github.com/dadrus/heimdall/internal/validation"
+	"github.com/dadrus/heimdall/internal/watcher"
+	"github.com/dadrus/heimdall/internal/x/testsupport"
+)
+
+func newGeoIPDatabase(t *testing.T) *geoip.Database {
+	t.Helper()
+
+	dbFile := filepath.Join(t.TempDir(), "geo.mmdb")
+	err := testsupport.WriteGeoIPDatabase(dbFile, testsupport.GeoIPEntry{
+		Network: "192.0.2.0/24", Country: "DE", City: "Berlin", ASN: 3320, ASOrganization: "Deutsche Telekom AG",
+	})
+	require.NoError(t, err)
+
+	db, err := geoip.NewDatabase([]string{dbFile}, &watcher.NoopWatcher{})
+	require.NoError(t, err)
+
+	return db
+}
+
+func TestCreateGeoIPContextualizer(t *testing.T) {
+	t.Parallel()
+
+	validator, err := validation.NewValidator()
+	require.NoError(t, err)
+
+	// without configured geoip databases
+	appCtx := app.NewContextMock(t)
+	appCtx.EXPECT().Validator().Maybe().Return(validator)
+	appCtx.EXPECT().Logger().Maybe().Return(log.Logger)
+	appCtx.EXPECT().GeoIP().Return(nil)
+
+	_, err = newGeoIPContextualizer(appCtx, "geo", nil)
+	require.ErrorIs(t, err, heimdall.ErrConfiguration)
+	require.ErrorContains(t, err, "requires geoip databases")
+
+	db := newGeoIPDatabase(t)
+
+	for uc, tc := range map[string]struct {
+		config []byte
+		assert func(t *testing.T, err error, contextualizer *geoipContextualizer)
+	}{
+		"with unsupported fields": {
+			config: []byte(`foo: bar`),
+			assert: func(t *testing.T, err error, _ *geoipContextualizer) {
+				t.Helper()
+
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				require.ErrorContains(t, err, "failed decoding")
+			},
+		},
+		"without configuration": {
+			assert: func(t *testing.T, err error, contextualizer *geoipContextualizer) {
+				t.Helper()
+
+				require.NoError(t, err)
+				assert.Equal(t, "geo", contextualizer.ID())
+				assert.False(t, contextualizer.ContinueOnError())
+				assert.NotNil(t, contextualizer.r)
+				require.Len(t, contextualizer.trustedProxies, 2)
+				assert.Equal(t, "10.0.0.0/8", contextualizer.trustedProxies[0].String())
+				assert.Equal(t, "192.168.1.1/32", contextualizer.trustedProxies[1].String())
+			},
+		},
+		"with continue_pipeline_on_error": {
+			config: []byte(`continue_pipeline_on_error: true`),
+			assert: func(t *testing.T, err error, contextualizer *geoipContextualizer) {
+				t.Helper()
+
+				require.NoError(t, err)
+				assert.True(t, contextualizer.ContinueOnError())
+			},
+		},
+	} {
+		t.Run(uc, func(t *testing.T) {
+			conf, err := testsupport.DecodeTestConfig(tc.config)
+			require.NoError(t, err)
+
+			appCtx := app.NewContextMock(t)
+			appCtx.EXPECT().Validator().Maybe().Return(validator)
+			appCtx.EXPECT().Logger().Maybe().Return(log.Logger)
+			appCtx.EXPECT().GeoIP().Maybe().Return(db)
+			appCtx.EXPECT().Config().Maybe().Return(&config.Configuration{
+				Serve: config.ServeConfig{TrustedProxies: []string{"10.0.0.0/8", "192.168.1.1", "invalid"}},
+			})
+
+			contextualizer, err := newGeoIPContextualizer(appCtx, "geo", conf)
+
+			tc.assert(t, err, contextualizer)
+		})
+	}
+}
+
+func TestCreateGeoIPContextualizerFromPrototype(t *testing.T) {
+	t.Parallel()
+
+	validator, err := validation.NewValidator()
+	require.NoError(t, err)
+
+	appCtx := app.NewContextMock(t)
+	appCtx.EXPECT().Validator().Maybe().Return(validator)
+	appCtx.EXPECT().Logger().Maybe().Return(log.Logger)
+	appCtx.EXPECT().GeoIP().Return(newGeoIPDatabase(t))
+	appCtx.EXPECT().Config().Return(&config.Configuration{
+		Serve: config.ServeConfig{TrustedProxies: []string{"10.0.0.0/8"}},
+	})
+
+	prototype, err := newGeoIPContextualizer(appCtx, "geo", nil)
+	require.NoError(t, err)
+
+	// without config
+	contextualizer, err := prototype.WithConfig(nil)
+	require.NoError(t, err)
+	assert.Equal(t, prototype, contextualizer)
+
+	// with unsupported config
+	_, err = prototype.WithConfig(map[string]any{"foo": "bar"})
+	require.ErrorIs(t, err, heimdall.ErrConfiguration)
+
+	// with config
+	contextualizer, err = prototype.WithConfig(map[string]any{"continue_pipeline_on_error": true})
+	require.NoError(t, err)
+	assert.NotEqual(t, prototype, contextualizer)
+	assert.Equal(t, "geo", contextualizer.ID())
+	assert.True(t, contextualizer.ContinueOnError())
+
+	configured, ok := contextualizer.(*geoipContextualizer)
+	require.True(t, ok)
+	assert.Equal(t, prototype.r, configured.r)
+	assert.Equal(t, prototype.trustedProxies, configured.trustedProxies)
+}
+
+func TestGeoIPContextualizerExecute(t *testing.T) {
+	t.Parallel()
+
+	contextualizer := &geoipContextualizer{
+		id:             "geo",
+		r:              newGeoIPDatabase(t),
+		trustedProxies: parseNetworks([]string{"10.0.0.0/8", "2001:db8::1"}),
+	}
+
+	for uc, tc := range map[string]struct {
+		ips    []string
+		assert func(t *testing.T, err error, outputs map[string]any)
+	}{
+		"without client ip addresses": {
+			assert: func(t *testing.T, err error, outputs map[string]any) {
+				t.Helper()
+
+				require.ErrorIs(t, err, heimdall.ErrArgument)
+				require.ErrorContains(t, err, "no valid client ip address")
+
+				var identifier interface{ ID() string }
+				require.ErrorAs(t, err, &identifier)
+				assert.Equal(t, "geo", identifier.ID())
+
+				assert.NotContains(t, outputs, "geo")
+			},
+		},
+		"with known client ip address": {
+			ips: []string{"192.0.2.1", "10.0.0.1"},
+			assert: func(t *testing.T, err error, outputs map[string]any) {
+				t.Helper()
+
+				require.NoError(t, err)
+				assert.Equal(t, map[string]any{
+					"ip":              "192.0.2.1",
+					"country":         "DE",
+					"city":            "Berlin",
+					"asn":             int64(3320),
+					"as_organization": "Deutsche Telekom AG",
+				}, outputs["geo"])
+			},
+		},
+		"with unknown client ip address": {
+			ips: []string{"198.51.100.1"},
+			assert: func(t *testing.T, err error, outputs map[string]any) {
+				t.Helper()
+
+				require.NoError(t, err)
+				assert.Equal(t, map[string]any{
+					"ip":              "198.51.100.1",
+					"country":         "",
+					"city":            "",
+					"asn":             int64(0),
+					"as_organization": "",
+				}, outputs["geo"])
+			},
+		},
+		"with forged X-Forwarded-For header": {
+			// the client sent X-Forwarded-For: 192.0.2.1, the trusted proxies appended the actual addresses
+			ips: []string{"192.0.2.1", "198.51.100.1", "2001:db8::1", "10.0.0.1"},
+			assert: func(t *testing.T, err error, outputs map[string]any) {
+				t.Helper()
+
+				require.NoError(t, err)
+				require.Contains(t, outputs, "geo")
+				assert.Equal(t, "198.51.100.1", outputs["geo"].(map[string]any)["ip"])
+				assert.Empty(t, outputs["geo"].(map[string]any)["country"])
+			},
+		},
+		"with entry not being an ip address": {
+			ips: []string{"192.0.2.1", "unknown", "10.0.0.2", "10.0.0.1"},
+			assert: func(t *testing.T, err error, outputs map[string]any) {
+				t.Helper()
+
+				require.NoError(t, err)
+				require.Contains(t, outputs, "geo")
+				assert.Equal(t, "10.0.0.1", outputs["geo"].(map[string]any)["ip"])
+			},
+		},
+		"with trusted proxies only": {
+			ips: []string{"10.0.0.2", "10.0.0.1"},
+			assert: func(t *testing.T, err error, outputs map[string]any) {
+				t.Helper()
+
+				require.NoError(t, err)
+				require.Contains(t, outputs, "geo")
+				assert.Equal(t, "10.0.0.1", outputs["geo"].(map[string]any)["ip"])
+			},
+		},
+	} {
+		t.Run(uc, func(t *testing.T) {
+			outputs := map[string]any{}
+
+			ctx := heimdallmocks.NewRequestContextMock(t)
+			ctx.EXPECT().Context().Return(t.Context())
+			ctx.EXPECT().Request().Return(&heimdall.Request{ClientIPAddresses: tc.ips})
+			ctx.EXPECT().Outputs().Maybe().Return(outputs)
+
+			err := contextualizer.Execute(ctx, nil)
+
+			tc.assert(t, err, outputs)
+		})
+	}
+}
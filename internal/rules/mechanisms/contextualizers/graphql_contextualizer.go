@@ -0,0 +1,467 @@
+// Copyright 2025 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package contextualizers
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/rs/zerolog"
+
+	"github.com/dadrus/heimdall/internal/app"
+	"github.com/dadrus/heimdall/internal/cache"
+	"github.com/dadrus/heimdall/internal/heimdall"
+	"github.com/dadrus/heimdall/internal/rules/endpoint"
+	"github.com/dadrus/heimdall/internal/rules/mechanisms/subject"
+	"github.com/dadrus/heimdall/internal/rules/mechanisms/template"
+	"github.com/dadrus/heimdall/internal/rules/mechanisms/values"
+	"github.com/dadrus/heimdall/internal/x"
+	"github.com/dadrus/heimdall/internal/x/errorchain"
+	"github.com/dadrus/heimdall/internal/x/stringx"
+)
+
+// by intention. Used only during application bootstrap
+//
+//nolint:gochecknoinits
+func init() {
+	registerTypeFactory(
+		func(app app.Context, id string, typ string, conf map[string]any) (bool, Contextualizer, error) {
+			if typ != ContextualizerGraphQL {
+				return false, nil, nil
+			}
+
+			eh, err := newGraphQLContextualizer(app, id, conf)
+
+			return true, eh, err
+		})
+}
+
+// graphQLError represents an entry of the errors array of a GraphQL response.
+type graphQLError struct {
+	Message    string         `json:"message"`
+	Path       []any          `json:"path,omitempty"`
+	Extensions map[string]any `json:"extensions,omitempty"`
+}
+
+// graphQLErrors is the error returned (as cause) if the GraphQL endpoint responded with errors.
+type graphQLErrors []graphQLError
+
+func (e graphQLErrors) Error() string {
+	msgs := make([]string, len(e))
+	for idx, err := range e {
+		msgs[idx] = err.Message
+	}
+
+	return strings.Join(msgs, "; ")
+}
+
+type graphQLRequest struct {
+	Query         string         `json:"query"`
+	OperationName string         `json:"operationName,omitempty"`
+	Variables     map[string]any `json:"variables,omitempty"`
+}
+
+type graphQLResponse struct {
+	Data   map[string]any `json:"data"`
+	Errors graphQLErrors  `json:"errors"`
+}
+
+type graphQLContextualizer struct {
+	id              string
+	app             app.Context
+	e               endpoint.Endpoint
+	query           string
+	operationName   string
+	variables       template.Template
+	ttl             time.Duration
+	continueOnError bool
+	v               values.Values
+}
+
+func newGraphQLContextualizer(
+	app app.Context,
+	id string,
+	rawConfig map[string]any,
+) (*graphQLContextualizer, error) {
+	logger := app.Logger()
+	logger.Info().Str("_id", id).Msg("Creating graphql contextualizer")
+
+	type Config struct {
+		Endpoint        endpoint.Endpoint `mapstructure:"endpoint"                   validate:"required"`
+		Query           string            `mapstructure:"query"                      validate:"required"`
+		OperationName   string            `mapstructure:"operation_name"`
+		Variables       template.Template `mapstructure:"variables"`
+		CacheTTL        *time.Duration    `mapstructure:"cache_ttl"`
+		ContinueOnError bool              `mapstructure:"continue_pipeline_on_error"`
+		Values          values.Values     `mapstructure:"values"`
+	}
+
+	var conf Config
+	if err := decodeConfig(app, rawConfig, &conf); err != nil {
+		return nil, errorchain.NewWithMessagef(heimdall.ErrConfiguration,
+			"failed decoding config for graphql contextualizer '%s'", id).CausedBy(err)
+	}
+
+	if strings.HasPrefix(conf.Endpoint.URL, "http://") {
+		logger.Warn().Str("_id", id).
+			Msg("No TLS configured for the endpoint used in graphql contextualizer")
+	}
+
+	return &graphQLContextualizer{
+		id:            id,
+		app:           app,
+		e:             conf.Endpoint,
+		query:         conf.Query,
+		operationName: conf.OperationName,
+		variables:     conf.Variables,
+		ttl: x.IfThenElseExec(conf.CacheTTL != nil,
+			func() time.Duration { return *conf.CacheTTL },
+			func() time.Duration { return defaultTTL }),
+		continueOnError: conf.ContinueOnError,
+		v:               conf.Values,
+	}, nil
+}
+
+//nolint:cyclop
+func (c *graphQLContextualizer) Execute(ctx heimdall.RequestContext, sub *subject.Subject) error {
+	logger := zerolog.Ctx(ctx.Context())
+	logger.Debug().Str("_id", c.id).Msg("Updating using graphql contextualizer")
+
+	if sub == nil {
+		return errorchain.NewWithMessage(heimdall.ErrInternal,
+			"failed to execute graphql contextualizer due to 'nil' subject").
+			WithErrorContext(c)
+	}
+
+	cch := cache.Ctx(ctx.Context())
+
+	var (
+		cacheKey string
+		response *contextualizerData
+	)
+
+	vals, variables, err := c.renderTemplates(ctx, sub)
+	if err != nil {
+		return err
+	}
+
+	if c.ttl > 0 {
+		cacheKey = c.calculateCacheKey(sub, vals, variables)
+		if entry, err := cch.Get(ctx.Context(), cacheKey); err == nil {
+			var cd contextualizerData
+
+			if err = json.Unmarshal(entry, &cd); err == nil {
+				logger.Debug().Msg("Reusing contextualizer response from cache")
+
+				response = &cd
+			}
+		}
+	}
+
+	if response == nil {
+		response, err = c.callEndpoint(ctx, sub, vals, variables)
+		if err != nil {
+			// partial data might have been sent along with the errors
+			if response != nil && response.Payload != nil {
+				ctx.Outputs()[c.id] = response.Payload
+			}
+
+			return err
+		}
+
+		if c.ttl > 0 && len(cacheKey) != 0 {
+			data, _ := json.Marshal(response)
+
+			if err = cch.Set(ctx.Context(), cacheKey, data, c.ttl); err != nil {
+				logger.Warn().Err(err).Msg("Failed to cache contextualizer response")
+			}
+		}
+	}
+
+	if response.Payload != nil {
+		ctx.Outputs()[c.id] = response.Payload
+	}
+
+	return nil
+}
+
+func (c *graphQLContextualizer) WithConfig(rawConfig map[string]any) (Contextualizer, error) {
+	if len(rawConfig) == 0 {
+		return c, nil
+	}
+
+	type Config struct {
+		Variables       template.Template `mapstructure:"variables"`
+		CacheTTL        *time.Duration    `mapstructure:"cache_ttl"`
+		ContinueOnError *bool             `mapstructure:"continue_pipeline_on_error"`
+		Values          values.Values     `mapstructure:"values"`
+	}
+
+	var conf Config
+	if err := decodeConfig(c.app, rawConfig, &conf); err != nil {
+		return nil, errorchain.NewWithMessagef(heimdall.ErrConfiguration,
+			"failed decoding config for graphql contextualizer '%s'", c.id).CausedBy(err)
+	}
+
+	return &graphQLContextualizer{
+		id:            c.id,
+		app:           c.app,
+		e:             c.e,
+		query:         c.query,
+		operationName: c.operationName,
+		variables:     x.IfThenElse(conf.Variables != nil, conf.Variables, c.variables),
+		ttl: x.IfThenElseExec(conf.CacheTTL != nil,
+			func() time.Duration { return *conf.CacheTTL },
+			func() time.Duration { return c.ttl }),
+		continueOnError: x.IfThenElseExec(conf.ContinueOnError != nil,
+			func() bool { return *conf.ContinueOnError },
+			func() bool { return c.continueOnError }),
+		v: c.v.Merge(conf.Values),
+	}, nil
+}
+
+func (c *graphQLContextualizer) ID() string { return c.id }
+
+func (c *graphQLContextualizer) ContinueOnError() bool { return c.continueOnError }
+
+func (c *graphQLContextualizer) callEndpoint(
+	ctx heimdall.RequestContext,
+	sub *subject.Subject,
+	values map[string]string,
+	variables map[string]any,
+) (*contextualizerData, error) {
+	logger := zerolog.Ctx(ctx.Context())
+	logger.Debug().Msg("Calling graphql endpoint")
+
+	req, err := c.createRequest(ctx, sub, values, variables)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.e.CreateClient(req.URL.Hostname()).Do(req)
+	if err != nil {
+		var clientErr *url.Error
+		if errors.As(err, &clientErr) && clientErr.Timeout() {
+			return nil, errorchain.NewWithMessage(heimdall.ErrCommunicationTimeout,
+				"request to the graphql endpoint timed out").
+				WithErrorContext(c).
+				CausedBy(err)
+		}
+
+		return nil, errorchain.NewWithMessage(heimdall.ErrCommunication,
+			"request to the graphql endpoint failed").
+			WithErrorContext(c).
+			CausedBy(err)
+	}
+
+	defer resp.Body.Close()
+
+	return c.readResponse(ctx, resp)
+}
+
+func (c *graphQLContextualizer) createRequest(
+	ctx heimdall.RequestContext,
+	sub *subject.Subject,
+	values map[string]string,
+	variables map[string]any,
+) (*http.Request, error) {
+	body, err := json.Marshal(graphQLRequest{
+		Query:         c.query,
+		OperationName: c.operationName,
+		Variables:     variables,
+	})
+	if err != nil {
+		return nil, errorchain.NewWithMessage(heimdall.ErrInternal, "failed to marshal graphql request").
+			WithErrorContext(c).
+			CausedBy(err)
+	}
+
+	endpointRenderer := endpoint.RenderFunc(func(value string) (string, error) {
+		tpl, err := template.New(value)
+		if err != nil {
+			return "", errorchain.NewWithMessage(heimdall.ErrInternal, "failed to create template").
+				WithErrorContext(c).
+				CausedBy(err)
+		}
+
+		return tpl.Render(map[string]any{
+			"Subject": sub,
+			"Values":  values,
+			"Outputs": ctx.Outputs(),
+		})
+	})
+
+	req, err := c.e.CreateRequest(ctx.Context(), bytes.NewReader(body), endpointRenderer)
+	if err != nil {
+		return nil, errorchain.NewWithMessage(heimdall.ErrInternal, "failed creating request").
+			WithErrorContext(c).
+			CausedBy(err)
+	}
+
+	if len(req.Header.Get("Content-Type")) == 0 {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	if len(req.Header.Get("Accept")) == 0 {
+		req.Header.Set("Accept", "application/graphql-response+json, application/json")
+	}
+
+	return req, nil
+}
+
+func (c *graphQLContextualizer) readResponse(
+	ctx heimdall.RequestContext,
+	resp *http.Response,
+) (*contextualizerData, error) {
+	logger := zerolog.Ctx(ctx.Context())
+
+	if err := endpoint.UnavailabilityError(resp); err != nil {
+		var chain *errorchain.ErrorChain
+		if errors.As(err, &chain) {
+			chain.WithErrorContext(c)
+		}
+
+		return nil, err
+	}
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return nil, errorchain.NewWithMessagef(heimdall.ErrCommunication,
+			"unexpected response code: %v", resp.StatusCode).
+			WithErrorContext(c)
+	}
+
+	rawData, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errorchain.NewWithMessage(heimdall.ErrInternal, "failed to read response").
+			WithErrorContext(c).
+			CausedBy(err)
+	}
+
+	var gqlResp graphQLResponse
+	if err = json.Unmarshal(rawData, &gqlResp); err != nil {
+		return nil, errorchain.NewWithMessage(heimdall.ErrInternal, "failed to unmarshal graphql response").
+			WithErrorContext(c).
+			CausedBy(err)
+	}
+
+	if len(gqlResp.Errors) != 0 {
+		logger.Debug().Int("_errors", len(gqlResp.Errors)).Msg("GraphQL endpoint responded with errors")
+
+		var partial *contextualizerData
+		if gqlResp.Data != nil {
+			partial = &contextualizerData{Payload: gqlResp.Data}
+		}
+
+		return partial, errorchain.NewWithMessagef(heimdall.ErrGraphQL,
+			"graphql endpoint responded with errors: %s", gqlResp.Errors.Error()).
+			WithErrorContext(c).
+			CausedBy(gqlResp.Errors)
+	}
+
+	if gqlResp.Data == nil {
+		logger.Warn().Msg("No data received from the graphql endpoint")
+
+		return &contextualizerData{}, nil
+	}
+
+	return &contextualizerData{Payload: gqlResp.Data}, nil
+}
+
+func (c *graphQLContextualizer) calculateCacheKey(
+	sub *subject.Subject,
+	values map[string]string,
+	variables map[string]any,
+) string {
+	const int64BytesCount = 8
+
+	ttlBytes := make([]byte, int64BytesCount)
+	//nolint:gosec
+	// no integer overflow during conversion possible
+	binary.LittleEndian.PutUint64(ttlBytes, uint64(c.ttl))
+
+	// map keys are sorted by the json encoder, so the result is deterministic
+	rawVariables, _ := json.Marshal(variables)
+
+	hash := sha256.New()
+	hash.Write(c.e.Hash())
+	hash.Write(stringx.ToBytes(c.id))
+	hash.Write(stringx.ToBytes(c.query))
+	hash.Write(stringx.ToBytes(c.operationName))
+	hash.Write(rawVariables)
+	hash.Write(ttlBytes)
+	hash.Write(sub.Hash())
+
+	for k, v := range values {
+		hash.Write(stringx.ToBytes(k))
+		hash.Write(stringx.ToBytes(v))
+	}
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+func (c *graphQLContextualizer) renderTemplates(
+	ctx heimdall.RequestContext,
+	sub *subject.Subject,
+) (map[string]string, map[string]any, error) {
+	values, err := c.v.Render(map[string]any{
+		"Request": ctx.Request(),
+		"Subject": sub,
+		"Outputs": ctx.Outputs(),
+	})
+	if err != nil {
+		return nil, nil, errorchain.NewWithMessage(heimdall.ErrInternal,
+			"failed to render values for the graphql endpoint").
+			WithErrorContext(c).
+			CausedBy(err)
+	}
+
+	if c.variables == nil {
+		return values, nil, nil
+	}
+
+	rendered, err := c.variables.Render(map[string]any{
+		"Request": ctx.Request(),
+		"Subject": sub,
+		"Values":  values,
+		"Outputs": ctx.Outputs(),
+	})
+	if err != nil {
+		return nil, nil, errorchain.NewWithMessage(heimdall.ErrInternal,
+			"failed to render variables for the graphql endpoint").
+			WithErrorContext(c).
+			CausedBy(err)
+	}
+
+	var variables map[string]any
+	if err = json.Unmarshal(stringx.ToBytes(rendered), &variables); err != nil {
+		return nil, nil, errorchain.NewWithMessage(heimdall.ErrInternal,
+			"rendered variables for the graphql endpoint are not a valid JSON object").
+			WithErrorContext(c).
+			CausedBy(err)
+	}
+
+	return values, variables, nil
+}
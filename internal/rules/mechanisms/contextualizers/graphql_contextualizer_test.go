@@ -0,0 +1,393 @@
+// Copyright 2025 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package contextualizers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dadrus/heimdall/internal/cache"
+	"github.com/dadrus/heimdall/internal/cache/mocks"
+	"github.com/dadrus/heimdall/internal/heimdall"
+	heimdallmocks "github.com/dadrus/heimdall/internal/heimdall/mocks"
+	"github.com/dadrus/heimdall/internal/rules/mechanisms/subject"
+	"github.com/dadrus/heimdall/internal/x/testsupport"
+)
+
+func TestCreateGraphQLContextualizer(t *testing.T) {
+	t.Parallel()
+
+	for uc, tc := range map[string]struct {
+		config []byte
+		assert func(t *testing.T, err error, contextualizer *graphQLContextualizer)
+	}{
+		"without endpoint": {
+			config: []byte(`query: "{ me { id } }"`),
+			assert: func(t *testing.T, err error, _ *graphQLContextualizer) {
+				t.Helper()
+
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				require.ErrorContains(t, err, "'endpoint' is a required field")
+			},
+		},
+		"without query": {
+			config: []byte(`endpoint: http://foo.bar/graphql`),
+			assert: func(t *testing.T, err error, _ *graphQLContextualizer) {
+				t.Helper()
+
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				require.ErrorContains(t, err, "'query' is a required field")
+			},
+		},
+		"with unsupported fields": {
+			config: []byte(`
+endpoint: http://foo.bar/graphql
+query: "{ me { id } }"
+foo: bar
+`),
+			assert: func(t *testing.T, err error, _ *graphQLContextualizer) {
+				t.Helper()
+
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				require.ErrorContains(t, err, "failed decoding")
+			},
+		},
+		"with minimal configuration": {
+			config: []byte(`
+endpoint: http://foo.bar/graphql
+query: "{ me { id } }"
+`),
+			assert: func(t *testing.T, err error, contextualizer *graphQLContextualizer) {
+				t.Helper()
+
+				require.NoError(t, err)
+				assert.Equal(t, "http://foo.bar/graphql", contextualizer.e.URL)
+				assert.Equal(t, "{ me { id } }", contextualizer.query)
+				assert.Empty(t, contextualizer.operationName)
+				assert.Nil(t, contextualizer.variables)
+				assert.Equal(t, defaultTTL, contextualizer.ttl)
+				assert.False(t, contextualizer.ContinueOnError())
+				assert.Equal(t, "contextualizer", contextualizer.ID())
+			},
+		},
+		"with full configuration": {
+			config: []byte(`
+endpoint:
+  url: https://foo.bar/graphql
+  headers:
+    Authorization: Bearer foo
+query: "query User($id: ID!) { user(id: $id) { id groups } }"
+operation_name: User
+variables: '{"id": {{ quote .Subject.ID }}}'
+cache_ttl: 0s
+continue_pipeline_on_error: true
+values:
+  foo: bar
+`),
+			assert: func(t *testing.T, err error, contextualizer *graphQLContextualizer) {
+				t.Helper()
+
+				require.NoError(t, err)
+				assert.Equal(t, "User", contextualizer.operationName)
+				assert.NotNil(t, contextualizer.variables)
+				assert.Equal(t, time.Duration(0), contextualizer.ttl)
+				assert.True(t, contextualizer.ContinueOnError())
+				assert.Len(t, contextualizer.v, 1)
+			},
+		},
+	} {
+		t.Run(uc, func(t *testing.T) {
+			conf, err := testsupport.DecodeTestConfig(tc.config)
+			require.NoError(t, err)
+
+			contextualizer, err := newGraphQLContextualizer(newTestAppContext(t, false), "contextualizer", conf)
+
+			tc.assert(t, err, contextualizer)
+		})
+	}
+}
+
+func TestCreateGraphQLContextualizerFromPrototype(t *testing.T) {
+	t.Parallel()
+
+	appCtx := newTestAppContext(t, false)
+
+	conf, err := testsupport.DecodeTestConfig([]byte(`
+endpoint: http://foo.bar/graphql
+query: "{ me { id } }"
+variables: '{"id": "foo"}'
+values:
+  foo: bar
+`))
+	require.NoError(t, err)
+
+	prototype, err := newGraphQLContextualizer(appCtx, "contextualizer", conf)
+	require.NoError(t, err)
+
+	// without config
+	configured, err := prototype.WithConfig(nil)
+	require.NoError(t, err)
+	assert.Equal(t, prototype, configured)
+
+	// with not overridable config
+	_, err = prototype.WithConfig(map[string]any{"query": "{ foo }"})
+	require.ErrorIs(t, err, heimdall.ErrConfiguration)
+
+	// with config
+	configured, err = prototype.WithConfig(map[string]any{
+		"variables":                  `{"id": "bar"}`,
+		"cache_ttl":                  "1s",
+		"continue_pipeline_on_error": true,
+		"values":                     map[string]any{"bar": "baz"},
+	})
+	require.NoError(t, err)
+
+	contextualizer, ok := configured.(*graphQLContextualizer)
+	require.True(t, ok)
+	assert.Equal(t, prototype.id, contextualizer.id)
+	assert.Equal(t, prototype.e, contextualizer.e)
+	assert.Equal(t, prototype.query, contextualizer.query)
+	assert.NotEqual(t, prototype.variables, contextualizer.variables)
+	assert.Equal(t, time.Second, contextualizer.ttl)
+	assert.True(t, contextualizer.ContinueOnError())
+	assert.Len(t, contextualizer.v, 2)
+}
+
+func TestGraphQLContextualizerExecute(t *testing.T) {
+	t.Parallel()
+
+	for uc, tc := range map[string]struct {
+		config         []byte
+		subject        *subject.Subject
+		handler        http.HandlerFunc
+		configureCache func(t *testing.T, cch *mocks.CacheMock)
+		assert         func(t *testing.T, err error, outputs map[string]any)
+	}{
+		"with nil subject": {
+			config: []byte(`query: "{ me { id } }"`),
+			assert: func(t *testing.T, err error, _ map[string]any) {
+				t.Helper()
+
+				require.ErrorIs(t, err, heimdall.ErrInternal)
+				require.ErrorContains(t, err, "'nil' subject")
+			},
+		},
+		"with variables not rendering to a JSON object": {
+			config: []byte(`
+query: "{ me { id } }"
+variables: '{{ .Subject.ID }}'
+cache_ttl: 0s
+`),
+			subject: &subject.Subject{ID: "foo"},
+			assert: func(t *testing.T, err error, _ map[string]any) {
+				t.Helper()
+
+				require.ErrorIs(t, err, heimdall.ErrInternal)
+				require.ErrorContains(t, err, "not a valid JSON object")
+			},
+		},
+		"with unexpected response code": {
+			config: []byte(`
+query: "{ me { id } }"
+cache_ttl: 0s
+`),
+			subject: &subject.Subject{ID: "foo"},
+			handler: func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusBadGateway)
+			},
+			assert: func(t *testing.T, err error, _ map[string]any) {
+				t.Helper()
+
+				require.ErrorIs(t, err, heimdall.ErrCommunication)
+				require.ErrorContains(t, err, "unexpected response code")
+				require.NotErrorIs(t, err, heimdall.ErrGraphQL)
+
+				var gqlErrs graphQLErrors
+				require.NotErrorAs(t, err, &gqlErrs)
+			},
+		},
+		"with errors in the graphql response": {
+			config: []byte(`
+query: "{ me { id } }"
+cache_ttl: 0s
+`),
+			subject: &subject.Subject{ID: "foo"},
+			handler: func(w http.ResponseWriter, _ *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`{"data": null, "errors": [{"message": "user not found", "path": ["me"]}]}`))
+			},
+			assert: func(t *testing.T, err error, outputs map[string]any) {
+				t.Helper()
+
+				require.ErrorIs(t, err, heimdall.ErrGraphQL)
+				require.NotErrorIs(t, err, heimdall.ErrCommunication)
+				require.ErrorContains(t, err, "graphql endpoint responded with errors: user not found")
+
+				var gqlErrs graphQLErrors
+				require.ErrorAs(t, err, &gqlErrs)
+				require.Len(t, gqlErrs, 1)
+				assert.Equal(t, []any{"me"}, gqlErrs[0].Path)
+
+				var identifier interface{ ID() string }
+				require.ErrorAs(t, err, &identifier)
+				assert.Equal(t, "contextualizer", identifier.ID())
+
+				assert.NotContains(t, outputs, "contextualizer")
+			},
+		},
+		"with errors and partial data in the graphql response": {
+			config: []byte(`
+query: "{ me { id name } }"
+cache_ttl: 0s
+`),
+			subject: &subject.Subject{ID: "foo"},
+			handler: func(w http.ResponseWriter, _ *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`{
+					"data": {"me": {"id": "foo", "name": null}},
+					"errors": [{"message": "name not resolvable", "path": ["me", "name"]}]
+				}`))
+			},
+			assert: func(t *testing.T, err error, outputs map[string]any) {
+				t.Helper()
+
+				require.ErrorIs(t, err, heimdall.ErrGraphQL)
+				require.ErrorContains(t, err, "name not resolvable")
+				assert.Equal(t, map[string]any{"me": map[string]any{"id": "foo", "name": nil}}, outputs["contextualizer"])
+			},
+		},
+		"with successful response and caching of it": {
+			config: []byte(`
+endpoint:
+  headers:
+    X-User: '{{ .Subject.ID }}'
+query: "query User($id: ID!) { user(id: $id) { id groups } }"
+operation_name: User
+variables: '{"id": {{ quote .Values.id }}}'
+values:
+  id: '{{ .Subject.ID }}'
+`),
+			subject: &subject.Subject{ID: "alice"},
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, http.MethodPost, r.Method)
+				assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+				assert.Equal(t, "alice", r.Header.Get("X-User"))
+
+				body, err := io.ReadAll(r.Body)
+				assert.NoError(t, err)
+				assert.JSONEq(t, `{
+					"query": "query User($id: ID!) { user(id: $id) { id groups } }",
+					"operationName": "User",
+					"variables": {"id": "alice"}
+				}`, string(body))
+
+				w.Header().Set("Content-Type", "application/graphql-response+json")
+				_, _ = w.Write([]byte(`{"data": {"user": {"id": "alice", "groups": ["admin"]}}}`))
+			},
+			configureCache: func(t *testing.T, cch *mocks.CacheMock) {
+				t.Helper()
+
+				cch.EXPECT().Get(mock.Anything, mock.Anything).Return(nil, errors.New("no cache entry"))
+				cch.EXPECT().Set(mock.Anything, mock.Anything, mock.Anything, defaultTTL).Return(nil)
+			},
+			assert: func(t *testing.T, err error, outputs map[string]any) {
+				t.Helper()
+
+				require.NoError(t, err)
+				assert.Equal(t,
+					map[string]any{"user": map[string]any{"id": "alice", "groups": []any{"admin"}}},
+					outputs["contextualizer"])
+			},
+		},
+		"with response from cache": {
+			config: []byte(`
+query: "{ me { id } }"
+`),
+			subject: &subject.Subject{ID: "alice"},
+			handler: func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusInternalServerError)
+			},
+			configureCache: func(t *testing.T, cch *mocks.CacheMock) {
+				t.Helper()
+
+				data, err := json.Marshal(contextualizerData{Payload: map[string]any{"me": map[string]any{"id": "alice"}}})
+				require.NoError(t, err)
+
+				cch.EXPECT().Get(mock.Anything, mock.Anything).Return(data, nil)
+			},
+			assert: func(t *testing.T, err error, outputs map[string]any) {
+				t.Helper()
+
+				require.NoError(t, err)
+				assert.Equal(t, map[string]any{"me": map[string]any{"id": "alice"}}, outputs["contextualizer"])
+			},
+		},
+	} {
+		t.Run(uc, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tc.handler == nil {
+					t.Error("unexpected call to the graphql endpoint")
+					w.WriteHeader(http.StatusInternalServerError)
+
+					return
+				}
+
+				tc.handler(w, r)
+			}))
+			defer srv.Close()
+
+			conf, err := testsupport.DecodeTestConfig(tc.config)
+			require.NoError(t, err)
+
+			if ep, ok := conf["endpoint"].(map[string]any); ok {
+				ep["url"] = srv.URL
+			} else {
+				conf["endpoint"] = srv.URL
+			}
+
+			appCtx := newTestAppContext(t, false)
+
+			contextualizer, err := newGraphQLContextualizer(appCtx, "contextualizer", conf)
+			require.NoError(t, err)
+
+			cch := mocks.NewCacheMock(t)
+			if tc.configureCache != nil {
+				tc.configureCache(t, cch)
+			}
+
+			outputs := map[string]any{}
+
+			ctx := heimdallmocks.NewRequestContextMock(t)
+			ctx.EXPECT().Context().Return(cache.WithContext(t.Context(), cch))
+			ctx.EXPECT().Request().Maybe().Return(&heimdall.Request{})
+			ctx.EXPECT().Outputs().Maybe().Return(outputs)
+
+			err = contextualizer.Execute(ctx, tc.subject)
+
+			tc.assert(t, err, outputs)
+		})
+	}
+}
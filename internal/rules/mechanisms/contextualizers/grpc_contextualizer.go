@@ -0,0 +1,327 @@
+// Copyright 2025 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package contextualizers
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/rs/zerolog"
+
+	"github.com/dadrus/heimdall/internal/app"
+	"github.com/dadrus/heimdall/internal/cache"
+	"github.com/dadrus/heimdall/internal/heimdall"
+	"github.com/dadrus/heimdall/internal/rules/endpoint"
+	"github.com/dadrus/heimdall/internal/rules/mechanisms/subject"
+	"github.com/dadrus/heimdall/internal/rules/mechanisms/template"
+	"github.com/dadrus/heimdall/internal/rules/mechanisms/values"
+	"github.com/dadrus/heimdall/internal/x"
+	"github.com/dadrus/heimdall/internal/x/errorchain"
+	"github.com/dadrus/heimdall/internal/x/stringx"
+)
+
+// by intention. Used only during application bootstrap
+//
+//nolint:gochecknoinits
+func init() {
+	registerTypeFactory(
+		func(app app.Context, id string, typ string, conf map[string]any) (bool, Contextualizer, error) {
+			if typ != ContextualizerGRPC {
+				return false, nil, nil
+			}
+
+			eh, err := newGRPCContextualizer(app, id, conf)
+
+			return true, eh, err
+		})
+}
+
+type grpcContextualizer struct {
+	id              string
+	app             app.Context
+	e               endpoint.GRPCEndpoint
+	client          *endpoint.GRPCClient
+	ttl             time.Duration
+	payload         template.Template
+	continueOnError bool
+	v               values.Values
+}
+
+func newGRPCContextualizer(app app.Context, id string, rawConfig map[string]any) (*grpcContextualizer, error) {
+	logger := app.Logger()
+	logger.Info().Str("_id", id).Msg("Creating grpc contextualizer")
+
+	type Config struct {
+		Endpoint        endpoint.GRPCEndpoint `mapstructure:"endpoint"                   validate:"required"`
+		Payload         template.Template     `mapstructure:"payload"`
+		CacheTTL        *time.Duration        `mapstructure:"cache_ttl"`
+		ContinueOnError bool                  `mapstructure:"continue_pipeline_on_error"`
+		Values          values.Values         `mapstructure:"values"`
+	}
+
+	var conf Config
+	if err := decodeConfig(app, rawConfig, &conf); err != nil {
+		return nil, errorchain.NewWithMessagef(heimdall.ErrConfiguration,
+			"failed decoding config for grpc contextualizer '%s'", id).CausedBy(err)
+	}
+
+	if conf.Endpoint.TLS.Disabled {
+		logger.Warn().Str("_id", id).
+			Msg("No TLS configured for the endpoint used in grpc contextualizer")
+	}
+
+	client, err := conf.Endpoint.CreateClient(app, id)
+	if err != nil {
+		return nil, errorchain.NewWithMessagef(heimdall.ErrConfiguration,
+			"failed creating grpc client for grpc contextualizer '%s'", id).CausedBy(err)
+	}
+
+	ttl := defaultTTL
+	if conf.CacheTTL != nil {
+		ttl = *conf.CacheTTL
+	}
+
+	return &grpcContextualizer{
+		id:              id,
+		app:             app,
+		e:               conf.Endpoint,
+		client:          client,
+		payload:         conf.Payload,
+		ttl:             ttl,
+		continueOnError: conf.ContinueOnError,
+		v:               conf.Values,
+	}, nil
+}
+
+func (c *grpcContextualizer) Execute(ctx heimdall.RequestContext, sub *subject.Subject) error {
+	logger := zerolog.Ctx(ctx.Context())
+	logger.Debug().Str("_id", c.id).Msg("Updating using grpc contextualizer")
+
+	if sub == nil {
+		return errorchain.NewWithMessage(heimdall.ErrInternal,
+			"failed to execute grpc contextualizer due to 'nil' subject").
+			WithErrorContext(c)
+	}
+
+	cch := cache.Ctx(ctx.Context())
+
+	var (
+		cacheKey string
+		response *contextualizerData
+	)
+
+	vals, payload, err := c.renderTemplates(ctx, sub)
+	if err != nil {
+		return err
+	}
+
+	if c.ttl > 0 {
+		cacheKey = c.calculateCacheKey(sub, vals, payload)
+		if entry, err := cch.Get(ctx.Context(), cacheKey); err == nil {
+			var cd contextualizerData
+
+			if err = json.Unmarshal(entry, &cd); err == nil {
+				logger.Debug().Msg("Reusing contextualizer response from cache")
+
+				response = &cd
+			}
+		}
+	}
+
+	if response == nil {
+		response, err = c.callEndpoint(ctx, sub, vals, payload)
+		if err != nil {
+			return err
+		}
+
+		if c.ttl > 0 && len(cacheKey) != 0 {
+			data, _ := json.Marshal(response)
+
+			if err = cch.Set(ctx.Context(), cacheKey, data, c.ttl); err != nil {
+				logger.Warn().Err(err).Msg("Failed to cache contextualizer response")
+			}
+		}
+	}
+
+	if response.Payload != nil {
+		ctx.Outputs()[c.id] = response.Payload
+	}
+
+	return nil
+}
+
+func (c *grpcContextualizer) WithConfig(rawConfig map[string]any) (Contextualizer, error) {
+	if len(rawConfig) == 0 {
+		return c, nil
+	}
+
+	type Config struct {
+		Payload         template.Template `mapstructure:"payload"`
+		CacheTTL        *time.Duration    `mapstructure:"cache_ttl"`
+		ContinueOnError *bool             `mapstructure:"continue_pipeline_on_error"`
+		Values          values.Values     `mapstructure:"values"`
+	}
+
+	var conf Config
+	if err := decodeConfig(c.app, rawConfig, &conf); err != nil {
+		return nil, errorchain.NewWithMessagef(heimdall.ErrConfiguration,
+			"failed decoding config for grpc contextualizer '%s'", c.id).CausedBy(err)
+	}
+
+	return &grpcContextualizer{
+		id:      c.id,
+		app:     c.app,
+		e:       c.e,
+		client:  c.client,
+		payload: x.IfThenElse(conf.Payload != nil, conf.Payload, c.payload),
+		ttl: x.IfThenElseExec(conf.CacheTTL != nil,
+			func() time.Duration { return *conf.CacheTTL },
+			func() time.Duration { return c.ttl }),
+		continueOnError: x.IfThenElseExec(conf.ContinueOnError != nil,
+			func() bool { return *conf.ContinueOnError },
+			func() bool { return c.continueOnError }),
+		v: c.v.Merge(conf.Values),
+	}, nil
+}
+
+func (c *grpcContextualizer) ID() string { return c.id }
+
+func (c *grpcContextualizer) ContinueOnError() bool { return c.continueOnError }
+
+func (c *grpcContextualizer) callEndpoint(
+	ctx heimdall.RequestContext,
+	sub *subject.Subject,
+	values map[string]string,
+	payload string,
+) (*contextualizerData, error) {
+	logger := zerolog.Ctx(ctx.Context())
+	logger.Debug().Msg("Calling contextualizer grpc endpoint")
+
+	renderer := endpoint.RenderFunc(func(value string) (string, error) {
+		tpl, err := template.New(value)
+		if err != nil {
+			return "", errorchain.NewWithMessage(heimdall.ErrInternal, "failed to create template").
+				WithErrorContext(c).
+				CausedBy(err)
+		}
+
+		return tpl.Render(map[string]any{
+			"Subject": sub,
+			"Values":  values,
+			"Outputs": ctx.Outputs(),
+		})
+	})
+
+	resp, err := c.client.Invoke(ctx.Context(), payload, renderer)
+	if err != nil {
+		switch {
+		case errors.Is(err, heimdall.ErrCommunicationTimeout):
+			return nil, errorchain.NewWithMessage(heimdall.ErrCommunicationTimeout,
+				"call to the contextualizer grpc endpoint timed out").
+				WithErrorContext(c).
+				CausedBy(err)
+		case errors.Is(err, heimdall.ErrCommunication):
+			return nil, errorchain.NewWithMessage(heimdall.ErrCommunication,
+				"call to the contextualizer grpc endpoint failed").
+				WithErrorContext(c).
+				CausedBy(err)
+		default:
+			return nil, errorchain.NewWithMessage(heimdall.ErrInternal,
+				"failed calling the contextualizer grpc endpoint").
+				WithErrorContext(c).
+				CausedBy(err)
+		}
+	}
+
+	var data any
+	if err = json.Unmarshal(resp.Payload, &data); err != nil {
+		return nil, errorchain.NewWithMessage(heimdall.ErrInternal, "failed to unmarshal response").
+			WithErrorContext(c).
+			CausedBy(err)
+	}
+
+	return &contextualizerData{Payload: data}, nil
+}
+
+func (c *grpcContextualizer) calculateCacheKey(
+	sub *subject.Subject,
+	values map[string]string,
+	payload string,
+) string {
+	const int64BytesCount = 8
+
+	ttlBytes := make([]byte, int64BytesCount)
+	//nolint:gosec
+	// no integer overflow during conversion possible
+	binary.LittleEndian.PutUint64(ttlBytes, uint64(c.ttl))
+
+	hash := sha256.New()
+	hash.Write(c.e.Hash())
+	hash.Write(stringx.ToBytes(c.id))
+	hash.Write(stringx.ToBytes(payload))
+	hash.Write(ttlBytes)
+	hash.Write(sub.Hash())
+
+	for k, v := range values {
+		hash.Write(stringx.ToBytes(k))
+		hash.Write(stringx.ToBytes(v))
+	}
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+func (c *grpcContextualizer) renderTemplates(
+	ctx heimdall.RequestContext,
+	sub *subject.Subject,
+) (map[string]string, string, error) {
+	var (
+		values  map[string]string
+		payload string
+		err     error
+	)
+
+	if values, err = c.v.Render(map[string]any{
+		"Request": ctx.Request(),
+		"Subject": sub,
+		"Outputs": ctx.Outputs(),
+	}); err != nil {
+		return nil, "", errorchain.NewWithMessage(heimdall.ErrInternal,
+			"failed to render values for the contextualization endpoint").
+			WithErrorContext(c).
+			CausedBy(err)
+	}
+
+	if c.payload != nil {
+		if payload, err = c.payload.Render(map[string]any{
+			"Request": ctx.Request(),
+			"Subject": sub,
+			"Values":  values,
+			"Outputs": ctx.Outputs(),
+		}); err != nil {
+			return nil, "", errorchain.NewWithMessage(heimdall.ErrInternal,
+				"failed to render payload for the contextualization endpoint").
+				WithErrorContext(c).
+				CausedBy(err)
+		}
+	}
+
+	return values, payload, nil
+}
@@ -0,0 +1,381 @@
+// Copyright 2025 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package contextualizers
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/dadrus/heimdall/internal/app"
+	"github.com/dadrus/heimdall/internal/cache"
+	"github.com/dadrus/heimdall/internal/cache/mocks"
+	"github.com/dadrus/heimdall/internal/config"
+	"github.com/dadrus/heimdall/internal/heimdall"
+	heimdallmocks "github.com/dadrus/heimdall/internal/heimdall/mocks"
+	"github.com/dadrus/heimdall/internal/rules/mechanisms/subject"
+	"github.com/dadrus/heimdall/internal/validation"
+	"github.com/dadrus/heimdall/internal/watcher"
+	"github.com/dadrus/heimdall/internal/x/testsupport"
+)
+
+func newTestAppContext(t *testing.T, enforceTLS bool) app.Context {
+	t.Helper()
+
+	es := config.EnforcementSettings{EnforceEgressTLS: enforceTLS}
+	validator, err := validation.NewValidator(
+		validation.WithTagValidator(es),
+		validation.WithErrorTranslator(es),
+	)
+	require.NoError(t, err)
+
+	appCtx := app.NewContextMock(t)
+	appCtx.EXPECT().Validator().Maybe().Return(validator)
+	appCtx.EXPECT().Logger().Maybe().Return(log.Logger)
+	appCtx.EXPECT().Watcher().Maybe().Return(&watcher.NoopWatcher{})
+	appCtx.EXPECT().CertificateObserver().Maybe().Return(nil)
+
+	return appCtx
+}
+
+func TestCreateGRPCContextualizer(t *testing.T) {
+	t.Parallel()
+
+	for uc, tc := range map[string]struct {
+		enforceTLS bool
+		config     []byte
+		assert     func(t *testing.T, err error, contextualizer *grpcContextualizer)
+	}{
+		"without endpoint": {
+			config: []byte(`payload: bar`),
+			assert: func(t *testing.T, err error, _ *grpcContextualizer) {
+				t.Helper()
+
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				require.ErrorContains(t, err, "'endpoint' is a required field")
+			},
+		},
+		"with unsupported fields": {
+			config: []byte(`
+endpoint:
+  address: foo:1234
+  method: foo.Bar/Baz
+foo: bar
+`),
+			assert: func(t *testing.T, err error, _ *grpcContextualizer) {
+				t.Helper()
+
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				require.ErrorContains(t, err, "failed decoding")
+			},
+		},
+		"with disabled TLS while TLS is enforced": {
+			enforceTLS: true,
+			config: []byte(`
+endpoint:
+  address: foo:1234
+  method: foo.Bar/Baz
+  tls:
+    disabled: true
+`),
+			assert: func(t *testing.T, err error, _ *grpcContextualizer) {
+				t.Helper()
+
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				require.ErrorContains(t, err, "'endpoint'.'tls'.'disabled' must be false")
+			},
+		},
+		"with malformed method": {
+			config: []byte(`
+endpoint:
+  address: foo:1234
+  method: Baz
+`),
+			assert: func(t *testing.T, err error, _ *grpcContextualizer) {
+				t.Helper()
+
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				require.ErrorContains(t, err, "failed creating grpc client")
+			},
+		},
+		"with full configuration": {
+			enforceTLS: true,
+			config: []byte(`
+endpoint:
+  address: foo:1234
+  method: foo.Bar/Baz
+  timeout: 2s
+  metadata:
+    x-foo: bar
+payload: '{"id": {{ quote .Subject.ID }}}'
+cache_ttl: 5s
+continue_pipeline_on_error: true
+values:
+  foo: bar
+`),
+			assert: func(t *testing.T, err error, contextualizer *grpcContextualizer) {
+				t.Helper()
+
+				require.NoError(t, err)
+				assert.Equal(t, "foo:1234", contextualizer.e.Address)
+				assert.Equal(t, "foo.Bar/Baz", contextualizer.e.Method)
+				assert.Equal(t, 2*time.Second, contextualizer.e.Timeout)
+				assert.Equal(t, map[string]string{"x-foo": "bar"}, contextualizer.e.Metadata)
+				assert.NotNil(t, contextualizer.client)
+				assert.NotNil(t, contextualizer.payload)
+				assert.Equal(t, 5*time.Second, contextualizer.ttl)
+				assert.True(t, contextualizer.ContinueOnError())
+				assert.Len(t, contextualizer.v, 1)
+				assert.Equal(t, "contextualizer", contextualizer.ID())
+			},
+		},
+		"with minimal configuration": {
+			config: []byte(`
+endpoint:
+  address: foo:1234
+  method: foo.Bar/Baz
+`),
+			assert: func(t *testing.T, err error, contextualizer *grpcContextualizer) {
+				t.Helper()
+
+				require.NoError(t, err)
+				assert.Nil(t, contextualizer.payload)
+				assert.Equal(t, defaultTTL, contextualizer.ttl)
+				assert.False(t, contextualizer.ContinueOnError())
+			},
+		},
+	} {
+		t.Run(uc, func(t *testing.T) {
+			conf, err := testsupport.DecodeTestConfig(tc.config)
+			require.NoError(t, err)
+
+			contextualizer, err := newGRPCContextualizer(newTestAppContext(t, tc.enforceTLS), "contextualizer", conf)
+
+			tc.assert(t, err, contextualizer)
+		})
+	}
+}
+
+func TestCreateGRPCContextualizerFromPrototype(t *testing.T) {
+	t.Parallel()
+
+	appCtx := newTestAppContext(t, false)
+
+	conf, err := testsupport.DecodeTestConfig([]byte(`
+endpoint:
+  address: foo:1234
+  method: foo.Bar/Baz
+payload: foo
+cache_ttl: 5s
+values:
+  foo: bar
+`))
+	require.NoError(t, err)
+
+	prototype, err := newGRPCContextualizer(appCtx, "contextualizer", conf)
+	require.NoError(t, err)
+
+	// without config
+	configured, err := prototype.WithConfig(nil)
+	require.NoError(t, err)
+	assert.Equal(t, prototype, configured)
+
+	// with unsupported config
+	_, err = prototype.WithConfig(map[string]any{"endpoint": map[string]any{"address": "bar:1234"}})
+	require.ErrorIs(t, err, heimdall.ErrConfiguration)
+
+	// with config
+	configured, err = prototype.WithConfig(map[string]any{
+		"payload":                    "bar",
+		"cache_ttl":                  "1s",
+		"continue_pipeline_on_error": true,
+		"values":                     map[string]any{"bar": "baz"},
+	})
+	require.NoError(t, err)
+
+	contextualizer, ok := configured.(*grpcContextualizer)
+	require.True(t, ok)
+	assert.Equal(t, prototype.id, contextualizer.id)
+	assert.Equal(t, prototype.e, contextualizer.e)
+	assert.Same(t, prototype.client, contextualizer.client)
+	assert.NotEqual(t, prototype.payload, contextualizer.payload)
+	assert.Equal(t, time.Second, contextualizer.ttl)
+	assert.True(t, contextualizer.ContinueOnError())
+	assert.False(t, prototype.ContinueOnError())
+	assert.Len(t, contextualizer.v, 2)
+}
+
+func TestGRPCContextualizerExecute(t *testing.T) {
+	t.Parallel()
+
+	for uc, tc := range map[string]struct {
+		config         func(addr string) []byte
+		subject        *subject.Subject
+		interceptor    grpc.UnaryServerInterceptor
+		configureCache func(t *testing.T, cch *mocks.CacheMock)
+		assert         func(t *testing.T, err error, outputs map[string]any)
+	}{
+		"with nil subject": {
+			config: func(addr string) []byte {
+				return []byte("endpoint:\n  address: " + addr + "\n  method: grpc.health.v1.Health/Check")
+			},
+			assert: func(t *testing.T, err error, _ map[string]any) {
+				t.Helper()
+
+				require.ErrorIs(t, err, heimdall.ErrInternal)
+				require.ErrorContains(t, err, "'nil' subject")
+			},
+		},
+		"with failing call": {
+			config: func(addr string) []byte {
+				return []byte(`
+endpoint:
+  address: ` + addr + `
+  method: grpc.health.v1.Health/Check
+  tls:
+    disabled: true
+payload: '{"service": "bar"}'
+cache_ttl: 0s
+`)
+			},
+			subject: &subject.Subject{ID: "foo"},
+			assert: func(t *testing.T, err error, outputs map[string]any) {
+				t.Helper()
+
+				require.ErrorIs(t, err, heimdall.ErrCommunication)
+				require.ErrorContains(t, err, "grpc endpoint failed")
+
+				var identifier interface{ ID() string }
+				require.ErrorAs(t, err, &identifier)
+				assert.Equal(t, "contextualizer", identifier.ID())
+
+				assert.NotContains(t, outputs, "contextualizer")
+			},
+		},
+		"with successful call and caching of the response": {
+			config: func(addr string) []byte {
+				return []byte(`
+endpoint:
+  address: ` + addr + `
+  method: grpc.health.v1.Health/Check
+  tls:
+    disabled: true
+  metadata:
+    x-subject: '{{ .Subject.ID }}'
+payload: '{"service": {{ quote .Values.service }}}'
+values:
+  service: '{{ .Subject.Attributes.service }}'
+`)
+			},
+			subject: &subject.Subject{ID: "alice", Attributes: map[string]any{"service": "foo"}},
+			interceptor: func(
+				ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler,
+			) (any, error) {
+				if strings.HasPrefix(info.FullMethod, "/grpc.health.v1.Health") {
+					md, _ := metadata.FromIncomingContext(ctx)
+					if len(md.Get("x-subject")) != 1 || md.Get("x-subject")[0] != "alice" {
+						return nil, errors.New("unexpected metadata")
+					}
+				}
+
+				return handler(ctx, req)
+			},
+			configureCache: func(t *testing.T, cch *mocks.CacheMock) {
+				t.Helper()
+
+				cch.EXPECT().Get(mock.Anything, mock.Anything).Return(nil, errors.New("no cache entry"))
+				cch.EXPECT().Set(mock.Anything, mock.Anything, mock.MatchedBy(func(data []byte) bool {
+					var cd contextualizerData
+
+					return json.Unmarshal(data, &cd) == nil &&
+						assert.Equal(t, map[string]any{"status": "SERVING"}, cd.Payload)
+				}), defaultTTL).Return(nil)
+			},
+			assert: func(t *testing.T, err error, outputs map[string]any) {
+				t.Helper()
+
+				require.NoError(t, err)
+				assert.Equal(t, map[string]any{"status": "SERVING"}, outputs["contextualizer"])
+			},
+		},
+		"with response from cache": {
+			config: func(addr string) []byte {
+				return []byte(`
+endpoint:
+  address: ` + addr + `
+  method: grpc.health.v1.Health/Check
+  tls:
+    disabled: true
+`)
+			},
+			subject: &subject.Subject{ID: "alice"},
+			interceptor: func(_ context.Context, _ any, info *grpc.UnaryServerInfo, _ grpc.UnaryHandler) (any, error) {
+				return nil, errors.New("unexpected call to " + info.FullMethod)
+			},
+			configureCache: func(t *testing.T, cch *mocks.CacheMock) {
+				t.Helper()
+
+				data, err := json.Marshal(contextualizerData{Payload: map[string]any{"status": "NOT_SERVING"}})
+				require.NoError(t, err)
+
+				cch.EXPECT().Get(mock.Anything, mock.Anything).Return(data, nil)
+			},
+			assert: func(t *testing.T, err error, outputs map[string]any) {
+				t.Helper()
+
+				require.NoError(t, err)
+				assert.Equal(t, map[string]any{"status": "NOT_SERVING"}, outputs["contextualizer"])
+			},
+		},
+	} {
+		t.Run(uc, func(t *testing.T) {
+			addr := testsupport.StartGRPCHealthServer(t, tc.interceptor)
+
+			conf, err := testsupport.DecodeTestConfig(tc.config(addr))
+			require.NoError(t, err)
+
+			contextualizer, err := newGRPCContextualizer(newTestAppContext(t, false), "contextualizer", conf)
+			require.NoError(t, err)
+
+			cch := mocks.NewCacheMock(t)
+			if tc.configureCache != nil {
+				tc.configureCache(t, cch)
+			}
+
+			outputs := map[string]any{}
+
+			ctx := heimdallmocks.NewRequestContextMock(t)
+			ctx.EXPECT().Context().Return(cache.WithContext(t.Context(), cch))
+			ctx.EXPECT().Request().Maybe().Return(&heimdall.Request{})
+			ctx.EXPECT().Outputs().Maybe().Return(outputs)
+
+			err = contextualizer.Execute(ctx, tc.subject)
+
+			tc.assert(t, err, outputs)
+		})
+	}
+}
@@ -0,0 +1,305 @@
+// Copyright 2025 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package contextualizers
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+
+	"github.com/rs/zerolog"
+	"gopkg.in/yaml.v3"
+
+	"github.com/dadrus/heimdall/internal/app"
+	"github.com/dadrus/heimdall/internal/heimdall"
+	"github.com/dadrus/heimdall/internal/rules/mechanisms/subject"
+	"github.com/dadrus/heimdall/internal/rules/mechanisms/template"
+	"github.com/dadrus/heimdall/internal/x"
+	"github.com/dadrus/heimdall/internal/x/errorchain"
+)
+
+const (
+	onMissingError   = "error"
+	onMissingIgnore  = "ignore"
+	onMissingDefault = "default"
+)
+
+var (
+	errUnsupportedDatasetFormat = errors.New("unsupported dataset format")
+	errMalformedDataset         = errors.New("malformed dataset")
+)
+
+// by intention. Used only during application bootstrap
+//
+//nolint:gochecknoinits
+func init() {
+	registerTypeFactory(
+		func(app app.Context, id string, typ string, conf map[string]any) (bool, Contextualizer, error) {
+			if typ != ContextualizerLookup {
+				return false, nil, nil
+			}
+
+			eh, err := newLookupContextualizer(app, id, conf)
+
+			return true, eh, err
+		})
+}
+
+type lookupDataset struct {
+	path    string
+	entries atomic.Pointer[map[string]any]
+}
+
+func (d *lookupDataset) get(key string) (any, bool) {
+	entry, found := (*d.entries.Load())[key]
+
+	return entry, found
+}
+
+func (d *lookupDataset) load() error {
+	data, err := os.ReadFile(d.path)
+	if err != nil {
+		return err
+	}
+
+	var entries map[string]any
+
+	switch strings.ToLower(filepath.Ext(d.path)) {
+	case ".csv":
+		entries, err = parseCSVDataset(data)
+	case ".json", ".yaml", ".yml":
+		// YAML is a superset of JSON, so both formats are supported
+		err = yaml.Unmarshal(data, &entries)
+	default:
+		err = errorchain.NewWithMessagef(errUnsupportedDatasetFormat, "'%s'", filepath.Ext(d.path))
+	}
+
+	if err != nil {
+		return err
+	}
+
+	d.entries.Store(&entries)
+
+	return nil
+}
+
+func (d *lookupDataset) OnChanged(log zerolog.Logger) {
+	if err := d.load(); err != nil {
+		log.Warn().Err(err).
+			Str("_source", "lookup").
+			Str("_file", d.path).
+			Msg("Dataset reload failed")
+	} else {
+		log.Info().
+			Str("_source", "lookup").
+			Str("_file", d.path).
+			Msg("Dataset reloaded")
+	}
+}
+
+// parseCSVDataset expects the first line to hold the column names and the first column to hold the
+// keys. Each entry is made available as a map with the column names as keys.
+func parseCSVDataset(data []byte) (map[string]any, error) {
+	records, err := csv.NewReader(bytes.NewReader(data)).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(records) == 0 {
+		return nil, errorchain.NewWithMessage(errMalformedDataset, "no header line present")
+	}
+
+	header := records[0]
+	entries := make(map[string]any, len(records)-1)
+
+	for _, record := range records[1:] {
+		if _, found := entries[record[0]]; found {
+			return nil, errorchain.NewWithMessagef(errMalformedDataset, "duplicate key '%s'", record[0])
+		}
+
+		entry := make(map[string]any, len(header))
+		for idx, column := range header {
+			entry[column] = record[idx]
+		}
+
+		entries[record[0]] = entry
+	}
+
+	return entries, nil
+}
+
+type lookupContextualizer struct {
+	id              string
+	app             app.Context
+	dataset         *lookupDataset
+	key             template.Template
+	onMissing       string
+	defaultEntry    any
+	continueOnError bool
+}
+
+func newLookupContextualizer(app app.Context, id string, rawConfig map[string]any) (*lookupContextualizer, error) {
+	logger := app.Logger()
+	logger.Info().Str("_id", id).Msg("Creating lookup contextualizer")
+
+	type Config struct {
+		File            string            `mapstructure:"file"                       validate:"required_without=Entries,excluded_with=Entries"` //nolint:lll
+		Entries         map[string]any    `mapstructure:"entries"                    validate:"required_without=File"`
+		Key             template.Template `mapstructure:"key"                        validate:"required"`
+		OnMissing       string            `mapstructure:"on_missing"                 validate:"omitempty,oneof=error ignore default"`
+		Default         any               `mapstructure:"default"                    validate:"required_if=OnMissing default"`
+		ContinueOnError bool              `mapstructure:"continue_pipeline_on_error"`
+	}
+
+	var conf Config
+	if err := decodeConfig(app, rawConfig, &conf); err != nil {
+		return nil, errorchain.NewWithMessagef(heimdall.ErrConfiguration,
+			"failed decoding config for lookup contextualizer '%s'", id).CausedBy(err)
+	}
+
+	dataset, err := newLookupDataset(app, conf.File, conf.Entries)
+	if err != nil {
+		return nil, errorchain.NewWithMessagef(heimdall.ErrConfiguration,
+			"failed loading dataset for lookup contextualizer '%s'", id).CausedBy(err)
+	}
+
+	return &lookupContextualizer{
+		id:              id,
+		app:             app,
+		dataset:         dataset,
+		key:             conf.Key,
+		onMissing:       x.IfThenElse(len(conf.OnMissing) != 0, conf.OnMissing, onMissingError),
+		defaultEntry:    conf.Default,
+		continueOnError: conf.ContinueOnError,
+	}, nil
+}
+
+func newLookupDataset(app app.Context, path string, entries map[string]any) (*lookupDataset, error) {
+	dataset := &lookupDataset{path: path}
+
+	if len(path) == 0 {
+		dataset.entries.Store(&entries)
+
+		return dataset, nil
+	}
+
+	if err := dataset.load(); err != nil {
+		return nil, err
+	}
+
+	if err := app.Watcher().Add(path, dataset); err != nil {
+		return nil, err
+	}
+
+	return dataset, nil
+}
+
+func (c *lookupContextualizer) Execute(ctx heimdall.RequestContext, sub *subject.Subject) error {
+	logger := zerolog.Ctx(ctx.Context())
+	logger.Debug().Str("_id", c.id).Msg("Updating using lookup contextualizer")
+
+	key, err := c.key.Render(map[string]any{
+		"Request": ctx.Request(),
+		"Subject": sub,
+		"Outputs": ctx.Outputs(),
+	})
+	if err != nil {
+		return errorchain.NewWithMessage(heimdall.ErrInternal, "failed to render lookup key").
+			WithErrorContext(c).
+			CausedBy(err)
+	}
+
+	entry, found := c.dataset.get(key)
+	if found {
+		ctx.Outputs()[c.id] = entry
+
+		return nil
+	}
+
+	switch c.onMissing {
+	case onMissingIgnore:
+		logger.Debug().Str("_key", key).Msg("No entry found. Ignoring")
+	case onMissingDefault:
+		logger.Debug().Str("_key", key).Msg("No entry found. Using default")
+
+		ctx.Outputs()[c.id] = c.defaultEntry
+	default:
+		return errorchain.NewWithMessagef(heimdall.ErrArgument, "no entry found for key '%s'", key).
+			WithErrorContext(c)
+	}
+
+	return nil
+}
+
+func (c *lookupContextualizer) WithConfig(rawConfig map[string]any) (Contextualizer, error) {
+	if len(rawConfig) == 0 {
+		return c, nil
+	}
+
+	type Config struct {
+		Entries         map[string]any    `mapstructure:"entries"`
+		Key             template.Template `mapstructure:"key"`
+		OnMissing       string            `mapstructure:"on_missing"                 validate:"omitempty,oneof=error ignore default"` //nolint:lll
+		Default         any               `mapstructure:"default"`
+		ContinueOnError *bool             `mapstructure:"continue_pipeline_on_error"`
+	}
+
+	var conf Config
+	if err := decodeConfig(c.app, rawConfig, &conf); err != nil {
+		return nil, errorchain.NewWithMessagef(heimdall.ErrConfiguration,
+			"failed decoding config for lookup contextualizer '%s'", c.id).CausedBy(err)
+	}
+
+	if len(conf.Entries) != 0 && len(c.dataset.path) != 0 {
+		return nil, errorchain.NewWithMessagef(heimdall.ErrConfiguration,
+			"entries cannot be used with file based dataset of lookup contextualizer '%s'", c.id)
+	}
+
+	onMissing := x.IfThenElse(len(conf.OnMissing) != 0, conf.OnMissing, c.onMissing)
+	defaultEntry := x.IfThenElse(conf.Default != nil, conf.Default, c.defaultEntry)
+
+	if onMissing == onMissingDefault && defaultEntry == nil {
+		return nil, errorchain.NewWithMessagef(heimdall.ErrConfiguration,
+			"no default configured for lookup contextualizer '%s'", c.id)
+	}
+
+	dataset := c.dataset
+	if len(conf.Entries) != 0 {
+		dataset = &lookupDataset{}
+		dataset.entries.Store(&conf.Entries)
+	}
+
+	return &lookupContextualizer{
+		id:           c.id,
+		app:          c.app,
+		dataset:      dataset,
+		key:          x.IfThenElse(conf.Key != nil, conf.Key, c.key),
+		onMissing:    onMissing,
+		defaultEntry: defaultEntry,
+		continueOnError: x.IfThenElseExec(conf.ContinueOnError != nil,
+			func() bool { return *conf.ContinueOnError },
+			func() bool { return c.continueOnError }),
+	}, nil
+}
+
+func (c *lookupContextualizer) ID() string { return c.id }
+
+func (c *lookupContextualizer) ContinueOnError() bool { return c.continueOnError }
@@ -0,0 +1,459 @@
+// Copyright 2025 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package contextualizers
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dadrus/heimdall/internal/app"
+	"github.com/dadrus/heimdall/internal/heimdall"
+	heimdallmocks "github.com/dadrus/heimdall/internal/heimdall/mocks"
+	"github.com/dadrus/heimdall/internal/rules/mechanisms/subject"
+	"github.com/dadrus/heimdall/internal/validation"
+	"github.com/dadrus/heimdall/internal/watcher/mocks"
+	"github.com/dadrus/heimdall/internal/x/testsupport"
+)
+
+func writeLookupDataset(t *testing.T, name, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+	return path
+}
+
+func TestCreateLookupContextualizer(t *testing.T) {
+	t.Parallel()
+
+	yamlFile := writeLookupDataset(t, "tenants.yaml", `
+acme:
+  plan: enterprise
+  region: eu
+`)
+	jsonFile := writeLookupDataset(t, "tenants.json", `{"acme": {"plan": "enterprise"}}`)
+	csvFile := writeLookupDataset(t, "tenants.csv", "tenant,plan,region\nacme,enterprise,eu\nfoo,free,us\n")
+	duplicatesFile := writeLookupDataset(t, "duplicates.csv", "tenant,plan\nacme,enterprise\nacme,free\n")
+	txtFile := writeLookupDataset(t, "tenants.txt", "acme")
+
+	for uc, tc := range map[string]struct {
+		config         []byte
+		configureMocks func(t *testing.T, wm *mocks.WatcherMock)
+		assert         func(t *testing.T, err error, contextualizer *lookupContextualizer)
+	}{
+		"without key": {
+			config: []byte(`entries: { acme: { plan: enterprise } }`),
+			assert: func(t *testing.T, err error, _ *lookupContextualizer) {
+				t.Helper()
+
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				require.ErrorContains(t, err, "'key' is a required field")
+			},
+		},
+		"without file and entries": {
+			config: []byte(`key: "{{ .Subject.ID }}"`),
+			assert: func(t *testing.T, err error, _ *lookupContextualizer) {
+				t.Helper()
+
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				require.ErrorContains(t, err, "'file' is a required field")
+			},
+		},
+		"with file and entries": {
+			config: []byte(`
+key: "{{ .Subject.ID }}"
+file: ` + yamlFile + `
+entries: { acme: { plan: enterprise } }
+`),
+			assert: func(t *testing.T, err error, _ *lookupContextualizer) {
+				t.Helper()
+
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				require.ErrorContains(t, err, "'file' is an excluded field")
+			},
+		},
+		"with unsupported on_missing value": {
+			config: []byte(`
+key: "{{ .Subject.ID }}"
+entries: { acme: { plan: enterprise } }
+on_missing: foo
+`),
+			assert: func(t *testing.T, err error, _ *lookupContextualizer) {
+				t.Helper()
+
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				require.ErrorContains(t, err, "'on_missing' must be one of [error ignore default]")
+			},
+		},
+		"with on_missing set to default, but without default": {
+			config: []byte(`
+key: "{{ .Subject.ID }}"
+entries: { acme: { plan: enterprise } }
+on_missing: default
+`),
+			assert: func(t *testing.T, err error, _ *lookupContextualizer) {
+				t.Helper()
+
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				require.ErrorContains(t, err, "'default' is a required field")
+			},
+		},
+		"with not existing file": {
+			config: []byte(`
+key: "{{ .Subject.ID }}"
+file: /does/not/exist.yaml
+`),
+			assert: func(t *testing.T, err error, _ *lookupContextualizer) {
+				t.Helper()
+
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				require.ErrorContains(t, err, "failed loading dataset")
+			},
+		},
+		"with unsupported file format": {
+			config: []byte(`
+key: "{{ .Subject.ID }}"
+file: ` + txtFile + `
+`),
+			assert: func(t *testing.T, err error, _ *lookupContextualizer) {
+				t.Helper()
+
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				require.ErrorIs(t, err, errUnsupportedDatasetFormat)
+			},
+		},
+		"with csv file containing duplicate keys": {
+			config: []byte(`
+key: "{{ .Subject.ID }}"
+file: ` + duplicatesFile + `
+`),
+			assert: func(t *testing.T, err error, _ *lookupContextualizer) {
+				t.Helper()
+
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				require.ErrorIs(t, err, errMalformedDataset)
+				require.ErrorContains(t, err, "duplicate key 'acme'")
+			},
+		},
+		"with inline entries": {
+			config: []byte(`
+key: "{{ .Subject.ID }}"
+entries:
+  acme:
+    plan: enterprise
+on_missing: default
+default:
+  plan: free
+continue_pipeline_on_error: true
+`),
+			assert: func(t *testing.T, err error, contextualizer *lookupContextualizer) {
+				t.Helper()
+
+				require.NoError(t, err)
+				entry, found := contextualizer.dataset.get("acme")
+				require.True(t, found)
+				assert.Equal(t, map[string]any{"plan": "enterprise"}, entry)
+				assert.Equal(t, onMissingDefault, contextualizer.onMissing)
+				assert.Equal(t, map[string]any{"plan": "free"}, contextualizer.defaultEntry)
+				assert.True(t, contextualizer.ContinueOnError())
+				assert.Equal(t, "contextualizer", contextualizer.ID())
+			},
+		},
+		"with yaml file": {
+			config: []byte(`
+key: "{{ .Subject.ID }}"
+file: ` + yamlFile + `
+`),
+			configureMocks: func(t *testing.T, wm *mocks.WatcherMock) {
+				t.Helper()
+
+				wm.EXPECT().Add(yamlFile, mock.Anything).Return(nil)
+			},
+			assert: func(t *testing.T, err error, contextualizer *lookupContextualizer) {
+				t.Helper()
+
+				require.NoError(t, err)
+				entry, found := contextualizer.dataset.get("acme")
+				require.True(t, found)
+				assert.Equal(t, map[string]any{"plan": "enterprise", "region": "eu"}, entry)
+				assert.Equal(t, onMissingError, contextualizer.onMissing)
+				assert.False(t, contextualizer.ContinueOnError())
+			},
+		},
+		"with json file": {
+			config: []byte(`
+key: "{{ .Subject.ID }}"
+file: ` + jsonFile + `
+`),
+			configureMocks: func(t *testing.T, wm *mocks.WatcherMock) {
+				t.Helper()
+
+				wm.EXPECT().Add(jsonFile, mock.Anything).Return(nil)
+			},
+			assert: func(t *testing.T, err error, contextualizer *lookupContextualizer) {
+				t.Helper()
+
+				require.NoError(t, err)
+				entry, found := contextualizer.dataset.get("acme")
+				require.True(t, found)
+				assert.Equal(t, map[string]any{"plan": "enterprise"}, entry)
+			},
+		},
+		"with csv file": {
+			config: []byte(`
+key: "{{ .Subject.ID }}"
+file: ` + csvFile + `
+`),
+			configureMocks: func(t *testing.T, wm *mocks.WatcherMock) {
+				t.Helper()
+
+				wm.EXPECT().Add(csvFile, mock.Anything).Return(nil)
+			},
+			assert: func(t *testing.T, err error, contextualizer *lookupContextualizer) {
+				t.Helper()
+
+				require.NoError(t, err)
+				entry, found := contextualizer.dataset.get("foo")
+				require.True(t, found)
+				assert.Equal(t, map[string]any{"tenant": "foo", "plan": "free", "region": "us"}, entry)
+			},
+		},
+	} {
+		t.Run(uc, func(t *testing.T) {
+			conf, err := testsupport.DecodeTestConfig(tc.config)
+			require.NoError(t, err)
+
+			validator, err := validation.NewValidator()
+			require.NoError(t, err)
+
+			wm := mocks.NewWatcherMock(t)
+			if tc.configureMocks != nil {
+				tc.configureMocks(t, wm)
+			}
+
+			appCtx := app.NewContextMock(t)
+			appCtx.EXPECT().Validator().Maybe().Return(validator)
+			appCtx.EXPECT().Logger().Return(log.Logger)
+			appCtx.EXPECT().Watcher().Maybe().Return(wm)
+
+			contextualizer, err := newLookupContextualizer(appCtx, "contextualizer", conf)
+
+			tc.assert(t, err, contextualizer)
+		})
+	}
+}
+
+func TestCreateLookupContextualizerFromPrototype(t *testing.T) {
+	t.Parallel()
+
+	validator, err := validation.NewValidator()
+	require.NoError(t, err)
+
+	appCtx := app.NewContextMock(t)
+	appCtx.EXPECT().Validator().Maybe().Return(validator)
+	appCtx.EXPECT().Logger().Return(log.Logger)
+	appCtx.EXPECT().Watcher().Maybe().Return(mocks.NewWatcherMock(t))
+
+	conf, err := testsupport.DecodeTestConfig([]byte(`
+key: "{{ .Subject.ID }}"
+entries:
+  acme:
+    plan: enterprise
+`))
+	require.NoError(t, err)
+
+	prototype, err := newLookupContextualizer(appCtx, "contextualizer", conf)
+	require.NoError(t, err)
+
+	// without config
+	configured, err := prototype.WithConfig(nil)
+	require.NoError(t, err)
+	assert.Equal(t, prototype, configured)
+
+	// with not overridable config
+	_, err = prototype.WithConfig(map[string]any{"file": "/foo/bar.yaml"})
+	require.ErrorIs(t, err, heimdall.ErrConfiguration)
+
+	// with on_missing set to default, but without default
+	_, err = prototype.WithConfig(map[string]any{"on_missing": "default"})
+	require.ErrorIs(t, err, heimdall.ErrConfiguration)
+	require.ErrorContains(t, err, "no default configured")
+
+	// with config
+	configured, err = prototype.WithConfig(map[string]any{
+		"key":                        "{{ .Request.Header \"X-Tenant\" }}",
+		"entries":                    map[string]any{"foo": map[string]any{"plan": "free"}},
+		"on_missing":                 "ignore",
+		"continue_pipeline_on_error": true,
+	})
+	require.NoError(t, err)
+
+	contextualizer, ok := configured.(*lookupContextualizer)
+	require.True(t, ok)
+	assert.Equal(t, prototype.id, contextualizer.id)
+	assert.NotEqual(t, prototype.key, contextualizer.key)
+	assert.Equal(t, onMissingIgnore, contextualizer.onMissing)
+	assert.True(t, contextualizer.ContinueOnError())
+
+	_, found := contextualizer.dataset.get("acme")
+	assert.False(t, found)
+	_, found = contextualizer.dataset.get("foo")
+	assert.True(t, found)
+
+	_, found = prototype.dataset.get("acme")
+	assert.True(t, found)
+}
+
+func TestLookupContextualizerExecute(t *testing.T) {
+	t.Parallel()
+
+	for uc, tc := range map[string]struct {
+		config []byte
+		assert func(t *testing.T, err error, outputs map[string]any)
+	}{
+		"with key rendering error": {
+			config: []byte(`
+key: "{{ len .Subject.Attributes.foo.bar }}"
+entries: { acme: { plan: enterprise } }
+`),
+			assert: func(t *testing.T, err error, _ map[string]any) {
+				t.Helper()
+
+				require.ErrorIs(t, err, heimdall.ErrInternal)
+				require.ErrorContains(t, err, "failed to render lookup key")
+			},
+		},
+		"with existing entry": {
+			config: []byte(`
+key: "{{ .Subject.Attributes.tenant }}"
+entries: { acme: { plan: enterprise } }
+`),
+			assert: func(t *testing.T, err error, outputs map[string]any) {
+				t.Helper()
+
+				require.NoError(t, err)
+				assert.Equal(t, map[string]any{"plan": "enterprise"}, outputs["contextualizer"])
+			},
+		},
+		"with missing entry": {
+			config: []byte(`
+key: "{{ .Subject.ID }}"
+entries: { acme: { plan: enterprise } }
+`),
+			assert: func(t *testing.T, err error, outputs map[string]any) {
+				t.Helper()
+
+				require.ErrorIs(t, err, heimdall.ErrArgument)
+				require.ErrorContains(t, err, "no entry found for key 'alice'")
+
+				var identifier interface{ ID() string }
+				require.ErrorAs(t, err, &identifier)
+				assert.Equal(t, "contextualizer", identifier.ID())
+
+				assert.NotContains(t, outputs, "contextualizer")
+			},
+		},
+		"with missing entry to be ignored": {
+			config: []byte(`
+key: "{{ .Subject.ID }}"
+entries: { acme: { plan: enterprise } }
+on_missing: ignore
+`),
+			assert: func(t *testing.T, err error, outputs map[string]any) {
+				t.Helper()
+
+				require.NoError(t, err)
+				assert.NotContains(t, outputs, "contextualizer")
+			},
+		},
+		"with missing entry and default": {
+			config: []byte(`
+key: "{{ .Subject.ID }}"
+entries: { acme: { plan: enterprise } }
+on_missing: default
+default: { plan: free }
+`),
+			assert: func(t *testing.T, err error, outputs map[string]any) {
+				t.Helper()
+
+				require.NoError(t, err)
+				assert.Equal(t, map[string]any{"plan": "free"}, outputs["contextualizer"])
+			},
+		},
+	} {
+		t.Run(uc, func(t *testing.T) {
+			conf, err := testsupport.DecodeTestConfig(tc.config)
+			require.NoError(t, err)
+
+			validator, err := validation.NewValidator()
+			require.NoError(t, err)
+
+			appCtx := app.NewContextMock(t)
+			appCtx.EXPECT().Validator().Maybe().Return(validator)
+			appCtx.EXPECT().Logger().Return(log.Logger)
+
+			contextualizer, err := newLookupContextualizer(appCtx, "contextualizer", conf)
+			require.NoError(t, err)
+
+			outputs := map[string]any{}
+
+			ctx := heimdallmocks.NewRequestContextMock(t)
+			ctx.EXPECT().Context().Return(t.Context())
+			ctx.EXPECT().Request().Return(&heimdall.Request{})
+			ctx.EXPECT().Outputs().Return(outputs)
+
+			err = contextualizer.Execute(ctx, &subject.Subject{
+				ID:         "alice",
+				Attributes: map[string]any{"tenant": "acme"},
+			})
+
+			tc.assert(t, err, outputs)
+		})
+	}
+}
+
+func TestLookupDatasetReload(t *testing.T) {
+	t.Parallel()
+
+	path := writeLookupDataset(t, "tenants.yaml", "acme: { plan: enterprise }")
+
+	dataset := &lookupDataset{path: path}
+	require.NoError(t, dataset.load())
+
+	// reload with updated content
+	require.NoError(t, os.WriteFile(path, []byte("acme: { plan: free }"), 0o600))
+	dataset.OnChanged(log.Logger)
+
+	entry, found := dataset.get("acme")
+	require.True(t, found)
+	assert.Equal(t, map[string]any{"plan": "free"}, entry)
+
+	// failing reload keeps the previous content
+	require.NoError(t, os.WriteFile(path, []byte("- foo"), 0o600))
+	dataset.OnChanged(log.Logger)
+
+	entry, found = dataset.get("acme")
+	require.True(t, found)
+	assert.Equal(t, map[string]any{"plan": "free"}, entry)
+}
@@ -19,5 +19,6 @@ package errorhandlers
 const (
 	ErrorHandlerDefault         = "default"
 	ErrorHandlerRedirect        = "redirect"
+	ErrorHandlerResponse        = "response"
 	ErrorHandlerWWWAuthenticate = "www_authenticate"
 )
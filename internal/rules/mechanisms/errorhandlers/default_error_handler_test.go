@@ -37,7 +37,7 @@ func TestDefaultErrorHandlerExecution(t *testing.T) {
 	errorHandler := newDefaultErrorHandler("foo")
 
 	// WHEN & THEN
-	require.NoError(t, errorHandler.Execute(ctx, heimdall.ErrConfiguration))
+	require.NoError(t, errorHandler.Execute(ctx, nil, heimdall.ErrConfiguration))
 }
 
 func TestDefaultErrorHandlerPrototype(t *testing.T) {
@@ -18,12 +18,13 @@ package errorhandlers
 
 import (
 	"github.com/dadrus/heimdall/internal/heimdall"
+	"github.com/dadrus/heimdall/internal/rules/mechanisms/subject"
 )
 
 //go:generate mockery --name ErrorHandler --structname ErrorHandlerMock
 
 type ErrorHandler interface {
 	ID() string
-	Execute(ctx heimdall.RequestContext, causeErr error) error
+	Execute(ctx heimdall.RequestContext, sub *subject.Subject, causeErr error) error
 	WithConfig(config map[string]any) (ErrorHandler, error)
 }
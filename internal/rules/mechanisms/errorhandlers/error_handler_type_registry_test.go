@@ -30,7 +30,7 @@ func TestCreateErrorHandlerPrototypePrototype(t *testing.T) {
 	t.Parallel()
 
 	// there are 3 error handlers implemented, which should have been registered
-	require.Len(t, errorHandlerTypeFactories, 3)
+	require.Len(t, errorHandlerTypeFactories, 4)
 
 	for _, tc := range []struct {
 		uc     string
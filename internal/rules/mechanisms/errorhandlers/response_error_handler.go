@@ -0,0 +1,234 @@
+// Copyright 2025 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package errorhandlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/elnormous/contenttype"
+	"github.com/rs/zerolog"
+
+	"github.com/dadrus/heimdall/internal/app"
+	"github.com/dadrus/heimdall/internal/heimdall"
+	"github.com/dadrus/heimdall/internal/rules/mechanisms/subject"
+	"github.com/dadrus/heimdall/internal/rules/mechanisms/template"
+	"github.com/dadrus/heimdall/internal/x/errorchain"
+)
+
+// by intention. Used only during application bootstrap
+//
+//nolint:gochecknoinits
+func init() {
+	registerTypeFactory(
+		func(app app.Context, id string, typ string, conf map[string]any) (bool, ErrorHandler, error) {
+			if typ != ErrorHandlerResponse {
+				return false, nil, nil
+			}
+
+			eh, err := newResponseErrorHandler(app, id, conf)
+
+			return true, eh, err
+		})
+}
+
+type bodyVariant struct {
+	ContentType string            `mapstructure:"content_type" validate:"required"`
+	Template    template.Template `mapstructure:"template"     validate:"required"`
+}
+
+type responseErrorHandler struct {
+	id         string
+	code       template.Template
+	headers    map[string]template.Template
+	bodies     []bodyVariant
+	mediaTypes []contenttype.MediaType
+}
+
+func newResponseErrorHandler(app app.Context, id string, rawConfig map[string]any) (*responseErrorHandler, error) {
+	logger := app.Logger()
+	logger.Info().Str("_id", id).Msg("Creating response error handler")
+
+	type Config struct {
+		Code    template.Template            `mapstructure:"code"    validate:"required"`
+		Headers map[string]template.Template `mapstructure:"headers"`
+		Body    []bodyVariant                `mapstructure:"body"    validate:"dive"`
+	}
+
+	var conf Config
+	if err := decodeConfig(app.Validator(), ErrorHandlerResponse, rawConfig, &conf); err != nil {
+		return nil, err
+	}
+
+	mediaTypes := make([]contenttype.MediaType, len(conf.Body))
+
+	for idx, variant := range conf.Body {
+		mt, err := contenttype.ParseMediaType(variant.ContentType)
+		if err != nil {
+			return nil, errorchain.NewWithMessagef(heimdall.ErrConfiguration,
+				"invalid content type '%s' in body variant %d", variant.ContentType, idx).CausedBy(err)
+		}
+
+		mediaTypes[idx] = mt
+	}
+
+	return &responseErrorHandler{
+		id:         id,
+		code:       conf.Code,
+		headers:    conf.Headers,
+		bodies:     conf.Body,
+		mediaTypes: mediaTypes,
+	}, nil
+}
+
+func (eh *responseErrorHandler) ID() string { return eh.id }
+
+func (eh *responseErrorHandler) Execute(ctx heimdall.RequestContext, sub *subject.Subject, causeErr error) error {
+	logger := zerolog.Ctx(ctx.Context())
+	logger.Debug().Str("_id", eh.id).Msg("Handling error using response error handler")
+
+	var retryAfter string
+
+	var retryAfterErr *heimdall.RetryAfterError
+	if errors.As(causeErr, &retryAfterErr) {
+		retryAfter = retryAfterErr.RetryAfter
+	}
+
+	values := map[string]any{
+		"Request": ctx.Request(),
+		"Subject": sub,
+		"Error": map[string]any{
+			"Type":       errorType(causeErr),
+			"Message":    causeErr.Error(),
+			"RetryAfter": retryAfter,
+		},
+	}
+
+	code, err := eh.renderCode(values)
+	if err != nil {
+		return err
+	}
+
+	body, contentType, err := eh.renderBody(ctx.Request().Header("Accept"), values)
+	if err != nil {
+		return err
+	}
+
+	headers := make(map[string]string, len(eh.headers)+1)
+	if len(contentType) != 0 {
+		headers["Content-Type"] = contentType
+	}
+
+	for name, tpl := range eh.headers {
+		value, err := tpl.Render(values)
+		if err != nil {
+			return errorchain.NewWithMessagef(heimdall.ErrInternal,
+				"failed to render value for '%s' header", name).CausedBy(err)
+		}
+
+		if len(value) != 0 {
+			headers[http.CanonicalHeaderKey(name)] = value
+		}
+	}
+
+	ctx.SetPipelineError(&heimdall.ResponseError{
+		Message: causeErr.Error(),
+		Code:    code,
+		Headers: headers,
+		Body:    body,
+	})
+
+	return nil
+}
+
+func (eh *responseErrorHandler) WithConfig(conf map[string]any) (ErrorHandler, error) {
+	if len(conf) != 0 {
+		return nil, errorchain.NewWithMessage(heimdall.ErrConfiguration,
+			"reconfiguration of a response error handler is not supported")
+	}
+
+	return eh, nil
+}
+
+func (eh *responseErrorHandler) renderCode(values map[string]any) (int, error) {
+	value, err := eh.code.Render(values)
+	if err != nil {
+		return 0, errorchain.NewWithMessage(heimdall.ErrInternal, "failed to render response code").
+			CausedBy(err)
+	}
+
+	code, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil || code < 100 || code > 599 {
+		return 0, errorchain.NewWithMessagef(heimdall.ErrInternal,
+			"rendered response code '%s' is not a valid http status code", value)
+	}
+
+	return code, nil
+}
+
+func (eh *responseErrorHandler) renderBody(accept string, values map[string]any) (string, string, error) {
+	if len(eh.bodies) == 0 {
+		return "", "", nil
+	}
+
+	// the first variant is used if the client did not express any preference or if none
+	// of the configured variants is acceptable for it.
+	variant := eh.bodies[0]
+
+	if len(accept) != 0 {
+		if mt, _, err := contenttype.GetAcceptableMediaTypeFromHeader(accept, eh.mediaTypes); err == nil {
+			for idx, candidate := range eh.mediaTypes {
+				if candidate.Equal(mt) {
+					variant = eh.bodies[idx]
+
+					break
+				}
+			}
+		}
+	}
+
+	body, err := variant.Template.Render(values)
+	if err != nil {
+		return "", "", errorchain.NewWithMessagef(heimdall.ErrInternal,
+			"failed to render '%s' response body", variant.ContentType).CausedBy(err)
+	}
+
+	return body, variant.ContentType, nil
+}
+
+func errorType(err error) string {
+	switch {
+	case errors.Is(err, heimdall.ErrAuthentication):
+		return "authentication_error"
+	case errors.Is(err, heimdall.ErrAuthorization):
+		return "authorization_error"
+	case errors.Is(err, heimdall.ErrRateLimit):
+		return "rate_limit_error"
+	case errors.Is(err, heimdall.ErrServiceUnavailable):
+		return "service_unavailable_error"
+	case errors.Is(err, heimdall.ErrGraphQL):
+		return "graphql_error"
+	case errors.Is(err, heimdall.ErrCommunication), errors.Is(err, heimdall.ErrCommunicationTimeout):
+		return "communication_error"
+	case errors.Is(err, heimdall.ErrArgument):
+		return "precondition_error"
+	default:
+		return "internal_error"
+	}
+}
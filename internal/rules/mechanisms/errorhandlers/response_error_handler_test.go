@@ -0,0 +1,512 @@
+// Copyright 2025 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package errorhandlers
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dadrus/heimdall/internal/app"
+	"github.com/dadrus/heimdall/internal/heimdall"
+	"github.com/dadrus/heimdall/internal/heimdall/mocks"
+	"github.com/dadrus/heimdall/internal/rules/mechanisms/subject"
+	"github.com/dadrus/heimdall/internal/validation"
+	"github.com/dadrus/heimdall/internal/x/errorchain"
+	"github.com/dadrus/heimdall/internal/x/testsupport"
+)
+
+func TestCreateResponseErrorHandler(t *testing.T) {
+	t.Parallel()
+
+	for uc, tc := range map[string]struct {
+		config []byte
+		assert func(t *testing.T, err error, eh *responseErrorHandler)
+	}{
+		"configuration without required 'code' parameter": {
+			config: []byte(`headers: { X-Foo: bar }`),
+			assert: func(t *testing.T, err error, _ *responseErrorHandler) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				assert.Contains(t, err.Error(), "'code' is a required field")
+			},
+		},
+		"with unexpected fields in configuration": {
+			config: []byte(`
+code: "403"
+foo: bar
+`),
+			assert: func(t *testing.T, err error, _ *responseErrorHandler) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				assert.Contains(t, err.Error(), "failed decoding")
+			},
+		},
+		"with body variant without content type": {
+			config: []byte(`
+code: "403"
+body:
+  - template: foo
+`),
+			assert: func(t *testing.T, err error, _ *responseErrorHandler) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				assert.Contains(t, err.Error(), "'body'[0].'content_type' is a required field")
+			},
+		},
+		"with body variant having invalid content type": {
+			config: []byte(`
+code: "403"
+body:
+  - content_type: foo
+    template: bar
+`),
+			assert: func(t *testing.T, err error, _ *responseErrorHandler) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				assert.Contains(t, err.Error(), "invalid content type 'foo'")
+			},
+		},
+		"with minimal valid configuration": {
+			config: []byte(`code: "403"`),
+			assert: func(t *testing.T, err error, eh *responseErrorHandler) {
+				t.Helper()
+
+				require.NoError(t, err)
+				require.NotNil(t, eh)
+				assert.Equal(t, "with minimal valid configuration", eh.ID())
+				assert.Equal(t, "403", eh.code.String())
+				assert.Empty(t, eh.headers)
+				assert.Empty(t, eh.bodies)
+				assert.Empty(t, eh.mediaTypes)
+			},
+		},
+		"with full valid configuration": {
+			config: []byte(`
+code: "{{ if eq .Error.Type \"authentication_error\" }}401{{ else }}403{{ end }}"
+headers:
+  X-Error-Type: "{{ .Error.Type }}"
+body:
+  - content_type: application/json
+    template: '{"error": "{{ .Error.Type }}"}'
+  - content_type: text/html
+    template: "<p>{{ .Error.Type }}</p>"
+`),
+			assert: func(t *testing.T, err error, eh *responseErrorHandler) {
+				t.Helper()
+
+				require.NoError(t, err)
+				require.NotNil(t, eh)
+				assert.Equal(t, "with full valid configuration", eh.ID())
+				assert.Len(t, eh.headers, 1)
+				assert.Contains(t, eh.headers, "X-Error-Type")
+				assert.Len(t, eh.bodies, 2)
+				require.Len(t, eh.mediaTypes, 2)
+				assert.Equal(t, "application/json", eh.mediaTypes[0].MIME())
+				assert.Equal(t, "text/html", eh.mediaTypes[1].MIME())
+			},
+		},
+	} {
+		t.Run(uc, func(t *testing.T) {
+			// GIVEN
+			conf, err := testsupport.DecodeTestConfig(tc.config)
+			require.NoError(t, err)
+
+			validator, err := validation.NewValidator()
+			require.NoError(t, err)
+
+			appCtx := app.NewContextMock(t)
+			appCtx.EXPECT().Validator().Maybe().Return(validator)
+			appCtx.EXPECT().Logger().Return(log.Logger)
+
+			// WHEN
+			errorHandler, err := newResponseErrorHandler(appCtx, uc, conf)
+
+			// THEN
+			tc.assert(t, err, errorHandler)
+		})
+	}
+}
+
+func TestCreateResponseErrorHandlerFromPrototype(t *testing.T) {
+	t.Parallel()
+
+	for uc, tc := range map[string]struct {
+		config []byte
+		assert func(t *testing.T, err error, prototype *responseErrorHandler, configured ErrorHandler)
+	}{
+		"no new configuration provided": {
+			assert: func(t *testing.T, err error, prototype *responseErrorHandler, configured ErrorHandler) {
+				t.Helper()
+
+				require.NoError(t, err)
+				assert.Equal(t, prototype, configured)
+			},
+		},
+		"unsupported configuration provided": {
+			config: []byte(`code: "401"`),
+			assert: func(t *testing.T, err error, _ *responseErrorHandler, _ ErrorHandler) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				require.ErrorContains(t, err, "reconfiguration of a response error handler is not supported")
+			},
+		},
+	} {
+		t.Run(uc, func(t *testing.T) {
+			// GIVEN
+			pc, err := testsupport.DecodeTestConfig([]byte(`code: "403"`))
+			require.NoError(t, err)
+
+			conf, err := testsupport.DecodeTestConfig(tc.config)
+			require.NoError(t, err)
+
+			validator, err := validation.NewValidator()
+			require.NoError(t, err)
+
+			appCtx := app.NewContextMock(t)
+			appCtx.EXPECT().Validator().Maybe().Return(validator)
+			appCtx.EXPECT().Logger().Return(log.Logger)
+
+			prototype, err := newResponseErrorHandler(appCtx, uc, pc)
+			require.NoError(t, err)
+
+			// WHEN
+			errorHandler, err := prototype.WithConfig(conf)
+
+			// THEN
+			tc.assert(t, err, prototype, errorHandler)
+		})
+	}
+}
+
+func TestResponseErrorHandlerExecute(t *testing.T) {
+	t.Parallel()
+
+	fullConfig := []byte(`
+code: "{{ if eq .Error.Type \"authentication_error\" }}401{{ else }}403{{ end }}"
+headers:
+  x-error-type: "{{ .Error.Type }}"
+  Cache-Control: no-store
+body:
+  - content_type: application/json
+    template: '{"error": "{{ .Error.Type }}", "subject": "{{ if .Subject }}{{ .Subject.ID }}{{ end }}"}'
+  - content_type: text/html
+    template: "<p>{{ .Error.Message }} for {{ .Request.URL.Path }}</p>"
+`)
+
+	for uc, tc := range map[string]struct {
+		config           []byte
+		subject          *subject.Subject
+		error            error
+		configureContext func(t *testing.T, ctx *mocks.RequestContextMock)
+		assert           func(t *testing.T, err error)
+	}{
+		"with code template rendering error": {
+			config: []byte(`code: "{{ len .foobar }}"`),
+			error:  heimdall.ErrAuthentication,
+			configureContext: func(t *testing.T, ctx *mocks.RequestContextMock) {
+				t.Helper()
+
+				ctx.EXPECT().Request().Return(nil)
+			},
+			assert: func(t *testing.T, err error) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrInternal)
+				assert.Contains(t, err.Error(), "failed to render response code")
+			},
+		},
+		"with code rendered to an invalid value": {
+			config: []byte(`code: "foo"`),
+			error:  heimdall.ErrAuthentication,
+			configureContext: func(t *testing.T, ctx *mocks.RequestContextMock) {
+				t.Helper()
+
+				ctx.EXPECT().Request().Return(nil)
+			},
+			assert: func(t *testing.T, err error) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrInternal)
+				assert.Contains(t, err.Error(), "'foo' is not a valid http status code")
+			},
+		},
+		"with header template rendering error": {
+			config: []byte(`
+code: "403"
+headers:
+  X-Foo: "{{ len .foobar }}"
+`),
+			error: heimdall.ErrAuthorization,
+			configureContext: func(t *testing.T, ctx *mocks.RequestContextMock) {
+				t.Helper()
+
+				reqf := mocks.NewRequestFunctionsMock(t)
+				reqf.EXPECT().Header("Accept").Return("")
+
+				ctx.EXPECT().Request().Return(&heimdall.Request{RequestFunctions: reqf})
+			},
+			assert: func(t *testing.T, err error) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrInternal)
+				assert.Contains(t, err.Error(), "failed to render value for 'X-Foo' header")
+			},
+		},
+		"with body template rendering error": {
+			config: []byte(`
+code: "403"
+body:
+  - content_type: text/plain
+    template: "{{ len .foobar }}"
+`),
+			error: heimdall.ErrAuthorization,
+			configureContext: func(t *testing.T, ctx *mocks.RequestContextMock) {
+				t.Helper()
+
+				reqf := mocks.NewRequestFunctionsMock(t)
+				reqf.EXPECT().Header("Accept").Return("")
+
+				ctx.EXPECT().Request().Return(&heimdall.Request{RequestFunctions: reqf})
+			},
+			assert: func(t *testing.T, err error) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrInternal)
+				assert.Contains(t, err.Error(), "failed to render 'text/plain' response body")
+			},
+		},
+		"with retry hint and empty header": {
+			config: []byte(`
+code: "429"
+headers:
+  Retry-After: "{{ .Error.RetryAfter }}"
+  X-Subject: "{{ if .Subject }}{{ .Subject.ID }}{{ end }}"
+`),
+			error: errorchain.New(heimdall.ErrRateLimit).
+				CausedBy(&heimdall.RetryAfterError{RetryAfter: "120"}),
+			configureContext: func(t *testing.T, ctx *mocks.RequestContextMock) {
+				t.Helper()
+
+				reqf := mocks.NewRequestFunctionsMock(t)
+				reqf.EXPECT().Header("Accept").Return("")
+
+				ctx.EXPECT().Request().Return(&heimdall.Request{RequestFunctions: reqf})
+				ctx.EXPECT().SetPipelineError(mock.MatchedBy(func(respErr *heimdall.ResponseError) bool {
+					t.Helper()
+
+					assert.Equal(t, http.StatusTooManyRequests, respErr.Code)
+					assert.Equal(t, map[string]string{"Retry-After": "120"}, respErr.Headers)
+
+					return true
+				}))
+			},
+			assert: func(t *testing.T, err error) {
+				t.Helper()
+
+				require.NoError(t, err)
+			},
+		},
+		"with code only": {
+			config: []byte(`code: "418"`),
+			error:  heimdall.ErrArgument,
+			configureContext: func(t *testing.T, ctx *mocks.RequestContextMock) {
+				t.Helper()
+
+				reqf := mocks.NewRequestFunctionsMock(t)
+				reqf.EXPECT().Header("Accept").Return("application/json")
+
+				ctx.EXPECT().Request().Return(&heimdall.Request{RequestFunctions: reqf})
+				ctx.EXPECT().SetPipelineError(mock.MatchedBy(func(respErr *heimdall.ResponseError) bool {
+					t.Helper()
+
+					assert.Equal(t, http.StatusTeapot, respErr.Code)
+					assert.Empty(t, respErr.Headers)
+					assert.Empty(t, respErr.Body)
+					assert.Equal(t, heimdall.ErrArgument.Error(), respErr.Message)
+
+					return true
+				}))
+			},
+			assert: func(t *testing.T, err error) {
+				t.Helper()
+
+				require.NoError(t, err)
+			},
+		},
+		"without accept header and subject": {
+			config: fullConfig,
+			error:  errorchain.NewWithMessage(heimdall.ErrAuthentication, "no token"),
+			configureContext: func(t *testing.T, ctx *mocks.RequestContextMock) {
+				t.Helper()
+
+				reqf := mocks.NewRequestFunctionsMock(t)
+				reqf.EXPECT().Header("Accept").Return("")
+
+				ctx.EXPECT().Request().Return(&heimdall.Request{RequestFunctions: reqf})
+				ctx.EXPECT().SetPipelineError(mock.MatchedBy(func(respErr *heimdall.ResponseError) bool {
+					t.Helper()
+
+					assert.Equal(t, http.StatusUnauthorized, respErr.Code)
+					assert.Equal(t, map[string]string{
+						"Content-Type":  "application/json",
+						"Cache-Control": "no-store",
+						"X-Error-Type":  "authentication_error",
+					}, respErr.Headers)
+					assert.JSONEq(t, `{"error": "authentication_error", "subject": ""}`, respErr.Body)
+
+					return true
+				}))
+			},
+			assert: func(t *testing.T, err error) {
+				t.Helper()
+
+				require.NoError(t, err)
+			},
+		},
+		"with subject and json preferred by the client": {
+			config:  fullConfig,
+			subject: &subject.Subject{ID: "foo"},
+			error:   errorchain.NewWithMessage(heimdall.ErrAuthorization, "denied"),
+			configureContext: func(t *testing.T, ctx *mocks.RequestContextMock) {
+				t.Helper()
+
+				reqf := mocks.NewRequestFunctionsMock(t)
+				reqf.EXPECT().Header("Accept").Return("text/html;q=0.5, application/json")
+
+				ctx.EXPECT().Request().Return(&heimdall.Request{RequestFunctions: reqf})
+				ctx.EXPECT().SetPipelineError(mock.MatchedBy(func(respErr *heimdall.ResponseError) bool {
+					t.Helper()
+
+					assert.Equal(t, http.StatusForbidden, respErr.Code)
+					assert.Equal(t, "application/json", respErr.Headers["Content-Type"])
+					assert.Equal(t, "authorization_error", respErr.Headers["X-Error-Type"])
+					assert.JSONEq(t, `{"error": "authorization_error", "subject": "foo"}`, respErr.Body)
+
+					return true
+				}))
+			},
+			assert: func(t *testing.T, err error) {
+				t.Helper()
+
+				require.NoError(t, err)
+			},
+		},
+		"with html preferred by the client": {
+			config: fullConfig,
+			error:  errorchain.NewWithMessage(heimdall.ErrCommunication, "upstream unreachable"),
+			configureContext: func(t *testing.T, ctx *mocks.RequestContextMock) {
+				t.Helper()
+
+				reqf := mocks.NewRequestFunctionsMock(t)
+				reqf.EXPECT().Header("Accept").Return("text/html,application/xhtml+xml,*/*;q=0.8")
+
+				ctx.EXPECT().Request().Return(&heimdall.Request{
+					RequestFunctions: reqf,
+					URL:              &heimdall.URL{URL: url.URL{Path: "/foo"}},
+				})
+				ctx.EXPECT().SetPipelineError(mock.MatchedBy(func(respErr *heimdall.ResponseError) bool {
+					t.Helper()
+
+					assert.Equal(t, http.StatusForbidden, respErr.Code)
+					assert.Equal(t, "text/html", respErr.Headers["Content-Type"])
+					assert.Equal(t, "communication_error", respErr.Headers["X-Error-Type"])
+					assert.Equal(t, "<p>communication error: upstream unreachable for /foo</p>", respErr.Body)
+
+					return true
+				}))
+			},
+			assert: func(t *testing.T, err error) {
+				t.Helper()
+
+				require.NoError(t, err)
+			},
+		},
+		"with no acceptable body variant": {
+			config: fullConfig,
+			error:  heimdall.ErrInternal,
+			configureContext: func(t *testing.T, ctx *mocks.RequestContextMock) {
+				t.Helper()
+
+				reqf := mocks.NewRequestFunctionsMock(t)
+				reqf.EXPECT().Header("Accept").Return("application/xml")
+
+				ctx.EXPECT().Request().Return(&heimdall.Request{RequestFunctions: reqf})
+				ctx.EXPECT().SetPipelineError(mock.MatchedBy(func(respErr *heimdall.ResponseError) bool {
+					t.Helper()
+
+					assert.Equal(t, http.StatusForbidden, respErr.Code)
+					assert.Equal(t, "application/json", respErr.Headers["Content-Type"])
+					assert.JSONEq(t, `{"error": "internal_error", "subject": ""}`, respErr.Body)
+
+					return true
+				}))
+			},
+			assert: func(t *testing.T, err error) {
+				t.Helper()
+
+				require.NoError(t, err)
+			},
+		},
+	} {
+		t.Run(uc, func(t *testing.T) {
+			// GIVEN
+			conf, err := testsupport.DecodeTestConfig(tc.config)
+			require.NoError(t, err)
+
+			mctx := mocks.NewRequestContextMock(t)
+			mctx.EXPECT().Context().Return(t.Context())
+
+			tc.configureContext(t, mctx)
+
+			validator, err := validation.NewValidator()
+			require.NoError(t, err)
+
+			appCtx := app.NewContextMock(t)
+			appCtx.EXPECT().Validator().Maybe().Return(validator)
+			appCtx.EXPECT().Logger().Return(log.Logger)
+
+			errorHandler, err := newResponseErrorHandler(appCtx, "foo", conf)
+			require.NoError(t, err)
+
+			// WHEN
+			execErr := errorHandler.Execute(mctx, tc.subject, tc.error)
+
+			// THEN
+			tc.assert(t, execErr)
+		})
+	}
+}
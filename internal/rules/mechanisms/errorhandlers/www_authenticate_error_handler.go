@@ -21,6 +21,7 @@ import (
 
 	"github.com/dadrus/heimdall/internal/app"
 	"github.com/dadrus/heimdall/internal/heimdall"
+	"github.com/dadrus/heimdall/internal/rules/mechanisms/subject"
 	"github.com/dadrus/heimdall/internal/x"
 )
 
@@ -70,7 +71,7 @@ func newWWWAuthenticateErrorHandler(
 
 func (eh *wwwAuthenticateErrorHandler) ID() string { return eh.id }
 
-func (eh *wwwAuthenticateErrorHandler) Execute(ctx heimdall.RequestContext, _ error) error {
+func (eh *wwwAuthenticateErrorHandler) Execute(ctx heimdall.RequestContext, _ *subject.Subject, _ error) error {
 	logger := zerolog.Ctx(ctx.Context())
 	logger.Debug().Str("_id", eh.id).Msg("Handling error using www-authenticate error handler")
 
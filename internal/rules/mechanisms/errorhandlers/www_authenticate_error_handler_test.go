@@ -279,7 +279,7 @@ func TestWWWAuthenticateErrorHandlerExecute(t *testing.T) {
 			require.NoError(t, err)
 
 			// WHEN
-			execErr := errorHandler.Execute(mctx, tc.error)
+			execErr := errorHandler.Execute(mctx, nil, tc.error)
 
 			// THEN
 			tc.assert(t, execErr)
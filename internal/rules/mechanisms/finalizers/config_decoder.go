@@ -19,15 +19,21 @@ package finalizers
 import (
 	"github.com/go-viper/mapstructure/v2"
 
+	"github.com/dadrus/heimdall/internal/app"
+	"github.com/dadrus/heimdall/internal/rules/endpoint"
+	"github.com/dadrus/heimdall/internal/rules/endpoint/authstrategy"
+	"github.com/dadrus/heimdall/internal/rules/mechanisms/authenticators/extractors"
 	"github.com/dadrus/heimdall/internal/rules/mechanisms/template"
-	"github.com/dadrus/heimdall/internal/validation"
 )
 
-func decodeConfig(validator validation.Validator, input, output any) error {
+func decodeConfig(app app.Context, input, output any) error {
 	dec, err := mapstructure.NewDecoder(
 		&mapstructure.DecoderConfig{
 			DecodeHook: mapstructure.ComposeDecodeHookFunc(
+				authstrategy.DecodeAuthenticationStrategyHookFunc(app),
+				endpoint.DecodeEndpointHookFunc(),
 				mapstructure.StringToTimeDurationHookFunc(),
+				extractors.DecodeCompositeExtractStrategyHookFunc(),
 				template.DecodeTemplateHookFunc(),
 			),
 			Result:      output,
@@ -41,7 +47,7 @@ func decodeConfig(validator validation.Validator, input, output any) error {
 		return err
 	}
 
-	if err = validator.ValidateStruct(output); err != nil {
+	if err = app.Validator().ValidateStruct(output); err != nil {
 		return err
 	}
 
@@ -22,4 +22,9 @@ const (
 	FinalizerHeader                  = "header"
 	FinalizerCookie                  = "cookie"
 	FinalizerOAuth2ClientCredentials = "oauth2_client_credentials" // nolint: gosec
+	FinalizerHTTPMessageSignatures   = "http_message_signatures"
+	FinalizerTokenExchange           = "token_exchange" // nolint: gosec
+	FinalizerPASETO                  = "paseto"
+	FinalizerRemove                  = "remove"
+	FinalizerURL                     = "url"
 )
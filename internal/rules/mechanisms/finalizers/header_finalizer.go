@@ -59,7 +59,7 @@ func newHeaderFinalizer(app app.Context, id string, rawConfig map[string]any) (*
 	}
 
 	var conf Config
-	if err := decodeConfig(app.Validator(), rawConfig, &conf); err != nil {
+	if err := decodeConfig(app, rawConfig, &conf); err != nil {
 		return nil, errorchain.NewWithMessagef(heimdall.ErrConfiguration,
 			"failed decoding config for header finalizer '%s'", id).CausedBy(err)
 	}
@@ -118,7 +118,7 @@ func (f *headerFinalizer) WithConfig(rawConfig map[string]any) (Finalizer, error
 	}
 
 	var conf Config
-	if err := decodeConfig(f.app.Validator(), rawConfig, &conf); err != nil {
+	if err := decodeConfig(f.app, rawConfig, &conf); err != nil {
 		return nil, errorchain.NewWithMessagef(heimdall.ErrConfiguration,
 			"failed decoding config for header finalizer '%s'", f.id).CausedBy(err)
 	}
@@ -0,0 +1,129 @@
+// Copyright 2025 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package finalizers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/dadrus/heimdall/internal/app"
+	"github.com/dadrus/heimdall/internal/heimdall"
+	"github.com/dadrus/heimdall/internal/rules/endpoint/authstrategy"
+	"github.com/dadrus/heimdall/internal/rules/mechanisms/subject"
+	"github.com/dadrus/heimdall/internal/x/errorchain"
+)
+
+// by intention. Used only during application bootstrap
+//
+//nolint:gochecknoinits
+func init() {
+	registerTypeFactory(
+		func(app app.Context, id string, typ string, conf map[string]any) (bool, Finalizer, error) {
+			if typ != FinalizerHTTPMessageSignatures {
+				return false, nil, nil
+			}
+
+			finalizer, err := newHTTPMessageSignaturesFinalizer(app, id, conf)
+
+			return true, finalizer, err
+		})
+}
+
+// httpMessageSignaturesFinalizer signs the request forwarded to the upstream service according to
+// RFC 9421. Since the final request is known only after the pipeline has been executed, the finalizer
+// registers itself as upstream request signer and the actual signing happens on request finalization.
+type httpMessageSignaturesFinalizer struct {
+	id     string
+	signer *authstrategy.HTTPMessageSignatures
+}
+
+func newHTTPMessageSignaturesFinalizer(
+	app app.Context,
+	id string,
+	rawConfig map[string]any,
+) (*httpMessageSignaturesFinalizer, error) {
+	logger := app.Logger()
+	logger.Info().Str("_id", id).Msg("Creating http_message_signatures finalizer")
+
+	type Config struct {
+		Signer     authstrategy.SignerConfig `mapstructure:"signer"     validate:"required"`
+		Components []string                  `mapstructure:"components" validate:"gt=0,dive,required"`
+		TTL        *time.Duration            `mapstructure:"ttl"`
+		Label      string                    `mapstructure:"label"`
+	}
+
+	var conf Config
+	if err := decodeConfig(app, rawConfig, &conf); err != nil {
+		return nil, errorchain.NewWithMessagef(heimdall.ErrConfiguration,
+			"failed decoding config for http_message_signatures finalizer '%s'", id).CausedBy(err)
+	}
+
+	signer := &authstrategy.HTTPMessageSignatures{
+		Signer:     conf.Signer,
+		Components: conf.Components,
+		TTL:        conf.TTL,
+		Label:      conf.Label,
+	}
+
+	if err := signer.Init(); err != nil {
+		return nil, err
+	}
+
+	if err := app.Watcher().Add(signer.Signer.KeyStore.Path, signer); err != nil {
+		return nil, errorchain.NewWithMessagef(heimdall.ErrInternal,
+			"failed registering http_message_signatures finalizer '%s' for updates", id).CausedBy(err)
+	}
+
+	app.KeyHolderRegistry().AddKeyHolder(signer)
+	app.CertificateObserver().Add(signer)
+
+	return &httpMessageSignaturesFinalizer{id: id, signer: signer}, nil
+}
+
+func (f *httpMessageSignaturesFinalizer) Execute(ctx heimdall.RequestContext, _ *subject.Subject) error {
+	logger := zerolog.Ctx(ctx.Context())
+	logger.Debug().Str("_id", f.id).Msg("Finalizing using http_message_signatures finalizer")
+
+	ctx.AddSignerForUpstream(f)
+
+	return nil
+}
+
+func (f *httpMessageSignaturesFinalizer) Sign(req *http.Request) error {
+	if err := f.signer.Apply(req.Context(), req); err != nil {
+		return errorchain.NewWithMessage(heimdall.ErrInternal, "failed to sign request").
+			WithErrorContext(f).
+			CausedBy(err)
+	}
+
+	return nil
+}
+
+func (f *httpMessageSignaturesFinalizer) WithConfig(rawConfig map[string]any) (Finalizer, error) {
+	if len(rawConfig) != 0 {
+		return nil, errorchain.NewWithMessage(heimdall.ErrConfiguration,
+			"reconfiguration of a http_message_signatures finalizer is not supported")
+	}
+
+	return f, nil
+}
+
+func (f *httpMessageSignaturesFinalizer) ID() string { return f.id }
+
+func (f *httpMessageSignaturesFinalizer) ContinueOnError() bool { return false }
@@ -0,0 +1,318 @@
+// Copyright 2025 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package finalizers
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dadrus/httpsig"
+	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dadrus/heimdall/internal/app"
+	"github.com/dadrus/heimdall/internal/heimdall"
+	heimdallmocks "github.com/dadrus/heimdall/internal/heimdall/mocks"
+	mocks3 "github.com/dadrus/heimdall/internal/keyholder/mocks"
+	mocks4 "github.com/dadrus/heimdall/internal/otel/metrics/certificate/mocks"
+	"github.com/dadrus/heimdall/internal/validation"
+	mocks2 "github.com/dadrus/heimdall/internal/watcher/mocks"
+	"github.com/dadrus/heimdall/internal/x/pkix/pemx"
+	"github.com/dadrus/heimdall/internal/x/testsupport"
+)
+
+type keyResolverFunc func(ctx context.Context, keyID string) (httpsig.Key, error)
+
+func (f keyResolverFunc) ResolveKey(ctx context.Context, keyID string) (httpsig.Key, error) {
+	return f(ctx, keyID)
+}
+
+func TestCreateHTTPMessageSignaturesFinalizer(t *testing.T) {
+	t.Parallel()
+
+	privKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	pemBytes, err := pemx.BuildPEM(
+		pemx.WithECDSAPrivateKey(privKey, pemx.WithHeader("X-Key-ID", "key")),
+	)
+	require.NoError(t, err)
+
+	testDir := t.TempDir()
+	pemFile := filepath.Join(testDir, "keystore.pem")
+
+	err = os.WriteFile(pemFile, pemBytes, 0o600)
+	require.NoError(t, err)
+
+	for uc, tc := range map[string]struct {
+		config              []byte
+		configureAppContext func(t *testing.T, ctx *app.ContextMock)
+		assert              func(t *testing.T, err error, finalizer *httpMessageSignaturesFinalizer)
+	}{
+		"without config": {
+			configureAppContext: func(t *testing.T, _ *app.ContextMock) { t.Helper() },
+			assert: func(t *testing.T, err error, _ *httpMessageSignaturesFinalizer) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				require.ErrorContains(t, err, "'signer' is a required field")
+				require.ErrorContains(t, err, "'components' must contain more than 0 items")
+			},
+		},
+		"with unsupported properties": {
+			config: []byte(`
+signer:
+  key_store:
+    path: ` + pemFile + `
+components: ["@method"]
+foo: bar
+`),
+			configureAppContext: func(t *testing.T, _ *app.ContextMock) { t.Helper() },
+			assert: func(t *testing.T, err error, _ *httpMessageSignaturesFinalizer) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				require.ErrorContains(t, err, "invalid keys: foo")
+			},
+		},
+		"with not existing key store": {
+			config: []byte(`
+signer:
+  key_store:
+    path: /does/not/exist.pem
+components: ["@method"]
+`),
+			configureAppContext: func(t *testing.T, _ *app.ContextMock) { t.Helper() },
+			assert: func(t *testing.T, err error, _ *httpMessageSignaturesFinalizer) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				require.ErrorContains(t, err, "failed loading keystore")
+			},
+		},
+		"with not existing key id": {
+			config: []byte(`
+signer:
+  key_store:
+    path: ` + pemFile + `
+  key_id: foo
+components: ["@method"]
+`),
+			configureAppContext: func(t *testing.T, _ *app.ContextMock) { t.Helper() },
+			assert: func(t *testing.T, err error, _ *httpMessageSignaturesFinalizer) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				require.ErrorContains(t, err, "failed retrieving key")
+			},
+		},
+		"with full valid configuration": {
+			config: []byte(`
+signer:
+  name: foo
+  key_store:
+    path: ` + pemFile + `
+  key_id: key
+components: ["@method", "@authority", "content-digest"]
+ttl: 2m
+label: bar
+`),
+			configureAppContext: func(t *testing.T, ctx *app.ContextMock) {
+				t.Helper()
+
+				wm := mocks2.NewWatcherMock(t)
+				wm.EXPECT().Add(pemFile, mock.Anything).Return(nil)
+
+				khr := mocks3.NewRegistryMock(t)
+				khr.EXPECT().AddKeyHolder(mock.Anything)
+
+				co := mocks4.NewObserverMock(t)
+				co.EXPECT().Add(mock.Anything)
+
+				ctx.EXPECT().Watcher().Return(wm)
+				ctx.EXPECT().KeyHolderRegistry().Return(khr)
+				ctx.EXPECT().CertificateObserver().Return(co)
+			},
+			assert: func(t *testing.T, err error, finalizer *httpMessageSignaturesFinalizer) {
+				t.Helper()
+
+				require.NoError(t, err)
+				require.NotNil(t, finalizer)
+				assert.Equal(t, "with full valid configuration", finalizer.ID())
+				assert.False(t, finalizer.ContinueOnError())
+				require.NotNil(t, finalizer.signer)
+				assert.Equal(t, "foo", finalizer.signer.Signer.Name)
+				assert.Equal(t, []string{"@method", "@authority", "content-digest"}, finalizer.signer.Components)
+				assert.Equal(t, 2*time.Minute, *finalizer.signer.TTL)
+				assert.Equal(t, "bar", finalizer.signer.Label)
+				require.Len(t, finalizer.signer.Keys(), 1)
+				assert.Equal(t, "key", finalizer.signer.Keys()[0].KeyID)
+			},
+		},
+	} {
+		t.Run(uc, func(t *testing.T) {
+			conf, err := testsupport.DecodeTestConfig(tc.config)
+			require.NoError(t, err)
+
+			validator, err := validation.NewValidator()
+			require.NoError(t, err)
+
+			appCtx := app.NewContextMock(t)
+			appCtx.EXPECT().Validator().Maybe().Return(validator)
+			appCtx.EXPECT().Logger().Return(log.Logger)
+
+			tc.configureAppContext(t, appCtx)
+
+			// WHEN
+			finalizer, err := newHTTPMessageSignaturesFinalizer(appCtx, uc, conf)
+
+			// THEN
+			tc.assert(t, err, finalizer)
+		})
+	}
+}
+
+func TestCreateHTTPMessageSignaturesFinalizerFromPrototype(t *testing.T) {
+	t.Parallel()
+
+	prototype := &httpMessageSignaturesFinalizer{id: "test"}
+
+	// without config
+	finalizer, err := prototype.WithConfig(nil)
+	require.NoError(t, err)
+	assert.Equal(t, prototype, finalizer)
+
+	// with config
+	_, err = prototype.WithConfig(map[string]any{"label": "foo"})
+	require.Error(t, err)
+	require.ErrorIs(t, err, heimdall.ErrConfiguration)
+	require.ErrorContains(t, err, "reconfiguration of a http_message_signatures finalizer is not supported")
+}
+
+func TestHTTPMessageSignaturesFinalizerExecute(t *testing.T) {
+	t.Parallel()
+
+	privKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	pemBytes, err := pemx.BuildPEM(
+		pemx.WithECDSAPrivateKey(privKey, pemx.WithHeader("X-Key-ID", "key")),
+	)
+	require.NoError(t, err)
+
+	pemFile := filepath.Join(t.TempDir(), "keystore.pem")
+
+	err = os.WriteFile(pemFile, pemBytes, 0o600)
+	require.NoError(t, err)
+
+	conf, err := testsupport.DecodeTestConfig([]byte(`
+signer:
+  key_store:
+    path: ` + pemFile + `
+components: ["@method", "@authority", "x-foo", "content-digest"]
+label: test
+`))
+	require.NoError(t, err)
+
+	validator, err := validation.NewValidator()
+	require.NoError(t, err)
+
+	wm := mocks2.NewWatcherMock(t)
+	wm.EXPECT().Add(pemFile, mock.Anything).Return(nil)
+
+	khr := mocks3.NewRegistryMock(t)
+	khr.EXPECT().AddKeyHolder(mock.Anything)
+
+	co := mocks4.NewObserverMock(t)
+	co.EXPECT().Add(mock.Anything)
+
+	appCtx := app.NewContextMock(t)
+	appCtx.EXPECT().Validator().Return(validator)
+	appCtx.EXPECT().Logger().Return(log.Logger)
+	appCtx.EXPECT().Watcher().Return(wm)
+	appCtx.EXPECT().KeyHolderRegistry().Return(khr)
+	appCtx.EXPECT().CertificateObserver().Return(co)
+
+	finalizer, err := newHTTPMessageSignaturesFinalizer(appCtx, "test", conf)
+	require.NoError(t, err)
+
+	var signer heimdall.UpstreamRequestSigner
+
+	ctx := heimdallmocks.NewRequestContextMock(t)
+	ctx.EXPECT().Context().Return(t.Context())
+	ctx.EXPECT().AddSignerForUpstream(mock.Anything).Run(func(s heimdall.UpstreamRequestSigner) {
+		signer = s
+	})
+
+	// WHEN
+	err = finalizer.Execute(ctx, nil)
+
+	// THEN
+	require.NoError(t, err)
+	require.NotNil(t, signer)
+
+	// WHEN
+	req, err := http.NewRequestWithContext(t.Context(), http.MethodPost,
+		"https://upstream.local/foo", strings.NewReader(`{"foo": "bar"}`))
+	require.NoError(t, err)
+	req.Header.Set("X-Foo", "bar")
+
+	err = signer.Sign(req)
+
+	// THEN
+	require.NoError(t, err)
+	assert.Equal(t, "bar", req.Header.Get("X-Foo"))
+	assert.NotEmpty(t, req.Header.Get("Content-Digest"))
+	assert.Contains(t, req.Header.Get("Signature-Input"),
+		`test=("@method" "@authority" "x-foo" "content-digest")`)
+	assert.Contains(t, req.Header.Get("Signature-Input"), `keyid="key"`)
+	assert.Contains(t, req.Header.Get("Signature"), "test=:")
+
+	verifier, err := httpsig.NewVerifier(
+		keyResolverFunc(func(_ context.Context, _ string) (httpsig.Key, error) {
+			return httpsig.Key{KeyID: "key", Algorithm: httpsig.EcdsaP256Sha256, Key: &privKey.PublicKey}, nil
+		}),
+		httpsig.WithRequiredTag("heimdall",
+			httpsig.WithRequiredComponents("@method", "@authority", "x-foo", "content-digest")),
+	)
+	require.NoError(t, err)
+
+	err = verifier.Verify(httpsig.MessageFromRequest(req))
+	require.NoError(t, err)
+
+	// WHEN
+	req.Header.Set("X-Foo", "baz")
+
+	err = verifier.Verify(httpsig.MessageFromRequest(req))
+
+	// THEN
+	require.Error(t, err)
+}
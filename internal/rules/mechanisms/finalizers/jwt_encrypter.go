@@ -0,0 +1,366 @@
+// Copyright 2025 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package finalizers
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"net/http"
+	"os"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/go-jose/go-jose/v4"
+	"github.com/goccy/go-json"
+	"github.com/rs/zerolog"
+
+	"github.com/dadrus/heimdall/internal/cache"
+	"github.com/dadrus/heimdall/internal/heimdall"
+	"github.com/dadrus/heimdall/internal/rules/endpoint"
+	"github.com/dadrus/heimdall/internal/watcher"
+	"github.com/dadrus/heimdall/internal/x"
+	"github.com/dadrus/heimdall/internal/x/errorchain"
+	"github.com/dadrus/heimdall/internal/x/pkix"
+	"github.com/dadrus/heimdall/internal/x/stringx"
+)
+
+const defaultEncryptionKeyCacheTTL = 10 * time.Minute
+
+var (
+	errNoEncryptionKey = errors.New("no key usable for encryption found")
+
+	supportedKeyAlgorithms = []jose.KeyAlgorithm{ //nolint:gochecknoglobals
+		jose.RSA_OAEP, jose.RSA_OAEP_256,
+		jose.ECDH_ES, jose.ECDH_ES_A128KW, jose.ECDH_ES_A192KW, jose.ECDH_ES_A256KW,
+	}
+)
+
+type EncryptionConfig struct {
+	KeyFile           string             `mapstructure:"key_file"           validate:"required_without=JWKSEndpoint,excluded_with=JWKSEndpoint"`                                   //nolint:lll,tagalign
+	JWKSEndpoint      *endpoint.Endpoint `mapstructure:"jwks_endpoint"      validate:"required_without=KeyFile"`                                                                   //nolint:lll,tagalign
+	KeyID             string             `mapstructure:"key_id"`                                                                                                                   //nolint:lll,tagalign
+	KeyAlgorithm      string             `mapstructure:"key_algorithm"      validate:"omitempty,oneof=RSA-OAEP RSA-OAEP-256 ECDH-ES ECDH-ES+A128KW ECDH-ES+A192KW ECDH-ES+A256KW"` //nolint:lll,tagalign
+	ContentEncryption string             `mapstructure:"content_encryption" validate:"omitempty,oneof=A128GCM A192GCM A256GCM A128CBC-HS256 A192CBC-HS384 A256CBC-HS512"`          //nolint:lll,tagalign
+	CacheTTL          *time.Duration     `mapstructure:"cache_ttl"          validate:"omitempty,excluded_with=KeyFile"`                                                            //nolint:lll,tagalign
+}
+
+// jwtEncrypter wraps signed tokens into JWEs (nested JWTs) encrypted to the public key of the upstream service.
+// The key is either loaded from a file, which is watched for changes, or retrieved from a JWKS endpoint.
+type jwtEncrypter struct {
+	path  string
+	ep    *endpoint.Endpoint
+	keyID string
+	alg   jose.KeyAlgorithm
+	enc   jose.ContentEncryption
+	ttl   time.Duration
+
+	mut sync.RWMutex
+	jwk *jose.JSONWebKey
+}
+
+func newJWTEncrypter(conf *EncryptionConfig, fw watcher.Watcher) (*jwtEncrypter, error) {
+	enc := &jwtEncrypter{
+		path:  conf.KeyFile,
+		ep:    conf.JWKSEndpoint,
+		keyID: conf.KeyID,
+		alg:   jose.KeyAlgorithm(conf.KeyAlgorithm),
+		enc: x.IfThenElse(len(conf.ContentEncryption) == 0,
+			jose.A256GCM, jose.ContentEncryption(conf.ContentEncryption)),
+		ttl: x.IfThenElseExec(conf.CacheTTL != nil,
+			func() time.Duration { return *conf.CacheTTL },
+			func() time.Duration { return defaultEncryptionKeyCacheTTL }),
+	}
+
+	if enc.ep != nil {
+		if enc.ep.Headers == nil {
+			enc.ep.Headers = make(map[string]string)
+		}
+
+		if _, ok := enc.ep.Headers["Accept"]; !ok {
+			enc.ep.Headers["Accept"] = "application/json"
+		}
+
+		if len(enc.ep.Method) == 0 {
+			enc.ep.Method = http.MethodGet
+		}
+
+		return enc, nil
+	}
+
+	if err := enc.load(); err != nil {
+		return nil, err
+	}
+
+	if err := fw.Add(enc.path, enc); err != nil {
+		return nil, errorchain.NewWithMessage(heimdall.ErrInternal, "failed registering jwt encrypter for updates").
+			CausedBy(err)
+	}
+
+	return enc, nil
+}
+
+func (e *jwtEncrypter) OnChanged(logger zerolog.Logger) {
+	err := e.load()
+	if err != nil {
+		logger.Warn().Err(err).
+			Str("_file", e.path).
+			Msg("Encryption key file reload failed")
+	} else {
+		logger.Info().
+			Str("_file", e.path).
+			Msg("Encryption key file reloaded")
+	}
+}
+
+func (e *jwtEncrypter) load() error {
+	contents, err := os.ReadFile(e.path)
+	if err != nil {
+		return errorchain.NewWithMessage(heimdall.ErrConfiguration, "failed reading encryption key file").
+			CausedBy(err)
+	}
+
+	keys, err := parseEncryptionKeys(contents)
+	if err != nil {
+		return errorchain.NewWithMessage(heimdall.ErrConfiguration, "failed parsing encryption key file").
+			CausedBy(err)
+	}
+
+	jwk, err := e.selectKey(keys)
+	if err != nil {
+		return errorchain.NewWithMessage(heimdall.ErrConfiguration, "failed loading encryption key").
+			CausedBy(err)
+	}
+
+	e.mut.Lock()
+	e.jwk = jwk
+	e.mut.Unlock()
+
+	return nil
+}
+
+func (e *jwtEncrypter) Hash() []byte {
+	hash := sha256.New()
+	hash.Write(stringx.ToBytes(e.keyID))
+	hash.Write(stringx.ToBytes(string(e.alg)))
+	hash.Write(stringx.ToBytes(string(e.enc)))
+
+	if e.ep != nil {
+		hash.Write(e.ep.Hash())
+	} else {
+		e.mut.RLock()
+		hash.Write(stringx.ToBytes(e.jwk.KeyID))
+		e.mut.RUnlock()
+	}
+
+	return hash.Sum(nil)
+}
+
+// Encrypt wraps the given token into a JWE encrypted to the given key, which is expected to be
+// the one resolved by EncryptionKey.
+func (e *jwtEncrypter) Encrypt(token string, jwk *jose.JSONWebKey) (string, error) {
+	encrypter, err := jose.NewEncrypter(
+		e.enc,
+		jose.Recipient{Algorithm: e.keyAlgorithm(jwk), Key: jwk.Key, KeyID: jwk.KeyID},
+		(&jose.EncrypterOptions{}).WithContentType("JWT"),
+	)
+	if err != nil {
+		return "", errorchain.NewWithMessage(heimdall.ErrInternal, "failed to create encrypter").
+			CausedBy(err)
+	}
+
+	obj, err := encrypter.Encrypt(stringx.ToBytes(token))
+	if err != nil {
+		return "", errorchain.NewWithMessage(heimdall.ErrInternal, "failed to encrypt token").
+			CausedBy(err)
+	}
+
+	return obj.CompactSerialize()
+}
+
+func (e *jwtEncrypter) keyAlgorithm(jwk *jose.JSONWebKey) jose.KeyAlgorithm {
+	if len(e.alg) != 0 {
+		return e.alg
+	}
+
+	if slices.Contains(supportedKeyAlgorithms, jose.KeyAlgorithm(jwk.Algorithm)) {
+		return jose.KeyAlgorithm(jwk.Algorithm)
+	}
+
+	if _, ok := jwk.Key.(*rsa.PublicKey); ok {
+		return jose.RSA_OAEP_256
+	}
+
+	return jose.ECDH_ES_A256KW
+}
+
+// EncryptionKey returns the key to encrypt tokens to. If a JWKS endpoint is configured, the key is
+// retrieved from it and cached for the configured ttl.
+func (e *jwtEncrypter) EncryptionKey(ctx context.Context) (*jose.JSONWebKey, error) {
+	if e.ep == nil {
+		e.mut.RLock()
+		defer e.mut.RUnlock()
+
+		return e.jwk, nil
+	}
+
+	logger := zerolog.Ctx(ctx)
+	cch := cache.Ctx(ctx)
+	cacheKey := hex.EncodeToString(e.Hash())
+
+	if entry, err := cch.Get(ctx, cacheKey); err == nil {
+		var jwk jose.JSONWebKey
+
+		if err = json.Unmarshal(entry, &jwk); err == nil {
+			logger.Debug().Msg("Reusing encryption key from cache")
+
+			return &jwk, nil
+		}
+	}
+
+	logger.Debug().Msg("Retrieving JWKS from configured endpoint")
+
+	rawData, err := e.ep.SendRequest(ctx, nil, nil)
+	if err != nil {
+		return nil, errorchain.NewWithMessage(heimdall.ErrCommunication,
+			"failed to retrieve encryption key from the JWKS endpoint").CausedBy(err)
+	}
+
+	keys, err := parseEncryptionKeys(rawData)
+	if err != nil {
+		return nil, errorchain.NewWithMessage(heimdall.ErrInternal, "failed to unmarshal received jwks").
+			CausedBy(err)
+	}
+
+	jwk, err := e.selectKey(keys)
+	if err != nil {
+		return nil, errorchain.NewWithMessage(heimdall.ErrInternal,
+			"failed to retrieve encryption key from the JWKS endpoint").CausedBy(err)
+	}
+
+	if e.ttl > 0 {
+		data, _ := json.Marshal(jwk)
+
+		if err = cch.Set(ctx, cacheKey, data, e.ttl); err != nil {
+			logger.Warn().Err(err).Msg("Failed to cache encryption key")
+		}
+	}
+
+	return jwk, nil
+}
+
+// selectKey returns the public part of the key referenced by the configured key id, or, if no key id
+// is configured, of the first key, which can be used for encryption purposes.
+func (e *jwtEncrypter) selectKey(keys []jose.JSONWebKey) (*jose.JSONWebKey, error) {
+	for _, key := range keys {
+		if len(e.keyID) != 0 && key.KeyID != e.keyID {
+			continue
+		}
+
+		if len(e.keyID) == 0 && len(key.Use) != 0 && key.Use != "enc" {
+			continue
+		}
+
+		pub := key.Public()
+
+		switch pub.Key.(type) {
+		case *rsa.PublicKey, *ecdsa.PublicKey:
+			return &pub, nil
+		}
+
+		if len(e.keyID) != 0 {
+			return nil, errorchain.NewWithMessagef(errNoEncryptionKey,
+				"key with key_id=%s is not an RSA or EC key", e.keyID)
+		}
+	}
+
+	if len(e.keyID) != 0 {
+		return nil, errorchain.NewWithMessagef(errNoEncryptionKey, "no key with key_id=%s present", e.keyID)
+	}
+
+	return nil, errNoEncryptionKey
+}
+
+// parseEncryptionKeys supports JWKS and JWK documents, as well as PEM encoded public keys and certificates.
+func parseEncryptionKeys(data []byte) ([]jose.JSONWebKey, error) {
+	if data = bytes.TrimSpace(data); bytes.HasPrefix(data, []byte("{")) {
+		var jwks jose.JSONWebKeySet
+		if err := json.Unmarshal(data, &jwks); err == nil && len(jwks.Keys) != 0 {
+			return jwks.Keys, nil
+		}
+
+		var jwk jose.JSONWebKey
+		if err := json.Unmarshal(data, &jwk); err != nil {
+			return nil, err
+		}
+
+		return []jose.JSONWebKey{jwk}, nil
+	}
+
+	var keys []jose.JSONWebKey
+
+	for block, rest := pem.Decode(data); block != nil; block, rest = pem.Decode(rest) {
+		var (
+			pubKey any
+			keyID  []byte
+			err    error
+		)
+
+		switch block.Type {
+		case "PUBLIC KEY", "ECDSA PUBLIC KEY":
+			pubKey, err = x509.ParsePKIXPublicKey(block.Bytes)
+		case "RSA PUBLIC KEY":
+			pubKey, err = x509.ParsePKCS1PublicKey(block.Bytes)
+		case "CERTIFICATE":
+			var cert *x509.Certificate
+
+			if cert, err = x509.ParseCertificate(block.Bytes); err == nil {
+				pubKey = cert.PublicKey
+				keyID = cert.SubjectKeyId
+			}
+		default:
+			return nil, errorchain.NewWithMessagef(heimdall.ErrConfiguration,
+				"unsupported entry '%s' in the pem file", block.Type)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		kid := block.Headers["X-Key-ID"]
+		if len(kid) == 0 {
+			if len(keyID) == 0 {
+				if keyID, err = pkix.SubjectKeyID(pubKey); err != nil {
+					return nil, err
+				}
+			}
+
+			kid = hex.EncodeToString(keyID)
+		}
+
+		keys = append(keys, jose.JSONWebKey{Key: pubKey, KeyID: kid, Use: "enc"})
+	}
+
+	return keys, nil
+}
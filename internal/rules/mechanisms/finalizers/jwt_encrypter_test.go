@@ -0,0 +1,367 @@
+// Copyright 2025 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package finalizers
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-jose/go-jose/v4"
+	"github.com/goccy/go-json"
+	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dadrus/heimdall/internal/cache"
+	cachemocks "github.com/dadrus/heimdall/internal/cache/mocks"
+	"github.com/dadrus/heimdall/internal/heimdall"
+	"github.com/dadrus/heimdall/internal/rules/endpoint"
+	"github.com/dadrus/heimdall/internal/watcher/mocks"
+	"github.com/dadrus/heimdall/internal/x/pkix/pemx"
+	"github.com/dadrus/heimdall/internal/x/testsupport"
+)
+
+func TestNewJWTEncrypter(t *testing.T) {
+	t.Parallel()
+
+	rsaPrivKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	ecdsaPrivKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	cert, err := testsupport.NewCertificateBuilder(
+		testsupport.WithValidity(time.Now(), 10*time.Hour),
+		testsupport.WithSubjectPubKey(&ecdsaPrivKey.PublicKey, x509.ECDSAWithSHA256),
+		testsupport.WithSubjectKeyID([]byte{0x01, 0x02}),
+		testsupport.WithSelfSigned(),
+		testsupport.WithSignaturePrivKey(ecdsaPrivKey),
+	).Build()
+	require.NoError(t, err)
+
+	rsaPubKeyBytes, err := x509.MarshalPKIXPublicKey(&rsaPrivKey.PublicKey)
+	require.NoError(t, err)
+
+	testDir := t.TempDir()
+
+	for uc, tc := range map[string]struct {
+		contents []byte
+		conf     EncryptionConfig
+		assert   func(t *testing.T, err error, enc *jwtEncrypter)
+	}{
+		"not existing file": {
+			assert: func(t *testing.T, err error, _ *jwtEncrypter) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				require.ErrorContains(t, err, "failed reading encryption key file")
+			},
+		},
+		"pem with rsa public key": {
+			contents: pem.EncodeToMemory(&pem.Block{
+				Type: "PUBLIC KEY", Headers: map[string]string{"X-Key-ID": "rsa"}, Bytes: rsaPubKeyBytes,
+			}),
+			assert: func(t *testing.T, err error, enc *jwtEncrypter) {
+				t.Helper()
+
+				require.NoError(t, err)
+				assert.Equal(t, "rsa", enc.jwk.KeyID)
+				assert.Equal(t, &rsaPrivKey.PublicKey, enc.jwk.Key)
+				assert.Equal(t, jose.RSA_OAEP_256, enc.keyAlgorithm(enc.jwk))
+				assert.Equal(t, jose.A256GCM, enc.enc)
+			},
+		},
+		"pem with certificate and key referenced by key id": {
+			contents: func() []byte {
+				data, err := pemx.BuildPEM(
+					pemx.WithECDSAPublicKey(&ecdsaPrivKey.PublicKey, pemx.WithHeader("X-Key-ID", "foo")),
+					pemx.WithX509Certificate(cert),
+				)
+				require.NoError(t, err)
+
+				return data
+			}(),
+			conf: EncryptionConfig{KeyID: "0102", KeyAlgorithm: "ECDH-ES"},
+			assert: func(t *testing.T, err error, enc *jwtEncrypter) {
+				t.Helper()
+
+				require.NoError(t, err)
+				assert.Equal(t, "0102", enc.jwk.KeyID)
+				assert.Equal(t, &ecdsaPrivKey.PublicKey, enc.jwk.Key)
+				assert.Equal(t, jose.ECDH_ES, enc.keyAlgorithm(enc.jwk))
+			},
+		},
+		"jwks with signing and encryption keys": {
+			contents: func() []byte {
+				data, err := json.Marshal(jose.JSONWebKeySet{Keys: []jose.JSONWebKey{
+					{Key: &ecdsaPrivKey.PublicKey, KeyID: "sig", Use: "sig", Algorithm: string(jose.ES256)},
+					{Key: rsaPrivKey, KeyID: "enc", Use: "enc", Algorithm: string(jose.RSA_OAEP)},
+				}})
+				require.NoError(t, err)
+
+				return data
+			}(),
+			assert: func(t *testing.T, err error, enc *jwtEncrypter) {
+				t.Helper()
+
+				require.NoError(t, err)
+				assert.Equal(t, "enc", enc.jwk.KeyID)
+				assert.Equal(t, &rsaPrivKey.PublicKey, enc.jwk.Key)
+				assert.Equal(t, jose.RSA_OAEP, enc.keyAlgorithm(enc.jwk))
+			},
+		},
+		"jwks without key with the configured key id": {
+			contents: func() []byte {
+				data, err := json.Marshal(jose.JSONWebKeySet{Keys: []jose.JSONWebKey{
+					{Key: &ecdsaPrivKey.PublicKey, KeyID: "foo"},
+				}})
+				require.NoError(t, err)
+
+				return data
+			}(),
+			conf: EncryptionConfig{KeyID: "bar"},
+			assert: func(t *testing.T, err error, _ *jwtEncrypter) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, errNoEncryptionKey)
+				require.ErrorContains(t, err, "key_id=bar")
+			},
+		},
+		"pem with unsupported entry": {
+			contents: pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: []byte("foo")}),
+			assert: func(t *testing.T, err error, _ *jwtEncrypter) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				require.ErrorContains(t, err, "unsupported entry 'PRIVATE KEY'")
+			},
+		},
+	} {
+		t.Run(uc, func(t *testing.T) {
+			tc.conf.KeyFile = filepath.Join(testDir, "does-not-exist.pem")
+
+			wm := mocks.NewWatcherMock(t)
+
+			if tc.contents != nil {
+				tc.conf.KeyFile = filepath.Join(testDir, uc)
+
+				err := os.WriteFile(tc.conf.KeyFile, tc.contents, 0o600)
+				require.NoError(t, err)
+
+				wm.EXPECT().Add(tc.conf.KeyFile, mock.Anything).Maybe().Return(nil)
+			}
+
+			// WHEN
+			enc, err := newJWTEncrypter(&tc.conf, wm)
+
+			// THEN
+			tc.assert(t, err, enc)
+		})
+	}
+}
+
+func TestJWTEncrypterOnChanged(t *testing.T) {
+	t.Parallel()
+
+	// GIVEN
+	key1, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	key2, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	require.NoError(t, err)
+
+	pemBytes1, err := pemx.BuildPEM(pemx.WithECDSAPublicKey(&key1.PublicKey, pemx.WithHeader("X-Key-ID", "key1")))
+	require.NoError(t, err)
+
+	pemBytes2, err := pemx.BuildPEM(pemx.WithECDSAPublicKey(&key2.PublicKey, pemx.WithHeader("X-Key-ID", "key2")))
+	require.NoError(t, err)
+
+	keyFile := filepath.Join(t.TempDir(), "keys.pem")
+	err = os.WriteFile(keyFile, pemBytes1, 0o600)
+	require.NoError(t, err)
+
+	wm := mocks.NewWatcherMock(t)
+	wm.EXPECT().Add(keyFile, mock.Anything).Return(nil)
+
+	enc, err := newJWTEncrypter(&EncryptionConfig{KeyFile: keyFile}, wm)
+	require.NoError(t, err)
+
+	hash1 := enc.Hash()
+
+	// WHEN
+	err = os.WriteFile(keyFile, []byte("foo"), 0o600)
+	require.NoError(t, err)
+
+	enc.OnChanged(log.Logger)
+
+	// THEN
+	assert.Equal(t, "key1", enc.jwk.KeyID)
+
+	// WHEN
+	err = os.WriteFile(keyFile, pemBytes2, 0o600)
+	require.NoError(t, err)
+
+	enc.OnChanged(log.Logger)
+
+	// THEN
+	assert.Equal(t, "key2", enc.jwk.KeyID)
+	assert.NotEqual(t, hash1, enc.Hash())
+}
+
+func TestJWTEncrypterEncrypt(t *testing.T) {
+	t.Parallel()
+
+	privKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	var (
+		endpointCalled bool
+		responseCode   int
+	)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		endpointCalled = true
+
+		assert.Equal(t, http.MethodGet, r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Accept"))
+
+		if responseCode != http.StatusOK {
+			w.WriteHeader(responseCode)
+
+			return
+		}
+
+		err := json.NewEncoder(w).Encode(jose.JSONWebKeySet{Keys: []jose.JSONWebKey{
+			{Key: &privKey.PublicKey, KeyID: "enc", Use: "enc"},
+		}})
+		assert.NoError(t, err)
+	}))
+	defer srv.Close()
+
+	for uc, tc := range map[string]struct {
+		code           int
+		configureCache func(t *testing.T, cch *cachemocks.CacheMock)
+		assert         func(t *testing.T, err error, token string)
+	}{
+		"with key retrieved from the endpoint": {
+			code: http.StatusOK,
+			configureCache: func(t *testing.T, cch *cachemocks.CacheMock) {
+				t.Helper()
+
+				cch.EXPECT().Get(mock.Anything, mock.Anything).Return(nil, errors.New("no cache entry"))
+				cch.EXPECT().Set(mock.Anything, mock.Anything, mock.Anything, 5*time.Minute).Return(nil)
+			},
+			assert: func(t *testing.T, err error, token string) {
+				t.Helper()
+
+				require.NoError(t, err)
+				assert.True(t, endpointCalled)
+
+				jwe, err := jose.ParseEncrypted(token,
+					[]jose.KeyAlgorithm{jose.ECDH_ES_A256KW}, []jose.ContentEncryption{jose.A256GCM})
+				require.NoError(t, err)
+
+				assert.Equal(t, "enc", jwe.Header.KeyID)
+				assert.Equal(t, "JWT", jwe.Header.ExtraHeaders[jose.HeaderContentType])
+
+				plaintext, err := jwe.Decrypt(privKey)
+				require.NoError(t, err)
+				assert.Equal(t, "foo.bar.baz", string(plaintext))
+			},
+		},
+		"with key from cache": {
+			configureCache: func(t *testing.T, cch *cachemocks.CacheMock) {
+				t.Helper()
+
+				data, err := json.Marshal(jose.JSONWebKey{Key: &privKey.PublicKey, KeyID: "cached"})
+				require.NoError(t, err)
+
+				cch.EXPECT().Get(mock.Anything, mock.Anything).Return(data, nil)
+			},
+			assert: func(t *testing.T, err error, token string) {
+				t.Helper()
+
+				require.NoError(t, err)
+				assert.False(t, endpointCalled)
+
+				jwe, err := jose.ParseEncrypted(token,
+					[]jose.KeyAlgorithm{jose.ECDH_ES_A256KW}, []jose.ContentEncryption{jose.A256GCM})
+				require.NoError(t, err)
+
+				assert.Equal(t, "cached", jwe.Header.KeyID)
+			},
+		},
+		"with endpoint responding with an error": {
+			code: http.StatusBadGateway,
+			configureCache: func(t *testing.T, cch *cachemocks.CacheMock) {
+				t.Helper()
+
+				cch.EXPECT().Get(mock.Anything, mock.Anything).Return(nil, errors.New("no cache entry"))
+			},
+			assert: func(t *testing.T, err error, _ string) {
+				t.Helper()
+
+				require.Error(t, err)
+				assert.True(t, endpointCalled)
+				require.ErrorIs(t, err, heimdall.ErrCommunication)
+				require.ErrorContains(t, err, "failed to retrieve encryption key")
+			},
+		},
+	} {
+		t.Run(uc, func(t *testing.T) {
+			// GIVEN
+			endpointCalled = false
+			responseCode = tc.code
+
+			ttl := 5 * time.Minute
+			cch := cachemocks.NewCacheMock(t)
+			tc.configureCache(t, cch)
+
+			enc, err := newJWTEncrypter(&EncryptionConfig{
+				JWKSEndpoint: &endpoint.Endpoint{URL: srv.URL},
+				CacheTTL:     &ttl,
+			}, mocks.NewWatcherMock(t))
+			require.NoError(t, err)
+
+			// WHEN
+			var token string
+
+			jwk, err := enc.EncryptionKey(cache.WithContext(t.Context(), cch))
+			if err == nil {
+				token, err = enc.Encrypt("foo.bar.baz", jwk)
+			}
+
+			// THEN
+			tc.assert(t, err, token)
+		})
+	}
+}
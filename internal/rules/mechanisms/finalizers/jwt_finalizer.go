@@ -17,13 +17,16 @@
 package finalizers
 
 import (
+	"crypto"
 	"crypto/sha256"
 	"crypto/x509"
 	"encoding/binary"
 	"encoding/hex"
 	"fmt"
+	"strings"
 	"time"
 
+	"github.com/go-jose/go-jose/v4"
 	"github.com/goccy/go-json"
 	"github.com/rs/zerolog"
 
@@ -67,6 +70,7 @@ type jwtFinalizer struct {
 	headerName   string
 	headerScheme string
 	signer       *jwtSigner
+	encrypter    *jwtEncrypter
 	v            values.Values
 }
 
@@ -80,26 +84,41 @@ func newJWTFinalizer(app app.Context, id string, rawConfig map[string]any) (*jwt
 	}
 
 	type Config struct {
-		Signer SignerConfig      `mapstructure:"signer" validate:"required"`
-		TTL    *time.Duration    `mapstructure:"ttl"    validate:"omitempty,gt=1s"`
-		Claims template.Template `mapstructure:"claims"`
-		Values values.Values     `mapstructure:"values"`
-		Header *HeaderConfig     `mapstructure:"header"`
+		Signer     SignerConfig      `mapstructure:"signer"     validate:"required"`
+		Encryption *EncryptionConfig `mapstructure:"encryption"`
+		TTL        *time.Duration    `mapstructure:"ttl"        validate:"omitempty,gt=1s"`
+		Claims     template.Template `mapstructure:"claims"`
+		Values     values.Values     `mapstructure:"values"`
+		Header     *HeaderConfig     `mapstructure:"header"`
 	}
 
 	var conf Config
-	if err := decodeConfig(app.Validator(), rawConfig, &conf); err != nil {
+	if err := decodeConfig(app, rawConfig, &conf); err != nil {
 		return nil, errorchain.NewWithMessagef(heimdall.ErrConfiguration,
 			"failed decoding config for jwt finalizer '%s'", id).CausedBy(err)
 	}
 
-	signer, err := newJWTSigner(&conf.Signer, app.Watcher())
+	signer, err := newJWTSigner(&conf.Signer, app.Watcher(), app.Scheduler(), logger)
 	if err != nil {
 		return nil, err
 	}
 
 	app.KeyHolderRegistry().AddKeyHolder(signer)
 
+	var encrypter *jwtEncrypter
+
+	if conf.Encryption != nil {
+		if conf.Encryption.JWKSEndpoint != nil && strings.HasPrefix(conf.Encryption.JWKSEndpoint.URL, "http://") {
+			logger.Warn().Str("_id", id).
+				Msg("No TLS configured for the jwks endpoint used in jwt finalizer")
+		}
+
+		encrypter, err = newJWTEncrypter(conf.Encryption, app.Watcher())
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	fin := &jwtFinalizer{
 		id:     id,
 		app:    app,
@@ -113,8 +132,9 @@ func newJWTFinalizer(app app.Context, id string, rawConfig map[string]any) (*jwt
 		headerScheme: x.IfThenElseExec(conf.Header != nil,
 			func() string { return conf.Header.Scheme },
 			func() string { return "Bearer" }),
-		signer: signer,
-		v:      conf.Values,
+		signer:    signer,
+		encrypter: encrypter,
+		v:         conf.Values,
 	}
 
 	app.CertificateObserver().Add(fin)
@@ -136,10 +156,20 @@ func (f *jwtFinalizer) Execute(ctx heimdall.RequestContext, sub *subject.Subject
 
 	var (
 		jwtToken string
+		encKey   *jose.JSONWebKey
 		err      error
 	)
 
-	cacheKey := f.calculateCacheKey(ctx, sub)
+	if f.encrypter != nil {
+		if encKey, err = f.encrypter.EncryptionKey(ctx.Context()); err != nil {
+			return errorchain.
+				NewWithMessage(heimdall.ErrInternal, "failed to resolve encryption key").
+				WithErrorContext(f).
+				CausedBy(err)
+		}
+	}
+
+	cacheKey := f.calculateCacheKey(ctx, sub, encKey)
 	if entry, err := cch.Get(ctx.Context(), cacheKey); err == nil {
 		logger.Debug().Msg("Reusing JWT from cache")
 
@@ -147,7 +177,7 @@ func (f *jwtFinalizer) Execute(ctx heimdall.RequestContext, sub *subject.Subject
 	}
 
 	if len(jwtToken) == 0 {
-		jwtToken, err = f.generateToken(ctx, sub)
+		jwtToken, err = f.generateToken(ctx, sub, encKey)
 		if err != nil {
 			return err
 		}
@@ -176,7 +206,7 @@ func (f *jwtFinalizer) WithConfig(rawConfig map[string]any) (Finalizer, error) {
 	}
 
 	var conf Config
-	if err := decodeConfig(f.app.Validator(), rawConfig, &conf); err != nil {
+	if err := decodeConfig(f.app, rawConfig, &conf); err != nil {
 		return nil, errorchain.NewWithMessagef(heimdall.ErrConfiguration,
 			"failed decoding config for jwt finalizer '%s'", f.id).CausedBy(err)
 	}
@@ -191,6 +221,7 @@ func (f *jwtFinalizer) WithConfig(rawConfig map[string]any) (Finalizer, error) {
 		headerName:   f.headerName,
 		headerScheme: f.headerScheme,
 		signer:       f.signer,
+		encrypter:    f.encrypter,
 		v:            f.v.Merge(conf.Values),
 	}, nil
 }
@@ -199,7 +230,9 @@ func (f *jwtFinalizer) ID() string { return f.id }
 
 func (f *jwtFinalizer) ContinueOnError() bool { return false }
 
-func (f *jwtFinalizer) generateToken(ctx heimdall.RequestContext, sub *subject.Subject) (string, error) {
+func (f *jwtFinalizer) generateToken(
+	ctx heimdall.RequestContext, sub *subject.Subject, encKey *jose.JSONWebKey,
+) (string, error) {
 	logger := zerolog.Ctx(ctx.Context())
 	logger.Debug().Msg("Generating new JWT")
 
@@ -247,10 +280,23 @@ func (f *jwtFinalizer) generateToken(ctx heimdall.RequestContext, sub *subject.S
 			CausedBy(err)
 	}
 
+	if f.encrypter != nil {
+		logger.Debug().Msg("Encrypting JWT")
+
+		if token, err = f.encrypter.Encrypt(token, encKey); err != nil {
+			return "", errorchain.
+				NewWithMessage(heimdall.ErrInternal, "failed to encrypt token").
+				WithErrorContext(f).
+				CausedBy(err)
+		}
+	}
+
 	return token, nil
 }
 
-func (f *jwtFinalizer) calculateCacheKey(ctx heimdall.RequestContext, sub *subject.Subject) string {
+func (f *jwtFinalizer) calculateCacheKey(
+	ctx heimdall.RequestContext, sub *subject.Subject, encKey *jose.JSONWebKey,
+) string {
 	const int64BytesCount = 8
 
 	ttlBytes := make([]byte, int64BytesCount)
@@ -261,6 +307,21 @@ func (f *jwtFinalizer) calculateCacheKey(ctx heimdall.RequestContext, sub *subje
 
 	hash := sha256.New()
 	hash.Write(f.signer.Hash())
+
+	if f.encrypter != nil {
+		hash.Write(f.encrypter.Hash())
+	}
+
+	if encKey != nil {
+		// the key resolved from a JWKS endpoint may change, e.g. due to key rotation, without the
+		// configuration of the encrypter being changed. Tokens encrypted to a previous key must
+		// not be reused in such cases.
+		thumbprint, _ := encKey.Thumbprint(crypto.SHA256)
+
+		hash.Write(stringx.ToBytes(encKey.KeyID))
+		hash.Write(thumbprint)
+	}
+
 	hash.Write(x.IfThenElseExec(f.claims != nil,
 		func() []byte { return f.claims.Hash() },
 		func() []byte { return []byte{} }))
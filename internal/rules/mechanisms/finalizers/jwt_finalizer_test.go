@@ -20,13 +20,17 @@ import (
 	"crypto/ecdsa"
 	"crypto/elliptic"
 	"crypto/rand"
+	"encoding/hex"
 	"errors"
+	"net/http"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/go-jose/go-jose/v4"
+	"github.com/goccy/go-json"
 	"github.com/rs/zerolog/log"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -35,11 +39,13 @@ import (
 	"github.com/dadrus/heimdall/internal/app"
 	"github.com/dadrus/heimdall/internal/cache"
 	"github.com/dadrus/heimdall/internal/cache/mocks"
+	"github.com/dadrus/heimdall/internal/config"
 	"github.com/dadrus/heimdall/internal/heimdall"
 	heimdallmocks "github.com/dadrus/heimdall/internal/heimdall/mocks"
 	mocks3 "github.com/dadrus/heimdall/internal/keyholder/mocks"
 	mocks4 "github.com/dadrus/heimdall/internal/otel/metrics/certificate/mocks"
 	"github.com/dadrus/heimdall/internal/rules/mechanisms/subject"
+	"github.com/dadrus/heimdall/internal/scheduler"
 	"github.com/dadrus/heimdall/internal/validation"
 	mocks2 "github.com/dadrus/heimdall/internal/watcher/mocks"
 	"github.com/dadrus/heimdall/internal/x"
@@ -101,6 +107,8 @@ signer:
 				t.Helper()
 
 				ctx.EXPECT().Watcher().Return(mocks2.NewWatcherMock(t))
+
+				ctx.EXPECT().Scheduler().Maybe().Return(&scheduler.NoopScheduler{})
 			},
 			assert: func(t *testing.T, err error, _ *jwtFinalizer) {
 				t.Helper()
@@ -109,6 +117,115 @@ signer:
 				require.ErrorContains(t, err, "failed loading keystore")
 			},
 		},
+		"with encryption using key file and jwks endpoint": {
+			config: []byte(`
+signer:
+  key_store:
+    path: ` + pemFile + `
+encryption:
+  key_file: /foo/bar.pem
+  jwks_endpoint:
+    url: https://foo.bar/jwks
+`),
+			configureAppContext: func(t *testing.T, _ *app.ContextMock) { t.Helper() },
+			assert: func(t *testing.T, err error, _ *jwtFinalizer) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorContains(t, err, "'key_file' is an excluded field")
+			},
+		},
+		"with encryption using unsupported key algorithm": {
+			config: []byte(`
+signer:
+  key_store:
+    path: ` + pemFile + `
+encryption:
+  key_file: /foo/bar.pem
+  key_algorithm: RSA1_5
+`),
+			configureAppContext: func(t *testing.T, _ *app.ContextMock) { t.Helper() },
+			assert: func(t *testing.T, err error, _ *jwtFinalizer) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorContains(t, err, "'key_algorithm' must be one of")
+			},
+		},
+		"with encryption using not existing key file": {
+			config: []byte(`
+signer:
+  key_store:
+    path: ` + pemFile + `
+encryption:
+  key_file: /does/not/exist.pem
+`),
+			configureAppContext: func(t *testing.T, ctx *app.ContextMock) {
+				t.Helper()
+
+				wm := mocks2.NewWatcherMock(t)
+				wm.EXPECT().Add(pemFile, mock.Anything).Return(nil)
+
+				khr := mocks3.NewRegistryMock(t)
+				khr.EXPECT().AddKeyHolder(mock.Anything)
+
+				ctx.EXPECT().Watcher().Return(wm)
+
+				ctx.EXPECT().Scheduler().Maybe().Return(&scheduler.NoopScheduler{})
+				ctx.EXPECT().KeyHolderRegistry().Return(khr)
+			},
+			assert: func(t *testing.T, err error, _ *jwtFinalizer) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorContains(t, err, "failed reading encryption key file")
+			},
+		},
+		"with encryption using jwks endpoint": {
+			config: []byte(`
+signer:
+  key_store:
+    path: ` + pemFile + `
+encryption:
+  jwks_endpoint:
+    url: http://foo.bar/jwks
+  key_id: enc-key
+  key_algorithm: ECDH-ES
+  content_encryption: A128GCM
+  cache_ttl: 1m
+`),
+			configureAppContext: func(t *testing.T, ctx *app.ContextMock) {
+				t.Helper()
+
+				wm := mocks2.NewWatcherMock(t)
+				wm.EXPECT().Add(pemFile, mock.Anything).Return(nil)
+
+				khr := mocks3.NewRegistryMock(t)
+				khr.EXPECT().AddKeyHolder(mock.Anything)
+
+				co := mocks4.NewObserverMock(t)
+				co.EXPECT().Add(mock.Anything)
+
+				ctx.EXPECT().Watcher().Return(wm)
+
+				ctx.EXPECT().Scheduler().Maybe().Return(&scheduler.NoopScheduler{})
+				ctx.EXPECT().KeyHolderRegistry().Return(khr)
+				ctx.EXPECT().CertificateObserver().Return(co)
+			},
+			assert: func(t *testing.T, err error, finalizer *jwtFinalizer) {
+				t.Helper()
+
+				require.NoError(t, err)
+				require.NotNil(t, finalizer.encrypter)
+				assert.Equal(t, "enc-key", finalizer.encrypter.keyID)
+				assert.Equal(t, jose.ECDH_ES, finalizer.encrypter.alg)
+				assert.Equal(t, jose.A128GCM, finalizer.encrypter.enc)
+				assert.Equal(t, time.Minute, finalizer.encrypter.ttl)
+				require.NotNil(t, finalizer.encrypter.ep)
+				assert.Equal(t, http.MethodGet, finalizer.encrypter.ep.Method)
+				assert.Equal(t, "application/json", finalizer.encrypter.ep.Headers["Accept"])
+			},
+		},
 		"with signer only": {
 			config: []byte(`
 signer:
@@ -129,6 +246,8 @@ signer:
 				co.EXPECT().Add(mock.Anything)
 
 				ctx.EXPECT().Watcher().Return(wm)
+
+				ctx.EXPECT().Scheduler().Maybe().Return(&scheduler.NoopScheduler{})
 				ctx.EXPECT().KeyHolderRegistry().Return(khr)
 				ctx.EXPECT().CertificateObserver().Return(co)
 			},
@@ -173,6 +292,8 @@ signer:
 				co.EXPECT().Add(mock.Anything)
 
 				ctx.EXPECT().Watcher().Return(wm)
+
+				ctx.EXPECT().Scheduler().Maybe().Return(&scheduler.NoopScheduler{})
 				ctx.EXPECT().KeyHolderRegistry().Return(khr)
 				ctx.EXPECT().CertificateObserver().Return(co)
 			},
@@ -233,6 +354,8 @@ claims:
 				co.EXPECT().Add(mock.Anything)
 
 				ctx.EXPECT().Watcher().Return(wm)
+
+				ctx.EXPECT().Scheduler().Maybe().Return(&scheduler.NoopScheduler{})
 				ctx.EXPECT().KeyHolderRegistry().Return(khr)
 				ctx.EXPECT().CertificateObserver().Return(co)
 			},
@@ -282,6 +405,8 @@ claims: '{ "sub": {{ quote .Subject.ID }} }'
 				co.EXPECT().Add(mock.Anything)
 
 				ctx.EXPECT().Watcher().Return(wm)
+
+				ctx.EXPECT().Scheduler().Maybe().Return(&scheduler.NoopScheduler{})
 				ctx.EXPECT().KeyHolderRegistry().Return(khr)
 				ctx.EXPECT().CertificateObserver().Return(co)
 			},
@@ -362,6 +487,8 @@ header:
 				co.EXPECT().Add(mock.Anything)
 
 				ctx.EXPECT().Watcher().Return(wm)
+
+				ctx.EXPECT().Scheduler().Maybe().Return(&scheduler.NoopScheduler{})
 				ctx.EXPECT().KeyHolderRegistry().Return(khr)
 				ctx.EXPECT().CertificateObserver().Return(co)
 			},
@@ -409,6 +536,8 @@ values:
 				co.EXPECT().Add(mock.Anything)
 
 				ctx.EXPECT().Watcher().Return(wm)
+
+				ctx.EXPECT().Scheduler().Maybe().Return(&scheduler.NoopScheduler{})
 				ctx.EXPECT().KeyHolderRegistry().Return(khr)
 				ctx.EXPECT().CertificateObserver().Return(co)
 			},
@@ -436,7 +565,9 @@ values:
 			conf, err := testsupport.DecodeTestConfig(tc.config)
 			require.NoError(t, err)
 
-			validator, err := validation.NewValidator()
+			validator, err := validation.NewValidator(
+				validation.WithTagValidator(config.EnforcementSettings{}),
+			)
 			require.NoError(t, err)
 
 			appCtx := app.NewContextMock(t)
@@ -712,6 +843,7 @@ foo: bar
 
 			appCtx := app.NewContextMock(t)
 			appCtx.EXPECT().Watcher().Return(wm)
+			appCtx.EXPECT().Scheduler().Maybe().Return(&scheduler.NoopScheduler{})
 			appCtx.EXPECT().KeyHolderRegistry().Return(khr)
 			appCtx.EXPECT().CertificateObserver().Return(co)
 			appCtx.EXPECT().Validator().Return(validator)
@@ -801,7 +933,7 @@ signer:
 				ctx.EXPECT().AddHeaderForUpstream("Authorization", "Bearer TestToken")
 				ctx.EXPECT().Outputs().Return(map[string]any{"foo": "bar"})
 
-				cacheKey := fin.calculateCacheKey(ctx, sub)
+				cacheKey := fin.calculateCacheKey(ctx, sub, nil)
 				cch.EXPECT().Get(mock.Anything, cacheKey).Return([]byte("TestToken"), nil)
 			},
 			assert: func(t *testing.T, err error) {
@@ -810,6 +942,114 @@ signer:
 				require.NoError(t, err)
 			},
 		},
+		"with no cache hit and with encryption": {
+			config: []byte(`
+signer:
+  key_store:
+    path: ` + pemFile + `
+ttl: 1m
+encryption:
+  jwks_endpoint:
+    url: https://foo.bar/jwks
+`),
+			subject: &subject.Subject{ID: "foo", Attributes: map[string]any{"baz": "bar"}},
+			configureMocks: func(t *testing.T, fin *jwtFinalizer, ctx *heimdallmocks.RequestContextMock,
+				cch *mocks.CacheMock, _ *subject.Subject,
+			) {
+				t.Helper()
+
+				encKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+				require.NoError(t, err)
+
+				jwk, err := json.Marshal(jose.JSONWebKey{Key: &encKey.PublicKey, KeyID: "enc"})
+				require.NoError(t, err)
+
+				ctx.EXPECT().AddHeaderForUpstream("Authorization",
+					mock.MatchedBy(func(val string) bool {
+						token, found := strings.CutPrefix(val, "Bearer ")
+						if !found {
+							return false
+						}
+
+						jwe, err := jose.ParseEncrypted(token,
+							[]jose.KeyAlgorithm{jose.ECDH_ES_A256KW}, []jose.ContentEncryption{jose.A256GCM})
+						if err != nil {
+							return false
+						}
+
+						signed, err := jwe.Decrypt(encKey)
+
+						return err == nil && strings.Count(string(signed), ".") == 2
+					}))
+				ctx.EXPECT().Outputs().Return(map[string]any{})
+
+				cch.EXPECT().Get(mock.Anything, hex.EncodeToString(fin.encrypter.Hash())).Return(jwk, nil).Once()
+				cch.EXPECT().Get(mock.Anything, mock.Anything).Return(nil, errors.New("no cache entry")).Once()
+				cch.EXPECT().Set(mock.Anything, mock.Anything, mock.Anything, configuredTTL-defaultCacheLeeway).Return(nil)
+			},
+			assert: func(t *testing.T, err error) {
+				t.Helper()
+
+				require.NoError(t, err)
+			},
+		},
+		"with cached token encrypted to a key no longer served by the jwks endpoint": {
+			config: []byte(`
+signer:
+  key_store:
+    path: ` + pemFile + `
+ttl: 1m
+encryption:
+  jwks_endpoint:
+    url: https://foo.bar/jwks
+`),
+			subject: &subject.Subject{ID: "foo", Attributes: map[string]any{"baz": "bar"}},
+			configureMocks: func(t *testing.T, fin *jwtFinalizer, ctx *heimdallmocks.RequestContextMock,
+				cch *mocks.CacheMock, sub *subject.Subject,
+			) {
+				t.Helper()
+
+				oldKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+				require.NoError(t, err)
+
+				newKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+				require.NoError(t, err)
+
+				jwk, err := json.Marshal(jose.JSONWebKey{Key: &newKey.PublicKey, KeyID: "enc"})
+				require.NoError(t, err)
+
+				ctx.EXPECT().AddHeaderForUpstream("Authorization",
+					mock.MatchedBy(func(val string) bool {
+						token, found := strings.CutPrefix(val, "Bearer ")
+						if !found {
+							return false
+						}
+
+						jwe, err := jose.ParseEncrypted(token,
+							[]jose.KeyAlgorithm{jose.ECDH_ES_A256KW}, []jose.ContentEncryption{jose.A256GCM})
+						if err != nil {
+							return false
+						}
+
+						_, err = jwe.Decrypt(newKey)
+
+						return err == nil
+					}))
+				ctx.EXPECT().Outputs().Return(map[string]any{})
+
+				staleCacheKey := fin.calculateCacheKey(ctx, sub, &jose.JSONWebKey{Key: &oldKey.PublicKey, KeyID: "enc"})
+
+				cch.EXPECT().Get(mock.Anything, hex.EncodeToString(fin.encrypter.Hash())).Return(jwk, nil).Once()
+				cch.EXPECT().Get(mock.Anything, staleCacheKey).Maybe().Return([]byte("TestToken"), nil)
+				cch.EXPECT().Get(mock.Anything, mock.Anything).Return(nil, errors.New("no cache entry")).Once()
+				cch.EXPECT().Set(mock.Anything, mock.Anything, mock.Anything, configuredTTL-defaultCacheLeeway).Return(nil)
+			},
+			assert: func(t *testing.T, err error) {
+				t.Helper()
+
+				require.NoError(t, err)
+			},
+		},
 		"with no cache hit and without custom claims": {
 			config: []byte(`
 signer:
@@ -1011,11 +1251,14 @@ values:
 			co := mocks4.NewObserverMock(t)
 			co.EXPECT().Add(mock.Anything)
 
-			validator, err := validation.NewValidator()
+			validator, err := validation.NewValidator(
+				validation.WithTagValidator(config.EnforcementSettings{}),
+			)
 			require.NoError(t, err)
 
 			appCtx := app.NewContextMock(t)
 			appCtx.EXPECT().Watcher().Return(wm)
+			appCtx.EXPECT().Scheduler().Maybe().Return(&scheduler.NoopScheduler{})
 			appCtx.EXPECT().KeyHolderRegistry().Return(khr)
 			appCtx.EXPECT().CertificateObserver().Return(co)
 			appCtx.EXPECT().Validator().Return(validator)
@@ -17,9 +17,16 @@
 package finalizers
 
 import (
+	"context"
 	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
 	"crypto/sha256"
 	"crypto/x509"
+	"slices"
 	"sync"
 	"time"
 
@@ -31,6 +38,7 @@ import (
 
 	"github.com/dadrus/heimdall/internal/heimdall"
 	"github.com/dadrus/heimdall/internal/keystore"
+	"github.com/dadrus/heimdall/internal/scheduler"
 	"github.com/dadrus/heimdall/internal/watcher"
 	"github.com/dadrus/heimdall/internal/x"
 	"github.com/dadrus/heimdall/internal/x/errorchain"
@@ -38,15 +46,48 @@ import (
 	"github.com/dadrus/heimdall/internal/x/stringx"
 )
 
+const (
+	keyTypeRSA     = "rsa"
+	keyTypeECDSA   = "ecdsa"
+	keyTypeEd25519 = "ed25519"
+
+	generatedRSAKeySize = 3072
+)
+
 type KeyStore struct {
 	Path     string `mapstructure:"path"     validate:"required"`
 	Password string `mapstructure:"password"`
 }
 
+type KeyGeneration struct {
+	Interval time.Duration `mapstructure:"interval" validate:"required,gt=0"`
+	KeyType  string        `mapstructure:"key_type" validate:"omitempty,oneof=rsa ecdsa ed25519"`
+}
+
+type KeyRotation struct {
+	ActivationDelay time.Duration  `mapstructure:"activation_delay" validate:"gte=0"`
+	Generate        *KeyGeneration `mapstructure:"generate"`
+}
+
 type SignerConfig struct {
-	Name     string   `mapstructure:"name"`
-	KeyStore KeyStore `mapstructure:"key_store" validate:"required"`
-	KeyID    string   `mapstructure:"key_id"`
+	Name     string       `mapstructure:"name"`
+	KeyStore *KeyStore    `mapstructure:"key_store"`
+	KeyID    string       `mapstructure:"key_id"`
+	Rotation *KeyRotation `mapstructure:"rotation"`
+}
+
+// retiredKey is a key no longer used for signing, which is still published
+// until the last token signed with it expires.
+type retiredKey struct {
+	jwk     jose.JSONWebKey
+	expires time.Time
+}
+
+// nextKey is a key already published, but used for signing only after activation.
+type nextKey struct {
+	jwk         jose.JSONWebKey
+	key         crypto.Signer
+	activatesAt time.Time
 }
 
 type jwtSigner struct {
@@ -54,21 +95,70 @@ type jwtSigner struct {
 	password string
 	keyID    string
 	iss      string
+	delay    time.Duration
+	keyType  string
 
 	mut     sync.RWMutex
 	jwk     jose.JSONWebKey
 	key     crypto.Signer
 	pubKeys []jose.JSONWebKey
+	next    *nextKey
+	retired []retiredKey
+	lastExp time.Time
 }
 
-func newJWTSigner(conf *SignerConfig, fw watcher.Watcher) (*jwtSigner, error) {
+func newJWTSigner(
+	conf *SignerConfig,
+	fw watcher.Watcher,
+	sch scheduler.Scheduler,
+	logger zerolog.Logger,
+) (*jwtSigner, error) {
+	var generation *KeyGeneration
+
+	if conf.Rotation != nil {
+		generation = conf.Rotation.Generate
+	}
+
+	switch {
+	case conf.KeyStore == nil && generation == nil:
+		return nil, errorchain.NewWithMessage(heimdall.ErrConfiguration,
+			"either 'key_store' or 'rotation.generate' must be configured")
+	case conf.KeyStore != nil && generation != nil:
+		return nil, errorchain.NewWithMessage(heimdall.ErrConfiguration,
+			"'key_store' and 'rotation.generate' are mutually exclusive")
+	case generation != nil && generation.Interval <= conf.Rotation.ActivationDelay:
+		return nil, errorchain.NewWithMessage(heimdall.ErrConfiguration,
+			"'rotation.generate.interval' must be greater than 'rotation.activation_delay'")
+	}
+
 	signer := &jwtSigner{
-		path:     conf.KeyStore.Path,
-		password: conf.KeyStore.Password,
-		keyID:    conf.KeyID,
-		iss:      x.IfThenElse(len(conf.Name) == 0, "heimdall", conf.Name),
+		keyID: conf.KeyID,
+		iss:   x.IfThenElse(len(conf.Name) == 0, "heimdall", conf.Name),
 	}
 
+	if conf.Rotation != nil {
+		signer.delay = conf.Rotation.ActivationDelay
+	}
+
+	if generation != nil {
+		signer.keyType = x.IfThenElse(len(generation.KeyType) == 0, keyTypeECDSA, generation.KeyType)
+
+		if err := signer.generate(); err != nil {
+			return nil, err
+		}
+
+		if err := sch.Schedule(logger.WithContext(context.Background()), generation.Interval,
+			signer.onSchedule); err != nil {
+			return nil, errorchain.NewWithMessage(heimdall.ErrInternal,
+				"failed scheduling signing key generation").CausedBy(err)
+		}
+
+		return signer, nil
+	}
+
+	signer.path = conf.KeyStore.Path
+	signer.password = conf.KeyStore.Password
+
 	if err := signer.load(); err != nil {
 		return nil, err
 	}
@@ -139,13 +229,112 @@ func (s *jwtSigner) load() error {
 	s.mut.Lock()
 	defer s.mut.Unlock()
 
-	s.jwk = kse.JWK()
-	s.key = kse.PrivateKey
+	s.rotate(kse.JWK(), kse.PrivateKey)
 	s.pubKeys = keys
 
 	return nil
 }
 
+func (s *jwtSigner) onSchedule(ctx context.Context) {
+	logger := zerolog.Ctx(ctx)
+
+	if err := s.generate(); err != nil {
+		logger.Warn().Err(err).Msg("Signing key generation failed")
+	} else {
+		logger.Info().Msg("New signing key generated")
+	}
+}
+
+func (s *jwtSigner) generate() error {
+	var (
+		key crypto.Signer
+		err error
+	)
+
+	switch s.keyType {
+	case keyTypeRSA:
+		key, err = rsa.GenerateKey(rand.Reader, generatedRSAKeySize)
+	case keyTypeEd25519:
+		_, key, err = ed25519.GenerateKey(rand.Reader)
+	default:
+		key, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	}
+
+	if err != nil {
+		return errorchain.NewWithMessage(heimdall.ErrInternal, "failed generating signing key").CausedBy(err)
+	}
+
+	ks, err := keystore.NewKeyStoreFromKey(key)
+	if err != nil {
+		return errorchain.NewWithMessage(heimdall.ErrInternal, "failed creating key store").CausedBy(err)
+	}
+
+	entry := ks.Entries()[0]
+
+	s.mut.Lock()
+	defer s.mut.Unlock()
+
+	s.rotate(entry.JWK(), entry.PrivateKey)
+
+	return nil
+}
+
+// rotate makes the given key the signing key. If an activation delay is configured, the key is
+// published as the next key first and used for signing only after the delay elapsed. The key
+// previously used for signing is kept published until all tokens signed with it expired.
+// Must be called with the write lock held.
+func (s *jwtSigner) rotate(jwk jose.JSONWebKey, key crypto.Signer) {
+	switch {
+	case s.key == nil, jwk.KeyID == s.jwk.KeyID:
+		// initial key, or the same key id reused, which can't be published twice
+		s.jwk, s.key, s.next = jwk, key, nil
+	case s.delay == 0:
+		s.next = &nextKey{jwk: jwk, key: key}
+		s.activate(time.Now())
+	default:
+		s.next = &nextKey{jwk: jwk, key: key, activatesAt: time.Now().Add(s.delay)}
+	}
+}
+
+// activate switches signing to the next key if its activation time has been reached.
+// Must be called with the write lock held.
+func (s *jwtSigner) activate(now time.Time) {
+	if s.next == nil || now.Before(s.next.activatesAt) {
+		return
+	}
+
+	retired := s.retired[:0]
+
+	for _, rk := range s.retired {
+		if rk.expires.After(now) {
+			retired = append(retired, rk)
+		}
+	}
+
+	if s.lastExp.After(now) {
+		retired = append(retired, retiredKey{jwk: s.jwk, expires: s.lastExp})
+	}
+
+	s.jwk, s.key, s.retired = s.next.jwk, s.next.key, retired
+	s.next = nil
+	s.lastExp = time.Time{}
+}
+
+// signingKey returns the key to be used for signing a token expiring at exp and
+// records the expiry so that the key stays published at least until then.
+func (s *jwtSigner) signingKey(exp time.Time) (jose.JSONWebKey, crypto.Signer) {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+
+	s.activate(time.Now())
+
+	if exp.After(s.lastExp) {
+		s.lastExp = exp
+	}
+
+	return s.jwk, s.key
+}
+
 func (s *jwtSigner) Hash() []byte {
 	s.mut.RLock()
 	jwk := s.jwk
@@ -160,10 +349,9 @@ func (s *jwtSigner) Hash() []byte {
 }
 
 func (s *jwtSigner) Sign(sub string, ttl time.Duration, customClaims map[string]any) (string, error) {
-	s.mut.RLock()
-	jwk := s.jwk
-	key := s.key
-	s.mut.RUnlock()
+	now := time.Now().UTC()
+	exp := now.Add(ttl)
+	jwk, key := s.signingKey(exp)
 
 	signer, err := jose.NewSigner(
 		jose.SigningKey{Algorithm: jose.SignatureAlgorithm(jwk.Algorithm), Key: key},
@@ -178,8 +366,6 @@ func (s *jwtSigner) Sign(sub string, ttl time.Duration, customClaims map[string]
 	claims := make(map[string]any)
 	maps.Merge(customClaims, claims)
 
-	now := time.Now().UTC()
-	exp := now.Add(ttl)
 	claims["exp"] = exp.Unix()
 	claims["jti"] = uuid.New()
 	claims["iat"] = now.Unix()
@@ -201,7 +387,27 @@ func (s *jwtSigner) Keys() []jose.JSONWebKey {
 	s.mut.RLock()
 	defer s.mut.RUnlock()
 
-	return s.pubKeys
+	now := time.Now()
+	keys := slices.Clone(s.pubKeys)
+	known := func(kid string) bool {
+		return slices.ContainsFunc(keys, func(key jose.JSONWebKey) bool { return key.KeyID == kid })
+	}
+
+	if !known(s.jwk.KeyID) {
+		keys = append(keys, s.jwk)
+	}
+
+	if s.next != nil && !known(s.next.jwk.KeyID) {
+		keys = append(keys, s.next.jwk)
+	}
+
+	for _, rk := range s.retired {
+		if rk.expires.After(now) && !known(rk.jwk.KeyID) {
+			keys = append(keys, rk.jwk)
+		}
+	}
+
+	return keys
 }
 
 func (s *jwtSigner) activeCertificateChain() []*x509.Certificate {
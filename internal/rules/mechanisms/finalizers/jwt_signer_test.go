@@ -17,7 +17,9 @@
 package finalizers
 
 import (
+	"context"
 	"crypto/ecdsa"
+	"crypto/ed25519"
 	"crypto/elliptic"
 	"crypto/rand"
 	"crypto/rsa"
@@ -40,6 +42,8 @@ import (
 
 	"github.com/dadrus/heimdall/internal/heimdall"
 	"github.com/dadrus/heimdall/internal/keystore"
+	"github.com/dadrus/heimdall/internal/scheduler"
+	schedulermocks "github.com/dadrus/heimdall/internal/scheduler/mocks"
 	"github.com/dadrus/heimdall/internal/watcher/mocks"
 	"github.com/dadrus/heimdall/internal/x/pkix/pemx"
 	"github.com/dadrus/heimdall/internal/x/testsupport"
@@ -174,7 +178,83 @@ func TestNewJWTSigner(t *testing.T) {
 				t.Helper()
 
 				require.Error(t, err)
-				require.ErrorContains(t, err, "failed loading keystore")
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				require.ErrorContains(t, err, "either 'key_store' or 'rotation.generate' must be configured")
+			},
+		},
+		{
+			uc: "with key store and key generation configured",
+			config: func(t *testing.T, _ *mocks.WatcherMock) *SignerConfig {
+				t.Helper()
+
+				return &SignerConfig{
+					KeyStore: &KeyStore{Path: keyFile.Name()},
+					Rotation: &KeyRotation{Generate: &KeyGeneration{Interval: time.Hour}},
+				}
+			},
+			assert: func(t *testing.T, err error, _ *jwtSigner) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				require.ErrorContains(t, err, "mutually exclusive")
+			},
+		},
+		{
+			uc: "with key generation interval not exceeding the activation delay",
+			config: func(t *testing.T, _ *mocks.WatcherMock) *SignerConfig {
+				t.Helper()
+
+				return &SignerConfig{
+					Rotation: &KeyRotation{
+						ActivationDelay: time.Hour,
+						Generate:        &KeyGeneration{Interval: time.Hour},
+					},
+				}
+			},
+			assert: func(t *testing.T, err error, _ *jwtSigner) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				require.ErrorContains(t, err, "must be greater than")
+			},
+		},
+		{
+			uc: "with key generation using default key type",
+			config: func(t *testing.T, _ *mocks.WatcherMock) *SignerConfig {
+				t.Helper()
+
+				return &SignerConfig{Rotation: &KeyRotation{Generate: &KeyGeneration{Interval: time.Hour}}}
+			},
+			assert: func(t *testing.T, err error, signer *jwtSigner) {
+				t.Helper()
+
+				require.NoError(t, err)
+
+				assert.Equal(t, "heimdall", signer.iss)
+				assert.IsType(t, &ecdsa.PrivateKey{}, signer.key)
+				assert.NotEmpty(t, signer.jwk.KeyID)
+				assert.Equal(t, string(jose.ES256), signer.jwk.Algorithm)
+				assert.Len(t, signer.Keys(), 1)
+			},
+		},
+		{
+			uc: "with key generation using ed25519 keys",
+			config: func(t *testing.T, _ *mocks.WatcherMock) *SignerConfig {
+				t.Helper()
+
+				return &SignerConfig{
+					Rotation: &KeyRotation{Generate: &KeyGeneration{Interval: time.Hour, KeyType: "ed25519"}},
+				}
+			},
+			assert: func(t *testing.T, err error, signer *jwtSigner) {
+				t.Helper()
+
+				require.NoError(t, err)
+
+				assert.IsType(t, ed25519.PrivateKey{}, signer.key)
+				assert.Equal(t, string(jose.EdDSA), signer.jwk.Algorithm)
 			},
 		},
 		{
@@ -184,7 +264,7 @@ func TestNewJWTSigner(t *testing.T) {
 
 				wm.EXPECT().Add(mock.Anything, mock.Anything).Return(nil)
 
-				return &SignerConfig{Name: "foo", KeyStore: KeyStore{Path: keyFile.Name()}}
+				return &SignerConfig{Name: "foo", KeyStore: &KeyStore{Path: keyFile.Name()}}
 			},
 			assert: func(t *testing.T, err error, signer *jwtSigner) {
 				t.Helper()
@@ -205,7 +285,7 @@ func TestNewJWTSigner(t *testing.T) {
 
 				wm.EXPECT().Add(mock.Anything, mock.Anything).Return(nil)
 
-				return &SignerConfig{Name: "foo", KeyStore: KeyStore{Path: keyFile.Name()}, KeyID: "key2"}
+				return &SignerConfig{Name: "foo", KeyStore: &KeyStore{Path: keyFile.Name()}, KeyID: "key2"}
 			},
 			assert: func(t *testing.T, err error, signer *jwtSigner) {
 				t.Helper()
@@ -224,7 +304,7 @@ func TestNewJWTSigner(t *testing.T) {
 			config: func(t *testing.T, _ *mocks.WatcherMock) *SignerConfig {
 				t.Helper()
 
-				return &SignerConfig{Name: "foo", KeyStore: KeyStore{Path: keyFile.Name()}, KeyID: "baz"}
+				return &SignerConfig{Name: "foo", KeyStore: &KeyStore{Path: keyFile.Name()}, KeyID: "baz"}
 			},
 			assert: func(t *testing.T, err error, _ *jwtSigner) {
 				t.Helper()
@@ -240,7 +320,7 @@ func TestNewJWTSigner(t *testing.T) {
 
 				wm.EXPECT().Add(mock.Anything, mock.Anything).Return(nil)
 
-				return &SignerConfig{Name: "foo", KeyStore: KeyStore{Path: keyFile.Name()}, KeyID: "key1"}
+				return &SignerConfig{Name: "foo", KeyStore: &KeyStore{Path: keyFile.Name()}, KeyID: "key1"}
 			},
 			assert: func(t *testing.T, err error, signer *jwtSigner) {
 				t.Helper()
@@ -261,7 +341,7 @@ func TestNewJWTSigner(t *testing.T) {
 
 				wm.EXPECT().Add(mock.Anything, mock.Anything).Return(nil)
 
-				return &SignerConfig{Name: "foo", KeyStore: KeyStore{Path: keyFile.Name()}, KeyID: "key2"}
+				return &SignerConfig{Name: "foo", KeyStore: &KeyStore{Path: keyFile.Name()}, KeyID: "key2"}
 			},
 			assert: func(t *testing.T, err error, signer *jwtSigner) {
 				t.Helper()
@@ -282,7 +362,7 @@ func TestNewJWTSigner(t *testing.T) {
 
 				wm.EXPECT().Add(mock.Anything, mock.Anything).Return(nil)
 
-				return &SignerConfig{Name: "foo", KeyStore: KeyStore{Path: keyFile.Name()}, KeyID: "key3"}
+				return &SignerConfig{Name: "foo", KeyStore: &KeyStore{Path: keyFile.Name()}, KeyID: "key3"}
 			},
 			assert: func(t *testing.T, err error, signer *jwtSigner) {
 				t.Helper()
@@ -303,7 +383,7 @@ func TestNewJWTSigner(t *testing.T) {
 
 				wm.EXPECT().Add(mock.Anything, mock.Anything).Return(nil)
 
-				return &SignerConfig{Name: "foo", KeyStore: KeyStore{Path: keyFile.Name()}, KeyID: "key4"}
+				return &SignerConfig{Name: "foo", KeyStore: &KeyStore{Path: keyFile.Name()}, KeyID: "key4"}
 			},
 			assert: func(t *testing.T, err error, signer *jwtSigner) {
 				t.Helper()
@@ -324,7 +404,7 @@ func TestNewJWTSigner(t *testing.T) {
 
 				wm.EXPECT().Add(mock.Anything, mock.Anything).Return(nil)
 
-				return &SignerConfig{Name: "foo", KeyStore: KeyStore{Path: keyFile.Name()}, KeyID: "key5"}
+				return &SignerConfig{Name: "foo", KeyStore: &KeyStore{Path: keyFile.Name()}, KeyID: "key5"}
 			},
 			assert: func(t *testing.T, err error, signer *jwtSigner) {
 				t.Helper()
@@ -345,7 +425,7 @@ func TestNewJWTSigner(t *testing.T) {
 
 				wm.EXPECT().Add(mock.Anything, mock.Anything).Return(nil)
 
-				return &SignerConfig{Name: "foo", KeyStore: KeyStore{Path: keyFile.Name()}, KeyID: "key6"}
+				return &SignerConfig{Name: "foo", KeyStore: &KeyStore{Path: keyFile.Name()}, KeyID: "key6"}
 			},
 			assert: func(t *testing.T, err error, signer *jwtSigner) {
 				t.Helper()
@@ -364,7 +444,7 @@ func TestNewJWTSigner(t *testing.T) {
 			config: func(t *testing.T, _ *mocks.WatcherMock) *SignerConfig {
 				t.Helper()
 
-				return &SignerConfig{Name: "foo", KeyStore: KeyStore{Path: "/does/not/exist"}}
+				return &SignerConfig{Name: "foo", KeyStore: &KeyStore{Path: "/does/not/exist"}}
 			},
 			assert: func(t *testing.T, err error, _ *jwtSigner) {
 				t.Helper()
@@ -381,7 +461,7 @@ func TestNewJWTSigner(t *testing.T) {
 
 				return &SignerConfig{
 					Name:     "foo",
-					KeyStore: KeyStore{Path: keyFile.Name()},
+					KeyStore: &KeyStore{Path: keyFile.Name()},
 					KeyID:    "missing_key_usage",
 				}
 			},
@@ -402,7 +482,7 @@ func TestNewJWTSigner(t *testing.T) {
 
 				return &SignerConfig{
 					Name:     "foo",
-					KeyStore: KeyStore{Path: keyFile.Name()},
+					KeyStore: &KeyStore{Path: keyFile.Name()},
 					KeyID:    "self_signed",
 				}
 			},
@@ -427,7 +507,7 @@ func TestNewJWTSigner(t *testing.T) {
 
 				return &SignerConfig{
 					Name:     "foo",
-					KeyStore: KeyStore{Path: keyFile.Name()},
+					KeyStore: &KeyStore{Path: keyFile.Name()},
 					KeyID:    "key7",
 				}
 			},
@@ -452,7 +532,7 @@ func TestNewJWTSigner(t *testing.T) {
 
 				return &SignerConfig{
 					Name:     "foo",
-					KeyStore: KeyStore{Path: keyFile.Name()},
+					KeyStore: &KeyStore{Path: keyFile.Name()},
 					KeyID:    "self_signed",
 				}
 			},
@@ -467,7 +547,7 @@ func TestNewJWTSigner(t *testing.T) {
 		t.Run("case="+tc.uc, func(t *testing.T) {
 			// WHEN
 			wm := mocks.NewWatcherMock(t)
-			signer, err := newJWTSigner(tc.config(t, wm), wm)
+			signer, err := newJWTSigner(tc.config(t, wm), wm, &scheduler.NoopScheduler{}, log.Logger)
 
 			// THEN
 			tc.assert(t, err, signer)
@@ -653,8 +733,10 @@ func TestJwtSignerKeys(t *testing.T) {
 	fw.EXPECT().Add(keyFile.Name(), mock.Anything).Return(nil)
 
 	signer, err := newJWTSigner(
-		&SignerConfig{KeyStore: KeyStore{Path: keyFile.Name()}},
+		&SignerConfig{KeyStore: &KeyStore{Path: keyFile.Name()}},
 		fw,
+		&scheduler.NoopScheduler{},
+		log.Logger,
 	)
 	require.NoError(t, err)
 
@@ -756,3 +838,186 @@ func TestJWTSignerOnChanged(t *testing.T) {
 	require.Equal(t, cert2, signer.jwk.Certificates[0])
 	require.Equal(t, privKey2, signer.key)
 }
+
+func TestJWTSignerRotation(t *testing.T) {
+	t.Parallel()
+
+	privKey1, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	privKey2, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	pemBytes1, err := pemx.BuildPEM(pemx.WithECDSAPrivateKey(privKey1, pemx.WithHeader("X-Key-ID", "key1")))
+	require.NoError(t, err)
+
+	pemBytes2, err := pemx.BuildPEM(pemx.WithECDSAPrivateKey(privKey2, pemx.WithHeader("X-Key-ID", "key2")))
+	require.NoError(t, err)
+
+	keyIDs := func(keys []jose.JSONWebKey) []string {
+		ids := make([]string, len(keys))
+		for idx, key := range keys {
+			ids[idx] = key.KeyID
+		}
+
+		return ids
+	}
+
+	for _, tc := range []struct {
+		uc     string
+		delay  time.Duration
+		sign   bool
+		assert func(t *testing.T, signer *jwtSigner)
+	}{
+		{
+			uc: "without activation delay and no issued tokens",
+			assert: func(t *testing.T, signer *jwtSigner) {
+				t.Helper()
+
+				assert.Equal(t, "key2", signer.jwk.KeyID)
+				assert.Equal(t, []string{"key2"}, keyIDs(signer.Keys()))
+			},
+		},
+		{
+			uc:   "without activation delay and issued tokens",
+			sign: true,
+			assert: func(t *testing.T, signer *jwtSigner) {
+				t.Helper()
+
+				assert.Equal(t, "key2", signer.jwk.KeyID)
+				assert.Equal(t, []string{"key2", "key1"}, keyIDs(signer.Keys()))
+			},
+		},
+		{
+			uc:    "with activation delay not yet elapsed",
+			delay: time.Hour,
+			sign:  true,
+			assert: func(t *testing.T, signer *jwtSigner) {
+				t.Helper()
+
+				_, err := signer.Sign("foo", time.Minute, nil)
+				require.NoError(t, err)
+
+				assert.Equal(t, "key1", signer.jwk.KeyID)
+				assert.Equal(t, privKey1, signer.key)
+				assert.Equal(t, []string{"key2", "key1"}, keyIDs(signer.Keys()))
+			},
+		},
+		{
+			uc:    "with activation delay elapsed",
+			delay: 50 * time.Millisecond,
+			sign:  true,
+			assert: func(t *testing.T, signer *jwtSigner) {
+				t.Helper()
+
+				time.Sleep(100 * time.Millisecond)
+
+				rawJWT, err := signer.Sign("foo", time.Minute, nil)
+				require.NoError(t, err)
+
+				token, err := jwt.ParseSigned(rawJWT, []jose.SignatureAlgorithm{jose.ES256})
+				require.NoError(t, err)
+				require.Len(t, token.Headers, 1)
+
+				assert.Equal(t, "key2", token.Headers[0].KeyID)
+				assert.Equal(t, privKey2, signer.key)
+				assert.Equal(t, []string{"key2", "key1"}, keyIDs(signer.Keys()))
+			},
+		},
+	} {
+		t.Run(tc.uc, func(t *testing.T) {
+			t.Parallel()
+
+			// GIVEN
+			pemFile := filepath.Join(t.TempDir(), "keystore.pem")
+			require.NoError(t, os.WriteFile(pemFile, pemBytes1, 0o600))
+
+			wm := mocks.NewWatcherMock(t)
+			wm.EXPECT().Add(pemFile, mock.Anything).Return(nil)
+
+			signer, err := newJWTSigner(
+				&SignerConfig{
+					KeyStore: &KeyStore{Path: pemFile},
+					Rotation: &KeyRotation{ActivationDelay: tc.delay},
+				},
+				wm,
+				&scheduler.NoopScheduler{},
+				log.Logger,
+			)
+			require.NoError(t, err)
+
+			if tc.sign {
+				_, err = signer.Sign("foo", time.Minute, nil)
+				require.NoError(t, err)
+			}
+
+			// WHEN
+			require.NoError(t, os.WriteFile(pemFile, pemBytes2, 0o600))
+			signer.OnChanged(log.Logger)
+
+			// THEN
+			tc.assert(t, signer)
+		})
+	}
+}
+
+func TestJWTSignerGeneratesKeysOnSchedule(t *testing.T) {
+	t.Parallel()
+
+	// GIVEN
+	var task func(ctx context.Context)
+
+	sch := schedulermocks.NewSchedulerMock(t)
+	sch.EXPECT().Schedule(mock.Anything, 2*time.Hour, mock.Anything).
+		Run(func(_ context.Context, _ time.Duration, fn func(ctx context.Context)) { task = fn }).
+		Return(nil)
+
+	signer, err := newJWTSigner(
+		&SignerConfig{
+			Rotation: &KeyRotation{
+				ActivationDelay: time.Hour,
+				Generate:        &KeyGeneration{Interval: 2 * time.Hour},
+			},
+		},
+		nil,
+		sch,
+		log.Logger,
+	)
+	require.NoError(t, err)
+	require.NotNil(t, task)
+
+	activeKeyID := signer.jwk.KeyID
+
+	// WHEN
+	task(log.Logger.WithContext(t.Context()))
+
+	// THEN
+	keys := signer.Keys()
+	require.Len(t, keys, 2)
+	assert.Equal(t, activeKeyID, keys[0].KeyID)
+	assert.Equal(t, activeKeyID, signer.jwk.KeyID)
+	require.NotNil(t, signer.next)
+	assert.Equal(t, signer.next.jwk.KeyID, keys[1].KeyID)
+	assert.NotEqual(t, activeKeyID, keys[1].KeyID)
+}
+
+func TestNewJWTSignerWithFailingScheduler(t *testing.T) {
+	t.Parallel()
+
+	// GIVEN
+	sch := schedulermocks.NewSchedulerMock(t)
+	sch.EXPECT().Schedule(mock.Anything, time.Hour, mock.Anything).Return(errors.New("test error"))
+
+	// WHEN
+	_, err := newJWTSigner(
+		&SignerConfig{Rotation: &KeyRotation{Generate: &KeyGeneration{Interval: time.Hour}}},
+		nil,
+		sch,
+		log.Logger,
+	)
+
+	// THEN
+	require.Error(t, err)
+	require.ErrorIs(t, err, heimdall.ErrInternal)
+	require.ErrorContains(t, err, "test error")
+}
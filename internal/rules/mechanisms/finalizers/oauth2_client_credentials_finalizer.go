@@ -74,7 +74,7 @@ func newOAuth2ClientCredentialsFinalizer(
 	}
 
 	var conf Config
-	if err := decodeConfig(app.Validator(), rawConfig, &conf); err != nil {
+	if err := decodeConfig(app, rawConfig, &conf); err != nil {
 		return nil, errorchain.NewWithMessagef(heimdall.ErrConfiguration,
 			"failed decoding config for oauth2_client_credentials finalizer '%s'", id).CausedBy(err)
 	}
@@ -119,7 +119,7 @@ func (f *oauth2ClientCredentialsFinalizer) WithConfig(rawConfig map[string]any)
 	}
 
 	var conf Config
-	if err := decodeConfig(f.app.Validator(), rawConfig, &conf); err != nil {
+	if err := decodeConfig(f.app, rawConfig, &conf); err != nil {
 		return nil, errorchain.NewWithMessagef(heimdall.ErrConfiguration,
 			"failed decoding config for oauth2_client_credentials finalizer '%s'", f.id).CausedBy(err)
 	}
@@ -0,0 +1,296 @@
+// Copyright 2025 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package finalizers
+
+import (
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/rs/zerolog"
+
+	"github.com/dadrus/heimdall/internal/app"
+	"github.com/dadrus/heimdall/internal/cache"
+	"github.com/dadrus/heimdall/internal/heimdall"
+	"github.com/dadrus/heimdall/internal/rules/mechanisms/subject"
+	"github.com/dadrus/heimdall/internal/rules/mechanisms/template"
+	"github.com/dadrus/heimdall/internal/rules/mechanisms/values"
+	"github.com/dadrus/heimdall/internal/x"
+	"github.com/dadrus/heimdall/internal/x/errorchain"
+	"github.com/dadrus/heimdall/internal/x/stringx"
+)
+
+const defaultPASETOTTL = 5 * time.Minute
+
+// by intention. Used only during application bootstrap
+//
+//nolint:gochecknoinits
+func init() {
+	registerTypeFactory(
+		func(app app.Context, id string, typ string, conf map[string]any) (bool, Finalizer, error) {
+			if typ != FinalizerPASETO {
+				return false, nil, nil
+			}
+
+			finalizer, err := newPASETOFinalizer(app, id, conf)
+
+			return true, finalizer, err
+		})
+}
+
+type pasetoFinalizer struct {
+	id           string
+	app          app.Context
+	claims       template.Template
+	ttl          time.Duration
+	headerName   string
+	headerScheme string
+	issuer       pasetoIssuer
+	v            values.Values
+}
+
+func newPASETOFinalizer(app app.Context, id string, rawConfig map[string]any) (*pasetoFinalizer, error) {
+	logger := app.Logger()
+	logger.Info().Str("_id", id).Msg("Creating paseto finalizer")
+
+	type HeaderConfig struct {
+		Name   string `mapstructure:"name"   validate:"required"`
+		Scheme string `mapstructure:"scheme"`
+	}
+
+	type Config struct {
+		Signer       *SignerConfig       `mapstructure:"signer"        validate:"required_without=SymmetricKey,excluded_with=SymmetricKey"` //nolint:lll,tagalign
+		SymmetricKey *SymmetricKeyConfig `mapstructure:"symmetric_key" validate:"required_without=Signer"`                                  //nolint:lll,tagalign
+		TTL          *time.Duration      `mapstructure:"ttl"           validate:"omitempty,gt=1s"`                                          //nolint:lll,tagalign
+		Claims       template.Template   `mapstructure:"claims"`
+		Values       values.Values       `mapstructure:"values"`
+		Header       *HeaderConfig       `mapstructure:"header"`
+	}
+
+	var conf Config
+	if err := decodeConfig(app, rawConfig, &conf); err != nil {
+		return nil, errorchain.NewWithMessagef(heimdall.ErrConfiguration,
+			"failed decoding config for paseto finalizer '%s'", id).CausedBy(err)
+	}
+
+	fin := &pasetoFinalizer{
+		id:     id,
+		app:    app,
+		claims: conf.Claims,
+		ttl: x.IfThenElseExec(conf.TTL != nil,
+			func() time.Duration { return *conf.TTL },
+			func() time.Duration { return defaultPASETOTTL }),
+		headerName: x.IfThenElseExec(conf.Header != nil,
+			func() string { return conf.Header.Name },
+			func() string { return "Authorization" }),
+		headerScheme: x.IfThenElseExec(conf.Header != nil,
+			func() string { return conf.Header.Scheme },
+			func() string { return "Bearer" }),
+		v: conf.Values,
+	}
+
+	if conf.Signer != nil {
+		signer, err := newPASETOSigner(conf.Signer, app.Watcher(), app.Scheduler(), logger)
+		if err != nil {
+			return nil, err
+		}
+
+		app.KeyHolderRegistry().AddKeyHolder(signer)
+		app.CertificateObserver().Add(fin)
+
+		fin.issuer = signer
+	} else {
+		encrypter, err := newPASETOEncrypter(conf.SymmetricKey, app.Watcher())
+		if err != nil {
+			return nil, err
+		}
+
+		fin.issuer = encrypter
+	}
+
+	return fin, nil
+}
+
+func (f *pasetoFinalizer) Execute(ctx heimdall.RequestContext, sub *subject.Subject) error {
+	logger := zerolog.Ctx(ctx.Context())
+	logger.Debug().Str("_id", f.id).Msg("Finalizing using PASETO finalizer")
+
+	if sub == nil {
+		return errorchain.
+			NewWithMessage(heimdall.ErrInternal, "failed to execute paseto finalizer due to 'nil' subject").
+			WithErrorContext(f)
+	}
+
+	cch := cache.Ctx(ctx.Context())
+
+	var (
+		token string
+		err   error
+	)
+
+	cacheKey := f.calculateCacheKey(ctx, sub)
+	if entry, err := cch.Get(ctx.Context(), cacheKey); err == nil {
+		logger.Debug().Msg("Reusing PASETO token from cache")
+
+		token = stringx.ToString(entry)
+	}
+
+	if len(token) == 0 {
+		token, err = f.generateToken(ctx, sub)
+		if err != nil {
+			return err
+		}
+
+		if len(cacheKey) != 0 && f.ttl > defaultCacheLeeway {
+			if err = cch.Set(ctx.Context(), cacheKey, stringx.ToBytes(token), f.ttl-defaultCacheLeeway); err != nil {
+				logger.Warn().Err(err).Msg("Failed to cache PASETO token")
+			}
+		}
+	}
+
+	ctx.AddHeaderForUpstream(f.headerName, fmt.Sprintf("%s %s", f.headerScheme, token))
+
+	return nil
+}
+
+func (f *pasetoFinalizer) WithConfig(rawConfig map[string]any) (Finalizer, error) {
+	if len(rawConfig) == 0 {
+		return f, nil
+	}
+
+	type Config struct {
+		TTL    *time.Duration    `mapstructure:"ttl"    validate:"omitempty,gt=1s"`
+		Claims template.Template `mapstructure:"claims"`
+		Values values.Values     `mapstructure:"values"`
+	}
+
+	var conf Config
+	if err := decodeConfig(f.app, rawConfig, &conf); err != nil {
+		return nil, errorchain.NewWithMessagef(heimdall.ErrConfiguration,
+			"failed decoding config for paseto finalizer '%s'", f.id).CausedBy(err)
+	}
+
+	return &pasetoFinalizer{
+		id:     f.id,
+		app:    f.app,
+		claims: x.IfThenElse(conf.Claims != nil, conf.Claims, f.claims),
+		ttl: x.IfThenElseExec(conf.TTL != nil,
+			func() time.Duration { return *conf.TTL },
+			func() time.Duration { return f.ttl }),
+		headerName:   f.headerName,
+		headerScheme: f.headerScheme,
+		issuer:       f.issuer,
+		v:            f.v.Merge(conf.Values),
+	}, nil
+}
+
+func (f *pasetoFinalizer) ID() string { return f.id }
+
+func (f *pasetoFinalizer) ContinueOnError() bool { return false }
+
+func (f *pasetoFinalizer) generateToken(ctx heimdall.RequestContext, sub *subject.Subject) (string, error) {
+	logger := zerolog.Ctx(ctx.Context())
+	logger.Debug().Msg("Generating new PASETO token")
+
+	result := map[string]any{}
+
+	if f.claims != nil {
+		vals, err := f.v.Render(map[string]any{
+			"Subject": sub,
+			"Outputs": ctx.Outputs(),
+		})
+		if err != nil {
+			return "", errorchain.NewWithMessage(heimdall.ErrInternal,
+				"failed to render values").
+				WithErrorContext(f).
+				CausedBy(err)
+		}
+
+		claims, err := f.claims.Render(map[string]any{
+			"Subject": sub,
+			"Outputs": ctx.Outputs(),
+			"Values":  vals,
+		})
+		if err != nil {
+			return "", errorchain.
+				NewWithMessage(heimdall.ErrInternal, "failed to render claims").
+				WithErrorContext(f).
+				CausedBy(err)
+		}
+
+		logger.Debug().Str("_value", claims).Msg("Rendered template")
+
+		if err = json.Unmarshal(stringx.ToBytes(claims), &result); err != nil {
+			return "", errorchain.
+				NewWithMessage(heimdall.ErrInternal, "failed to unmarshal claims rendered by template").
+				WithErrorContext(f).
+				CausedBy(err)
+		}
+	}
+
+	token, err := f.issuer.Issue(sub.ID, f.ttl, result)
+	if err != nil {
+		return "", errorchain.
+			NewWithMessage(heimdall.ErrInternal, "failed to issue token").
+			WithErrorContext(f).
+			CausedBy(err)
+	}
+
+	return token, nil
+}
+
+func (f *pasetoFinalizer) calculateCacheKey(ctx heimdall.RequestContext, sub *subject.Subject) string {
+	const int64BytesCount = 8
+
+	ttlBytes := make([]byte, int64BytesCount)
+
+	//nolint:gosec
+	// no integer overflow during conversion possible
+	binary.LittleEndian.PutUint64(ttlBytes, uint64(f.ttl))
+
+	hash := sha256.New()
+	hash.Write(f.issuer.Hash())
+	hash.Write(x.IfThenElseExec(f.claims != nil,
+		func() []byte { return f.claims.Hash() },
+		func() []byte { return []byte{} }))
+	hash.Write(ttlBytes)
+	hash.Write(sub.Hash())
+
+	for key, val := range f.v {
+		hash.Write(stringx.ToBytes(key))
+		hash.Write(val.Hash())
+	}
+
+	rawSub, _ := json.Marshal(ctx.Outputs())
+	hash.Write(rawSub)
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+func (f *pasetoFinalizer) Name() string { return f.id }
+
+func (f *pasetoFinalizer) Certificates() []*x509.Certificate {
+	if signer, ok := f.issuer.(*pasetoSigner); ok {
+		return signer.activeCertificateChain()
+	}
+
+	return nil
+}
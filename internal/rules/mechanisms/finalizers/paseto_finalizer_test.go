@@ -0,0 +1,677 @@
+// Copyright 2025 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package finalizers
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dadrus/heimdall/internal/app"
+	"github.com/dadrus/heimdall/internal/cache"
+	"github.com/dadrus/heimdall/internal/cache/mocks"
+	"github.com/dadrus/heimdall/internal/config"
+	"github.com/dadrus/heimdall/internal/heimdall"
+	heimdallmocks "github.com/dadrus/heimdall/internal/heimdall/mocks"
+	mocks3 "github.com/dadrus/heimdall/internal/keyholder/mocks"
+	mocks4 "github.com/dadrus/heimdall/internal/otel/metrics/certificate/mocks"
+	"github.com/dadrus/heimdall/internal/rules/mechanisms/subject"
+	"github.com/dadrus/heimdall/internal/scheduler"
+	"github.com/dadrus/heimdall/internal/validation"
+	mocks2 "github.com/dadrus/heimdall/internal/watcher/mocks"
+	"github.com/dadrus/heimdall/internal/x"
+	"github.com/dadrus/heimdall/internal/x/paseto"
+	"github.com/dadrus/heimdall/internal/x/pkix/pemx"
+	"github.com/dadrus/heimdall/internal/x/testsupport"
+)
+
+func TestCreatePASETOFinalizer(t *testing.T) {
+	t.Parallel()
+
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	ecKey, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	require.NoError(t, err)
+
+	edPEMBytes, err := pemx.BuildPEM(pemx.WithEd25519PrivateKey(edKey, pemx.WithHeader("X-Key-ID", "key")))
+	require.NoError(t, err)
+
+	ecPEMBytes, err := pemx.BuildPEM(pemx.WithECDSAPrivateKey(ecKey, pemx.WithHeader("X-Key-ID", "key")))
+	require.NoError(t, err)
+
+	symKey := make([]byte, paseto.KeySize)
+	_, err = rand.Read(symKey)
+	require.NoError(t, err)
+
+	testDir := t.TempDir()
+	edPEMFile := filepath.Join(testDir, "ed25519.pem")
+	ecPEMFile := filepath.Join(testDir, "ecdsa.pem")
+	hexKeyFile := filepath.Join(testDir, "hex.key")
+	rawKeyFile := filepath.Join(testDir, "raw.key")
+	badKeyFile := filepath.Join(testDir, "bad.key")
+
+	require.NoError(t, os.WriteFile(edPEMFile, edPEMBytes, 0o600))
+	require.NoError(t, os.WriteFile(ecPEMFile, ecPEMBytes, 0o600))
+	require.NoError(t, os.WriteFile(hexKeyFile, []byte(hex.EncodeToString(symKey)+"\n"), 0o600))
+	require.NoError(t, os.WriteFile(rawKeyFile, symKey, 0o600))
+	require.NoError(t, os.WriteFile(badKeyFile, []byte("foo"), 0o600))
+
+	for uc, tc := range map[string]struct {
+		config              []byte
+		configureAppContext func(t *testing.T, ctx *app.ContextMock)
+		assert              func(t *testing.T, err error, finalizer *pasetoFinalizer)
+	}{
+		"without config": {
+			configureAppContext: func(t *testing.T, _ *app.ContextMock) { t.Helper() },
+			assert: func(t *testing.T, err error, _ *pasetoFinalizer) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				require.ErrorContains(t, err, "'signer' is a required field")
+				require.ErrorContains(t, err, "'symmetric_key' is a required field")
+			},
+		},
+		"with signer and symmetric key": {
+			config: []byte(`
+signer:
+  key_store:
+    path: ` + edPEMFile + `
+symmetric_key:
+  path: ` + hexKeyFile + `
+`),
+			configureAppContext: func(t *testing.T, _ *app.ContextMock) { t.Helper() },
+			assert: func(t *testing.T, err error, _ *pasetoFinalizer) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				require.ErrorContains(t, err, "'signer' is an excluded field")
+			},
+		},
+		"with too short ttl": {
+			config: []byte(`
+ttl: 5ms
+symmetric_key:
+  path: ` + hexKeyFile + `
+`),
+			configureAppContext: func(t *testing.T, _ *app.ContextMock) { t.Helper() },
+			assert: func(t *testing.T, err error, _ *pasetoFinalizer) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				require.ErrorContains(t, err, "'ttl' must be greater than 1s")
+			},
+		},
+		"with signer using non ed25519 key": {
+			config: []byte(`
+signer:
+  key_store:
+    path: ` + ecPEMFile + `
+`),
+			configureAppContext: func(t *testing.T, ctx *app.ContextMock) {
+				t.Helper()
+
+				wm := mocks2.NewWatcherMock(t)
+				wm.EXPECT().Add(ecPEMFile, mock.Anything).Return(nil)
+
+				ctx.EXPECT().Watcher().Return(wm)
+
+				ctx.EXPECT().Scheduler().Maybe().Return(&scheduler.NoopScheduler{})
+			},
+			assert: func(t *testing.T, err error, _ *pasetoFinalizer) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				require.ErrorContains(t, err, "can only be signed with ed25519 keys")
+			},
+		},
+		"with signer": {
+			config: []byte(`
+signer:
+  name: foo
+  key_store:
+    path: ` + edPEMFile + `
+claims: '{ "foo": "bar" }'
+header:
+  name: X-Token
+`),
+			configureAppContext: func(t *testing.T, ctx *app.ContextMock) {
+				t.Helper()
+
+				wm := mocks2.NewWatcherMock(t)
+				wm.EXPECT().Add(edPEMFile, mock.Anything).Return(nil)
+
+				khr := mocks3.NewRegistryMock(t)
+				khr.EXPECT().AddKeyHolder(mock.Anything)
+
+				co := mocks4.NewObserverMock(t)
+				co.EXPECT().Add(mock.Anything)
+
+				ctx.EXPECT().Watcher().Return(wm)
+
+				ctx.EXPECT().Scheduler().Maybe().Return(&scheduler.NoopScheduler{})
+				ctx.EXPECT().KeyHolderRegistry().Return(khr)
+				ctx.EXPECT().CertificateObserver().Return(co)
+			},
+			assert: func(t *testing.T, err error, finalizer *pasetoFinalizer) {
+				t.Helper()
+
+				require.NoError(t, err)
+				require.NotNil(t, finalizer)
+
+				assert.Equal(t, "with signer", finalizer.ID())
+				assert.Equal(t, finalizer.Name(), finalizer.ID())
+				assert.Equal(t, defaultPASETOTTL, finalizer.ttl)
+				assert.NotNil(t, finalizer.claims)
+				assert.Equal(t, "X-Token", finalizer.headerName)
+				assert.Empty(t, finalizer.headerScheme)
+				assert.False(t, finalizer.ContinueOnError())
+				assert.Empty(t, finalizer.Certificates())
+
+				signer, ok := finalizer.issuer.(*pasetoSigner)
+				require.True(t, ok)
+				assert.Equal(t, "foo", signer.iss)
+				assert.Equal(t, edKey, signer.key)
+				assert.Equal(t, "key", signer.jwk.KeyID)
+			},
+		},
+		"with hex encoded symmetric key": {
+			config: []byte(`
+ttl: 1m
+symmetric_key:
+  name: bar
+  path: ` + hexKeyFile + `
+  key_id: sym
+`),
+			configureAppContext: func(t *testing.T, ctx *app.ContextMock) {
+				t.Helper()
+
+				wm := mocks2.NewWatcherMock(t)
+				wm.EXPECT().Add(hexKeyFile, mock.Anything).Return(nil)
+
+				ctx.EXPECT().Watcher().Return(wm)
+
+				ctx.EXPECT().Scheduler().Maybe().Return(&scheduler.NoopScheduler{})
+			},
+			assert: func(t *testing.T, err error, finalizer *pasetoFinalizer) {
+				t.Helper()
+
+				require.NoError(t, err)
+				require.NotNil(t, finalizer)
+
+				assert.Equal(t, time.Minute, finalizer.ttl)
+				assert.Nil(t, finalizer.claims)
+				assert.Equal(t, "Authorization", finalizer.headerName)
+				assert.Equal(t, "Bearer", finalizer.headerScheme)
+				assert.Empty(t, finalizer.Certificates())
+
+				encrypter, ok := finalizer.issuer.(*pasetoEncrypter)
+				require.True(t, ok)
+				assert.Equal(t, "bar", encrypter.iss)
+				assert.Equal(t, "sym", encrypter.keyID)
+				assert.Equal(t, symKey, encrypter.key)
+			},
+		},
+		"with raw symmetric key": {
+			config: []byte(`
+symmetric_key:
+  path: ` + rawKeyFile + `
+`),
+			configureAppContext: func(t *testing.T, ctx *app.ContextMock) {
+				t.Helper()
+
+				wm := mocks2.NewWatcherMock(t)
+				wm.EXPECT().Add(rawKeyFile, mock.Anything).Return(nil)
+
+				ctx.EXPECT().Watcher().Return(wm)
+
+				ctx.EXPECT().Scheduler().Maybe().Return(&scheduler.NoopScheduler{})
+			},
+			assert: func(t *testing.T, err error, finalizer *pasetoFinalizer) {
+				t.Helper()
+
+				require.NoError(t, err)
+
+				encrypter, ok := finalizer.issuer.(*pasetoEncrypter)
+				require.True(t, ok)
+				assert.Equal(t, "heimdall", encrypter.iss)
+				assert.Empty(t, encrypter.keyID)
+				assert.Equal(t, symKey, encrypter.key)
+			},
+		},
+		"with symmetric key of wrong size": {
+			config: []byte(`
+symmetric_key:
+  path: ` + badKeyFile + `
+`),
+			configureAppContext: func(t *testing.T, ctx *app.ContextMock) {
+				t.Helper()
+
+				ctx.EXPECT().Watcher().Return(mocks2.NewWatcherMock(t))
+
+				ctx.EXPECT().Scheduler().Maybe().Return(&scheduler.NoopScheduler{})
+			},
+			assert: func(t *testing.T, err error, _ *pasetoFinalizer) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				require.ErrorContains(t, err, "failed loading symmetric key")
+				require.ErrorContains(t, err, "key must be 32 bytes long")
+			},
+		},
+		"with not existing symmetric key file": {
+			config: []byte(`
+symmetric_key:
+  path: /does/not/exist.key
+`),
+			configureAppContext: func(t *testing.T, ctx *app.ContextMock) {
+				t.Helper()
+
+				ctx.EXPECT().Watcher().Return(mocks2.NewWatcherMock(t))
+
+				ctx.EXPECT().Scheduler().Maybe().Return(&scheduler.NoopScheduler{})
+			},
+			assert: func(t *testing.T, err error, _ *pasetoFinalizer) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				require.ErrorContains(t, err, "failed loading symmetric key")
+			},
+		},
+	} {
+		t.Run(uc, func(t *testing.T) {
+			conf, err := testsupport.DecodeTestConfig(tc.config)
+			require.NoError(t, err)
+
+			validator, err := validation.NewValidator(
+				validation.WithTagValidator(config.EnforcementSettings{}),
+			)
+			require.NoError(t, err)
+
+			appCtx := app.NewContextMock(t)
+			appCtx.EXPECT().Validator().Maybe().Return(validator)
+			appCtx.EXPECT().Logger().Return(log.Logger)
+
+			tc.configureAppContext(t, appCtx)
+
+			// WHEN
+			finalizer, err := newPASETOFinalizer(appCtx, uc, conf)
+
+			// THEN
+			tc.assert(t, err, finalizer)
+		})
+	}
+}
+
+func TestCreatePASETOFinalizerFromPrototype(t *testing.T) {
+	t.Parallel()
+
+	symKey := make([]byte, paseto.KeySize)
+	_, err := rand.Read(symKey)
+	require.NoError(t, err)
+
+	keyFile := filepath.Join(t.TempDir(), "paseto.key")
+	require.NoError(t, os.WriteFile(keyFile, []byte(hex.EncodeToString(symKey)), 0o600))
+
+	for uc, tc := range map[string]struct {
+		config []byte
+		assert func(t *testing.T, err error, prototype, configured *pasetoFinalizer)
+	}{
+		"with empty config": {
+			assert: func(t *testing.T, err error, prototype, configured *pasetoFinalizer) {
+				t.Helper()
+
+				require.NoError(t, err)
+				assert.Equal(t, prototype, configured)
+			},
+		},
+		"with not overridable property": {
+			config: []byte(`header: { name: Foo }`),
+			assert: func(t *testing.T, err error, _, _ *pasetoFinalizer) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				require.ErrorContains(t, err, "invalid keys: header")
+			},
+		},
+		"with ttl and claims": {
+			config: []byte(`
+ttl: 1m
+claims: '{ "foo": "bar" }'
+`),
+			assert: func(t *testing.T, err error, prototype, configured *pasetoFinalizer) {
+				t.Helper()
+
+				require.NoError(t, err)
+				require.NotNil(t, configured)
+				assert.NotEqual(t, prototype, configured)
+
+				assert.Equal(t, prototype.ID(), configured.ID())
+				assert.Equal(t, defaultPASETOTTL, prototype.ttl)
+				assert.Equal(t, time.Minute, configured.ttl)
+				assert.Nil(t, prototype.claims)
+				assert.NotNil(t, configured.claims)
+				assert.Equal(t, prototype.issuer, configured.issuer)
+				assert.Equal(t, prototype.headerName, configured.headerName)
+				assert.Equal(t, prototype.headerScheme, configured.headerScheme)
+			},
+		},
+	} {
+		t.Run(uc, func(t *testing.T) {
+			protoConf, err := testsupport.DecodeTestConfig([]byte(`
+symmetric_key:
+  path: ` + keyFile + `
+`))
+			require.NoError(t, err)
+
+			conf, err := testsupport.DecodeTestConfig(tc.config)
+			require.NoError(t, err)
+
+			validator, err := validation.NewValidator(
+				validation.WithTagValidator(config.EnforcementSettings{}),
+			)
+			require.NoError(t, err)
+
+			wm := mocks2.NewWatcherMock(t)
+			wm.EXPECT().Add(keyFile, mock.Anything).Return(nil)
+
+			appCtx := app.NewContextMock(t)
+			appCtx.EXPECT().Validator().Maybe().Return(validator)
+			appCtx.EXPECT().Logger().Return(log.Logger)
+			appCtx.EXPECT().Watcher().Return(wm)
+			appCtx.EXPECT().Scheduler().Maybe().Return(&scheduler.NoopScheduler{})
+
+			prototype, err := newPASETOFinalizer(appCtx, uc, protoConf)
+			require.NoError(t, err)
+
+			// WHEN
+			finalizer, err := prototype.WithConfig(conf)
+
+			// THEN
+			var (
+				ok            bool
+				realFinalizer *pasetoFinalizer
+			)
+
+			if err == nil {
+				realFinalizer, ok = finalizer.(*pasetoFinalizer)
+				require.True(t, ok)
+			}
+
+			tc.assert(t, err, prototype, realFinalizer)
+		})
+	}
+}
+
+func TestPASETOFinalizerExecute(t *testing.T) {
+	t.Parallel()
+
+	edPubKey, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	symKey := make([]byte, paseto.KeySize)
+	_, err = rand.Read(symKey)
+	require.NoError(t, err)
+
+	pemBytes, err := pemx.BuildPEM(pemx.WithEd25519PrivateKey(edKey, pemx.WithHeader("X-Key-ID", "key")))
+	require.NoError(t, err)
+
+	testDir := t.TempDir()
+	pemFile := filepath.Join(testDir, "keystore.pem")
+	keyFile := filepath.Join(testDir, "paseto.key")
+
+	require.NoError(t, os.WriteFile(pemFile, pemBytes, 0o600))
+	require.NoError(t, os.WriteFile(keyFile, symKey, 0o600))
+
+	assertClaims := func(t *testing.T, payload []byte, iss string) {
+		t.Helper()
+
+		var claims map[string]any
+
+		require.NoError(t, json.Unmarshal(payload, &claims))
+		assert.Equal(t, iss, claims["iss"])
+		assert.Equal(t, "foo", claims["sub"])
+		assert.Equal(t, "bar", claims["baz"])
+		assert.NotEmpty(t, claims["jti"])
+
+		iat, err := time.Parse(time.RFC3339, claims["iat"].(string))
+		require.NoError(t, err)
+
+		exp, err := time.Parse(time.RFC3339, claims["exp"].(string))
+		require.NoError(t, err)
+
+		assert.Equal(t, time.Minute, exp.Sub(iat))
+	}
+
+	for uc, tc := range map[string]struct {
+		config         []byte
+		subject        *subject.Subject
+		configureMocks func(t *testing.T, fin *pasetoFinalizer, ctx *heimdallmocks.RequestContextMock,
+			cch *mocks.CacheMock, sub *subject.Subject)
+		assert func(t *testing.T, err error)
+	}{
+		"with 'nil' subject": {
+			config: []byte(`
+symmetric_key:
+  path: ` + keyFile + `
+`),
+			assert: func(t *testing.T, err error) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrInternal)
+				require.ErrorContains(t, err, "'nil' subject")
+
+				var identifier interface{ ID() string }
+				require.ErrorAs(t, err, &identifier)
+				assert.Equal(t, "with 'nil' subject", identifier.ID())
+			},
+		},
+		"with used prefilled cache": {
+			config: []byte(`
+symmetric_key:
+  path: ` + keyFile + `
+`),
+			subject: &subject.Subject{ID: "foo"},
+			configureMocks: func(t *testing.T, fin *pasetoFinalizer, ctx *heimdallmocks.RequestContextMock,
+				cch *mocks.CacheMock, sub *subject.Subject,
+			) {
+				t.Helper()
+
+				ctx.EXPECT().Outputs().Return(map[string]any{"foo": "bar"})
+				ctx.EXPECT().AddHeaderForUpstream("Authorization", "Bearer TestToken")
+
+				cch.EXPECT().Get(mock.Anything, fin.calculateCacheKey(ctx, sub)).Return([]byte("TestToken"), nil)
+			},
+			assert: func(t *testing.T, err error) {
+				t.Helper()
+
+				require.NoError(t, err)
+			},
+		},
+		"with claims rendering error": {
+			config: []byte(`
+symmetric_key:
+  path: ` + keyFile + `
+claims: "{{ len .foobar }}"
+`),
+			subject: &subject.Subject{ID: "foo"},
+			configureMocks: func(t *testing.T, _ *pasetoFinalizer, ctx *heimdallmocks.RequestContextMock,
+				cch *mocks.CacheMock, _ *subject.Subject,
+			) {
+				t.Helper()
+
+				ctx.EXPECT().Outputs().Return(map[string]any{})
+				cch.EXPECT().Get(mock.Anything, mock.Anything).Return(nil, errors.New("no cache entry"))
+			},
+			assert: func(t *testing.T, err error) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrInternal)
+				require.ErrorContains(t, err, "failed to render claims")
+			},
+		},
+		"issuing v4.public token": {
+			config: []byte(`
+ttl: 1m
+signer:
+  name: foo
+  key_store:
+    path: ` + pemFile + `
+claims: '{ "baz": {{ quote .Outputs.baz }} }'
+`),
+			subject: &subject.Subject{ID: "foo"},
+			configureMocks: func(t *testing.T, _ *pasetoFinalizer, ctx *heimdallmocks.RequestContextMock,
+				cch *mocks.CacheMock, _ *subject.Subject,
+			) {
+				t.Helper()
+
+				ctx.EXPECT().Outputs().Return(map[string]any{"baz": "bar"})
+				ctx.EXPECT().AddHeaderForUpstream("Authorization", mock.Anything).Run(
+					func(_ string, value string) {
+						token, found := strings.CutPrefix(value, "Bearer ")
+						require.True(t, found)
+
+						payload, footer, err := paseto.Verify(edPubKey, token, nil)
+						require.NoError(t, err)
+						assert.JSONEq(t, `{"kid":"key"}`, string(footer))
+						assertClaims(t, payload, "foo")
+					})
+
+				cch.EXPECT().Get(mock.Anything, mock.Anything).Return(nil, errors.New("no cache entry"))
+				cch.EXPECT().Set(mock.Anything, mock.Anything, mock.Anything, time.Minute-defaultCacheLeeway).
+					Return(nil)
+			},
+			assert: func(t *testing.T, err error) {
+				t.Helper()
+
+				require.NoError(t, err)
+			},
+		},
+		"issuing v4.local token": {
+			config: []byte(`
+ttl: 1m
+symmetric_key:
+  path: ` + keyFile + `
+claims: '{ "baz": {{ quote .Values.baz }} }'
+values:
+  baz: bar
+header:
+  name: X-Token
+`),
+			subject: &subject.Subject{ID: "foo"},
+			configureMocks: func(t *testing.T, _ *pasetoFinalizer, ctx *heimdallmocks.RequestContextMock,
+				cch *mocks.CacheMock, _ *subject.Subject,
+			) {
+				t.Helper()
+
+				ctx.EXPECT().Outputs().Return(map[string]any{})
+				ctx.EXPECT().AddHeaderForUpstream("X-Token", mock.Anything).Run(
+					func(_ string, value string) {
+						token := strings.TrimSpace(value)
+
+						payload, footer, err := paseto.Decrypt(symKey, token, nil)
+						require.NoError(t, err)
+						assert.Empty(t, footer)
+						assertClaims(t, payload, "heimdall")
+					})
+
+				cch.EXPECT().Get(mock.Anything, mock.Anything).Return(nil, errors.New("no cache entry"))
+				cch.EXPECT().Set(mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+					Return(errors.New("test error"))
+			},
+			assert: func(t *testing.T, err error) {
+				t.Helper()
+
+				require.NoError(t, err)
+			},
+		},
+	} {
+		t.Run(uc, func(t *testing.T) {
+			// GIVEN
+			configureMocks := x.IfThenElse(tc.configureMocks != nil,
+				tc.configureMocks,
+				func(t *testing.T, _ *pasetoFinalizer, _ *heimdallmocks.RequestContextMock,
+					_ *mocks.CacheMock, _ *subject.Subject,
+				) {
+					t.Helper()
+				})
+
+			conf, err := testsupport.DecodeTestConfig(tc.config)
+			require.NoError(t, err)
+
+			validator, err := validation.NewValidator(
+				validation.WithTagValidator(config.EnforcementSettings{}),
+			)
+			require.NoError(t, err)
+
+			wm := mocks2.NewWatcherMock(t)
+			wm.EXPECT().Add(mock.Anything, mock.Anything).Return(nil)
+
+			khr := mocks3.NewRegistryMock(t)
+			khr.EXPECT().AddKeyHolder(mock.Anything).Maybe()
+
+			co := mocks4.NewObserverMock(t)
+			co.EXPECT().Add(mock.Anything).Maybe()
+
+			appCtx := app.NewContextMock(t)
+			appCtx.EXPECT().Validator().Maybe().Return(validator)
+			appCtx.EXPECT().Logger().Return(log.Logger)
+			appCtx.EXPECT().Watcher().Return(wm)
+			appCtx.EXPECT().Scheduler().Maybe().Return(&scheduler.NoopScheduler{})
+			appCtx.EXPECT().KeyHolderRegistry().Maybe().Return(khr)
+			appCtx.EXPECT().CertificateObserver().Maybe().Return(co)
+
+			finalizer, err := newPASETOFinalizer(appCtx, uc, conf)
+			require.NoError(t, err)
+
+			cch := mocks.NewCacheMock(t)
+			ctx := heimdallmocks.NewRequestContextMock(t)
+			ctx.EXPECT().Context().Return(cache.WithContext(t.Context(), cch))
+
+			configureMocks(t, finalizer, ctx, cch, tc.subject)
+
+			// WHEN
+			err = finalizer.Execute(ctx, tc.subject)
+
+			// THEN
+			tc.assert(t, err)
+		})
+	}
+}
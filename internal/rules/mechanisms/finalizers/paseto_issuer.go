@@ -0,0 +1,218 @@
+// Copyright 2025 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package finalizers
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"sync"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/google/uuid"
+	"github.com/knadh/koanf/maps"
+	"github.com/rs/zerolog"
+
+	"github.com/dadrus/heimdall/internal/heimdall"
+	"github.com/dadrus/heimdall/internal/scheduler"
+	"github.com/dadrus/heimdall/internal/watcher"
+	"github.com/dadrus/heimdall/internal/x"
+	"github.com/dadrus/heimdall/internal/x/errorchain"
+	"github.com/dadrus/heimdall/internal/x/paseto"
+	"github.com/dadrus/heimdall/internal/x/stringx"
+)
+
+type SymmetricKeyConfig struct {
+	Name  string `mapstructure:"name"`
+	Path  string `mapstructure:"path"   validate:"required"`
+	KeyID string `mapstructure:"key_id"`
+}
+
+type pasetoIssuer interface {
+	Issue(sub string, ttl time.Duration, customClaims map[string]any) (string, error)
+	Hash() []byte
+}
+
+// pasetoSigner issues v4.public tokens signed with an Ed25519 key from the configured key store.
+// Key store handling (including reloading on changes) is the same as for the jwt signer.
+type pasetoSigner struct {
+	*jwtSigner
+}
+
+func newPASETOSigner(
+	conf *SignerConfig,
+	fw watcher.Watcher,
+	sch scheduler.Scheduler,
+	logger zerolog.Logger,
+) (*pasetoSigner, error) {
+	signer, err := newJWTSigner(conf, fw, sch, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, ok := signer.key.(ed25519.PrivateKey); !ok {
+		return nil, errorchain.NewWithMessage(heimdall.ErrConfiguration,
+			"v4.public PASETO tokens can only be signed with ed25519 keys")
+	}
+
+	return &pasetoSigner{jwtSigner: signer}, nil
+}
+
+func (s *pasetoSigner) Hash() []byte {
+	hash := sha256.New()
+	hash.Write(stringx.ToBytes(paseto.HeaderV4Public))
+	hash.Write(s.jwtSigner.Hash())
+
+	return hash.Sum(nil)
+}
+
+func (s *pasetoSigner) Issue(sub string, ttl time.Duration, customClaims map[string]any) (string, error) {
+	payload, err := pasetoPayload(s.iss, sub, ttl, customClaims)
+	if err != nil {
+		return "", err
+	}
+
+	jwk, key := s.signingKey(time.Now().Add(ttl))
+
+	edKey, ok := key.(ed25519.PrivateKey)
+	if !ok {
+		return "", errorchain.NewWithMessage(heimdall.ErrInternal,
+			"v4.public PASETO tokens can only be signed with ed25519 keys")
+	}
+
+	token, err := paseto.Sign(edKey, payload, pasetoFooter(jwk.KeyID), nil)
+	if err != nil {
+		return "", errorchain.NewWithMessage(heimdall.ErrInternal, "failed to sign claims").CausedBy(err)
+	}
+
+	return token, nil
+}
+
+// pasetoEncrypter issues v4.local tokens encrypted with a symmetric key loaded from a file,
+// which is reloaded on changes.
+type pasetoEncrypter struct {
+	path  string
+	keyID string
+	iss   string
+
+	mut sync.RWMutex
+	key []byte
+}
+
+func newPASETOEncrypter(conf *SymmetricKeyConfig, fw watcher.Watcher) (*pasetoEncrypter, error) {
+	enc := &pasetoEncrypter{
+		path:  conf.Path,
+		keyID: conf.KeyID,
+		iss:   x.IfThenElse(len(conf.Name) == 0, "heimdall", conf.Name),
+	}
+
+	if err := enc.load(); err != nil {
+		return nil, err
+	}
+
+	if err := fw.Add(enc.path, enc); err != nil {
+		return nil, errorchain.NewWithMessage(heimdall.ErrInternal, "failed registering paseto key for updates").
+			CausedBy(err)
+	}
+
+	return enc, nil
+}
+
+func (e *pasetoEncrypter) OnChanged(logger zerolog.Logger) {
+	err := e.load()
+	if err != nil {
+		logger.Warn().Err(err).
+			Str("_file", e.path).
+			Msg("Symmetric key reload failed")
+	} else {
+		logger.Info().
+			Str("_file", e.path).
+			Msg("Symmetric key reloaded")
+	}
+}
+
+func (e *pasetoEncrypter) load() error {
+	key, err := paseto.ReadKeyFile(e.path)
+	if err != nil {
+		return errorchain.NewWithMessage(heimdall.ErrConfiguration, "failed loading symmetric key").
+			CausedBy(err)
+	}
+
+	e.mut.Lock()
+	e.key = key
+	e.mut.Unlock()
+
+	return nil
+}
+
+func (e *pasetoEncrypter) Hash() []byte {
+	hash := sha256.New()
+	hash.Write(stringx.ToBytes(paseto.HeaderV4Local))
+	hash.Write(stringx.ToBytes(e.path))
+	hash.Write(stringx.ToBytes(e.keyID))
+	hash.Write(stringx.ToBytes(e.iss))
+
+	return hash.Sum(nil)
+}
+
+func (e *pasetoEncrypter) Issue(sub string, ttl time.Duration, customClaims map[string]any) (string, error) {
+	e.mut.RLock()
+	key := e.key
+	e.mut.RUnlock()
+
+	payload, err := pasetoPayload(e.iss, sub, ttl, customClaims)
+	if err != nil {
+		return "", err
+	}
+
+	token, err := paseto.Encrypt(key, payload, pasetoFooter(e.keyID), nil)
+	if err != nil {
+		return "", errorchain.NewWithMessage(heimdall.ErrInternal, "failed to encrypt claims").CausedBy(err)
+	}
+
+	return token, nil
+}
+
+func pasetoPayload(iss, sub string, ttl time.Duration, customClaims map[string]any) ([]byte, error) {
+	claims := make(map[string]any)
+	maps.Merge(customClaims, claims)
+
+	now := time.Now().UTC()
+	claims["exp"] = now.Add(ttl).Format(time.RFC3339)
+	claims["jti"] = uuid.New()
+	claims["iat"] = now.Format(time.RFC3339)
+	claims["iss"] = iss
+	claims["nbf"] = now.Format(time.RFC3339)
+	claims["sub"] = sub
+
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return nil, errorchain.NewWithMessage(heimdall.ErrInternal, "failed to marshal claims").CausedBy(err)
+	}
+
+	return payload, nil
+}
+
+func pasetoFooter(keyID string) []byte {
+	if len(keyID) == 0 {
+		return nil
+	}
+
+	footer, _ := json.Marshal(map[string]string{"kid": keyID})
+
+	return footer
+}
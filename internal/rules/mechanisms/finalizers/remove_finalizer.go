@@ -0,0 +1,152 @@
+// Copyright 2025 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package finalizers
+
+import (
+	"strings"
+
+	"github.com/gobwas/glob"
+	"github.com/rs/zerolog"
+
+	"github.com/dadrus/heimdall/internal/app"
+	"github.com/dadrus/heimdall/internal/heimdall"
+	"github.com/dadrus/heimdall/internal/rules/mechanisms/subject"
+	"github.com/dadrus/heimdall/internal/x/errorchain"
+)
+
+// by intention. Used only during application bootstrap
+//
+//nolint:gochecknoinits
+func init() {
+	registerTypeFactory(
+		func(app app.Context, id string, typ string, conf map[string]any) (bool, Finalizer, error) {
+			if typ != FinalizerRemove {
+				return false, nil, nil
+			}
+
+			finalizer, err := newRemoveFinalizer(app, id, conf)
+
+			return true, finalizer, err
+		})
+}
+
+// removeFinalizer strips headers and cookies from the request forwarded to the upstream service.
+// Header names are matched case-insensitively, cookie names case-sensitively. Both support glob patterns.
+type removeFinalizer struct {
+	id      string
+	app     app.Context
+	headers []glob.Glob
+	cookies []glob.Glob
+}
+
+func newRemoveFinalizer(app app.Context, id string, rawConfig map[string]any) (*removeFinalizer, error) {
+	logger := app.Logger()
+	logger.Info().Str("_id", id).Msg("Creating remove finalizer")
+
+	type Config struct {
+		Headers []string `mapstructure:"headers" validate:"required_without=Cookies,dive,required"`
+		Cookies []string `mapstructure:"cookies" validate:"required_without=Headers,dive,required"`
+	}
+
+	var conf Config
+	if err := decodeConfig(app, rawConfig, &conf); err != nil {
+		return nil, errorchain.NewWithMessagef(heimdall.ErrConfiguration,
+			"failed decoding config for remove finalizer '%s'", id).CausedBy(err)
+	}
+
+	headers, err := compilePatterns(conf.Headers, strings.ToLower)
+	if err != nil {
+		return nil, errorchain.NewWithMessagef(heimdall.ErrConfiguration,
+			"failed compiling header patterns for remove finalizer '%s'", id).CausedBy(err)
+	}
+
+	cookies, err := compilePatterns(conf.Cookies, func(pattern string) string { return pattern })
+	if err != nil {
+		return nil, errorchain.NewWithMessagef(heimdall.ErrConfiguration,
+			"failed compiling cookie patterns for remove finalizer '%s'", id).CausedBy(err)
+	}
+
+	return &removeFinalizer{
+		id:      id,
+		app:     app,
+		headers: headers,
+		cookies: cookies,
+	}, nil
+}
+
+func (f *removeFinalizer) Execute(ctx heimdall.RequestContext, _ *subject.Subject) error {
+	logger := zerolog.Ctx(ctx.Context())
+	logger.Debug().Str("_id", f.id).Msg("Finalizing using remove finalizer")
+
+	req := ctx.Request()
+
+	if len(f.headers) != 0 {
+		for name := range req.Headers() {
+			if name != "Host" && matchesAny(f.headers, strings.ToLower(name)) {
+				ctx.RemoveHeaderForUpstream(name)
+			}
+		}
+	}
+
+	if len(f.cookies) != 0 {
+		for _, cookie := range strings.Split(req.Header("Cookie"), ";") {
+			name, _, ok := strings.Cut(cookie, "=")
+			if name = strings.TrimSpace(name); ok && matchesAny(f.cookies, name) {
+				ctx.RemoveCookieForUpstream(name)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (f *removeFinalizer) WithConfig(config map[string]any) (Finalizer, error) {
+	if len(config) == 0 {
+		return f, nil
+	}
+
+	return newRemoveFinalizer(f.app, f.id, config)
+}
+
+func (f *removeFinalizer) ID() string { return f.id }
+
+func (f *removeFinalizer) ContinueOnError() bool { return false }
+
+func compilePatterns(patterns []string, normalize func(string) string) ([]glob.Glob, error) {
+	compiled := make([]glob.Glob, len(patterns))
+
+	for idx, pattern := range patterns {
+		pg, err := glob.Compile(normalize(pattern))
+		if err != nil {
+			return nil, err
+		}
+
+		compiled[idx] = pg
+	}
+
+	return compiled, nil
+}
+
+func matchesAny(patterns []glob.Glob, value string) bool {
+	for _, pattern := range patterns {
+		if pattern.Match(value) {
+			return true
+		}
+	}
+
+	return false
+}
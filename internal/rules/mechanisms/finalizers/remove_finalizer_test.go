@@ -0,0 +1,278 @@
+// Copyright 2025 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package finalizers
+
+import (
+	"testing"
+
+	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dadrus/heimdall/internal/app"
+	"github.com/dadrus/heimdall/internal/heimdall"
+	"github.com/dadrus/heimdall/internal/heimdall/mocks"
+	"github.com/dadrus/heimdall/internal/validation"
+	"github.com/dadrus/heimdall/internal/x"
+	"github.com/dadrus/heimdall/internal/x/testsupport"
+)
+
+func TestCreateRemoveFinalizer(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		uc     string
+		id     string
+		config []byte
+		assert func(t *testing.T, err error, finalizer *removeFinalizer)
+	}{
+		{
+			uc: "without configuration",
+			assert: func(t *testing.T, err error, _ *removeFinalizer) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				assert.Contains(t, err.Error(), "'headers' is a required field")
+				assert.Contains(t, err.Error(), "'cookies' is a required field")
+			},
+		},
+		{
+			uc:     "with empty header name",
+			config: []byte(`headers: [""]`),
+			assert: func(t *testing.T, err error, _ *removeFinalizer) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				assert.Contains(t, err.Error(), "'headers'[0] is a required field")
+			},
+		},
+		{
+			uc: "with unsupported attributes",
+			config: []byte(`
+headers: [ foo ]
+foo: bar
+`),
+			assert: func(t *testing.T, err error, _ *removeFinalizer) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				assert.Contains(t, err.Error(), "failed decoding")
+			},
+		},
+		{
+			uc:     "with bad header pattern",
+			config: []byte(`headers: [ "X-[" ]`),
+			assert: func(t *testing.T, err error, _ *removeFinalizer) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				assert.Contains(t, err.Error(), "failed compiling header patterns")
+			},
+		},
+		{
+			uc:     "with bad cookie pattern",
+			config: []byte(`cookies: [ "[foo" ]`),
+			assert: func(t *testing.T, err error, _ *removeFinalizer) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				assert.Contains(t, err.Error(), "failed compiling cookie patterns")
+			},
+		},
+		{
+			uc: "with valid config",
+			id: "rem",
+			config: []byte(`
+headers: [ Authorization, "X-User-*" ]
+cookies: [ session ]
+`),
+			assert: func(t *testing.T, err error, finalizer *removeFinalizer) {
+				t.Helper()
+
+				require.NoError(t, err)
+				assert.Len(t, finalizer.headers, 2)
+				assert.Len(t, finalizer.cookies, 1)
+				assert.Equal(t, "rem", finalizer.ID())
+				assert.False(t, finalizer.ContinueOnError())
+			},
+		},
+	} {
+		t.Run("case="+tc.uc, func(t *testing.T) {
+			// GIVEN
+			conf, err := testsupport.DecodeTestConfig(tc.config)
+			require.NoError(t, err)
+
+			validator, err := validation.NewValidator()
+			require.NoError(t, err)
+
+			appCtx := app.NewContextMock(t)
+			appCtx.EXPECT().Validator().Maybe().Return(validator)
+			appCtx.EXPECT().Logger().Return(log.Logger)
+
+			// WHEN
+			finalizer, err := newRemoveFinalizer(appCtx, tc.id, conf)
+
+			// THEN
+			tc.assert(t, err, finalizer)
+		})
+	}
+}
+
+func TestCreateRemoveFinalizerFromPrototype(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		uc              string
+		prototypeConfig []byte
+		config          []byte
+		assert          func(t *testing.T, err error, prototype *removeFinalizer, configured *removeFinalizer)
+	}{
+		{
+			uc:              "no new configuration provided",
+			prototypeConfig: []byte(`headers: [ foo ]`),
+			assert: func(t *testing.T, err error, prototype *removeFinalizer, configured *removeFinalizer) {
+				t.Helper()
+
+				require.NoError(t, err)
+				assert.Equal(t, prototype, configured)
+			},
+		},
+		{
+			uc:              "new configuration provided",
+			prototypeConfig: []byte(`headers: [ foo ]`),
+			config:          []byte(`cookies: [ bar, baz ]`),
+			assert: func(t *testing.T, err error, prototype *removeFinalizer, configured *removeFinalizer) {
+				t.Helper()
+
+				require.NoError(t, err)
+				assert.NotEqual(t, prototype, configured)
+				assert.Equal(t, prototype.ID(), configured.ID())
+				assert.Empty(t, configured.headers)
+				assert.Len(t, configured.cookies, 2)
+			},
+		},
+	} {
+		t.Run("case="+tc.uc, func(t *testing.T) {
+			// GIVEN
+			pc, err := testsupport.DecodeTestConfig(tc.prototypeConfig)
+			require.NoError(t, err)
+
+			conf, err := testsupport.DecodeTestConfig(tc.config)
+			require.NoError(t, err)
+
+			validator, err := validation.NewValidator()
+			require.NoError(t, err)
+
+			appCtx := app.NewContextMock(t)
+			appCtx.EXPECT().Validator().Maybe().Return(validator)
+			appCtx.EXPECT().Logger().Return(log.Logger)
+
+			prototype, err := newRemoveFinalizer(appCtx, "rem", pc)
+			require.NoError(t, err)
+
+			// WHEN
+			finalizer, err := prototype.WithConfig(conf)
+
+			// THEN
+			configured, ok := finalizer.(*removeFinalizer)
+			require.True(t, ok)
+
+			tc.assert(t, err, prototype, configured)
+		})
+	}
+}
+
+func TestRemoveFinalizerExecute(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		uc               string
+		config           []byte
+		configureContext func(t *testing.T, ctx *mocks.RequestContextMock)
+	}{
+		{
+			uc:     "no matching headers and cookies present",
+			config: []byte(`{ headers: [ "X-User-*" ], cookies: [ session ] }`),
+			configureContext: func(t *testing.T, ctx *mocks.RequestContextMock) {
+				t.Helper()
+
+				reqf := mocks.NewRequestFunctionsMock(t)
+				reqf.EXPECT().Headers().Return(map[string]string{"Host": "foo.local", "Accept": "*/*"})
+				reqf.EXPECT().Header("Cookie").Return("")
+
+				ctx.EXPECT().Request().Return(&heimdall.Request{RequestFunctions: reqf})
+			},
+		},
+		{
+			uc:     "matching headers and cookies present",
+			config: []byte(`{ headers: [ authorization, "X-User-*", "*" ], cookies: [ "sess*", csrf ] }`),
+			configureContext: func(t *testing.T, ctx *mocks.RequestContextMock) {
+				t.Helper()
+
+				reqf := mocks.NewRequestFunctionsMock(t)
+				reqf.EXPECT().Headers().Return(map[string]string{
+					"Host":          "foo.local",
+					"Authorization": "Bearer foo",
+					"X-User-Id":     "bar",
+				})
+				reqf.EXPECT().Header("Cookie").Return("session=foo; CSRF=bar; session_id=baz")
+
+				ctx.EXPECT().Request().Return(&heimdall.Request{RequestFunctions: reqf})
+				ctx.EXPECT().RemoveHeaderForUpstream("Authorization")
+				ctx.EXPECT().RemoveHeaderForUpstream("X-User-Id")
+				ctx.EXPECT().RemoveCookieForUpstream("session")
+				ctx.EXPECT().RemoveCookieForUpstream("session_id")
+			},
+		},
+	} {
+		t.Run(tc.uc, func(t *testing.T) {
+			// GIVEN
+			configureContext := x.IfThenElse(tc.configureContext != nil,
+				tc.configureContext,
+				func(t *testing.T, _ *mocks.RequestContextMock) { t.Helper() })
+
+			conf, err := testsupport.DecodeTestConfig(tc.config)
+			require.NoError(t, err)
+
+			mctx := mocks.NewRequestContextMock(t)
+			mctx.EXPECT().Context().Return(t.Context())
+
+			configureContext(t, mctx)
+
+			validator, err := validation.NewValidator()
+			require.NoError(t, err)
+
+			appCtx := app.NewContextMock(t)
+			appCtx.EXPECT().Validator().Maybe().Return(validator)
+			appCtx.EXPECT().Logger().Return(log.Logger)
+
+			finalizer, err := newRemoveFinalizer(appCtx, "rem", conf)
+			require.NoError(t, err)
+
+			// WHEN
+			err = finalizer.Execute(mctx, nil)
+
+			// THEN
+			require.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,273 @@
+// Copyright 2025 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package finalizers
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/dadrus/heimdall/internal/app"
+	"github.com/dadrus/heimdall/internal/heimdall"
+	"github.com/dadrus/heimdall/internal/rules/mechanisms/authenticators/extractors"
+	"github.com/dadrus/heimdall/internal/rules/mechanisms/subject"
+	"github.com/dadrus/heimdall/internal/rules/mechanisms/template"
+	"github.com/dadrus/heimdall/internal/rules/oauth2/clientcredentials"
+	"github.com/dadrus/heimdall/internal/rules/oauth2/tokenexchange"
+	"github.com/dadrus/heimdall/internal/x"
+	"github.com/dadrus/heimdall/internal/x/errorchain"
+)
+
+// by intention. Used only during application bootstrap
+//
+//nolint:gochecknoinits
+func init() {
+	registerTypeFactory(
+		func(app app.Context, id string, typ string, conf map[string]any) (bool, Finalizer, error) {
+			if typ != FinalizerTokenExchange {
+				return false, nil, nil
+			}
+
+			finalizer, err := newTokenExchangeFinalizer(app, id, conf)
+
+			return true, finalizer, err
+		})
+}
+
+type tokenExchangeFinalizer struct {
+	id                 string
+	app                app.Context
+	cfg                tokenexchange.Config
+	ads                extractors.AuthDataExtractStrategy
+	subjectToken       template.Template
+	subjectTokenType   string
+	requestedTokenType string
+	audience           template.Template
+	resource           template.Template
+	scope              template.Template
+	headerName         string
+	headerScheme       string
+}
+
+func newTokenExchangeFinalizer(
+	app app.Context,
+	id string,
+	rawConfig map[string]any,
+) (*tokenExchangeFinalizer, error) {
+	logger := app.Logger()
+	logger.Info().Str("_id", id).Msg("Creating token_exchange finalizer")
+
+	type HeaderConfig struct {
+		Name   string `mapstructure:"name"   validate:"required"`
+		Scheme string `mapstructure:"scheme"`
+	}
+
+	type Config struct {
+		tokenexchange.Config `mapstructure:",squash"`
+
+		SubjectTokenSource extractors.CompositeExtractStrategy `mapstructure:"subject_token_source" validate:"excluded_with=SubjectToken"` //nolint:lll,tagalign
+		SubjectToken       template.Template                   `mapstructure:"subject_token"`
+		SubjectTokenType   string                              `mapstructure:"subject_token_type"`
+		RequestedTokenType string                              `mapstructure:"requested_token_type"`
+		Audience           template.Template                   `mapstructure:"audience"`
+		Resource           template.Template                   `mapstructure:"resource"`
+		Scope              template.Template                   `mapstructure:"scope"`
+		Header             *HeaderConfig                       `mapstructure:"header"`
+	}
+
+	var conf Config
+	if err := decodeConfig(app, rawConfig, &conf); err != nil {
+		return nil, errorchain.NewWithMessagef(heimdall.ErrConfiguration,
+			"failed decoding config for token_exchange finalizer '%s'", id).CausedBy(err)
+	}
+
+	if strings.HasPrefix(conf.TokenURL, "http://") {
+		logger.Warn().Str("_id", id).
+			Msg("No TLS configured for the token_url used in token_exchange finalizer")
+	}
+
+	conf.AuthMethod = x.IfThenElse(
+		len(conf.AuthMethod) == 0,
+		clientcredentials.AuthMethodBasicAuth,
+		conf.AuthMethod,
+	)
+
+	return &tokenExchangeFinalizer{
+		id:  id,
+		app: app,
+		cfg: conf.Config,
+		ads: x.IfThenElseExec(conf.SubjectTokenSource == nil,
+			func() extractors.CompositeExtractStrategy {
+				return extractors.CompositeExtractStrategy{
+					extractors.HeaderValueExtractStrategy{Name: "Authorization", Scheme: "Bearer"},
+				}
+			},
+			func() extractors.CompositeExtractStrategy { return conf.SubjectTokenSource },
+		),
+		subjectToken:       conf.SubjectToken,
+		subjectTokenType:   conf.SubjectTokenType,
+		requestedTokenType: conf.RequestedTokenType,
+		audience:           conf.Audience,
+		resource:           conf.Resource,
+		scope:              conf.Scope,
+		headerName: x.IfThenElseExec(conf.Header != nil,
+			func() string { return conf.Header.Name },
+			func() string { return "Authorization" }),
+		headerScheme: x.IfThenElseExec(conf.Header != nil,
+			func() string { return conf.Header.Scheme },
+			func() string { return "" }),
+	}, nil
+}
+
+func (f *tokenExchangeFinalizer) ContinueOnError() bool { return false }
+func (f *tokenExchangeFinalizer) ID() string            { return f.id }
+
+func (f *tokenExchangeFinalizer) WithConfig(rawConfig map[string]any) (Finalizer, error) {
+	if len(rawConfig) == 0 {
+		return f, nil
+	}
+
+	type HeaderConfig struct {
+		Name   string `mapstructure:"name"   validate:"required"`
+		Scheme string `mapstructure:"scheme"`
+	}
+
+	type Config struct {
+		Audience template.Template `mapstructure:"audience"`
+		Resource template.Template `mapstructure:"resource"`
+		Scope    template.Template `mapstructure:"scope"`
+		TTL      *time.Duration    `mapstructure:"cache_ttl"`
+		Header   *HeaderConfig     `mapstructure:"header"`
+	}
+
+	var conf Config
+	if err := decodeConfig(f.app, rawConfig, &conf); err != nil {
+		return nil, errorchain.NewWithMessagef(heimdall.ErrConfiguration,
+			"failed decoding config for token_exchange finalizer '%s'", f.id).CausedBy(err)
+	}
+
+	cfg := f.cfg
+	cfg.TTL = x.IfThenElse(conf.TTL != nil, conf.TTL, cfg.TTL)
+
+	return &tokenExchangeFinalizer{
+		id:                 f.id,
+		app:                f.app,
+		cfg:                cfg,
+		ads:                f.ads,
+		subjectToken:       f.subjectToken,
+		subjectTokenType:   f.subjectTokenType,
+		requestedTokenType: f.requestedTokenType,
+		audience:           x.IfThenElse(conf.Audience != nil, conf.Audience, f.audience),
+		resource:           x.IfThenElse(conf.Resource != nil, conf.Resource, f.resource),
+		scope:              x.IfThenElse(conf.Scope != nil, conf.Scope, f.scope),
+		headerName: x.IfThenElseExec(conf.Header != nil,
+			func() string { return conf.Header.Name },
+			func() string { return f.headerName }),
+		headerScheme: x.IfThenElseExec(conf.Header != nil,
+			func() string { return conf.Header.Scheme },
+			func() string { return f.headerScheme }),
+	}, nil
+}
+
+func (f *tokenExchangeFinalizer) Execute(ctx heimdall.RequestContext, sub *subject.Subject) error {
+	logger := zerolog.Ctx(ctx.Context())
+	logger.Debug().Str("_id", f.id).Msg("Finalizing using token_exchange finalizer")
+
+	tplData := map[string]any{
+		"Request": ctx.Request(),
+		"Subject": sub,
+		"Outputs": ctx.Outputs(),
+	}
+
+	subjectToken, err := f.getSubjectToken(ctx, tplData)
+	if err != nil {
+		return err
+	}
+
+	req := &tokenexchange.Request{
+		SubjectToken:       subjectToken,
+		SubjectTokenType:   f.subjectTokenType,
+		RequestedTokenType: f.requestedTokenType,
+	}
+
+	for name, entry := range map[string]struct {
+		tpl template.Template
+		dst *string
+	}{
+		"audience": {tpl: f.audience, dst: &req.Audience},
+		"resource": {tpl: f.resource, dst: &req.Resource},
+		"scope":    {tpl: f.scope, dst: &req.Scope},
+	} {
+		if entry.tpl == nil {
+			continue
+		}
+
+		value, err := entry.tpl.Render(tplData)
+		if err != nil {
+			return errorchain.NewWithMessagef(heimdall.ErrInternal, "failed to render %s", name).
+				WithErrorContext(f).
+				CausedBy(err)
+		}
+
+		*entry.dst = strings.TrimSpace(value)
+	}
+
+	token, err := f.cfg.Exchange(ctx.Context(), req)
+	if err != nil {
+		return err
+	}
+
+	headerScheme := x.IfThenElse(len(f.headerScheme) != 0, f.headerScheme, token.TokenType)
+	if headerScheme == "N_A" {
+		// the issued token is not an access token, so the token type is not applicable
+		headerScheme = "Bearer"
+	}
+
+	ctx.AddHeaderForUpstream(f.headerName, fmt.Sprintf("%s %s", headerScheme, token.AccessToken))
+
+	return nil
+}
+
+func (f *tokenExchangeFinalizer) getSubjectToken(ctx heimdall.RequestContext, tplData map[string]any) (string, error) {
+	if f.subjectToken == nil {
+		token, err := f.ads.GetAuthData(ctx)
+		if err != nil {
+			return "", errorchain.NewWithMessage(heimdall.ErrArgument, "no subject token present").
+				WithErrorContext(f).
+				CausedBy(err)
+		}
+
+		return token, nil
+	}
+
+	token, err := f.subjectToken.Render(tplData)
+	if err != nil {
+		return "", errorchain.NewWithMessage(heimdall.ErrInternal, "failed to render subject token").
+			WithErrorContext(f).
+			CausedBy(err)
+	}
+
+	token = strings.TrimSpace(token)
+	if len(token) == 0 {
+		return "", errorchain.NewWithMessage(heimdall.ErrArgument, "rendered subject token is empty").
+			WithErrorContext(f)
+	}
+
+	return token, nil
+}
@@ -0,0 +1,620 @@
+// Copyright 2025 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package finalizers
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dadrus/heimdall/internal/app"
+	"github.com/dadrus/heimdall/internal/cache"
+	mocks2 "github.com/dadrus/heimdall/internal/cache/mocks"
+	"github.com/dadrus/heimdall/internal/config"
+	"github.com/dadrus/heimdall/internal/heimdall"
+	"github.com/dadrus/heimdall/internal/heimdall/mocks"
+	"github.com/dadrus/heimdall/internal/rules/mechanisms/authenticators/extractors"
+	"github.com/dadrus/heimdall/internal/rules/mechanisms/subject"
+	"github.com/dadrus/heimdall/internal/rules/mechanisms/template"
+	"github.com/dadrus/heimdall/internal/rules/oauth2/clientcredentials"
+	"github.com/dadrus/heimdall/internal/rules/oauth2/tokenexchange"
+	"github.com/dadrus/heimdall/internal/validation"
+	"github.com/dadrus/heimdall/internal/x/testsupport"
+)
+
+func TestNewTokenExchangeFinalizer(t *testing.T) {
+	t.Parallel()
+
+	for uc, tc := range map[string]struct {
+		enforceTLS bool
+		config     []byte
+		assert     func(t *testing.T, err error, finalizer *tokenExchangeFinalizer)
+	}{
+		"without configuration": {
+			assert: func(t *testing.T, err error, _ *tokenExchangeFinalizer) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				require.ErrorContains(t, err, "'token_url' is a required field")
+				require.ErrorContains(t, err, "'client_id' is a required field")
+				require.ErrorContains(t, err, "'client_secret' is a required field")
+			},
+		},
+		"with unsupported properties": {
+			config: []byte(`
+token_url: https://foo.bar
+client_id: foo
+client_secret: bar
+foo: bar
+`),
+			assert: func(t *testing.T, err error, _ *tokenExchangeFinalizer) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				require.ErrorContains(t, err, "invalid keys: foo")
+			},
+		},
+		"with both, subject token source and subject token template": {
+			config: []byte(`
+token_url: https://foo.bar
+client_id: foo
+client_secret: bar
+subject_token: "{{ .Request.Header \"X-Token\" }}"
+subject_token_source:
+  - header: X-Token
+`),
+			assert: func(t *testing.T, err error, _ *tokenExchangeFinalizer) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				require.ErrorContains(t, err, "'subject_token_source' is an excluded field")
+			},
+		},
+		"with token url not using TLS and enforced TLS": {
+			enforceTLS: true,
+			config: []byte(`
+token_url: http://foo.bar
+client_id: foo
+client_secret: bar
+`),
+			assert: func(t *testing.T, err error, _ *tokenExchangeFinalizer) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				require.ErrorContains(t, err, "'token_url' scheme must be https")
+			},
+		},
+		"with minimal configuration": {
+			config: []byte(`
+token_url: https://foo.bar
+client_id: foo
+client_secret: bar
+`),
+			assert: func(t *testing.T, err error, finalizer *tokenExchangeFinalizer) {
+				t.Helper()
+
+				require.NoError(t, err)
+				require.NotNil(t, finalizer)
+
+				assert.Equal(t, "fin", finalizer.ID())
+				assert.Equal(t, "https://foo.bar", finalizer.cfg.TokenURL)
+				assert.Equal(t, "foo", finalizer.cfg.ClientID)
+				assert.Equal(t, "bar", finalizer.cfg.ClientSecret)
+				assert.Equal(t, clientcredentials.AuthMethodBasicAuth, finalizer.cfg.AuthMethod)
+				assert.Nil(t, finalizer.cfg.TTL)
+				assert.Equal(t, extractors.CompositeExtractStrategy{
+					extractors.HeaderValueExtractStrategy{Name: "Authorization", Scheme: "Bearer"},
+				}, finalizer.ads)
+				assert.Nil(t, finalizer.subjectToken)
+				assert.Empty(t, finalizer.subjectTokenType)
+				assert.Empty(t, finalizer.requestedTokenType)
+				assert.Nil(t, finalizer.audience)
+				assert.Nil(t, finalizer.resource)
+				assert.Nil(t, finalizer.scope)
+				assert.Equal(t, "Authorization", finalizer.headerName)
+				assert.Empty(t, finalizer.headerScheme)
+				assert.False(t, finalizer.ContinueOnError())
+			},
+		},
+		"with full configuration": {
+			config: []byte(`
+token_url: https://foo.bar
+client_id: foo
+client_secret: bar
+auth_method: request_body
+cache_ttl: 11s
+subject_token: "{{ .Outputs.token }}"
+subject_token_type: urn:ietf:params:oauth:token-type:jwt
+requested_token_type: urn:ietf:params:oauth:token-type:access_token
+audience: upstream
+resource: "https://{{ .Request.URL.Host }}"
+scope: read write
+header:
+  name: X-Token
+  scheme: Baz
+`),
+			assert: func(t *testing.T, err error, finalizer *tokenExchangeFinalizer) {
+				t.Helper()
+
+				require.NoError(t, err)
+				require.NotNil(t, finalizer)
+
+				assert.Equal(t, clientcredentials.AuthMethodRequestBody, finalizer.cfg.AuthMethod)
+				assert.Equal(t, 11*time.Second, *finalizer.cfg.TTL)
+				assert.NotNil(t, finalizer.subjectToken)
+				assert.Equal(t, "urn:ietf:params:oauth:token-type:jwt", finalizer.subjectTokenType)
+				assert.Equal(t, tokenexchange.TokenTypeAccessToken, finalizer.requestedTokenType)
+				assert.NotNil(t, finalizer.audience)
+				assert.NotNil(t, finalizer.resource)
+				assert.NotNil(t, finalizer.scope)
+				assert.Equal(t, "X-Token", finalizer.headerName)
+				assert.Equal(t, "Baz", finalizer.headerScheme)
+			},
+		},
+	} {
+		t.Run(uc, func(t *testing.T) {
+			// GIVEN
+			conf, err := testsupport.DecodeTestConfig(tc.config)
+			require.NoError(t, err)
+
+			es := config.EnforcementSettings{EnforceEgressTLS: tc.enforceTLS}
+			validator, err := validation.NewValidator(
+				validation.WithTagValidator(es),
+				validation.WithErrorTranslator(es),
+			)
+			require.NoError(t, err)
+
+			appCtx := app.NewContextMock(t)
+			appCtx.EXPECT().Validator().Maybe().Return(validator)
+			appCtx.EXPECT().Logger().Return(log.Logger)
+
+			// WHEN
+			finalizer, err := newTokenExchangeFinalizer(appCtx, "fin", conf)
+
+			// THEN
+			tc.assert(t, err, finalizer)
+		})
+	}
+}
+
+func TestCreateTokenExchangeFinalizerFromPrototype(t *testing.T) {
+	t.Parallel()
+
+	for uc, tc := range map[string]struct {
+		config []byte
+		assert func(t *testing.T, err error, prototype, configured *tokenExchangeFinalizer)
+	}{
+		"without new configuration": {
+			assert: func(t *testing.T, err error, prototype, configured *tokenExchangeFinalizer) {
+				t.Helper()
+
+				require.NoError(t, err)
+				assert.Equal(t, prototype, configured)
+			},
+		},
+		"with not overridable property": {
+			config: []byte(`client_id: baz`),
+			assert: func(t *testing.T, err error, _, _ *tokenExchangeFinalizer) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				require.ErrorContains(t, err, "invalid keys: client_id")
+			},
+		},
+		"with overridden audience, cache ttl and header": {
+			config: []byte(`
+audience: other
+cache_ttl: 1m
+header:
+  name: X-Other
+`),
+			assert: func(t *testing.T, err error, prototype, configured *tokenExchangeFinalizer) {
+				t.Helper()
+
+				require.NoError(t, err)
+				require.NotNil(t, configured)
+				assert.NotEqual(t, prototype, configured)
+
+				assert.Equal(t, prototype.ID(), configured.ID())
+				assert.Equal(t, prototype.cfg.TokenURL, configured.cfg.TokenURL)
+				assert.Equal(t, prototype.cfg.ClientID, configured.cfg.ClientID)
+				assert.Equal(t, time.Minute, *configured.cfg.TTL)
+				assert.Equal(t, 10*time.Second, *prototype.cfg.TTL)
+				assert.Equal(t, prototype.ads, configured.ads)
+				assert.Equal(t, prototype.resource, configured.resource)
+				assert.Equal(t, prototype.scope, configured.scope)
+				assert.NotEqual(t, prototype.audience, configured.audience)
+				assert.Equal(t, "X-Other", configured.headerName)
+				assert.Empty(t, configured.headerScheme)
+			},
+		},
+	} {
+		t.Run(uc, func(t *testing.T) {
+			// GIVEN
+			protoConf, err := testsupport.DecodeTestConfig([]byte(`
+token_url: https://foo.bar
+client_id: foo
+client_secret: bar
+cache_ttl: 10s
+audience: upstream
+resource: https://upstream.local
+scope: read
+header:
+  name: X-Token
+  scheme: Bearer
+`))
+			require.NoError(t, err)
+
+			conf, err := testsupport.DecodeTestConfig(tc.config)
+			require.NoError(t, err)
+
+			es := config.EnforcementSettings{}
+			validator, err := validation.NewValidator(
+				validation.WithTagValidator(es),
+				validation.WithErrorTranslator(es),
+			)
+			require.NoError(t, err)
+
+			appCtx := app.NewContextMock(t)
+			appCtx.EXPECT().Validator().Maybe().Return(validator)
+			appCtx.EXPECT().Logger().Return(log.Logger)
+
+			prototype, err := newTokenExchangeFinalizer(appCtx, "fin", protoConf)
+			require.NoError(t, err)
+
+			// WHEN
+			finalizer, err := prototype.WithConfig(conf)
+
+			// THEN
+			var (
+				ok            bool
+				realFinalizer *tokenExchangeFinalizer
+			)
+
+			if err == nil {
+				realFinalizer, ok = finalizer.(*tokenExchangeFinalizer)
+				require.True(t, ok)
+			}
+
+			tc.assert(t, err, prototype, realFinalizer)
+		})
+	}
+}
+
+func TestTokenExchangeFinalizerExecute(t *testing.T) {
+	t.Parallel()
+
+	type (
+		RequestAsserter func(t *testing.T, req *http.Request)
+		ResponseBuilder func(t *testing.T) (any, int)
+	)
+
+	var (
+		endpointCalled bool
+		assertRequest  RequestAsserter
+		buildResponse  ResponseBuilder
+	)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		endpointCalled = true
+
+		if err := req.ParseForm(); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+
+			return
+		}
+
+		assertRequest(t, req)
+
+		resp, code := buildResponse(t)
+
+		rawResp, err := json.MarshalContext(req.Context(), resp)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Content-Length", strconv.Itoa(len(rawResp)))
+
+		w.WriteHeader(code)
+		_, err = w.Write(rawResp)
+		assert.NoError(t, err)
+	}))
+	defer srv.Close()
+
+	mustTemplate := func(t *testing.T, val string) template.Template {
+		t.Helper()
+
+		tpl, err := template.New(val)
+		require.NoError(t, err)
+
+		return tpl
+	}
+
+	cfg := tokenexchange.Config{
+		TokenURL:     srv.URL,
+		ClientID:     "bar",
+		ClientSecret: "foo",
+		AuthMethod:   clientcredentials.AuthMethodBasicAuth,
+	}
+
+	defaultSource := extractors.CompositeExtractStrategy{
+		extractors.HeaderValueExtractStrategy{Name: "Authorization", Scheme: "Bearer"},
+	}
+
+	for uc, tc := range map[string]struct {
+		finalizer      func(t *testing.T) *tokenExchangeFinalizer
+		configureMocks func(t *testing.T, ctx *mocks.RequestContextMock, cch *mocks2.CacheMock)
+		assertRequest  RequestAsserter
+		buildResponse  ResponseBuilder
+		assert         func(t *testing.T, err error, tokenEndpointCalled bool)
+	}{
+		"no subject token present in the request": {
+			finalizer: func(t *testing.T) *tokenExchangeFinalizer {
+				t.Helper()
+
+				return &tokenExchangeFinalizer{id: "test", cfg: cfg, ads: defaultSource}
+			},
+			configureMocks: func(t *testing.T, ctx *mocks.RequestContextMock, _ *mocks2.CacheMock) {
+				t.Helper()
+
+				reqf := mocks.NewRequestFunctionsMock(t)
+				reqf.EXPECT().Header("Authorization").Return("")
+
+				ctx.EXPECT().Request().Return(&heimdall.Request{RequestFunctions: reqf})
+			},
+			assert: func(t *testing.T, err error, tokenEndpointCalled bool) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrArgument)
+				require.ErrorContains(t, err, "no subject token present")
+				assert.False(t, tokenEndpointCalled)
+			},
+		},
+		"rendered subject token is empty": {
+			finalizer: func(t *testing.T) *tokenExchangeFinalizer {
+				t.Helper()
+
+				return &tokenExchangeFinalizer{
+					id: "test", cfg: cfg, ads: defaultSource,
+					subjectToken: mustTemplate(t, `{{ .Request.Header "X-Token" }}`),
+				}
+			},
+			configureMocks: func(t *testing.T, ctx *mocks.RequestContextMock, _ *mocks2.CacheMock) {
+				t.Helper()
+
+				reqf := mocks.NewRequestFunctionsMock(t)
+				reqf.EXPECT().Header("X-Token").Return("")
+
+				ctx.EXPECT().Request().Return(&heimdall.Request{RequestFunctions: reqf})
+			},
+			assert: func(t *testing.T, err error, tokenEndpointCalled bool) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrArgument)
+				require.ErrorContains(t, err, "rendered subject token is empty")
+				assert.False(t, tokenEndpointCalled)
+			},
+		},
+		"reusing exchanged token from cache": {
+			finalizer: func(t *testing.T) *tokenExchangeFinalizer {
+				t.Helper()
+
+				return &tokenExchangeFinalizer{id: "test", cfg: cfg, ads: defaultSource, headerName: "Authorization"}
+			},
+			configureMocks: func(t *testing.T, ctx *mocks.RequestContextMock, cch *mocks2.CacheMock) {
+				t.Helper()
+
+				reqf := mocks.NewRequestFunctionsMock(t)
+				reqf.EXPECT().Header("Authorization").Return("Bearer subject-token")
+
+				ctx.EXPECT().Request().Return(&heimdall.Request{RequestFunctions: reqf})
+
+				rawData, err := json.Marshal(clientcredentials.TokenInfo{AccessToken: "foobar", TokenType: "Bearer"})
+				require.NoError(t, err)
+
+				cch.EXPECT().Get(mock.Anything, mock.Anything).Return(rawData, nil)
+				ctx.EXPECT().AddHeaderForUpstream("Authorization", "Bearer foobar")
+			},
+			assert: func(t *testing.T, err error, tokenEndpointCalled bool) {
+				t.Helper()
+
+				require.NoError(t, err)
+				assert.False(t, tokenEndpointCalled)
+			},
+		},
+		"exchanging subject token from the request": {
+			finalizer: func(t *testing.T) *tokenExchangeFinalizer {
+				t.Helper()
+
+				return &tokenExchangeFinalizer{
+					id:         "test",
+					cfg:        cfg,
+					ads:        defaultSource,
+					audience:   mustTemplate(t, "{{ .Subject.ID }}-service"),
+					resource:   mustTemplate(t, "https://{{ .Request.URL.Host }}/api"),
+					scope:      mustTemplate(t, "{{ .Outputs.scope }}"),
+					headerName: "Authorization",
+				}
+			},
+			configureMocks: func(t *testing.T, ctx *mocks.RequestContextMock, cch *mocks2.CacheMock) {
+				t.Helper()
+
+				reqf := mocks.NewRequestFunctionsMock(t)
+				reqf.EXPECT().Header("Authorization").Return("Bearer subject-token")
+
+				ctx.EXPECT().Request().Return(&heimdall.Request{
+					RequestFunctions: reqf,
+					URL:              &heimdall.URL{URL: url.URL{Scheme: "https", Host: "upstream.local", Path: "/foo"}},
+				})
+				ctx.EXPECT().Outputs().Return(map[string]any{"scope": "read"})
+
+				cch.EXPECT().Get(mock.Anything, mock.Anything).Return(nil, errors.New("no cache entry"))
+				cch.EXPECT().Set(mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+				ctx.EXPECT().AddHeaderForUpstream("Authorization", "Bearer exchanged")
+			},
+			assertRequest: func(t *testing.T, req *http.Request) {
+				t.Helper()
+
+				clientID, clientSecret, ok := req.BasicAuth()
+				require.True(t, ok)
+				assert.Equal(t, "bar", clientID)
+				assert.Equal(t, "foo", clientSecret)
+
+				assert.Equal(t, tokenexchange.GrantType, req.FormValue("grant_type"))
+				assert.Equal(t, "subject-token", req.FormValue("subject_token"))
+				assert.Equal(t, tokenexchange.TokenTypeAccessToken, req.FormValue("subject_token_type"))
+				assert.Equal(t, "alice-service", req.FormValue("audience"))
+				assert.Equal(t, "https://upstream.local/api", req.FormValue("resource"))
+				assert.Equal(t, "read", req.FormValue("scope"))
+			},
+			buildResponse: func(t *testing.T) (any, int) {
+				t.Helper()
+
+				return map[string]any{
+					"access_token":      "exchanged",
+					"issued_token_type": tokenexchange.TokenTypeAccessToken,
+					"token_type":        "Bearer",
+					"expires_in":        300,
+				}, http.StatusOK
+			},
+			assert: func(t *testing.T, err error, tokenEndpointCalled bool) {
+				t.Helper()
+
+				require.NoError(t, err)
+				assert.True(t, tokenEndpointCalled)
+			},
+		},
+		"exchanging templated subject token into a custom header": {
+			finalizer: func(t *testing.T) *tokenExchangeFinalizer {
+				t.Helper()
+
+				return &tokenExchangeFinalizer{
+					id:                 "test",
+					cfg:                cfg,
+					ads:                defaultSource,
+					subjectToken:       mustTemplate(t, "{{ .Outputs.token }}"),
+					subjectTokenType:   "urn:ietf:params:oauth:token-type:jwt",
+					requestedTokenType: "urn:ietf:params:oauth:token-type:jwt",
+					headerName:         "X-Token",
+				}
+			},
+			configureMocks: func(t *testing.T, ctx *mocks.RequestContextMock, cch *mocks2.CacheMock) {
+				t.Helper()
+
+				ctx.EXPECT().Request().Return(&heimdall.Request{})
+				ctx.EXPECT().Outputs().Return(map[string]any{"token": "templated-token"})
+
+				cch.EXPECT().Get(mock.Anything, mock.Anything).Return(nil, errors.New("no cache entry"))
+				ctx.EXPECT().AddHeaderForUpstream("X-Token", "Bearer exchanged")
+			},
+			assertRequest: func(t *testing.T, req *http.Request) {
+				t.Helper()
+
+				assert.Equal(t, "templated-token", req.FormValue("subject_token"))
+				assert.Equal(t, "urn:ietf:params:oauth:token-type:jwt", req.FormValue("subject_token_type"))
+				assert.Equal(t, "urn:ietf:params:oauth:token-type:jwt", req.FormValue("requested_token_type"))
+				assert.Empty(t, req.FormValue("audience"))
+			},
+			buildResponse: func(t *testing.T) (any, int) {
+				t.Helper()
+
+				return map[string]any{
+					"access_token":      "exchanged",
+					"issued_token_type": "urn:ietf:params:oauth:token-type:jwt",
+					"token_type":        "N_A",
+				}, http.StatusOK
+			},
+			assert: func(t *testing.T, err error, tokenEndpointCalled bool) {
+				t.Helper()
+
+				require.NoError(t, err)
+				assert.True(t, tokenEndpointCalled)
+			},
+		},
+		"token exchange fails": {
+			finalizer: func(t *testing.T) *tokenExchangeFinalizer {
+				t.Helper()
+
+				return &tokenExchangeFinalizer{id: "test", cfg: cfg, ads: defaultSource, headerName: "Authorization"}
+			},
+			configureMocks: func(t *testing.T, ctx *mocks.RequestContextMock, cch *mocks2.CacheMock) {
+				t.Helper()
+
+				reqf := mocks.NewRequestFunctionsMock(t)
+				reqf.EXPECT().Header("Authorization").Return("Bearer subject-token")
+
+				ctx.EXPECT().Request().Return(&heimdall.Request{RequestFunctions: reqf})
+
+				cch.EXPECT().Get(mock.Anything, mock.Anything).Return(nil, errors.New("no cache entry"))
+			},
+			assertRequest: func(t *testing.T, _ *http.Request) { t.Helper() },
+			buildResponse: func(t *testing.T) (any, int) {
+				t.Helper()
+
+				return &clientcredentials.TokenErrorResponse{ErrorType: "invalid_target"}, http.StatusBadRequest
+			},
+			assert: func(t *testing.T, err error, tokenEndpointCalled bool) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrCommunication)
+				require.ErrorContains(t, err, "invalid_target")
+				assert.True(t, tokenEndpointCalled)
+			},
+		},
+	} {
+		t.Run(uc, func(t *testing.T) {
+			endpointCalled = false
+
+			cch := mocks2.NewCacheMock(t)
+			ctx := mocks.NewRequestContextMock(t)
+
+			ctx.EXPECT().Context().Return(cache.WithContext(t.Context(), cch))
+			tc.configureMocks(t, ctx, cch)
+			ctx.EXPECT().Outputs().Maybe().Return(map[string]any{})
+
+			assertRequest = tc.assertRequest
+			buildResponse = tc.buildResponse
+
+			// WHEN
+			err := tc.finalizer(t).Execute(ctx, &subject.Subject{ID: "alice"})
+
+			// THEN
+			tc.assert(t, err, endpointCalled)
+		})
+	}
+}
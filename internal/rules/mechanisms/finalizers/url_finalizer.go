@@ -0,0 +1,166 @@
+// Copyright 2025 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package finalizers
+
+import (
+	"github.com/rs/zerolog"
+
+	"github.com/dadrus/heimdall/internal/app"
+	"github.com/dadrus/heimdall/internal/heimdall"
+	"github.com/dadrus/heimdall/internal/rules/mechanisms/subject"
+	"github.com/dadrus/heimdall/internal/rules/mechanisms/template"
+	"github.com/dadrus/heimdall/internal/x/errorchain"
+)
+
+// by intention. Used only during application bootstrap
+//
+//nolint:gochecknoinits
+func init() {
+	registerTypeFactory(
+		func(app app.Context, id string, typ string, conf map[string]any) (bool, Finalizer, error) {
+			if typ != FinalizerURL {
+				return false, nil, nil
+			}
+
+			finalizer, err := newURLFinalizer(app, id, conf)
+
+			return true, finalizer, err
+		})
+}
+
+type PathConfig struct {
+	Value  template.Template `mapstructure:"value"  validate:"required_without=Prefix"`
+	Prefix template.Template `mapstructure:"prefix" validate:"required_without=Value"`
+}
+
+type QueryConfig struct {
+	Set    map[string]template.Template `mapstructure:"set"    validate:"required_without=Remove"`
+	Remove []string                     `mapstructure:"remove" validate:"required_without=Set,dive,required"`
+}
+
+// urlFinalizer modifies the path and the query of the url the request is forwarded to.
+type urlFinalizer struct {
+	id    string
+	app   app.Context
+	path  *PathConfig
+	query *QueryConfig
+}
+
+func newURLFinalizer(app app.Context, id string, rawConfig map[string]any) (*urlFinalizer, error) {
+	logger := app.Logger()
+	logger.Info().Str("_id", id).Msg("Creating url finalizer")
+
+	type Config struct {
+		Path  *PathConfig  `mapstructure:"path"  validate:"required_without=Query"`
+		Query *QueryConfig `mapstructure:"query" validate:"required_without=Path"`
+	}
+
+	var conf Config
+	if err := decodeConfig(app, rawConfig, &conf); err != nil {
+		return nil, errorchain.NewWithMessagef(heimdall.ErrConfiguration,
+			"failed decoding config for url finalizer '%s'", id).CausedBy(err)
+	}
+
+	return &urlFinalizer{
+		id:    id,
+		app:   app,
+		path:  conf.Path,
+		query: conf.Query,
+	}, nil
+}
+
+func (f *urlFinalizer) Execute(ctx heimdall.RequestContext, sub *subject.Subject) error {
+	logger := zerolog.Ctx(ctx.Context())
+	logger.Debug().Str("_id", f.id).Msg("Finalizing using url finalizer")
+
+	if sub == nil {
+		return errorchain.
+			NewWithMessage(heimdall.ErrInternal, "failed to execute url finalizer due to 'nil' subject").
+			WithErrorContext(f)
+	}
+
+	data := map[string]any{
+		"Request": ctx.Request(),
+		"Subject": sub,
+		"Outputs": ctx.Outputs(),
+	}
+
+	if f.query != nil {
+		for _, name := range f.query.Remove {
+			ctx.RemoveQueryParameterForUpstream(name)
+		}
+
+		for name, tmpl := range f.query.Set {
+			value, err := f.render(tmpl, data, "'"+name+"' query parameter")
+			if err != nil {
+				return err
+			}
+
+			ctx.SetQueryParameterForUpstream(name, value)
+		}
+	}
+
+	if f.path != nil {
+		if f.path.Value != nil {
+			value, err := f.render(f.path.Value, data, "path")
+			if err != nil {
+				return err
+			}
+
+			if len(value) != 0 {
+				ctx.SetPathForUpstream(value)
+			}
+		}
+
+		if f.path.Prefix != nil {
+			value, err := f.render(f.path.Prefix, data, "path prefix")
+			if err != nil {
+				return err
+			}
+
+			if len(value) != 0 {
+				ctx.AddPathPrefixForUpstream(value)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (f *urlFinalizer) render(tmpl template.Template, data map[string]any, what string) (string, error) {
+	value, err := tmpl.Render(data)
+	if err != nil {
+		return "", errorchain.
+			NewWithMessagef(heimdall.ErrInternal, "failed to render value for the %s", what).
+			WithErrorContext(f).
+			CausedBy(err)
+	}
+
+	return value, nil
+}
+
+func (f *urlFinalizer) WithConfig(config map[string]any) (Finalizer, error) {
+	if len(config) == 0 {
+		return f, nil
+	}
+
+	return newURLFinalizer(f.app, f.id, config)
+}
+
+func (f *urlFinalizer) ID() string { return f.id }
+
+func (f *urlFinalizer) ContinueOnError() bool { return false }
@@ -0,0 +1,315 @@
+// Copyright 2025 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package finalizers
+
+import (
+	"testing"
+
+	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dadrus/heimdall/internal/app"
+	"github.com/dadrus/heimdall/internal/heimdall"
+	"github.com/dadrus/heimdall/internal/heimdall/mocks"
+	"github.com/dadrus/heimdall/internal/rules/mechanisms/subject"
+	"github.com/dadrus/heimdall/internal/validation"
+	"github.com/dadrus/heimdall/internal/x"
+	"github.com/dadrus/heimdall/internal/x/testsupport"
+)
+
+func TestCreateURLFinalizer(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		uc     string
+		config []byte
+		assert func(t *testing.T, err error, finalizer *urlFinalizer)
+	}{
+		{
+			uc: "without configuration",
+			assert: func(t *testing.T, err error, _ *urlFinalizer) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				assert.Contains(t, err.Error(), "'path' is a required field")
+				assert.Contains(t, err.Error(), "'query' is a required field")
+			},
+		},
+		{
+			uc:     "with empty path configuration",
+			config: []byte(`path: {}`),
+			assert: func(t *testing.T, err error, _ *urlFinalizer) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				assert.Contains(t, err.Error(), "'path'.'value' is a required field")
+			},
+		},
+		{
+			uc:     "with empty query configuration",
+			config: []byte(`query: {}`),
+			assert: func(t *testing.T, err error, _ *urlFinalizer) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				assert.Contains(t, err.Error(), "'query'.'set' is a required field")
+			},
+		},
+		{
+			uc:     "with bad template",
+			config: []byte(`path: { prefix: "{{ .Subject.ID | foobar }}" }`),
+			assert: func(t *testing.T, err error, _ *urlFinalizer) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				assert.Contains(t, err.Error(), "failed decoding")
+			},
+		},
+		{
+			uc: "with valid config",
+			config: []byte(`
+path:
+  prefix: "/tenants/{{ .Subject.Attributes.tenant }}"
+query:
+  set:
+    user: "{{ .Subject.ID }}"
+  remove: [ debug ]
+`),
+			assert: func(t *testing.T, err error, finalizer *urlFinalizer) {
+				t.Helper()
+
+				require.NoError(t, err)
+				assert.Equal(t, "url", finalizer.ID())
+				require.NotNil(t, finalizer.path)
+				assert.Nil(t, finalizer.path.Value)
+				assert.NotNil(t, finalizer.path.Prefix)
+				require.NotNil(t, finalizer.query)
+				assert.Len(t, finalizer.query.Set, 1)
+				assert.Equal(t, []string{"debug"}, finalizer.query.Remove)
+				assert.False(t, finalizer.ContinueOnError())
+			},
+		},
+	} {
+		t.Run("case="+tc.uc, func(t *testing.T) {
+			// GIVEN
+			conf, err := testsupport.DecodeTestConfig(tc.config)
+			require.NoError(t, err)
+
+			validator, err := validation.NewValidator()
+			require.NoError(t, err)
+
+			appCtx := app.NewContextMock(t)
+			appCtx.EXPECT().Validator().Maybe().Return(validator)
+			appCtx.EXPECT().Logger().Return(log.Logger)
+
+			// WHEN
+			finalizer, err := newURLFinalizer(appCtx, "url", conf)
+
+			// THEN
+			tc.assert(t, err, finalizer)
+		})
+	}
+}
+
+func TestCreateURLFinalizerFromPrototype(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		uc     string
+		config []byte
+		assert func(t *testing.T, err error, prototype *urlFinalizer, configured *urlFinalizer)
+	}{
+		{
+			uc: "no new configuration provided",
+			assert: func(t *testing.T, err error, prototype *urlFinalizer, configured *urlFinalizer) {
+				t.Helper()
+
+				require.NoError(t, err)
+				assert.Equal(t, prototype, configured)
+			},
+		},
+		{
+			uc:     "new configuration provided",
+			config: []byte(`query: { remove: [ foo ] }`),
+			assert: func(t *testing.T, err error, prototype *urlFinalizer, configured *urlFinalizer) {
+				t.Helper()
+
+				require.NoError(t, err)
+				assert.NotEqual(t, prototype, configured)
+				assert.Equal(t, prototype.ID(), configured.ID())
+				assert.Nil(t, configured.path)
+				require.NotNil(t, configured.query)
+				assert.Equal(t, []string{"foo"}, configured.query.Remove)
+			},
+		},
+	} {
+		t.Run("case="+tc.uc, func(t *testing.T) {
+			// GIVEN
+			pc, err := testsupport.DecodeTestConfig([]byte(`path: { value: "/foo" }`))
+			require.NoError(t, err)
+
+			conf, err := testsupport.DecodeTestConfig(tc.config)
+			require.NoError(t, err)
+
+			validator, err := validation.NewValidator()
+			require.NoError(t, err)
+
+			appCtx := app.NewContextMock(t)
+			appCtx.EXPECT().Validator().Maybe().Return(validator)
+			appCtx.EXPECT().Logger().Return(log.Logger)
+
+			prototype, err := newURLFinalizer(appCtx, "url", pc)
+			require.NoError(t, err)
+
+			// WHEN
+			finalizer, err := prototype.WithConfig(conf)
+
+			// THEN
+			configured, ok := finalizer.(*urlFinalizer)
+			require.True(t, ok)
+
+			tc.assert(t, err, prototype, configured)
+		})
+	}
+}
+
+func TestURLFinalizerExecute(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		uc               string
+		config           []byte
+		subject          *subject.Subject
+		configureContext func(t *testing.T, ctx *mocks.RequestContextMock)
+		assert           func(t *testing.T, err error)
+	}{
+		{
+			uc:     "with nil subject",
+			config: []byte(`path: { value: "/foo" }`),
+			assert: func(t *testing.T, err error) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrInternal)
+				assert.Contains(t, err.Error(), "'nil' subject")
+
+				var identifier interface{ ID() string }
+				require.ErrorAs(t, err, &identifier)
+				assert.Equal(t, "url", identifier.ID())
+			},
+		},
+		{
+			uc:      "with template rendering error",
+			config:  []byte(`query: { set: { foo: "{{ len .Subject.ID.foo }}" } }`),
+			subject: &subject.Subject{ID: "bar"},
+			configureContext: func(t *testing.T, ctx *mocks.RequestContextMock) {
+				t.Helper()
+
+				ctx.EXPECT().Request().Return(&heimdall.Request{})
+				ctx.EXPECT().Outputs().Return(map[string]any{})
+			},
+			assert: func(t *testing.T, err error) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrInternal)
+				assert.Contains(t, err.Error(), "'foo' query parameter")
+			},
+		},
+		{
+			uc: "with all modifications configured",
+			config: []byte(`
+path:
+  value: "/users/{{ .Subject.ID }}"
+  prefix: "/{{ .Outputs.tenant }}"
+query:
+  set:
+    user: "{{ .Subject.ID }}"
+  remove: [ debug, trace ]
+`),
+			subject: &subject.Subject{ID: "bar"},
+			configureContext: func(t *testing.T, ctx *mocks.RequestContextMock) {
+				t.Helper()
+
+				ctx.EXPECT().Request().Return(&heimdall.Request{})
+				ctx.EXPECT().Outputs().Return(map[string]any{"tenant": "foo"})
+				ctx.EXPECT().RemoveQueryParameterForUpstream("debug")
+				ctx.EXPECT().RemoveQueryParameterForUpstream("trace")
+				ctx.EXPECT().SetQueryParameterForUpstream("user", "bar")
+				ctx.EXPECT().SetPathForUpstream("/users/bar")
+				ctx.EXPECT().AddPathPrefixForUpstream("/foo")
+			},
+			assert: func(t *testing.T, err error) {
+				t.Helper()
+
+				require.NoError(t, err)
+			},
+		},
+		{
+			uc:      "with empty rendered path prefix",
+			config:  []byte(`path: { prefix: "{{ .Outputs.tenant }}" }`),
+			subject: &subject.Subject{ID: "bar"},
+			configureContext: func(t *testing.T, ctx *mocks.RequestContextMock) {
+				t.Helper()
+
+				ctx.EXPECT().Request().Return(&heimdall.Request{})
+				ctx.EXPECT().Outputs().Return(map[string]any{"tenant": ""})
+			},
+			assert: func(t *testing.T, err error) {
+				t.Helper()
+
+				require.NoError(t, err)
+			},
+		},
+	} {
+		t.Run(tc.uc, func(t *testing.T) {
+			// GIVEN
+			configureContext := x.IfThenElse(tc.configureContext != nil,
+				tc.configureContext,
+				func(t *testing.T, _ *mocks.RequestContextMock) { t.Helper() })
+
+			conf, err := testsupport.DecodeTestConfig(tc.config)
+			require.NoError(t, err)
+
+			mctx := mocks.NewRequestContextMock(t)
+			mctx.EXPECT().Context().Return(t.Context())
+
+			configureContext(t, mctx)
+
+			validator, err := validation.NewValidator()
+			require.NoError(t, err)
+
+			appCtx := app.NewContextMock(t)
+			appCtx.EXPECT().Validator().Maybe().Return(validator)
+			appCtx.EXPECT().Logger().Return(log.Logger)
+
+			finalizer, err := newURLFinalizer(appCtx, "url", conf)
+			require.NoError(t, err)
+
+			// WHEN
+			err = finalizer.Execute(mctx, tc.subject)
+
+			// THEN
+			tc.assert(t, err)
+		})
+	}
+}
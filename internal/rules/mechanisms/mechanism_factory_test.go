@@ -504,6 +504,9 @@ func TestHandlerFactoryCreateErrorHandler(t *testing.T) {
 func TestCreateHandlerFactory(t *testing.T) {
 	t.Parallel()
 
+	trueValue := true
+	falseValue := false
+
 	for _, tc := range []struct {
 		uc     string
 		conf   *config.Configuration
@@ -525,6 +528,94 @@ func TestCreateHandlerFactory(t *testing.T) {
 				assert.Empty(t, factory.r.authorizers)
 			},
 		},
+		{
+			uc: "successful with not enforced authorizer",
+			conf: &config.Configuration{
+				Prototypes: &config.MechanismPrototypes{
+					Authorizers: []config.Mechanism{
+						{ID: "foo", Type: authorizers.AuthorizerAllow, Enforce: &falseValue},
+						{ID: "bar", Type: authorizers.AuthorizerAllow, Enforce: &trueValue},
+						{ID: "baz", Type: authorizers.AuthorizerAllow},
+					},
+				},
+			},
+			assert: func(t *testing.T, err error, factory *mechanismsFactory) {
+				t.Helper()
+
+				require.NoError(t, err)
+				require.Len(t, factory.r.authorizers, 3)
+
+				enforcer, ok := factory.r.authorizers["foo"].(interface{ Enforced() bool })
+				require.True(t, ok)
+				assert.False(t, enforcer.Enforced())
+
+				_, ok = factory.r.authorizers["bar"].(interface{ Enforced() bool })
+				assert.False(t, ok)
+
+				_, ok = factory.r.authorizers["baz"].(interface{ Enforced() bool })
+				assert.False(t, ok)
+			},
+		},
+		{
+			uc: "enforce configured for authenticator",
+			conf: &config.Configuration{
+				Prototypes: &config.MechanismPrototypes{
+					Authenticators: []config.Mechanism{{ID: "foo", Type: authenticators.AuthenticatorAnonymous, Enforce: &falseValue}},
+				},
+			},
+			assert: func(t *testing.T, err error, _ *mechanismsFactory) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				require.ErrorContains(t, err, "enforce is not supported for authenticator 'foo'")
+			},
+		},
+		{
+			uc: "enforce configured for contextualizer",
+			conf: &config.Configuration{
+				Prototypes: &config.MechanismPrototypes{
+					Contextualizers: []config.Mechanism{{ID: "foo", Type: contextualizers.ContextualizerGeneric, Enforce: &falseValue}},
+				},
+			},
+			assert: func(t *testing.T, err error, _ *mechanismsFactory) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				require.ErrorContains(t, err, "enforce is not supported for contextualizer 'foo'")
+			},
+		},
+		{
+			uc: "enforce configured for finalizer",
+			conf: &config.Configuration{
+				Prototypes: &config.MechanismPrototypes{
+					Finalizers: []config.Mechanism{{ID: "foo", Type: finalizers.FinalizerNoop, Enforce: &falseValue}},
+				},
+			},
+			assert: func(t *testing.T, err error, _ *mechanismsFactory) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				require.ErrorContains(t, err, "enforce is not supported for finalizer 'foo'")
+			},
+		},
+		{
+			uc: "enforce configured for error handler",
+			conf: &config.Configuration{
+				Prototypes: &config.MechanismPrototypes{
+					ErrorHandlers: []config.Mechanism{{ID: "foo", Type: errorhandlers.ErrorHandlerDefault, Enforce: &falseValue}},
+				},
+			},
+			assert: func(t *testing.T, err error, _ *mechanismsFactory) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				require.ErrorContains(t, err, "enforce is not supported for error handler 'foo'")
+			},
+		},
 		{
 			uc: "fails",
 			conf: &config.Configuration{
@@ -21,6 +21,7 @@ import (
 
 	"github.com/dadrus/heimdall/internal/app"
 	"github.com/dadrus/heimdall/internal/config"
+	"github.com/dadrus/heimdall/internal/heimdall"
 	"github.com/dadrus/heimdall/internal/rules/mechanisms/authenticators"
 	"github.com/dadrus/heimdall/internal/rules/mechanisms/authorizers"
 	"github.com/dadrus/heimdall/internal/rules/mechanisms/contextualizers"
@@ -37,6 +38,10 @@ func newMechanismRepository(app app.Context) (*mechanismRepository, error) {
 
 	logger.Debug().Msg("Loading definitions for authenticators")
 
+	if err := checkEnforceNotUsed("authenticator", conf.Prototypes.Authenticators); err != nil {
+		return nil, err
+	}
+
 	authenticatorMap, err := createPipelineObjects[authenticators.Authenticator](
 		app, conf.Prototypes.Authenticators, authenticators.CreatePrototype)
 	if err != nil {
@@ -55,8 +60,18 @@ func newMechanismRepository(app app.Context) (*mechanismRepository, error) {
 		return nil, err
 	}
 
+	for _, pe := range conf.Prototypes.Authorizers {
+		if pe.Enforce != nil && !*pe.Enforce {
+			authorizerMap[pe.ID] = authorizers.NonEnforced(authorizerMap[pe.ID])
+		}
+	}
+
 	logger.Debug().Msg("Loading definitions for contextualizer")
 
+	if err = checkEnforceNotUsed("contextualizer", conf.Prototypes.Contextualizers); err != nil {
+		return nil, err
+	}
+
 	contextualizerMap, err := createPipelineObjects[contextualizers.Contextualizer](
 		app, conf.Prototypes.Contextualizers, contextualizers.CreatePrototype)
 	if err != nil {
@@ -67,6 +82,10 @@ func newMechanismRepository(app app.Context) (*mechanismRepository, error) {
 
 	logger.Debug().Msg("Loading definitions for finalizers")
 
+	if err = checkEnforceNotUsed("finalizer", conf.Prototypes.Finalizers); err != nil {
+		return nil, err
+	}
+
 	finalizerMap, err := createPipelineObjects[finalizers.Finalizer](
 		app, conf.Prototypes.Finalizers, finalizers.CreatePrototype)
 	if err != nil {
@@ -77,6 +96,10 @@ func newMechanismRepository(app app.Context) (*mechanismRepository, error) {
 
 	logger.Debug().Msg("Loading definitions for error handler")
 
+	if err = checkEnforceNotUsed("error handler", conf.Prototypes.ErrorHandlers); err != nil {
+		return nil, err
+	}
+
 	ehMap, err := createPipelineObjects[errorhandlers.ErrorHandler](
 		app, conf.Prototypes.ErrorHandlers, errorhandlers.CreatePrototype)
 	if err != nil {
@@ -94,6 +117,20 @@ func newMechanismRepository(app app.Context) (*mechanismRepository, error) {
 	}, nil
 }
 
+// checkEnforceNotUsed makes sure, enforce is configured for authorizer prototypes only, as
+// only these can be used in a not enforced (shadow) mode. That is the same behavior as for
+// the configuration of the mechanisms in a rule.
+func checkEnforceNotUsed(kind string, pObjects []config.Mechanism) error {
+	for _, pe := range pObjects {
+		if pe.Enforce != nil {
+			return errorchain.NewWithMessagef(heimdall.ErrConfiguration,
+				"enforce is not supported for %s '%s'", kind, pe.ID)
+		}
+	}
+
+	return nil
+}
+
 func createPipelineObjects[T any](
 	app app.Context,
 	pObjects []config.Mechanism,
@@ -5,6 +5,8 @@ package mocks
 import (
 	heimdall "github.com/dadrus/heimdall/internal/heimdall"
 	mock "github.com/stretchr/testify/mock"
+
+	subject "github.com/dadrus/heimdall/internal/rules/mechanisms/subject"
 )
 
 // ErrorHandlerMock is an autogenerated mock type for the errorHandler type
@@ -20,17 +22,17 @@ func (_m *ErrorHandlerMock) EXPECT() *ErrorHandlerMock_Expecter {
 	return &ErrorHandlerMock_Expecter{mock: &_m.Mock}
 }
 
-// Execute provides a mock function with given fields: ctx, causeErr
-func (_m *ErrorHandlerMock) Execute(ctx heimdall.RequestContext, causeErr error) error {
-	ret := _m.Called(ctx, causeErr)
+// Execute provides a mock function with given fields: ctx, sub, causeErr
+func (_m *ErrorHandlerMock) Execute(ctx heimdall.RequestContext, sub *subject.Subject, causeErr error) error {
+	ret := _m.Called(ctx, sub, causeErr)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Execute")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(heimdall.RequestContext, error) error); ok {
-		r0 = rf(ctx, causeErr)
+	if rf, ok := ret.Get(0).(func(heimdall.RequestContext, *subject.Subject, error) error); ok {
+		r0 = rf(ctx, sub, causeErr)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -45,14 +47,15 @@ type ErrorHandlerMock_Execute_Call struct {
 
 // Execute is a helper method to define mock.On call
 //   - ctx heimdall.RequestContext
+//   - sub *subject.Subject
 //   - causeErr error
-func (_e *ErrorHandlerMock_Expecter) Execute(ctx interface{}, causeErr interface{}) *ErrorHandlerMock_Execute_Call {
-	return &ErrorHandlerMock_Execute_Call{Call: _e.mock.On("Execute", ctx, causeErr)}
+func (_e *ErrorHandlerMock_Expecter) Execute(ctx interface{}, sub interface{}, causeErr interface{}) *ErrorHandlerMock_Execute_Call {
+	return &ErrorHandlerMock_Execute_Call{Call: _e.mock.On("Execute", ctx, sub, causeErr)}
 }
 
-func (_c *ErrorHandlerMock_Execute_Call) Run(run func(ctx heimdall.RequestContext, causeErr error)) *ErrorHandlerMock_Execute_Call {
+func (_c *ErrorHandlerMock_Execute_Call) Run(run func(ctx heimdall.RequestContext, sub *subject.Subject, causeErr error)) *ErrorHandlerMock_Execute_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(heimdall.RequestContext), args[1].(error))
+		run(args[0].(heimdall.RequestContext), args[1].(*subject.Subject), args[2].(error))
 	})
 	return _c
 }
@@ -62,7 +65,7 @@ func (_c *ErrorHandlerMock_Execute_Call) Return(_a0 error) *ErrorHandlerMock_Exe
 	return _c
 }
 
-func (_c *ErrorHandlerMock_Execute_Call) RunAndReturn(run func(heimdall.RequestContext, error) error) *ErrorHandlerMock_Execute_Call {
+func (_c *ErrorHandlerMock_Execute_Call) RunAndReturn(run func(heimdall.RequestContext, *subject.Subject, error) error) *ErrorHandlerMock_Execute_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -0,0 +1,248 @@
+// Copyright 2025 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package tokenexchange
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/rs/zerolog"
+
+	"github.com/dadrus/heimdall/internal/cache"
+	"github.com/dadrus/heimdall/internal/heimdall"
+	"github.com/dadrus/heimdall/internal/rules/endpoint"
+	"github.com/dadrus/heimdall/internal/rules/oauth2/clientcredentials"
+	"github.com/dadrus/heimdall/internal/x"
+	"github.com/dadrus/heimdall/internal/x/errorchain"
+	"github.com/dadrus/heimdall/internal/x/stringx"
+)
+
+const (
+	GrantType = "urn:ietf:params:oauth:grant-type:token-exchange"
+
+	TokenTypeAccessToken = "urn:ietf:params:oauth:token-type:access_token" // nolint: gosec
+)
+
+// Config holds the settings required to communicate with the token endpoint of an
+// authorization server supporting RFC 8693. Client authentication is done the same way
+// as for the client credentials grant flow.
+type Config struct {
+	TokenURL     string                       `mapstructure:"token_url"     validate:"required,url,enforced=istls"`
+	ClientID     string                       `mapstructure:"client_id"     validate:"required"`
+	ClientSecret string                       `mapstructure:"client_secret" validate:"required"`
+	AuthMethod   clientcredentials.AuthMethod `mapstructure:"auth_method"   validate:"omitempty,oneof=basic_auth request_body"` //nolint:lll
+	TTL          *time.Duration               `mapstructure:"cache_ttl"`
+}
+
+// Request represents the parameters of a token exchange request.
+type Request struct {
+	SubjectToken       string
+	SubjectTokenType   string
+	RequestedTokenType string
+	Audience           string
+	Resource           string
+	Scope              string
+}
+
+func (c *Config) Exchange(ctx context.Context, req *Request) (*clientcredentials.TokenInfo, error) {
+	logger := zerolog.Ctx(ctx)
+	cch := cache.Ctx(ctx)
+
+	var cacheKey string
+
+	if c.isCacheEnabled() {
+		cacheKey = c.calculateCacheKey(req)
+		if entry, err := cch.Get(ctx, cacheKey); err == nil {
+			var tokenInfo clientcredentials.TokenInfo
+
+			if err = json.Unmarshal(entry, &tokenInfo); err == nil {
+				logger.Debug().Msg("Reusing exchanged token from cache")
+
+				return &tokenInfo, nil
+			}
+		}
+	}
+
+	logger.Debug().Msg("Exchanging token")
+
+	tokenInfo, err := c.exchangeToken(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if cacheTTL := c.getCacheTTL(tokenInfo); cacheTTL > 0 {
+		data, _ := json.Marshal(tokenInfo)
+
+		if err = cch.Set(ctx, cacheKey, data, cacheTTL); err != nil {
+			logger.Warn().Err(err).Msg("Failed to cache exchanged token")
+		}
+	}
+
+	return tokenInfo, nil
+}
+
+func (c *Config) exchangeToken(ctx context.Context, req *Request) (*clientcredentials.TokenInfo, error) {
+	ept := endpoint.Endpoint{
+		URL:    c.TokenURL,
+		Method: http.MethodPost,
+		// client authentication is the same as for the client credentials grant flow
+		AuthStrategy: &clientcredentials.Config{
+			TokenURL:     c.TokenURL,
+			ClientID:     c.ClientID,
+			ClientSecret: c.ClientSecret,
+			AuthMethod:   c.AuthMethod,
+		},
+		Headers: map[string]string{
+			"Content-Type": "application/x-www-form-urlencoded",
+			"Accept":       "application/json",
+		},
+	}
+
+	data := url.Values{
+		"grant_type":    []string{GrantType},
+		"subject_token": []string{req.SubjectToken},
+		"subject_token_type": []string{
+			x.IfThenElse(len(req.SubjectTokenType) != 0, req.SubjectTokenType, TokenTypeAccessToken),
+		},
+	}
+
+	for name, value := range map[string]string{
+		"requested_token_type": req.RequestedTokenType,
+		"audience":             req.Audience,
+		"resource":             req.Resource,
+		"scope":                req.Scope,
+	} {
+		if len(value) != 0 {
+			data.Set(name, value)
+		}
+	}
+
+	rawData, err := ept.SendRequest(
+		ctx,
+		strings.NewReader(data.Encode()),
+		nil,
+		func(resp *http.Response) ([]byte, error) {
+			if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusBadRequest {
+				return nil, errorchain.NewWithMessagef(heimdall.ErrCommunication,
+					"unexpected response code: %v", resp.StatusCode)
+			}
+
+			rawData, err := io.ReadAll(resp.Body)
+			if err != nil {
+				return nil, errorchain.NewWithMessage(heimdall.ErrInternal,
+					"failed to read response").CausedBy(err)
+			}
+
+			if resp.StatusCode == http.StatusBadRequest {
+				var ter clientcredentials.TokenErrorResponse
+				if err = json.Unmarshal(rawData, &ter); err != nil {
+					return nil, errorchain.NewWithMessagef(heimdall.ErrCommunication,
+						"failed to exchange token: %s", stringx.ToString(rawData))
+				}
+
+				return nil, errorchain.New(heimdall.ErrCommunication).CausedBy(&ter)
+			}
+
+			return rawData, nil
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp clientcredentials.TokenEndpointResponse
+	if err := json.Unmarshal(rawData, &resp); err != nil {
+		return nil, errorchain.
+			NewWithMessage(heimdall.ErrInternal, "failed to unmarshal response").
+			CausedBy(err)
+	}
+
+	tokenInfo, err := resp.TokenInfo()
+	if err != nil {
+		return nil, errorchain.New(heimdall.ErrCommunication).CausedBy(err)
+	}
+
+	if len(tokenInfo.AccessToken) == 0 {
+		return nil, errorchain.NewWithMessage(heimdall.ErrCommunication,
+			"token endpoint response does not contain an access_token")
+	}
+
+	return tokenInfo, nil
+}
+
+func (c *Config) calculateCacheKey(req *Request) string {
+	digest := sha256.New()
+	digest.Write(stringx.ToBytes(c.TokenURL))
+	digest.Write(stringx.ToBytes(c.ClientID))
+	digest.Write(stringx.ToBytes(c.ClientSecret))
+	digest.Write(stringx.ToBytes(req.SubjectToken))
+	digest.Write(stringx.ToBytes(req.SubjectTokenType))
+	digest.Write(stringx.ToBytes(req.RequestedTokenType))
+	digest.Write(stringx.ToBytes(req.Audience))
+	digest.Write(stringx.ToBytes(req.Resource))
+	digest.Write(stringx.ToBytes(req.Scope))
+
+	return hex.EncodeToString(digest.Sum(nil))
+}
+
+func (c *Config) getCacheTTL(resp *clientcredentials.TokenInfo) time.Duration {
+	// timeLeeway defines the default time deviation to ensure the token is still valid
+	// when used from cache
+	const timeLeeway = 5
+
+	if !c.isCacheEnabled() {
+		return 0
+	}
+
+	// like for the client credentials flow, the expiration information from the token
+	// endpoint response is used if available. The configured ttl takes precedence if shorter.
+	tokenEndpointResponseTTL := x.IfThenElseExec(!resp.Expiry.IsZero(),
+		func() time.Duration {
+			expiresIn := time.Until(resp.Expiry) - timeLeeway*time.Second
+
+			return x.IfThenElse(expiresIn > 0, expiresIn, 0)
+		},
+		func() time.Duration { return 0 })
+
+	configuredTTL := x.IfThenElseExec(c.TTL != nil,
+		func() time.Duration { return *c.TTL },
+		func() time.Duration { return 0 })
+
+	switch {
+	case configuredTTL == 0 && tokenEndpointResponseTTL == 0:
+		return 0
+	case configuredTTL == 0 && tokenEndpointResponseTTL != 0:
+		return tokenEndpointResponseTTL
+	case configuredTTL != 0 && tokenEndpointResponseTTL == 0:
+		return configuredTTL
+	default:
+		return min(configuredTTL, tokenEndpointResponseTTL)
+	}
+}
+
+func (c *Config) isCacheEnabled() bool {
+	// cache is enabled if it is not configured (in that case the ttl value from the
+	// token response if used), or if it is configured and the value > 0
+	return c.TTL == nil || (c.TTL != nil && *c.TTL > 0)
+}
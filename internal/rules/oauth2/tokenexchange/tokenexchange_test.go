@@ -0,0 +1,370 @@
+// Copyright 2025 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package tokenexchange
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dadrus/heimdall/internal/cache"
+	"github.com/dadrus/heimdall/internal/cache/mocks"
+	"github.com/dadrus/heimdall/internal/heimdall"
+	"github.com/dadrus/heimdall/internal/rules/oauth2/clientcredentials"
+	"github.com/dadrus/heimdall/internal/x"
+)
+
+func TestTokenExchange(t *testing.T) {
+	t.Parallel()
+
+	type (
+		RequestAsserter func(t *testing.T, req *http.Request)
+		ResponseBuilder func(t *testing.T) (any, int)
+	)
+
+	var (
+		endpointCalled bool
+		assertRequest  RequestAsserter
+		buildResponse  ResponseBuilder
+	)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		endpointCalled = true
+
+		if req.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+
+			return
+		}
+
+		if err := req.ParseForm(); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+
+			return
+		}
+
+		assertRequest(t, req)
+
+		resp, code := buildResponse(t)
+
+		rawResp, err := json.MarshalContext(req.Context(), resp)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Content-Length", strconv.Itoa(len(rawResp)))
+
+		w.WriteHeader(code)
+		_, err = w.Write(rawResp)
+		assert.NoError(t, err)
+	}))
+	defer srv.Close()
+
+	for _, tc := range []struct {
+		uc             string
+		cfg            *Config
+		req            *Request
+		configureMocks func(t *testing.T, cch *mocks.CacheMock)
+		assertRequest  RequestAsserter
+		buildResponse  ResponseBuilder
+		assert         func(t *testing.T, err error, tokenEndpointCalled bool, token *clientcredentials.TokenInfo)
+	}{
+		{
+			uc:  "reusing response from cache",
+			cfg: &Config{},
+			req: &Request{SubjectToken: "foo"},
+			configureMocks: func(t *testing.T, cch *mocks.CacheMock) {
+				t.Helper()
+
+				rawData, err := json.Marshal(&clientcredentials.TokenInfo{TokenType: "Bearer", AccessToken: "foobar"})
+				require.NoError(t, err)
+
+				cch.EXPECT().Get(mock.Anything, mock.Anything).Return(rawData, nil)
+			},
+			assert: func(t *testing.T, err error, tokenEndpointCalled bool, token *clientcredentials.TokenInfo) {
+				t.Helper()
+
+				require.NoError(t, err)
+				assert.False(t, tokenEndpointCalled)
+				assert.Equal(t, "Bearer", token.TokenType)
+				assert.Equal(t, "foobar", token.AccessToken)
+			},
+		},
+		{
+			uc:  "minimal request, ttl not configured and token has expires_in claim",
+			cfg: &Config{TokenURL: srv.URL, ClientID: "bar", ClientSecret: "foo"},
+			req: &Request{SubjectToken: "subject-token"},
+			configureMocks: func(t *testing.T, cch *mocks.CacheMock) {
+				t.Helper()
+
+				cch.EXPECT().Get(mock.Anything, mock.Anything).Return(nil, errors.New("no cache entry"))
+				cch.EXPECT().Set(mock.Anything, mock.Anything, mock.Anything,
+					mock.MatchedBy(func(ttl time.Duration) bool {
+						return ttl.Round(time.Second) == 5*time.Minute-5*time.Second
+					}),
+				).Return(nil)
+			},
+			assertRequest: func(t *testing.T, req *http.Request) {
+				t.Helper()
+
+				clientID, clientSecret, ok := req.BasicAuth()
+				require.True(t, ok)
+				assert.Equal(t, "bar", clientID)
+				assert.Equal(t, "foo", clientSecret)
+
+				assert.Equal(t, "application/x-www-form-urlencoded", req.Header.Get("Content-Type"))
+				assert.Equal(t, "application/json", req.Header.Get("Accept"))
+				assert.Equal(t, GrantType, req.FormValue("grant_type"))
+				assert.Equal(t, "subject-token", req.FormValue("subject_token"))
+				assert.Equal(t, TokenTypeAccessToken, req.FormValue("subject_token_type"))
+				assert.Len(t, req.PostForm, 3)
+			},
+			buildResponse: func(t *testing.T) (any, int) {
+				t.Helper()
+
+				return map[string]any{
+					"access_token":      "exchanged",
+					"issued_token_type": TokenTypeAccessToken,
+					"token_type":        "Bearer",
+					"expires_in":        int64((5 * time.Minute).Seconds()),
+				}, http.StatusOK
+			},
+			assert: func(t *testing.T, err error, tokenEndpointCalled bool, token *clientcredentials.TokenInfo) {
+				t.Helper()
+
+				require.NoError(t, err)
+				assert.True(t, tokenEndpointCalled)
+				assert.Equal(t, "Bearer", token.TokenType)
+				assert.Equal(t, "exchanged", token.AccessToken)
+			},
+		},
+		{
+			uc: "full request with request body authentication and disabled cache",
+			cfg: &Config{
+				TokenURL:     srv.URL,
+				ClientID:     "bar",
+				ClientSecret: "foo",
+				AuthMethod:   clientcredentials.AuthMethodRequestBody,
+				TTL: func() *time.Duration {
+					ttl := 0 * time.Second
+
+					return &ttl
+				}(),
+			},
+			req: &Request{
+				SubjectToken:       "subject-token",
+				SubjectTokenType:   "urn:ietf:params:oauth:token-type:jwt",
+				RequestedTokenType: "urn:ietf:params:oauth:token-type:jwt",
+				Audience:           "upstream",
+				Resource:           "https://upstream.local/api",
+				Scope:              "read write",
+			},
+			assertRequest: func(t *testing.T, req *http.Request) {
+				t.Helper()
+
+				_, _, ok := req.BasicAuth()
+				assert.False(t, ok)
+				assert.Equal(t, "bar", req.FormValue("client_id"))
+				assert.Equal(t, "foo", req.FormValue("client_secret"))
+				assert.Equal(t, GrantType, req.FormValue("grant_type"))
+				assert.Equal(t, "subject-token", req.FormValue("subject_token"))
+				assert.Equal(t, "urn:ietf:params:oauth:token-type:jwt", req.FormValue("subject_token_type"))
+				assert.Equal(t, "urn:ietf:params:oauth:token-type:jwt", req.FormValue("requested_token_type"))
+				assert.Equal(t, "upstream", req.FormValue("audience"))
+				assert.Equal(t, "https://upstream.local/api", req.FormValue("resource"))
+				assert.Equal(t, "read write", req.FormValue("scope"))
+			},
+			buildResponse: func(t *testing.T) (any, int) {
+				t.Helper()
+
+				return map[string]any{
+					"access_token":      "exchanged",
+					"issued_token_type": "urn:ietf:params:oauth:token-type:jwt",
+					"token_type":        "N_A",
+				}, http.StatusOK
+			},
+			assert: func(t *testing.T, err error, tokenEndpointCalled bool, token *clientcredentials.TokenInfo) {
+				t.Helper()
+
+				require.NoError(t, err)
+				assert.True(t, tokenEndpointCalled)
+				assert.Equal(t, "N_A", token.TokenType)
+				assert.Equal(t, "exchanged", token.AccessToken)
+			},
+		},
+		{
+			uc: "configured ttl is shorter than the token lifetime",
+			cfg: &Config{
+				TokenURL:     srv.URL,
+				ClientID:     "bar",
+				ClientSecret: "foo",
+				TTL: func() *time.Duration {
+					ttl := 1 * time.Minute
+
+					return &ttl
+				}(),
+			},
+			req: &Request{SubjectToken: "subject-token", Audience: "upstream"},
+			configureMocks: func(t *testing.T, cch *mocks.CacheMock) {
+				t.Helper()
+
+				cch.EXPECT().Get(mock.Anything, mock.Anything).Return(nil, errors.New("no cache entry"))
+				cch.EXPECT().Set(mock.Anything, mock.Anything, mock.Anything, 1*time.Minute).Return(nil)
+			},
+			buildResponse: func(t *testing.T) (any, int) {
+				t.Helper()
+
+				return map[string]any{
+					"access_token": "exchanged",
+					"token_type":   "Bearer",
+					"expires_in":   int64((5 * time.Minute).Seconds()),
+				}, http.StatusOK
+			},
+			assert: func(t *testing.T, err error, tokenEndpointCalled bool, token *clientcredentials.TokenInfo) {
+				t.Helper()
+
+				require.NoError(t, err)
+				assert.True(t, tokenEndpointCalled)
+				assert.Equal(t, "exchanged", token.AccessToken)
+			},
+		},
+		{
+			uc:  "response without access token",
+			cfg: &Config{TokenURL: srv.URL, ClientID: "bar", ClientSecret: "foo"},
+			req: &Request{SubjectToken: "subject-token"},
+			configureMocks: func(t *testing.T, cch *mocks.CacheMock) {
+				t.Helper()
+
+				cch.EXPECT().Get(mock.Anything, mock.Anything).Return(nil, errors.New("no cache entry"))
+			},
+			buildResponse: func(t *testing.T) (any, int) {
+				t.Helper()
+
+				return map[string]any{"token_type": "Bearer"}, http.StatusOK
+			},
+			assert: func(t *testing.T, err error, tokenEndpointCalled bool, _ *clientcredentials.TokenInfo) {
+				t.Helper()
+
+				assert.True(t, tokenEndpointCalled)
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrCommunication)
+				require.ErrorContains(t, err, "does not contain an access_token")
+			},
+		},
+		{
+			uc:  "unexpected response code",
+			cfg: &Config{TokenURL: srv.URL, ClientID: "bar", ClientSecret: "foo"},
+			req: &Request{SubjectToken: "subject-token"},
+			configureMocks: func(t *testing.T, cch *mocks.CacheMock) {
+				t.Helper()
+
+				cch.EXPECT().Get(mock.Anything, mock.Anything).Return(nil, errors.New("no cache entry"))
+			},
+			buildResponse: func(t *testing.T) (any, int) {
+				t.Helper()
+
+				return map[string]any{}, http.StatusUnauthorized
+			},
+			assert: func(t *testing.T, err error, tokenEndpointCalled bool, _ *clientcredentials.TokenInfo) {
+				t.Helper()
+
+				assert.True(t, tokenEndpointCalled)
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrCommunication)
+				require.ErrorContains(t, err, "unexpected response code")
+			},
+		},
+		{
+			uc:  "error response from the authorization server",
+			cfg: &Config{TokenURL: srv.URL, ClientID: "bar", ClientSecret: "foo"},
+			req: &Request{SubjectToken: "subject-token", Audience: "unknown"},
+			configureMocks: func(t *testing.T, cch *mocks.CacheMock) {
+				t.Helper()
+
+				cch.EXPECT().Get(mock.Anything, mock.Anything).Return(nil, errors.New("no cache entry"))
+			},
+			buildResponse: func(t *testing.T) (any, int) {
+				t.Helper()
+
+				return &clientcredentials.TokenErrorResponse{
+					ErrorType:        "invalid_target",
+					ErrorDescription: "unknown audience",
+				}, http.StatusBadRequest
+			},
+			assert: func(t *testing.T, err error, tokenEndpointCalled bool, _ *clientcredentials.TokenInfo) {
+				t.Helper()
+
+				assert.True(t, tokenEndpointCalled)
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrCommunication)
+				require.ErrorContains(t, err, "invalid_target")
+				require.ErrorContains(t, err, "unknown audience")
+			},
+		},
+	} {
+		t.Run(tc.uc, func(t *testing.T) {
+			endpointCalled = false
+			configureMocks := x.IfThenElse(tc.configureMocks != nil,
+				tc.configureMocks,
+				func(t *testing.T, _ *mocks.CacheMock) { t.Helper() },
+			)
+			assertRequest = x.IfThenElse(tc.assertRequest != nil,
+				tc.assertRequest,
+				func(t *testing.T, _ *http.Request) { t.Helper() },
+			)
+			buildResponse = tc.buildResponse
+
+			cch := mocks.NewCacheMock(t)
+			ctx := cache.WithContext(t.Context(), cch)
+
+			configureMocks(t, cch)
+
+			// WHEN
+			token, err := tc.cfg.Exchange(ctx, tc.req)
+
+			// THEN
+			tc.assert(t, err, endpointCalled, token)
+		})
+	}
+}
+
+func TestTokenExchangeCacheKey(t *testing.T) {
+	t.Parallel()
+
+	cfg := &Config{TokenURL: "https://auth.local/token", ClientID: "foo", ClientSecret: "bar"}
+
+	key1 := cfg.calculateCacheKey(&Request{SubjectToken: "token1", Audience: "foo"})
+	key2 := cfg.calculateCacheKey(&Request{SubjectToken: "token1", Audience: "bar"})
+	key3 := cfg.calculateCacheKey(&Request{SubjectToken: "token2", Audience: "foo"})
+	key4 := cfg.calculateCacheKey(&Request{SubjectToken: "token1", Audience: "foo"})
+
+	assert.NotEqual(t, key1, key2)
+	assert.NotEqual(t, key1, key3)
+	assert.NotEqual(t, key2, key3)
+	assert.Equal(t, key1, key4)
+}
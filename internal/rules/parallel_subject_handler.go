@@ -0,0 +1,201 @@
+// Copyright 2025 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package rules
+
+import (
+	"context"
+	"maps"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/dadrus/heimdall/internal/heimdall"
+	"github.com/dadrus/heimdall/internal/rules/mechanisms/subject"
+)
+
+const groupTypeParallel = "parallel"
+
+// parallelSubjectHandler executes independent pipeline steps concurrently. Each step works on its
+// own copy of the Outputs object, which are merged back in the order the steps are configured, once
+// all steps completed. A failing step, which does not allow the pipeline to continue, cancels the
+// remaining ones.
+type parallelSubjectHandler struct {
+	handlers []*conditionalSubjectHandler
+	timeout  time.Duration
+}
+
+func (p *parallelSubjectHandler) Execute(ctx heimdall.RequestContext, sub *subject.Subject) error {
+	logger := zerolog.Ctx(ctx.Context())
+
+	var (
+		cancel context.CancelFunc
+		wg     sync.WaitGroup
+		mut    sync.Mutex
+		once   sync.Once
+		failed error
+	)
+
+	stepsCtx := ctx.Context()
+	if p.timeout > 0 {
+		stepsCtx, cancel = context.WithTimeout(stepsCtx, p.timeout)
+	} else {
+		stepsCtx, cancel = context.WithCancel(stepsCtx)
+	}
+
+	defer cancel()
+
+	outputs := ctx.Outputs()
+	stepContexts := make([]*parallelStepContext, len(p.handlers))
+
+	for idx, handler := range p.handlers {
+		stepCtx := &parallelStepContext{
+			RequestContext: ctx,
+			ctx:            stepsCtx,
+			mut:            &mut,
+			outputs:        maps.Clone(outputs),
+		}
+		stepContexts[idx] = stepCtx
+
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			if err := handler.Execute(stepCtx, sub); err != nil {
+				logger.Info().Err(err).Str("_id", handler.ID()).Msg("Pipeline step execution failed")
+
+				if handler.ContinueOnError() {
+					logger.Info().Str("_id", handler.ID()).Msg("Error ignored. Continuing group execution")
+
+					return
+				}
+
+				once.Do(func() {
+					failed = err
+
+					cancel()
+				})
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	for _, stepCtx := range stepContexts {
+		maps.Copy(outputs, stepCtx.outputs)
+	}
+
+	return failed
+}
+
+func (p *parallelSubjectHandler) ID() string {
+	ids := make([]string, len(p.handlers))
+	for idx, handler := range p.handlers {
+		ids[idx] = handler.ID()
+	}
+
+	return groupTypeParallel + "(" + strings.Join(ids, ",") + ")"
+}
+
+func (p *parallelSubjectHandler) ContinueOnError() bool { return false }
+
+// parallelStepContext isolates a step of a parallel group. It provides the step with its own
+// Outputs object and the shared context of the group, and serializes modifications of the
+// wrapped request context.
+type parallelStepContext struct {
+	heimdall.RequestContext
+
+	ctx     context.Context //nolint:containedctx
+	mut     *sync.Mutex
+	outputs map[string]any
+}
+
+func (c *parallelStepContext) Context() context.Context { return c.ctx }
+
+func (c *parallelStepContext) Outputs() map[string]any { return c.outputs }
+
+func (c *parallelStepContext) AddHeaderForUpstream(name, value string) {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+
+	c.RequestContext.AddHeaderForUpstream(name, value)
+}
+
+func (c *parallelStepContext) AddCookieForUpstream(name, value string) {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+
+	c.RequestContext.AddCookieForUpstream(name, value)
+}
+
+func (c *parallelStepContext) RemoveHeaderForUpstream(name string) {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+
+	c.RequestContext.RemoveHeaderForUpstream(name)
+}
+
+func (c *parallelStepContext) RemoveCookieForUpstream(name string) {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+
+	c.RequestContext.RemoveCookieForUpstream(name)
+}
+
+func (c *parallelStepContext) SetQueryParameterForUpstream(name, value string) {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+
+	c.RequestContext.SetQueryParameterForUpstream(name, value)
+}
+
+func (c *parallelStepContext) RemoveQueryParameterForUpstream(name string) {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+
+	c.RequestContext.RemoveQueryParameterForUpstream(name)
+}
+
+func (c *parallelStepContext) SetPathForUpstream(path string) {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+
+	c.RequestContext.SetPathForUpstream(path)
+}
+
+func (c *parallelStepContext) AddPathPrefixForUpstream(prefix string) {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+
+	c.RequestContext.AddPathPrefixForUpstream(prefix)
+}
+
+func (c *parallelStepContext) AddSignerForUpstream(signer heimdall.UpstreamRequestSigner) {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+
+	c.RequestContext.AddSignerForUpstream(signer)
+}
+
+func (c *parallelStepContext) SetPipelineError(err error) {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+
+	c.RequestContext.SetPipelineError(err)
+}
@@ -0,0 +1,309 @@
+// Copyright 2025 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package rules
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dadrus/heimdall/internal/handler/requestcontext"
+	"github.com/dadrus/heimdall/internal/heimdall"
+	"github.com/dadrus/heimdall/internal/heimdall/mocks"
+	"github.com/dadrus/heimdall/internal/rules/mechanisms/subject"
+	rulemocks "github.com/dadrus/heimdall/internal/rules/mocks"
+)
+
+func TestParallelSubjectHandlerExecute(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		uc             string
+		timeout        time.Duration
+		configureMocks func(t *testing.T, ctx *mocks.RequestContextMock, h1, h2 *rulemocks.SubjectHandlerMock)
+		assert         func(t *testing.T, err error, outputs map[string]any)
+	}{
+		{
+			uc: "all steps succeed and are executed concurrently",
+			configureMocks: func(t *testing.T, ctx *mocks.RequestContextMock, h1, h2 *rulemocks.SubjectHandlerMock) {
+				t.Helper()
+
+				started := make(chan struct{})
+
+				ctx.EXPECT().AddHeaderForUpstream("X-Foo", "bar")
+
+				h1.EXPECT().Execute(mock.Anything, mock.Anything).RunAndReturn(
+					func(ctx heimdall.RequestContext, _ *subject.Subject) error {
+						// waits for the second step to be started
+						select {
+						case <-started:
+						case <-ctx.Context().Done():
+							return ctx.Context().Err()
+						}
+
+						ctx.Outputs()["foo"] = "h1"
+						ctx.Outputs()["h1"] = ctx.Outputs()["existing"]
+						ctx.AddHeaderForUpstream("X-Foo", "bar")
+
+						return nil
+					})
+				h2.EXPECT().Execute(mock.Anything, mock.Anything).RunAndReturn(
+					func(ctx heimdall.RequestContext, _ *subject.Subject) error {
+						close(started)
+
+						ctx.Outputs()["foo"] = "h2"
+						ctx.Outputs()["h2"] = "baz"
+
+						return nil
+					})
+			},
+			timeout: 5 * time.Second,
+			assert: func(t *testing.T, err error, outputs map[string]any) {
+				t.Helper()
+
+				require.NoError(t, err)
+				assert.Equal(t, map[string]any{
+					"existing": "value",
+					"foo":      "h2",
+					"h1":       "value",
+					"h2":       "baz",
+				}, outputs)
+			},
+		},
+		{
+			uc: "failing step configured to continue on error",
+			configureMocks: func(t *testing.T, _ *mocks.RequestContextMock, h1, h2 *rulemocks.SubjectHandlerMock) {
+				t.Helper()
+
+				h1.EXPECT().Execute(mock.Anything, mock.Anything).Return(heimdall.ErrCommunication)
+				h1.EXPECT().ContinueOnError().Return(true)
+				h2.EXPECT().Execute(mock.Anything, mock.Anything).RunAndReturn(
+					func(ctx heimdall.RequestContext, _ *subject.Subject) error {
+						ctx.Outputs()["h2"] = "baz"
+
+						return nil
+					})
+			},
+			assert: func(t *testing.T, err error, outputs map[string]any) {
+				t.Helper()
+
+				require.NoError(t, err)
+				assert.Equal(t, map[string]any{"existing": "value", "h2": "baz"}, outputs)
+			},
+		},
+		{
+			uc: "failing step cancels the remaining ones",
+			configureMocks: func(t *testing.T, _ *mocks.RequestContextMock, h1, h2 *rulemocks.SubjectHandlerMock) {
+				t.Helper()
+
+				h1.EXPECT().Execute(mock.Anything, mock.Anything).Return(heimdall.ErrCommunication)
+				h1.EXPECT().ContinueOnError().Return(false)
+				h2.EXPECT().Execute(mock.Anything, mock.Anything).RunAndReturn(
+					func(ctx heimdall.RequestContext, _ *subject.Subject) error {
+						<-ctx.Context().Done()
+
+						return errors.New("canceled")
+					})
+				h2.EXPECT().ContinueOnError().Return(false)
+			},
+			assert: func(t *testing.T, err error, outputs map[string]any) {
+				t.Helper()
+
+				require.ErrorIs(t, err, heimdall.ErrCommunication)
+				assert.Equal(t, map[string]any{"existing": "value"}, outputs)
+			},
+		},
+		{
+			uc:      "steps exceed the timeout of the group",
+			timeout: 10 * time.Millisecond,
+			configureMocks: func(t *testing.T, _ *mocks.RequestContextMock, h1, h2 *rulemocks.SubjectHandlerMock) {
+				t.Helper()
+
+				h1.EXPECT().Execute(mock.Anything, mock.Anything).RunAndReturn(
+					func(ctx heimdall.RequestContext, _ *subject.Subject) error {
+						<-ctx.Context().Done()
+
+						return ctx.Context().Err()
+					})
+				h1.EXPECT().ContinueOnError().Return(false)
+				h2.EXPECT().Execute(mock.Anything, mock.Anything).RunAndReturn(
+					func(ctx heimdall.RequestContext, _ *subject.Subject) error {
+						<-ctx.Context().Done()
+
+						return ctx.Context().Err()
+					})
+				h2.EXPECT().ContinueOnError().Return(false)
+			},
+			assert: func(t *testing.T, err error, _ map[string]any) {
+				t.Helper()
+
+				require.ErrorIs(t, err, context.DeadlineExceeded)
+			},
+		},
+	} {
+		t.Run(tc.uc, func(t *testing.T) {
+			// GIVEN
+			h1 := rulemocks.NewSubjectHandlerMock(t)
+			h2 := rulemocks.NewSubjectHandlerMock(t)
+			c1 := rulemocks.NewExecutionConditionMock(t)
+			c2 := rulemocks.NewExecutionConditionMock(t)
+
+			h1.EXPECT().ID().Maybe().Return("foo")
+			h2.EXPECT().ID().Maybe().Return("bar")
+			c1.EXPECT().CanExecuteOnSubject(mock.Anything, mock.Anything).Return(true, nil)
+			c2.EXPECT().CanExecuteOnSubject(mock.Anything, mock.Anything).Return(true, nil)
+
+			group := &parallelSubjectHandler{
+				timeout: tc.timeout,
+				handlers: []*conditionalSubjectHandler{
+					{h: h1, c: c1},
+					{h: h2, c: c2},
+				},
+			}
+
+			outputs := map[string]any{"existing": "value"}
+
+			ctx := mocks.NewRequestContextMock(t)
+			ctx.EXPECT().Context().Return(t.Context())
+			ctx.EXPECT().Outputs().Return(outputs)
+
+			tc.configureMocks(t, ctx, h1, h2)
+
+			// WHEN
+			err := group.Execute(ctx, &subject.Subject{ID: "foo"})
+
+			// THEN
+			tc.assert(t, err, outputs)
+		})
+	}
+}
+
+func TestParallelSubjectHandlerExecuteWithStepsReadingRequestBody(t *testing.T) {
+	t.Parallel()
+
+	// GIVEN
+	h1 := rulemocks.NewSubjectHandlerMock(t)
+	h2 := rulemocks.NewSubjectHandlerMock(t)
+	c1 := rulemocks.NewExecutionConditionMock(t)
+	c2 := rulemocks.NewExecutionConditionMock(t)
+
+	readBody := func(name string) func(ctx heimdall.RequestContext, _ *subject.Subject) error {
+		return func(ctx heimdall.RequestContext, _ *subject.Subject) error {
+			ctx.Outputs()[name] = ctx.Request().Body()
+
+			return nil
+		}
+	}
+
+	h1.EXPECT().ID().Maybe().Return("foo")
+	h2.EXPECT().ID().Maybe().Return("bar")
+	h1.EXPECT().Execute(mock.Anything, mock.Anything).RunAndReturn(readBody("h1"))
+	h2.EXPECT().Execute(mock.Anything, mock.Anything).RunAndReturn(readBody("h2"))
+	c1.EXPECT().CanExecuteOnSubject(mock.Anything, mock.Anything).Return(true, nil)
+	c2.EXPECT().CanExecuteOnSubject(mock.Anything, mock.Anything).Return(true, nil)
+
+	group := &parallelSubjectHandler{
+		handlers: []*conditionalSubjectHandler{
+			{h: h1, c: c1},
+			{h: h2, c: c2},
+		},
+	}
+
+	req := httptest.NewRequestWithContext(t.Context(), http.MethodPost, "https://foo.bar/test",
+		strings.NewReader(`{"foo":"bar"}`))
+	req.Header.Set("Content-Type", "application/json")
+
+	ctx := requestcontext.New(req)
+
+	// WHEN
+	err := group.Execute(ctx, &subject.Subject{ID: "foo"})
+
+	// THEN
+	require.NoError(t, err)
+
+	expected := map[string]any{"foo": "bar"}
+	assert.Equal(t, expected, ctx.Outputs()["h1"])
+	assert.Equal(t, expected, ctx.Outputs()["h2"])
+}
+
+func TestParallelSubjectHandlerExecuteWithStepsAddingUpstreamSigners(t *testing.T) {
+	t.Parallel()
+
+	// GIVEN
+	const signersPerStep = 50
+
+	h1 := rulemocks.NewSubjectHandlerMock(t)
+	h2 := rulemocks.NewSubjectHandlerMock(t)
+	c1 := rulemocks.NewExecutionConditionMock(t)
+	c2 := rulemocks.NewExecutionConditionMock(t)
+	signer := mocks.NewUpstreamRequestSignerMock(t)
+
+	addSigners := func(ctx heimdall.RequestContext, _ *subject.Subject) error {
+		for range signersPerStep {
+			ctx.AddSignerForUpstream(signer)
+		}
+
+		return nil
+	}
+
+	h1.EXPECT().ID().Maybe().Return("foo")
+	h2.EXPECT().ID().Maybe().Return("bar")
+	h1.EXPECT().Execute(mock.Anything, mock.Anything).RunAndReturn(addSigners)
+	h2.EXPECT().Execute(mock.Anything, mock.Anything).RunAndReturn(addSigners)
+	c1.EXPECT().CanExecuteOnSubject(mock.Anything, mock.Anything).Return(true, nil)
+	c2.EXPECT().CanExecuteOnSubject(mock.Anything, mock.Anything).Return(true, nil)
+
+	group := &parallelSubjectHandler{
+		handlers: []*conditionalSubjectHandler{
+			{h: h1, c: c1},
+			{h: h2, c: c2},
+		},
+	}
+
+	req := httptest.NewRequestWithContext(t.Context(), http.MethodGet, "https://foo.bar/test", nil)
+	ctx := requestcontext.New(req)
+
+	// WHEN
+	err := group.Execute(ctx, &subject.Subject{ID: "foo"})
+
+	// THEN
+	require.NoError(t, err)
+	assert.Len(t, ctx.UpstreamSigners(), 2*signersPerStep)
+}
+
+func TestParallelSubjectHandlerID(t *testing.T) {
+	t.Parallel()
+
+	h1 := rulemocks.NewSubjectHandlerMock(t)
+	h2 := rulemocks.NewSubjectHandlerMock(t)
+
+	h1.EXPECT().ID().Return("foo")
+	h2.EXPECT().ID().Return("bar")
+
+	group := &parallelSubjectHandler{handlers: []*conditionalSubjectHandler{{h: h1}, {h: h2}}}
+
+	assert.Equal(t, "parallel(foo,bar)", group.ID())
+	assert.False(t, group.ContinueOnError())
+}
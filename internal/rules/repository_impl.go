@@ -29,6 +29,13 @@ import (
 	"github.com/dadrus/heimdall/internal/x/slicex"
 )
 
+// lifecycleAware is implemented by rules maintaining background activities, like health
+// checking of upstream targets, which must only run while the rule is in use.
+type lifecycleAware interface {
+	activate() error
+	deactivate()
+}
+
 type repository struct {
 	dr rule.Rule
 
@@ -89,6 +96,10 @@ func (r *repository) AddRuleSet(_ context.Context, _ string, rules []rule.Rule)
 		return err
 	}
 
+	if err := activate(rules); err != nil {
+		return err
+	}
+
 	r.knownRules = append(r.knownRules, rules...)
 
 	r.rulesTreeMutex.Lock()
@@ -144,6 +155,10 @@ func (r *repository) UpdateRuleSet(_ context.Context, srcID string, rules []rule
 		return err
 	}
 
+	if err := activate(toBeAdded); err != nil {
+		return err
+	}
+
 	r.knownRules = slices.DeleteFunc(r.knownRules, func(loaded rule.Rule) bool {
 		return slices.Contains(toBeDeleted, loaded)
 	})
@@ -153,6 +168,8 @@ func (r *repository) UpdateRuleSet(_ context.Context, srcID string, rules []rule
 	r.index = tmp
 	r.rulesTreeMutex.Unlock()
 
+	deactivate(toBeDeleted)
+
 	return nil
 }
 
@@ -178,6 +195,8 @@ func (r *repository) DeleteRuleSet(_ context.Context, srcID string) error {
 	r.index = tmp
 	r.rulesTreeMutex.Unlock()
 
+	deactivate(applicable)
+
 	return nil
 }
 
@@ -215,3 +234,29 @@ func (r *repository) removeRulesFrom(tree *radixtree.Tree[rule.Route], tbdRules
 
 	return nil
 }
+
+func activate(rules []rule.Rule) error {
+	for idx, rul := range rules {
+		la, ok := rul.(lifecycleAware)
+		if !ok {
+			continue
+		}
+
+		if err := la.activate(); err != nil {
+			deactivate(rules[:idx])
+
+			return errorchain.NewWithMessagef(heimdall.ErrInternal, "failed activating rule ID='%s'", rul.ID()).
+				CausedBy(err)
+		}
+	}
+
+	return nil
+}
+
+func deactivate(rules []rule.Rule) {
+	for _, rul := range rules {
+		if la, ok := rul.(lifecycleAware); ok {
+			la.deactivate()
+		}
+	}
+}
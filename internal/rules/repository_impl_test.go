@@ -17,6 +17,7 @@
 package rules
 
 import (
+	"bytes"
 	"net/http"
 	"net/url"
 	"testing"
@@ -275,6 +276,74 @@ func TestRepositoryUpdateRuleSet(t *testing.T) {
 	require.NoError(t, err)
 }
 
+type lifecycleTrackingRule struct {
+	*ruleImpl
+
+	active bool
+}
+
+func (r *lifecycleTrackingRule) activate() error {
+	r.active = true
+
+	return nil
+}
+
+func (r *lifecycleTrackingRule) deactivate() { r.active = false }
+
+func (r *lifecycleTrackingRule) EqualTo(other rule.Rule) bool {
+	return r.ID() == other.ID() &&
+		r.SrcID() == other.SrcID() &&
+		bytes.Equal(r.hash, other.(*lifecycleTrackingRule).hash) // nolint: forcetypeassert
+}
+
+func newLifecycleTrackingRule(id string, hash byte, path string) *lifecycleTrackingRule {
+	rul := &ruleImpl{id: id, srcID: "1", hash: []byte{hash}}
+	rul.routes = append(rul.routes, &routeImpl{rule: rul, path: path})
+
+	return &lifecycleTrackingRule{ruleImpl: rul}
+}
+
+func TestRepositoryRuleLifecycle(t *testing.T) {
+	t.Parallel()
+
+	// GIVEN
+	repo := newRepository(&ruleFactory{}).(*repository) //nolint: forcetypeassert
+
+	rule1 := newLifecycleTrackingRule("1", 1, "/foo/1")
+	rule2 := newLifecycleTrackingRule("2", 1, "/foo/2")
+	rule3 := newLifecycleTrackingRule("3", 1, "/foo/3")
+
+	// WHEN
+	require.NoError(t, repo.AddRuleSet(t.Context(), "1", []rule.Rule{rule1, rule2, rule3}))
+
+	// THEN
+	assert.True(t, rule1.active)
+	assert.True(t, rule2.active)
+	assert.True(t, rule3.active)
+
+	// GIVEN
+	// rule 1 is not changed, rule 2 is changed, rule 3 is deleted
+	updatedRule1 := newLifecycleTrackingRule("1", 1, "/foo/1")
+	updatedRule2 := newLifecycleTrackingRule("2", 2, "/foo/2")
+
+	// WHEN
+	require.NoError(t, repo.UpdateRuleSet(t.Context(), "1", []rule.Rule{updatedRule1, updatedRule2}))
+
+	// THEN
+	assert.True(t, rule1.active)
+	assert.False(t, updatedRule1.active)
+	assert.False(t, rule2.active)
+	assert.True(t, updatedRule2.active)
+	assert.False(t, rule3.active)
+
+	// WHEN
+	require.NoError(t, repo.DeleteRuleSet(t.Context(), "1"))
+
+	// THEN
+	assert.False(t, rule1.active)
+	assert.False(t, updatedRule2.active)
+}
+
 func TestRepositoryFindRule(t *testing.T) {
 	t.Parallel()
 
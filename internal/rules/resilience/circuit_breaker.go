@@ -0,0 +1,161 @@
+// Copyright 2025 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package resilience
+
+import (
+	"sync"
+	"time"
+)
+
+type state int
+
+const (
+	stateClosed state = iota
+	stateHalfOpen
+	stateOpen
+)
+
+func (s state) String() string {
+	switch s {
+	case stateHalfOpen:
+		return "half_open"
+	case stateOpen:
+		return "open"
+	default:
+		return "closed"
+	}
+}
+
+type outcome int
+
+const (
+	outcomeSuccess outcome = iota
+	outcomeFailure
+	// outcomeIgnored is used for requests, which have been canceled by the client
+	// and neither tell anything about the health of the upstream nor shall be counted.
+	outcomeIgnored
+)
+
+// circuitBreaker opens after the configured number of consecutive failures and rejects all
+// requests until the open duration has elapsed. Afterward, it lets a limited number of probe
+// requests pass (half open state). If all of them succeed, it closes again, otherwise it reopens.
+type circuitBreaker struct {
+	threshold        int
+	openDuration     time.Duration
+	halfOpenRequests int
+	onTransition     func(to state)
+
+	mut        sync.Mutex
+	state      state
+	generation uint64
+	failures   int
+	openUntil  time.Time
+	inflight   int
+	successes  int
+}
+
+// allow returns whether the request can be sent to the upstream. If so, the returned generation
+// must be passed to done together with the outcome of the request.
+func (cb *circuitBreaker) allow(now time.Time) (uint64, bool) {
+	cb.mut.Lock()
+	defer cb.mut.Unlock()
+
+	if cb.state == stateOpen {
+		if now.Before(cb.openUntil) {
+			return 0, false
+		}
+
+		cb.transition(stateHalfOpen, now)
+	}
+
+	if cb.state == stateHalfOpen {
+		if cb.inflight >= cb.halfOpenRequests {
+			return 0, false
+		}
+
+		cb.inflight++
+	}
+
+	return cb.generation, true
+}
+
+func (cb *circuitBreaker) done(generation uint64, result outcome, now time.Time) {
+	cb.mut.Lock()
+	defer cb.mut.Unlock()
+
+	// the request has been started before the last state transition
+	// and is irrelevant for the current state
+	if generation != cb.generation {
+		return
+	}
+
+	switch cb.state {
+	case stateHalfOpen:
+		cb.inflight--
+
+		switch result {
+		case outcomeFailure:
+			cb.transition(stateOpen, now)
+		case outcomeSuccess:
+			cb.successes++
+			if cb.successes >= cb.halfOpenRequests {
+				cb.transition(stateClosed, now)
+			}
+		case outcomeIgnored:
+		}
+	case stateClosed:
+		switch result {
+		case outcomeFailure:
+			cb.failures++
+			if cb.failures >= cb.threshold {
+				cb.transition(stateOpen, now)
+			}
+		case outcomeSuccess:
+			cb.failures = 0
+		case outcomeIgnored:
+		}
+	case stateOpen:
+	}
+}
+
+// current returns the state the circuit breaker is effectively in.
+func (cb *circuitBreaker) current(now time.Time) state {
+	cb.mut.Lock()
+	defer cb.mut.Unlock()
+
+	if cb.state == stateOpen && !now.Before(cb.openUntil) {
+		return stateHalfOpen
+	}
+
+	return cb.state
+}
+
+func (cb *circuitBreaker) transition(to state, now time.Time) {
+	cb.state = to
+	cb.generation++
+	cb.failures = 0
+	cb.inflight = 0
+	cb.successes = 0
+
+	if to == stateOpen {
+		cb.openUntil = now.Add(cb.openDuration)
+	}
+
+	if cb.onTransition != nil {
+		cb.onTransition(to)
+	}
+}
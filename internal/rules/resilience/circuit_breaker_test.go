@@ -0,0 +1,93 @@
+// Copyright 2025 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package resilience
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCircuitBreakerStateTransitions(t *testing.T) {
+	t.Parallel()
+
+	// GIVEN
+	var transitions []state
+
+	now := time.Now()
+	cb := &circuitBreaker{
+		threshold:        2,
+		openDuration:     time.Minute,
+		halfOpenRequests: 1,
+		onTransition:     func(to state) { transitions = append(transitions, to) },
+	}
+
+	// WHEN & THEN
+	gen, ok := cb.allow(now)
+	assert.True(t, ok)
+	cb.done(gen, outcomeFailure, now)
+
+	// success resets the number of consecutive failures
+	gen, _ = cb.allow(now)
+	cb.done(gen, outcomeSuccess, now)
+
+	gen, _ = cb.allow(now)
+	cb.done(gen, outcomeFailure, now)
+
+	// canceled requests are not counted
+	gen, _ = cb.allow(now)
+	cb.done(gen, outcomeIgnored, now)
+	assert.Equal(t, stateClosed, cb.current(now))
+
+	// a request started before opening does not affect the state
+	staleGen, _ := cb.allow(now)
+
+	gen, _ = cb.allow(now)
+	cb.done(gen, outcomeFailure, now)
+	assert.Equal(t, stateOpen, cb.current(now))
+
+	cb.done(staleGen, outcomeSuccess, now)
+	assert.Equal(t, stateOpen, cb.current(now))
+
+	_, ok = cb.allow(now.Add(30 * time.Second))
+	assert.False(t, ok)
+
+	// open duration elapsed, a single probe request is allowed
+	now = now.Add(time.Minute)
+	assert.Equal(t, stateHalfOpen, cb.current(now))
+
+	gen, ok = cb.allow(now)
+	assert.True(t, ok)
+
+	_, ok = cb.allow(now)
+	assert.False(t, ok)
+
+	// failed probe reopens the circuit
+	cb.done(gen, outcomeFailure, now)
+	assert.Equal(t, stateOpen, cb.current(now))
+
+	now = now.Add(time.Minute)
+	gen, ok = cb.allow(now)
+	assert.True(t, ok)
+
+	// successful probe closes the circuit
+	cb.done(gen, outcomeSuccess, now)
+	assert.Equal(t, stateClosed, cb.current(now))
+
+	assert.Equal(t, []state{stateOpen, stateHalfOpen, stateOpen, stateHalfOpen, stateClosed}, transitions)
+}
@@ -0,0 +1,108 @@
+// Copyright 2025 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package resilience
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+
+	"github.com/dadrus/heimdall/version"
+)
+
+const (
+	ruleIDAttrKey = attribute.Key("rule_id")
+	stateAttrKey  = attribute.Key("state")
+	resultAttrKey = attribute.Key("result")
+	targetAttrKey = attribute.Key("target")
+)
+
+type metrics struct {
+	meter        metric.Meter
+	retries      metric.Int64Counter
+	transitions  metric.Int64Counter
+	breakerState metric.Int64ObservableGauge
+}
+
+func newMetrics() (*metrics, error) {
+	meter := otel.GetMeterProvider().Meter(
+		"github.com/dadrus/heimdall/internal/rules/resilience",
+		metric.WithInstrumentationVersion(version.Version),
+	)
+
+	retries, err := meter.Int64Counter(
+		"upstream.retries",
+		metric.WithDescription("Number of retries of requests to an upstream service"),
+		metric.WithUnit("{retry}"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	transitions, err := meter.Int64Counter(
+		"upstream.circuit_breaker.transitions",
+		metric.WithDescription("Number of state transitions of the circuit breaker of an upstream service"),
+		metric.WithUnit("{transition}"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	breakerState, err := meter.Int64ObservableGauge(
+		"upstream.circuit_breaker.state",
+		metric.WithDescription("State of the circuit breaker of an upstream service (0 - closed, 1 - half open, 2 - open)"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &metrics{
+		meter:        meter,
+		retries:      retries,
+		transitions:  transitions,
+		breakerState: breakerState,
+	}, nil
+}
+
+func (m *metrics) observeBreakerState(ruleID string, breakers map[string]*circuitBreaker) (metric.Registration, error) {
+	return m.meter.RegisterCallback(
+		func(_ context.Context, observer metric.Observer) error {
+			now := time.Now()
+
+			for host, cb := range breakers {
+				observer.ObserveInt64(m.breakerState, int64(cb.current(now)),
+					metric.WithAttributes(m.attributes(ruleID, host)...))
+			}
+
+			return nil
+		},
+		m.breakerState,
+	)
+}
+
+// attributes returns the attributes identifying a circuit breaker. The target attribute is
+// only present if the circuit breaker is responsible for a particular target.
+func (m *metrics) attributes(ruleID, host string) []attribute.KeyValue {
+	if len(host) == 0 {
+		return []attribute.KeyValue{ruleIDAttrKey.String(ruleID)}
+	}
+
+	return []attribute.KeyValue{ruleIDAttrKey.String(ruleID), targetAttrKey.String(host)}
+}
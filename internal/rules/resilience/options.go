@@ -0,0 +1,37 @@
+// Copyright 2025 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package resilience
+
+import (
+	"github.com/rs/zerolog"
+)
+
+type options struct {
+	logger zerolog.Logger
+}
+
+func newOptions() *options {
+	return &options{logger: zerolog.Nop()}
+}
+
+type Option func(*options)
+
+func WithLogger(logger zerolog.Logger) Option {
+	return func(o *options) {
+		o.logger = logger
+	}
+}
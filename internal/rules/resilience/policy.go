@@ -0,0 +1,333 @@
+// Copyright 2025 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package resilience
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel/metric"
+
+	"github.com/dadrus/heimdall/internal/heimdall"
+	"github.com/dadrus/heimdall/internal/rules/config"
+	"github.com/dadrus/heimdall/internal/x"
+	"github.com/dadrus/heimdall/internal/x/errorchain"
+)
+
+const (
+	defaultInitialBackoff      = 25 * time.Millisecond
+	defaultMaxBackoff          = 250 * time.Millisecond
+	defaultBudgetPercent       = 20
+	defaultBudgetMinConcurrent = 3
+	defaultConsecutiveFailures = 5
+	defaultOpenDuration        = 30 * time.Second
+	defaultHalfOpenRequests    = 1
+)
+
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// Selector allows sending retries of a request to a different upstream target.
+type Selector interface {
+	// Reselect reports the outcome of the previous attempt for the target it has been sent to
+	// and returns the host the next attempt shall be sent to. A zero status code together with
+	// a nil error means, the previous attempt has not been forwarded at all.
+	Reselect(ctx context.Context, statusCode int, err error) string
+}
+
+// Policy implements the retry and circuit breaking behavior configured for a backend.
+type Policy struct {
+	ruleID string
+	retry  *retryPolicy
+	// circuit breakers keyed by the target host. If no targets are configured, there is
+	// a single circuit breaker with an empty key
+	breakers map[string]*circuitBreaker
+	metrics  *metrics
+	logger   zerolog.Logger
+
+	mut          sync.Mutex
+	started      bool
+	registration metric.Registration
+}
+
+func New(ruleID string, conf *config.Backend, opts ...Option) (*Policy, error) {
+	args := newOptions()
+	for _, opt := range opts {
+		opt(args)
+	}
+
+	met, err := newMetrics()
+	if err != nil {
+		return nil, err
+	}
+
+	policy := &Policy{
+		ruleID:  ruleID,
+		metrics: met,
+		logger:  args.logger,
+	}
+
+	if conf.Retry != nil {
+		policy.retry = newRetryPolicy(conf.Retry)
+	}
+
+	if conf.CircuitBreaker != nil {
+		policy.breakers = make(map[string]*circuitBreaker, max(len(conf.Targets), 1))
+
+		if len(conf.Targets) == 0 {
+			policy.breakers[""] = policy.newCircuitBreaker("", conf.CircuitBreaker)
+		}
+
+		// each target has its own circuit breaker to not have a single failing target
+		// cutting off the entire backend
+		for _, tgt := range conf.Targets {
+			policy.breakers[tgt.Host] = policy.newCircuitBreaker(tgt.Host, conf.CircuitBreaker)
+		}
+	}
+
+	return policy, nil
+}
+
+func newRetryPolicy(conf *config.Retry) *retryPolicy {
+	policy := &retryPolicy{
+		maxAttempts:    conf.MaxAttempts,
+		onStatus:       conf.OnStatus,
+		initialBackoff: defaultInitialBackoff,
+		maxBackoff:     defaultMaxBackoff,
+		budget: &retryBudget{
+			percent:       defaultBudgetPercent,
+			minConcurrent: defaultBudgetMinConcurrent,
+		},
+	}
+
+	if len(policy.onStatus) == 0 {
+		policy.onStatus = []int{http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout}
+	}
+
+	if conf.Backoff != nil {
+		policy.initialBackoff = conf.Backoff.Initial.OrDefault(defaultInitialBackoff)
+		policy.maxBackoff = max(conf.Backoff.Max.OrDefault(defaultMaxBackoff), policy.initialBackoff)
+	}
+
+	if conf.Budget != nil {
+		policy.budget.percent = int64(x.IfThenElse(conf.Budget.Percent != 0,
+			conf.Budget.Percent, defaultBudgetPercent))
+		policy.budget.minConcurrent = int64(x.IfThenElse(conf.Budget.MinConcurrent != 0,
+			conf.Budget.MinConcurrent, defaultBudgetMinConcurrent))
+	}
+
+	return policy
+}
+
+func (p *Policy) newCircuitBreaker(host string, conf *config.CircuitBreaker) *circuitBreaker {
+	return &circuitBreaker{
+		threshold: x.IfThenElse(conf.ConsecutiveFailures != 0,
+			conf.ConsecutiveFailures, defaultConsecutiveFailures),
+		openDuration: conf.OpenDuration.OrDefault(defaultOpenDuration),
+		halfOpenRequests: x.IfThenElse(conf.HalfOpenRequests != 0,
+			conf.HalfOpenRequests, defaultHalfOpenRequests),
+		onTransition: func(to state) {
+			p.metrics.transitions.Add(context.Background(), 1, metric.WithAttributes(
+				append(p.metrics.attributes(p.ruleID, host), stateAttrKey.String(to.String()))...))
+
+			p.logger.Info().
+				Str("_rule_id", p.ruleID).
+				Str("_target", host).
+				Str("_state", to.String()).
+				Msg("Upstream circuit breaker state changed")
+		},
+	}
+}
+
+// breakerFor returns the circuit breaker responsible for the given host, or nil if
+// no circuit breaker is configured.
+func (p *Policy) breakerFor(host string) *circuitBreaker {
+	if cb, ok := p.breakers[host]; ok {
+		return cb
+	}
+
+	return p.breakers[""]
+}
+
+// Start starts the export of the circuit breaker state metric.
+func (p *Policy) Start() error {
+	p.mut.Lock()
+	defer p.mut.Unlock()
+
+	if p.started || len(p.breakers) == 0 {
+		return nil
+	}
+
+	registration, err := p.metrics.observeBreakerState(p.ruleID, p.breakers)
+	if err != nil {
+		return err
+	}
+
+	p.registration = registration
+	p.started = true
+
+	return nil
+}
+
+// Stop stops all activities started by Start.
+func (p *Policy) Stop() {
+	p.mut.Lock()
+	defer p.mut.Unlock()
+
+	if !p.started {
+		return
+	}
+
+	_ = p.registration.Unregister()
+	p.started = false
+}
+
+// RoundTripper wraps the given round tripper with the configured retry and circuit breaking
+// behavior. If a selector is given, each retry is sent to the target selected by it. Can be
+// called on a nil Policy, in which case next is returned as is.
+func (p *Policy) RoundTripper(next http.RoundTripper, selector Selector) http.RoundTripper {
+	if p == nil {
+		return next
+	}
+
+	return &roundTripper{policy: p, next: next, selector: selector}
+}
+
+type roundTripper struct {
+	policy   *Policy
+	next     http.RoundTripper
+	selector Selector
+}
+
+func (rt *roundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	retry := rt.policy.retry
+	if retry == nil || !retry.applicable(req) {
+		return rt.attempt(req)
+	}
+
+	retry.budget.requests.Add(1)
+	defer retry.budget.requests.Add(-1)
+
+	ctx := req.Context()
+	resp, err := rt.attempt(req)
+
+	for retries := 1; retries < retry.maxAttempts; retries++ {
+		// an open circuit affects the current target only, if another one can be selected
+		if ctx.Err() != nil || !retry.retryable(resp, err, rt.selector != nil) {
+			break
+		}
+
+		if !retry.budget.acquire() {
+			rt.policy.metrics.retries.Add(ctx, 1, metric.WithAttributes(
+				ruleIDAttrKey.String(rt.policy.ruleID), resultAttrKey.String("budget_exhausted")))
+
+			break
+		}
+
+		statusCode := 0
+
+		if resp != nil {
+			statusCode = resp.StatusCode
+
+			// drain the body to allow reuse of the connection
+			_, _ = io.Copy(io.Discard, resp.Body)
+			_ = resp.Body.Close()
+		}
+
+		resp, err = rt.retry(req, retries, statusCode, err)
+
+		retry.budget.release()
+	}
+
+	return resp, err
+}
+
+func (rt *roundTripper) retry(req *http.Request, retry, statusCode int, prevErr error) (*http.Response, error) {
+	ctx := req.Context()
+
+	if err := wait(ctx, rt.policy.retry.backoff(retry)); err != nil {
+		return nil, err
+	}
+
+	rt.policy.metrics.retries.Add(ctx, 1, metric.WithAttributes(
+		ruleIDAttrKey.String(rt.policy.ruleID), resultAttrKey.String("attempted")))
+
+	zerolog.Ctx(ctx).Debug().
+		Int("_attempt", retry+1).
+		Msg("Retrying upstream request")
+
+	req = req.Clone(ctx)
+
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+
+		req.Body = body
+	}
+
+	if rt.selector != nil {
+		// a request rejected by an open circuit breaker has not been forwarded
+		if errors.Is(prevErr, ErrCircuitOpen) {
+			prevErr = nil
+		}
+
+		host := rt.selector.Reselect(ctx, statusCode, prevErr)
+
+		if req.Host == req.URL.Host {
+			req.Host = host
+		}
+
+		req.URL.Host = host
+	}
+
+	return rt.attempt(req)
+}
+
+func (rt *roundTripper) attempt(req *http.Request) (*http.Response, error) {
+	breaker := rt.policy.breakerFor(req.URL.Host)
+	if breaker == nil {
+		return rt.next.RoundTrip(req)
+	}
+
+	generation, allowed := breaker.allow(time.Now())
+	if !allowed {
+		return nil, errorchain.New(heimdall.ErrCommunication).CausedBy(ErrCircuitOpen)
+	}
+
+	resp, err := rt.next.RoundTrip(req)
+
+	breaker.done(generation, classify(req, resp, err), time.Now())
+
+	return resp, err
+}
+
+func classify(req *http.Request, resp *http.Response, err error) outcome {
+	switch {
+	case err != nil && req.Context().Err() != nil:
+		return outcomeIgnored
+	case err != nil || resp.StatusCode >= http.StatusInternalServerError:
+		return outcomeFailure
+	default:
+		return outcomeSuccess
+	}
+}
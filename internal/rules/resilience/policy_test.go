@@ -0,0 +1,311 @@
+// Copyright 2025 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package resilience
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dadrus/heimdall/internal/heimdall"
+	"github.com/dadrus/heimdall/internal/rules/config"
+)
+
+func TestPolicyRoundTripperOnNilPolicy(t *testing.T) {
+	t.Parallel()
+
+	// GIVEN
+	var policy *Policy
+
+	// WHEN
+	rt := policy.RoundTripper(http.DefaultTransport, nil)
+
+	// THEN
+	assert.Equal(t, http.DefaultTransport, rt)
+}
+
+func TestPolicyRoundTripper(t *testing.T) {
+	t.Parallel()
+
+	backoff := &config.Backoff{Initial: config.Duration(time.Millisecond), Max: config.Duration(2 * time.Millisecond)}
+
+	for uc, tc := range map[string]struct {
+		backend   *config.Backend
+		method    string
+		responses []int
+		configure func(t *testing.T, policy *Policy)
+		requests  int
+		assert    func(t *testing.T, calls int, responses []*http.Response, errs []error)
+	}{
+		"idempotent request is retried until it succeeds": {
+			backend:   &config.Backend{Retry: &config.Retry{MaxAttempts: 3, Backoff: backoff}},
+			method:    http.MethodGet,
+			responses: []int{http.StatusServiceUnavailable, http.StatusBadGateway, http.StatusOK},
+			requests:  1,
+			assert: func(t *testing.T, calls int, responses []*http.Response, errs []error) {
+				t.Helper()
+
+				require.NoError(t, errs[0])
+				assert.Equal(t, http.StatusOK, responses[0].StatusCode)
+				assert.Equal(t, 3, calls)
+			},
+		},
+		"retries are limited by max attempts": {
+			backend:   &config.Backend{Retry: &config.Retry{MaxAttempts: 2, Backoff: backoff}},
+			method:    http.MethodGet,
+			responses: []int{http.StatusServiceUnavailable, http.StatusServiceUnavailable, http.StatusOK},
+			requests:  1,
+			assert: func(t *testing.T, calls int, responses []*http.Response, errs []error) {
+				t.Helper()
+
+				require.NoError(t, errs[0])
+				assert.Equal(t, http.StatusServiceUnavailable, responses[0].StatusCode)
+				assert.Equal(t, 2, calls)
+			},
+		},
+		"only configured status codes are retried": {
+			backend: &config.Backend{Retry: &config.Retry{
+				MaxAttempts: 3, OnStatus: []int{http.StatusInternalServerError}, Backoff: backoff,
+			}},
+			method:    http.MethodGet,
+			responses: []int{http.StatusServiceUnavailable, http.StatusOK},
+			requests:  1,
+			assert: func(t *testing.T, calls int, responses []*http.Response, errs []error) {
+				t.Helper()
+
+				require.NoError(t, errs[0])
+				assert.Equal(t, http.StatusServiceUnavailable, responses[0].StatusCode)
+				assert.Equal(t, 1, calls)
+			},
+		},
+		"not idempotent request is not retried": {
+			backend:   &config.Backend{Retry: &config.Retry{MaxAttempts: 3, Backoff: backoff}},
+			method:    http.MethodPost,
+			responses: []int{http.StatusServiceUnavailable, http.StatusOK},
+			requests:  1,
+			assert: func(t *testing.T, calls int, responses []*http.Response, errs []error) {
+				t.Helper()
+
+				require.NoError(t, errs[0])
+				assert.Equal(t, http.StatusServiceUnavailable, responses[0].StatusCode)
+				assert.Equal(t, 1, calls)
+			},
+		},
+		"retry budget is exhausted": {
+			backend:   &config.Backend{Retry: &config.Retry{MaxAttempts: 3, Backoff: backoff}},
+			method:    http.MethodGet,
+			responses: []int{http.StatusServiceUnavailable, http.StatusOK},
+			configure: func(t *testing.T, policy *Policy) {
+				t.Helper()
+
+				// simulate other requests being retried right now
+				policy.retry.budget.retries.Store(defaultBudgetMinConcurrent)
+			},
+			requests: 1,
+			assert: func(t *testing.T, calls int, responses []*http.Response, errs []error) {
+				t.Helper()
+
+				require.NoError(t, errs[0])
+				assert.Equal(t, http.StatusServiceUnavailable, responses[0].StatusCode)
+				assert.Equal(t, 1, calls)
+			},
+		},
+		"open circuit breaker fails fast": {
+			backend: &config.Backend{CircuitBreaker: &config.CircuitBreaker{
+				ConsecutiveFailures: 2, OpenDuration: config.Duration(time.Minute),
+			}},
+			method:    http.MethodGet,
+			responses: []int{http.StatusInternalServerError, http.StatusInternalServerError, http.StatusOK},
+			requests:  3,
+			assert: func(t *testing.T, calls int, responses []*http.Response, errs []error) {
+				t.Helper()
+
+				require.NoError(t, errs[0])
+				require.NoError(t, errs[1])
+				assert.Equal(t, http.StatusInternalServerError, responses[0].StatusCode)
+				assert.Equal(t, http.StatusInternalServerError, responses[1].StatusCode)
+
+				require.Error(t, errs[2])
+				require.ErrorIs(t, errs[2], heimdall.ErrCommunication)
+				require.ErrorIs(t, errs[2], ErrCircuitOpen)
+				assert.Equal(t, 2, calls)
+			},
+		},
+		"retries are counted by the circuit breaker and are not done if it is open": {
+			backend: &config.Backend{
+				Retry:          &config.Retry{MaxAttempts: 3, Backoff: backoff},
+				CircuitBreaker: &config.CircuitBreaker{ConsecutiveFailures: 2},
+			},
+			method:    http.MethodGet,
+			responses: []int{http.StatusServiceUnavailable, http.StatusServiceUnavailable, http.StatusOK},
+			requests:  1,
+			assert: func(t *testing.T, calls int, _ []*http.Response, errs []error) {
+				t.Helper()
+
+				require.Error(t, errs[0])
+				require.ErrorIs(t, errs[0], ErrCircuitOpen)
+				assert.Equal(t, 2, calls)
+			},
+		},
+	} {
+		t.Run(uc, func(t *testing.T) {
+			t.Parallel()
+
+			// GIVEN
+			var calls atomic.Int32
+
+			srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+				idx := int(calls.Add(1)) - 1
+
+				rw.WriteHeader(tc.responses[min(idx, len(tc.responses)-1)])
+			}))
+			defer srv.Close()
+
+			policy, err := New("test", tc.backend)
+			require.NoError(t, err)
+
+			if tc.configure != nil {
+				tc.configure(t, policy)
+			}
+
+			client := &http.Client{Transport: policy.RoundTripper(http.DefaultTransport, nil)}
+
+			var (
+				responses []*http.Response
+				errs      []error
+			)
+
+			// WHEN
+			for range tc.requests {
+				req, err := http.NewRequestWithContext(t.Context(), tc.method, srv.URL, strings.NewReader(""))
+				require.NoError(t, err)
+
+				resp, err := client.Do(req)
+				if resp != nil {
+					resp.Body.Close()
+				}
+
+				responses = append(responses, resp)
+				errs = append(errs, err)
+			}
+
+			// THEN
+			tc.assert(t, int(calls.Load()), responses, errs)
+		})
+	}
+}
+
+type hostSelector struct {
+	hosts    []string
+	outcomes []int
+}
+
+func (s *hostSelector) Reselect(_ context.Context, statusCode int, _ error) string {
+	host := s.hosts[len(s.outcomes)%len(s.hosts)]
+	s.outcomes = append(s.outcomes, statusCode)
+
+	return host
+}
+
+func TestPolicyRoundTripperWithSelector(t *testing.T) {
+	t.Parallel()
+
+	// GIVEN
+	var callsA, callsB atomic.Int32
+
+	srvA := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+		callsA.Add(1)
+		rw.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srvA.Close()
+
+	srvB := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+		callsB.Add(1)
+		rw.WriteHeader(http.StatusOK)
+	}))
+	defer srvB.Close()
+
+	urlA, err := url.Parse(srvA.URL)
+	require.NoError(t, err)
+
+	urlB, err := url.Parse(srvB.URL)
+	require.NoError(t, err)
+
+	policy, err := New("test", &config.Backend{
+		Targets: []config.Target{{Host: urlA.Host}, {Host: urlB.Host}},
+		Retry: &config.Retry{
+			MaxAttempts: 2,
+			Backoff:     &config.Backoff{Initial: config.Duration(time.Millisecond), Max: config.Duration(time.Millisecond)},
+		},
+		CircuitBreaker: &config.CircuitBreaker{ConsecutiveFailures: 1, OpenDuration: config.Duration(time.Minute)},
+	})
+	require.NoError(t, err)
+
+	selector := &hostSelector{hosts: []string{urlB.Host}}
+	client := &http.Client{Transport: policy.RoundTripper(http.DefaultTransport, selector)}
+
+	for range 2 {
+		req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, srvA.URL, nil)
+		require.NoError(t, err)
+
+		// WHEN
+		resp, err := client.Do(req)
+
+		// THEN the retry is sent to the reselected target
+		require.NoError(t, err)
+		resp.Body.Close()
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	}
+
+	// the circuit of the first target opened after the first failure and does
+	// neither affect the other target, nor prevent the retry on the other target
+	assert.Equal(t, int32(1), callsA.Load())
+	assert.Equal(t, int32(2), callsB.Load())
+	assert.Equal(t, []int{http.StatusServiceUnavailable, 0}, selector.outcomes)
+	assert.Equal(t, stateOpen, policy.breakers[urlA.Host].current(time.Now()))
+	assert.Equal(t, stateClosed, policy.breakers[urlB.Host].current(time.Now()))
+}
+
+func TestPolicyStartStop(t *testing.T) {
+	t.Parallel()
+
+	// GIVEN
+	policy, err := New("test", &config.Backend{CircuitBreaker: &config.CircuitBreaker{}})
+	require.NoError(t, err)
+
+	// WHEN & THEN
+	require.NoError(t, policy.Start())
+	require.NoError(t, policy.Start())
+	assert.True(t, policy.started)
+
+	policy.Stop()
+	policy.Stop()
+	assert.False(t, policy.started)
+
+	assert.Equal(t, defaultConsecutiveFailures, policy.breakers[""].threshold)
+	assert.Equal(t, defaultOpenDuration, policy.breakers[""].openDuration)
+	assert.Equal(t, defaultHalfOpenRequests, policy.breakers[""].halfOpenRequests)
+}
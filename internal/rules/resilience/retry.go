@@ -0,0 +1,111 @@
+// Copyright 2025 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package resilience
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"net/http"
+	"slices"
+	"sync/atomic"
+	"time"
+)
+
+var idempotentMethods = []string{ //nolint:gochecknoglobals
+	http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete,
+}
+
+type retryPolicy struct {
+	maxAttempts    int
+	onStatus       []int
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+	budget         *retryBudget
+}
+
+// applicable returns true if the given request can be sent again. That is the case for
+// idempotent requests, which either have no body, or a body, which can be recreated.
+func (p *retryPolicy) applicable(req *http.Request) bool {
+	idempotent := slices.Contains(idempotentMethods, req.Method) ||
+		len(req.Header.Get("Idempotency-Key")) != 0 ||
+		len(req.Header.Get("X-Idempotency-Key")) != 0
+
+	return idempotent && (req.Body == nil || req.Body == http.NoBody || req.GetBody != nil)
+}
+
+// retryable returns true if the outcome of an attempt allows a retry. Requests rejected by an
+// open circuit breaker are only retried if the retry can be sent to another target.
+func (p *retryPolicy) retryable(resp *http.Response, err error, reselectable bool) bool {
+	if err != nil {
+		return !errors.Is(err, context.Canceled) &&
+			!errors.Is(err, context.DeadlineExceeded) &&
+			(reselectable || !errors.Is(err, ErrCircuitOpen))
+	}
+
+	return slices.Contains(p.onStatus, resp.StatusCode)
+}
+
+// backoff returns the time to wait before the given retry attempt (starting with 1).
+// It grows exponentially and is jittered to avoid retry storms.
+func (p *retryPolicy) backoff(retry int) time.Duration {
+	delay := p.maxBackoff
+	if shift := retry - 1; shift < 32 && p.initialBackoff<<shift < p.maxBackoff { //nolint:mnd
+		delay = p.initialBackoff << shift
+	}
+
+	half := delay / 2 //nolint:mnd
+
+	return half + rand.N(half+1) //nolint:gosec
+}
+
+func wait(ctx context.Context, delay time.Duration) error {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// retryBudget limits the number of concurrent retries to a percentage of the in-flight
+// requests, but allows at least minConcurrent retries. This prevents retries from
+// amplifying the load on an upstream, which is already in trouble.
+type retryBudget struct {
+	percent       int64
+	minConcurrent int64
+
+	requests atomic.Int64
+	retries  atomic.Int64
+}
+
+func (b *retryBudget) acquire() bool {
+	allowed := max(b.minConcurrent, b.requests.Load()*b.percent/100) //nolint:mnd
+
+	if b.retries.Add(1) > allowed {
+		b.retries.Add(-1)
+
+		return false
+	}
+
+	return true
+}
+
+func (b *retryBudget) release() { b.retries.Add(-1) }
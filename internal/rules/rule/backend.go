@@ -17,7 +17,13 @@
 package rule
 
 import (
+	"context"
+	"crypto/tls"
+	"net/http"
 	"net/url"
+	"sync"
+	"sync/atomic"
+	"time"
 )
 
 //go:generate mockery --name Backend --structname BackendMock
@@ -25,4 +31,71 @@ import (
 type Backend interface {
 	URL() *url.URL
 	ForwardHostHeader() bool
+	TransportSettings() *TransportSettings
+	// RoundTripper wraps the given round tripper with the retry and circuit breaking
+	// behavior configured for the backend.
+	RoundTripper(next http.RoundTripper) http.RoundTripper
+	// Done must be called as soon as the request to the backend has been completed, or if
+	// it has not been sent at all. statusCode is 0 if no response has been received, err is
+	// set if the communication with the backend failed.
+	Done(ctx context.Context, statusCode int, err error)
+}
+
+// TransportSettings describe how connections to a backend shall be established.
+// Each backend defining these has its own transport, which is replaced as soon as
+// the TLS configuration changes, e.g. due to a renewed key or trust store.
+type TransportSettings struct {
+	MaxPerHost      int
+	MaxIdle         int
+	MaxIdlePerHost  int
+	ConnectTimeout  time.Duration
+	ResponseTimeout time.Duration
+
+	tlsConfig atomic.Pointer[tls.Config]
+
+	mut          sync.Mutex
+	transport    *http.Transport
+	transportTLS *tls.Config
+}
+
+// TLSConfig returns the current TLS configuration, if any.
+func (s *TransportSettings) TLSConfig() *tls.Config { return s.tlsConfig.Load() }
+
+// SetTLSConfig replaces the TLS configuration. A transport created with the previous
+// configuration is replaced on its next use.
+func (s *TransportSettings) SetTLSConfig(cfg *tls.Config) { s.tlsConfig.Store(cfg) }
+
+// Transport returns the transport for the backend. It is created using the given function
+// on first use and recreated if the TLS configuration has changed since. Idle connections
+// of a replaced transport are closed.
+func (s *TransportSettings) Transport(create func(settings *TransportSettings) *http.Transport) *http.Transport {
+	cfg := s.TLSConfig()
+
+	s.mut.Lock()
+	defer s.mut.Unlock()
+
+	if s.transport != nil && s.transportTLS == cfg {
+		return s.transport
+	}
+
+	if s.transport != nil {
+		s.transport.CloseIdleConnections()
+	}
+
+	s.transport = create(s)
+	s.transportTLS = cfg
+
+	return s.transport
+}
+
+// Close closes the idle connections of the transport created so far. It is called as
+// soon as the backend is not used anymore.
+func (s *TransportSettings) Close() {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+
+	if s.transport != nil {
+		s.transport.CloseIdleConnections()
+		s.transport = nil
+	}
 }
@@ -3,8 +3,14 @@
 package mocks
 
 import (
+	context "context"
+
 	mock "github.com/stretchr/testify/mock"
 
+	http "net/http"
+
+	rule "github.com/dadrus/heimdall/internal/rules/rule"
+
 	url "net/url"
 )
 
@@ -21,6 +27,45 @@ func (_m *BackendMock) EXPECT() *BackendMock_Expecter {
 	return &BackendMock_Expecter{mock: &_m.Mock}
 }
 
+// Done provides a mock function with given fields: ctx, statusCode, err
+func (_m *BackendMock) Done(ctx context.Context, statusCode int, err error) {
+	_m.Called(ctx, statusCode, err)
+}
+
+// BackendMock_Done_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Done'
+type BackendMock_Done_Call struct {
+	*mock.Call
+}
+
+// Done is a helper method to define mock.On call
+//   - ctx context.Context
+//   - statusCode int
+//   - err error
+func (_e *BackendMock_Expecter) Done(ctx interface{}, statusCode interface{}, err interface{}) *BackendMock_Done_Call {
+	return &BackendMock_Done_Call{Call: _e.mock.On("Done", ctx, statusCode, err)}
+}
+
+func (_c *BackendMock_Done_Call) Run(run func(ctx context.Context, statusCode int, err error)) *BackendMock_Done_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg2 error
+		if args[2] != nil {
+			arg2 = args[2].(error)
+		}
+		run(args[0].(context.Context), args[1].(int), arg2)
+	})
+	return _c
+}
+
+func (_c *BackendMock_Done_Call) Return() *BackendMock_Done_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *BackendMock_Done_Call) RunAndReturn(run func(context.Context, int, error)) *BackendMock_Done_Call {
+	_c.Run(run)
+	return _c
+}
+
 // ForwardHostHeader provides a mock function with no fields
 func (_m *BackendMock) ForwardHostHeader() bool {
 	ret := _m.Called()
@@ -66,6 +111,105 @@ func (_c *BackendMock_ForwardHostHeader_Call) RunAndReturn(run func() bool) *Bac
 	return _c
 }
 
+// RoundTripper provides a mock function with given fields: next
+func (_m *BackendMock) RoundTripper(next http.RoundTripper) http.RoundTripper {
+	ret := _m.Called(next)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RoundTripper")
+	}
+
+	var r0 http.RoundTripper
+	if rf, ok := ret.Get(0).(func(http.RoundTripper) http.RoundTripper); ok {
+		r0 = rf(next)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(http.RoundTripper)
+		}
+	}
+
+	return r0
+}
+
+// BackendMock_RoundTripper_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RoundTripper'
+type BackendMock_RoundTripper_Call struct {
+	*mock.Call
+}
+
+// RoundTripper is a helper method to define mock.On call
+//   - next http.RoundTripper
+func (_e *BackendMock_Expecter) RoundTripper(next interface{}) *BackendMock_RoundTripper_Call {
+	return &BackendMock_RoundTripper_Call{Call: _e.mock.On("RoundTripper", next)}
+}
+
+func (_c *BackendMock_RoundTripper_Call) Run(run func(next http.RoundTripper)) *BackendMock_RoundTripper_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 http.RoundTripper
+		if args[0] != nil {
+			arg0 = args[0].(http.RoundTripper)
+		}
+		run(arg0)
+	})
+	return _c
+}
+
+func (_c *BackendMock_RoundTripper_Call) Return(_a0 http.RoundTripper) *BackendMock_RoundTripper_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *BackendMock_RoundTripper_Call) RunAndReturn(run func(http.RoundTripper) http.RoundTripper) *BackendMock_RoundTripper_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// TransportSettings provides a mock function with no fields
+func (_m *BackendMock) TransportSettings() *rule.TransportSettings {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for TransportSettings")
+	}
+
+	var r0 *rule.TransportSettings
+	if rf, ok := ret.Get(0).(func() *rule.TransportSettings); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*rule.TransportSettings)
+		}
+	}
+
+	return r0
+}
+
+// BackendMock_TransportSettings_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'TransportSettings'
+type BackendMock_TransportSettings_Call struct {
+	*mock.Call
+}
+
+// TransportSettings is a helper method to define mock.On call
+func (_e *BackendMock_Expecter) TransportSettings() *BackendMock_TransportSettings_Call {
+	return &BackendMock_TransportSettings_Call{Call: _e.mock.On("TransportSettings")}
+}
+
+func (_c *BackendMock_TransportSettings_Call) Run(run func()) *BackendMock_TransportSettings_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *BackendMock_TransportSettings_Call) Return(_a0 *rule.TransportSettings) *BackendMock_TransportSettings_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *BackendMock_TransportSettings_Call) RunAndReturn(run func() *rule.TransportSettings) *BackendMock_TransportSettings_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // URL provides a mock function with no fields
 func (_m *BackendMock) URL() *url.URL {
 	ret := _m.Called()
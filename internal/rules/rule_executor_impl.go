@@ -19,6 +19,7 @@ package rules
 import (
 	"github.com/rs/zerolog"
 
+	"github.com/dadrus/heimdall/internal/accesscontext"
 	"github.com/dadrus/heimdall/internal/heimdall"
 	"github.com/dadrus/heimdall/internal/rules/rule"
 )
@@ -44,5 +45,7 @@ func (e *ruleExecutor) Execute(ctx heimdall.RequestContext) (rule.Backend, error
 		return nil, err
 	}
 
+	accesscontext.SetRuleID(ctx.Context(), rul.ID())
+
 	return rul.Execute(ctx)
 }
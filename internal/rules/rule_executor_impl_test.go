@@ -65,6 +65,7 @@ func TestRuleExecutorExecute(t *testing.T) {
 				ctx.EXPECT().Context().Return(t.Context())
 				ctx.EXPECT().Request().Return(req)
 				repo.EXPECT().FindRule(ctx).Return(rule, nil)
+				rule.EXPECT().ID().Return("foo")
 				rule.EXPECT().Execute(ctx).Return(nil, heimdall.ErrAuthentication)
 			},
 		},
@@ -79,6 +80,7 @@ func TestRuleExecutorExecute(t *testing.T) {
 				ctx.EXPECT().Context().Return(t.Context())
 				ctx.EXPECT().Request().Return(req)
 				repo.EXPECT().FindRule(ctx).Return(rule, nil)
+				rule.EXPECT().ID().Return("foo")
 				rule.EXPECT().Execute(ctx).Return(upstream, nil)
 			},
 		},
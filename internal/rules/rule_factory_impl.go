@@ -19,20 +19,27 @@ package rules
 import (
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/rs/zerolog"
 
 	"github.com/dadrus/heimdall/internal/config"
+	"github.com/dadrus/heimdall/internal/geoip"
 	"github.com/dadrus/heimdall/internal/heimdall"
 	config2 "github.com/dadrus/heimdall/internal/rules/config"
+	"github.com/dadrus/heimdall/internal/rules/loadbalancer"
 	"github.com/dadrus/heimdall/internal/rules/mechanisms"
+	"github.com/dadrus/heimdall/internal/rules/resilience"
 	"github.com/dadrus/heimdall/internal/rules/rule"
+	"github.com/dadrus/heimdall/internal/watcher"
 	"github.com/dadrus/heimdall/internal/x"
 	"github.com/dadrus/heimdall/internal/x/errorchain"
 )
 
 func NewRuleFactory(
 	hf mechanisms.MechanismFactory,
+	geo geoip.Resolver,
+	cw watcher.Watcher,
 	conf *config.Configuration,
 	mode config.OperationMode,
 	logger zerolog.Logger,
@@ -42,6 +49,8 @@ func NewRuleFactory(
 
 	rf := &ruleFactory{
 		hf:                hf,
+		geo:               geo,
+		cw:                cw,
 		hasDefaultRule:    false,
 		secureDefaultRule: bool(sdr),
 		logger:            logger,
@@ -59,6 +68,8 @@ func NewRuleFactory(
 
 type ruleFactory struct {
 	hf                  mechanisms.MechanismFactory
+	geo                 geoip.Resolver
+	cw                  watcher.Watcher
 	logger              zerolog.Logger
 	defaultRule         *ruleImpl
 	hasDefaultRule      bool
@@ -81,7 +92,7 @@ func (f *ruleFactory) CreateRule(version, srcID string, ruleConfig config2.Rule)
 		config2.EncodedSlashesOff,
 	)
 
-	authenticators, subHandlers, finalizers, err := f.createExecutePipeline(version, ruleConfig.Execute)
+	authenticators, subHandlers, finalizers, err := f.createExecutePipeline(version, ruleConfig.ID, ruleConfig.Execute)
 	if err != nil {
 		return nil, err
 	}
@@ -112,12 +123,37 @@ func (f *ruleFactory) CreateRule(version, srcID string, ruleConfig config2.Rule)
 		return nil, err
 	}
 
+	transportSettings, err := newTransportSettings(ruleConfig.Backend)
+	if err != nil {
+		return nil, err
+	}
+
+	balancer, err := f.createBalancer(ruleConfig.ID, ruleConfig.Backend, transportSettings)
+	if err != nil {
+		return nil, err
+	}
+
+	policy, err := f.createResiliencePolicy(ruleConfig.ID, ruleConfig.Backend)
+	if err != nil {
+		return nil, err
+	}
+
+	urlRewriter, err := newURLRewriter(ruleConfig.Backend)
+	if err != nil {
+		return nil, err
+	}
+
 	rul := &ruleImpl{
 		id:                 ruleConfig.ID,
 		srcID:              srcID,
 		slashesHandling:    slashesHandling,
 		allowsBacktracking: allowsBacktracking,
 		backend:            ruleConfig.Backend,
+		transportSettings:  transportSettings,
+		tlsReloader:        f.createTLSReloader(ruleConfig.Backend, transportSettings),
+		balancer:           balancer,
+		policy:             policy,
+		urlRewriter:        urlRewriter,
 		hash:               hash,
 		sc:                 authenticators,
 		sh:                 subHandlers,
@@ -156,9 +192,59 @@ func (f *ruleFactory) CreateRule(version, srcID string, ruleConfig config2.Rule)
 	return rul, nil
 }
 
+func (f *ruleFactory) createBalancer(
+	ruleID string,
+	conf *config2.Backend,
+	settings *rule.TransportSettings,
+) (*loadbalancer.Balancer, error) {
+	// load balancing is only relevant if requests are forwarded by heimdall
+	if f.mode != config.ProxyMode || conf == nil || len(conf.Targets) == 0 {
+		return nil, nil //nolint:nilnil
+	}
+
+	var opts []loadbalancer.Option
+
+	if settings != nil {
+		opts = append(opts, loadbalancer.WithTLSConfig(settings.TLSConfig))
+	}
+
+	balancer, err := loadbalancer.New(ruleID, conf, append(opts, loadbalancer.WithLogger(f.logger))...)
+	if err != nil {
+		return nil, errorchain.NewWithMessage(heimdall.ErrInternal,
+			"failed creating load balancer").CausedBy(err)
+	}
+
+	return balancer, nil
+}
+
+func (f *ruleFactory) createTLSReloader(conf *config2.Backend, settings *rule.TransportSettings) *tlsReloader {
+	// transport settings are only relevant if requests are forwarded by heimdall
+	if f.mode != config.ProxyMode || conf == nil {
+		return nil
+	}
+
+	return newTLSReloader(conf.TLS, settings, f.cw)
+}
+
+func (f *ruleFactory) createResiliencePolicy(ruleID string, conf *config2.Backend) (*resilience.Policy, error) {
+	// retries and circuit breaking are only relevant if requests are forwarded by heimdall
+	if f.mode != config.ProxyMode || !conf.HasResiliencePolicy() {
+		return nil, nil //nolint:nilnil
+	}
+
+	policy, err := resilience.New(ruleID, conf, resilience.WithLogger(f.logger))
+	if err != nil {
+		return nil, errorchain.NewWithMessage(heimdall.ErrInternal,
+			"failed creating resilience policy").CausedBy(err)
+	}
+
+	return policy, nil
+}
+
 //nolint:funlen,gocognit,cyclop
 func (f *ruleFactory) createExecutePipeline(
 	version string,
+	ruleID string,
 	pipeline []config.MechanismConfig,
 ) (compositeSubjectCreator, compositeSubjectHandler, compositeSubjectHandler, error) {
 	var (
@@ -185,6 +271,15 @@ func (f *ruleFactory) createExecutePipeline(
 		return nil
 	}
 
+	groupsCheck := func() error {
+		if len(finalizers) != 0 {
+			return errorchain.NewWithMessage(heimdall.ErrConfiguration,
+				"at least one finalizer is defined before a group")
+		}
+
+		return nil
+	}
+
 	finalizersCheck := func() error { return nil }
 
 	for _, pipelineStep := range pipeline {
@@ -209,7 +304,16 @@ func (f *ruleFactory) createExecutePipeline(
 			continue
 		}
 
-		handler, err := createHandler(version, "authorizer", pipelineStep, authorizersCheck,
+		handler, err := f.createGroupHandler(version, ruleID, pipelineStep, groupsCheck)
+		if err != nil && !errors.Is(err, errHandlerNotFound) {
+			return nil, nil, nil, err
+		} else if handler != nil {
+			subjectHandlers = append(subjectHandlers, handler)
+
+			continue
+		}
+
+		handler, err = createHandler(f.geo, version, ruleID, "authorizer", pipelineStep, authorizersCheck,
 			f.hf.CreateAuthorizer)
 		if err != nil && !errors.Is(err, errHandlerNotFound) {
 			return nil, nil, nil, err
@@ -219,7 +323,7 @@ func (f *ruleFactory) createExecutePipeline(
 			continue
 		}
 
-		handler, err = createHandler(version, "contextualizer", pipelineStep, contextualizersCheck,
+		handler, err = createHandler(f.geo, version, ruleID, "contextualizer", pipelineStep, contextualizersCheck,
 			f.hf.CreateContextualizer)
 		if err != nil && !errors.Is(err, errHandlerNotFound) {
 			return nil, nil, nil, err
@@ -229,7 +333,7 @@ func (f *ruleFactory) createExecutePipeline(
 			continue
 		}
 
-		handler, err = createHandler(version, "finalizer", pipelineStep, finalizersCheck,
+		handler, err = createHandler(f.geo, version, ruleID, "finalizer", pipelineStep, finalizersCheck,
 			f.hf.CreateFinalizer)
 		if err != nil && !errors.Is(err, errHandlerNotFound) {
 			return nil, nil, nil, err
@@ -257,7 +361,7 @@ func (f *ruleFactory) createOnErrorPipeline(
 		if found {
 			conf := getConfig(ehStep["config"])
 
-			condition, err := getExecutionCondition(ehStep["if"])
+			condition, err := getExecutionCondition(ehStep["if"], f.geo)
 			if err != nil {
 				return nil, err
 			}
@@ -290,6 +394,7 @@ func (f *ruleFactory) initWithDefaultRule(ruleConfig *config.DefaultRule, logger
 
 	authenticators, subHandlers, finalizers, err := f.createExecutePipeline(
 		config2.CurrentRuleSetVersion,
+		"default",
 		ruleConfig.Execute,
 	)
 	if err != nil {
@@ -333,12 +438,174 @@ func (f *ruleFactory) initWithDefaultRule(ruleConfig *config.DefaultRule, logger
 	return nil
 }
 
+// createGroupHandler creates an any_of, an all_of, or a parallel group of authorizers, contextualizers,
+// or other groups. Parallel groups can hold contextualizers only.
+//
+//nolint:cyclop,funlen
+func (f *ruleFactory) createGroupHandler(
+	version string,
+	ruleID string,
+	configMap map[string]any,
+	check CheckFunc,
+) (subjectHandler, error) {
+	var (
+		groupType string
+		rawSteps  any
+	)
+
+	for _, typ := range []string{groupTypeAnyOf, groupTypeAllOf, groupTypeParallel} {
+		if steps, found := configMap[typ]; found {
+			if len(groupType) != 0 {
+				return nil, errorchain.NewWithMessagef(heimdall.ErrConfiguration,
+					"%s and %s cannot be used in the same pipeline step", groupType, typ)
+			}
+
+			groupType, rawSteps = typ, steps
+		}
+	}
+
+	if len(groupType) == 0 {
+		return nil, errHandlerNotFound
+	}
+
+	if err := check(); err != nil {
+		return nil, err
+	}
+
+	if _, found := configMap["enforce"]; found {
+		return nil, errorchain.NewWithMessagef(heimdall.ErrConfiguration, "enforce is not supported for %s", groupType)
+	}
+
+	condition, err := getExecutionCondition(configMap["if"], f.geo)
+	if err != nil {
+		return nil, err
+	}
+
+	timeout, err := getGroupTimeout(groupType, configMap["timeout"])
+	if err != nil {
+		return nil, err
+	}
+
+	steps, err := getGroupSteps(groupType, rawSteps)
+	if err != nil {
+		return nil, err
+	}
+
+	noCheck := func() error { return nil }
+	handlers := make([]*conditionalSubjectHandler, 0, len(steps))
+
+	for _, step := range steps {
+		if _, found := step["enforce"]; found {
+			return nil, errorchain.NewWithMessagef(heimdall.ErrConfiguration,
+				"enforce is not supported for steps of %s", groupType)
+		}
+
+		var handler subjectHandler
+
+		err = errHandlerNotFound
+
+		// parallel groups are meant for independent contextualizers only
+		if groupType != groupTypeParallel {
+			handler, err = f.createGroupHandler(version, ruleID, step, noCheck)
+			if errors.Is(err, errHandlerNotFound) {
+				handler, err = createHandler(f.geo, version, ruleID, "authorizer", step, noCheck, f.hf.CreateAuthorizer)
+			}
+		}
+
+		if errors.Is(err, errHandlerNotFound) {
+			handler, err = createHandler(f.geo, version, ruleID, "contextualizer", step, noCheck, f.hf.CreateContextualizer)
+		}
+
+		if errors.Is(err, errHandlerNotFound) {
+			return nil, errorchain.NewWithMessagef(heimdall.ErrConfiguration,
+				"unsupported configuration in %s", groupType)
+		} else if err != nil {
+			return nil, err
+		}
+
+		conditional, ok := handler.(*conditionalSubjectHandler)
+		if !ok {
+			return nil, errorchain.NewWithMessagef(heimdall.ErrConfiguration,
+				"not enforced authorizer '%s' cannot be used in %s", handler.ID(), groupType)
+		}
+
+		handlers = append(handlers, conditional)
+	}
+
+	if groupType == groupTypeParallel {
+		return &conditionalSubjectHandler{
+			h: &parallelSubjectHandler{handlers: handlers, timeout: timeout},
+			c: condition,
+		}, nil
+	}
+
+	return &conditionalSubjectHandler{h: &groupSubjectHandler{typ: groupType, handlers: handlers}, c: condition}, nil
+}
+
+func getGroupTimeout(groupType string, conf any) (time.Duration, error) {
+	if conf == nil {
+		return 0, nil
+	}
+
+	if groupType != groupTypeParallel {
+		return 0, errorchain.NewWithMessagef(heimdall.ErrConfiguration, "timeout is not supported for %s", groupType)
+	}
+
+	value, ok := conf.(string)
+	if !ok {
+		return 0, errorchain.NewWithMessagef(heimdall.ErrConfiguration,
+			"unexpected type '%T' for the timeout of %s", conf, groupType)
+	}
+
+	timeout, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, errorchain.NewWithMessagef(heimdall.ErrConfiguration,
+			"failed parsing timeout of %s", groupType).CausedBy(err)
+	}
+
+	return timeout, nil
+}
+
+func getGroupSteps(groupType string, conf any) ([]config.MechanismConfig, error) {
+	var steps []config.MechanismConfig
+
+	switch typed := conf.(type) {
+	case []config.MechanismConfig:
+		steps = typed
+	case []map[string]any:
+		for _, step := range typed {
+			steps = append(steps, step)
+		}
+	case []any:
+		for _, step := range typed {
+			values, ok := step.(map[string]any)
+			if !ok {
+				return nil, errorchain.NewWithMessagef(heimdall.ErrConfiguration,
+					"unexpected type '%T' for a step of %s", step, groupType)
+			}
+
+			steps = append(steps, values)
+		}
+	default:
+		return nil, errorchain.NewWithMessagef(heimdall.ErrConfiguration,
+			"unexpected type '%T' for %s", conf, groupType)
+	}
+
+	if len(steps) == 0 {
+		return nil, errorchain.NewWithMessagef(heimdall.ErrConfiguration, "empty %s", groupType)
+	}
+
+	return steps, nil
+}
+
 type CheckFunc func() error
 
 var errHandlerNotFound = errors.New("handler not found")
 
 func createHandler[T subjectHandler](
+	geo geoip.Resolver,
 	version string,
+	ruleID string,
 	handlerType string,
 	configMap map[string]any,
 	check CheckFunc,
@@ -353,7 +620,7 @@ func createHandler[T subjectHandler](
 		return nil, err
 	}
 
-	condition, err := getExecutionCondition(configMap["if"])
+	condition, err := getExecutionCondition(configMap["if"], geo)
 	if err != nil {
 		return nil, err
 	}
@@ -363,9 +630,51 @@ func createHandler[T subjectHandler](
 		return nil, err
 	}
 
+	enforced, err := isEnforced(handlerType, handler, configMap["enforce"])
+	if err != nil {
+		return nil, err
+	}
+
+	if !enforced {
+		shadowed, err := newShadowSubjectHandler(ruleID, &conditionalSubjectHandler{h: handler, c: condition})
+		if err != nil {
+			return nil, err
+		}
+
+		return shadowed, nil
+	}
+
 	return &conditionalSubjectHandler{h: handler, c: condition}, nil
 }
 
+// isEnforced determines whether the outcome of the given handler is enforced. Only authorizers can be
+// used in a not enforced (shadow) mode, either by configuring the corresponding prototype, or the
+// pipeline step accordingly, with the latter taking precedence.
+func isEnforced(handlerType string, handler any, conf any) (bool, error) {
+	enforced := true
+
+	if enforcer, ok := handler.(interface{ Enforced() bool }); ok {
+		enforced = enforcer.Enforced()
+	}
+
+	if conf == nil {
+		return enforced, nil
+	}
+
+	if handlerType != "authorizer" {
+		return false, errorchain.NewWithMessagef(heimdall.ErrConfiguration,
+			"enforce is not supported for %s", handlerType)
+	}
+
+	value, ok := conf.(bool)
+	if !ok {
+		return false, errorchain.NewWithMessagef(heimdall.ErrConfiguration,
+			"unexpected type '%T' for enforce", conf)
+	}
+
+	return value, nil
+}
+
 func getConfig(conf any) config.MechanismConfig {
 	if conf == nil {
 		return nil
@@ -379,7 +688,7 @@ func getConfig(conf any) config.MechanismConfig {
 	return m
 }
 
-func getExecutionCondition(conf any) (executionCondition, error) {
+func getExecutionCondition(conf any, geo geoip.Resolver) (executionCondition, error) {
 	if conf == nil {
 		return defaultExecutionCondition{}, nil
 	}
@@ -395,5 +704,5 @@ func getExecutionCondition(conf any) (executionCondition, error) {
 			"empty execution condition")
 	}
 
-	return newCelExecutionCondition(expression)
+	return newCelExecutionCondition(expression, geo)
 }
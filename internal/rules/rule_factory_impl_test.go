@@ -20,6 +20,7 @@ import (
 	"errors"
 	"net/url"
 	"testing"
+	"time"
 
 	"github.com/rs/zerolog/log"
 	"github.com/stretchr/testify/assert"
@@ -30,12 +31,14 @@ import (
 	"github.com/dadrus/heimdall/internal/heimdall"
 	config2 "github.com/dadrus/heimdall/internal/rules/config"
 	mocks2 "github.com/dadrus/heimdall/internal/rules/mechanisms/authenticators/mocks"
+	"github.com/dadrus/heimdall/internal/rules/mechanisms/authorizers"
 	mocks4 "github.com/dadrus/heimdall/internal/rules/mechanisms/authorizers/mocks"
 	mocks5 "github.com/dadrus/heimdall/internal/rules/mechanisms/contextualizers/mocks"
 	mocks6 "github.com/dadrus/heimdall/internal/rules/mechanisms/errorhandlers/mocks"
 	mocks7 "github.com/dadrus/heimdall/internal/rules/mechanisms/finalizers/mocks"
 	mocks3 "github.com/dadrus/heimdall/internal/rules/mechanisms/mocks"
 	"github.com/dadrus/heimdall/internal/rules/mocks"
+	"github.com/dadrus/heimdall/internal/watcher"
 	"github.com/dadrus/heimdall/internal/x"
 )
 
@@ -424,6 +427,8 @@ func TestRuleFactoryNew(t *testing.T) {
 			// WHEN
 			factory, err := NewRuleFactory(
 				handlerFactory,
+				nil,
+				&watcher.NoopWatcher{},
 				tc.config,
 				config.DecisionMode,
 				log.Logger,
@@ -451,6 +456,8 @@ func TestRuleFactoryCreateRule(t *testing.T) {
 	t.Parallel()
 
 	trueValue := true
+	weight := 2
+	zeroWeight := 0
 
 	for _, tc := range []struct {
 		uc             string
@@ -500,6 +507,34 @@ func TestRuleFactoryCreateRule(t *testing.T) {
 				require.ErrorContains(t, err, "methods list contains empty values")
 			},
 		},
+		{
+			uc: "with error while creating backend transport settings",
+			config: config2.Rule{
+				ID:      "foobar",
+				Matcher: config2.Matcher{Routes: []config2.Route{{Path: "/foo/bar"}}},
+				Backend: &config2.Backend{
+					Host: "foo.bar",
+					TLS: &config2.BackendTLS{
+						TrustStore: &config2.TrustStore{Path: "/does/not/exist.pem"},
+					},
+				},
+				Execute: []config.MechanismConfig{
+					{"authenticator": "foo"},
+				},
+			},
+			configureMocks: func(t *testing.T, mhf *mocks3.MechanismFactoryMock) {
+				t.Helper()
+
+				mhf.EXPECT().CreateAuthenticator("test", "foo", mock.Anything).Return(&mocks2.AuthenticatorMock{}, nil)
+			},
+			assert: func(t *testing.T, err error, _ *ruleImpl) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				require.ErrorContains(t, err, "failed loading backend tls trust store")
+			},
+		},
 		{
 			uc: "with error while creating route path params matcher",
 			config: config2.Rule{
@@ -677,6 +712,142 @@ func TestRuleFactoryCreateRule(t *testing.T) {
 				assert.NotNil(t, rul.backend)
 			},
 		},
+		{
+			uc:     "with multiple backend targets in proxy mode",
+			opMode: config.ProxyMode,
+			config: config2.Rule{
+				ID: "foobar",
+				Backend: &config2.Backend{
+					Targets: []config2.Target{{Host: "foo.bar"}, {Host: "bar.foo", Weight: &weight}},
+				},
+				Matcher: config2.Matcher{Routes: []config2.Route{{Path: "/foo/bar"}}},
+				Execute: []config.MechanismConfig{
+					{"authenticator": "foo"},
+				},
+			},
+			configureMocks: func(t *testing.T, mhf *mocks3.MechanismFactoryMock) {
+				t.Helper()
+
+				mhf.EXPECT().CreateAuthenticator("test", "foo", mock.Anything).Return(&mocks2.AuthenticatorMock{}, nil)
+			},
+			assert: func(t *testing.T, err error, rul *ruleImpl) {
+				t.Helper()
+
+				require.NoError(t, err)
+				require.NotNil(t, rul)
+				assert.NotNil(t, rul.backend)
+				assert.NotNil(t, rul.balancer)
+			},
+		},
+		{
+			uc:     "with all backend targets drained in proxy mode",
+			opMode: config.ProxyMode,
+			config: config2.Rule{
+				ID: "foobar",
+				Backend: &config2.Backend{
+					Targets: []config2.Target{{Host: "foo.bar", Weight: &zeroWeight}, {Host: "bar.foo", Weight: &zeroWeight}},
+				},
+				Matcher: config2.Matcher{Routes: []config2.Route{{Path: "/foo/bar"}}},
+				Execute: []config.MechanismConfig{
+					{"authenticator": "foo"},
+				},
+			},
+			configureMocks: func(t *testing.T, mhf *mocks3.MechanismFactoryMock) {
+				t.Helper()
+
+				mhf.EXPECT().CreateAuthenticator("test", "foo", mock.Anything).Return(&mocks2.AuthenticatorMock{}, nil)
+			},
+			assert: func(t *testing.T, err error, _ *ruleImpl) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				assert.Contains(t, err.Error(), "weight greater than 0")
+			},
+		},
+		{
+			uc: "with multiple backend targets in decision mode",
+			config: config2.Rule{
+				ID: "foobar",
+				Backend: &config2.Backend{
+					Targets: []config2.Target{{Host: "foo.bar"}, {Host: "bar.foo", Weight: &weight}},
+				},
+				Matcher: config2.Matcher{Routes: []config2.Route{{Path: "/foo/bar"}}},
+				Execute: []config.MechanismConfig{
+					{"authenticator": "foo"},
+				},
+			},
+			configureMocks: func(t *testing.T, mhf *mocks3.MechanismFactoryMock) {
+				t.Helper()
+
+				mhf.EXPECT().CreateAuthenticator("test", "foo", mock.Anything).Return(&mocks2.AuthenticatorMock{}, nil)
+			},
+			assert: func(t *testing.T, err error, rul *ruleImpl) {
+				t.Helper()
+
+				require.NoError(t, err)
+				require.NotNil(t, rul)
+				assert.NotNil(t, rul.backend)
+				assert.Nil(t, rul.balancer)
+			},
+		},
+		{
+			uc:     "with retry and circuit breaker settings in proxy mode",
+			opMode: config.ProxyMode,
+			config: config2.Rule{
+				ID: "foobar",
+				Backend: &config2.Backend{
+					Host:           "foo.bar",
+					Retry:          &config2.Retry{MaxAttempts: 3},
+					CircuitBreaker: &config2.CircuitBreaker{ConsecutiveFailures: 10},
+				},
+				Matcher: config2.Matcher{Routes: []config2.Route{{Path: "/foo/bar"}}},
+				Execute: []config.MechanismConfig{
+					{"authenticator": "foo"},
+				},
+			},
+			configureMocks: func(t *testing.T, mhf *mocks3.MechanismFactoryMock) {
+				t.Helper()
+
+				mhf.EXPECT().CreateAuthenticator("test", "foo", mock.Anything).Return(&mocks2.AuthenticatorMock{}, nil)
+			},
+			assert: func(t *testing.T, err error, rul *ruleImpl) {
+				t.Helper()
+
+				require.NoError(t, err)
+				require.NotNil(t, rul)
+				assert.NotNil(t, rul.backend)
+				assert.NotNil(t, rul.policy)
+			},
+		},
+		{
+			uc: "with retry and circuit breaker settings in decision mode",
+			config: config2.Rule{
+				ID: "foobar",
+				Backend: &config2.Backend{
+					Host:           "foo.bar",
+					Retry:          &config2.Retry{MaxAttempts: 3},
+					CircuitBreaker: &config2.CircuitBreaker{ConsecutiveFailures: 10},
+				},
+				Matcher: config2.Matcher{Routes: []config2.Route{{Path: "/foo/bar"}}},
+				Execute: []config.MechanismConfig{
+					{"authenticator": "foo"},
+				},
+			},
+			configureMocks: func(t *testing.T, mhf *mocks3.MechanismFactoryMock) {
+				t.Helper()
+
+				mhf.EXPECT().CreateAuthenticator("test", "foo", mock.Anything).Return(&mocks2.AuthenticatorMock{}, nil)
+			},
+			assert: func(t *testing.T, err error, rul *ruleImpl) {
+				t.Helper()
+
+				require.NoError(t, err)
+				require.NotNil(t, rul)
+				assert.NotNil(t, rul.backend)
+				assert.Nil(t, rul.policy)
+			},
+		},
 		{
 			uc: "with default rule and regular rule with id and a single route only",
 			config: config2.Rule{
@@ -995,6 +1166,463 @@ func TestRuleFactoryCreateRule(t *testing.T) {
 				require.Empty(t, rul.eh)
 			},
 		},
+		{
+			uc: "with enforce configuration type error",
+			config: config2.Rule{
+				ID:      "foobar",
+				Matcher: config2.Matcher{Routes: []config2.Route{{Path: "/foo/bar"}}},
+				Execute: []config.MechanismConfig{
+					{"authenticator": "foo"},
+					{"authorizer": "bar", "enforce": "false"},
+				},
+			},
+			configureMocks: func(t *testing.T, mhf *mocks3.MechanismFactoryMock) {
+				t.Helper()
+
+				mhf.EXPECT().CreateAuthenticator("test", "foo", mock.Anything).Return(&mocks2.AuthenticatorMock{}, nil)
+				mhf.EXPECT().CreateAuthorizer("test", "bar", mock.Anything).Return(&mocks4.AuthorizerMock{}, nil)
+			},
+			assert: func(t *testing.T, err error, _ *ruleImpl) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				require.ErrorContains(t, err, "unexpected type 'string' for enforce")
+			},
+		},
+		{
+			uc: "with enforce configuration for a contextualizer",
+			config: config2.Rule{
+				ID:      "foobar",
+				Matcher: config2.Matcher{Routes: []config2.Route{{Path: "/foo/bar"}}},
+				Execute: []config.MechanismConfig{
+					{"authenticator": "foo"},
+					{"contextualizer": "bar", "enforce": false},
+				},
+			},
+			configureMocks: func(t *testing.T, mhf *mocks3.MechanismFactoryMock) {
+				t.Helper()
+
+				mhf.EXPECT().CreateAuthenticator("test", "foo", mock.Anything).Return(&mocks2.AuthenticatorMock{}, nil)
+				mhf.EXPECT().CreateContextualizer("test", "bar", mock.Anything).
+					Return(&mocks5.ContextualizerMock{}, nil)
+			},
+			assert: func(t *testing.T, err error, _ *ruleImpl) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				require.ErrorContains(t, err, "enforce is not supported for contextualizer")
+			},
+		},
+		{
+			uc: "with not enforced authorizers",
+			config: config2.Rule{
+				ID:      "foobar",
+				Matcher: config2.Matcher{Routes: []config2.Route{{Path: "/foo/bar"}}},
+				Execute: []config.MechanismConfig{
+					{"authenticator": "foo"},
+					{"authorizer": "bar", "enforce": false},
+					{"authorizer": "baz"},
+					{"authorizer": "baz", "enforce": true},
+					{"authorizer": "zab", "enforce": true},
+				},
+			},
+			configureMocks: func(t *testing.T, mhf *mocks3.MechanismFactoryMock) {
+				t.Helper()
+
+				mhf.EXPECT().CreateAuthenticator("test", "foo", mock.Anything).
+					Return(&mocks2.AuthenticatorMock{}, nil)
+				mhf.EXPECT().CreateAuthorizer("test", "bar", mock.Anything).
+					Return(&mocks4.AuthorizerMock{}, nil)
+				mhf.EXPECT().CreateAuthorizer("test", "baz", mock.Anything).
+					Return(authorizers.NonEnforced(&mocks4.AuthorizerMock{}), nil).Times(2)
+				mhf.EXPECT().CreateAuthorizer("test", "zab", mock.Anything).
+					Return(&mocks4.AuthorizerMock{}, nil)
+			},
+			assert: func(t *testing.T, err error, rul *ruleImpl) {
+				t.Helper()
+
+				require.NoError(t, err)
+				require.NotNil(t, rul)
+				require.Len(t, rul.sh, 4)
+
+				shadowed, ok := rul.sh[0].(*shadowSubjectHandler)
+				require.True(t, ok)
+				assert.Equal(t, "foobar", shadowed.ruleID)
+				assert.IsType(t, &conditionalSubjectHandler{}, shadowed.h)
+
+				shadowed, ok = rul.sh[1].(*shadowSubjectHandler)
+				require.True(t, ok)
+				assert.Equal(t, "foobar", shadowed.ruleID)
+
+				assert.IsType(t, &conditionalSubjectHandler{}, rul.sh[2])
+				assert.IsType(t, &conditionalSubjectHandler{}, rul.sh[3])
+			},
+		},
+		{
+			uc: "with group defined after a finalizer",
+			config: config2.Rule{
+				ID:      "foobar",
+				Matcher: config2.Matcher{Routes: []config2.Route{{Path: "/foo/bar"}}},
+				Execute: []config.MechanismConfig{
+					{"authenticator": "foo"},
+					{"finalizer": "bar"},
+					{"any_of": []any{map[string]any{"authorizer": "bar"}}},
+				},
+			},
+			configureMocks: func(t *testing.T, mhf *mocks3.MechanismFactoryMock) {
+				t.Helper()
+
+				mhf.EXPECT().CreateAuthenticator("test", "foo", mock.Anything).Return(&mocks2.AuthenticatorMock{}, nil)
+				mhf.EXPECT().CreateFinalizer("test", "bar", mock.Anything).Return(&mocks7.FinalizerMock{}, nil)
+			},
+			assert: func(t *testing.T, err error, _ *ruleImpl) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				require.ErrorContains(t, err, "finalizer is defined before a group")
+			},
+		},
+		{
+			uc: "with any_of and all_of in the same step",
+			config: config2.Rule{
+				ID:      "foobar",
+				Matcher: config2.Matcher{Routes: []config2.Route{{Path: "/foo/bar"}}},
+				Execute: []config.MechanismConfig{
+					{"authenticator": "foo"},
+					{
+						"any_of": []any{map[string]any{"authorizer": "bar"}},
+						"all_of": []any{map[string]any{"authorizer": "baz"}},
+					},
+				},
+			},
+			configureMocks: func(t *testing.T, mhf *mocks3.MechanismFactoryMock) {
+				t.Helper()
+
+				mhf.EXPECT().CreateAuthenticator("test", "foo", mock.Anything).Return(&mocks2.AuthenticatorMock{}, nil)
+			},
+			assert: func(t *testing.T, err error, _ *ruleImpl) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				require.ErrorContains(t, err, "cannot be used in the same pipeline step")
+			},
+		},
+		{
+			uc: "with empty group",
+			config: config2.Rule{
+				ID:      "foobar",
+				Matcher: config2.Matcher{Routes: []config2.Route{{Path: "/foo/bar"}}},
+				Execute: []config.MechanismConfig{
+					{"authenticator": "foo"},
+					{"all_of": []any{}},
+				},
+			},
+			configureMocks: func(t *testing.T, mhf *mocks3.MechanismFactoryMock) {
+				t.Helper()
+
+				mhf.EXPECT().CreateAuthenticator("test", "foo", mock.Anything).Return(&mocks2.AuthenticatorMock{}, nil)
+			},
+			assert: func(t *testing.T, err error, _ *ruleImpl) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				require.ErrorContains(t, err, "empty all_of")
+			},
+		},
+		{
+			uc: "with malformed group steps",
+			config: config2.Rule{
+				ID:      "foobar",
+				Matcher: config2.Matcher{Routes: []config2.Route{{Path: "/foo/bar"}}},
+				Execute: []config.MechanismConfig{
+					{"authenticator": "foo"},
+					{"any_of": []any{"bar"}},
+				},
+			},
+			configureMocks: func(t *testing.T, mhf *mocks3.MechanismFactoryMock) {
+				t.Helper()
+
+				mhf.EXPECT().CreateAuthenticator("test", "foo", mock.Anything).Return(&mocks2.AuthenticatorMock{}, nil)
+			},
+			assert: func(t *testing.T, err error, _ *ruleImpl) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				require.ErrorContains(t, err, "unexpected type 'string' for a step of any_of")
+			},
+		},
+		{
+			uc: "with unsupported mechanism in group",
+			config: config2.Rule{
+				ID:      "foobar",
+				Matcher: config2.Matcher{Routes: []config2.Route{{Path: "/foo/bar"}}},
+				Execute: []config.MechanismConfig{
+					{"authenticator": "foo"},
+					{"any_of": []any{map[string]any{"finalizer": "bar"}}},
+				},
+			},
+			configureMocks: func(t *testing.T, mhf *mocks3.MechanismFactoryMock) {
+				t.Helper()
+
+				mhf.EXPECT().CreateAuthenticator("test", "foo", mock.Anything).Return(&mocks2.AuthenticatorMock{}, nil)
+			},
+			assert: func(t *testing.T, err error, _ *ruleImpl) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				require.ErrorContains(t, err, "unsupported configuration in any_of")
+			},
+		},
+		{
+			uc: "with enforce configuration in group",
+			config: config2.Rule{
+				ID:      "foobar",
+				Matcher: config2.Matcher{Routes: []config2.Route{{Path: "/foo/bar"}}},
+				Execute: []config.MechanismConfig{
+					{"authenticator": "foo"},
+					{"any_of": []any{map[string]any{"authorizer": "bar", "enforce": false}}},
+				},
+			},
+			configureMocks: func(t *testing.T, mhf *mocks3.MechanismFactoryMock) {
+				t.Helper()
+
+				mhf.EXPECT().CreateAuthenticator("test", "foo", mock.Anything).Return(&mocks2.AuthenticatorMock{}, nil)
+			},
+			assert: func(t *testing.T, err error, _ *ruleImpl) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				require.ErrorContains(t, err, "enforce is not supported for steps of any_of")
+			},
+		},
+		{
+			uc: "with not enforced authorizer in group",
+			config: config2.Rule{
+				ID:      "foobar",
+				Matcher: config2.Matcher{Routes: []config2.Route{{Path: "/foo/bar"}}},
+				Execute: []config.MechanismConfig{
+					{"authenticator": "foo"},
+					{"all_of": []any{map[string]any{"authorizer": "bar"}}},
+				},
+			},
+			configureMocks: func(t *testing.T, mhf *mocks3.MechanismFactoryMock) {
+				t.Helper()
+
+				authorizer := mocks4.NewAuthorizerMock(t)
+				authorizer.EXPECT().ID().Return("bar")
+
+				mhf.EXPECT().CreateAuthenticator("test", "foo", mock.Anything).Return(&mocks2.AuthenticatorMock{}, nil)
+				mhf.EXPECT().CreateAuthorizer("test", "bar", mock.Anything).
+					Return(authorizers.NonEnforced(authorizer), nil)
+			},
+			assert: func(t *testing.T, err error, _ *ruleImpl) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				require.ErrorContains(t, err, "not enforced authorizer 'bar' cannot be used in all_of")
+			},
+		},
+		{
+			uc: "with nested groups",
+			config: config2.Rule{
+				ID:      "foobar",
+				Matcher: config2.Matcher{Routes: []config2.Route{{Path: "/foo/bar"}}},
+				Execute: []config.MechanismConfig{
+					{"authenticator": "foo"},
+					{
+						"any_of": []any{
+							map[string]any{"authorizer": "bar"},
+							map[string]any{
+								"all_of": []any{
+									map[string]any{"contextualizer": "baz"},
+									map[string]any{"authorizer": "zab", "if": "true"},
+								},
+							},
+						},
+						"if": "Request.Method == 'POST'",
+					},
+				},
+			},
+			configureMocks: func(t *testing.T, mhf *mocks3.MechanismFactoryMock) {
+				t.Helper()
+
+				mhf.EXPECT().CreateAuthenticator("test", "foo", mock.Anything).
+					Return(&mocks2.AuthenticatorMock{}, nil)
+				mhf.EXPECT().CreateAuthorizer("test", "bar", mock.Anything).
+					Return(&mocks4.AuthorizerMock{}, nil)
+				mhf.EXPECT().CreateContextualizer("test", "baz", mock.Anything).
+					Return(&mocks5.ContextualizerMock{}, nil)
+				mhf.EXPECT().CreateAuthorizer("test", "zab", mock.Anything).
+					Return(&mocks4.AuthorizerMock{}, nil)
+			},
+			assert: func(t *testing.T, err error, rul *ruleImpl) {
+				t.Helper()
+
+				require.NoError(t, err)
+				require.Len(t, rul.sh, 1)
+
+				sh, ok := rul.sh[0].(*conditionalSubjectHandler)
+				require.True(t, ok)
+				assert.IsType(t, &celExecutionCondition{}, sh.c)
+
+				anyOf, ok := sh.h.(*groupSubjectHandler)
+				require.True(t, ok)
+				assert.Equal(t, groupTypeAnyOf, anyOf.typ)
+				require.Len(t, anyOf.handlers, 2)
+				assert.IsType(t, defaultExecutionCondition{}, anyOf.handlers[0].c)
+
+				allOf, ok := anyOf.handlers[1].h.(*groupSubjectHandler)
+				require.True(t, ok)
+				assert.Equal(t, groupTypeAllOf, allOf.typ)
+				require.Len(t, allOf.handlers, 2)
+				assert.IsType(t, defaultExecutionCondition{}, allOf.handlers[0].c)
+				assert.IsType(t, &celExecutionCondition{}, allOf.handlers[1].c)
+			},
+		},
+		{
+			uc: "with parallel group of contextualizers",
+			config: config2.Rule{
+				ID:      "foobar",
+				Matcher: config2.Matcher{Routes: []config2.Route{{Path: "/foo/bar"}}},
+				Execute: []config.MechanismConfig{
+					{"authenticator": "foo"},
+					{
+						"parallel": []any{
+							map[string]any{"contextualizer": "bar"},
+							map[string]any{"contextualizer": "baz", "if": "true"},
+						},
+						"timeout": "2s",
+					},
+				},
+			},
+			configureMocks: func(t *testing.T, mhf *mocks3.MechanismFactoryMock) {
+				t.Helper()
+
+				mhf.EXPECT().CreateAuthenticator("test", "foo", mock.Anything).
+					Return(&mocks2.AuthenticatorMock{}, nil)
+				mhf.EXPECT().CreateContextualizer("test", "bar", mock.Anything).
+					Return(&mocks5.ContextualizerMock{}, nil)
+				mhf.EXPECT().CreateContextualizer("test", "baz", mock.Anything).
+					Return(&mocks5.ContextualizerMock{}, nil)
+			},
+			assert: func(t *testing.T, err error, rul *ruleImpl) {
+				t.Helper()
+
+				require.NoError(t, err)
+				require.Len(t, rul.sh, 1)
+
+				sh, ok := rul.sh[0].(*conditionalSubjectHandler)
+				require.True(t, ok)
+
+				parallel, ok := sh.h.(*parallelSubjectHandler)
+				require.True(t, ok)
+				assert.Equal(t, 2*time.Second, parallel.timeout)
+				require.Len(t, parallel.handlers, 2)
+				assert.IsType(t, defaultExecutionCondition{}, parallel.handlers[0].c)
+				assert.IsType(t, &celExecutionCondition{}, parallel.handlers[1].c)
+			},
+		},
+		{
+			uc: "with authorizer in parallel group",
+			config: config2.Rule{
+				ID:      "foobar",
+				Matcher: config2.Matcher{Routes: []config2.Route{{Path: "/foo/bar"}}},
+				Execute: []config.MechanismConfig{
+					{"authenticator": "foo"},
+					{"parallel": []any{map[string]any{"authorizer": "bar"}}},
+				},
+			},
+			configureMocks: func(t *testing.T, mhf *mocks3.MechanismFactoryMock) {
+				t.Helper()
+
+				mhf.EXPECT().CreateAuthenticator("test", "foo", mock.Anything).Return(&mocks2.AuthenticatorMock{}, nil)
+			},
+			assert: func(t *testing.T, err error, _ *ruleImpl) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				require.ErrorContains(t, err, "unsupported configuration in parallel")
+			},
+		},
+		{
+			uc: "with nested group in parallel group",
+			config: config2.Rule{
+				ID:      "foobar",
+				Matcher: config2.Matcher{Routes: []config2.Route{{Path: "/foo/bar"}}},
+				Execute: []config.MechanismConfig{
+					{"authenticator": "foo"},
+					{"parallel": []any{map[string]any{"all_of": []any{map[string]any{"contextualizer": "bar"}}}}},
+				},
+			},
+			configureMocks: func(t *testing.T, mhf *mocks3.MechanismFactoryMock) {
+				t.Helper()
+
+				mhf.EXPECT().CreateAuthenticator("test", "foo", mock.Anything).Return(&mocks2.AuthenticatorMock{}, nil)
+			},
+			assert: func(t *testing.T, err error, _ *ruleImpl) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				require.ErrorContains(t, err, "unsupported configuration in parallel")
+			},
+		},
+		{
+			uc: "with timeout for an all_of group",
+			config: config2.Rule{
+				ID:      "foobar",
+				Matcher: config2.Matcher{Routes: []config2.Route{{Path: "/foo/bar"}}},
+				Execute: []config.MechanismConfig{
+					{"authenticator": "foo"},
+					{"all_of": []any{map[string]any{"contextualizer": "bar"}}, "timeout": "1s"},
+				},
+			},
+			configureMocks: func(t *testing.T, mhf *mocks3.MechanismFactoryMock) {
+				t.Helper()
+
+				mhf.EXPECT().CreateAuthenticator("test", "foo", mock.Anything).Return(&mocks2.AuthenticatorMock{}, nil)
+			},
+			assert: func(t *testing.T, err error, _ *ruleImpl) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				require.ErrorContains(t, err, "timeout is not supported for all_of")
+			},
+		},
+		{
+			uc: "with malformed timeout for a parallel group",
+			config: config2.Rule{
+				ID:      "foobar",
+				Matcher: config2.Matcher{Routes: []config2.Route{{Path: "/foo/bar"}}},
+				Execute: []config.MechanismConfig{
+					{"authenticator": "foo"},
+					{"parallel": []any{map[string]any{"contextualizer": "bar"}}, "timeout": "foo"},
+				},
+			},
+			configureMocks: func(t *testing.T, mhf *mocks3.MechanismFactoryMock) {
+				t.Helper()
+
+				mhf.EXPECT().CreateAuthenticator("test", "foo", mock.Anything).Return(&mocks2.AuthenticatorMock{}, nil)
+			},
+			assert: func(t *testing.T, err error, _ *ruleImpl) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				require.ErrorContains(t, err, "failed parsing timeout of parallel")
+			},
+		},
 		{
 			uc: "with bad conditional expression in the error pipeline",
 			config: config2.Rule{
@@ -1101,6 +1729,7 @@ func TestRuleFactoryCreateRule(t *testing.T) {
 
 			factory := &ruleFactory{
 				hf:             handlerFactory,
+				cw:             &watcher.NoopWatcher{},
 				defaultRule:    tc.defaultRule,
 				mode:           tc.opMode,
 				logger:         log.Logger,
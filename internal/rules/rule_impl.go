@@ -18,6 +18,9 @@ package rules
 
 import (
 	"bytes"
+	"context"
+	"errors"
+	"net/http"
 	"net/url"
 	"strings"
 
@@ -25,6 +28,9 @@ import (
 
 	"github.com/dadrus/heimdall/internal/heimdall"
 	"github.com/dadrus/heimdall/internal/rules/config"
+	"github.com/dadrus/heimdall/internal/rules/loadbalancer"
+	"github.com/dadrus/heimdall/internal/rules/mechanisms/subject"
+	"github.com/dadrus/heimdall/internal/rules/resilience"
 	"github.com/dadrus/heimdall/internal/rules/rule"
 	"github.com/dadrus/heimdall/internal/x/errorchain"
 )
@@ -38,6 +44,11 @@ type ruleImpl struct {
 	routes             []rule.Route
 	slashesHandling    config.EncodedSlashesHandling
 	backend            *config.Backend
+	transportSettings  *rule.TransportSettings
+	tlsReloader        *tlsReloader
+	balancer           *loadbalancer.Balancer
+	policy             *resilience.Policy
+	urlRewriter        *urlRewriter
 	sc                 compositeSubjectCreator
 	sh                 compositeSubjectHandler
 	fi                 compositeSubjectHandler
@@ -75,34 +86,128 @@ func (r *ruleImpl) Execute(ctx heimdall.RequestContext) (rule.Backend, error) {
 	// authenticators
 	sub, err := r.sc.Execute(ctx)
 	if err != nil {
-		return nil, r.eh.Execute(ctx, err)
+		return nil, r.eh.Execute(ctx, nil, err)
 	}
 
 	// authorizers & contextualizer
 	if err = r.sh.Execute(ctx, sub); err != nil {
-		return nil, r.eh.Execute(ctx, err)
+		return nil, r.eh.Execute(ctx, sub, err)
 	}
 
 	// finalizers
 	if err = r.fi.Execute(ctx, sub); err != nil {
-		return nil, r.eh.Execute(ctx, err)
+		return nil, r.eh.Execute(ctx, sub, err)
 	}
 
-	return r.createBackend(request), nil
+	upstream, err := r.createBackend(ctx.Context(), request, sub)
+	if err != nil {
+		return nil, r.eh.Execute(ctx, sub, err)
+	}
+
+	return upstream, nil
 }
 
-func (r *ruleImpl) createBackend(request *heimdall.Request) rule.Backend {
-	var upstream rule.Backend
+func (r *ruleImpl) createBackend(
+	ctx context.Context,
+	request *heimdall.Request,
+	sub *subject.Subject,
+) (rule.Backend, error) {
+	if r.backend == nil {
+		return nil, nil //nolint:nilnil
+	}
 
-	if r.backend != nil {
-		upstream = backend{
-			targetURL: r.backend.CreateURL(&request.URL.URL),
-			forwardHostHeader: r.backend.ForwardHostHeader == nil ||
-				(r.backend.ForwardHostHeader != nil && *r.backend.ForwardHostHeader),
+	upstream := backend{
+		targetURL: r.backend.CreateURL(&request.URL.URL),
+		forwardHostHeader: r.backend.ForwardHostHeader == nil ||
+			(r.backend.ForwardHostHeader != nil && *r.backend.ForwardHostHeader),
+		transportSettings: r.transportSettings,
+		policy:            r.policy,
+	}
+
+	if r.urlRewriter != nil {
+		if err := r.urlRewriter.rewrite(upstream.targetURL, map[string]any{
+			"Request": request,
+			"Subject": sub,
+		}); err != nil {
+			return nil, err
 		}
 	}
 
-	return upstream
+	switch {
+	case r.balancer != nil:
+		key := r.hashKey(request, sub)
+		tgt := &balancedTarget{balancer: r.balancer, key: key, current: r.balancer.Next(ctx, key)}
+
+		upstream.targetURL.Host = tgt.current.Host()
+		upstream.selector = tgt
+		upstream.done = tgt.done
+	case len(r.backend.Targets) != 0:
+		upstream.targetURL.Host = r.backend.Targets[0].Host
+	}
+
+	return upstream, nil
+}
+
+func (r *ruleImpl) hashKey(request *heimdall.Request, sub *subject.Subject) string {
+	if r.backend.LoadBalancing == nil || r.backend.LoadBalancing.HashKey == nil {
+		return ""
+	}
+
+	switch r.backend.LoadBalancing.HashKey.Source {
+	case config.HashKeySourceSubject:
+		if sub != nil {
+			return sub.ID
+		}
+	case config.HashKeySourceHeader:
+		return request.Header(r.backend.LoadBalancing.HashKey.Header)
+	}
+
+	return ""
+}
+
+func (r *ruleImpl) activate() error {
+	if r.tlsReloader != nil {
+		if err := r.tlsReloader.start(); err != nil {
+			return err
+		}
+	}
+
+	if r.balancer != nil {
+		if err := r.balancer.Start(); err != nil {
+			r.deactivate()
+
+			return err
+		}
+	}
+
+	if r.policy != nil {
+		if err := r.policy.Start(); err != nil {
+			r.deactivate()
+
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *ruleImpl) deactivate() {
+	if r.tlsReloader != nil {
+		r.tlsReloader.stop()
+	}
+
+	if r.balancer != nil {
+		r.balancer.Stop()
+	}
+
+	if r.policy != nil {
+		r.policy.Stop()
+	}
+
+	// releases the connections to the backend
+	if r.transportSettings != nil {
+		r.transportSettings.Close()
+	}
 }
 
 func (r *ruleImpl) ID() string { return r.id }
@@ -156,12 +261,52 @@ func (r *routeImpl) Rule() rule.Rule { return r.rule }
 type backend struct {
 	targetURL         *url.URL
 	forwardHostHeader bool
+	transportSettings *rule.TransportSettings
+	policy            *resilience.Policy
+	selector          resilience.Selector
+	done              func(ctx context.Context, statusCode int, err error)
 }
 
 func (b backend) URL() *url.URL { return b.targetURL }
 
 func (b backend) ForwardHostHeader() bool { return b.forwardHostHeader }
 
+func (b backend) TransportSettings() *rule.TransportSettings { return b.transportSettings }
+
+func (b backend) RoundTripper(next http.RoundTripper) http.RoundTripper {
+	return b.policy.RoundTripper(next, b.selector)
+}
+
+func (b backend) Done(ctx context.Context, statusCode int, err error) {
+	if b.done != nil {
+		b.done(ctx, statusCode, err)
+	}
+}
+
+// balancedTarget keeps track of the target the current attempt of a request is sent to
+// and lets retries be sent to another one.
+type balancedTarget struct {
+	balancer *loadbalancer.Balancer
+	key      string
+	current  *loadbalancer.Target
+}
+
+func (t *balancedTarget) Reselect(ctx context.Context, statusCode int, err error) string {
+	t.balancer.Done(ctx, t.current, statusCode, err)
+	t.current = t.balancer.Next(ctx, t.key, t.current)
+
+	return t.current.Host()
+}
+
+func (t *balancedTarget) done(ctx context.Context, statusCode int, err error) {
+	// a request rejected by an open circuit breaker has not been forwarded
+	if errors.Is(err, resilience.ErrCircuitOpen) {
+		statusCode, err = 0, nil
+	}
+
+	t.balancer.Done(ctx, t.current, statusCode, err)
+}
+
 func unescape(value string, handling config.EncodedSlashesHandling) string {
 	if handling == config.EncodedSlashesOn {
 		unescaped, _ := url.PathUnescape(value)
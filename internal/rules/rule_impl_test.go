@@ -18,18 +18,25 @@ package rules
 
 import (
 	"errors"
+	"net/http"
+	"net/http/httptest"
 	"net/url"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
 	"github.com/dadrus/heimdall/internal/heimdall"
 	heimdallmocks "github.com/dadrus/heimdall/internal/heimdall/mocks"
 	"github.com/dadrus/heimdall/internal/rules/config"
+	"github.com/dadrus/heimdall/internal/rules/loadbalancer"
 	"github.com/dadrus/heimdall/internal/rules/mechanisms/subject"
 	"github.com/dadrus/heimdall/internal/rules/mocks"
+	"github.com/dadrus/heimdall/internal/rules/resilience"
 	"github.com/dadrus/heimdall/internal/rules/rule"
+	watchermocks "github.com/dadrus/heimdall/internal/watcher/mocks"
 	"github.com/dadrus/heimdall/internal/x"
 )
 
@@ -64,7 +71,7 @@ func TestRuleExecute(t *testing.T) {
 				testErr := errors.New("test error")
 
 				authenticator.EXPECT().Execute(ctx).Return(nil, testErr)
-				errHandler.EXPECT().Execute(ctx, testErr).Return(nil)
+				errHandler.EXPECT().Execute(ctx, (*subject.Subject)(nil), testErr).Return(nil)
 			},
 			assert: func(t *testing.T, err error, backend rule.Backend, _ map[string]string) {
 				t.Helper()
@@ -85,7 +92,7 @@ func TestRuleExecute(t *testing.T) {
 				testErr := errors.New("test error")
 
 				authenticator.EXPECT().Execute(ctx).Return(nil, testErr)
-				errHandler.EXPECT().Execute(ctx, testErr).Return(errors.New("some error"))
+				errHandler.EXPECT().Execute(ctx, (*subject.Subject)(nil), testErr).Return(errors.New("some error"))
 			},
 			assert: func(t *testing.T, err error, backend rule.Backend, _ map[string]string) {
 				t.Helper()
@@ -111,7 +118,7 @@ func TestRuleExecute(t *testing.T) {
 				authenticator.EXPECT().Execute(ctx).Return(sub, nil)
 				authorizer.EXPECT().Execute(ctx, sub).Return(testErr)
 				authorizer.EXPECT().ContinueOnError().Return(false)
-				errHandler.EXPECT().Execute(ctx, testErr).Return(nil)
+				errHandler.EXPECT().Execute(ctx, sub, testErr).Return(nil)
 			},
 			assert: func(t *testing.T, err error, backend rule.Backend, _ map[string]string) {
 				t.Helper()
@@ -136,7 +143,7 @@ func TestRuleExecute(t *testing.T) {
 				authenticator.EXPECT().Execute(ctx).Return(sub, nil)
 				authorizer.EXPECT().Execute(ctx, sub).Return(testErr)
 				authorizer.EXPECT().ContinueOnError().Return(false)
-				errHandler.EXPECT().Execute(ctx, testErr).Return(errors.New("some error"))
+				errHandler.EXPECT().Execute(ctx, sub, testErr).Return(errors.New("some error"))
 			},
 			assert: func(t *testing.T, err error, backend rule.Backend, _ map[string]string) {
 				t.Helper()
@@ -163,7 +170,7 @@ func TestRuleExecute(t *testing.T) {
 				authorizer.EXPECT().Execute(ctx, sub).Return(nil)
 				finalizer.EXPECT().Execute(ctx, sub).Return(testErr)
 				finalizer.EXPECT().ContinueOnError().Return(false)
-				errHandler.EXPECT().Execute(ctx, testErr).Return(nil)
+				errHandler.EXPECT().Execute(ctx, sub, testErr).Return(nil)
 			},
 			assert: func(t *testing.T, err error, backend rule.Backend, _ map[string]string) {
 				t.Helper()
@@ -189,7 +196,7 @@ func TestRuleExecute(t *testing.T) {
 				authorizer.EXPECT().Execute(ctx, sub).Return(nil)
 				finalizer.EXPECT().Execute(ctx, sub).Return(testErr)
 				finalizer.EXPECT().ContinueOnError().Return(false)
-				errHandler.EXPECT().Execute(ctx, testErr).Return(errors.New("some error"))
+				errHandler.EXPECT().Execute(ctx, sub, testErr).Return(errors.New("some error"))
 			},
 			assert: func(t *testing.T, err error, backend rule.Backend, _ map[string]string) {
 				t.Helper()
@@ -430,12 +437,81 @@ func TestRuleExecute(t *testing.T) {
 				assert.True(t, backend.ForwardHostHeader())
 			},
 		},
+		"rewriting upstream url using captures and subject": {
+			backend: &config.Backend{
+				Host: "foo.bar",
+				URLRewriter: &config.URLRewriter{
+					PathTemplate:     "/profiles/{{ .Request.URL.Captures.id }}",
+					QueryParamsToAdd: map[string]string{"user": "{{ .Subject.ID }}"},
+				},
+			},
+			configureMocks: func(t *testing.T, ctx *heimdallmocks.RequestContextMock, authenticator *mocks.SubjectCreatorMock,
+				authorizer *mocks.SubjectHandlerMock, finalizer *mocks.SubjectHandlerMock,
+				_ *mocks.ErrorHandlerMock,
+			) {
+				t.Helper()
+
+				sub := &subject.Subject{ID: "Foo"}
+
+				authenticator.EXPECT().Execute(ctx).Return(sub, nil)
+				authorizer.EXPECT().Execute(ctx, sub).Return(nil)
+				finalizer.EXPECT().Execute(ctx, sub).Return(nil)
+
+				targetURL, _ := url.Parse("http://foo.local/api/v1/users/bar/profile")
+				ctx.EXPECT().Request().Return(&heimdall.Request{
+					URL: &heimdall.URL{URL: *targetURL, Captures: map[string]string{"id": "bar"}},
+				})
+			},
+			assert: func(t *testing.T, err error, backend rule.Backend, _ map[string]string) {
+				t.Helper()
+
+				require.NoError(t, err)
+
+				expectedURL, _ := url.Parse("http://foo.bar/profiles/bar?user=Foo")
+				assert.Equal(t, expectedURL, backend.URL())
+			},
+		},
+		"rewriting upstream url fails, but error handler succeeds": {
+			backend: &config.Backend{
+				Host:        "foo.bar",
+				URLRewriter: &config.URLRewriter{PathTemplate: "/profiles/{{ .Request.URL.Captures.id }}"},
+			},
+			configureMocks: func(t *testing.T, ctx *heimdallmocks.RequestContextMock, authenticator *mocks.SubjectCreatorMock,
+				authorizer *mocks.SubjectHandlerMock, finalizer *mocks.SubjectHandlerMock,
+				errHandler *mocks.ErrorHandlerMock,
+			) {
+				t.Helper()
+
+				sub := &subject.Subject{ID: "Foo"}
+
+				authenticator.EXPECT().Execute(ctx).Return(sub, nil)
+				authorizer.EXPECT().Execute(ctx, sub).Return(nil)
+				finalizer.EXPECT().Execute(ctx, sub).Return(nil)
+				errHandler.EXPECT().Execute(ctx, sub, mock.MatchedBy(func(err error) bool {
+					return errors.Is(err, heimdall.ErrInternal)
+				})).Return(nil)
+
+				targetURL, _ := url.Parse("http://foo.local/api/v1/users/bar/profile")
+				ctx.EXPECT().Request().Return(&heimdall.Request{
+					URL: &heimdall.URL{URL: *targetURL, Captures: map[string]string{"id": "%25zz"}},
+				})
+			},
+			assert: func(t *testing.T, err error, backend rule.Backend, _ map[string]string) {
+				t.Helper()
+
+				require.NoError(t, err)
+				assert.Nil(t, backend)
+			},
+		},
 	} {
 		t.Run(uc, func(t *testing.T) {
 			// GIVEN
 			ctx := heimdallmocks.NewRequestContextMock(t)
 			ctx.EXPECT().Context().Return(t.Context())
 
+			urlRewriter, err := newURLRewriter(tc.backend)
+			require.NoError(t, err)
+
 			authenticator := mocks.NewSubjectCreatorMock(t)
 			authorizer := mocks.NewSubjectHandlerMock(t)
 			finalizer := mocks.NewSubjectHandlerMock(t)
@@ -443,6 +519,7 @@ func TestRuleExecute(t *testing.T) {
 
 			rul := &ruleImpl{
 				backend:         tc.backend,
+				urlRewriter:     urlRewriter,
 				slashesHandling: x.IfThenElse(len(tc.slashHandling) != 0, tc.slashHandling, config.EncodedSlashesOff),
 				sc:              compositeSubjectCreator{authenticator},
 				sh:              compositeSubjectHandler{authorizer},
@@ -460,3 +537,230 @@ func TestRuleExecute(t *testing.T) {
 		})
 	}
 }
+
+func TestRuleCreateBackendWithTargets(t *testing.T) {
+	t.Parallel()
+
+	targets := []config.Target{{Host: "a.local"}, {Host: "b.local"}, {Host: "c.local"}}
+
+	for uc, tc := range map[string]struct {
+		backend        *config.Backend
+		withBalancer   bool
+		configureMocks func(t *testing.T, reqf *heimdallmocks.RequestFunctionsMock)
+		assert         func(t *testing.T, hosts []string)
+	}{
+		"without balancer the first target is used": {
+			backend: &config.Backend{Targets: targets},
+			assert: func(t *testing.T, hosts []string) {
+				t.Helper()
+
+				for _, host := range hosts {
+					assert.Equal(t, "a.local", host)
+				}
+			},
+		},
+		"round robin": {
+			backend:      &config.Backend{Targets: targets},
+			withBalancer: true,
+			assert: func(t *testing.T, hosts []string) {
+				t.Helper()
+
+				assert.Equal(t, []string{"a.local", "b.local", "c.local", "a.local"}, hosts)
+			},
+		},
+		"consistent hash by subject": {
+			backend: &config.Backend{
+				Targets: targets,
+				LoadBalancing: &config.LoadBalancing{
+					Strategy: config.ConsistentHash,
+					HashKey:  &config.HashKey{Source: config.HashKeySourceSubject},
+				},
+			},
+			withBalancer: true,
+			assert: func(t *testing.T, hosts []string) {
+				t.Helper()
+
+				for _, host := range hosts {
+					assert.Equal(t, hosts[0], host)
+				}
+			},
+		},
+		"consistent hash by header": {
+			backend: &config.Backend{
+				Targets: targets,
+				LoadBalancing: &config.LoadBalancing{
+					Strategy: config.ConsistentHash,
+					HashKey:  &config.HashKey{Source: config.HashKeySourceHeader, Header: "X-Tenant"},
+				},
+			},
+			withBalancer: true,
+			configureMocks: func(t *testing.T, reqf *heimdallmocks.RequestFunctionsMock) {
+				t.Helper()
+
+				reqf.EXPECT().Header("X-Tenant").Return("acme")
+			},
+			assert: func(t *testing.T, hosts []string) {
+				t.Helper()
+
+				for _, host := range hosts {
+					assert.Equal(t, hosts[0], host)
+				}
+			},
+		},
+	} {
+		t.Run(uc, func(t *testing.T) {
+			// GIVEN
+			reqf := heimdallmocks.NewRequestFunctionsMock(t)
+			configureMocks := x.IfThenElse(tc.configureMocks != nil,
+				tc.configureMocks,
+				func(t *testing.T, _ *heimdallmocks.RequestFunctionsMock) { t.Helper() })
+			configureMocks(t, reqf)
+
+			rul := &ruleImpl{id: "test", backend: tc.backend}
+
+			if tc.withBalancer {
+				balancer, err := loadbalancer.New(rul.id, tc.backend)
+				require.NoError(t, err)
+
+				rul.balancer = balancer
+			}
+
+			targetURL, err := url.Parse("http://foo.local/api/v1/foo")
+			require.NoError(t, err)
+
+			req := &heimdall.Request{RequestFunctions: reqf, URL: &heimdall.URL{URL: *targetURL}}
+			sub := &subject.Subject{ID: "foo"}
+
+			var hosts []string
+
+			// WHEN
+			for range 4 {
+				backend, err := rul.createBackend(t.Context(), req, sub)
+				require.NoError(t, err)
+
+				assert.Equal(t, "/api/v1/foo", backend.URL().Path)
+				hosts = append(hosts, backend.URL().Host)
+
+				backend.Done(t.Context(), 200, nil)
+			}
+
+			// THEN
+			tc.assert(t, hosts)
+		})
+	}
+}
+
+func TestRuleBackendRetriesOnAnotherTarget(t *testing.T) {
+	t.Parallel()
+
+	// GIVEN
+	failing := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+		rw.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer failing.Close()
+
+	healthy := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+		rw.WriteHeader(http.StatusOK)
+	}))
+	defer healthy.Close()
+
+	failingURL, err := url.Parse(failing.URL)
+	require.NoError(t, err)
+
+	healthyURL, err := url.Parse(healthy.URL)
+	require.NoError(t, err)
+
+	conf := &config.Backend{
+		Targets: []config.Target{{Host: failingURL.Host}, {Host: healthyURL.Host}},
+		Retry: &config.Retry{
+			MaxAttempts: 2,
+			Backoff:     &config.Backoff{Initial: config.Duration(time.Millisecond), Max: config.Duration(time.Millisecond)},
+		},
+	}
+
+	balancer, err := loadbalancer.New("test", conf)
+	require.NoError(t, err)
+
+	policy, err := resilience.New("test", conf)
+	require.NoError(t, err)
+
+	rul := &ruleImpl{id: "test", backend: conf, balancer: balancer, policy: policy}
+
+	targetURL, err := url.Parse("http://foo.local/api")
+	require.NoError(t, err)
+
+	req := &heimdall.Request{
+		RequestFunctions: heimdallmocks.NewRequestFunctionsMock(t),
+		URL:              &heimdall.URL{URL: *targetURL},
+	}
+
+	for range 4 {
+		backend, err := rul.createBackend(t.Context(), req, &subject.Subject{ID: "foo"})
+		require.NoError(t, err)
+
+		upstreamReq, err := http.NewRequestWithContext(t.Context(), http.MethodGet, backend.URL().String(), nil)
+		require.NoError(t, err)
+
+		// WHEN
+		resp, err := backend.RoundTripper(http.DefaultTransport).RoundTrip(upstreamReq)
+
+		// THEN
+		require.NoError(t, err)
+		resp.Body.Close()
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		backend.Done(t.Context(), resp.StatusCode, nil)
+	}
+}
+
+func TestRuleActivateDeactivate(t *testing.T) {
+	t.Parallel()
+
+	// GIVEN
+	conf := &config.BackendTLS{TrustStore: &config.TrustStore{Path: "/path/to/trust_store.pem"}}
+	settings := &rule.TransportSettings{}
+	cw := watchermocks.NewWatcherMock(t)
+	reloader := newTLSReloader(conf, settings, cw)
+
+	rul := &ruleImpl{id: "test", transportSettings: settings, tlsReloader: reloader}
+
+	cw.EXPECT().Add("/path/to/trust_store.pem", reloader).Return(nil).Once()
+	cw.EXPECT().Remove("/path/to/trust_store.pem", reloader).Once()
+
+	// WHEN
+	err := rul.activate()
+
+	// THEN
+	require.NoError(t, err)
+
+	// WHEN
+	rul.deactivate()
+
+	// THEN expectations are met
+}
+
+func TestRuleActivateWithFailingWatcher(t *testing.T) {
+	t.Parallel()
+
+	// GIVEN
+	conf := &config.BackendTLS{
+		KeyStore:   &config.KeyStore{Path: "/path/to/key_store.pem"},
+		TrustStore: &config.TrustStore{Path: "/path/to/trust_store.pem"},
+	}
+	cw := watchermocks.NewWatcherMock(t)
+	reloader := newTLSReloader(conf, &rule.TransportSettings{}, cw)
+
+	rul := &ruleImpl{id: "test", tlsReloader: reloader}
+
+	cw.EXPECT().Add("/path/to/key_store.pem", reloader).Return(nil).Once()
+	cw.EXPECT().Add("/path/to/trust_store.pem", reloader).Return(errors.New("test error")).Once()
+	cw.EXPECT().Remove(mock.Anything, reloader).Twice()
+
+	// WHEN
+	err := rul.activate()
+
+	// THEN
+	require.Error(t, err)
+	require.ErrorContains(t, err, "test error")
+}
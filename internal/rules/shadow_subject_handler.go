@@ -0,0 +1,86 @@
+// Copyright 2025 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package rules
+
+import (
+	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/dadrus/heimdall/internal/heimdall"
+	"github.com/dadrus/heimdall/internal/rules/mechanisms/subject"
+	"github.com/dadrus/heimdall/version"
+)
+
+const (
+	ruleIDAttrKey      = attribute.Key("rule_id")
+	mechanismIDAttrKey = attribute.Key("mechanism_id")
+)
+
+// shadowSubjectHandler executes a not enforced (shadow mode) pipeline step. Failures of the
+// wrapped handler are recorded in logs, metrics and traces, but do not stop the pipeline execution.
+type shadowSubjectHandler struct {
+	h       subjectHandler
+	ruleID  string
+	denials metric.Int64Counter
+}
+
+func newShadowSubjectHandler(ruleID string, handler subjectHandler) (*shadowSubjectHandler, error) {
+	meter := otel.GetMeterProvider().Meter(
+		"github.com/dadrus/heimdall/internal/rules",
+		metric.WithInstrumentationVersion(version.Version),
+	)
+
+	denials, err := meter.Int64Counter(
+		"authorization.shadow.denials",
+		metric.WithDescription("Number of requests, which would have been denied by not enforced authorizers"),
+		metric.WithUnit("{request}"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &shadowSubjectHandler{h: handler, ruleID: ruleID, denials: denials}, nil
+}
+
+func (h *shadowSubjectHandler) Execute(ctx heimdall.RequestContext, sub *subject.Subject) error {
+	err := h.h.Execute(ctx, sub)
+	if err == nil {
+		return nil
+	}
+
+	logger := zerolog.Ctx(ctx.Context())
+	logger.Warn().
+		Err(err).
+		Str("_rule_id", h.ruleID).
+		Str("_id", h.h.ID()).
+		Msg("Not enforced authorizer would deny the request. Continuing pipeline execution")
+
+	attrs := []attribute.KeyValue{ruleIDAttrKey.String(h.ruleID), mechanismIDAttrKey.String(h.h.ID())}
+
+	h.denials.Add(ctx.Context(), 1, metric.WithAttributes(attrs...))
+	trace.SpanFromContext(ctx.Context()).AddEvent("shadow authorization denial",
+		trace.WithAttributes(append(attrs, attribute.String("error", err.Error()))...))
+
+	return nil
+}
+
+func (h *shadowSubjectHandler) ID() string { return h.h.ID() }
+
+func (h *shadowSubjectHandler) ContinueOnError() bool { return true }
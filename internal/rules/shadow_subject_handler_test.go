@@ -0,0 +1,150 @@
+// Copyright 2025 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package rules
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/dadrus/heimdall/internal/heimdall/mocks"
+	rulemocks "github.com/dadrus/heimdall/internal/rules/mocks"
+)
+
+func TestShadowSubjectHandlerExecute(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		uc             string
+		configureMocks func(t *testing.T, h *rulemocks.SubjectHandlerMock)
+		assert         func(t *testing.T, err error, rm metricdata.ResourceMetrics, spans tracetest.SpanStubs)
+	}{
+		{
+			uc: "handler succeeds",
+			configureMocks: func(t *testing.T, h *rulemocks.SubjectHandlerMock) {
+				t.Helper()
+
+				h.EXPECT().Execute(mock.Anything, mock.Anything).Return(nil)
+			},
+			assert: func(t *testing.T, err error, rm metricdata.ResourceMetrics, spans tracetest.SpanStubs) {
+				t.Helper()
+
+				require.NoError(t, err)
+				assert.Empty(t, rm.ScopeMetrics)
+				require.Len(t, spans, 1)
+				assert.Empty(t, spans[0].Events)
+			},
+		},
+		{
+			uc: "handler fails",
+			configureMocks: func(t *testing.T, h *rulemocks.SubjectHandlerMock) {
+				t.Helper()
+
+				h.EXPECT().Execute(mock.Anything, mock.Anything).Return(errors.New("test error"))
+				h.EXPECT().ID().Return("authz")
+			},
+			assert: func(t *testing.T, err error, rm metricdata.ResourceMetrics, spans tracetest.SpanStubs) {
+				t.Helper()
+
+				require.NoError(t, err)
+
+				require.Len(t, rm.ScopeMetrics, 1)
+				require.Len(t, rm.ScopeMetrics[0].Metrics, 1)
+
+				denials := rm.ScopeMetrics[0].Metrics[0]
+				assert.Equal(t, "authorization.shadow.denials", denials.Name)
+
+				sum, ok := denials.Data.(metricdata.Sum[int64])
+				require.True(t, ok)
+				require.Len(t, sum.DataPoints, 1)
+				assert.Equal(t, int64(1), sum.DataPoints[0].Value)
+
+				ruleID, _ := sum.DataPoints[0].Attributes.Value(ruleIDAttrKey)
+				mechanismID, _ := sum.DataPoints[0].Attributes.Value(mechanismIDAttrKey)
+				assert.Equal(t, "rule1", ruleID.AsString())
+				assert.Equal(t, "authz", mechanismID.AsString())
+
+				require.Len(t, spans, 1)
+				require.Len(t, spans[0].Events, 1)
+				assert.Equal(t, "shadow authorization denial", spans[0].Events[0].Name)
+				assert.Contains(t, spans[0].Events[0].Attributes, ruleIDAttrKey.String("rule1"))
+				assert.Contains(t, spans[0].Events[0].Attributes, mechanismIDAttrKey.String("authz"))
+			},
+		},
+	} {
+		t.Run(tc.uc, func(t *testing.T) {
+			// GIVEN
+			reader := sdkmetric.NewManualReader()
+			meterProvider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+			denials, err := meterProvider.Meter("test").Int64Counter("authorization.shadow.denials")
+			require.NoError(t, err)
+
+			recorder := tracetest.NewSpanRecorder()
+			tracerProvider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+			spanCtx, span := tracerProvider.Tracer("test").Start(t.Context(), "test")
+
+			handler := rulemocks.NewSubjectHandlerMock(t)
+			decorator := shadowSubjectHandler{h: handler, ruleID: "rule1", denials: denials}
+
+			ctx := mocks.NewRequestContextMock(t)
+			ctx.EXPECT().Context().Maybe().Return(spanCtx)
+
+			tc.configureMocks(t, handler)
+
+			// WHEN
+			err = decorator.Execute(ctx, nil)
+
+			// THEN
+			span.End()
+
+			var rm metricdata.ResourceMetrics
+			require.NoError(t, reader.Collect(t.Context(), &rm))
+
+			tc.assert(t, err, rm, tracetest.SpanStubsFromReadOnlySpans(recorder.Ended()))
+		})
+	}
+}
+
+func TestShadowSubjectHandlerContinueOnError(t *testing.T) {
+	t.Parallel()
+
+	handler, err := newShadowSubjectHandler("rule1", rulemocks.NewSubjectHandlerMock(t))
+	require.NoError(t, err)
+
+	assert.True(t, handler.ContinueOnError())
+}
+
+func TestShadowSubjectHandlerID(t *testing.T) {
+	t.Parallel()
+
+	handler := rulemocks.NewSubjectHandlerMock(t)
+	handler.EXPECT().ID().Return("test")
+
+	decorator, err := newShadowSubjectHandler("rule1", handler)
+	require.NoError(t, err)
+
+	assert.Equal(t, "test", decorator.ID())
+}
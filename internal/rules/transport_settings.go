@@ -0,0 +1,161 @@
+// Copyright 2025 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package rules
+
+import (
+	"crypto/tls"
+	"slices"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/dadrus/heimdall/internal/config"
+	"github.com/dadrus/heimdall/internal/heimdall"
+	config2 "github.com/dadrus/heimdall/internal/rules/config"
+	"github.com/dadrus/heimdall/internal/rules/rule"
+	"github.com/dadrus/heimdall/internal/truststore"
+	"github.com/dadrus/heimdall/internal/watcher"
+	"github.com/dadrus/heimdall/internal/x/errorchain"
+	"github.com/dadrus/heimdall/internal/x/tlsx"
+)
+
+func newTransportSettings(conf *config2.Backend) (*rule.TransportSettings, error) {
+	if !conf.HasTransportSettings() {
+		return nil, nil //nolint:nilnil
+	}
+
+	settings := &rule.TransportSettings{}
+
+	if conf.TLS != nil {
+		tlsConf, err := newTLSConfig(conf.TLS)
+		if err != nil {
+			return nil, err
+		}
+
+		settings.SetTLSConfig(tlsConf)
+	}
+
+	if conf.Connection != nil {
+		settings.MaxPerHost = conf.Connection.MaxPerHost
+		settings.MaxIdle = conf.Connection.MaxIdle
+		settings.MaxIdlePerHost = conf.Connection.MaxIdlePerHost
+	}
+
+	if conf.Timeout != nil {
+		settings.ConnectTimeout = time.Duration(conf.Timeout.Connect)
+		settings.ResponseTimeout = time.Duration(conf.Timeout.Response)
+	}
+
+	return settings, nil
+}
+
+func newTLSConfig(conf *config2.BackendTLS) (*tls.Config, error) {
+	tlsConf := &config.TLS{}
+
+	switch conf.MinVersion {
+	case "TLS1.2":
+		tlsConf.MinVersion = tls.VersionTLS12
+	case "TLS1.3":
+		tlsConf.MinVersion = tls.VersionTLS13
+	}
+
+	if conf.KeyStore != nil {
+		tlsConf.KeyStore = config.KeyStore{Path: conf.KeyStore.Path, Password: conf.KeyStore.Password}
+		tlsConf.KeyID = conf.KeyID
+	}
+
+	cfg, err := tlsx.ToTLSConfig(tlsConf, tlsx.WithClientAuthentication(conf.KeyStore != nil))
+	if err != nil {
+		return nil, errorchain.NewWithMessage(heimdall.ErrConfiguration,
+			"failed loading backend tls key store").CausedBy(err)
+	}
+
+	cfg.ServerName = conf.ServerName
+
+	if conf.TrustStore != nil {
+		ts, err := truststore.NewTrustStoreFromPEMFile(conf.TrustStore.Path, false)
+		if err != nil {
+			return nil, errorchain.NewWithMessage(heimdall.ErrConfiguration,
+				"failed loading backend tls trust store").CausedBy(err)
+		}
+
+		cfg.RootCAs = ts.CertPool()
+	}
+
+	return cfg, nil
+}
+
+// tlsReloader reloads the TLS configuration of a backend if its key or trust store
+// changes, which results in the transport of the backend being replaced.
+type tlsReloader struct {
+	conf     *config2.BackendTLS
+	settings *rule.TransportSettings
+	cw       watcher.Watcher
+}
+
+func newTLSReloader(conf *config2.BackendTLS, settings *rule.TransportSettings, cw watcher.Watcher) *tlsReloader {
+	if conf == nil || settings == nil || (conf.KeyStore == nil && conf.TrustStore == nil) {
+		return nil
+	}
+
+	return &tlsReloader{conf: conf, settings: settings, cw: cw}
+}
+
+func (r *tlsReloader) paths() []string {
+	var paths []string
+
+	if r.conf.KeyStore != nil {
+		paths = append(paths, r.conf.KeyStore.Path)
+	}
+
+	if r.conf.TrustStore != nil && !slices.Contains(paths, r.conf.TrustStore.Path) {
+		paths = append(paths, r.conf.TrustStore.Path)
+	}
+
+	return paths
+}
+
+func (r *tlsReloader) start() error {
+	for _, path := range r.paths() {
+		if err := r.cw.Add(path, r); err != nil {
+			r.stop()
+
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *tlsReloader) stop() {
+	for _, path := range r.paths() {
+		r.cw.Remove(path, r)
+	}
+}
+
+func (r *tlsReloader) OnChanged(logger zerolog.Logger) {
+	tlsConf, err := newTLSConfig(r.conf)
+	if err != nil {
+		logger.Warn().Err(err).Msg("Backend TLS configuration reload failed")
+
+		return
+	}
+
+	r.settings.SetTLSConfig(tlsConf)
+
+	logger.Info().Msg("Backend TLS configuration reloaded")
+}
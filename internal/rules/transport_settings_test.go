@@ -0,0 +1,289 @@
+// Copyright 2025 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package rules
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dadrus/heimdall/internal/heimdall"
+	config2 "github.com/dadrus/heimdall/internal/rules/config"
+	"github.com/dadrus/heimdall/internal/rules/rule"
+	"github.com/dadrus/heimdall/internal/watcher/mocks"
+	"github.com/dadrus/heimdall/internal/x/pkix/pemx"
+	"github.com/dadrus/heimdall/internal/x/testsupport"
+)
+
+func TestNewTransportSettings(t *testing.T) {
+	t.Parallel()
+
+	key, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	require.NoError(t, err)
+
+	cert, err := testsupport.NewCertificateBuilder(
+		testsupport.WithSerialNumber(big.NewInt(1)),
+		testsupport.WithValidity(time.Now(), 10*time.Hour),
+		testsupport.WithSubject(pkix.Name{
+			CommonName:   "test cert",
+			Organization: []string{"Test"},
+			Country:      []string{"EU"},
+		}),
+		testsupport.WithSubjectPubKey(&key.PublicKey, x509.ECDSAWithSHA384),
+		testsupport.WithSignaturePrivKey(key),
+		testsupport.WithKeyUsage(x509.KeyUsageDigitalSignature),
+		testsupport.WithExtendedKeyUsage(x509.ExtKeyUsageClientAuth),
+		testsupport.WithGeneratedSubjectKeyID(),
+		testsupport.WithSelfSigned(),
+	).Build()
+	require.NoError(t, err)
+
+	pemBytes, err := pemx.BuildPEM(
+		pemx.WithECDSAPrivateKey(key),
+		pemx.WithX509Certificate(cert),
+	)
+	require.NoError(t, err)
+
+	pemFile := filepath.Join(t.TempDir(), "keystore.pem")
+	require.NoError(t, os.WriteFile(pemFile, pemBytes, 0o600))
+
+	for uc, tc := range map[string]struct {
+		backend *config2.Backend
+		assert  func(t *testing.T, err error, settings *rule.TransportSettings)
+	}{
+		"no backend": {
+			assert: func(t *testing.T, err error, settings *rule.TransportSettings) {
+				t.Helper()
+
+				require.NoError(t, err)
+				assert.Nil(t, settings)
+			},
+		},
+		"backend without transport settings": {
+			backend: &config2.Backend{Host: "foo.bar"},
+			assert: func(t *testing.T, err error, settings *rule.TransportSettings) {
+				t.Helper()
+
+				require.NoError(t, err)
+				assert.Nil(t, settings)
+			},
+		},
+		"backend with connection settings only": {
+			backend: &config2.Backend{
+				Host:       "foo.bar",
+				Connection: &config2.Connection{MaxPerHost: 10, MaxIdle: 20, MaxIdlePerHost: 5},
+			},
+			assert: func(t *testing.T, err error, settings *rule.TransportSettings) {
+				t.Helper()
+
+				require.NoError(t, err)
+				require.NotNil(t, settings)
+				assert.Nil(t, settings.TLSConfig())
+				assert.Equal(t, 10, settings.MaxPerHost)
+				assert.Equal(t, 20, settings.MaxIdle)
+				assert.Equal(t, 5, settings.MaxIdlePerHost)
+			},
+		},
+		"backend with timeout settings only": {
+			backend: &config2.Backend{
+				Host:    "foo.bar",
+				Timeout: &config2.Timeout{Connect: config2.Duration(time.Second), Response: config2.Duration(time.Minute)},
+			},
+			assert: func(t *testing.T, err error, settings *rule.TransportSettings) {
+				t.Helper()
+
+				require.NoError(t, err)
+				require.NotNil(t, settings)
+				assert.Equal(t, time.Second, settings.ConnectTimeout)
+				assert.Equal(t, time.Minute, settings.ResponseTimeout)
+				assert.Zero(t, settings.MaxPerHost)
+			},
+		},
+		"backend with tls trust store only": {
+			backend: &config2.Backend{
+				Host: "foo.bar",
+				TLS: &config2.BackendTLS{
+					TrustStore: &config2.TrustStore{Path: pemFile},
+					ServerName: "baz.bar",
+				},
+			},
+			assert: func(t *testing.T, err error, settings *rule.TransportSettings) {
+				t.Helper()
+
+				require.NoError(t, err)
+				require.NotNil(t, settings)
+				require.NotNil(t, settings.TLSConfig())
+				assert.NotNil(t, settings.TLSConfig().RootCAs)
+				assert.Nil(t, settings.TLSConfig().GetClientCertificate)
+				assert.Equal(t, "baz.bar", settings.TLSConfig().ServerName)
+				assert.Equal(t, uint16(tls.VersionTLS13), settings.TLSConfig().MinVersion)
+			},
+		},
+		"backend with tls key store for mTLS": {
+			backend: &config2.Backend{
+				Host: "foo.bar",
+				TLS: &config2.BackendTLS{
+					KeyStore:   &config2.KeyStore{Path: pemFile},
+					MinVersion: "TLS1.2",
+				},
+			},
+			assert: func(t *testing.T, err error, settings *rule.TransportSettings) {
+				t.Helper()
+
+				require.NoError(t, err)
+				require.NotNil(t, settings)
+				require.NotNil(t, settings.TLSConfig())
+				assert.Nil(t, settings.TLSConfig().RootCAs)
+				assert.Equal(t, uint16(tls.VersionTLS12), settings.TLSConfig().MinVersion)
+				require.NotNil(t, settings.TLSConfig().GetClientCertificate)
+
+				clientCert, err := settings.TLSConfig().GetClientCertificate(&tls.CertificateRequestInfo{
+					SignatureSchemes: []tls.SignatureScheme{tls.ECDSAWithP384AndSHA384},
+					Version:          tls.VersionTLS13,
+				})
+				require.NoError(t, err)
+				assert.Equal(t, cert, clientCert.Leaf)
+			},
+		},
+		"backend with not existing trust store": {
+			backend: &config2.Backend{
+				Host: "foo.bar",
+				TLS:  &config2.BackendTLS{TrustStore: &config2.TrustStore{Path: "/does/not/exist.pem"}},
+			},
+			assert: func(t *testing.T, err error, _ *rule.TransportSettings) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				require.ErrorContains(t, err, "trust store")
+			},
+		},
+		"backend with not existing key store": {
+			backend: &config2.Backend{
+				Host: "foo.bar",
+				TLS:  &config2.BackendTLS{KeyStore: &config2.KeyStore{Path: "/does/not/exist.pem"}},
+			},
+			assert: func(t *testing.T, err error, _ *rule.TransportSettings) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				require.ErrorContains(t, err, "key store")
+			},
+		},
+	} {
+		t.Run(uc, func(t *testing.T) {
+			// WHEN
+			settings, err := newTransportSettings(tc.backend)
+
+			// THEN
+			tc.assert(t, err, settings)
+		})
+	}
+}
+
+func TestTLSReloader(t *testing.T) {
+	t.Parallel()
+
+	// GIVEN
+	newKeyStore := func(t *testing.T, serial int64) []byte {
+		t.Helper()
+
+		key, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+		require.NoError(t, err)
+
+		cert, err := testsupport.NewCertificateBuilder(
+			testsupport.WithSerialNumber(big.NewInt(serial)),
+			testsupport.WithValidity(time.Now(), 10*time.Hour),
+			testsupport.WithSubject(pkix.Name{CommonName: "test cert"}),
+			testsupport.WithSubjectPubKey(&key.PublicKey, x509.ECDSAWithSHA384),
+			testsupport.WithSignaturePrivKey(key),
+			testsupport.WithKeyUsage(x509.KeyUsageDigitalSignature),
+			testsupport.WithExtendedKeyUsage(x509.ExtKeyUsageClientAuth),
+			testsupport.WithSelfSigned(),
+		).Build()
+		require.NoError(t, err)
+
+		pemBytes, err := pemx.BuildPEM(pemx.WithECDSAPrivateKey(key), pemx.WithX509Certificate(cert))
+		require.NoError(t, err)
+
+		return pemBytes
+	}
+
+	pemFile := filepath.Join(t.TempDir(), "keystore.pem")
+	require.NoError(t, os.WriteFile(pemFile, newKeyStore(t, 1), 0o600))
+
+	conf := &config2.BackendTLS{
+		KeyStore:   &config2.KeyStore{Path: pemFile},
+		TrustStore: &config2.TrustStore{Path: pemFile},
+	}
+
+	settings, err := newTransportSettings(&config2.Backend{Host: "foo.bar", TLS: conf})
+	require.NoError(t, err)
+
+	cw := mocks.NewWatcherMock(t)
+	reloader := newTLSReloader(conf, settings, cw)
+	require.NotNil(t, reloader)
+
+	cw.EXPECT().Add(pemFile, reloader).Return(nil).Once()
+	cw.EXPECT().Remove(pemFile, reloader).Once()
+
+	require.NoError(t, reloader.start())
+
+	initial := settings.TLSConfig()
+
+	// WHEN the key store is renewed
+	require.NoError(t, os.WriteFile(pemFile, newKeyStore(t, 2), 0o600))
+	reloader.OnChanged(log.Logger)
+
+	// THEN
+	reloaded := settings.TLSConfig()
+	require.NotNil(t, reloaded)
+	assert.NotSame(t, initial, reloaded)
+	assert.False(t, initial.RootCAs.Equal(reloaded.RootCAs))
+
+	// WHEN the key store is broken
+	require.NoError(t, os.WriteFile(pemFile, []byte("foo"), 0o600))
+	reloader.OnChanged(log.Logger)
+
+	// THEN the last valid configuration is kept
+	assert.Same(t, reloaded, settings.TLSConfig())
+
+	reloader.stop()
+}
+
+func TestNewTLSReloaderWithoutKeyMaterial(t *testing.T) {
+	t.Parallel()
+
+	// WHEN
+	reloader := newTLSReloader(&config2.BackendTLS{ServerName: "foo.bar"}, &rule.TransportSettings{}, nil)
+
+	// THEN
+	assert.Nil(t, reloader)
+}
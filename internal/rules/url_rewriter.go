@@ -0,0 +1,179 @@
+// Copyright 2025 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package rules
+
+import (
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/dadrus/heimdall/internal/heimdall"
+	"github.com/dadrus/heimdall/internal/rules/config"
+	"github.com/dadrus/heimdall/internal/rules/mechanisms/template"
+	"github.com/dadrus/heimdall/internal/x/errorchain"
+)
+
+var replacementReference = regexp.MustCompile(`\$(\$|\{[^}]*}|\w+)`)
+
+// urlRewriter applies those rewrites of the upstream url, which require compilation or depend
+// on the actual request. These are applied after the rewrites done by config.URLRewriter.
+type urlRewriter struct {
+	pathRegex       *regexp.Regexp
+	pathReplacement string
+	pathTemplate    template.Template
+	queryToRename   map[string]string
+	queryToAdd      map[string]template.Template
+}
+
+func newURLRewriter(conf *config.Backend) (*urlRewriter, error) {
+	if conf == nil || !conf.URLRewriter.HasDynamicRewrites() {
+		return nil, nil //nolint:nilnil
+	}
+
+	var (
+		rc  = conf.URLRewriter
+		rw  = &urlRewriter{queryToRename: rc.QueryParamsToRename}
+		err error
+	)
+
+	if rc.PathReplacement != nil {
+		if rw.pathRegex, err = regexp.Compile(rc.PathReplacement.Regex); err != nil {
+			return nil, errorchain.NewWithMessage(heimdall.ErrConfiguration,
+				"failed to compile path replacement regex").CausedBy(err)
+		}
+
+		if err = checkReplacementReferences(rw.pathRegex, rc.PathReplacement.Replacement); err != nil {
+			return nil, err
+		}
+
+		rw.pathReplacement = rc.PathReplacement.Replacement
+	}
+
+	if len(rc.PathTemplate) != 0 {
+		if rw.pathTemplate, err = template.New(rc.PathTemplate); err != nil {
+			return nil, errorchain.NewWithMessage(heimdall.ErrConfiguration,
+				"failed to parse path template").CausedBy(err)
+		}
+	}
+
+	if len(rc.QueryParamsToAdd) != 0 {
+		rw.queryToAdd = make(map[string]template.Template, len(rc.QueryParamsToAdd))
+
+		for name, value := range rc.QueryParamsToAdd {
+			if rw.queryToAdd[name], err = template.New(value); err != nil {
+				return nil, errorchain.NewWithMessagef(heimdall.ErrConfiguration,
+					"failed to parse template for query parameter '%s'", name).CausedBy(err)
+			}
+		}
+	}
+
+	return rw, nil
+}
+
+// checkReplacementReferences verifies the replacement does only reference capture groups
+// existing in the given regex. Otherwise, these would be silently replaced with empty strings.
+func checkReplacementReferences(regex *regexp.Regexp, replacement string) error {
+	for _, match := range replacementReference.FindAllStringSubmatch(replacement, -1) {
+		ref := strings.TrimSuffix(strings.TrimPrefix(match[1], "{"), "}")
+		if ref == "$" {
+			continue
+		}
+
+		if idx, err := strconv.Atoi(ref); err == nil {
+			if idx > regex.NumSubexp() {
+				return errorchain.NewWithMessagef(heimdall.ErrConfiguration,
+					"path replacement references not existing capture group '%s'", ref)
+			}
+
+			continue
+		}
+
+		if regex.SubexpIndex(ref) == -1 {
+			return errorchain.NewWithMessagef(heimdall.ErrConfiguration,
+				"path replacement references not existing capture group '%s'", ref)
+		}
+	}
+
+	return nil
+}
+
+func (r *urlRewriter) rewrite(value *url.URL, values map[string]any) error {
+	if r.pathRegex != nil {
+		if err := setPath(value, r.pathRegex.ReplaceAllString(value.EscapedPath(), r.pathReplacement)); err != nil {
+			return err
+		}
+	}
+
+	if r.pathTemplate != nil {
+		path, err := r.pathTemplate.Render(values)
+		if err != nil {
+			return errorchain.NewWithMessage(heimdall.ErrInternal,
+				"failed to render path template").CausedBy(err)
+		}
+
+		if err = setPath(value, path); err != nil {
+			return err
+		}
+	}
+
+	if len(r.queryToRename) == 0 && len(r.queryToAdd) == 0 {
+		return nil
+	}
+
+	query := value.Query()
+
+	for from, to := range r.queryToRename {
+		if params, ok := query[from]; ok {
+			query.Del(from)
+			query[to] = append(query[to], params...)
+		}
+	}
+
+	for name, tpl := range r.queryToAdd {
+		param, err := tpl.Render(values)
+		if err != nil {
+			return errorchain.NewWithMessagef(heimdall.ErrInternal,
+				"failed to render value for query parameter '%s'", name).CausedBy(err)
+		}
+
+		// replaces the parameter if present to prevent spoofing by the client
+		query.Set(name, param)
+	}
+
+	value.RawQuery = query.Encode()
+
+	return nil
+}
+
+func setPath(value *url.URL, rawPath string) error {
+	path, err := url.PathUnescape(rawPath)
+	if err != nil {
+		return errorchain.NewWithMessage(heimdall.ErrInternal,
+			"rewritten upstream url path is invalid").CausedBy(err)
+	}
+
+	value.Path = path
+	value.RawPath = ""
+
+	if path != rawPath {
+		// the new path contains url encoded parts
+		value.RawPath = rawPath
+	}
+
+	return nil
+}
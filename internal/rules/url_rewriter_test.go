@@ -0,0 +1,264 @@
+// Copyright 2025 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package rules
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dadrus/heimdall/internal/heimdall"
+	"github.com/dadrus/heimdall/internal/rules/config"
+	"github.com/dadrus/heimdall/internal/rules/mechanisms/subject"
+)
+
+func TestNewURLRewriter(t *testing.T) {
+	t.Parallel()
+
+	for uc, tc := range map[string]struct {
+		backend *config.Backend
+		assert  func(t *testing.T, err error, rw *urlRewriter)
+	}{
+		"no backend": {
+			assert: func(t *testing.T, err error, rw *urlRewriter) {
+				t.Helper()
+
+				require.NoError(t, err)
+				assert.Nil(t, rw)
+			},
+		},
+		"backend without dynamic rewrites": {
+			backend: &config.Backend{Host: "foo.bar", URLRewriter: &config.URLRewriter{PathPrefixToCut: "/api"}},
+			assert: func(t *testing.T, err error, rw *urlRewriter) {
+				t.Helper()
+
+				require.NoError(t, err)
+				assert.Nil(t, rw)
+			},
+		},
+		"invalid path regex": {
+			backend: &config.Backend{Host: "foo.bar", URLRewriter: &config.URLRewriter{
+				PathReplacement: &config.PathReplacement{Regex: "^/(foo"},
+			}},
+			assert: func(t *testing.T, err error, _ *urlRewriter) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				require.ErrorContains(t, err, "failed to compile path replacement regex")
+			},
+		},
+		"replacement references not existing numbered group": {
+			backend: &config.Backend{Host: "foo.bar", URLRewriter: &config.URLRewriter{
+				PathReplacement: &config.PathReplacement{Regex: "^/users/([^/]+)$", Replacement: "/profiles/$2"},
+			}},
+			assert: func(t *testing.T, err error, _ *urlRewriter) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				require.ErrorContains(t, err, "not existing capture group '2'")
+			},
+		},
+		"replacement references not existing named group": {
+			backend: &config.Backend{Host: "foo.bar", URLRewriter: &config.URLRewriter{
+				PathReplacement: &config.PathReplacement{Regex: "^/users/(?P<id>[^/]+)$", Replacement: "/profiles/$idx"},
+			}},
+			assert: func(t *testing.T, err error, _ *urlRewriter) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				require.ErrorContains(t, err, "not existing capture group 'idx'")
+			},
+		},
+		"invalid path template": {
+			backend: &config.Backend{Host: "foo.bar", URLRewriter: &config.URLRewriter{
+				PathTemplate: "/profiles/{{ .Request.URL.Captures.id ",
+			}},
+			assert: func(t *testing.T, err error, _ *urlRewriter) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				require.ErrorContains(t, err, "failed to parse path template")
+			},
+		},
+		"invalid query parameter template": {
+			backend: &config.Backend{Host: "foo.bar", URLRewriter: &config.URLRewriter{
+				QueryParamsToAdd: map[string]string{"user": "{{ .Subject.ID "},
+			}},
+			assert: func(t *testing.T, err error, _ *urlRewriter) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				require.ErrorContains(t, err, "query parameter 'user'")
+			},
+		},
+		"valid configuration": {
+			backend: &config.Backend{Host: "foo.bar", URLRewriter: &config.URLRewriter{
+				PathReplacement: &config.PathReplacement{
+					Regex:       "^/users/(?P<id>[^/]+)/(profile)$",
+					Replacement: "/${2}s/$id/$$1",
+				},
+				QueryParamsToRename: map[string]string{"foo": "bar"},
+				QueryParamsToAdd:    map[string]string{"user": "{{ .Subject.ID }}"},
+			}},
+			assert: func(t *testing.T, err error, rw *urlRewriter) {
+				t.Helper()
+
+				require.NoError(t, err)
+				require.NotNil(t, rw)
+				assert.NotNil(t, rw.pathRegex)
+				assert.Nil(t, rw.pathTemplate)
+				assert.Len(t, rw.queryToAdd, 1)
+				assert.Equal(t, map[string]string{"foo": "bar"}, rw.queryToRename)
+			},
+		},
+	} {
+		t.Run(uc, func(t *testing.T) {
+			t.Parallel()
+
+			// WHEN
+			rw, err := newURLRewriter(tc.backend)
+
+			// THEN
+			tc.assert(t, err, rw)
+		})
+	}
+}
+
+func TestURLRewriterRewrite(t *testing.T) {
+	t.Parallel()
+
+	for uc, tc := range map[string]struct {
+		rewriter *config.URLRewriter
+		original string
+		captures map[string]string
+		expected string
+	}{
+		"regex based path replacement": {
+			rewriter: &config.URLRewriter{
+				PathReplacement: &config.PathReplacement{
+					Regex:       "^/api/v1/users/([^/]+)/profile$",
+					Replacement: "/profiles/$1",
+				},
+			},
+			original: "https://foo.bar/api/v1/users/alice/profile?foo=bar",
+			expected: "https://foo.bar/profiles/alice?foo=bar",
+		},
+		"regex based path replacement not matching": {
+			rewriter: &config.URLRewriter{
+				PathReplacement: &config.PathReplacement{
+					Regex:       "^/api/v1/users/([^/]+)/profile$",
+					Replacement: "/profiles/$1",
+				},
+			},
+			original: "https://foo.bar/api/v1/users/alice?foo=bar",
+			expected: "https://foo.bar/api/v1/users/alice?foo=bar",
+		},
+		"regex based path replacement keeping url encoded parts": {
+			rewriter: &config.URLRewriter{
+				PathReplacement: &config.PathReplacement{
+					Regex:       "^/files/(?P<name>.+)$",
+					Replacement: "/storage/${name}",
+				},
+			},
+			original: "https://foo.bar/files/%5Bid%5D%2Fbar",
+			expected: "https://foo.bar/storage/%5Bid%5D%2Fbar",
+		},
+		"path created from template using captures": {
+			rewriter: &config.URLRewriter{
+				PathTemplate: "/profiles/{{ .Request.URL.Captures.id }}",
+			},
+			original: "https://foo.bar/api/v1/users/alice/profile",
+			captures: map[string]string{"id": "alice"},
+			expected: "https://foo.bar/profiles/alice",
+		},
+		"path segment moved into query parameter": {
+			rewriter: &config.URLRewriter{
+				PathReplacement:  &config.PathReplacement{Regex: "^/tenants/[^/]+", Replacement: ""},
+				QueryParamsToAdd: map[string]string{"tenant": "{{ .Request.URL.Captures.tenant }}"},
+			},
+			original: "https://foo.bar/tenants/acme/orders?limit=10",
+			captures: map[string]string{"tenant": "acme"},
+			expected: "https://foo.bar/orders?limit=10&tenant=acme",
+		},
+		"query parameters renamed and added": {
+			rewriter: &config.URLRewriter{
+				QueryParamsToRename: map[string]string{"q": "query", "missing": "foo"},
+				QueryParamsToAdd:    map[string]string{"user": "{{ .Subject.ID }}", "query": "baz"},
+			},
+			original: "https://foo.bar/search?q=foo&q=bar&user=mallory",
+			expected: "https://foo.bar/search?query=baz&user=alice",
+		},
+		"query parameter renamed to an existing one": {
+			rewriter: &config.URLRewriter{
+				QueryParamsToRename: map[string]string{"q": "query"},
+			},
+			original: "https://foo.bar/search?q=foo&query=bar",
+			expected: "https://foo.bar/search?query=bar&query=foo",
+		},
+	} {
+		t.Run(uc, func(t *testing.T) {
+			t.Parallel()
+
+			// GIVEN
+			rw, err := newURLRewriter(&config.Backend{Host: "foo.bar", URLRewriter: tc.rewriter})
+			require.NoError(t, err)
+
+			original, err := url.Parse(tc.original)
+			require.NoError(t, err)
+
+			req := &heimdall.Request{URL: &heimdall.URL{URL: *original, Captures: tc.captures}}
+			value := *original
+
+			// WHEN
+			err = rw.rewrite(&value, map[string]any{"Request": req, "Subject": &subject.Subject{ID: "alice"}})
+
+			// THEN
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, value.String())
+		})
+	}
+}
+
+func TestURLRewriterRewriteWithInvalidPath(t *testing.T) {
+	t.Parallel()
+
+	// GIVEN
+	rw, err := newURLRewriter(&config.Backend{Host: "foo.bar", URLRewriter: &config.URLRewriter{
+		PathTemplate: "/profiles/{{ .Request.URL.Captures.id }}",
+	}})
+	require.NoError(t, err)
+
+	original, err := url.Parse("https://foo.bar/users/alice")
+	require.NoError(t, err)
+
+	req := &heimdall.Request{URL: &heimdall.URL{URL: *original, Captures: map[string]string{"id": "%zz"}}}
+
+	// WHEN
+	err = rw.rewrite(original, map[string]any{"Request": req})
+
+	// THEN
+	require.Error(t, err)
+	require.ErrorIs(t, err, heimdall.ErrInternal)
+	require.ErrorContains(t, err, "path is invalid")
+}
@@ -0,0 +1,49 @@
+// Copyright 2025 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package scheduler
+
+import (
+	"context"
+
+	"github.com/rs/zerolog"
+	"go.uber.org/fx"
+)
+
+// Module is used on app bootstrap.
+// nolint: gochecknoglobals
+var Module = fx.Options(
+	fx.Provide(
+		fx.Annotate(
+			func(logger zerolog.Logger) (Scheduler, error) {
+				sch, err := newScheduler(logger)
+				if err != nil {
+					return nil, err
+				}
+
+				return sch, nil
+			},
+			// nolint: forcetypeassert
+			fx.OnStart(func(ctx context.Context, s Scheduler) error {
+				s.(*scheduler).start(ctx)
+
+				return nil
+			}),
+			// nolint: forcetypeassert
+			fx.OnStop(func(ctx context.Context, s Scheduler) error { return s.(*scheduler).stop(ctx) }),
+		),
+	),
+)
@@ -0,0 +1,32 @@
+// Copyright 2025 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package scheduler
+
+import (
+	"context"
+	"time"
+)
+
+//go:generate mockery --name Scheduler --structname SchedulerMock
+
+// Scheduler executes registered tasks periodically. Tasks are executed only while
+// heimdall is running and stop being executed on its shutdown.
+type Scheduler interface {
+	// Schedule registers the given task for execution every interval. The given context
+	// is passed to each execution of the task.
+	Schedule(ctx context.Context, interval time.Duration, task func(ctx context.Context)) error
+}
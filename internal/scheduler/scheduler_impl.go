@@ -0,0 +1,70 @@
+// Copyright 2025 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package scheduler
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-co-op/gocron/v2"
+	"github.com/rs/zerolog"
+
+	"github.com/dadrus/heimdall/internal/heimdall"
+	"github.com/dadrus/heimdall/internal/x/errorchain"
+)
+
+type scheduler struct {
+	s gocron.Scheduler
+	l zerolog.Logger
+}
+
+func newScheduler(logger zerolog.Logger) (*scheduler, error) {
+	sch, err := gocron.NewScheduler(
+		gocron.WithLocation(time.UTC),
+		gocron.WithGlobalJobOptions(gocron.WithSingletonMode(gocron.LimitModeReschedule)),
+	)
+	if err != nil {
+		return nil, errorchain.NewWithMessage(heimdall.ErrInternal, "failed creating scheduler").
+			CausedBy(err)
+	}
+
+	return &scheduler{s: sch, l: logger}, nil
+}
+
+func (s *scheduler) Schedule(ctx context.Context, interval time.Duration, task func(ctx context.Context)) error {
+	if _, err := s.s.NewJob(
+		gocron.DurationJob(interval),
+		gocron.NewTask(task),
+		gocron.WithContext(ctx),
+	); err != nil {
+		return errorchain.NewWithMessage(heimdall.ErrInternal, "failed scheduling task").CausedBy(err)
+	}
+
+	return nil
+}
+
+func (s *scheduler) start(_ context.Context) {
+	s.l.Debug().Msg("Starting scheduler")
+
+	s.s.Start()
+}
+
+func (s *scheduler) stop(_ context.Context) error {
+	s.l.Debug().Msg("Stopping scheduler")
+
+	return s.s.Shutdown()
+}
@@ -0,0 +1,72 @@
+// Copyright 2025 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package scheduler
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type ctxKey struct{}
+
+func TestSchedulerLifeCycle(t *testing.T) {
+	t.Parallel()
+
+	// GIVEN
+	var (
+		calls atomic.Int32
+		value atomic.Value
+	)
+
+	sch, err := newScheduler(log.Logger)
+	require.NoError(t, err)
+
+	err = sch.Schedule(context.WithValue(t.Context(), ctxKey{}, "foo"), 10*time.Millisecond,
+		func(ctx context.Context) {
+			value.Store(ctx.Value(ctxKey{}))
+			calls.Add(1)
+		})
+	require.NoError(t, err)
+
+	// WHEN not started
+	time.Sleep(50 * time.Millisecond)
+
+	// THEN
+	assert.Zero(t, calls.Load())
+
+	// WHEN
+	sch.start(t.Context())
+	time.Sleep(50 * time.Millisecond)
+
+	// THEN
+	assert.Positive(t, calls.Load())
+	assert.Equal(t, "foo", value.Load())
+
+	// WHEN
+	require.NoError(t, sch.stop(t.Context()))
+	executed := calls.Load()
+	time.Sleep(50 * time.Millisecond)
+
+	// THEN
+	assert.Equal(t, executed, calls.Load())
+}
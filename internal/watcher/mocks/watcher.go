@@ -63,6 +63,40 @@ func (_c *WatcherMock_Add_Call) RunAndReturn(run func(string, watcher.ChangeList
 	return _c
 }
 
+// Remove provides a mock function with given fields: path, cl
+func (_m *WatcherMock) Remove(path string, cl watcher.ChangeListener) {
+	_m.Called(path, cl)
+}
+
+// WatcherMock_Remove_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Remove'
+type WatcherMock_Remove_Call struct {
+	*mock.Call
+}
+
+// Remove is a helper method to define mock.On call
+//   - path string
+//   - cl watcher.ChangeListener
+func (_e *WatcherMock_Expecter) Remove(path interface{}, cl interface{}) *WatcherMock_Remove_Call {
+	return &WatcherMock_Remove_Call{Call: _e.mock.On("Remove", path, cl)}
+}
+
+func (_c *WatcherMock_Remove_Call) Run(run func(path string, cl watcher.ChangeListener)) *WatcherMock_Remove_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(watcher.ChangeListener))
+	})
+	return _c
+}
+
+func (_c *WatcherMock_Remove_Call) Return() *WatcherMock_Remove_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *WatcherMock_Remove_Call) RunAndReturn(run func(string, watcher.ChangeListener)) *WatcherMock_Remove_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 type mockConstructorTestingTNewWatcherMock interface {
 	mock.TestingT
 	Cleanup(func())
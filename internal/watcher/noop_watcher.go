@@ -23,3 +23,4 @@ type NoopWatcher struct{}
 func (*NoopWatcher) start(_ context.Context)              {}
 func (*NoopWatcher) stop(_ context.Context) error         { return nil }
 func (*NoopWatcher) Add(_ string, _ ChangeListener) error { return nil }
+func (*NoopWatcher) Remove(_ string, _ ChangeListener)    {}
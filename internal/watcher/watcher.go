@@ -28,4 +28,7 @@ type ChangeListener interface {
 
 type Watcher interface {
 	Add(path string, cl ChangeListener) error
+	// Remove unregisters the given listener for the given path. The path is not
+	// watched anymore if there are no further listeners registered for it.
+	Remove(path string, cl ChangeListener)
 }
@@ -18,6 +18,7 @@ package watcher
 
 import (
 	"context"
+	"slices"
 	"sync"
 
 	"github.com/fsnotify/fsnotify"
@@ -102,6 +103,29 @@ func (w *watcher) Add(path string, cl ChangeListener) error {
 	return nil
 }
 
+func (w *watcher) Remove(path string, cl ChangeListener) {
+	w.mut.Lock()
+	defer w.mut.Unlock()
+
+	list, ok := w.m[path]
+	if !ok {
+		return
+	}
+
+	list = slices.DeleteFunc(list, func(registered ChangeListener) bool { return registered == cl })
+	if len(list) != 0 {
+		w.m[path] = list
+
+		return
+	}
+
+	delete(w.m, path)
+
+	if err := w.w.Remove(path); err != nil {
+		w.l.Debug().Err(err).Str("_file", path).Msg("Failed to stop watching file")
+	}
+}
+
 func (w *watcher) fireOnChange(evt fsnotify.Event) {
 	w.mut.Lock()
 	listeners := w.m[evt.Name]
@@ -75,3 +75,41 @@ func TestWatcherLifeCycle(t *testing.T) {
 	f2.WriteString("baz")
 	time.Sleep(100 * time.Millisecond)
 }
+
+func TestWatcherRemove(t *testing.T) {
+	t.Parallel()
+
+	// GIVEN
+	cw, err := newWatcher(log.Logger)
+	require.NoError(t, err)
+
+	cw.start(t.Context())
+	defer cw.stop(t.Context())
+
+	testDir := t.TempDir()
+	f1, err := os.Create(filepath.Join(testDir, "file1"))
+	require.NoError(t, err)
+
+	cl1 := NewChangeListenerMock(t)
+	cl2 := NewChangeListenerMock(t)
+
+	cl2.EXPECT().OnChanged(mock.Anything).Once()
+
+	require.NoError(t, cw.Add(f1.Name(), cl1))
+	require.NoError(t, cw.Add(f1.Name(), cl2))
+
+	// WHEN
+	cw.Remove(f1.Name(), cl1)
+	cw.Remove("not-watched", cl1)
+
+	f1.WriteString("foo")
+	time.Sleep(100 * time.Millisecond)
+
+	cw.Remove(f1.Name(), cl2)
+
+	f1.WriteString("bar")
+	time.Sleep(100 * time.Millisecond)
+
+	// THEN
+	require.NotContains(t, cw.m, f1.Name())
+}
@@ -0,0 +1,318 @@
+// Copyright 2025 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package paseto implements the v4 version of Platform-Agnostic SEcurity TOkens
+// (https://github.com/paseto-standard/paseto-spec), both, the local (symmetric
+// authenticated encryption) and the public (Ed25519 signatures) purposes.
+package paseto
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"golang.org/x/crypto/blake2b"
+	"golang.org/x/crypto/chacha20"
+)
+
+const (
+	HeaderV4Local  = "v4.local."
+	HeaderV4Public = "v4.public."
+
+	KeySize = 32
+
+	nonceSize = 32
+	macSize   = 32
+
+	encKeyDomain  = "paseto-encryption-key"
+	authKeyDomain = "paseto-auth-key-for-aead"
+)
+
+var (
+	ErrMalformedToken        = errors.New("malformed token")
+	ErrUnsupportedToken      = errors.New("unsupported token version or purpose")
+	ErrInvalidKey            = errors.New("invalid key")
+	ErrInvalidTokenAuth      = errors.New("token authentication failed")
+	ErrInvalidTokenSignature = errors.New("token signature verification failed")
+)
+
+// Encrypt creates a v4.local token for the given payload and footer authenticated encrypted
+// with the given 32 bytes key. The implicit assertion is authenticated, but not part of the token.
+func Encrypt(key, payload, footer, implicit []byte) (string, error) {
+	nonce := make([]byte, nonceSize)
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	return encrypt(key, nonce, payload, footer, implicit)
+}
+
+// Decrypt verifies and decrypts the given v4.local token using the given 32 bytes key and
+// returns the payload and the footer.
+func Decrypt(key []byte, token string, implicit []byte) ([]byte, []byte, error) {
+	if len(key) != KeySize {
+		return nil, nil, ErrInvalidKey
+	}
+
+	raw, footer, err := split(token, HeaderV4Local)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(raw) < nonceSize+macSize {
+		return nil, nil, ErrMalformedToken
+	}
+
+	nonce := raw[:nonceSize]
+	ciphertext := raw[nonceSize : len(raw)-macSize]
+	tag := raw[len(raw)-macSize:]
+
+	encKey, counterNonce, authKey, err := deriveKeys(key, nonce)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	expTag, err := mac(authKey, pae([]byte(HeaderV4Local), nonce, ciphertext, footer, implicit))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if !hmac.Equal(tag, expTag) {
+		return nil, nil, ErrInvalidTokenAuth
+	}
+
+	cipher, err := chacha20.NewUnauthenticatedCipher(encKey, counterNonce)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	payload := make([]byte, len(ciphertext))
+	cipher.XORKeyStream(payload, ciphertext)
+
+	return payload, footer, nil
+}
+
+// Sign creates a v4.public token for the given payload and footer signed with the given Ed25519 key.
+// The implicit assertion is covered by the signature, but not part of the token.
+func Sign(key ed25519.PrivateKey, payload, footer, implicit []byte) (string, error) {
+	if len(key) != ed25519.PrivateKeySize {
+		return "", ErrInvalidKey
+	}
+
+	sig := ed25519.Sign(key, pae([]byte(HeaderV4Public), payload, footer, implicit))
+
+	return assemble(HeaderV4Public, append(bytes.Clone(payload), sig...), footer), nil
+}
+
+// Verify verifies the signature of the given v4.public token using the given Ed25519 public key
+// and returns the payload and the footer.
+func Verify(key ed25519.PublicKey, token string, implicit []byte) ([]byte, []byte, error) {
+	if len(key) != ed25519.PublicKeySize {
+		return nil, nil, ErrInvalidKey
+	}
+
+	raw, footer, err := split(token, HeaderV4Public)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(raw) < ed25519.SignatureSize {
+		return nil, nil, ErrMalformedToken
+	}
+
+	payload := raw[:len(raw)-ed25519.SignatureSize]
+	sig := raw[len(raw)-ed25519.SignatureSize:]
+
+	if !ed25519.Verify(key, pae([]byte(HeaderV4Public), payload, footer, implicit), sig) {
+		return nil, nil, ErrInvalidTokenSignature
+	}
+
+	return payload, footer, nil
+}
+
+// Footer returns the (unverified) footer of the given token. It is intended to be used
+// to e.g. select the key required to verify the token.
+func Footer(token string) ([]byte, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) == 3 { //nolint:mnd
+		return nil, nil
+	}
+
+	if len(parts) != 4 { //nolint:mnd
+		return nil, ErrMalformedToken
+	}
+
+	footer, err := base64.RawURLEncoding.DecodeString(parts[3])
+	if err != nil {
+		return nil, ErrMalformedToken
+	}
+
+	return footer, nil
+}
+
+// ReadKeyFile reads a key for v4.local tokens from the given file. The key is expected to be
+// either hex encoded, or to be present in its raw form.
+func ReadKeyFile(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if trimmed := bytes.TrimSpace(data); len(trimmed) == hex.EncodedLen(KeySize) {
+		key := make([]byte, KeySize)
+		if _, err = hex.Decode(key, trimmed); err == nil {
+			return key, nil
+		}
+	}
+
+	if len(data) != KeySize {
+		return nil, fmt.Errorf("%w: key must be %d bytes long", ErrInvalidKey, KeySize)
+	}
+
+	return data, nil
+}
+
+func encrypt(key, nonce, payload, footer, implicit []byte) (string, error) {
+	if len(key) != KeySize {
+		return "", ErrInvalidKey
+	}
+
+	encKey, counterNonce, authKey, err := deriveKeys(key, nonce)
+	if err != nil {
+		return "", err
+	}
+
+	cipher, err := chacha20.NewUnauthenticatedCipher(encKey, counterNonce)
+	if err != nil {
+		return "", err
+	}
+
+	ciphertext := make([]byte, len(payload))
+	cipher.XORKeyStream(ciphertext, payload)
+
+	tag, err := mac(authKey, pae([]byte(HeaderV4Local), nonce, ciphertext, footer, implicit))
+	if err != nil {
+		return "", err
+	}
+
+	raw := make([]byte, 0, len(nonce)+len(ciphertext)+len(tag))
+	raw = append(raw, nonce...)
+	raw = append(raw, ciphertext...)
+	raw = append(raw, tag...)
+
+	return assemble(HeaderV4Local, raw, footer), nil
+}
+
+func deriveKeys(key, nonce []byte) ([]byte, []byte, []byte, error) {
+	const encKeyAndNonceSize = KeySize + chacha20.NonceSizeX
+
+	tmp, err := keyedHash(encKeyAndNonceSize, key, []byte(encKeyDomain), nonce)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	authKey, err := keyedHash(KeySize, key, []byte(authKeyDomain), nonce)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	return tmp[:KeySize], tmp[KeySize:], authKey, nil
+}
+
+func mac(key, data []byte) ([]byte, error) { return keyedHash(macSize, key, data) }
+
+func keyedHash(size int, key []byte, data ...[]byte) ([]byte, error) {
+	hash, err := blake2b.New(size, key)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, d := range data {
+		hash.Write(d)
+	}
+
+	return hash.Sum(nil), nil
+}
+
+func assemble(header string, raw, footer []byte) string {
+	var sb strings.Builder
+
+	sb.WriteString(header)
+	sb.WriteString(base64.RawURLEncoding.EncodeToString(raw))
+
+	if len(footer) != 0 {
+		sb.WriteString(".")
+		sb.WriteString(base64.RawURLEncoding.EncodeToString(footer))
+	}
+
+	return sb.String()
+}
+
+func split(token, header string) ([]byte, []byte, error) {
+	if !strings.HasPrefix(token, header) {
+		return nil, nil, ErrUnsupportedToken
+	}
+
+	body, encodedFooter, hasFooter := strings.Cut(token[len(header):], ".")
+
+	raw, err := base64.RawURLEncoding.DecodeString(body)
+	if err != nil {
+		return nil, nil, ErrMalformedToken
+	}
+
+	if !hasFooter {
+		return raw, nil, nil
+	}
+
+	footer, err := base64.RawURLEncoding.DecodeString(encodedFooter)
+	if err != nil || len(footer) == 0 {
+		return nil, nil, ErrMalformedToken
+	}
+
+	return raw, footer, nil
+}
+
+// pae implements the pre-authentication encoding as defined by the PASETO specification.
+func pae(pieces ...[]byte) []byte {
+	const int64BytesCount = 8
+
+	buf := make([]byte, int64BytesCount, int64BytesCount*(len(pieces)+1))
+
+	//nolint:gosec
+	// no integer overflow during conversion possible
+	binary.LittleEndian.PutUint64(buf, uint64(len(pieces)))
+
+	for _, piece := range pieces {
+		var size [int64BytesCount]byte
+
+		//nolint:gosec
+		// no integer overflow during conversion possible
+		binary.LittleEndian.PutUint64(size[:], uint64(len(piece)))
+
+		buf = append(buf, size[:]...)
+		buf = append(buf, piece...)
+	}
+
+	return buf
+}
@@ -0,0 +1,316 @@
+// Copyright 2025 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package paseto
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// test vectors taken from https://github.com/paseto-standard/test-vectors (4-E-1 and 4-S-1).
+const (
+	testLocalKey   = "707172737475767778797a7b7c7d7e7f808182838485868788898a8b8c8d8e8f"
+	testLocalToken = "v4.local.AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAQAr68PS4AXe7If_ZgesdkUMvSwscFlAl1pk5HC0e8k" +
+		"ApeaqMfGo_7OpBnwJOAbY9V7WU6abu74MmcUE8YWAiaArVI8XJ5hOb_4v9RmDkneN0S92dx0OW4pgy7omxgf3S8c3LlQg"
+	testLocalPayload = `{"data":"this is a secret message","exp":"2022-01-01T00:00:00+00:00"}`
+
+	testSecretKey = "b4cbfb43df4ce210727d953e4a713307fa19bb7d9f85041438d9e11b942a3774" +
+		"1eb9dbbbbc047c03fd70604e0071f0987e16b28b757225c11f00415d0e20b1a2"
+	testPublicToken = "v4.public.eyJkYXRhIjoidGhpcyBpcyBhIHNpZ25lZCBtZXNzYWdlIiwiZXhwIjoiMjAyMi0wMS0wMVQwMDowMDow" +
+		"MCswMDowMCJ9bg_XBBzds8lTZShVlwwKSgeKpLT3yukTw6JUz3W4h_ExsQV-P0V54zemZDcAxFaSeef1QlXEFtkqxT1ciiQEDA"
+	testPublicPayload = `{"data":"this is a signed message","exp":"2022-01-01T00:00:00+00:00"}`
+)
+
+func TestLocalTestVector(t *testing.T) {
+	t.Parallel()
+
+	key, err := hex.DecodeString(testLocalKey)
+	require.NoError(t, err)
+
+	token, err := encrypt(key, make([]byte, nonceSize), []byte(testLocalPayload), nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, testLocalToken, token)
+
+	payload, footer, err := Decrypt(key, testLocalToken, nil)
+	require.NoError(t, err)
+	assert.Equal(t, testLocalPayload, string(payload))
+	assert.Empty(t, footer)
+}
+
+func TestPublicTestVector(t *testing.T) {
+	t.Parallel()
+
+	rawKey, err := hex.DecodeString(testSecretKey)
+	require.NoError(t, err)
+
+	key := ed25519.PrivateKey(rawKey)
+
+	token, err := Sign(key, []byte(testPublicPayload), nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, testPublicToken, token)
+
+	payload, footer, err := Verify(key.Public().(ed25519.PublicKey), testPublicToken, nil)
+	require.NoError(t, err)
+	assert.Equal(t, testPublicPayload, string(payload))
+	assert.Empty(t, footer)
+}
+
+func TestEncryptDecrypt(t *testing.T) {
+	t.Parallel()
+
+	key := make([]byte, KeySize)
+	_, err := rand.Read(key)
+	require.NoError(t, err)
+
+	otherKey := make([]byte, KeySize)
+	_, err = rand.Read(otherKey)
+	require.NoError(t, err)
+
+	token, err := Encrypt(key, []byte(`{"sub":"foo"}`), []byte(`{"kid":"bar"}`), []byte("baz"))
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(token, HeaderV4Local))
+
+	for uc, tc := range map[string]struct {
+		key      []byte
+		token    string
+		implicit []byte
+		assert   func(t *testing.T, err error, payload, footer []byte)
+	}{
+		"valid token": {
+			key: key, token: token, implicit: []byte("baz"),
+			assert: func(t *testing.T, err error, payload, footer []byte) {
+				t.Helper()
+
+				require.NoError(t, err)
+				assert.JSONEq(t, `{"sub":"foo"}`, string(payload))
+				assert.JSONEq(t, `{"kid":"bar"}`, string(footer))
+			},
+		},
+		"invalid key size": {
+			key: key[:16], token: token, implicit: []byte("baz"),
+			assert: func(t *testing.T, err error, _, _ []byte) {
+				t.Helper()
+
+				require.ErrorIs(t, err, ErrInvalidKey)
+			},
+		},
+		"wrong key": {
+			key: otherKey, token: token, implicit: []byte("baz"),
+			assert: func(t *testing.T, err error, _, _ []byte) {
+				t.Helper()
+
+				require.ErrorIs(t, err, ErrInvalidTokenAuth)
+			},
+		},
+		"wrong implicit assertion": {
+			key: key, token: token, implicit: []byte("foo"),
+			assert: func(t *testing.T, err error, _, _ []byte) {
+				t.Helper()
+
+				require.ErrorIs(t, err, ErrInvalidTokenAuth)
+			},
+		},
+		"modified footer": {
+			key: key, token: token[:strings.LastIndex(token, ".")] + ".eyJraWQiOiJmb28ifQ", implicit: []byte("baz"),
+			assert: func(t *testing.T, err error, _, _ []byte) {
+				t.Helper()
+
+				require.ErrorIs(t, err, ErrInvalidTokenAuth)
+			},
+		},
+		"public token": {
+			key: key, token: testPublicToken,
+			assert: func(t *testing.T, err error, _, _ []byte) {
+				t.Helper()
+
+				require.ErrorIs(t, err, ErrUnsupportedToken)
+			},
+		},
+		"malformed token": {
+			key: key, token: HeaderV4Local + "Zm9v",
+			assert: func(t *testing.T, err error, _, _ []byte) {
+				t.Helper()
+
+				require.ErrorIs(t, err, ErrMalformedToken)
+			},
+		},
+	} {
+		t.Run(uc, func(t *testing.T) {
+			payload, footer, err := Decrypt(tc.key, tc.token, tc.implicit)
+
+			tc.assert(t, err, payload, footer)
+		})
+	}
+}
+
+func TestSignVerify(t *testing.T) {
+	t.Parallel()
+
+	pubKey, privKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	otherPubKey, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	token, err := Sign(privKey, []byte(`{"sub":"foo"}`), []byte(`{"kid":"bar"}`), nil)
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(token, HeaderV4Public))
+
+	for uc, tc := range map[string]struct {
+		key    ed25519.PublicKey
+		token  string
+		assert func(t *testing.T, err error, payload, footer []byte)
+	}{
+		"valid token": {
+			key: pubKey, token: token,
+			assert: func(t *testing.T, err error, payload, footer []byte) {
+				t.Helper()
+
+				require.NoError(t, err)
+				assert.JSONEq(t, `{"sub":"foo"}`, string(payload))
+				assert.JSONEq(t, `{"kid":"bar"}`, string(footer))
+			},
+		},
+		"wrong key": {
+			key: otherPubKey, token: token,
+			assert: func(t *testing.T, err error, _, _ []byte) {
+				t.Helper()
+
+				require.ErrorIs(t, err, ErrInvalidTokenSignature)
+			},
+		},
+		"modified footer": {
+			key: pubKey, token: token[:strings.LastIndex(token, ".")] + ".eyJraWQiOiJmb28ifQ",
+			assert: func(t *testing.T, err error, _, _ []byte) {
+				t.Helper()
+
+				require.ErrorIs(t, err, ErrInvalidTokenSignature)
+			},
+		},
+		"local token": {
+			key: pubKey, token: testLocalToken,
+			assert: func(t *testing.T, err error, _, _ []byte) {
+				t.Helper()
+
+				require.ErrorIs(t, err, ErrUnsupportedToken)
+			},
+		},
+		"too short token": {
+			key: pubKey, token: HeaderV4Public + "Zm9v",
+			assert: func(t *testing.T, err error, _, _ []byte) {
+				t.Helper()
+
+				require.ErrorIs(t, err, ErrMalformedToken)
+			},
+		},
+	} {
+		t.Run(uc, func(t *testing.T) {
+			payload, footer, err := Verify(tc.key, tc.token, nil)
+
+			tc.assert(t, err, payload, footer)
+		})
+	}
+}
+
+func TestFooter(t *testing.T) {
+	t.Parallel()
+
+	for uc, tc := range map[string]struct {
+		token  string
+		footer string
+		err    error
+	}{
+		"without footer":    {token: testPublicToken},
+		"with footer":       {token: "v4.public.Zm9v.eyJraWQiOiJmb28ifQ", footer: `{"kid":"foo"}`},
+		"not a token":       {token: "foo", err: ErrMalformedToken},
+		"malformed footer":  {token: "v4.public.Zm9v.!!!", err: ErrMalformedToken},
+		"too many segments": {token: "v4.public.Zm9v.Zm9v.Zm9v", err: ErrMalformedToken},
+	} {
+		t.Run(uc, func(t *testing.T) {
+			footer, err := Footer(tc.token)
+
+			if tc.err != nil {
+				require.ErrorIs(t, err, tc.err)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tc.footer, string(footer))
+			}
+		})
+	}
+}
+
+func TestReadKeyFile(t *testing.T) {
+	t.Parallel()
+
+	key := make([]byte, KeySize)
+	_, err := rand.Read(key)
+	require.NoError(t, err)
+
+	testDir := t.TempDir()
+
+	for uc, tc := range map[string]struct {
+		content []byte
+		assert  func(t *testing.T, err error, readKey []byte)
+	}{
+		"hex encoded key": {
+			content: []byte(hex.EncodeToString(key) + "\n"),
+			assert: func(t *testing.T, err error, readKey []byte) {
+				t.Helper()
+
+				require.NoError(t, err)
+				assert.Equal(t, key, readKey)
+			},
+		},
+		"raw key": {
+			content: key,
+			assert: func(t *testing.T, err error, readKey []byte) {
+				t.Helper()
+
+				require.NoError(t, err)
+				assert.Equal(t, key, readKey)
+			},
+		},
+		"key of wrong size": {
+			content: []byte("foo"),
+			assert: func(t *testing.T, err error, _ []byte) {
+				t.Helper()
+
+				require.ErrorIs(t, err, ErrInvalidKey)
+			},
+		},
+	} {
+		t.Run(uc, func(t *testing.T) {
+			path := filepath.Join(testDir, strings.ReplaceAll(uc, " ", "_"))
+			require.NoError(t, os.WriteFile(path, tc.content, 0o600))
+
+			readKey, err := ReadKeyFile(path)
+
+			tc.assert(t, err, readKey)
+		})
+	}
+
+	_, err = ReadKeyFile(filepath.Join(testDir, "does_not_exist"))
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
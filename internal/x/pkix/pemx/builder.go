@@ -19,6 +19,7 @@ package pemx
 import (
 	"bytes"
 	"crypto/ecdsa"
+	"crypto/ed25519"
 	"crypto/rsa"
 	"crypto/x509"
 	"encoding/pem"
@@ -97,6 +98,24 @@ func WithRSAPrivateKey(key *rsa.PrivateKey, opts ...BlockOption) EntryOption {
 	}
 }
 
+func WithEd25519PrivateKey(key ed25519.PrivateKey, opts ...BlockOption) EntryOption {
+	return func(block *pem.Block) error {
+		raw, err := x509.MarshalPKCS8PrivateKey(key)
+		if err != nil {
+			return err
+		}
+
+		block.Type = "PRIVATE KEY"
+		block.Bytes = raw
+
+		for _, opt := range opts {
+			opt(block)
+		}
+
+		return nil
+	}
+}
+
 func BuildPEM(opts ...EntryOption) ([]byte, error) {
 	buf := new(bytes.Buffer)
 
@@ -0,0 +1,85 @@
+// Copyright 2025 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package testsupport
+
+import (
+	"net"
+	"os"
+
+	"github.com/maxmind/mmdbwriter"
+	"github.com/maxmind/mmdbwriter/mmdbtype"
+)
+
+// GeoIPEntry describes the data of a network written to a MaxMind DB file
+// by WriteGeoIPDatabase. Empty fields are omitted.
+type GeoIPEntry struct {
+	Network        string
+	Country        string
+	City           string
+	ASN            uint32
+	ASOrganization string
+}
+
+func WriteGeoIPDatabase(path string, entries ...GeoIPEntry) error {
+	tree, err := mmdbwriter.New(mmdbwriter.Options{
+		DatabaseType:            "Heimdall-Test",
+		IncludeReservedNetworks: true,
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		_, network, err := net.ParseCIDR(entry.Network)
+		if err != nil {
+			return err
+		}
+
+		data := mmdbtype.Map{}
+
+		if len(entry.Country) != 0 {
+			data["country"] = mmdbtype.Map{"iso_code": mmdbtype.String(entry.Country)}
+		}
+
+		if len(entry.City) != 0 {
+			data["city"] = mmdbtype.Map{"names": mmdbtype.Map{"en": mmdbtype.String(entry.City)}}
+		}
+
+		if entry.ASN != 0 {
+			data["autonomous_system_number"] = mmdbtype.Uint32(entry.ASN)
+		}
+
+		if len(entry.ASOrganization) != 0 {
+			data["autonomous_system_organization"] = mmdbtype.String(entry.ASOrganization)
+		}
+
+		if err = tree.Insert(network, data); err != nil {
+			return err
+		}
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	defer file.Close()
+
+	_, err = tree.WriteTo(file)
+
+	return err
+}
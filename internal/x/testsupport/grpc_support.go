@@ -0,0 +1,56 @@
+// Copyright 2025 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package testsupport
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+)
+
+// StartGRPCHealthServer starts a plaintext gRPC server exposing the grpc.health.v1.Health service
+// with "foo" service being in the serving state, as well as the server reflection service. The
+// given interceptor, if not nil, is applied to all unary calls. Returns the address of the server.
+func StartGRPCHealthServer(t *testing.T, interceptor grpc.UnaryServerInterceptor) string {
+	t.Helper()
+
+	var opts []grpc.ServerOption
+	if interceptor != nil {
+		opts = append(opts, grpc.UnaryInterceptor(interceptor))
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	healthSrv := health.NewServer()
+	healthSrv.SetServingStatus("foo", grpc_health_v1.HealthCheckResponse_SERVING)
+
+	srv := grpc.NewServer(opts...)
+	grpc_health_v1.RegisterHealthServer(srv, healthSrv)
+	reflection.Register(srv)
+
+	go func() { _ = srv.Serve(listener) }()
+
+	t.Cleanup(srv.Stop)
+
+	return listener.Addr().String()
+}
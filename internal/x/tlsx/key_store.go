@@ -71,10 +71,13 @@ func (cr *keyStore) load() error {
 
 	var entry *keystore.Entry
 
-	if len(cr.keyID) != 0 {
+	switch {
+	case len(cr.keyID) != 0:
 		entry, err = ks.GetKey(cr.keyID)
-	} else {
-		entry, err = ks.Entries()[0], nil
+	case len(ks.Entries()) != 0:
+		entry = ks.Entries()[0]
+	default:
+		err = errorchain.NewWithMessage(heimdall.ErrConfiguration, "key store is empty")
 	}
 
 	if err != nil {
@@ -33,6 +33,7 @@ import (
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
+	"github.com/dadrus/heimdall/internal/heimdall"
 	"github.com/dadrus/heimdall/internal/x/pkix/pemx"
 	"github.com/dadrus/heimdall/internal/x/testsupport"
 )
@@ -165,3 +166,21 @@ func TestKeyStoreOnChanged(t *testing.T) {
 	// THEN
 	require.Equal(t, cert2, ks.tlsCert.Leaf)
 }
+
+func TestKeyStoreLoadFromEmptyFile(t *testing.T) {
+	t.Parallel()
+
+	// GIVEN
+	pemFile := filepath.Join(t.TempDir(), "keystore.pem")
+	require.NoError(t, os.WriteFile(pemFile, []byte{}, 0o600))
+
+	ks := &keyStore{path: pemFile}
+
+	// WHEN
+	err := ks.load()
+
+	// THEN
+	require.Error(t, err)
+	require.ErrorIs(t, err, heimdall.ErrConfiguration)
+	require.ErrorContains(t, err, "empty")
+}